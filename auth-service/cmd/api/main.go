@@ -1,34 +1,57 @@
-// Package main - Uygulaman\u0131n giri\u015f noktas\u0131 (entry point)
-// cmd/api/ = Executable binary'nin bulundu\u011fu yer
-// Go'da her executable bir main package ve main() fonksiyonu i\u00e7ermelidir
+// Package main - Uygulamanın giriş noktası (entry point)
+// cmd/api/ = Executable binary'nin bulunduğu yer
+// Go'da her executable bir main package ve main() fonksiyonu içermelidir
 package main
 
 import (
-	"context"     // Context management (timeout, cancel)
-	"log"         // Logging (basit, production'da zerolog/zap kullan\u0131l\u0131r)
-	"net/http"    // HTTP server
-	"os"          // OS i\u015flemleri (signals, environment variables)
-	"os/signal"   // OS signal'lerini yakalamak i\u00e7in (SIGINT, SIGTERM)
-	"syscall"     // System calls
-	"time"        // Zaman i\u015flemleri
+	"context"    // Context management (timeout, cancel)
+	"crypto/rsa" // RSA public key (RS256 JWT)
+	"log"        // Logging (basit, production'da zerolog/zap kullanılır)
+	"log/slog"   // Structured logging (request log'ları)
+	"net/http"   // HTTP server
+	"os"         // OS işlemleri (environment variables, stdout)
+	"time"       // Zaman işlemleri
 
 	// Internal packages (bizim projemizin paketleri)
-	// Go module ad\u0131 + relative path
+	// Go module adı + relative path
 	"auth-service/config"                                // Configuration management
+	_ "auth-service/docs"                                // Swagger spec (swag.Register ile kaydedilir)
 	"auth-service/internal/application/usecase"          // Business logic (Use Cases)
+	"auth-service/internal/domain"                       // Domain entities and interfaces
+	"auth-service/internal/infrastructure/audit"         // Audit log
+	"auth-service/internal/infrastructure/blacklist"     // Revoked access tokens
+	"auth-service/internal/infrastructure/captcha"       // CAPTCHA verification
+	"auth-service/internal/infrastructure/cleanup"       // Background token cleanup
+	"auth-service/internal/infrastructure/health"        // Dependency health checks
+	"auth-service/internal/infrastructure/hibp"          // Breached password check
+	"auth-service/internal/infrastructure/idempotency"   // Idempotency-Key responses
+	"auth-service/internal/infrastructure/mailer"        // Outgoing email
+	"auth-service/internal/infrastructure/metrics"       // Prometheus metrics
+	"auth-service/internal/infrastructure/oauth"         // Social login providers
+	"auth-service/internal/infrastructure/passkey"       // Pending passkey challenges
+	"auth-service/internal/infrastructure/ratelimit"     // Request rate limiting
 	"auth-service/internal/infrastructure/repository"    // Database repositories
+	"auth-service/internal/infrastructure/tokenversion"  // Cached access token versions
+	"auth-service/internal/infrastructure/usercache"     // Cached users for token refresh
+	"auth-service/internal/infrastructure/vault"         // JWT secrets from Vault
+	"auth-service/internal/infrastructure/webhook"       // Outgoing webhook events
 	"auth-service/internal/presentation/http/handler"    // HTTP handlers (controllers)
 	"auth-service/internal/presentation/http/middleware" // HTTP middleware
+	"auth-service/internal/presentation/rpc"             // gRPC token API
 	"auth-service/pkg/database"                          // Database connection
 	"auth-service/pkg/security"                          // Security services (JWT, password)
+	"auth-service/pkg/server"                            // HTTP server with graceful shutdown
 
-	// External packages (3rd party k\u00fct\u00fcphaneler)
-	"github.com/gin-contrib/cors" // CORS middleware for Gin
-	"github.com/gin-gonic/gin"    // Gin web framework
+	// External packages (3rd party kütüphaneler)
+	"github.com/gin-gonic/gin"                 // Gin web framework
+	"github.com/go-webauthn/webauthn/webauthn" // Passkey (WebAuthn) ceremonies
+	swaggerFiles "github.com/swaggo/files"     // Swagger UI static files
+	ginSwagger "github.com/swaggo/gin-swagger" // Swagger UI handler
 )
 
-// Swagger annotations - API dokümantasyonu i\u00e7in
-// swag init komutu ile otomatik docs olu\u015fturulur
+// Swagger annotations - API dokümantasyonu için
+// make swagger (swag init) ile docs/ package'ı oluşturulur, /swagger/index.html'de sunulur
+// @host verilmez: Swagger UI istekleri dokümanı sunan host'a gönderir
 // @title Auth Service API
 // @version 1.0
 // @description Enterprise Auth Microservice with Clean Architecture
@@ -40,17 +63,749 @@ import (
 // @license.name MIT
 // @license.url https://opensource.org/licenses/MIT
 
-// @host localhost:5004
-// @BasePath /api
+// @BasePath /
 
 // @securityDefinitions.apikey BearerAuth
 // @in header
 // @name Authorization
 // @description Type "Bearer" followed by a space and JWT token.
 
-// main - Uygulaman\u0131n ba\u015flang\u0131\u00e7 fonksiyonu
-// Go program\u0131 main() fonksiyonundan \u00e7al\u0131\u015fmaya ba\u015flar
-// Dependency Injection (DI) pattern'\u0131 kullan\u0131l\u0131r:\n// 1. Config y\u00fckle\n// 2. Database ba\u011flan\n// 3. Repository'leri olu\u015ftur\n// 4. Service'leri olu\u015ftur\n// 5. Use Case'leri olu\u015ftur\n// 6. Handler'lar\u0131 olu\u015ftur\n// 7. Router'i kur\n// 8. Server'i ba\u015flat\nfunc main() {
-	// ===== 1. CONFIGURATION =====\n\t// .env dosyas\u0131n\u0131 y\u00fckle ve config struct'\u0131na parse et\n\tcfg, err := config.Load()\n\tif err != nil {\n\t\t// Fatalf = Error log'la ve program\u0131 sonland\u0131r (exit code 1)\n\t\t// %v = value formatter (any type'i string'e \u00e7evirir)\n\t\tlog.Fatalf(\"\u274c Failed to load configuration: %v\", err)\n\t}\n\n\t// ===== 2. GIN MODE =====\n\t// Gin'in \u00e7al\u0131\u015fma modu: \"debug\", \"release\" veya \"test\"\n\t// debug = verbose logging, release = production mode (daha h\u0131zl\u0131)\n\tgin.SetMode(cfg.Server.Mode)\n\n\t// ===== 3. DATABASE CONNECTION =====\n\t// PostgreSQL'e ba\u011flan ve GORM instance'\u0131 al\n\t// & = cfg.Database struct'\u0131n\u0131n pointer'\u0131n\u0131 g\u00f6nder (memory efficient)\n\tdb, err := database.NewPostgresDB(&cfg.Database)\n\tif err != nil {\n\t\tlog.Fatalf(\"\u274c Failed to connect to database: %v\", err)\n\t}\n\n\t// ===== 4. REPOSITORIES (Data Access Layer) =====\n\t// Repository Pattern: Database access'\u0131 kaps\u00fclleyen layer\n\t// Bu sayede database de\u011fi\u015firse sadece repository'leri de\u011fi\u015ftiririz\n\tuserRepo := repository.NewUserRepository(db)\n\trefreshTokenRepo := repository.NewRefreshTokenRepository(db)\n\n\t// ===== 5. SERVICES (Security Layer) =====\n\t// JWT token olu\u015fturma/do\u011frulama servisi\n\tjwtService := security.NewJWTService(\n\t\tcfg.JWT.Secret,                // Secret key (.env'den gelir)\n\t\tcfg.JWT.AccessTokenExpiry,     // 15 dakika\n\t\tcfg.JWT.RefreshTokenExpiry,    // 7 g\u00fcn\n\t)\n\t// \u015eifre hash'leme/kar\u015f\u0131la\u015ft\u0131rma servisi (bcrypt)\n\tpasswordService := security.NewPasswordService(cfg.Security.BcryptCost)\n\n\t// ===== 6. USE CASES (Business Logic Layer) =====\n\t// Clean Architecture'da i\u015f mant\u0131\u011f\u0131 use case'lerde bulunur\n\t// T\u00fcm dependencies inject edilir (DI pattern)\n\tauthUseCase := usecase.NewAuthUseCase(\n\t\tuserRepo,                       // User repository\n\t\trefreshTokenRepo,               // Token repository\n\t\tjwtService,                     // JWT service\n\t\tpasswordService,                // Password service\n\t\tcfg.JWT.AccessTokenExpiry,      // Token expiry config\n\t\tcfg.JWT.RefreshTokenExpiry,\n\t)\n\n\t// ===== 7. HANDLERS (Presentation Layer) =====\n\t// HTTP request'leri handle eden controller'lar\n\t// Use case'leri \u00e7a\u011f\u0131r\u0131r ve response d\u00f6ner\n\tauthHandler := handler.NewAuthHandler(authUseCase, jwtService)\n\n\t// ===== 8. ROUTER SETUP =====\n\t// Gin router'\u0131 kur: routes, middleware, CORS\n\trouter := setupRouter(cfg, authHandler, jwtService)\n\n\t// ===== 9. HTTP SERVER =====\n\t// Go'nun standard library HTTP server'\u0131\n\tsrv := &http.Server{\n\t\t// Address - Server'in dinleyece\u011fi adres ve port\n\t\t// 0.0.0.0:5004 = T\u00fcm network interface'lerinde 5004 portunu dinle\n\t\tAddr:           cfg.Server.Host + \":\" + cfg.Server.Port,\n\t\t\n\t\t// Handler - Gin router (http.Handler interface'ini implement eder)\n\t\tHandler:        router,\n\t\t\n\t\t// ReadTimeout - Request body'i okumak i\u00e7in max s\u00fcre\n\t\t// Slowloris attack gibi sald\u0131r\u0131lara kar\u015f\u0131 koruma\n\t\tReadTimeout:    10 * time.Second,\n\t\t\n\t\t// WriteTimeout - Response yazmak i\u00e7in max s\u00fcre\n\t\tWriteTimeout:   10 * time.Second,\n\t\t\n\t\t// MaxHeaderBytes - Request header'lar\u0131n max boyutu\n\t\t// 1 << 20 = 1 MB (bit shift: 1 * 2^20)\n\t\tMaxHeaderBytes: 1 << 20,\n\t}\n\n\t// ===== 10. GRACEFUL SHUTDOWN =====\n\t// Goroutine - Go'nun lightweight thread'i\n\t// go keyword = fonksiyonu ayr\u0131 bir goroutine'de \u00e7al\u0131\u015ft\u0131r (async)\n\tgo func() {\n\t\t// Printf = formatted print (\u00e7\u0131kt\u0131 vermek i\u00e7in)\n\t\tlog.Printf(\"\ud83d\ude80 Auth Service starting on %s:%s\", cfg.Server.Host, cfg.Server.Port)\n\t\t\n\t\t// Server'i ba\u015flat (blocking call - server kapanana kadar bekler)\n\t\tif err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {\n\t\t\t// ErrServerClosed = Normal shutdown, di\u011ferleri hata\n\t\t\tlog.Fatalf(\"\u274c Failed to start server: %v\", err)\n\t\t}\n\t}()  // () = goroutine'i hemen \u00e7al\u0131\u015ft\u0131r\n\n\t// ===== 11. SIGNAL HANDLING =====\n\t// OS signal'lerini yakalamak i\u00e7in channel olu\u015ftur\n\t// Channel = Go'nun goroutine'ler aras\u0131 ileti\u015fim arac\u0131\n\t// make() = channel olu\u015fturma, buffer size = 1\n\tquit := make(chan os.Signal, 1)\n\t\n\t// SIGINT (Ctrl+C) ve SIGTERM signal'lerini yakala\n\t// signal.Notify = Bu signal'ler geldi\u011finde quit channel'\u0131na g\u00f6nder\n\tsignal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)\n\t\n\t// Channel'dan signal bekle (blocking)\n\t// <-quit = Channel'dan okuma, signal gelene kadar bekler\n\t<-quit\n\n\tlog.Println(\"\ud83d\uded1 Shutting down server...\")\n\n\t// ===== 12. GRACEFUL SHUTDOWN =====\n\t// Context with timeout - 5 saniye i\u00e7inde kapat\n\t// WithTimeout = Belirli s\u00fcre sonra otomatik cancel olan context\n\tctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)\n\t// defer = Fonksiyon bitince \u00e7al\u0131\u015f\u0131r (cleanup i\u00e7in kullan\u0131l\u0131r)\n\tdefer cancel()  // Context'i serbest b\u0131rak (memory leak'i \u00f6nler)\n\n\t// Server'i graceful kapat\n\t// Graceful shutdown = Mevcut request'leri tamamla, yenilerini alma\n\tif err := srv.Shutdown(ctx); err != nil {\n\t\tlog.Fatalf(\"\u274c Server forced to shutdown: %v\", err)\n\t}\n\n\tlog.Println(\"\u2705 Server exited successfully\")\n}
+// @securityDefinitions.apikey ApiKeyAuth
+// @in header
+// @name X-API-Key
+// @description Service API key (ak_...) with the endpoint's scope.
+
+// main - Uygulamanın başlangıç fonksiyonu
+// Go programı main() fonksiyonundan çalışmaya başlar
+// Dependency Injection (DI) pattern'ı kullanılır:
+// 1. Config yükle
+// 2. Database bağlan
+// 3. Repository'leri oluştur
+// 4. Service'leri oluştur
+// 5. Use Case'leri oluştur
+// 6. Handler'ları oluştur
+// 7. Router'i kur
+// 8. Server'i başlat
+func main() {
+	// ===== 1. CONFIGURATION =====
+	// .env dosyasını yükle ve config struct'ına parse et
+	cfg, err := config.Load()
+	if err != nil {
+		// Fatalf = Error log'la ve programı sonlandır (exit code 1)
+		// %v = value formatter (any type'i string'e çevirir)
+		log.Fatalf("❌ Failed to load configuration: %v", err)
+	}
+
+	// ===== 2. GIN MODE =====
+	// Gin'in çalışma modu: "debug", "release" veya "test"
+	// debug = verbose logging, release = production mode (daha hızlı)
+	gin.SetMode(cfg.Server.Mode)
+
+	// Structured logger: request log'ları ve use case'lerin kritik olmayan hataları
+	// LOG_FORMAT=json|text, LOG_LEVEL=debug|info|warn|error
+	logger := newLogger(cfg.Logging)
+
+	// ===== 3. DATABASE CONNECTION =====
+	// PostgreSQL'e bağlan ve GORM instance'ı al
+	// & = cfg.Database struct'ının pointer'ını gönder (memory efficient)
+	db, err := database.NewPostgresDB(&cfg.Database)
+	if err != nil {
+		log.Fatalf("❌ Failed to connect to database: %v", err)
+	}
+
+	// ===== 4. REPOSITORIES (Data Access Layer) =====
+	// Repository Pattern: Database access'ı kapsülleyen layer
+	// Bu sayede database değişirse sadece repository'leri değiştiririz
+	var userRepoOpts []repository.UserRepositoryOption
+	if cfg.Database.CaseInsensitiveUsernames {
+		// Kullanıcı adları büyük/küçük harf duyarsız aranır ("Jane" = "jane")
+		userRepoOpts = append(userRepoOpts, repository.WithCaseInsensitiveUsernames())
+	}
+	userRepo := repository.NewUserRepository(db, userRepoOpts...)
+	refreshTokenRepo := repository.NewRefreshTokenRepository(db)
+	passwordResetRepo := repository.NewPasswordResetTokenRepository(db)
+	passwordHistoryRepo := repository.NewPasswordHistoryRepository(db)
+	verificationRepo := repository.NewVerificationTokenRepository(db)
+	magicLinkRepo := repository.NewMagicLinkTokenRepository(db)
+	inviteRepo := repository.NewInviteRepository(db)
+	oauthAccountRepo := repository.NewOAuthAccountRepository(db)
+	auditLogRepo := repository.NewAuditLogRepository(db)
+	apiKeyRepo := repository.NewAPIKeyRepository(db)
+	webhookDeliveryRepo := repository.NewWebhookDeliveryRepository(db)
+	organizationRepo := repository.NewOrganizationRepository(db)
+
+	// Redis: logout edilen access token'ların blacklist'i (jti -> kalan ömür kadar TTL)
+	redisClient := database.NewRedisClient(&cfg.Redis)
+	tokenBlacklist := blacklist.NewRedisTokenBlacklist(redisClient)
+	// Redis: kullanıcıların token version'ları ("tv" claim'i her istekte DB'ye gitmeden kontrol edilir)
+	// Version artınca (şifre değişikliği, force logout) cache hemen güncellenir
+	userRepo = tokenversion.NewCachedUserRepository(userRepo, tokenversion.NewRedisCache(redisClient, tokenversion.DefaultTTL))
+	// Redis: login/forgot-password rate limit sayaçları (tüm replikalar ortak sayar)
+	rateLimiter := ratelimit.NewRedisSlidingWindow(redisClient)
+	// Redis: Idempotency-Key ile gelen isteklerin cevapları (retry'da aynı cevap döner)
+	idempotencyStore := idempotency.NewRedisStore(redisClient)
+
+	// ===== 5. SERVICES (Security Layer) =====
+	// JWT token oluşturma/doğrulama servisi
+	jwtService, err := newJWTService(&cfg.JWT)
+	if err != nil {
+		log.Fatalf("❌ Failed to load JWT signing keys: %v", err)
+	}
+	// Şifre hash'leme/karşılaştırma (PASSWORD_HASH_ALGORITHM: argon2id veya bcrypt)
+	passwordHasher := newPasswordHasher(cfg.Security)
+
+	// Dış bildirimler: mail, webhook ve audit log
+	// SMTP_HOST verilmişse mail'ler SMTP ile gönderilir (HTML + text), yoksa log'a yazılır
+	// Log'da debug modda mail içeriği (token dahil) görünür, release'de sadece alıcı/konu
+	// Link'ler frontend'e yönlenir
+	var mailSender usecase.Mailer = mailer.NewLogMailer(cfg.Server.Mode == "debug")
+	if cfg.SMTP.Host != "" {
+		mailSender = mailer.NewSMTPMailer(cfg.SMTP.GetAddr(), cfg.SMTP.Username, cfg.SMTP.Password, cfg.SMTP.From)
+	}
+	// WEBHOOK_URLS boşsa olaylar gönderilmez
+	// Olaylar kuyruğa alınır ve arka planda her URL'e gönderilir (istek beklemez);
+	// başarısız gönderimler backoff ile tekrar denenir, sonuç webhook_deliveries tablosuna yazılır
+	eventPublisher := webhook.NewDispatcher(cfg.Webhook.URLs, cfg.Webhook.Secret, cfg.Webhook.Timeout,
+		cfg.Webhook.MaxAttempts, cfg.Webhook.RetryBackoff, webhookDeliveryRepo)
+	eventPublisher.Start(context.Background())
+	// Audit olayları audit_logs tablosuna yazılır (GET /admin/audit-logs ile sorgulanır)
+	auditLogger := audit.NewDBLogger(auditLogRepo)
+	// Prometheus metrics (HTTP istekleri, sebebe göre başarısız login'ler, user cache hit/miss)
+	appMetrics := metrics.New()
+	// Redis: refresh'te token sahibi DB'ye gitmeden okunur (kısa TTL, şifre hash'i saklanmaz)
+	// Kullanıcıya her yazma (profil, ban, şifre değişikliği...) cache'teki kaydı siler
+	userCache := usercache.NewRedisCache(redisClient, usercache.DefaultTTL, appMetrics)
+	userRepo = usercache.NewInvalidatingUserRepository(userRepo, userCache)
+
+	// ===== 6. USE CASES (Business Logic Layer) =====
+	// Clean Architecture'da iş mantığı use case'lerde bulunur
+	// Tüm dependencies inject edilir (DI pattern)
+	authOptions := []usecase.AuthUseCaseOption{
+		usecase.WithMailer(mailSender, cfg.Server.FrontendURL),
+		usecase.WithEventPublisher(eventPublisher),
+		// Açıksa yeni kullanıcılar admin onayı bekler
+		usecase.WithRegistrationApproval(cfg.Approval.Required),
+		usecase.WithRegistrationEnabled(cfg.Registration.Enabled),
+		usecase.WithInvites(inviteRepo, cfg.Registration.InviteRequired),
+		usecase.WithLoginMetrics(appMetrics),
+		usecase.WithUserCache(userCache),
+		usecase.WithTokenBlacklist(tokenBlacklist),
+		// "remember_me": true ile login olanların refresh token'ı JWT_REMEMBER_ME_EXPIRY (30 gün) yaşar
+		usecase.WithRememberMe(cfg.JWT.RememberMeExpiry),
+		// Her refresh oturumu uzatır; JWT_REFRESH_ABSOLUTE_TTL verildiyse login'den itibaren bu süreden sonra tekrar login gerekir
+		usecase.WithRefreshAbsoluteTTL(cfg.JWT.RefreshAbsoluteTTL),
+		usecase.WithOAuthAccounts(oauthAccountRepo),
+		// Şifresiz giriş: email'e tek kullanımlık, kısa ömürlü (MAGIC_LINK_TOKEN_TTL) link gönderilir
+		usecase.WithMagicLinks(magicLinkRepo),
+		// Son PASSWORD_HISTORY_DEPTH şifre, değiştirme/sıfırlamada tekrar kullanılamaz
+		usecase.WithPasswordHistory(passwordHistoryRepo),
+		// Multi-tenancy: email/username organizasyon içinde benzersiz; slug verilmezse "default" organizasyon
+		usecase.WithOrganizations(organizationRepo),
+		// Login, logout, şifre değişikliği ve token reuse audit log'a yazılır
+		usecase.WithAuditLogger(auditLogger),
+		// GET /me/export kullanıcının audit geçmişini buradan okur
+		usecase.WithAuditLogHistory(auditLogRepo),
+		usecase.WithLogger(logger),
+		// Register: user ve ilk refresh token birlikte kaydedilir ya da hiçbiri kaydedilmez
+		usecase.WithTransactioner(repository.NewTransactioner(db)),
+	}
+	// PASSWORD_BREACH_CHECK: yeni şifreler HaveIBeenPwned'de aranır (sadece SHA-1'in ilk 5 karakteri gider)
+	if cfg.Security.PasswordBreachCheck {
+		authOptions = append(authOptions, usecase.WithBreachChecker(hibp.NewClient(3*time.Second)))
+	}
+	// WEBAUTHN_RP_ID verilmişse passkey ile kayıt ve giriş açılır
+	// Challenge'lar Redis'te WEBAUTHN_SESSION_TTL kadar tutulur (begin ve finish farklı replikalara düşebilir)
+	if cfg.WebAuthn.RPID != "" {
+		wa, err := webauthn.New(&webauthn.Config{
+			RPID:          cfg.WebAuthn.RPID,
+			RPDisplayName: cfg.WebAuthn.RPDisplayName,
+			RPOrigins:     cfg.WebAuthn.RPOrigins,
+			Timeouts: webauthn.TimeoutsConfig{
+				Login:        webauthn.TimeoutConfig{Enforce: true, Timeout: cfg.WebAuthn.SessionTTL, TimeoutUVD: cfg.WebAuthn.SessionTTL},
+				Registration: webauthn.TimeoutConfig{Enforce: true, Timeout: cfg.WebAuthn.SessionTTL, TimeoutUVD: cfg.WebAuthn.SessionTTL},
+			},
+		})
+		if err != nil {
+			log.Fatalf("❌ Invalid WebAuthn config: %v", err)
+		}
+		authOptions = append(authOptions, usecase.WithPasskeys(wa, repository.NewCredentialRepository(db),
+			passkey.NewRedisSessionStore(redisClient), cfg.WebAuthn.SessionTTL))
+	}
+	authUseCase := usecase.NewAuthUseCase(
+		userRepo,                  // User repository
+		refreshTokenRepo,          // Token repository
+		passwordResetRepo,         // Password reset token repository
+		verificationRepo,          // Email verification token repository
+		jwtService,                // JWT service
+		passwordHasher,            // Password hasher
+		cfg.JWT.AccessTokenExpiry, // Token expiry config
+		cfg.JWT.RefreshTokenExpiry,
+		cfg.Security, // Güvenlik ayarları (şifre policy'si, token TTL'leri vs.)
+		authOptions...,
+	)
+	// Admin işlemleri (hesap onayı, ban vs.)
+	// Ban'da kullanıcının oturumları kapatılır, access token'ları blacklist'e alınır
+	// Geçici şifreler AuthUseCase'in hasher'ı ile hash'lenir, login'de doğrulanabilsin diye
+	adminUseCase := usecase.NewAdminUseCase(userRepo, mailSender, eventPublisher, auditLogger, auditLogRepo,
+		usecase.WithSessionRevocation(refreshTokenRepo, tokenBlacklist, cfg.JWT.AccessTokenExpiry),
+		usecase.WithPasswordHasher(passwordHasher),
+		usecase.WithInviteIssuing(inviteRepo, cfg.Registration.InviteTTL))
+	// Servisler arası API key'ler (X-API-Key); sadece SHA-256 hash'leri saklanır
+	apiKeyUseCase := usecase.NewAPIKeyUseCase(apiKeyRepo, auditLogger)
+
+	// ===== 7. HEALTH CHECKS =====
+	// /health/detailed için bağımlılık kontrolleri
+	// Her kontrol kendi timeout'u ile çalışır, yavaş bir bağımlılık tüm raporu bekletmez
+	healthService := health.NewService(cfg.Health.CheckTimeout)
+	// Postgres kritik: düşerse servis "unhealthy" olur
+	healthService.Register(health.NewPostgresChecker(db), true)
+	// Diğerleri kritik değil: düşerlerse servis sadece "degraded" olur
+	healthService.Register(health.NewRedisChecker(redisClient), false)
+	if cfg.SMTP.Host != "" {
+		healthService.Register(health.NewTCPChecker("smtp", cfg.SMTP.GetAddr()), false)
+	}
+	for _, endpoint := range cfg.Health.OAuthEndpoints {
+		healthService.Register(health.NewHTTPChecker("oauth:"+endpoint, endpoint), false)
+	}
+	// /ready için: sadece DB (DB olmadan hiçbir request cevaplanamaz)
+	readiness := health.NewService(cfg.Health.CheckTimeout)
+	readiness.Register(health.NewPostgresChecker(db), true)
+
+	// ===== 8. HANDLERS (Presentation Layer) =====
+	// HTTP request'leri handle eden controller'lar
+	// Use case'leri çağırır ve response döner
+	var authHandlerOpts []handler.AuthHandlerOption
+	if cfg.JWT.RefreshTokenCookie {
+		// Refresh token her client'a HttpOnly cookie ile verilir (sadece "X-Client-Type: web" olanlara değil)
+		authHandlerOpts = append(authHandlerOpts, handler.WithRefreshTokenCookie())
+	}
+	authHandler := handler.NewAuthHandler(authUseCase, jwtService, authHandlerOpts...)
+	healthHandler := handler.NewHealthHandler(healthService, readiness)
+	adminHandler := handler.NewAdminHandler(adminUseCase)
+	apiKeyHandler := handler.NewAPIKeyHandler(apiKeyUseCase)
+	// Social login: sadece client ID'si verilmiş provider'lar; hiçbiri yoksa route'lar kaydedilmez
+	var oauthHandler *handler.OAuthHandler
+	if providers := oauth.Providers(cfg.OAuth); len(providers) > 0 {
+		oauthHandler = handler.NewOAuthHandler(authUseCase, providers)
+	}
+
+	// Süresi dolmuş (ve istenirse eski iptal edilmiş) refresh token'ları periyodik sil
+	// TOKEN_CLEANUP_INTERVAL=0 ise çalışmaz
+	// Shutdown'da çalışan temizliğin bitmesi beklenir (DB kapanmadan önce)
+	var onShutdown []func() error
+	if cfg.Cleanup.Interval > 0 {
+		tokenCleaner := cleanup.NewTokenCleaner(refreshTokenRepo, cfg.Cleanup.Interval, cfg.Cleanup.RevokedRetention)
+		tokenCleaner.Start(context.Background())
+		onShutdown = append(onShutdown, func() error {
+			tokenCleaner.Stop()
+			return nil
+		})
+	}
+	// Vault'ta rotate edilen JWT secret'ı deploy gerekmeden devreye girer
+	// Vault'a ulaşılamazsa mevcut anahtarlarla devam edilir, sonraki turda tekrar denenir
+	if cfg.JWT.SecretProvider == config.JWTSecretProviderVault {
+		refreshCtx, stopRefresh := context.WithCancel(context.Background())
+		jwtService.StartKeyRefresh(refreshCtx, cfg.JWT.SecretRefreshInterval, func(err error) {
+			logger.Error("failed to refresh JWT signing keys", "error", err)
+		})
+		onShutdown = append(onShutdown, func() error {
+			stopRefresh()
+			return nil
+		})
+	}
+	// Arka planda çalışan email'ler ve son giriş zamanı yazmaları beklenir (kendi timeout'ları var)
+	onShutdown = append(onShutdown, func() error {
+		authUseCase.Stop()
+		return nil
+	})
+	// Gönderilmekte olan webhook'lar beklenir, kuyrukta kalanlar "gönderilmedi" olarak kaydedilir
+	onShutdown = append(onShutdown, func() error {
+		eventPublisher.Stop()
+		return nil
+	})
+
+	// CAPTCHA_PROVIDER verilmişse register ve forgot-password captcha_token ister
+	// Boşsa (local/dev) kontrol tamamen kapalıdır; "noop" boş olmayan her token'ı kabul eder
+	var captchaVerifier middleware.CaptchaVerifier
+	switch cfg.Captcha.Provider {
+	case "":
+	case "noop":
+		captchaVerifier = captcha.NoopVerifier{}
+	default:
+		client, err := captcha.NewClient(cfg.Captcha.Provider, cfg.Captcha.Secret, cfg.Captcha.Timeout)
+		if err != nil {
+			log.Fatalf("❌ Failed to set up CAPTCHA: %v", err)
+		}
+		captchaVerifier = client
+	}
+
+	// ===== 9. ROUTER SETUP =====
+	// Gin router'ı kur: routes, middleware, CORS
+	router := setupRouter(cfg, authHandler, healthHandler, adminHandler, apiKeyHandler, oauthHandler, jwtService, tokenBlacklist, authUseCase, apiKeyUseCase, rateLimiter, idempotencyStore, captchaVerifier, appMetrics, logger)
+
+	// ===== 10. HTTP SERVER =====
+	// Go'nun standard library HTTP server'ı
+	srv := &http.Server{
+		// Address - Server'in dinleyeceği adres ve port
+		// 0.0.0.0:5004 = Tüm network interface'lerinde 5004 portunu dinle
+		Addr: cfg.Server.Host + ":" + cfg.Server.Port,
+
+		// Handler - Gin router (http.Handler interface'ini implement eder)
+		Handler: router,
+
+		// ReadTimeout - Request body'i okumak için max süre
+		// Slowloris attack gibi saldırılara karşı koruma
+		ReadTimeout: 10 * time.Second,
+
+		// WriteTimeout - Response yazmak için max süre
+		WriteTimeout: 10 * time.Second,
+
+		// MaxHeaderBytes - Request header'ların max boyutu
+		// 1 << 20 = 1 MB (bit shift: 1 * 2^20)
+		MaxHeaderBytes: 1 << 20,
+	}
+
+	// ===== 11. RUN + GRACEFUL SHUTDOWN =====
+	// SIGINT (Ctrl+C) veya SIGTERM (deploy) gelince:
+	// 1. Yeni bağlantı kabul edilmez (load balancer /ready ile zaten trafiği çekmiş olur)
+	// 2. Devam eden request'ler SERVER_SHUTDOWN_TIMEOUT kadar beklenir
+	// 3. Arka plan işleri durdurulur, DB pool'u ve Redis bağlantısı kapatılır
+	sqlDB, err := db.DB()
+	if err != nil {
+		log.Fatalf("❌ Failed to get database pool: %v", err)
+	}
+	onShutdown = append(onShutdown, sqlDB.Close, redisClient.Close)
+
+	// ===== 12. gRPC TOKEN API =====
+	// Diğer servisler token'ı HTTP introspection yerine gRPC ile doğrular / iptal eder (pkg/authclient)
+	// Aynı API key'ler kullanılır (x-api-key metadata'sı): ValidateToken token:introspect,
+	// Revoke token:revoke scope'u ister
+	// HTTP server ile birlikte başlar ve aynı shutdown timeout'u ile durur
+	grpcServer := rpc.NewServer(cfg.Server.Host+":"+cfg.Server.GRPCPort, authUseCase, apiKeyUseCase)
+
+	log.Printf("🚀 Auth Service starting on %s:%s (gRPC :%s)", cfg.Server.Host, cfg.Server.Port, cfg.Server.GRPCPort)
+	if err := server.New(srv, cfg.Server.ShutdownTimeout, onShutdown...).AddService(grpcServer).Run(context.Background()); err != nil {
+		log.Fatalf("❌ %v", err)
+	}
+
+	log.Println("✅ Server exited successfully")
+}
+
+// newLogger - LOG_FORMAT'a göre JSON veya text handler'lı logger oluşturur
+// Bilinmeyen LOG_LEVEL info kabul edilir
+func newLogger(cfg config.LoggingConfig) *slog.Logger {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(cfg.Level)); err != nil {
+		level = slog.LevelInfo
+	}
+	opts := &slog.HandlerOptions{Level: level}
+	if cfg.Format == "text" {
+		return slog.New(slog.NewTextHandler(os.Stdout, opts))
+	}
+	return slog.New(slog.NewJSONHandler(os.Stdout, opts))
+}
+
+// newPasswordHasher - Yeni şifreler için seçilen algoritmanın hasher'ını döner
+// Eski bcrypt hash'leri Argon2id'ye geçildikten sonra da doğrulanır (hash prefix'inden anlaşılır)
+// BCRYPT_CALIBRATE_TARGET verilmişse bcrypt cost'u bu makinede ölçülerek seçilir (BCRYPT_COST yerine);
+// farklı cost'lu eski hash'ler login'de yeniden hash'lenir
+// BCRYPT_PREHASH açıksa bcrypt şifrenin SHA-256'sını hash'ler (72 byte sınırı yok);
+// eski düz bcrypt hash'leri doğrulanmaya devam eder ve login'de yeni şemaya geçer
+func newPasswordHasher(cfg config.SecurityConfig) security.PasswordHasher {
+	if cfg.PasswordHashAlgorithm == config.PasswordHashBcrypt {
+		var opts []security.BcryptOption
+		if cfg.BcryptPreHash {
+			opts = append(opts, security.WithBcryptPreHash())
+		}
+		hasher := security.NewBcryptHasher(cfg.BcryptCost, opts...)
+		if cfg.BcryptCalibrateTarget > 0 {
+			cost := hasher.CalibrateCost(cfg.BcryptCalibrateTarget)
+			log.Printf("🔐 bcrypt cost calibrated to %d (target %s per hash, BCRYPT_COST=%d ignored)", cost, cfg.BcryptCalibrateTarget, cfg.BcryptCost)
+		}
+		return hasher
+	}
+	return security.NewArgon2idHasher(security.Argon2Params{
+		Memory:      uint32(cfg.Argon2Memory),
+		Time:        uint32(cfg.Argon2Time),
+		Parallelism: uint8(cfg.Argon2Parallelism),
+	})
+}
+
+// newJWTService - JWT_PRIVATE_KEY_PATH verilmişse RS256, yoksa HS256 (JWT_SECRET veya JWT_SECRET_PROVIDER=vault) kullanır
+// RS256'da downstream servisler token'ları sadece public key ile doğrulayabilir
+func newJWTService(cfg *config.JWTConfig) (*security.JWTService, error) {
+	// JWT_ISSUER başka olan (aynı secret'ı paylaşsa bile) ve JWT_AUDIENCE'tan hiçbirine
+	// verilmemiş token'lar reddedilir. exp/nbf kontrolünde JWT_CLOCK_SKEW_LEEWAY kadar saat farkı tolere edilir
+	opts := []security.JWTOption{
+		security.WithIssuer(cfg.Issuer),
+		security.WithAudience(cfg.Audiences...),
+		security.WithLeeway(cfg.ClockSkewLeeway),
+	}
+	if cfg.PrivateKeyPath == "" {
+		// HS256 secret'ları SecretProvider'dan gelir:
+		// env: JWT_SECRET aktif, JWT_PREVIOUS_SECRETS sadece doğrulama (süreleri dolana kadar eski token'lar geçerli)
+		// vault: Vault'taki KV secret'ı; main JWT_SECRET_REFRESH_INTERVAL'da bir yeniden okur (bkz. StartKeyRefresh)
+		var provider security.SecretProvider = security.NewStaticSecretProvider(cfg.Secret, cfg.PreviousSecrets...)
+		if cfg.SecretProvider == config.JWTSecretProviderVault {
+			provider = vault.NewSecretProvider(cfg.VaultAddr, cfg.VaultToken, cfg.VaultSecretPath, 5*time.Second)
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		return security.NewJWTServiceWithProvider(ctx, provider,
+			cfg.AccessTokenExpiry,  // 15 dakika
+			cfg.RefreshTokenExpiry, // 7 gün
+			opts...,
+		)
+	}
+
+	privateKey, err := security.LoadRSAPrivateKey(cfg.PrivateKeyPath)
+	if err != nil {
+		return nil, err
+	}
+	// Public key opsiyonel: verilmezse private key'den türetilir
+	var publicKey *rsa.PublicKey
+	if cfg.PublicKeyPath != "" {
+		if publicKey, err = security.LoadRSAPublicKey(cfg.PublicKeyPath); err != nil {
+			return nil, err
+		}
+	}
+	return security.NewRSAJWTService(privateKey, publicKey, cfg.AccessTokenExpiry, cfg.RefreshTokenExpiry, opts...), nil
+}
+
+// setupRouter - Gin router'ı yapılandırır
+// Bu fonksiyon:
+// 1. Middleware'leri ekler (logger, recovery, CORS)
+// 2. Route'ları tanımlar (public ve protected)
+// 3. Handler'ları route'lara bağlar
+func setupRouter(cfg *config.Config, authHandler *handler.AuthHandler, healthHandler *handler.HealthHandler, adminHandler *handler.AdminHandler, apiKeyHandler *handler.APIKeyHandler, oauthHandler *handler.OAuthHandler, jwtService *security.JWTService, tokenBlacklist domain.TokenBlacklist, tokenVersions middleware.TokenVersionSource, apiKeys middleware.APIKeyAuthenticator, rateLimiter middleware.RateLimiter, idempotencyStore middleware.IdempotencyStore, captchaVerifier middleware.CaptchaVerifier, appMetrics *metrics.Metrics, logger *slog.Logger) *gin.Engine {
+	// Yeni Gin router oluştur (default middleware'ler YOK)
+	// gin.New() vs gin.Default():
+	// - New() = Boş router (middleware kendimiz ekleriz)
+	// - Default() = Logger + Recovery middleware'li
+	router := gin.New()
+
+	// Client IP (rate limit key'leri, audit log) sadece güvenilen proxy'lerin X-Forwarded-For'undan alınır
+	// Gin varsayılan olarak her proxy'ye güvenir: header'ı her istekte değiştiren client IP limitlerini aşardı.
+	// TRUSTED_PROXIES boşsa (nil) hiçbir proxy'ye güvenilmez, client IP bağlantının adresidir.
+	if err := router.SetTrustedProxies(cfg.Server.TrustedProxies); err != nil {
+		log.Fatalf("❌ Invalid TRUSTED_PROXIES: %v", err)
+	}
+
+	// ===== MIDDLEWARE =====
+	// Middleware = Her request'te çalışan fonksiyonlar (chain of responsibility pattern)
+	// Sıralama önemli! Yukarıdan aşağıya çalışır.
+
+	// 1. Request ID + Logger - Her request'e X-Request-ID verir (client gönderdiyse onu kullanır)
+	// ve JSON log satırı yazar (method, path, status, latency, request ID, user ID)
+	// Aynı ID audit kayıtlarına da yazılır
+	router.Use(middleware.RequestID(), middleware.RequestLogger(logger))
+
+	// 2. Recovery - Panic olursa yakalar ve 500 döner (crash önler)
+	// Go'da panic = exception gibi, ama kullanımı nadir
+	router.Use(gin.Recovery())
+
+	// Metrics - Her request'in method, route pattern, status ve süresini kaydeder
+	// Route pattern kullanılır (/users/:id), gerçek path değil: ID'ler label olmaz
+	router.Use(middleware.Metrics(appMetrics))
+
+	// 3. CORS - Cross-Origin Resource Sharing
+	// Frontend (React, Vue vs.) farklı domain'den API'yi çağırabilsin
+	// Örnek: Frontend http://localhost:3000, Backend http://localhost:5004
+	// Varsayılan: hiçbir origin'e izin yok, CORS_ALLOWED_ORIGINS ile tek tek açılır
+	router.Use(middleware.CORS(middleware.CORSConfig{
+		// AllowedOrigins - Hangi origin'lerden request kabul edilir
+		// .env'den gelir: "http://localhost:3000,http://localhost:5000"
+		AllowedOrigins: cfg.CORS.AllowedOrigins,
+
+		// AllowedMethods - Hangi HTTP methodları izinli (GET, POST, PUT, DELETE vs.)
+		AllowedMethods: cfg.CORS.AllowedMethods,
+
+		// AllowedHeaders - Hangi header'lar gönderilebilir (Authorization, X-Request-ID vs.)
+		AllowedHeaders: cfg.CORS.AllowedHeaders,
+
+		// ExposedHeaders - Frontend'in okuyabileceği response header'ları
+		ExposedHeaders: []string{middleware.RequestIDHeader, "Retry-After"},
+
+		// AllowCredentials - Cookie gönderilebilir mi ("*" origin ile birlikte kullanılamaz)
+		AllowCredentials: cfg.CORS.AllowCredentials,
+
+		// MaxAge - Preflight request cache süresi (OPTIONS request'i tekrarlanmaz)
+		MaxAge: cfg.CORS.MaxAge,
+	}))
+
+	// ===== INTERNAL REQUEST SIGNING =====
+	// Internal endpoint'ler için HMAC imza kontrolü (user auth'tan bağımsız)
+	// Hangi route'ların imza isteyeceği REQUEST_SIGNING_ENDPOINTS ile seçilir;
+	// secret boşsa middleware hiçbir şey yapmaz
+	requestSignature := middleware.RequestSignature(middleware.RequestSignatureConfig{
+		Secret:  []byte(cfg.Signing.Secret),
+		MaxSkew: cfg.Signing.MaxSkew,
+		Paths:   cfg.Signing.Endpoints,
+		Replay:  middleware.NewMemoryReplayCache(),
+	})
+
+	// Middleware sadece takıldığı route'larda çalışır: listedeki bir route'a takılı değilse
+	// imza sessizce hiç kontrol edilmezdi. Bu yüzden başlangıçta reddedilir.
+	// Yeni bir route'a requestSignature eklenince buraya da eklenmeli.
+	signableRoutes := map[string]bool{"/health/detailed": true, "/metrics": true, "/api/auth/introspect": true}
+	for _, endpoint := range cfg.Signing.Endpoints {
+		if !signableRoutes[endpoint] {
+			log.Fatalf("❌ REQUEST_SIGNING_ENDPOINTS: %s does not check request signatures", endpoint)
+		}
+	}
+
+	// ===== HEALTH CHECK =====
+	// Kubernetes, Docker, load balancer'lar için
+	// GET /health -> 200 OK = process ayakta (liveness; bağımlılıklara bakmaz, DB düşünce restart tetiklemez)
+	router.GET("/health", authHandler.Health)
+
+	// GET /ready -> 200 = DB erişilebilir, trafik alabilir; 503 = load balancer'dan çıkar (readiness)
+	router.GET("/ready", healthHandler.Ready)
+
+	// GET /health/detailed -> Her bağımlılığın durumu, latency'si ve kontrol zamanı
+	// Sadece internal network'ten erişilebilir (altyapı detaylarını dışarı sızdırmamak için)
+	// REQUEST_SIGNING_ENDPOINTS içindeyse ayrıca imzalı olmalı
+	router.GET("/health/detailed", middleware.InternalOnly(), requestSignature, healthHandler.Detailed)
+
+	// GET /metrics - Prometheus scrape endpoint'i (sadece internal network)
+	router.GET("/metrics", middleware.InternalOnly(), requestSignature, gin.WrapH(appMetrics.Handler()))
+
+	// GET /errors - Makine tarafından okunabilir hata kodu kataloğu (public, cache'lenebilir)
+	router.GET("/errors", handler.ErrorCatalog)
+
+	// GET /swagger/index.html - Swagger UI; spec /swagger/doc.json (docs/ package'ından, make swagger ile üretilir)
+	// "Authorize" ile Bearer token veya X-API-Key girilip korumalı route'lar denenebilir
+	// SWAGGER_ENABLED=false ile kapatılır (örn: production'da API yüzeyini göstermemek için)
+	if cfg.Server.SwaggerEnabled {
+		router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
+	}
+
+	// ===== API ROUTES =====
+	// Route grouping - "/api" prefix'li tüm route'lar
+	// Login ve link isteyen endpoint'ler için hesaptan bağımsız IP limiti
+	// (route'a göre ayrı sayılır: key'in başında route var)
+	ipRateLimit := middleware.RateLimit(rateLimiter, middleware.KeyByIP,
+		cfg.Security.IPRateLimitRequests, cfg.Security.RateLimitWindow)
+
+	// Group = Route'ları organize etmek için (namespace gibi)
+	api := router.Group("/api")
+	{
+		// Auth route group - "/api/auth" prefix'li route'lar
+		auth := api.Group("/auth")
+		{
+			// ===== PUBLIC ROUTES (Authentication gerekmez) =====
+			// POST /api/auth/register - Yeni kullanıcı kaydı
+			// Idempotency-Key header'ı ile gelen retry'lar ilk başarılı cevabı (201 + token'lar)
+			// IDEMPOTENCY_KEY_TTL boyunca tekrar alır, ErrUserAlreadyExists almaz
+			// CAPTCHA_PROVIDER açıksa captcha_token zorunlu (Idempotency'den sonra: replay edilen retry'lar
+			// tek kullanımlık token'ı tekrar doğrulatmaz)
+			auth.POST("/register", middleware.Idempotency(idempotencyStore, cfg.Security.IdempotencyKeyTTL),
+				middleware.Captcha(captchaVerifier), authHandler.Register)
+
+			// POST /api/auth/login - Kullanıcı girişi
+			// IP + email/username başına RATE_LIMIT_REQUESTS / RATE_LIMIT_WINDOW; aşılırsa 429 + Retry-After
+			// (Sadece IP'ye göre sıkı sınırlasaydık aynı NAT arkasındaki herkes birlikte kilitlenirdi)
+			// Ayrıca IP başına daha geniş IP_RATE_LIMIT_REQUESTS: tek IP her istekte farklı hesap deneyemesin
+			auth.POST("/login", ipRateLimit, middleware.RateLimit(rateLimiter, middleware.KeyByIPAndField("email_or_username"),
+				cfg.Security.RateLimitRequests, cfg.Security.RateLimitWindow), authHandler.Login)
+
+			// POST /api/auth/refresh - Token yenileme
+			auth.POST("/refresh", authHandler.RefreshToken)
+
+			// POST /api/auth/forgot-password - Şifre sıfırlama link'i iste
+			// Email kayıtlı olmasa da aynı cevap döner (user enumeration koruması)
+			// Mail bombalamaya karşı login ile aynı limitler; CAPTCHA_PROVIDER açıksa captcha_token zorunlu
+			auth.POST("/forgot-password", ipRateLimit, middleware.RateLimit(rateLimiter, middleware.KeyByIPAndField("email"),
+				cfg.Security.RateLimitRequests, cfg.Security.RateLimitWindow), middleware.Captcha(captchaVerifier), authHandler.ForgotPassword)
+
+			// POST /api/auth/magic-link - Şifresiz giriş link'i iste
+			// Email kayıtlı olmasa da aynı cevap döner; forgot-password ile aynı limitler
+			auth.POST("/magic-link", ipRateLimit, middleware.RateLimit(rateLimiter, middleware.KeyByIPAndField("email"),
+				cfg.Security.RateLimitRequests, cfg.Security.RateLimitWindow), authHandler.RequestMagicLink)
+
+			// GET /api/auth/magic-link/callback?token=... - Link'teki token'ı tüket, token'ları döner
+			auth.GET("/magic-link/callback", authHandler.MagicLinkCallback)
+
+			// POST /api/auth/reset-password - Token ile yeni şifre belirle
+			auth.POST("/reset-password", authHandler.ResetPassword)
+
+			// GET /api/auth/reset-password/validate?token=... - Reset token'ı tüketmeden kontrol et
+			// Reset sayfası açılırken bozuk/süresi dolmuş link'i hemen göstermek için
+			auth.GET("/reset-password/validate", authHandler.ValidateResetToken)
+
+			// POST /api/auth/password-strength - Şifre gücünü tahmin et (hiçbir şey saklanmaz)
+			// Kayıt formunda kullanıcı yazarken gücü göstermek için
+			auth.POST("/password-strength", authHandler.PasswordStrength)
+
+			// GET /api/auth/availability?username=...&email=... - Kayıt formu için email/username boşta mı?
+			// Hesap enumeration'ına açık: sadece IP'ye göre ve login'den sıkı limit
+			// (AVAILABILITY_RATE_LIMIT_REQUESTS / RATE_LIMIT_WINDOW); her istekte farklı değer denendiği için alan bazlı key işe yaramaz
+			auth.GET("/availability", middleware.RateLimit(rateLimiter, middleware.KeyByIP,
+				cfg.Security.AvailabilityRateLimitRequests, cfg.Security.RateLimitWindow), authHandler.CheckAvailability)
+
+			// POST /api/auth/introspect - RFC 7662 token introspection (gateway'ler ve diğer servisler için)
+			// Çağıran servis "token:introspect" scope'lu bir API key göndermeli (X-API-Key)
+			// REQUEST_SIGNING_ENDPOINTS içindeyse ayrıca imzalı olmalı
+			auth.POST("/introspect", requestSignature, middleware.APIKeyMiddleware(apiKeys),
+				middleware.RequireScope(domain.ScopeTokenIntrospect), authHandler.Introspect)
+
+			// GET /api/auth/oauth/:provider/login -> Provider'a (google, github) yönlendirir,
+			// /callback kendi token'larımızı döner. State cookie'si ile CSRF'e karşı korunur
+			if oauthHandler != nil {
+				auth.GET("/oauth/:provider/login", oauthHandler.Login)
+				auth.GET("/oauth/:provider/callback", oauthHandler.Callback)
+			}
+
+			// POST /api/auth/passkeys/login/begin -> navigator.credentials.get() options'ı (kullanıcı adı gerekmez)
+			// POST /api/auth/passkeys/login/finish -> İmzayı doğrular, /login gibi token'ları döner
+			// WEBAUTHN_RP_ID boşsa route'lar yok (404)
+			if cfg.WebAuthn.RPID != "" {
+				auth.POST("/passkeys/login/begin", middleware.RateLimit(rateLimiter, middleware.KeyByIP,
+					cfg.Security.RateLimitRequests, cfg.Security.RateLimitWindow), authHandler.BeginPasskeyLogin)
+				auth.POST("/passkeys/login/finish", authHandler.FinishPasskeyLogin)
+			}
+
+			// POST /api/auth/verify-email - Email doğrulama link'indeki token'ı tüket
+			auth.POST("/verify-email", authHandler.VerifyEmail)
+
+			// POST /api/auth/verify-email/resend - Giriş yapmadan yeni doğrulama mail'i iste
+			// Email kayıtlı olmasa da aynı cevap döner; aynı hesaba VERIFICATION_RESEND_COOLDOWN içinde ikinci mail gönderilmez (cevap yine aynı)
+			auth.POST("/verify-email/resend", middleware.RateLimit(rateLimiter, middleware.KeyByIPAndField("email"),
+				cfg.Security.RateLimitRequests, cfg.Security.RateLimitWindow), authHandler.RequestVerificationEmail)
+
+			// GET /api/auth/me - Mevcut kullanıcı bilgisi (veritabanından güncel profil)
+			// Frontend'de "Profil" sayfası için
+			// Salt-okunur: JWT_EXPIRED_TOKEN_GRACE kadar önce süresi dolmuş token'lar da kabul edilir
+			// (refresh yolda iken gereksiz 401 almamak için). State değiştiren route'lar buraya eklenmemeli!
+			auth.GET("/me", middleware.ReadOnlyAuthMiddleware(jwtService, tokenBlacklist, tokenVersions, cfg.JWT.ExpiredTokenGrace), authHandler.Me)
+
+			// ===== PROTECTED ROUTES (JWT token gerekir) =====
+			// Sub-group oluştur ve middleware ekle
+			protected := auth.Group("")
+			// AuthMiddleware - JWT token'ı doğrular
+			// Token geçersizse veya logout ile blacklist'e alınmışsa 401 Unauthorized döner
+			// OrganizationScope - :orgID taşıyan route'larda token'ın organizasyonu değilse 403
+			// RequirePasswordChanged - Admin'in verdiği geçici şifreyle giriş yapan kullanıcı şifresini
+			// değiştirene kadar sadece şifre değiştirip çıkış yapabilir (diğerleri 403)
+			protected.Use(middleware.AuthMiddleware(jwtService, tokenBlacklist, tokenVersions), middleware.OrganizationScope(),
+				middleware.RequirePasswordChanged("/api/auth/password", "/api/auth/logout"))
+			{
+				// POST /api/auth/logout - Kullanıcı çıkışı
+				// Token'dan user ID çıkarılır (middleware set eder)
+				protected.POST("/logout", authHandler.Logout)
+
+				// PUT /api/auth/me - Ad/soyad güncelle (email ve username değiştirilemez)
+				// State değiştirdiği için GET /me'nin grace'li middleware'i DEĞİL, normal AuthMiddleware
+				protected.PUT("/me", authHandler.UpdateProfile)
+
+				// GET /api/auth/me/export - Kullanıcının tüm verisini JSON dosyası olarak indir (KVKK/GDPR)
+				// Profil + aktif oturumlar + audit geçmişi; şifre hash'i ve token'lar yok
+				// Tüm audit geçmişini okuduğu için kullanıcı başına DATA_EXPORT_RATE_LIMIT_REQUESTS / RATE_LIMIT_WINDOW
+				protected.GET("/me/export", middleware.RateLimit(rateLimiter, middleware.KeyByUser,
+					cfg.Security.DataExportRateLimitRequests, cfg.Security.RateLimitWindow), authHandler.ExportUserData)
+
+				// PUT /api/auth/username - Username değiştir (benzersiz olmalı, USERNAME_CHANGE_COOLDOWN'da bir kez)
+				// Eski username'i taşıyan access token'lar geçersiz olur; istemci refresh ile yenisini alır
+				protected.PUT("/username", authHandler.ChangeUsername)
+
+				// POST /api/auth/deactivate - Hesabı devre dışı bırak (şifre gerekir, tüm oturumlar kapanır)
+				protected.POST("/deactivate", authHandler.DeactivateAccount)
+
+				// DELETE /api/auth/me - Hesabı sil (soft delete; email/username anonimleşir, şifre gerekir)
+				protected.DELETE("/me", authHandler.DeleteAccount)
+
+				// PUT /api/auth/password - Şifre değiştir (mevcut şifre gerekir)
+				// Diğer cihazlardaki oturumlar kapanır, bu oturum açık kalır
+				protected.PUT("/password", authHandler.ChangePassword)
+
+				// POST /api/auth/resend-verification - Yeni doğrulama mail'i gönder
+				// Eski link'ler geçersiz olur; VERIFICATION_RESEND_COOLDOWN içinde ikinci mail gönderilmez (429)
+				// Kurbanın email'iyle kayıt olup döngüde çağırmaya karşı ayrıca kullanıcı başına limit
+				protected.POST("/resend-verification", middleware.RateLimit(rateLimiter, middleware.KeyByUser,
+					cfg.Security.RateLimitRequests, cfg.Security.RateLimitWindow), authHandler.ResendVerification)
+
+				// GET /api/auth/session - "Hâlâ giriş yapmış mıyım?" kontrolü (SPA keep-alive)
+				// DB'ye gitmez: sadece middleware'in doğruladığı claim'ler + blacklist; kalan süre ile refresh zamanlanır
+				protected.GET("/session", authHandler.CurrentSession)
+
+				// GET /api/auth/sessions - Aktif oturumlar (cihaz, IP, son kullanım)
+				protected.GET("/sessions", authHandler.ListSessions)
+
+				// GET /api/auth/linked-accounts - Bağlı social login hesapları (provider + bağlanma zamanı)
+				// DELETE /api/auth/linked-accounts/:provider - Bağlantıyı kaldır
+				// Şifresi olmayan kullanıcının son provider'ı kaldırılamaz (409, önce şifre belirlemeli)
+				protected.GET("/linked-accounts", authHandler.ListLinkedAccounts)
+				protected.DELETE("/linked-accounts/:provider", authHandler.UnlinkAccount)
+
+				// POST /api/auth/passkeys/register/begin -> navigator.credentials.create() options'ı
+				// POST /api/auth/passkeys/register/finish -> Yeni passkey'in public key'ini saklar
+				if cfg.WebAuthn.RPID != "" {
+					protected.POST("/passkeys/register/begin", authHandler.BeginPasskeyRegistration)
+					protected.POST("/passkeys/register/finish", authHandler.FinishPasskeyRegistration)
+				}
+			}
+		}
+
+		// Admin route group - "/api/admin" prefix'li route'lar
+		// Internal network + geçerli JWT + "admin" rolü gerekir
+		// Ayrıca her route kendi scope'unu ister (users:read, users:write, api_keys:manage)
+		admin := api.Group("/admin")
+		admin.Use(middleware.InternalOnly(), middleware.AuthMiddleware(jwtService, tokenBlacklist, tokenVersions), middleware.OrganizationScope(),
+			middleware.RequirePasswordChanged(), middleware.RequireRole(domain.RoleAdmin))
+		{
+			// GET /api/admin/users?role=admin&page=1&page_size=20 - Kullanıcıları (role göre) listele
+			admin.GET("/users", middleware.RequireScope(domain.ScopeUsersRead), adminHandler.ListUsers)
+
+			// POST /api/admin/users/:id/approve - Onay bekleyen hesabı aktif et
+			admin.POST("/users/:id/approve", middleware.RequireScope(domain.ScopeUsersWrite), adminHandler.ApproveUser)
+
+			// POST /api/admin/users/:id/reject - Onay bekleyen hesabı reddet (silinir)
+			admin.POST("/users/:id/reject", middleware.RequireScope(domain.ScopeUsersWrite), adminHandler.RejectUser)
+
+			// POST /api/admin/users/:id/ban - Hesabı devre dışı bırak, tüm oturumlarını hemen kapat
+			admin.POST("/users/:id/ban", middleware.RequireScope(domain.ScopeUsersWrite), adminHandler.BanUser)
+
+			// POST /api/admin/users/:id/unban - Banlanan hesabı tekrar aktif et
+			admin.POST("/users/:id/unban", middleware.RequireScope(domain.ScopeUsersWrite), adminHandler.UnbanUser)
+
+			// POST /api/admin/users/:id/logout - Hesabı banlamadan tüm oturumlarını kapat (ele geçirilmiş hesaplar)
+			admin.POST("/users/:id/logout", middleware.RequireScope(domain.ScopeUsersWrite), adminHandler.ForceLogout)
+
+			// POST /api/admin/users/:id/reset-password - Geçici şifre ver (kullanıcıya mail'lenir ve response'ta döner)
+			// Tüm oturumlar kapanır; kullanıcı girişten sonra şifresini değiştirmek zorunda
+			admin.POST("/users/:id/reset-password", middleware.RequireScope(domain.ScopeUsersWrite), adminHandler.ResetPassword)
+
+			// GET /api/admin/users/:id/sessions - Kullanıcının oturumları (IP, user-agent, son kullanım)
+			// ?include_revoked=true -> iptal edilmiş/süresi dolmuş oturumlar da (inceleme için zaman çizelgesi)
+			admin.GET("/users/:id/sessions", middleware.RequireScope(domain.ScopeUsersRead), adminHandler.ListUserSessions)
+
+			// PUT /api/admin/users/:id/role - Kullanıcının rolünü değiştir (user/admin)
+			admin.PUT("/users/:id/role", middleware.RequireScope(domain.ScopeUsersWrite), adminHandler.ChangeRole)
+
+			// POST /api/admin/users/verify - Import edilen kullanıcıların email'lerini toplu doğrula
+			admin.POST("/users/verify", middleware.RequireScope(domain.ScopeUsersWrite), adminHandler.BulkVerifyEmails)
+
+			// POST /api/admin/users/import - Başka sistemden taşınan kullanıcıları şifre hash'leriyle oluştur (en fazla 500)
+			admin.POST("/users/import", middleware.RequireScope(domain.ScopeUsersWrite), adminHandler.ImportUsers)
+
+			// POST /api/admin/invites - Admin'in organizasyonuna tek kullanımlık kayıt daveti; token sadece bu cevapta döner
+			// REGISTRATION_INVITE_REQUIRED açıkken kayıt sadece bu token'la (invite_token) yapılabilir
+			admin.POST("/invites", middleware.RequireScope(domain.ScopeUsersWrite), adminHandler.CreateInvite)
+
+			// GET /api/admin/audit-logs?user_id=...&action=login_failed - Güvenlik olayları, en yeni önce
+			admin.GET("/audit-logs", middleware.RequireScope(domain.ScopeUsersRead), adminHandler.ListAuditLogs)
+
+			// POST /api/admin/api-keys - Servis için API key oluştur; düz key sadece bu cevapta döner
+			admin.POST("/api-keys", middleware.RequireScope(domain.ScopeAPIKeysManage), apiKeyHandler.CreateAPIKey)
+
+			// GET /api/admin/api-keys - API key'leri listele (key'lerin kendisi dönmez)
+			admin.GET("/api-keys", middleware.RequireScope(domain.ScopeAPIKeysManage), apiKeyHandler.ListAPIKeys)
+
+			// DELETE /api/admin/api-keys/:id - API key'i iptal et
+			admin.DELETE("/api-keys/:id", middleware.RequireScope(domain.ScopeAPIKeysManage), apiKeyHandler.RevokeAPIKey)
+		}
+	}
 
-// setupRouter - Gin router'\u0131 yap\u0131land\u0131r\u0131r\n// Bu fonksiyon:\n// 1. Middleware'leri ekler (logger, recovery, CORS)\n// 2. Route'lar\u0131 tan\u0131mlar (public ve protected)\n// 3. Handler'lar\u0131 route'lara ba\u011flar\nfunc setupRouter(cfg *config.Config, authHandler *handler.AuthHandler, jwtService *security.JWTService) *gin.Engine {\n\t// Yeni Gin router olu\u015ftur (default middleware'ler YOK)\n\t// gin.New() vs gin.Default():\n\t// - New() = Bo\u015f router (middleware kendimiz ekleriz)\n\t// - Default() = Logger + Recovery middleware'li\n\trouter := gin.New()\n\n\t// ===== MIDDLEWARE =====\n\t// Middleware = Her request'te \u00e7al\u0131\u015fan fonksiyonlar (chain of responsibility pattern)\n\t// S\u0131ralama \u00f6nemli! Yukar\u0131dan a\u015fa\u011f\u0131ya \u00e7al\u0131\u015f\u0131r.\n\t\n\t// 1. Logger - Request'leri loglar (method, path, status, latency)\n\trouter.Use(gin.Logger())\n\t\n\t// 2. Recovery - Panic olursa yakalar ve 500 d\u00f6ner (crash \u00f6nler)\n\t// Go'da panic = exception gibi, ama kullan\u0131m\u0131 nadir\n\trouter.Use(gin.Recovery())\n\n\t// 3. CORS - Cross-Origin Resource Sharing\n\t// Frontend (React, Vue vs.) farkl\u0131 domain'den API'yi \u00e7a\u011f\u0131rabilsin\n\t// \u00d6rnek: Frontend http://localhost:3000, Backend http://localhost:5004\n\trouter.Use(cors.New(cors.Config{\n\t\t// AllowOrigins - Hangi origin'lerden request kabul edilir\n\t\t// .env'den gelir: \"http://localhost:3000,http://localhost:5000\"\n\t\tAllowOrigins:     cfg.CORS.AllowedOrigins,\n\t\t\n\t\t// AllowMethods - Hangi HTTP methodlar\u0131 izinli (GET, POST, PUT, DELETE vs.)\n\t\tAllowMethods:     cfg.CORS.AllowedMethods,\n\t\t\n\t\t// AllowHeaders - Hangi header'lar g\u00f6nderilebilir (Authorization, Content-Type vs.)\n\t\tAllowHeaders:     cfg.CORS.AllowedHeaders,\n\t\t\n\t\t// AllowCredentials - Cookie ve Authorization header g\u00f6nderilebilir mi\n\t\tAllowCredentials: true,\n\t\t\n\t\t// MaxAge - Preflight request cache s\u00fcresi (OPTIONS request'i tekrarlanmaz)\n\t\tMaxAge:           12 * time.Hour,\n\t}))\n\n\t// ===== HEALTH CHECK =====\n\t// Kubernetes, Docker, load balancer'lar i\u00e7in\n\t// GET /health -> 200 OK = servis sa\u011fl\u0131kl\u0131\n\trouter.GET(\"/health\", authHandler.Health)\n\n\t// ===== API ROUTES =====\n\t// Route grouping - \"/api\" prefix'li t\u00fcm route'lar\n\t// Group = Route'lar\u0131 organize etmek i\u00e7in (namespace gibi)\n\tapi := router.Group(\"/api\")\n\t{\n\t\t// Auth route group - \"/api/auth\" prefix'li route'lar\n\t\tauth := api.Group(\"/auth\")\n\t\t{\n\t\t\t// ===== PUBLIC ROUTES (Authentication gerekmez) =====\n\t\t\t// POST /api/auth/register - Yeni kullan\u0131c\u0131 kayd\u0131\n\t\t\tauth.POST(\"/register\", authHandler.Register)\n\t\t\t\n\t\t\t// POST /api/auth/login - Kullan\u0131c\u0131 giri\u015fi\n\t\t\tauth.POST(\"/login\", authHandler.Login)\n\t\t\t\n\t\t\t// POST /api/auth/refresh - Token yenileme\n\t\t\tauth.POST(\"/refresh\", authHandler.RefreshToken)\n\n\t\t\t// ===== PROTECTED ROUTES (JWT token gerekir) =====\n\t\t\t// Sub-group olu\u015ftur ve middleware ekle\n\t\t\tprotected := auth.Group(\"\")\n\t\t\t// AuthMiddleware - JWT token'\u0131 do\u011frular\n\t\t\t// Token ge\u00e7ersizse 401 Unauthorized d\u00f6ner\n\t\t\tprotected.Use(middleware.AuthMiddleware(jwtService))\n\t\t\t{\n\t\t\t\t// POST /api/auth/logout - Kullan\u0131c\u0131 \u00e7\u0131k\u0131\u015f\u0131\n\t\t\t\t// Token'dan user ID \u00e7\u0131kar\u0131l\u0131r (middleware set eder)\n\t\t\t\tprotected.POST(\"/logout\", authHandler.Logout)\n\t\t\t\t\n\t\t\t\t// GET /api/auth/me - Mevcut kullan\u0131c\u0131 bilgisi\n\t\t\t\t// Frontend'de \"Profil\" sayfas\u0131 i\u00e7in\n\t\t\t\tprotected.GET(\"/me\", authHandler.Me)\n\t\t\t}\n\t\t}\n\t}\n\n\t// Router'\u0131 d\u00f6nd\u00fcr\n\treturn router\n}
+	// Router'ı döndür
+	return router
+}
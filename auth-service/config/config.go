@@ -1,9 +1,12 @@
 package config
 
 import (
+	"errors"
 	"fmt"
+	"net"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/joho/godotenv"
@@ -11,19 +14,41 @@ import (
 
 // Config holds all configuration for the application
 type Config struct {
-	Server   ServerConfig
-	Database DatabaseConfig
-	Redis    RedisConfig
-	JWT      JWTConfig
-	Security SecurityConfig
-	CORS     CORSConfig
-	Logging  LoggingConfig
+	Server       ServerConfig
+	Database     DatabaseConfig
+	Redis        RedisConfig
+	JWT          JWTConfig
+	Security     SecurityConfig
+	CORS         CORSConfig
+	Logging      LoggingConfig
+	SMTP         SMTPConfig
+	Health       HealthConfig
+	Signing      RequestSigningConfig
+	Approval     ApprovalConfig
+	Registration RegistrationConfig
+	Webhook      WebhookConfig
+	OAuth        OAuthConfig
+	WebAuthn     WebAuthnConfig
+	Cleanup      CleanupConfig
+	Captcha      CaptchaConfig
 }
 
 type ServerConfig struct {
 	Port string
 	Host string
 	Mode string
+	// GRPCPort serves the gRPC token API (pkg/authpb) for other services
+	GRPCPort string
+	// ShutdownTimeout is how long in-flight requests may run after SIGTERM
+	ShutdownTimeout time.Duration
+	// FrontendURL is the base URL used for links in outgoing emails
+	FrontendURL string
+	// SwaggerEnabled serves the generated API docs at /swagger/index.html
+	SwaggerEnabled bool
+	// TrustedProxies are the IPs or CIDRs of reverse proxies whose
+	// X-Forwarded-For header is believed. Empty trusts no proxy: the client
+	// IP (rate limits, audit logs) is the address of the connection
+	TrustedProxies []string
 }
 
 type DatabaseConfig struct {
@@ -33,6 +58,21 @@ type DatabaseConfig struct {
 	Password string
 	DBName   string
 	SSLMode  string
+	// CaseInsensitiveUsernames stores a case-folded copy of each username in
+	// username_normalized and looks usernames up by it. Enabling it backfills
+	// existing rows at startup; the display username keeps its original casing
+	CaseInsensitiveUsernames bool
+
+	// Connection pool; 0 for MaxOpenConns, ConnMaxLifetime or ConnMaxIdleTime
+	// means unlimited. Keep MaxOpenConns times the replica count below the
+	// server's max_connections
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime time.Duration
+	ConnMaxIdleTime time.Duration
+	// LogLevel is the GORM log level: silent, error, warn (slow queries and
+	// errors) or info (every statement, including their parameters)
+	LogLevel string
 }
 
 type RedisConfig struct {
@@ -43,21 +83,129 @@ type RedisConfig struct {
 }
 
 type JWTConfig struct {
-	Secret              string
-	AccessTokenExpiry   time.Duration
-	RefreshTokenExpiry  time.Duration
+	Secret             string
+	AccessTokenExpiry  time.Duration
+	RefreshTokenExpiry time.Duration
+	// RememberMeExpiry is the refresh token lifetime of logins with
+	// remember_me set; it must not be shorter than RefreshTokenExpiry
+	RememberMeExpiry time.Duration
+	// RefreshAbsoluteTTL caps a session at this long after login, however
+	// often its refresh token is rotated; 0 lets sessions slide forever
+	RefreshAbsoluteTTL time.Duration
+	// ExpiredTokenGrace is how long after expiry an access token is still
+	// accepted on read-only endpoints; 0 disables the grace period
+	ExpiredTokenGrace time.Duration
+	// RefreshTokenCookie sends refresh tokens to every client in an HttpOnly
+	// cookie instead of the response body, not only to clients sending
+	// "X-Client-Type: web"
+	RefreshTokenCookie bool
+	// Issuer is the "iss" claim of issued tokens; tokens from any other
+	// issuer are rejected
+	Issuer string
+	// Audiences are stamped into tokens as "aud"; a token is only accepted
+	// if it names at least one of them. Empty disables the audience check
+	Audiences []string
+	// ClockSkewLeeway is the clock difference between replicas tolerated
+	// when checking exp, nbf and iat
+	ClockSkewLeeway time.Duration
+	// PrivateKeyPath switches signing to RS256 when set; PublicKeyPath is
+	// optional and defaults to the public half of the private key
+	PrivateKeyPath string
+	PublicKeyPath  string
+	// PreviousSecrets are HS256 secrets rotated out of JWT_SECRET, newest
+	// first. They only verify tokens, for one access-token lifetime after startup
+	PreviousSecrets []string
+	// SecretProvider is where the HS256 secrets come from: "env" (JWT_SECRET
+	// and JWT_PREVIOUS_SECRETS) or "vault" (a KV v2 secret with "current"
+	// and "previous" fields, re-read every SecretRefreshInterval)
+	SecretProvider        string
+	SecretRefreshInterval time.Duration
+	VaultAddr             string
+	VaultToken            string
+	VaultSecretPath       string
 }
 
-type SecurityConfig struct {
-	BcryptCost       int
-	MaxLoginAttempts int
-	LockoutDuration  time.Duration
+// JWT secret providers (JWT_SECRET_PROVIDER)
+const (
+	JWTSecretProviderEnv   = "env"
+	JWTSecretProviderVault = "vault"
+)
+
+// minJWTSecretLength is the shortest JWT_SECRET accepted: HS256 keys
+// shorter than the 32-byte hash output weaken the signature
+const minJWTSecretLength = 32
+
+// Validate reports every invalid setting at once
+func (c JWTConfig) Validate() error {
+	var errs []error
+
+	switch c.SecretProvider {
+	case JWTSecretProviderEnv:
+	case JWTSecretProviderVault:
+		if c.PrivateKeyPath != "" {
+			errs = append(errs, fmt.Errorf("JWT_SECRET_PROVIDER=vault cannot be combined with JWT_PRIVATE_KEY_PATH"))
+		}
+		if c.VaultAddr == "" || c.VaultToken == "" || c.VaultSecretPath == "" {
+			errs = append(errs, fmt.Errorf("JWT_SECRET_PROVIDER=vault requires VAULT_ADDR, VAULT_TOKEN and VAULT_JWT_SECRET_PATH"))
+		}
+		if c.SecretRefreshInterval <= 0 {
+			errs = append(errs, fmt.Errorf("JWT_SECRET_REFRESH_INTERVAL must be a positive duration like 5m, got %s", c.SecretRefreshInterval))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("JWT_SECRET_PROVIDER must be env or vault, got %q", c.SecretProvider))
+	}
+	if c.PrivateKeyPath == "" && c.SecretProvider == JWTSecretProviderEnv {
+		if c.Secret == "" {
+			errs = append(errs, fmt.Errorf("JWT_SECRET is required unless JWT_PRIVATE_KEY_PATH is set"))
+		} else if len(c.Secret) < minJWTSecretLength {
+			errs = append(errs, fmt.Errorf("JWT_SECRET must be at least %d bytes, got %d", minJWTSecretLength, len(c.Secret)))
+		}
+	}
+	if c.AccessTokenExpiry <= 0 {
+		errs = append(errs, fmt.Errorf("JWT_ACCESS_TOKEN_EXPIRY must be a positive duration like 15m, got %s", c.AccessTokenExpiry))
+	}
+	if c.RefreshTokenExpiry <= 0 {
+		errs = append(errs, fmt.Errorf("JWT_REFRESH_TOKEN_EXPIRY must be a positive duration like 7d, got %s", c.RefreshTokenExpiry))
+	}
+	if c.RememberMeExpiry < c.RefreshTokenExpiry {
+		errs = append(errs, fmt.Errorf("JWT_REMEMBER_ME_EXPIRY (%s) must not be shorter than JWT_REFRESH_TOKEN_EXPIRY (%s)",
+			c.RememberMeExpiry, c.RefreshTokenExpiry))
+	}
+	if c.RefreshAbsoluteTTL < 0 {
+		errs = append(errs, fmt.Errorf("JWT_REFRESH_ABSOLUTE_TTL must not be negative, got %s", c.RefreshAbsoluteTTL))
+	}
+	if c.ExpiredTokenGrace < 0 {
+		errs = append(errs, fmt.Errorf("JWT_EXPIRED_TOKEN_GRACE must not be negative, got %s", c.ExpiredTokenGrace))
+	}
+	if c.ClockSkewLeeway < 0 {
+		errs = append(errs, fmt.Errorf("JWT_CLOCK_SKEW_LEEWAY must not be negative, got %s", c.ClockSkewLeeway))
+	}
+
+	return errors.Join(errs...)
 }
 
+// CORSConfig configures cross-origin access; no origin is allowed unless listed
 type CORSConfig struct {
-	AllowedOrigins []string
-	AllowedMethods []string
-	AllowedHeaders []string
+	AllowedOrigins   []string
+	AllowedMethods   []string
+	AllowedHeaders   []string
+	AllowCredentials bool
+	// MaxAge is how long browsers cache a preflight response
+	MaxAge time.Duration
+}
+
+// Validate rejects a wildcard origin together with credentials, which the
+// CORS spec forbids
+func (c CORSConfig) Validate() error {
+	if !c.AllowCredentials {
+		return nil
+	}
+	for _, origin := range c.AllowedOrigins {
+		if strings.TrimSpace(origin) == "*" {
+			return fmt.Errorf("CORS_ALLOWED_ORIGINS must list explicit origins when CORS_ALLOW_CREDENTIALS is true")
+		}
+	}
+	return nil
 }
 
 type LoggingConfig struct {
@@ -65,6 +213,114 @@ type LoggingConfig struct {
 	Format string
 }
 
+// SMTPConfig configures outgoing email. While Host is empty emails are only
+// written to the log.
+type SMTPConfig struct {
+	Host string
+	Port string
+	// Username enables PLAIN authentication; net/smtp only sends it over TLS
+	// or to localhost
+	Username string
+	Password string
+	From     string
+}
+
+type HealthConfig struct {
+	CheckTimeout   time.Duration
+	OAuthEndpoints []string
+}
+
+// RequestSigningConfig configures HMAC signing of internal requests.
+// Signing is disabled while Secret is empty.
+type RequestSigningConfig struct {
+	Secret  string
+	MaxSkew time.Duration
+	// Endpoints lists the route patterns that require a signature
+	Endpoints []string
+}
+
+// ApprovalConfig controls the manual approval workflow for new accounts
+type ApprovalConfig struct {
+	// Required creates new users in the pending_approval state; they cannot
+	// log in until an admin approves them
+	Required bool
+}
+
+// RegistrationConfig controls who can create an account
+type RegistrationConfig struct {
+	// Enabled allows new accounts at all; while false, registration answers
+	// 403 registration_disabled and social login only signs in existing users
+	Enabled bool
+	// InviteRequired only accepts registrations with a single-use invite
+	// token issued by an admin; social login cannot create accounts either
+	InviteRequired bool
+	// InviteTTL is how long an issued invite can be used
+	InviteTTL time.Duration
+}
+
+// WebhookConfig configures outgoing webhook events. Every event is sent to
+// each URL; events are dropped while URLs is empty.
+type WebhookConfig struct {
+	URLs []string
+	// Secret signs each delivery (X-Signature, same scheme as request signing)
+	Secret  string
+	Timeout time.Duration
+	// MaxAttempts is how often a failed delivery is tried in total; the wait
+	// between tries starts at RetryBackoff and doubles each time
+	MaxAttempts  int
+	RetryBackoff time.Duration
+}
+
+// OAuthConfig configures social login providers
+type OAuthConfig struct {
+	Google OAuthClientConfig
+	GitHub OAuthClientConfig
+}
+
+// OAuthClientConfig holds the OAuth2 client registered with a provider. The
+// provider is disabled while ClientID is empty.
+type OAuthClientConfig struct {
+	ClientID     string
+	ClientSecret string
+	// RedirectURL must match the callback URL registered with the provider
+	RedirectURL string
+}
+
+// WebAuthnConfig configures passkey registration and login. Passkeys are
+// disabled while RPID is empty.
+type WebAuthnConfig struct {
+	// RPID is the domain passkeys are bound to, e.g. "example.com"; it must
+	// be the origins' host or a parent domain of it
+	RPID          string
+	RPDisplayName string
+	// RPOrigins are the frontend origins allowed to run the ceremonies
+	RPOrigins []string
+	// SessionTTL is how long a started registration or login may take
+	SessionTTL time.Duration
+}
+
+// CaptchaConfig configures the CAPTCHA required on registration and
+// forgot-password. The check is disabled while Provider is empty.
+type CaptchaConfig struct {
+	// Provider is recaptcha, hcaptcha, turnstile, or noop to accept any
+	// non-empty token in development and end-to-end tests
+	Provider string
+	// Secret is the server-side key issued by the provider
+	Secret  string
+	Timeout time.Duration
+}
+
+// CleanupConfig configures the background removal of dead refresh tokens
+type CleanupConfig struct {
+	// Interval between runs; 0 disables the cleanup
+	Interval time.Duration
+	// RevokedRetention also removes revoked tokens created longer ago than
+	// this, before they expire; 0 keeps them until they expire. Revoked
+	// tokens are what detects refresh token reuse, so keep it well above the
+	// time a stolen token could plausibly be replayed.
+	RevokedRetention time.Duration
+}
+
 // Load loads configuration from environment variables
 func Load() (*Config, error) {
 	// Load .env file if it exists
@@ -75,6 +331,16 @@ func Load() (*Config, error) {
 			Port: getEnv("SERVER_PORT", "5004"),
 			Host: getEnv("SERVER_HOST", "0.0.0.0"),
 			Mode: getEnv("GIN_MODE", "debug"),
+
+			GRPCPort: getEnv("GRPC_PORT", "5005"),
+
+			ShutdownTimeout: getEnvAsDuration("SERVER_SHUTDOWN_TIMEOUT", 15*time.Second),
+
+			FrontendURL: getEnv("FRONTEND_URL", "http://localhost:3000"),
+
+			SwaggerEnabled: getEnvAsBool("SWAGGER_ENABLED", true),
+
+			TrustedProxies: getEnvAsSlice("TRUSTED_PROXIES", nil),
 		},
 		Database: DatabaseConfig{
 			Host:     getEnv("DB_HOST", "localhost"),
@@ -83,6 +349,14 @@ func Load() (*Config, error) {
 			Password: getEnv("DB_PASSWORD", "postgres"),
 			DBName:   getEnv("DB_NAME", "auth_db"),
 			SSLMode:  getEnv("DB_SSLMODE", "disable"),
+
+			CaseInsensitiveUsernames: getEnvAsBool("DB_CASE_INSENSITIVE_USERNAMES", false),
+
+			MaxOpenConns:    getEnvAsInt("DB_MAX_OPEN_CONNS", 25),
+			MaxIdleConns:    getEnvAsInt("DB_MAX_IDLE_CONNS", 10),
+			ConnMaxLifetime: getEnvAsDuration("DB_CONN_MAX_LIFETIME", 30*time.Minute),
+			ConnMaxIdleTime: getEnvAsDuration("DB_CONN_MAX_IDLE_TIME", 5*time.Minute),
+			LogLevel:        getEnv("DB_LOG_LEVEL", "warn"),
 		},
 		Redis: RedisConfig{
 			Host:     getEnv("REDIS_HOST", "localhost"),
@@ -91,24 +365,148 @@ func Load() (*Config, error) {
 			DB:       getEnvAsInt("REDIS_DB", 0),
 		},
 		JWT: JWTConfig{
-			Secret:             getEnv("JWT_SECRET", "your-secret-key"),
-			AccessTokenExpiry:  parseDuration(getEnv("JWT_ACCESS_TOKEN_EXPIRY", "15m")),
-			RefreshTokenExpiry: parseDuration(getEnv("JWT_REFRESH_TOKEN_EXPIRY", "7d")),
-		},
-		Security: SecurityConfig{
-			BcryptCost:       getEnvAsInt("BCRYPT_COST", 12),
-			MaxLoginAttempts: getEnvAsInt("MAX_LOGIN_ATTEMPTS", 5),
-			LockoutDuration:  parseDuration(getEnv("LOCKOUT_DURATION", "15m")),
+			Secret:             getEnv("JWT_SECRET", ""),
+			AccessTokenExpiry:  getEnvAsTTL("JWT_ACCESS_TOKEN_EXPIRY", 15*time.Minute),
+			RefreshTokenExpiry: getEnvAsTTL("JWT_REFRESH_TOKEN_EXPIRY", 7*24*time.Hour),
+			RememberMeExpiry:   getEnvAsTTL("JWT_REMEMBER_ME_EXPIRY", 30*24*time.Hour),
+			RefreshAbsoluteTTL: getEnvAsTTL("JWT_REFRESH_ABSOLUTE_TTL", 0),
+			ExpiredTokenGrace:  getEnvAsDuration("JWT_EXPIRED_TOKEN_GRACE", 0),
+			RefreshTokenCookie: getEnvAsBool("JWT_REFRESH_TOKEN_COOKIE", false),
+			Issuer:             getEnv("JWT_ISSUER", "auth-service"),
+			Audiences:          getEnvAsSlice("JWT_AUDIENCE", nil),
+			ClockSkewLeeway:    getEnvAsDuration("JWT_CLOCK_SKEW_LEEWAY", 30*time.Second),
+			PrivateKeyPath:     getEnv("JWT_PRIVATE_KEY_PATH", ""),
+			PublicKeyPath:      getEnv("JWT_PUBLIC_KEY_PATH", ""),
+			PreviousSecrets:    getEnvAsSlice("JWT_PREVIOUS_SECRETS", nil),
+
+			SecretProvider:        getEnv("JWT_SECRET_PROVIDER", JWTSecretProviderEnv),
+			SecretRefreshInterval: getEnvAsDuration("JWT_SECRET_REFRESH_INTERVAL", 5*time.Minute),
+			VaultAddr:             getEnv("VAULT_ADDR", ""),
+			VaultToken:            getEnv("VAULT_TOKEN", ""),
+			VaultSecretPath:       getEnv("VAULT_JWT_SECRET_PATH", ""),
 		},
+		Security: loadSecurityConfig(),
 		CORS: CORSConfig{
-			AllowedOrigins: getEnvAsSlice("CORS_ALLOWED_ORIGINS", []string{"*"}),
+			AllowedOrigins: getEnvAsSlice("CORS_ALLOWED_ORIGINS", nil),
 			AllowedMethods: getEnvAsSlice("CORS_ALLOWED_METHODS", []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"}),
-			AllowedHeaders: getEnvAsSlice("CORS_ALLOWED_HEADERS", []string{"Origin", "Content-Type", "Authorization"}),
+			AllowedHeaders: getEnvAsSlice("CORS_ALLOWED_HEADERS", []string{"Origin", "Content-Type", "Authorization", "X-Request-ID", "X-Client-Type"}),
+
+			AllowCredentials: getEnvAsBool("CORS_ALLOW_CREDENTIALS", true),
+			MaxAge:           getEnvAsDuration("CORS_MAX_AGE", 12*time.Hour),
 		},
 		Logging: LoggingConfig{
 			Level:  getEnv("LOG_LEVEL", "info"),
 			Format: getEnv("LOG_FORMAT", "json"),
 		},
+		SMTP: SMTPConfig{
+			Host:     getEnv("SMTP_HOST", ""),
+			Port:     getEnv("SMTP_PORT", "587"),
+			Username: getEnv("SMTP_USERNAME", ""),
+			Password: getEnv("SMTP_PASSWORD", ""),
+			From:     getEnv("SMTP_FROM", "no-reply@localhost"),
+		},
+		Health: HealthConfig{
+			CheckTimeout:   parseDuration(getEnv("HEALTH_CHECK_TIMEOUT", "2s")),
+			OAuthEndpoints: getEnvAsSlice("HEALTH_OAUTH_ENDPOINTS", nil),
+		},
+		Approval: ApprovalConfig{
+			Required: getEnvAsBool("REGISTRATION_APPROVAL_REQUIRED", false),
+		},
+		Registration: RegistrationConfig{
+			Enabled:        getEnvAsBool("REGISTRATION_ENABLED", true),
+			InviteRequired: getEnvAsBool("REGISTRATION_INVITE_REQUIRED", false),
+			InviteTTL:      getEnvAsTTL("INVITE_TTL", 7*24*time.Hour),
+		},
+		Webhook: WebhookConfig{
+			// WEBHOOK_URL (a single URL) is still read when WEBHOOK_URLS is not set
+			URLs:         getEnvAsSlice("WEBHOOK_URLS", getEnvAsSlice("WEBHOOK_URL", nil)),
+			Secret:       getEnv("WEBHOOK_SECRET", ""),
+			Timeout:      getEnvAsDuration("WEBHOOK_TIMEOUT", 5*time.Second),
+			MaxAttempts:  getEnvAsInt("WEBHOOK_MAX_ATTEMPTS", 5),
+			RetryBackoff: getEnvAsDuration("WEBHOOK_RETRY_BACKOFF", time.Second),
+		},
+		OAuth: OAuthConfig{
+			Google: OAuthClientConfig{
+				ClientID:     getEnv("GOOGLE_CLIENT_ID", ""),
+				ClientSecret: getEnv("GOOGLE_CLIENT_SECRET", ""),
+				RedirectURL:  getEnv("GOOGLE_REDIRECT_URL", "http://localhost:5004/api/auth/oauth/google/callback"),
+			},
+			GitHub: OAuthClientConfig{
+				ClientID:     getEnv("GITHUB_CLIENT_ID", ""),
+				ClientSecret: getEnv("GITHUB_CLIENT_SECRET", ""),
+				RedirectURL:  getEnv("GITHUB_REDIRECT_URL", "http://localhost:5004/api/auth/oauth/github/callback"),
+			},
+		},
+		WebAuthn: WebAuthnConfig{
+			RPID:          getEnv("WEBAUTHN_RP_ID", ""),
+			RPDisplayName: getEnv("WEBAUTHN_RP_NAME", "Auth Service"),
+			RPOrigins:     getEnvAsSlice("WEBAUTHN_RP_ORIGINS", []string{getEnv("FRONTEND_URL", "http://localhost:3000")}),
+			SessionTTL:    getEnvAsDuration("WEBAUTHN_SESSION_TTL", 5*time.Minute),
+		},
+		Signing: RequestSigningConfig{
+			Secret:    getEnv("REQUEST_SIGNING_SECRET", ""),
+			MaxSkew:   getEnvAsDuration("REQUEST_SIGNING_MAX_SKEW", 5*time.Minute),
+			Endpoints: getEnvAsSlice("REQUEST_SIGNING_ENDPOINTS", nil),
+		},
+		Cleanup: CleanupConfig{
+			Interval:         getEnvAsDuration("TOKEN_CLEANUP_INTERVAL", time.Hour),
+			RevokedRetention: getEnvAsDuration("REVOKED_TOKEN_RETENTION", 0),
+		},
+		Captcha: CaptchaConfig{
+			Provider: getEnv("CAPTCHA_PROVIDER", ""),
+			Secret:   getEnv("CAPTCHA_SECRET", ""),
+			Timeout:  getEnvAsDuration("CAPTCHA_TIMEOUT", 5*time.Second),
+		},
+	}
+
+	if err := config.Security.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid security config: %w", err)
+	}
+	if err := config.CORS.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid CORS config: %w", err)
+	}
+	if err := config.JWT.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid JWT config: %w", err)
+	}
+	if db := config.Database; db.MaxOpenConns < 0 || db.MaxIdleConns < 0 || db.ConnMaxLifetime < 0 || db.ConnMaxIdleTime < 0 {
+		return nil, fmt.Errorf("DB_MAX_OPEN_CONNS, DB_MAX_IDLE_CONNS, DB_CONN_MAX_LIFETIME and DB_CONN_MAX_IDLE_TIME must not be negative")
+	}
+	if db := config.Database; db.MaxOpenConns > 0 && db.MaxIdleConns > db.MaxOpenConns {
+		return nil, fmt.Errorf("DB_MAX_IDLE_CONNS (%d) must not exceed DB_MAX_OPEN_CONNS (%d)", db.MaxIdleConns, db.MaxOpenConns)
+	}
+	switch config.Database.LogLevel {
+	case "silent", "error", "warn", "info":
+	default:
+		return nil, fmt.Errorf("DB_LOG_LEVEL must be one of silent, error, warn, info, got %q", config.Database.LogLevel)
+	}
+	if config.Server.ShutdownTimeout <= 0 {
+		return nil, fmt.Errorf("SERVER_SHUTDOWN_TIMEOUT must be positive, got %s", config.Server.ShutdownTimeout)
+	}
+	for _, proxy := range config.Server.TrustedProxies {
+		if _, _, err := net.ParseCIDR(proxy); err != nil && net.ParseIP(proxy) == nil {
+			return nil, fmt.Errorf("TRUSTED_PROXIES must list IP addresses or CIDRs, got %q", proxy)
+		}
+	}
+	if config.Cleanup.Interval < 0 || config.Cleanup.RevokedRetention < 0 {
+		return nil, fmt.Errorf("TOKEN_CLEANUP_INTERVAL and REVOKED_TOKEN_RETENTION must not be negative")
+	}
+	if config.WebAuthn.RPID != "" && config.WebAuthn.SessionTTL <= 0 {
+		return nil, fmt.Errorf("WEBAUTHN_SESSION_TTL must be positive, got %s", config.WebAuthn.SessionTTL)
+	}
+	if config.Registration.InviteTTL <= 0 {
+		return nil, fmt.Errorf("INVITE_TTL must be positive, got %s", config.Registration.InviteTTL)
+	}
+	switch config.Captcha.Provider {
+	case "", "noop":
+	case "recaptcha", "hcaptcha", "turnstile":
+		if config.Captcha.Secret == "" {
+			return nil, fmt.Errorf("CAPTCHA_SECRET is required for CAPTCHA_PROVIDER %s", config.Captcha.Provider)
+		}
+	default:
+		return nil, fmt.Errorf("CAPTCHA_PROVIDER must be one of recaptcha, hcaptcha, turnstile, noop, got %q", config.Captcha.Provider)
+	}
+	if config.Webhook.MaxAttempts < 1 || config.Webhook.RetryBackoff < 0 {
+		return nil, fmt.Errorf("WEBHOOK_MAX_ATTEMPTS must be at least 1 and WEBHOOK_RETRY_BACKOFF must not be negative")
 	}
 
 	return config, nil
@@ -127,6 +525,11 @@ func (c *RedisConfig) GetRedisAddr() string {
 	return fmt.Sprintf("%s:%s", c.Host, c.Port)
 }
 
+// GetAddr returns the SMTP server address
+func (c *SMTPConfig) GetAddr() string {
+	return fmt.Sprintf("%s:%s", c.Host, c.Port)
+}
+
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
@@ -142,12 +545,20 @@ func getEnvAsInt(key string, defaultValue int) int {
 	return defaultValue
 }
 
+func getEnvAsBool(key string, defaultValue bool) bool {
+	valueStr := getEnv(key, "")
+	if value, err := strconv.ParseBool(valueStr); err == nil {
+		return value
+	}
+	return defaultValue
+}
+
 func getEnvAsSlice(key string, defaultValue []string) []string {
 	valueStr := getEnv(key, "")
 	if valueStr == "" {
 		return defaultValue
 	}
-	
+
 	var result []string
 	for _, v := range splitString(valueStr, ",") {
 		if trimmed := trim(v); trimmed != "" {
@@ -157,14 +568,50 @@ func getEnvAsSlice(key string, defaultValue []string) []string {
 	return result
 }
 
+func getEnvAsDuration(key string, defaultValue time.Duration) time.Duration {
+	valueStr := getEnv(key, "")
+	if value, err := parseDurationWithDays(valueStr); err == nil {
+		return value
+	}
+	return defaultValue
+}
+
+// getEnvAsTTL is getEnvAsDuration for token lifetimes: a value that does not
+// parse yields 0 instead of the default, so validation rejects the typo
+// rather than silently running with a different lifetime
+func getEnvAsTTL(key string, defaultValue time.Duration) time.Duration {
+	valueStr := getEnv(key, "")
+	if valueStr == "" {
+		return defaultValue
+	}
+	value, err := parseDurationWithDays(valueStr)
+	if err != nil {
+		return 0
+	}
+	return value
+}
+
 func parseDuration(s string) time.Duration {
-	d, err := time.ParseDuration(s)
+	d, err := parseDurationWithDays(s)
 	if err != nil {
 		return 15 * time.Minute
 	}
 	return d
 }
 
+// parseDurationWithDays extends time.ParseDuration with a "d" (day) suffix,
+// e.g. "7d", so TTLs like JWT_REFRESH_TOKEN_EXPIRY=7d parse as documented
+func parseDurationWithDays(s string) (time.Duration, error) {
+	if n := len(s); n > 1 && s[n-1] == 'd' {
+		days, err := strconv.Atoi(s[:n-1])
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q", s)
+		}
+		return time.Duration(days) * 24 * time.Hour, nil
+	}
+	return time.ParseDuration(s)
+}
+
 func splitString(s, sep string) []string {
 	var result []string
 	current := ""
@@ -187,14 +634,14 @@ func splitString(s, sep string) []string {
 func trim(s string) string {
 	start := 0
 	end := len(s)
-	
+
 	for start < end && (s[start] == ' ' || s[start] == '\t' || s[start] == '\n') {
 		start++
 	}
-	
+
 	for end > start && (s[end-1] == ' ' || s[end-1] == '\t' || s[end-1] == '\n') {
 		end--
 	}
-	
+
 	return s[start:end]
 }
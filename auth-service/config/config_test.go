@@ -0,0 +1,461 @@
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+const testJWTSecret = "0123456789abcdef0123456789abcdef"
+
+// setLoadEnv resets the environment Load reads to its defaults, plus the
+// JWT secret Load requires
+func setLoadEnv(t *testing.T) {
+	t.Helper()
+	unsetSecurityEnv(t)
+	for _, key := range []string{"JWT_SECRET", "JWT_PRIVATE_KEY_PATH", "JWT_ACCESS_TOKEN_EXPIRY", "JWT_REFRESH_TOKEN_EXPIRY", "JWT_REMEMBER_ME_EXPIRY", "JWT_REFRESH_ABSOLUTE_TTL",
+		"JWT_SECRET_PROVIDER", "JWT_SECRET_REFRESH_INTERVAL", "VAULT_ADDR", "VAULT_TOKEN", "VAULT_JWT_SECRET_PATH"} {
+		t.Setenv(key, "")
+	}
+	t.Setenv("JWT_SECRET", testJWTSecret)
+}
+
+func TestLoadJWTValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		env     map[string]string
+		wantErr string
+	}{
+		{"defaults", nil, ""},
+		{"missing secret", map[string]string{"JWT_SECRET": ""}, "JWT_SECRET is required"},
+		{"short secret", map[string]string{"JWT_SECRET": "your-secret-key"}, "JWT_SECRET must be at least 32 bytes"},
+		{"RS256 needs no secret", map[string]string{"JWT_SECRET": "", "JWT_PRIVATE_KEY_PATH": "/etc/auth/jwt.pem"}, ""},
+		{"zero access TTL", map[string]string{"JWT_ACCESS_TOKEN_EXPIRY": "0s"}, "JWT_ACCESS_TOKEN_EXPIRY must be a positive duration"},
+		{"negative refresh TTL", map[string]string{"JWT_REFRESH_TOKEN_EXPIRY": "-1h"}, "JWT_REFRESH_TOKEN_EXPIRY must be a positive duration"},
+		{"unparseable access TTL", map[string]string{"JWT_ACCESS_TOKEN_EXPIRY": "15 minutes"}, "JWT_ACCESS_TOKEN_EXPIRY must be a positive duration"},
+		{"negative absolute TTL", map[string]string{"JWT_REFRESH_ABSOLUTE_TTL": "-1d"}, "JWT_REFRESH_ABSOLUTE_TTL must not be negative"},
+		{"unknown secret provider", map[string]string{"JWT_SECRET_PROVIDER": "kms"}, "JWT_SECRET_PROVIDER must be env or vault"},
+		{"vault needs no secret", map[string]string{"JWT_SECRET": "", "JWT_SECRET_PROVIDER": "vault",
+			"VAULT_ADDR": "https://vault:8200", "VAULT_TOKEN": "s.token", "VAULT_JWT_SECRET_PATH": "secret/data/auth-service/jwt"}, ""},
+		{"vault without address", map[string]string{"JWT_SECRET_PROVIDER": "vault", "VAULT_TOKEN": "s.token",
+			"VAULT_JWT_SECRET_PATH": "secret/data/auth-service/jwt"}, "requires VAULT_ADDR, VAULT_TOKEN and VAULT_JWT_SECRET_PATH"},
+		{"vault with RS256", map[string]string{"JWT_SECRET_PROVIDER": "vault", "JWT_PRIVATE_KEY_PATH": "/etc/auth/jwt.pem",
+			"VAULT_ADDR": "https://vault:8200", "VAULT_TOKEN": "s.token", "VAULT_JWT_SECRET_PATH": "secret/data/auth-service/jwt"}, "cannot be combined with JWT_PRIVATE_KEY_PATH"},
+		{"zero secret refresh interval", map[string]string{"JWT_SECRET_PROVIDER": "vault", "JWT_SECRET_REFRESH_INTERVAL": "0s",
+			"VAULT_ADDR": "https://vault:8200", "VAULT_TOKEN": "s.token", "VAULT_JWT_SECRET_PATH": "secret/data/auth-service/jwt"}, "JWT_SECRET_REFRESH_INTERVAL must be a positive duration"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setLoadEnv(t)
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+
+			cfg, err := Load()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Load: %v", err)
+				}
+				if cfg.JWT.AccessTokenExpiry != 15*time.Minute || cfg.JWT.RefreshTokenExpiry != 7*24*time.Hour {
+					t.Errorf("TTLs = %s/%s, want 15m/7d", cfg.JWT.AccessTokenExpiry, cfg.JWT.RefreshTokenExpiry)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("got %v, want error containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadExpiredTokenGrace(t *testing.T) {
+	setLoadEnv(t)
+
+	t.Setenv("JWT_EXPIRED_TOKEN_GRACE", "")
+	cfg, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.JWT.ExpiredTokenGrace != 0 {
+		t.Errorf("default grace = %s, want disabled", cfg.JWT.ExpiredTokenGrace)
+	}
+
+	t.Setenv("JWT_EXPIRED_TOKEN_GRACE", "30s")
+	if cfg, err = Load(); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.JWT.ExpiredTokenGrace != 30*time.Second {
+		t.Errorf("grace = %s, want 30s", cfg.JWT.ExpiredTokenGrace)
+	}
+
+	t.Setenv("JWT_EXPIRED_TOKEN_GRACE", "-1s")
+	if _, err := Load(); err == nil {
+		t.Error("negative grace should be rejected")
+	}
+}
+
+func TestLoadRememberMeExpiry(t *testing.T) {
+	setLoadEnv(t)
+
+	t.Setenv("JWT_REFRESH_TOKEN_EXPIRY", "")
+	t.Setenv("JWT_REMEMBER_ME_EXPIRY", "")
+	cfg, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.JWT.RememberMeExpiry != 30*24*time.Hour {
+		t.Errorf("default remember-me expiry = %s, want 30 days", cfg.JWT.RememberMeExpiry)
+	}
+
+	t.Setenv("JWT_REMEMBER_ME_EXPIRY", "1d")
+	if _, err := Load(); err == nil {
+		t.Error("remember-me expiry shorter than the refresh token expiry should be rejected")
+	}
+}
+
+func TestLoadRefreshAbsoluteTTL(t *testing.T) {
+	setLoadEnv(t)
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.JWT.RefreshAbsoluteTTL != 0 {
+		t.Errorf("default absolute TTL = %s, want 0 (no cap)", cfg.JWT.RefreshAbsoluteTTL)
+	}
+
+	t.Setenv("JWT_REFRESH_ABSOLUTE_TTL", "90d")
+	if cfg, err = Load(); err != nil || cfg.JWT.RefreshAbsoluteTTL != 90*24*time.Hour {
+		t.Errorf("absolute TTL = %s, %v; want 90 days", cfg.JWT.RefreshAbsoluteTTL, err)
+	}
+}
+
+func TestLoadPreviousSecrets(t *testing.T) {
+	setLoadEnv(t)
+
+	t.Setenv("JWT_PREVIOUS_SECRETS", "secret-2, secret-1")
+	cfg, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := cfg.JWT.PreviousSecrets; len(got) != 2 || got[0] != "secret-2" || got[1] != "secret-1" {
+		t.Errorf("PreviousSecrets = %q, want [secret-2 secret-1]", got)
+	}
+}
+
+func TestLoadIssuerAndAudience(t *testing.T) {
+	setLoadEnv(t)
+
+	t.Setenv("JWT_ISSUER", "")
+	t.Setenv("JWT_AUDIENCE", "")
+	cfg, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.JWT.Issuer != "auth-service" || cfg.JWT.Audiences != nil {
+		t.Errorf("defaults: iss = %q, aud = %q, want auth-service and none", cfg.JWT.Issuer, cfg.JWT.Audiences)
+	}
+
+	t.Setenv("JWT_ISSUER", "https://auth.example.com")
+	t.Setenv("JWT_AUDIENCE", "user-service, billing-service")
+	if cfg, err = Load(); err != nil {
+		t.Fatal(err)
+	}
+	if got := cfg.JWT.Audiences; cfg.JWT.Issuer != "https://auth.example.com" || len(got) != 2 || got[1] != "billing-service" {
+		t.Errorf("iss = %q, aud = %q", cfg.JWT.Issuer, got)
+	}
+}
+
+func TestLoadClockSkewLeeway(t *testing.T) {
+	setLoadEnv(t)
+
+	t.Setenv("JWT_CLOCK_SKEW_LEEWAY", "")
+	cfg, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.JWT.ClockSkewLeeway != 30*time.Second {
+		t.Errorf("default leeway = %s, want 30s", cfg.JWT.ClockSkewLeeway)
+	}
+
+	t.Setenv("JWT_CLOCK_SKEW_LEEWAY", "-1s")
+	if _, err := Load(); err == nil {
+		t.Error("negative leeway should be rejected")
+	}
+}
+
+func TestLoadCaseInsensitiveUsernames(t *testing.T) {
+	setLoadEnv(t)
+
+	t.Setenv("DB_CASE_INSENSITIVE_USERNAMES", "")
+	cfg, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Database.CaseInsensitiveUsernames {
+		t.Error("case-insensitive usernames should be opt-in")
+	}
+
+	t.Setenv("DB_CASE_INSENSITIVE_USERNAMES", "true")
+	if cfg, err = Load(); err != nil {
+		t.Fatal(err)
+	}
+	if !cfg.Database.CaseInsensitiveUsernames {
+		t.Error("DB_CASE_INSENSITIVE_USERNAMES=true not applied")
+	}
+}
+
+func TestLoadDatabasePool(t *testing.T) {
+	setLoadEnv(t)
+	for _, key := range []string{"DB_MAX_OPEN_CONNS", "DB_MAX_IDLE_CONNS", "DB_CONN_MAX_LIFETIME", "DB_CONN_MAX_IDLE_TIME"} {
+		t.Setenv(key, "")
+	}
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if db := cfg.Database; db.MaxOpenConns != 25 || db.MaxIdleConns != 10 || db.ConnMaxLifetime != 30*time.Minute || db.ConnMaxIdleTime != 5*time.Minute {
+		t.Errorf("pool defaults = %d/%d/%s/%s, want 25/10/30m/5m", db.MaxOpenConns, db.MaxIdleConns, db.ConnMaxLifetime, db.ConnMaxIdleTime)
+	}
+
+	t.Setenv("DB_MAX_OPEN_CONNS", "5")
+	if _, err := Load(); err == nil {
+		t.Error("more idle than open connections should be rejected")
+	}
+
+	t.Setenv("DB_MAX_OPEN_CONNS", "0")
+	t.Setenv("DB_CONN_MAX_LIFETIME", "-1m")
+	if _, err := Load(); err == nil {
+		t.Error("negative lifetime should be rejected")
+	}
+}
+
+func TestLoadDatabaseLogLevel(t *testing.T) {
+	setLoadEnv(t)
+
+	t.Setenv("DB_LOG_LEVEL", "")
+	cfg, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Database.LogLevel != "warn" {
+		t.Errorf("default log level = %q, want warn", cfg.Database.LogLevel)
+	}
+
+	t.Setenv("DB_LOG_LEVEL", "debug")
+	if _, err := Load(); err == nil {
+		t.Error("unknown log level should be rejected")
+	}
+}
+
+func TestLoadCleanup(t *testing.T) {
+	setLoadEnv(t)
+
+	t.Setenv("TOKEN_CLEANUP_INTERVAL", "")
+	t.Setenv("REVOKED_TOKEN_RETENTION", "")
+	cfg, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Cleanup.Interval != time.Hour || cfg.Cleanup.RevokedRetention != 0 {
+		t.Errorf("default cleanup = %+v, want hourly without revoked retention", cfg.Cleanup)
+	}
+
+	t.Setenv("TOKEN_CLEANUP_INTERVAL", "10m")
+	t.Setenv("REVOKED_TOKEN_RETENTION", "720h")
+	if cfg, err = Load(); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Cleanup.Interval != 10*time.Minute || cfg.Cleanup.RevokedRetention != 720*time.Hour {
+		t.Errorf("cleanup = %+v", cfg.Cleanup)
+	}
+
+	t.Setenv("REVOKED_TOKEN_RETENTION", "-1h")
+	if _, err := Load(); err == nil {
+		t.Error("negative retention should be rejected")
+	}
+}
+
+func TestLoadWebhook(t *testing.T) {
+	setLoadEnv(t)
+
+	t.Setenv("WEBHOOK_URLS", "")
+	t.Setenv("WEBHOOK_URL", "https://hooks.example.com/auth")
+	t.Setenv("WEBHOOK_MAX_ATTEMPTS", "")
+	t.Setenv("WEBHOOK_RETRY_BACKOFF", "")
+	cfg, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := cfg.Webhook.URLs; len(got) != 1 || got[0] != "https://hooks.example.com/auth" {
+		t.Errorf("URLs = %q, want WEBHOOK_URL as fallback", got)
+	}
+	if cfg.Webhook.MaxAttempts != 5 || cfg.Webhook.RetryBackoff != time.Second {
+		t.Errorf("default retries = %d every %s, want 5 from 1s", cfg.Webhook.MaxAttempts, cfg.Webhook.RetryBackoff)
+	}
+
+	t.Setenv("WEBHOOK_URLS", "https://a.example.com, https://b.example.com")
+	if cfg, err = Load(); err != nil {
+		t.Fatal(err)
+	}
+	if got := cfg.Webhook.URLs; len(got) != 2 || got[1] != "https://b.example.com" {
+		t.Errorf("URLs = %q", got)
+	}
+
+	t.Setenv("WEBHOOK_MAX_ATTEMPTS", "0")
+	if _, err := Load(); err == nil {
+		t.Error("zero max attempts should be rejected")
+	}
+}
+
+func TestLoadWebAuthn(t *testing.T) {
+	setLoadEnv(t)
+
+	t.Setenv("WEBAUTHN_RP_ID", "")
+	t.Setenv("WEBAUTHN_RP_ORIGINS", "")
+	t.Setenv("WEBAUTHN_SESSION_TTL", "0s")
+	t.Setenv("FRONTEND_URL", "https://app.example.com")
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("TTL must not be checked while passkeys are disabled: %v", err)
+	}
+	if got := cfg.WebAuthn.RPOrigins; len(got) != 1 || got[0] != "https://app.example.com" {
+		t.Errorf("RPOrigins = %q, want FRONTEND_URL as default", got)
+	}
+
+	t.Setenv("WEBAUTHN_RP_ID", "example.com")
+	if _, err := Load(); err == nil {
+		t.Error("zero session TTL should be rejected while passkeys are enabled")
+	}
+
+	t.Setenv("WEBAUTHN_SESSION_TTL", "")
+	if cfg, err = Load(); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.WebAuthn.SessionTTL != 5*time.Minute {
+		t.Errorf("default session TTL = %s, want 5m", cfg.WebAuthn.SessionTTL)
+	}
+}
+
+func TestLoadRegistration(t *testing.T) {
+	setLoadEnv(t)
+
+	t.Setenv("REGISTRATION_ENABLED", "")
+	t.Setenv("REGISTRATION_INVITE_REQUIRED", "")
+	t.Setenv("INVITE_TTL", "")
+	cfg, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cfg.Registration.Enabled || cfg.Registration.InviteRequired || cfg.Registration.InviteTTL != 7*24*time.Hour {
+		t.Errorf("defaults = %+v, want open registration and 7d invites", cfg.Registration)
+	}
+
+	t.Setenv("REGISTRATION_ENABLED", "false")
+	t.Setenv("REGISTRATION_INVITE_REQUIRED", "true")
+	t.Setenv("INVITE_TTL", "2d")
+	if cfg, err = Load(); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Registration.Enabled || !cfg.Registration.InviteRequired || cfg.Registration.InviteTTL != 48*time.Hour {
+		t.Errorf("registration = %+v", cfg.Registration)
+	}
+
+	t.Setenv("INVITE_TTL", "a week")
+	if _, err := Load(); err == nil {
+		t.Error("invalid invite TTL should be rejected")
+	}
+}
+
+func TestLoadCaptcha(t *testing.T) {
+	setLoadEnv(t)
+
+	t.Setenv("CAPTCHA_PROVIDER", "")
+	t.Setenv("CAPTCHA_SECRET", "")
+	if _, err := Load(); err != nil {
+		t.Fatalf("CAPTCHA is optional: %v", err)
+	}
+
+	t.Setenv("CAPTCHA_PROVIDER", "turnstile")
+	if _, err := Load(); err == nil {
+		t.Error("a provider without a secret should be rejected")
+	}
+	t.Setenv("CAPTCHA_SECRET", "0x4AAAAAAA")
+	if _, err := Load(); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("CAPTCHA_PROVIDER", "captchaland")
+	if _, err := Load(); err == nil {
+		t.Error("unknown provider should be rejected")
+	}
+}
+
+func TestLoadShutdownTimeout(t *testing.T) {
+	setLoadEnv(t)
+
+	t.Setenv("SERVER_SHUTDOWN_TIMEOUT", "")
+	cfg, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cfg.Server.ShutdownTimeout != 15*time.Second {
+		t.Errorf("default shutdown timeout = %s, want 15s", cfg.Server.ShutdownTimeout)
+	}
+
+	t.Setenv("SERVER_SHUTDOWN_TIMEOUT", "0s")
+	if _, err := Load(); err == nil {
+		t.Error("zero shutdown timeout should be rejected")
+	}
+}
+
+func TestLoadTrustedProxies(t *testing.T) {
+	setLoadEnv(t)
+
+	t.Setenv("TRUSTED_PROXIES", "")
+	cfg, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cfg.Server.TrustedProxies) != 0 {
+		t.Errorf("default trusted proxies = %v, want none", cfg.Server.TrustedProxies)
+	}
+
+	t.Setenv("TRUSTED_PROXIES", "10.0.0.0/8, 192.168.1.10")
+	if cfg, err = Load(); err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(cfg.Server.TrustedProxies, ","); got != "10.0.0.0/8,192.168.1.10" {
+		t.Errorf("trusted proxies = %q", got)
+	}
+
+	t.Setenv("TRUSTED_PROXIES", "load-balancer")
+	if _, err := Load(); err == nil || !strings.Contains(err.Error(), "TRUSTED_PROXIES") {
+		t.Errorf("hostname: err = %v, want a TRUSTED_PROXIES error", err)
+	}
+}
+
+func TestLoadCORS(t *testing.T) {
+	setLoadEnv(t)
+
+	t.Setenv("CORS_ALLOWED_ORIGINS", "")
+	cfg, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cfg.CORS.AllowedOrigins) != 0 {
+		t.Errorf("default origins = %q, want none", cfg.CORS.AllowedOrigins)
+	}
+
+	t.Setenv("CORS_ALLOWED_ORIGINS", "*")
+	t.Setenv("CORS_ALLOW_CREDENTIALS", "true")
+	if _, err := Load(); err == nil {
+		t.Error("wildcard origin with credentials should be rejected")
+	}
+
+	t.Setenv("CORS_ALLOW_CREDENTIALS", "false")
+	if _, err := Load(); err != nil {
+		t.Errorf("wildcard origin without credentials: %v", err)
+	}
+}
@@ -0,0 +1,376 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// SecurityConfig groups every security-relevant knob in one place so the
+// service's security posture can be reviewed and tuned from a single struct.
+// Defaults are listed in DefaultSecurityConfig.
+type SecurityConfig struct {
+	// PasswordHashAlgorithm is used for new password hashes. Existing hashes
+	// of the other algorithm keep working.
+	PasswordHashAlgorithm PasswordHashAlgorithm
+	// BcryptCost is the bcrypt work factor used for password hashes
+	BcryptCost int
+	// BcryptCalibrateTarget, when positive, replaces BcryptCost at startup
+	// with the highest cost whose hash takes at most this long on the host
+	BcryptCalibrateTarget time.Duration
+	// BcryptPreHash runs bcrypt on the SHA-256 of the password, so bytes past
+	// bcrypt's 72 byte limit count too. Existing hashes keep working and are
+	// upgraded at the next login
+	BcryptPreHash bool
+	// Argon2Memory (KiB), Argon2Time (passes) and Argon2Parallelism (lanes)
+	// are the Argon2id cost parameters
+	Argon2Memory      int
+	Argon2Time        int
+	Argon2Parallelism int
+	// MaxLoginAttempts is the number of consecutive failed logins before lockout
+	MaxLoginAttempts int
+	// LockoutDuration is how long an account stays locked after MaxLoginAttempts
+	LockoutDuration time.Duration
+	// LockoutNotification emails the user when their account gets locked,
+	// at most once per LockoutNotificationInterval (0: every lockout)
+	LockoutNotification         bool
+	LockoutNotificationInterval time.Duration
+	// LoginTimingEqualization hashes the submitted password even when no user
+	// matches, so response times don't reveal which accounts exist
+	LoginTimingEqualization bool
+	// MaxSessionsPerUser caps a user's active refresh tokens; a login beyond
+	// it signs out the oldest sessions. 0 means unlimited
+	MaxSessionsPerUser int
+	// SingleSessionMode signs out every other session of a user when they
+	// log in, so only the newest login stays active
+	SingleSessionMode bool
+
+	// PasswordMinLength is the minimum accepted password length
+	PasswordMinLength int
+	// PasswordMaxLength caps password length, bounding hashing cost
+	PasswordMaxLength int
+	// PasswordRequire* demand at least one character of the class
+	PasswordRequireUpper  bool
+	PasswordRequireLower  bool
+	PasswordRequireDigit  bool
+	PasswordRequireSymbol bool
+	// PasswordBreachCheck rejects passwords found in the HaveIBeenPwned
+	// breach corpus (k-anonymity range API: only 5 hex chars of the SHA-1
+	// leave the service)
+	PasswordBreachCheck bool
+	// PasswordPolicy selects how new passwords are checked
+	PasswordPolicy PasswordPolicy
+	// PasswordMinScore is the minimum estimated strength (0-4) under the
+	// "strength" and "both" policies
+	PasswordMinScore int
+	// PasswordHistoryDepth is how many of a user's recent passwords cannot be
+	// reused on change or reset; 0 disables the check
+	PasswordHistoryDepth int
+	// PasswordHistoryOnRegister also records the registration password, so
+	// the first change cannot go back to it either
+	PasswordHistoryOnRegister bool
+
+	// UsernameBlocklist are usernames that cannot be registered, on top of
+	// the built-in reserved names such as "admin" and "support"
+	UsernameBlocklist []string
+
+	UnverifiedLoginPolicy UnverifiedLoginPolicy
+	// VerificationGracePeriod is how long after registration an unverified
+	// user may still log in under the "grace" policy
+	VerificationGracePeriod time.Duration
+
+	// VerificationToken configures email verification links
+	VerificationToken TokenConfig
+	// PasswordResetToken configures password reset links
+	PasswordResetToken TokenConfig
+	// MagicLinkToken configures passwordless login links
+	MagicLinkToken TokenConfig
+	// VerificationResendCooldown is the minimum time between two
+	// verification emails to the same user; 0 disables the check
+	VerificationResendCooldown time.Duration
+	// UsernameChangeCooldown is the minimum time between two username
+	// changes by the same user; 0 disables the check
+	UsernameChangeCooldown time.Duration
+
+	// RateLimitRequests is the number of requests a client may make to the
+	// public auth endpoints within RateLimitWindow
+	RateLimitRequests int
+	RateLimitWindow   time.Duration
+	// IPRateLimitRequests caps the same endpoints per client IP within
+	// RateLimitWindow, whatever account is submitted; it is higher than
+	// RateLimitRequests because many users may share one NAT
+	IPRateLimitRequests int
+	// AvailabilityRateLimitRequests is the stricter per-IP limit for the
+	// signup availability check, which can be used to enumerate accounts
+	AvailabilityRateLimitRequests int
+	// DataExportRateLimitRequests is the per-user limit for the personal
+	// data export within RateLimitWindow; each export reads the whole audit
+	// history of the user
+	DataExportRateLimitRequests int
+
+	// IdempotencyKeyTTL is how long the response to a request sent with an
+	// Idempotency-Key header is replayed for retries with the same key
+	IdempotencyKeyTTL time.Duration
+}
+
+// TokenConfig sizes a single-use token sent by email
+type TokenConfig struct {
+	// Bytes is the number of random bytes in the token, before encoding
+	Bytes int
+	// TTL is how long the token stays valid
+	TTL time.Duration
+}
+
+// PasswordHashAlgorithm selects how new passwords are hashed
+type PasswordHashAlgorithm string
+
+const (
+	// PasswordHashArgon2id hashes with Argon2id (memory-hard, no length limit)
+	PasswordHashArgon2id PasswordHashAlgorithm = "argon2id"
+	// PasswordHashBcrypt hashes with bcrypt (only the first 72 bytes count,
+	// unless BcryptPreHash is set)
+	PasswordHashBcrypt PasswordHashAlgorithm = "bcrypt"
+)
+
+// UnverifiedLoginPolicy decides what happens when a user whose email is not
+// verified tries to log in
+type UnverifiedLoginPolicy string
+
+const (
+	// UnverifiedLoginBlock rejects the login until the email is verified
+	UnverifiedLoginBlock UnverifiedLoginPolicy = "block"
+	// UnverifiedLoginAllow lets the user in and flags the response
+	UnverifiedLoginAllow UnverifiedLoginPolicy = "allow"
+	// UnverifiedLoginGrace behaves like allow until VerificationGracePeriod
+	// has passed since registration, then like block
+	UnverifiedLoginGrace UnverifiedLoginPolicy = "grace"
+)
+
+// PasswordPolicy decides which checks a new password has to pass
+type PasswordPolicy string
+
+const (
+	// PasswordPolicyLength only enforces PasswordMinLength
+	PasswordPolicyLength PasswordPolicy = "length"
+	// PasswordPolicyStrength only enforces PasswordMinScore
+	PasswordPolicyStrength PasswordPolicy = "strength"
+	// PasswordPolicyBoth enforces PasswordMinLength and PasswordMinScore
+	PasswordPolicyBoth PasswordPolicy = "both"
+)
+
+// Bounds accepted by bcrypt (golang.org/x/crypto/bcrypt MinCost/MaxCost)
+const (
+	minBcryptCost = 4
+	maxBcryptCost = 31
+)
+
+// Argon2 requires at least 8 KiB of memory per lane and at most 255 lanes
+const (
+	minArgon2MemoryPerLane = 8
+	maxArgon2Parallelism   = 255
+)
+
+// minTokenBytes is the entropy floor for emailed tokens: 16 random bytes
+// (128 bits) keep them out of reach of online guessing
+const minTokenBytes = 16
+
+// DefaultSecurityConfig returns the settings used when no environment
+// variable overrides them
+func DefaultSecurityConfig() SecurityConfig {
+	return SecurityConfig{
+		PasswordHashAlgorithm:   PasswordHashArgon2id,
+		BcryptCost:              12,
+		Argon2Memory:            64 * 1024, // RFC 9106 second recommended option
+		Argon2Time:              3,
+		Argon2Parallelism:       4,
+		MaxLoginAttempts:        5,
+		LockoutDuration:         15 * time.Minute,
+		LoginTimingEqualization: true,
+		PasswordMinLength:       8,
+		PasswordMaxLength:       128,
+		PasswordPolicy:          PasswordPolicyLength,
+		PasswordMinScore:        3,
+		PasswordHistoryDepth:    5,
+		UnverifiedLoginPolicy:   UnverifiedLoginAllow,
+		VerificationGracePeriod: 72 * time.Hour,
+		VerificationToken:       TokenConfig{Bytes: 32, TTL: 24 * time.Hour},
+		PasswordResetToken:      TokenConfig{Bytes: 32, TTL: time.Hour},
+		MagicLinkToken:          TokenConfig{Bytes: 32, TTL: 15 * time.Minute},
+		RateLimitRequests:       10,
+		RateLimitWindow:         time.Minute,
+
+		IPRateLimitRequests:           100,
+		AvailabilityRateLimitRequests: 5,
+		DataExportRateLimitRequests:   2,
+		IdempotencyKeyTTL:             24 * time.Hour,
+		VerificationResendCooldown:    time.Minute,
+		UsernameChangeCooldown:        30 * 24 * time.Hour,
+		LockoutNotification:           true,
+		LockoutNotificationInterval:   time.Hour,
+	}
+}
+
+// loadSecurityConfig reads the security settings from the environment,
+// falling back to DefaultSecurityConfig for unset or unparsable values
+func loadSecurityConfig() SecurityConfig {
+	d := DefaultSecurityConfig()
+	return SecurityConfig{
+		PasswordHashAlgorithm:     PasswordHashAlgorithm(getEnv("PASSWORD_HASH_ALGORITHM", string(d.PasswordHashAlgorithm))),
+		BcryptCost:                getEnvAsInt("BCRYPT_COST", d.BcryptCost),
+		BcryptCalibrateTarget:     getEnvAsDuration("BCRYPT_CALIBRATE_TARGET", d.BcryptCalibrateTarget),
+		BcryptPreHash:             getEnvAsBool("BCRYPT_PREHASH", d.BcryptPreHash),
+		Argon2Memory:              getEnvAsInt("ARGON2_MEMORY", d.Argon2Memory),
+		Argon2Time:                getEnvAsInt("ARGON2_TIME", d.Argon2Time),
+		Argon2Parallelism:         getEnvAsInt("ARGON2_PARALLELISM", d.Argon2Parallelism),
+		MaxLoginAttempts:          getEnvAsInt("MAX_LOGIN_ATTEMPTS", d.MaxLoginAttempts),
+		LockoutDuration:           getEnvAsDuration("LOCKOUT_DURATION", d.LockoutDuration),
+		LoginTimingEqualization:   getEnvAsBool("LOGIN_TIMING_EQUALIZATION", d.LoginTimingEqualization),
+		MaxSessionsPerUser:        getEnvAsInt("MAX_SESSIONS_PER_USER", d.MaxSessionsPerUser),
+		SingleSessionMode:         getEnvAsBool("SINGLE_SESSION_MODE", d.SingleSessionMode),
+		PasswordMinLength:         getEnvAsInt("PASSWORD_MIN_LENGTH", d.PasswordMinLength),
+		PasswordMaxLength:         getEnvAsInt("PASSWORD_MAX_LENGTH", d.PasswordMaxLength),
+		PasswordRequireUpper:      getEnvAsBool("PASSWORD_REQUIRE_UPPERCASE", d.PasswordRequireUpper),
+		PasswordRequireLower:      getEnvAsBool("PASSWORD_REQUIRE_LOWERCASE", d.PasswordRequireLower),
+		PasswordRequireDigit:      getEnvAsBool("PASSWORD_REQUIRE_DIGIT", d.PasswordRequireDigit),
+		PasswordRequireSymbol:     getEnvAsBool("PASSWORD_REQUIRE_SYMBOL", d.PasswordRequireSymbol),
+		PasswordBreachCheck:       getEnvAsBool("PASSWORD_BREACH_CHECK", d.PasswordBreachCheck),
+		PasswordPolicy:            PasswordPolicy(getEnv("PASSWORD_POLICY", string(d.PasswordPolicy))),
+		PasswordMinScore:          getEnvAsInt("PASSWORD_MIN_SCORE", d.PasswordMinScore),
+		PasswordHistoryDepth:      getEnvAsInt("PASSWORD_HISTORY_DEPTH", d.PasswordHistoryDepth),
+		PasswordHistoryOnRegister: getEnvAsBool("PASSWORD_HISTORY_ON_REGISTER", d.PasswordHistoryOnRegister),
+		UsernameBlocklist:         getEnvAsSlice("USERNAME_BLOCKLIST", d.UsernameBlocklist),
+		UnverifiedLoginPolicy:     UnverifiedLoginPolicy(getEnv("UNVERIFIED_LOGIN_POLICY", string(d.UnverifiedLoginPolicy))),
+		VerificationGracePeriod:   getEnvAsDuration("VERIFICATION_GRACE_PERIOD", d.VerificationGracePeriod),
+		VerificationToken:         loadTokenConfig("VERIFICATION_TOKEN", d.VerificationToken),
+		PasswordResetToken:        loadTokenConfig("PASSWORD_RESET_TOKEN", d.PasswordResetToken),
+		MagicLinkToken:            loadTokenConfig("MAGIC_LINK_TOKEN", d.MagicLinkToken),
+		RateLimitRequests:         getEnvAsInt("RATE_LIMIT_REQUESTS", d.RateLimitRequests),
+		RateLimitWindow:           getEnvAsDuration("RATE_LIMIT_WINDOW", d.RateLimitWindow),
+
+		IPRateLimitRequests:           getEnvAsInt("IP_RATE_LIMIT_REQUESTS", d.IPRateLimitRequests),
+		AvailabilityRateLimitRequests: getEnvAsInt("AVAILABILITY_RATE_LIMIT_REQUESTS", d.AvailabilityRateLimitRequests),
+		DataExportRateLimitRequests:   getEnvAsInt("DATA_EXPORT_RATE_LIMIT_REQUESTS", d.DataExportRateLimitRequests),
+		IdempotencyKeyTTL:             getEnvAsDuration("IDEMPOTENCY_KEY_TTL", d.IdempotencyKeyTTL),
+		VerificationResendCooldown:    getEnvAsDuration("VERIFICATION_RESEND_COOLDOWN", d.VerificationResendCooldown),
+		UsernameChangeCooldown:        getEnvAsDuration("USERNAME_CHANGE_COOLDOWN", d.UsernameChangeCooldown),
+		LockoutNotification:           getEnvAsBool("LOCKOUT_NOTIFICATION", d.LockoutNotification),
+		LockoutNotificationInterval:   getEnvAsDuration("LOCKOUT_NOTIFICATION_INTERVAL", d.LockoutNotificationInterval),
+	}
+}
+
+// loadTokenConfig reads <prefix>_BYTES and <prefix>_TTL
+func loadTokenConfig(prefix string, d TokenConfig) TokenConfig {
+	return TokenConfig{
+		Bytes: getEnvAsInt(prefix+"_BYTES", d.Bytes),
+		TTL:   getEnvAsDuration(prefix+"_TTL", d.TTL),
+	}
+}
+
+// Validate reports every invalid setting at once
+func (c SecurityConfig) Validate() error {
+	var errs []error
+
+	if c.BcryptCost < minBcryptCost || c.BcryptCost > maxBcryptCost {
+		errs = append(errs, fmt.Errorf("BCRYPT_COST must be between %d and %d, got %d", minBcryptCost, maxBcryptCost, c.BcryptCost))
+	}
+	if c.BcryptCalibrateTarget < 0 {
+		errs = append(errs, fmt.Errorf("BCRYPT_CALIBRATE_TARGET must not be negative, got %s", c.BcryptCalibrateTarget))
+	}
+	switch c.PasswordHashAlgorithm {
+	case PasswordHashArgon2id, PasswordHashBcrypt:
+	default:
+		errs = append(errs, fmt.Errorf("PASSWORD_HASH_ALGORITHM must be one of argon2id, bcrypt, got %q", c.PasswordHashAlgorithm))
+	}
+	if c.Argon2Parallelism < 1 || c.Argon2Parallelism > maxArgon2Parallelism {
+		errs = append(errs, fmt.Errorf("ARGON2_PARALLELISM must be between 1 and %d, got %d", maxArgon2Parallelism, c.Argon2Parallelism))
+	}
+	if c.Argon2Time < 1 {
+		errs = append(errs, fmt.Errorf("ARGON2_TIME must be at least 1, got %d", c.Argon2Time))
+	}
+	if c.Argon2Memory < minArgon2MemoryPerLane*c.Argon2Parallelism {
+		errs = append(errs, fmt.Errorf("ARGON2_MEMORY must be at least %d KiB per lane, got %d", minArgon2MemoryPerLane, c.Argon2Memory))
+	}
+	if c.MaxLoginAttempts < 1 {
+		errs = append(errs, fmt.Errorf("MAX_LOGIN_ATTEMPTS must be at least 1, got %d", c.MaxLoginAttempts))
+	}
+	if c.MaxSessionsPerUser < 0 {
+		errs = append(errs, fmt.Errorf("MAX_SESSIONS_PER_USER must not be negative, got %d", c.MaxSessionsPerUser))
+	}
+	if c.PasswordMinLength < 8 {
+		errs = append(errs, fmt.Errorf("PASSWORD_MIN_LENGTH must be at least 8, got %d", c.PasswordMinLength))
+	}
+	if c.PasswordMaxLength < c.PasswordMinLength {
+		errs = append(errs, fmt.Errorf("PASSWORD_MAX_LENGTH must be at least PASSWORD_MIN_LENGTH (%d), got %d", c.PasswordMinLength, c.PasswordMaxLength))
+	}
+	if c.PasswordHistoryDepth < 0 {
+		errs = append(errs, fmt.Errorf("PASSWORD_HISTORY_DEPTH must not be negative, got %d", c.PasswordHistoryDepth))
+	}
+	if c.RateLimitRequests < 1 {
+		errs = append(errs, fmt.Errorf("RATE_LIMIT_REQUESTS must be at least 1, got %d", c.RateLimitRequests))
+	}
+	if c.IPRateLimitRequests < 1 {
+		errs = append(errs, fmt.Errorf("IP_RATE_LIMIT_REQUESTS must be at least 1, got %d", c.IPRateLimitRequests))
+	}
+	if c.AvailabilityRateLimitRequests < 1 {
+		errs = append(errs, fmt.Errorf("AVAILABILITY_RATE_LIMIT_REQUESTS must be at least 1, got %d", c.AvailabilityRateLimitRequests))
+	}
+	if c.DataExportRateLimitRequests < 1 {
+		errs = append(errs, fmt.Errorf("DATA_EXPORT_RATE_LIMIT_REQUESTS must be at least 1, got %d", c.DataExportRateLimitRequests))
+	}
+
+	if c.PasswordMinScore < 0 || c.PasswordMinScore > 4 {
+		errs = append(errs, fmt.Errorf("PASSWORD_MIN_SCORE must be between 0 and 4, got %d", c.PasswordMinScore))
+	}
+	switch c.PasswordPolicy {
+	case PasswordPolicyLength, PasswordPolicyStrength, PasswordPolicyBoth:
+	default:
+		errs = append(errs, fmt.Errorf("PASSWORD_POLICY must be one of length, strength, both, got %q", c.PasswordPolicy))
+	}
+
+	for _, t := range []struct {
+		name  string
+		bytes int
+	}{
+		{"VERIFICATION_TOKEN_BYTES", c.VerificationToken.Bytes},
+		{"PASSWORD_RESET_TOKEN_BYTES", c.PasswordResetToken.Bytes},
+		{"MAGIC_LINK_TOKEN_BYTES", c.MagicLinkToken.Bytes},
+	} {
+		if t.bytes < minTokenBytes {
+			errs = append(errs, fmt.Errorf("%s must be at least %d, got %d", t.name, minTokenBytes, t.bytes))
+		}
+	}
+	if c.VerificationResendCooldown < 0 {
+		errs = append(errs, fmt.Errorf("VERIFICATION_RESEND_COOLDOWN must not be negative, got %s", c.VerificationResendCooldown))
+	}
+	if c.LockoutNotificationInterval < 0 {
+		errs = append(errs, fmt.Errorf("LOCKOUT_NOTIFICATION_INTERVAL must not be negative, got %s", c.LockoutNotificationInterval))
+	}
+	if c.UsernameChangeCooldown < 0 {
+		errs = append(errs, fmt.Errorf("USERNAME_CHANGE_COOLDOWN must not be negative, got %s", c.UsernameChangeCooldown))
+	}
+
+	switch c.UnverifiedLoginPolicy {
+	case UnverifiedLoginBlock, UnverifiedLoginAllow, UnverifiedLoginGrace:
+	default:
+		errs = append(errs, fmt.Errorf("UNVERIFIED_LOGIN_POLICY must be one of block, allow, grace, got %q", c.UnverifiedLoginPolicy))
+	}
+
+	for _, d := range []struct {
+		name  string
+		value time.Duration
+	}{
+		{"LOCKOUT_DURATION", c.LockoutDuration},
+		{"VERIFICATION_GRACE_PERIOD", c.VerificationGracePeriod},
+		{"VERIFICATION_TOKEN_TTL", c.VerificationToken.TTL},
+		{"PASSWORD_RESET_TOKEN_TTL", c.PasswordResetToken.TTL},
+		{"MAGIC_LINK_TOKEN_TTL", c.MagicLinkToken.TTL},
+		{"RATE_LIMIT_WINDOW", c.RateLimitWindow},
+		{"IDEMPOTENCY_KEY_TTL", c.IdempotencyKeyTTL},
+	} {
+		if d.value <= 0 {
+			errs = append(errs, fmt.Errorf("%s must be positive, got %s", d.name, d.value))
+		}
+	}
+
+	return errors.Join(errs...)
+}
@@ -0,0 +1,180 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+var securityEnvKeys = []string{
+	"PASSWORD_HASH_ALGORITHM", "BCRYPT_COST", "BCRYPT_CALIBRATE_TARGET", "BCRYPT_PREHASH", "ARGON2_MEMORY", "ARGON2_TIME", "ARGON2_PARALLELISM",
+	"MAX_LOGIN_ATTEMPTS", "LOCKOUT_DURATION", "LOCKOUT_NOTIFICATION", "LOCKOUT_NOTIFICATION_INTERVAL", "LOGIN_TIMING_EQUALIZATION", "MAX_SESSIONS_PER_USER", "SINGLE_SESSION_MODE", "PASSWORD_MIN_LENGTH",
+	"PASSWORD_POLICY", "PASSWORD_MIN_SCORE", "PASSWORD_MAX_LENGTH", "PASSWORD_REQUIRE_UPPERCASE",
+	"PASSWORD_REQUIRE_LOWERCASE", "PASSWORD_REQUIRE_DIGIT", "PASSWORD_REQUIRE_SYMBOL", "PASSWORD_BREACH_CHECK",
+	"PASSWORD_HISTORY_DEPTH", "PASSWORD_HISTORY_ON_REGISTER",
+	"UNVERIFIED_LOGIN_POLICY", "VERIFICATION_GRACE_PERIOD", "VERIFICATION_TOKEN_TTL", "VERIFICATION_TOKEN_BYTES",
+	"PASSWORD_RESET_TOKEN_TTL", "PASSWORD_RESET_TOKEN_BYTES", "MAGIC_LINK_TOKEN_TTL", "MAGIC_LINK_TOKEN_BYTES", "RATE_LIMIT_REQUESTS", "RATE_LIMIT_WINDOW",
+	"USERNAME_BLOCKLIST", "IDEMPOTENCY_KEY_TTL", "IP_RATE_LIMIT_REQUESTS", "AVAILABILITY_RATE_LIMIT_REQUESTS",
+	"DATA_EXPORT_RATE_LIMIT_REQUESTS",
+	"VERIFICATION_RESEND_COOLDOWN",
+	"USERNAME_CHANGE_COOLDOWN",
+}
+
+// unsetSecurityEnv clears the security env vars for the duration of the test
+// (getEnv treats empty values as unset)
+func unsetSecurityEnv(t *testing.T) {
+	t.Helper()
+	for _, key := range securityEnvKeys {
+		t.Setenv(key, "")
+	}
+}
+
+func TestLoadSecurityConfigDefaults(t *testing.T) {
+	unsetSecurityEnv(t)
+
+	got := loadSecurityConfig()
+	if !reflect.DeepEqual(got, DefaultSecurityConfig()) {
+		t.Errorf("got %+v, want defaults %+v", got, DefaultSecurityConfig())
+	}
+	if err := got.Validate(); err != nil {
+		t.Errorf("defaults must be valid: %v", err)
+	}
+}
+
+func TestLoadSecurityConfigFromEnv(t *testing.T) {
+	unsetSecurityEnv(t)
+	t.Setenv("BCRYPT_COST", "10")
+	t.Setenv("BCRYPT_CALIBRATE_TARGET", "250ms")
+	t.Setenv("BCRYPT_PREHASH", "true")
+	t.Setenv("LOCKOUT_DURATION", "1d")
+	t.Setenv("UNVERIFIED_LOGIN_POLICY", "block")
+	t.Setenv("RATE_LIMIT_WINDOW", "30s")
+	t.Setenv("PASSWORD_POLICY", "both")
+	t.Setenv("PASSWORD_MIN_SCORE", "4")
+	t.Setenv("PASSWORD_HASH_ALGORITHM", "bcrypt")
+	t.Setenv("ARGON2_MEMORY", "19456")
+	t.Setenv("PASSWORD_REQUIRE_DIGIT", "true")
+	t.Setenv("PASSWORD_BREACH_CHECK", "true")
+	t.Setenv("USERNAME_BLOCKLIST", "acme, acme-support")
+	t.Setenv("IDEMPOTENCY_KEY_TTL", "1h")
+	t.Setenv("LOGIN_TIMING_EQUALIZATION", "false")
+	t.Setenv("SINGLE_SESSION_MODE", "true")
+	t.Setenv("PASSWORD_HISTORY_DEPTH", "10")
+	t.Setenv("PASSWORD_HISTORY_ON_REGISTER", "true")
+	t.Setenv("MAGIC_LINK_TOKEN_BYTES", "48")
+	t.Setenv("MAGIC_LINK_TOKEN_TTL", "5m")
+
+	got := loadSecurityConfig()
+	if got.BcryptCost != 10 || got.BcryptCalibrateTarget != 250*time.Millisecond || !got.BcryptPreHash {
+		t.Errorf("BcryptCost = %d, BcryptCalibrateTarget = %s, BcryptPreHash = %v", got.BcryptCost, got.BcryptCalibrateTarget, got.BcryptPreHash)
+	}
+	if got.LockoutDuration != 24*time.Hour {
+		t.Errorf("LockoutDuration = %s", got.LockoutDuration)
+	}
+	if got.UnverifiedLoginPolicy != UnverifiedLoginBlock {
+		t.Errorf("UnverifiedLoginPolicy = %q", got.UnverifiedLoginPolicy)
+	}
+	if got.RateLimitWindow != 30*time.Second {
+		t.Errorf("RateLimitWindow = %s", got.RateLimitWindow)
+	}
+	if got.PasswordPolicy != PasswordPolicyBoth || got.PasswordMinScore != 4 {
+		t.Errorf("PasswordPolicy = %q, PasswordMinScore = %d", got.PasswordPolicy, got.PasswordMinScore)
+	}
+	if got.PasswordHashAlgorithm != PasswordHashBcrypt || got.Argon2Memory != 19456 {
+		t.Errorf("PasswordHashAlgorithm = %q, Argon2Memory = %d", got.PasswordHashAlgorithm, got.Argon2Memory)
+	}
+	if !got.PasswordRequireDigit || got.PasswordRequireSymbol || !got.PasswordBreachCheck {
+		t.Errorf("PasswordRequireDigit = %v, PasswordRequireSymbol = %v, PasswordBreachCheck = %v",
+			got.PasswordRequireDigit, got.PasswordRequireSymbol, got.PasswordBreachCheck)
+	}
+	if !reflect.DeepEqual(got.UsernameBlocklist, []string{"acme", "acme-support"}) {
+		t.Errorf("UsernameBlocklist = %q", got.UsernameBlocklist)
+	}
+	if got.IdempotencyKeyTTL != time.Hour {
+		t.Errorf("IdempotencyKeyTTL = %s", got.IdempotencyKeyTTL)
+	}
+	if want := (TokenConfig{Bytes: 48, TTL: 5 * time.Minute}); got.MagicLinkToken != want {
+		t.Errorf("MagicLinkToken = %+v, want %+v", got.MagicLinkToken, want)
+	}
+	if got.LoginTimingEqualization {
+		t.Error("LoginTimingEqualization = true")
+	}
+	if !got.SingleSessionMode {
+		t.Error("SingleSessionMode = false")
+	}
+	if got.PasswordHistoryDepth != 10 || !got.PasswordHistoryOnRegister {
+		t.Errorf("PasswordHistoryDepth = %d, PasswordHistoryOnRegister = %v", got.PasswordHistoryDepth, got.PasswordHistoryOnRegister)
+	}
+}
+
+func TestLoadSecurityConfigFallsBackOnUnparsableValues(t *testing.T) {
+	unsetSecurityEnv(t)
+	t.Setenv("BCRYPT_COST", "twelve")
+	t.Setenv("PASSWORD_RESET_TOKEN_TTL", "soon")
+
+	got := loadSecurityConfig()
+	d := DefaultSecurityConfig()
+	if got.BcryptCost != d.BcryptCost {
+		t.Errorf("BcryptCost = %d, want default %d", got.BcryptCost, d.BcryptCost)
+	}
+	if got.PasswordResetToken.TTL != d.PasswordResetToken.TTL {
+		t.Errorf("PasswordResetToken.TTL = %s, want default %s", got.PasswordResetToken.TTL, d.PasswordResetToken.TTL)
+	}
+}
+
+func TestSecurityConfigValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*SecurityConfig)
+		want   string
+	}{
+		{"bcrypt cost too low", func(c *SecurityConfig) { c.BcryptCost = 3 }, "BCRYPT_COST"},
+		{"bcrypt cost too high", func(c *SecurityConfig) { c.BcryptCost = 32 }, "BCRYPT_COST"},
+		{"unknown hash algorithm", func(c *SecurityConfig) { c.PasswordHashAlgorithm = "md5" }, "PASSWORD_HASH_ALGORITHM"},
+		{"argon2 memory below lanes", func(c *SecurityConfig) { c.Argon2Memory = 16 }, "ARGON2_MEMORY"},
+		{"argon2 zero passes", func(c *SecurityConfig) { c.Argon2Time = 0 }, "ARGON2_TIME"},
+		{"argon2 too many lanes", func(c *SecurityConfig) { c.Argon2Parallelism = 256 }, "ARGON2_PARALLELISM"},
+		{"no login attempts", func(c *SecurityConfig) { c.MaxLoginAttempts = 0 }, "MAX_LOGIN_ATTEMPTS"},
+		{"short password minimum", func(c *SecurityConfig) { c.PasswordMinLength = 4 }, "PASSWORD_MIN_LENGTH"},
+		{"maximum below minimum", func(c *SecurityConfig) { c.PasswordMaxLength = 6 }, "PASSWORD_MAX_LENGTH"},
+		{"unknown policy", func(c *SecurityConfig) { c.UnverifiedLoginPolicy = "maybe" }, "UNVERIFIED_LOGIN_POLICY"},
+		{"unknown password policy", func(c *SecurityConfig) { c.PasswordPolicy = "rules" }, "PASSWORD_POLICY"},
+		{"password score out of range", func(c *SecurityConfig) { c.PasswordMinScore = 5 }, "PASSWORD_MIN_SCORE"},
+		{"negative password history depth", func(c *SecurityConfig) { c.PasswordHistoryDepth = -1 }, "PASSWORD_HISTORY_DEPTH"},
+		{"zero reset ttl", func(c *SecurityConfig) { c.PasswordResetToken.TTL = 0 }, "PASSWORD_RESET_TOKEN_TTL"},
+		{"zero magic link ttl", func(c *SecurityConfig) { c.MagicLinkToken.TTL = 0 }, "MAGIC_LINK_TOKEN_TTL"},
+		{"short verification token", func(c *SecurityConfig) { c.VerificationToken.Bytes = 8 }, "VERIFICATION_TOKEN_BYTES"},
+		{"short reset token", func(c *SecurityConfig) { c.PasswordResetToken.Bytes = 15 }, "PASSWORD_RESET_TOKEN_BYTES"},
+		{"short magic link token", func(c *SecurityConfig) { c.MagicLinkToken.Bytes = 0 }, "MAGIC_LINK_TOKEN_BYTES"},
+		{"negative bcrypt calibration target", func(c *SecurityConfig) { c.BcryptCalibrateTarget = -time.Second }, "BCRYPT_CALIBRATE_TARGET"},
+		{"zero rate limit", func(c *SecurityConfig) { c.RateLimitRequests = 0 }, "RATE_LIMIT_REQUESTS"},
+		{"zero IP rate limit", func(c *SecurityConfig) { c.IPRateLimitRequests = 0 }, "IP_RATE_LIMIT_REQUESTS"},
+		{"zero availability rate limit", func(c *SecurityConfig) { c.AvailabilityRateLimitRequests = 0 }, "AVAILABILITY_RATE_LIMIT_REQUESTS"},
+		{"zero data export rate limit", func(c *SecurityConfig) { c.DataExportRateLimitRequests = 0 }, "DATA_EXPORT_RATE_LIMIT_REQUESTS"},
+		{"negative resend cooldown", func(c *SecurityConfig) { c.VerificationResendCooldown = -time.Second }, "VERIFICATION_RESEND_COOLDOWN"},
+		{"negative lockout notification interval", func(c *SecurityConfig) { c.LockoutNotificationInterval = -time.Second }, "LOCKOUT_NOTIFICATION_INTERVAL"},
+		{"negative username change cooldown", func(c *SecurityConfig) { c.UsernameChangeCooldown = -time.Second }, "USERNAME_CHANGE_COOLDOWN"},
+		{"zero idempotency ttl", func(c *SecurityConfig) { c.IdempotencyKeyTTL = 0 }, "IDEMPOTENCY_KEY_TTL"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := DefaultSecurityConfig()
+			tt.modify(&cfg)
+			err := cfg.Validate()
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("got %v, want error mentioning %s", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseDurationSupportsDays(t *testing.T) {
+	if got := parseDuration("7d"); got != 7*24*time.Hour {
+		t.Errorf("parseDuration(7d) = %s", got)
+	}
+	if got := parseDuration("90m"); got != 90*time.Minute {
+		t.Errorf("parseDuration(90m) = %s", got)
+	}
+}
@@ -0,0 +1,180 @@
+package dto
+
+import "time"
+
+// Per-item outcomes of a bulk email verification
+const (
+	BulkVerifyStatusVerified        = "verified"
+	BulkVerifyStatusAlreadyVerified = "already_verified"
+	BulkVerifyStatusNotFound        = "not_found"
+)
+
+// BulkVerifyRequest represents the admin bulk email verification payload.
+// Each entry is either a user ID or an email address.
+type BulkVerifyRequest struct {
+	Users []string `json:"users" binding:"required,min=1,max=500,dive,required"`
+}
+
+// BulkVerifyResult is the outcome for one entry of a BulkVerifyRequest
+type BulkVerifyResult struct {
+	User   string `json:"user"`
+	UserID string `json:"user_id,omitempty"`
+	Status string `json:"status"`
+}
+
+// BulkVerifyResponse represents the bulk email verification response
+type BulkVerifyResponse struct {
+	Verified int                `json:"verified"`
+	Results  []BulkVerifyResult `json:"results"`
+}
+
+// Per-user outcomes of an import
+const (
+	ImportStatusCreated     = "created"
+	ImportStatusDuplicate   = "duplicate"
+	ImportStatusInvalidHash = "invalid_hash"
+)
+
+// ImportUser is one user exported from another system. PasswordHash is a
+// bcrypt or Argon2id hash; Algorithm, when given, must match it.
+type ImportUser struct {
+	Email        string `json:"email" binding:"required,email"`
+	Username     string `json:"username" binding:"required,min=3,max=50"`
+	PasswordHash string `json:"password_hash" binding:"required"`
+	Algorithm    string `json:"algorithm" binding:"omitempty,oneof=bcrypt argon2id"`
+	IsVerified   bool   `json:"is_verified"`
+}
+
+// ImportUsersRequest represents the admin user import payload
+type ImportUsersRequest struct {
+	Users []ImportUser `json:"users" binding:"required,min=1,max=500,dive"`
+}
+
+// ImportUserResult is the outcome for one entry of an ImportUsersRequest
+type ImportUserResult struct {
+	Email    string `json:"email"`
+	Username string `json:"username"`
+	UserID   string `json:"user_id,omitempty"`
+	Status   string `json:"status"`
+}
+
+// ImportUsersResponse represents the user import response
+type ImportUsersResponse struct {
+	Created int                `json:"created"`
+	Skipped int                `json:"skipped"`
+	Results []ImportUserResult `json:"results"`
+}
+
+// ChangeRoleRequest represents the admin role change payload
+type ChangeRoleRequest struct {
+	Role string `json:"role" binding:"required,oneof=user admin"`
+}
+
+// ResetPasswordResponse carries the temporary password an admin issued. It
+// was also emailed to the user; the user has to change it after signing in.
+type ResetPasswordResponse struct {
+	TemporaryPassword string `json:"temporary_password"`
+}
+
+// CreateInviteRequest represents the invite creation request payload
+type CreateInviteRequest struct {
+	// Email, if set, is the only address that can register with the invite
+	Email string `json:"email" binding:"omitempty,email"`
+}
+
+// InviteResponse carries a new registration invite. The token is only
+// returned here; the admin passes it on to the invited person.
+type InviteResponse struct {
+	ID        string    `json:"id"`
+	Token     string    `json:"token"`
+	Email     string    `json:"email,omitempty"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// ListUsersQuery represents the admin user list query parameters
+type ListUsersQuery struct {
+	Role       string `form:"role"`
+	IsActive   *bool  `form:"is_active"`
+	IsVerified *bool  `form:"is_verified"`
+	Search     string `form:"search" binding:"omitempty,max=100"`
+	Page       int    `form:"page" binding:"omitempty,min=1"`
+	PageSize   int    `form:"page_size" binding:"omitempty,min=1,max=100"`
+}
+
+// UserSessionsQuery are the query parameters of the admin session list
+type UserSessionsQuery struct {
+	// IncludeRevoked also lists revoked and expired sessions
+	IncludeRevoked bool `form:"include_revoked"`
+}
+
+// UserListResponse is one page of the admin user list
+type UserListResponse struct {
+	Users      []*UserInfo `json:"users"`
+	Page       int         `json:"page"`
+	PageSize   int         `json:"page_size"`
+	Total      int64       `json:"total"`
+	TotalPages int         `json:"total_pages"`
+}
+
+// ListAuditLogsQuery represents the admin audit log query parameters
+type ListAuditLogsQuery struct {
+	UserID   string `form:"user_id" binding:"omitempty,uuid"`
+	Action   string `form:"action"`
+	Page     int    `form:"page" binding:"omitempty,min=1"`
+	PageSize int    `form:"page_size" binding:"omitempty,min=1,max=100"`
+}
+
+// AuditLogInfo is one audit log entry. ActorID and TargetID are empty when
+// the user is unknown, e.g. for a failed login with an unknown email.
+type AuditLogInfo struct {
+	ID        string            `json:"id"`
+	Action    string            `json:"action"`
+	ActorID   string            `json:"actor_id,omitempty"`
+	TargetID  string            `json:"target_id,omitempty"`
+	IPAddress string            `json:"ip_address,omitempty"`
+	UserAgent string            `json:"user_agent,omitempty"`
+	RequestID string            `json:"request_id,omitempty"`
+	Details   map[string]string `json:"details,omitempty"`
+	CreatedAt time.Time         `json:"created_at"`
+}
+
+// AuditLogListResponse is one page of the audit log, newest first
+type AuditLogListResponse struct {
+	Logs       []*AuditLogInfo `json:"logs"`
+	Page       int             `json:"page"`
+	PageSize   int             `json:"page_size"`
+	Total      int64           `json:"total"`
+	TotalPages int             `json:"total_pages"`
+}
+
+// CreateAPIKeyRequest represents the admin API key creation payload. A zero
+// ExpiresIn creates a key that does not expire.
+type CreateAPIKeyRequest struct {
+	Name      string   `json:"name" binding:"required,max=100"`
+	Scopes    []string `json:"scopes" binding:"required,min=1,dive,required"`
+	ExpiresIn string   `json:"expires_in" example:"720h"`
+}
+
+// APIKeyInfo describes an API key without the key itself
+type APIKeyInfo struct {
+	ID        string     `json:"id"`
+	Name      string     `json:"name"`
+	Prefix    string     `json:"prefix"`
+	Scopes    []string   `json:"scopes"`
+	OwnerID   string     `json:"owner_id,omitempty"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// CreateAPIKeyResponse carries the plaintext key. It is returned only once;
+// the service keeps just a hash.
+type CreateAPIKeyResponse struct {
+	APIKeyInfo
+	Key string `json:"key"`
+}
+
+// APIKeyListResponse lists every API key, newest first
+type APIKeyListResponse struct {
+	Keys []*APIKeyInfo `json:"keys"`
+}
@@ -1,5 +1,10 @@
 package dto
 
+import (
+	"encoding/json"
+	"time"
+)
+
 // RegisterRequest represents the registration request payload
 type RegisterRequest struct {
 	Email     string `json:"email" binding:"required,email"`
@@ -7,26 +12,66 @@ type RegisterRequest struct {
 	Password  string `json:"password" binding:"required,min=8"`
 	FirstName string `json:"first_name" binding:"required"`
 	LastName  string `json:"last_name" binding:"required"`
+	// Phone is optional; it is stored in E.164 form and can be used to log
+	// in instead of the email or username
+	Phone string `json:"phone,omitempty" binding:"omitempty,max=32" example:"+905551234567"`
+	// OrganizationSlug selects the organization to join; empty joins the
+	// default organization
+	OrganizationSlug string `json:"organization_slug" binding:"omitempty,max=64"`
+	// CaptchaToken is the provider's CAPTCHA solution; required while a
+	// CAPTCHA provider is configured
+	CaptchaToken string `json:"captcha_token,omitempty"`
+	// InviteToken is a single-use invite from an admin; required while
+	// registration is invite-only. The user joins the invite's organization
+	// and organization_slug is ignored
+	InviteToken string `json:"invite_token,omitempty" binding:"omitempty,max=128"`
 }
 
 // LoginRequest represents the login request payload
 type LoginRequest struct {
+	// EmailOrUsername also accepts the phone number given at registration
 	EmailOrUsername string `json:"email_or_username" binding:"required"`
 	Password        string `json:"password" binding:"required"`
+	// OrganizationSlug is the organization the account belongs to; empty
+	// means the default organization
+	OrganizationSlug string `json:"organization_slug" binding:"omitempty,max=64"`
+	// RememberMe issues a refresh token with the longer remember-me lifetime
+	RememberMe bool `json:"remember_me"`
 }
 
-// RefreshTokenRequest represents the refresh token request payload
+// RefreshTokenRequest represents the refresh token request payload. Web
+// clients may leave it out and send the refresh token cookie instead.
 type RefreshTokenRequest struct {
-	RefreshToken string `json:"refresh_token" binding:"required"`
+	RefreshToken string `json:"refresh_token"`
+}
+
+// LogoutRequest represents the optional logout payload. With a refresh token
+// only that session is signed out.
+type LogoutRequest struct {
+	RefreshToken string `json:"refresh_token"`
 }
 
 // AuthResponse represents the authentication response
 type AuthResponse struct {
-	AccessToken  string    `json:"access_token"`
-	RefreshToken string    `json:"refresh_token"`
-	TokenType    string    `json:"token_type"`
-	ExpiresIn    int64     `json:"expires_in"`
+	// Token fields are empty for accounts that are still pending approval
+	AccessToken  string    `json:"access_token,omitempty"`
+	RefreshToken string    `json:"refresh_token,omitempty"`
+	TokenType    string    `json:"token_type,omitempty"`
+	ExpiresIn    int64     `json:"expires_in,omitempty"`
 	User         *UserInfo `json:"user"`
+	// RefreshTokenExpiresAt is when RefreshToken expires; it sets the
+	// lifetime of the refresh token cookie
+	RefreshTokenExpiresAt time.Time `json:"-"`
+	// EmailVerificationRequired is set when the login was allowed but the
+	// user still has to verify their email address
+	EmailVerificationRequired bool `json:"email_verification_required,omitempty"`
+	// ApprovalPending is set on registration when the account has to be
+	// approved by an admin before it can log in
+	ApprovalPending bool `json:"approval_pending,omitempty"`
+	// MustChangePassword is set when the user signed in with a temporary
+	// password from an admin. Until PUT /api/auth/password succeeds, the
+	// other authenticated endpoints answer 403 password_change_required
+	MustChangePassword bool `json:"must_change_password,omitempty"`
 }
 
 // UserInfo represents user information in responses
@@ -36,7 +81,211 @@ type UserInfo struct {
 	Username  string `json:"username"`
 	FirstName string `json:"first_name"`
 	LastName  string `json:"last_name"`
+	Phone     string `json:"phone,omitempty"`
 	IsActive  bool   `json:"is_active"`
+	Role      string `json:"role"`
+	// OrganizationID is empty when multi-tenancy is not configured
+	OrganizationID string `json:"organization_id,omitempty"`
+	IsVerified     bool   `json:"is_verified"`
+}
+
+// MeResponse is the current user's account state returned by GET /api/auth/me.
+// It extends UserInfo with what a frontend needs to render the account page,
+// so login and register responses keep their smaller shape.
+type MeResponse struct {
+	UserInfo
+	// Scopes are the permissions granted by the user's role
+	Scopes []string `json:"scopes"`
+	// TwoFactorEnabled is always false: the service has no second factor yet
+	TwoFactorEnabled bool      `json:"two_factor_enabled"`
+	CreatedAt        time.Time `json:"created_at"`
+	// LinkedProviders are the social login providers linked to the account
+	LinkedProviders []string `json:"linked_providers"`
+}
+
+// ForgotPasswordRequest represents the forgot-password request payload
+type ForgotPasswordRequest struct {
+	Email string `json:"email" binding:"required,email"`
+	// OrganizationSlug is the organization the account belongs to; empty
+	// means the default organization
+	OrganizationSlug string `json:"organization_slug" binding:"omitempty,max=64"`
+	// CaptchaToken is the provider's CAPTCHA solution; required while a
+	// CAPTCHA provider is configured
+	CaptchaToken string `json:"captcha_token,omitempty"`
+}
+
+// MagicLinkRequest represents the passwordless login request payload
+type MagicLinkRequest struct {
+	Email string `json:"email" binding:"required,email"`
+	// OrganizationSlug is the organization the account belongs to; empty
+	// means the default organization
+	OrganizationSlug string `json:"organization_slug" binding:"omitempty,max=64"`
+}
+
+// ResendVerificationRequest represents the unauthenticated resend-verification request payload
+type ResendVerificationRequest struct {
+	Email string `json:"email" binding:"required,email"`
+	// OrganizationSlug is the organization the account belongs to; empty
+	// means the default organization
+	OrganizationSlug string `json:"organization_slug" binding:"omitempty,max=64"`
+}
+
+// ResetPasswordRequest represents the reset-password request payload
+type ResetPasswordRequest struct {
+	Token       string `json:"token" binding:"required"`
+	NewPassword string `json:"new_password" binding:"required,min=8"`
+}
+
+// ChangePasswordRequest represents the change-password request payload
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+	NewPassword     string `json:"new_password" binding:"required,min=8"`
+}
+
+// UpdateProfileRequest represents the profile update payload. Email and
+// username cannot be changed here; see ChangeUsernameRequest.
+type UpdateProfileRequest struct {
+	FirstName string `json:"first_name" binding:"required,max=100"`
+	LastName  string `json:"last_name" binding:"required,max=100"`
+}
+
+// ChangeUsernameRequest represents the username change payload
+type ChangeUsernameRequest struct {
+	Username string `json:"username" binding:"required,min=3,max=50"`
+}
+
+// ConfirmPasswordRequest carries the current password to confirm an account
+// deactivation or deletion
+type ConfirmPasswordRequest struct {
+	Password string `json:"password" binding:"required"`
+}
+
+// VerifyEmailRequest represents the email verification request payload
+type VerifyEmailRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// ResetTokenValidationResponse represents the result of a password reset token check
+type ResetTokenValidationResponse struct {
+	Valid     bool      `json:"valid"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// PasswordStrengthRequest represents the password strength check payload.
+// Email and username are optional and lower the score when the password contains them.
+type PasswordStrengthRequest struct {
+	Password string `json:"password" binding:"required"`
+	Email    string `json:"email"`
+	Username string `json:"username"`
+}
+
+// PasswordStrengthResponse represents the estimated strength of a password
+type PasswordStrengthResponse struct {
+	Score       int      `json:"score"`
+	MinScore    int      `json:"min_score"`
+	Acceptable  bool     `json:"acceptable"`
+	Warning     string   `json:"warning,omitempty"`
+	Suggestions []string `json:"suggestions,omitempty"`
+}
+
+// AvailabilityRequest represents the query of the signup availability check.
+// At least one of Username and Email must be set.
+type AvailabilityRequest struct {
+	Username string `form:"username" binding:"omitempty,min=3,max=50"`
+	Email    string `form:"email" binding:"omitempty,email"`
+	// OrganizationSlug selects the organization, as in RegisterRequest
+	OrganizationSlug string `form:"organization" binding:"omitempty,max=64"`
+}
+
+// AvailabilityResponse reports whether the requested username and email are
+// free; fields that were not asked about are omitted
+type AvailabilityResponse struct {
+	UsernameAvailable *bool `json:"username_available,omitempty"`
+	EmailAvailable    *bool `json:"email_available,omitempty"`
+}
+
+// IntrospectionRequest represents an RFC 7662 token introspection request,
+// sent as application/x-www-form-urlencoded
+type IntrospectionRequest struct {
+	Token         string `form:"token" binding:"required"`
+	TokenTypeHint string `form:"token_type_hint"`
+}
+
+// IntrospectionResponse represents an RFC 7662 token introspection response.
+// Inactive tokens carry only {"active": false}.
+type IntrospectionResponse struct {
+	Active   bool   `json:"active"`
+	Sub      string `json:"sub,omitempty"`
+	Username string `json:"username,omitempty"`
+	Email    string `json:"email,omitempty"`
+	Scope    string `json:"scope,omitempty"` // Space-separated, as in RFC 7662
+	Exp      int64  `json:"exp,omitempty"`
+	Iat      int64  `json:"iat,omitempty"`
+}
+
+// SessionInfo describes one signed-in device of the user
+type SessionInfo struct {
+	ID         string     `json:"id"`
+	UserAgent  string     `json:"user_agent"`
+	IPAddress  string     `json:"ip_address"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+	ExpiresAt  time.Time  `json:"expires_at"`
+	Current    bool       `json:"current"`
+	// Revoked is only set in the admin view with include_revoked
+	Revoked bool `json:"revoked,omitempty"`
+}
+
+// SessionListResponse lists the user's active sessions
+type SessionListResponse struct {
+	Sessions []SessionInfo `json:"sessions"`
+}
+
+// LinkedAccountInfo describes a social login provider linked to the user.
+// The provider's user ID and tokens are not exposed.
+type LinkedAccountInfo struct {
+	Provider string    `json:"provider"`
+	LinkedAt time.Time `json:"linked_at"`
+}
+
+// LinkedAccountListResponse lists the user's linked social login providers
+type LinkedAccountListResponse struct {
+	LinkedAccounts []LinkedAccountInfo `json:"linked_accounts"`
+}
+
+// UserDataExport is everything the service stores about a user, for data
+// portability requests. Password hashes and token values are never included.
+type UserDataExport struct {
+	ExportedAt time.Time       `json:"exported_at"`
+	Profile    *UserInfo       `json:"profile"`
+	Sessions   []SessionInfo   `json:"sessions"`
+	AuditLogs  []*AuditLogInfo `json:"audit_logs"`
+}
+
+// SessionStatusResponse confirms the access token is still accepted. It is
+// built from the token's claims only.
+type SessionStatusResponse struct {
+	UserID    string    `json:"user_id"`
+	SessionID string    `json:"session_id,omitempty"`
+	ExpiresAt time.Time `json:"expires_at"`
+	// ExpiresIn is the access token's remaining lifetime in seconds, so the
+	// client can schedule a refresh
+	ExpiresIn int64 `json:"expires_in"`
+}
+
+// PasskeyOptionsResponse starts a passkey ceremony. Options are passed to
+// navigator.credentials.create() or .get() as is; SessionID is sent back with
+// the result.
+type PasskeyOptionsResponse struct {
+	SessionID string      `json:"session_id"`
+	Options   interface{} `json:"options"`
+}
+
+// PasskeyFinishRequest completes a passkey ceremony with the credential
+// returned by the browser (PublicKeyCredential serialized to JSON)
+type PasskeyFinishRequest struct {
+	SessionID  string          `json:"session_id" binding:"required"`
+	Credential json.RawMessage `json:"credential" binding:"required" swaggertype:"object"`
 }
 
 // ErrorResponse represents an error response
@@ -46,6 +295,18 @@ type ErrorResponse struct {
 	Details map[string]string `json:"details,omitempty"`
 }
 
+// ErrorCatalogEntry describes one error code clients may see in ErrorResponse.Error
+type ErrorCatalogEntry struct {
+	Code    string `json:"code"`
+	Status  int    `json:"status"`
+	Message string `json:"message"`
+}
+
+// ErrorCatalogResponse lists every error code the API can return
+type ErrorCatalogResponse struct {
+	Errors []ErrorCatalogEntry `json:"errors"`
+}
+
 // SuccessResponse represents a success response
 type SuccessResponse struct {
 	Message string      `json:"message"`
@@ -0,0 +1,85 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"auth-service/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+// DeactivateAccount - Kullanıcı kendi hesabını devre dışı bırakır
+// Hesap silinmez (IsActive=false): login ErrUserInactive döner, tüm oturumlar kapatılır.
+// Yanlışlıkla/başkası tarafından yapılmasın diye mevcut şifre istenir.
+func (uc *AuthUseCase) DeactivateAccount(ctx context.Context, userID uuid.UUID, password string) (err error) {
+	defer translateContextError(ctx, &err)
+
+	// ADIM 1: Kullanıcıyı bul ve şifreyi doğrula
+	user, err := uc.confirmPassword(ctx, userID, password)
+	if err != nil {
+		return err
+	}
+
+	// ADIM 2: Hesabı pasif yap
+	user.IsActive = false
+	if err := uc.userRepo.Update(ctx, user); err != nil {
+		return err
+	}
+	uc.logAudit(ctx, AuditDeactivated, user.ID, nil)
+	uc.publishUserEvent(ctx, EventUserDeactivated, user, nil)
+
+	// ADIM 3: Tüm oturumları ve mevcut access token'ı iptal et
+	return uc.signOutEverywhere(ctx, user.ID)
+}
+
+// DeleteAccount - Kullanıcı kendi hesabını siler (soft delete)
+// Satır audit geçmişi için silinmez; DeletedAt set edilir, email/username anonimleştirilir ve telefon
+// silinir, böylece hepsi yeni bir kayıt için tekrar kullanılabilir. Silinen kullanıcı bir daha
+// bulunamaz: login ve refresh ErrUserNotFound / ErrInvalidCredentials döner.
+func (uc *AuthUseCase) DeleteAccount(ctx context.Context, userID uuid.UUID, password string) (err error) {
+	defer translateContextError(ctx, &err)
+
+	// ADIM 1: Kullanıcıyı bul ve şifreyi doğrula
+	user, err := uc.confirmPassword(ctx, userID, password)
+	if err != nil {
+		return err
+	}
+
+	// ADIM 2: Kişisel bilgileri anonimleştir ve silindi olarak işaretle
+	// ID'den türetildiği için anonim email/username benzersizdir
+	now := time.Now()
+	user.Email = "deleted-" + user.ID.String() + "@deleted.invalid"
+	user.Username = "deleted-" + user.ID.String()
+	user.FirstName, user.LastName, user.Phone = "", "", ""
+	user.IsActive = false
+	user.DeletedAt = &now
+	if err := uc.userRepo.Update(ctx, user); err != nil {
+		return err
+	}
+	uc.logAudit(ctx, AuditDeleted, user.ID, nil)
+	uc.publishUserEvent(ctx, EventUserDeleted, user, nil)
+
+	// ADIM 3: Tüm oturumları ve mevcut access token'ı iptal et
+	return uc.signOutEverywhere(ctx, user.ID)
+}
+
+// confirmPassword - Hassas hesap işlemlerinden önce kullanıcının şifresini doğrular
+func (uc *AuthUseCase) confirmPassword(ctx context.Context, userID uuid.UUID, password string) (*domain.User, error) {
+	user, err := uc.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, notFoundAs(err, ErrUserNotFound)
+	}
+	if !uc.passwordHasher.Compare(user.PasswordHash, password) {
+		return nil, ErrInvalidCredentials
+	}
+	return user, nil
+}
+
+// signOutEverywhere - Tüm refresh token'ları ve isteği yapan access token'ı iptal eder
+func (uc *AuthUseCase) signOutEverywhere(ctx context.Context, userID uuid.UUID) error {
+	if err := uc.refreshTokenRepo.RevokeAllByUserID(ctx, userID); err != nil {
+		return err
+	}
+	return uc.blacklistCurrentToken(ctx)
+}
@@ -0,0 +1,94 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"auth-service/internal/application/dto"
+	"auth-service/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+func TestDeactivateAccount(t *testing.T) {
+	uc, deps := newTestUseCase(t)
+	user := seedUser(t, uc, deps, &domain.User{Email: "jane@example.com", Username: "jane", IsVerified: true}, "correct-horse")
+	session := loginTokens(t, uc)
+
+	if err := uc.DeactivateAccount(context.Background(), user.ID, "wrong"); err != ErrInvalidCredentials {
+		t.Fatalf("wrong password: err = %v", err)
+	}
+
+	ctx := ContextWithAccessToken(context.Background(), "access-jti", time.Now().Add(time.Minute))
+	if err := uc.DeactivateAccount(ctx, user.ID, "correct-horse"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := uc.Login(context.Background(), &dto.LoginRequest{EmailOrUsername: "jane", Password: "correct-horse"}); err != ErrUserInactive {
+		t.Errorf("login after deactivation: err = %v, want ErrUserInactive", err)
+	}
+	if _, err := uc.RefreshToken(context.Background(), session.RefreshToken); err == nil {
+		t.Error("refresh token still valid after deactivation")
+	}
+	if blacklisted, _ := deps.blacklist.Contains(context.Background(), "access-jti"); !blacklisted {
+		t.Error("access token of the request was not blacklisted")
+	}
+}
+
+func TestDeleteAccount(t *testing.T) {
+	uc, deps := newTestUseCase(t)
+	user := seedUser(t, uc, deps, &domain.User{Email: "jane@example.com", Username: "jane", FirstName: "Jane", IsVerified: true}, "correct-horse")
+	session := loginTokens(t, uc)
+
+	if err := uc.DeleteAccount(context.Background(), user.ID, "correct-horse"); err != nil {
+		t.Fatal(err)
+	}
+
+	// The row is kept, anonymized
+	deps.users.mu.Lock()
+	stored := *deps.users.users[user.ID]
+	deps.users.mu.Unlock()
+	if !stored.IsDeleted() || stored.Email == "jane@example.com" || stored.Username == "jane" || stored.FirstName != "" {
+		t.Errorf("stored user = %+v", stored)
+	}
+
+	// The user is gone for every lookup
+	if _, err := uc.GetProfile(context.Background(), user.ID); err != ErrUserNotFound {
+		t.Errorf("GetProfile err = %v", err)
+	}
+	if _, err := uc.RefreshToken(context.Background(), session.RefreshToken); err == nil {
+		t.Error("refresh token still valid after deletion")
+	}
+
+	// Email and username are free again
+	if _, err := uc.Register(context.Background(), &dto.RegisterRequest{
+		Email: "jane@example.com", Username: "jane", Password: "Another-Str0ng-Passphrase", FirstName: "Jane", LastName: "Doe",
+	}); err != nil {
+		t.Errorf("re-register: %v", err)
+	}
+}
+
+func TestLoginRejectsDeletedUser(t *testing.T) {
+	uc, deps := newTestUseCase(t)
+	// A repository that still returns the deleted row
+	deletedAt := time.Now()
+	user := seedUser(t, uc, deps, &domain.User{Email: "jane@example.com", Username: "jane", IsVerified: true}, "correct-horse")
+	user.DeletedAt = &deletedAt
+	repo := &deletedUserRepo{fakeUserRepo: deps.users, user: user}
+	uc.userRepo = repo
+
+	if _, err := uc.Login(context.Background(), &dto.LoginRequest{EmailOrUsername: "jane", Password: "correct-horse"}); err != ErrUserNotFound {
+		t.Errorf("err = %v, want ErrUserNotFound", err)
+	}
+}
+
+// deletedUserRepo returns user for every login lookup, even though it is deleted
+type deletedUserRepo struct {
+	*fakeUserRepo
+	user *domain.User
+}
+
+func (r *deletedUserRepo) GetByEmailOrUsername(ctx context.Context, orgID uuid.UUID, identifier string) (*domain.User, error) {
+	return r.user, nil
+}
@@ -0,0 +1,82 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+
+	"auth-service/pkg/security"
+
+	"github.com/google/uuid"
+)
+
+// temporaryPasswordBytes - Geçici şifrenin rastgele byte sayısı (base64url ile 16 karakter)
+const temporaryPasswordBytes = 12
+
+// errPasswordHasherNotConfigured - WithPasswordHasher verilmeden geçici şifre verilmeye çalışıldı
+var errPasswordHasherNotConfigured = errors.New("password hasher is not configured")
+
+// WithPasswordHasher - AdminResetPassword'ün geçici şifreleri hash'lemesi için
+// AuthUseCase'inkiyle aynı hasher olmalı ki kullanıcı geçici şifreyle giriş yapabilsin.
+func WithPasswordHasher(hasher security.PasswordHasher) AdminUseCaseOption {
+	return func(uc *AdminUseCase) {
+		uc.passwordHasher = hasher
+	}
+}
+
+// AdminResetPassword - Kullanıcıya rastgele bir geçici şifre verir (destek ekibi için)
+// Kullanıcının tüm oturumları kapanır; geçici şifreyle giriş yapabilir ama
+// MustChangePassword işareti yüzünden şifresini değiştirene kadar başka bir şey yapamaz.
+// Geçici şifre kullanıcıya mail'le gönderilir ve admin'e de döner (mail gelmezse iletebilsin diye);
+// bu yüzden hedef mutlaka admin'in organizasyonunda olmalı, yoksa hesap ele geçirilebilirdi.
+func (uc *AdminUseCase) AdminResetPassword(ctx context.Context, actorID, userID uuid.UUID) (string, error) {
+	if uc.passwordHasher == nil {
+		return "", errPasswordHasherNotConfigured
+	}
+	if uc.refreshTokens == nil {
+		return "", errSessionRevocationNotConfigured
+	}
+
+	// ADIM 1: Kullanıcıyı bul (başka organizasyonun kullanıcısı bulunamaz)
+	user, err := uc.orgUser(ctx, actorID, userID)
+	if err != nil {
+		return "", err
+	}
+
+	// ADIM 2: Geçici şifreyi üret ve hash'le
+	password, err := security.GenerateOpaqueToken(temporaryPasswordBytes)
+	if err != nil {
+		return "", err
+	}
+	passwordHash, err := uc.passwordHasher.Hash(password)
+	if err != nil {
+		return "", err
+	}
+
+	// ADIM 3: Şifreyi değiştir, değiştirme zorunluluğunu işaretle
+	// Kilitli hesap da açılır: kullanıcı geçici şifreyle hemen giriş yapabilmeli
+	user.PasswordHash = passwordHash
+	user.MustChangePassword = true
+	user.PasswordUnset = false
+	user.FailedLoginAttempts = 0
+	user.LockedUntil = nil
+	if err := uc.userRepo.Update(ctx, user); err != nil {
+		return "", err
+	}
+
+	// ADIM 4: Oturumları kapat (eski şifreyle açılmış oturumlar da dahil)
+	if err := uc.revokeSessions(ctx, user.ID); err != nil {
+		return "", err
+	}
+
+	// ADIM 5: Kararı kaydet ve bildir
+	uc.audit.Log(ctx, AuditEvent{Action: "user.password_reset", ActorID: actorID, TargetID: user.ID})
+	uc.publish(ctx, "user.password_reset", actorID, user)
+
+	// Bildirim hatası sıfırlamayı geri almaz; şifre zaten admin'e dönüyor
+	_ = uc.mailer.Send(ctx, user.Email, MailTemplateTemporaryPassword, map[string]any{
+		"Username": user.Username,
+		"Password": password,
+	})
+
+	return password, nil
+}
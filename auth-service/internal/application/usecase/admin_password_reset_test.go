@@ -0,0 +1,111 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"auth-service/internal/application/dto"
+	"auth-service/internal/domain"
+	"auth-service/pkg/security"
+
+	"github.com/google/uuid"
+)
+
+func TestAdminResetPasswordForcesChangeOnNextLogin(t *testing.T) {
+	uc, deps := newTestUseCase(t)
+	audit := &fakeAuditLogger{}
+	admin := NewAdminUseCase(deps.users, deps.mailer, nil, audit, nil,
+		WithSessionRevocation(deps.refreshTokens, deps.blacklist, 15*time.Minute),
+		WithPasswordHasher(uc.passwordHasher))
+	user := seedUser(t, uc, deps, &domain.User{Email: "jane@example.com", Username: "jane", IsVerified: true}, "correct-horse")
+
+	before, err := uc.Login(context.Background(), &dto.LoginRequest{EmailOrUsername: "jane", Password: "correct-horse"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	temporary, err := admin.AdminResetPassword(context.Background(), seedAdmin(t, deps.users, uuid.Nil), user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if temporary == "" {
+		t.Fatal("no temporary password returned")
+	}
+	if mail, ok := deps.mailer.Last(); !ok || mail.Template != MailTemplateTemporaryPassword || mail.Data["Password"] != temporary {
+		t.Errorf("last email = %+v, want the temporary password", mail)
+	}
+	if len(audit.events) != 1 || audit.events[0].Action != "user.password_reset" {
+		t.Errorf("audit events = %+v", audit.events)
+	}
+
+	// Sessions opened before the reset are gone, and so is the old password
+	if _, err := uc.RefreshToken(context.Background(), before.RefreshToken); err == nil {
+		t.Error("session from before the reset was refreshed")
+	}
+	if _, err := uc.Login(context.Background(), &dto.LoginRequest{EmailOrUsername: "jane", Password: "correct-horse"}); err != ErrInvalidCredentials {
+		t.Errorf("login with the old password: err = %v, want ErrInvalidCredentials", err)
+	}
+
+	// The temporary password signs in, flagged in the response and the token
+	resp, err := uc.Login(context.Background(), &dto.LoginRequest{EmailOrUsername: "jane", Password: temporary})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !resp.MustChangePassword {
+		t.Error("login with the temporary password is not flagged must_change_password")
+	}
+	claims, err := uc.jwtService.ValidateToken(resp.AccessToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !claims.PasswordChangeRequired {
+		t.Error("access token of the temporary password login lacks pwd_chg")
+	}
+
+	// Changing it clears the flag; the refreshed session gets an unflagged token
+	ctx := ContextWithSessionID(context.Background(), uuid.MustParse(claims.SessionID))
+	if err := uc.ChangePassword(ctx, user.ID, temporary, "battery-staple"); err != nil {
+		t.Fatal(err)
+	}
+	refreshed, err := uc.RefreshToken(context.Background(), resp.RefreshToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if refreshed.MustChangePassword {
+		t.Error("refresh after the password change is still flagged")
+	}
+	if claims, err := uc.jwtService.ValidateToken(refreshed.AccessToken); err != nil || claims.PasswordChangeRequired {
+		t.Errorf("access token claims = %+v, err = %v, want no pwd_chg", claims, err)
+	}
+}
+
+func TestAdminResetPasswordUnknownUser(t *testing.T) {
+	_, deps := newTestUseCase(t)
+	admin := NewAdminUseCase(deps.users, nil, nil, nil, nil,
+		WithSessionRevocation(deps.refreshTokens, deps.blacklist, 15*time.Minute),
+		WithPasswordHasher(security.NewBcryptHasher(4)))
+
+	if _, err := admin.AdminResetPassword(context.Background(), seedAdmin(t, deps.users, uuid.Nil), uuid.New()); err != ErrUserNotFound {
+		t.Errorf("err = %v, want ErrUserNotFound", err)
+	}
+}
+
+func TestAdminResetPasswordOtherOrganization(t *testing.T) {
+	uc, deps := newTestUseCase(t)
+	admin := NewAdminUseCase(deps.users, deps.mailer, nil, nil, nil,
+		WithSessionRevocation(deps.refreshTokens, deps.blacklist, 15*time.Minute),
+		WithPasswordHasher(uc.passwordHasher))
+	user := seedUser(t, uc, deps, &domain.User{Email: "jane@example.com", Username: "jane", IsVerified: true}, "correct-horse")
+
+	// An admin of another tenant gets no temporary password to sign in with
+	if _, err := admin.AdminResetPassword(context.Background(), seedAdmin(t, deps.users, uuid.New()), user.ID); err != ErrUserNotFound {
+		t.Fatalf("err = %v, want ErrUserNotFound", err)
+	}
+	if _, err := uc.Login(context.Background(), &dto.LoginRequest{EmailOrUsername: "jane", Password: "correct-horse"}); err != nil {
+		t.Errorf("password changed by another tenant's admin: %v", err)
+	}
+	if _, ok := deps.mailer.Last(); ok {
+		t.Error("temporary password was mailed")
+	}
+}
@@ -0,0 +1,422 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"auth-service/internal/application/dto"
+	"auth-service/internal/domain"
+	"auth-service/pkg/security"
+
+	"github.com/google/uuid"
+)
+
+// AdminUseCase - Admin işlemleri (hesap onayı vs.)
+// AuthUseCase'ten ayrı tutulur: kullanıcının kendi hesabı üzerindeki işlemler ile
+// başka kullanıcılar üzerindeki yetkili işlemler farklı sorumluluklardır.
+type AdminUseCase struct {
+	// userRepo - Kullanıcı veritabanı işlemleri
+	userRepo domain.UserRepository
+
+	// mailer - Kullanıcıya bildirim (örn: hesabınız onaylandı)
+	mailer Mailer
+
+	// events - Dış sistemlere olay bildirimi (webhook)
+	events EventPublisher
+
+	// audit - Admin kararlarının kaydı (kim, ne zaman, kime)
+	audit AuditLogger
+
+	// auditLogs - Kayıtlı audit log'ları okumak için; nil ise liste hep boş döner
+	auditLogs domain.AuditLogRepository
+
+	// refreshTokens - Banlanan kullanıcının oturumlarını kapatmak için (WithSessionRevocation)
+	refreshTokens domain.RefreshTokenRepository
+
+	// tokenBlacklist - Banlanan kullanıcının hâlâ geçerli access token'ları (sid), varsayılan no-op
+	tokenBlacklist domain.TokenBlacklist
+
+	// accessTokenTTL - Bir oturumun access token'larının blacklist'te tutulacağı süre
+	accessTokenTTL time.Duration
+
+	// passwordHasher - Admin'in verdiği geçici şifreleri hash'lemek için (WithPasswordHasher)
+	passwordHasher security.PasswordHasher
+
+	// invites - Kayıt davetleri ve geçerlilik süreleri (WithInviteIssuing)
+	invites   domain.InviteRepository
+	inviteTTL time.Duration
+}
+
+// AdminUseCaseOption - NewAdminUseCase'e opsiyonel bağımlılık vermek için (AuthUseCaseOption gibi)
+type AdminUseCaseOption func(*AdminUseCase)
+
+// NewAdminUseCase - AdminUseCase oluşturan constructor
+// Bağımlılıklardan nil verilenler no-op implementasyonla değiştirilir.
+func NewAdminUseCase(
+	userRepo domain.UserRepository, // Kullanıcı repository interface'i
+	mailer Mailer, // Kullanıcı bildirimleri
+	events EventPublisher, // Webhook olayları
+	audit AuditLogger, // Audit log
+	auditLogs domain.AuditLogRepository, // Audit log sorgulama
+	opts ...AdminUseCaseOption,
+) *AdminUseCase {
+	uc := &AdminUseCase{
+		userRepo:       userRepo,
+		mailer:         mailer,
+		events:         events,
+		audit:          audit,
+		auditLogs:      auditLogs,
+		tokenBlacklist: nopTokenBlacklist{},
+	}
+	for _, opt := range opts {
+		opt(uc)
+	}
+	if uc.mailer == nil {
+		uc.mailer = nopMailer{}
+	}
+	if uc.events == nil {
+		uc.events = nopEventPublisher{}
+	}
+	if uc.audit == nil {
+		uc.audit = nopAuditLogger{}
+	}
+	return uc
+}
+
+// ApproveUser - Onay bekleyen hesabı aktif hale getirir ve kullanıcıya bildirir
+// actorID = işlemi yapan admin (audit için)
+func (uc *AdminUseCase) ApproveUser(ctx context.Context, actorID, userID uuid.UUID) error {
+	// ADIM 1: Kullanıcıyı bul, onay bekliyor olmalı
+	user, err := uc.pendingUser(ctx, actorID, userID)
+	if err != nil {
+		return err
+	}
+
+	// ADIM 2: Aktif duruma geçir
+	user.Status = domain.UserStatusActive
+	if err := uc.userRepo.Update(ctx, user); err != nil {
+		return err
+	}
+
+	// ADIM 3: Kararı kaydet ve bildir
+	uc.audit.Log(ctx, AuditEvent{Action: "user.approved", ActorID: actorID, TargetID: user.ID})
+	uc.publish(ctx, "user.approved", actorID, user)
+
+	// Bildirim hatası onayı geri almaz
+	_ = uc.mailer.Send(ctx, user.Email, MailTemplateAccountApproved, map[string]any{
+		"Username": user.Username,
+	})
+
+	return nil
+}
+
+// RejectUser - Onay bekleyen hesabı reddeder; reddedilen hesap silinir
+func (uc *AdminUseCase) RejectUser(ctx context.Context, actorID, userID uuid.UUID) error {
+	// ADIM 1: Kullanıcıyı bul, onay bekliyor olmalı
+	user, err := uc.pendingUser(ctx, actorID, userID)
+	if err != nil {
+		return err
+	}
+
+	// ADIM 2: Hesabı sil
+	if err := uc.userRepo.Delete(ctx, user.ID); err != nil {
+		return err
+	}
+
+	// ADIM 3: Kararı kaydet (kullanıcı silindiği için email audit kaydında tutulur)
+	uc.audit.Log(ctx, AuditEvent{
+		Action:   "user.rejected",
+		ActorID:  actorID,
+		TargetID: user.ID,
+		Details:  map[string]string{"email": user.Email},
+	})
+	uc.publish(ctx, "user.rejected", actorID, user)
+
+	return nil
+}
+
+// BulkVerifyEmails - Güvenilir bir sistemden import edilen kullanıcıların email'lerini toplu doğrular
+// identifiers: user ID veya email adresleri (karışık olabilir)
+// Her giriş için ayrı sonuç döner; doğrulanacak kullanıcılar tek seferde (atomik) güncellenir.
+func (uc *AdminUseCase) BulkVerifyEmails(ctx context.Context, actorID uuid.UUID, identifiers []string) (*dto.BulkVerifyResponse, error) {
+	resp := &dto.BulkVerifyResponse{Results: make([]dto.BulkVerifyResult, len(identifiers))}
+
+	// ADIM 1: Her girişi kullanıcıya çözümle
+	// Aynı kullanıcı birden fazla kez (ID ve email ile) gelebilir, sadece bir kez güncellenir
+	// Email'ler organizasyon içinde benzersiz: admin'in kendi organizasyonunda aranır
+	orgID, err := uc.actorOrganization(ctx, actorID)
+	if err != nil {
+		return nil, err
+	}
+	var toVerify []*domain.User
+	seen := make(map[uuid.UUID]bool)
+	for i, identifier := range identifiers {
+		result := &resp.Results[i]
+		result.User = identifier
+
+		user := uc.lookupUser(ctx, orgID, identifier)
+		if user == nil {
+			result.Status = dto.BulkVerifyStatusNotFound
+			continue
+		}
+		result.UserID = user.ID.String()
+
+		switch {
+		case seen[user.ID]:
+			result.Status = dto.BulkVerifyStatusVerified
+		case user.IsVerified:
+			result.Status = dto.BulkVerifyStatusAlreadyVerified
+		default:
+			result.Status = dto.BulkVerifyStatusVerified
+			seen[user.ID] = true
+			toVerify = append(toVerify, user)
+		}
+	}
+
+	// ADIM 2: Hepsini tek batch'te doğrula (hata olursa hiçbiri doğrulanmaz)
+	ids := make([]uuid.UUID, len(toVerify))
+	for i, user := range toVerify {
+		ids[i] = user.ID
+	}
+	if err := uc.userRepo.MarkVerified(ctx, ids); err != nil {
+		return nil, err
+	}
+
+	// ADIM 3: Her doğrulanan kullanıcı için audit kaydı
+	for _, user := range toVerify {
+		uc.audit.Log(ctx, AuditEvent{
+			Action:   "user.email_verified",
+			ActorID:  actorID,
+			TargetID: user.ID,
+			Details:  map[string]string{"email": user.Email, "source": "admin_bulk_verify"},
+		})
+	}
+	resp.Verified = len(toVerify)
+
+	return resp, nil
+}
+
+// ChangeRole - Kullanıcının rolünü değiştirir (örn: user -> admin)
+// Yeni rol kullanıcının bir sonraki access token'ında geçerli olur; mevcut token'lar
+// süreleri dolana kadar eski rolü taşır.
+func (uc *AdminUseCase) ChangeRole(ctx context.Context, actorID, userID uuid.UUID, role string) error {
+	// ADIM 1: Rol geçerli mi?
+	if !domain.IsValidRole(role) {
+		return ErrInvalidRole
+	}
+
+	// ADIM 2: Kullanıcıyı bul (başka organizasyonun kullanıcısı bulunamaz)
+	user, err := uc.orgUser(ctx, actorID, userID)
+	if err != nil {
+		return err
+	}
+	if user.Role == role {
+		return nil
+	}
+
+	// ADIM 3: Rolü güncelle
+	previous := user.Role
+	user.Role = role
+	if err := uc.userRepo.Update(ctx, user); err != nil {
+		return err
+	}
+
+	// ADIM 4: Yetki değişikliğini kaydet
+	uc.audit.Log(ctx, AuditEvent{
+		Action:   "user.role_changed",
+		ActorID:  actorID,
+		TargetID: user.ID,
+		Details:  map[string]string{"from": previous, "to": role},
+	})
+	uc.publish(ctx, "user.role_changed", actorID, user)
+
+	return nil
+}
+
+// Admin kullanıcı listesi sayfalama varsayılanları
+const (
+	defaultUserListPageSize = 20
+	maxUserListPageSize     = 100
+)
+
+// ListUsers - Kullanıcıları oluşturulma sırasına göre sayfa sayfa listeler
+// filter'daki boş alanlar filtre uygulamaz: rol, aktiflik, doğrulanma durumu ve
+// email/username içinde arama birlikte kullanılabilir
+// page 1'den başlar; 0 veya negatif page/pageSize varsayılan değerlere çekilir
+// Sadece admin'in organizasyonundaki kullanıcılar döner (filter.OrganizationID ezilir).
+func (uc *AdminUseCase) ListUsers(ctx context.Context, actorID uuid.UUID, filter domain.UserFilter) (*dto.UserListResponse, error) {
+	// ADIM 1: Admin'in organizasyonu - token claim'ine değil veritabanındaki kayda bakılır
+	orgID, err := uc.actorOrganization(ctx, actorID)
+	if err != nil {
+		return nil, err
+	}
+	filter.OrganizationID = orgID
+
+	// ADIM 2: Sayfalama parametrelerini normalize et
+	if filter.Page < 1 {
+		filter.Page = 1
+	}
+	if filter.PageSize < 1 {
+		filter.PageSize = defaultUserListPageSize
+	}
+	if filter.PageSize > maxUserListPageSize {
+		filter.PageSize = maxUserListPageSize
+	}
+	filter.Search = strings.TrimSpace(filter.Search)
+
+	// ADIM 3: İlgili sayfayı ve toplam kayıt sayısını getir
+	users, total, err := uc.userRepo.List(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	// ADIM 4: Sayfalı zarf (envelope) oluştur
+	// UserInfo'ya çevirmek PasswordHash gibi iç alanların yanıta sızmasını engeller
+	resp := &dto.UserListResponse{
+		Users:      make([]*dto.UserInfo, len(users)),
+		Page:       filter.Page,
+		PageSize:   filter.PageSize,
+		Total:      total,
+		TotalPages: int((total + int64(filter.PageSize) - 1) / int64(filter.PageSize)),
+	}
+	for i, user := range users {
+		resp.Users[i] = toUserInfo(user)
+	}
+	return resp, nil
+}
+
+// ListAuditLogs - Audit log kayıtlarını en yeniden eskiye sayfa sayfa listeler
+// filter.UserID verilirse kullanıcının yaptığı VEYA ondan etkilenen kayıtlar, filter.Action verilirse sadece o action
+// Sadece admin'in organizasyonundaki kullanıcıların kayıtları döner (filter.OrganizationID ezilir).
+func (uc *AdminUseCase) ListAuditLogs(ctx context.Context, actorID uuid.UUID, filter domain.AuditLogFilter, page, pageSize int) (*dto.AuditLogListResponse, error) {
+	// ADIM 1: Sayfalama parametrelerini normalize et (kullanıcı listesiyle aynı sınırlar)
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = defaultUserListPageSize
+	}
+	if pageSize > maxUserListPageSize {
+		pageSize = maxUserListPageSize
+	}
+	resp := &dto.AuditLogListResponse{
+		Logs:     []*dto.AuditLogInfo{},
+		Page:     page,
+		PageSize: pageSize,
+	}
+	if uc.auditLogs == nil {
+		return resp, nil
+	}
+
+	// ADIM 2: Admin'in organizasyonuyla sınırla
+	orgID, err := uc.actorOrganization(ctx, actorID)
+	if err != nil {
+		return nil, err
+	}
+	filter.OrganizationID = orgID
+
+	// ADIM 3: İlgili sayfayı ve toplam kayıt sayısını getir
+	logs, total, err := uc.auditLogs.List(ctx, filter, page, pageSize)
+	if err != nil {
+		return nil, err
+	}
+
+	// ADIM 4: Sayfalı zarf (envelope) oluştur
+	resp.Total = total
+	resp.TotalPages = int((total + int64(pageSize) - 1) / int64(pageSize))
+	for _, log := range logs {
+		resp.Logs = append(resp.Logs, toAuditLogInfo(log))
+	}
+	return resp, nil
+}
+
+// toAuditLogInfo - Audit log kaydını response DTO'suna çevirir
+func toAuditLogInfo(log *domain.AuditLog) *dto.AuditLogInfo {
+	return &dto.AuditLogInfo{
+		ID:        log.ID.String(),
+		Action:    log.Action,
+		ActorID:   uuidString(log.ActorID),
+		TargetID:  uuidString(log.TargetID),
+		IPAddress: log.IPAddress,
+		UserAgent: log.UserAgent,
+		RequestID: log.RequestID,
+		Details:   log.Details,
+		CreatedAt: log.CreatedAt,
+	}
+}
+
+// uuidString - uuid.Nil (bilinmeyen kullanıcı) boş string olur
+func uuidString(id uuid.UUID) string {
+	if id == uuid.Nil {
+		return ""
+	}
+	return id.String()
+}
+
+// lookupUser - Girişi UUID ise ID ile, değilse email ile orgID organizasyonunda arar (bulunamazsa nil)
+// ID ile bulunan kullanıcı da orgID organizasyonunda olmalı.
+func (uc *AdminUseCase) lookupUser(ctx context.Context, orgID uuid.UUID, identifier string) *domain.User {
+	var (
+		user *domain.User
+		err  error
+	)
+	if id, parseErr := uuid.Parse(identifier); parseErr == nil {
+		user, err = uc.userRepo.GetByID(ctx, id)
+	} else {
+		user, err = uc.userRepo.GetByEmail(ctx, orgID, identifier)
+	}
+	if err != nil || user.OrganizationID != orgID {
+		return nil
+	}
+	return user
+}
+
+// actorOrganization - İşlemi yapan admin'in organizasyonu (tek tenant'ta uuid.Nil)
+func (uc *AdminUseCase) actorOrganization(ctx context.Context, actorID uuid.UUID) (uuid.UUID, error) {
+	actor, err := uc.userRepo.GetByID(ctx, actorID)
+	if err != nil {
+		return uuid.Nil, notFoundAs(err, ErrUserNotFound)
+	}
+	return actor.OrganizationID, nil
+}
+
+// orgUser - Admin işleminin hedef kullanıcısını getirir
+// Admin sadece kendi organizasyonundaki kullanıcılar üzerinde işlem yapabilir; başka
+// organizasyonun kullanıcısı için de ErrUserNotFound döner ki varlığı sızmasın.
+func (uc *AdminUseCase) orgUser(ctx context.Context, actorID, userID uuid.UUID) (*domain.User, error) {
+	orgID, err := uc.actorOrganization(ctx, actorID)
+	if err != nil {
+		return nil, err
+	}
+	user, err := uc.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, notFoundAs(err, ErrUserNotFound)
+	}
+	if user.OrganizationID != orgID {
+		return nil, ErrUserNotFound
+	}
+	return user, nil
+}
+
+// pendingUser - Admin'in organizasyonundaki kullanıcıyı getirir ve onay beklediğini doğrular
+func (uc *AdminUseCase) pendingUser(ctx context.Context, actorID, userID uuid.UUID) (*domain.User, error) {
+	user, err := uc.orgUser(ctx, actorID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !user.IsPendingApproval() {
+		return nil, ErrNotPendingApproval
+	}
+	return user, nil
+}
+
+// publish - Admin kararını webhook'a gönderir (best-effort)
+func (uc *AdminUseCase) publish(ctx context.Context, event string, actorID uuid.UUID, user *domain.User) {
+	_ = uc.events.Publish(ctx, event, map[string]interface{}{
+		"user_id":  user.ID.String(),
+		"email":    user.Email,
+		"username": user.Username,
+		"actor_id": actorID.String(),
+	})
+}
@@ -0,0 +1,380 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"auth-service/internal/application/dto"
+	"auth-service/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+func registerPending(t *testing.T, uc *AuthUseCase) *dto.AuthResponse {
+	t.Helper()
+	resp, err := uc.Register(context.Background(), &dto.RegisterRequest{
+		Email:     "jane@example.com",
+		Username:  "jane",
+		Password:  "correct-horse",
+		FirstName: "Jane",
+		LastName:  "Doe",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp
+}
+
+// seedAdmin stores an admin of orgID and returns its ID, the actor of admin actions
+func seedAdmin(t *testing.T, users domain.UserRepository, orgID uuid.UUID) uuid.UUID {
+	t.Helper()
+	admin := &domain.User{Email: "admin-" + uuid.NewString() + "@example.com", Username: "admin-" + uuid.NewString()[:8],
+		OrganizationID: orgID, Role: domain.RoleAdmin, IsActive: true}
+	if err := users.Create(context.Background(), admin); err != nil {
+		t.Fatal(err)
+	}
+	return admin.ID
+}
+
+func TestRegisterWithApprovalRequired(t *testing.T) {
+	uc, deps := newTestUseCaseWithConfig(t, testSecurityConfig(), WithRegistrationApproval(true))
+
+	resp := registerPending(t, uc)
+	if !resp.ApprovalPending || resp.AccessToken != "" || resp.RefreshToken != "" {
+		t.Errorf("response = %+v, want pending without tokens", resp)
+	}
+
+	user, _ := deps.users.GetByEmail(context.Background(), uuid.Nil, "jane@example.com")
+	if user.Status != domain.UserStatusPendingApproval {
+		t.Errorf("status = %q", user.Status)
+	}
+	if got := deps.events.names(); !reflect.DeepEqual(got, []string{"user.registered", "user.pending_approval"}) {
+		t.Errorf("events = %v", got)
+	}
+
+	_, err := uc.Login(context.Background(), &dto.LoginRequest{EmailOrUsername: "jane", Password: "correct-horse"})
+	if err != ErrPendingApproval {
+		t.Errorf("login: got %v, want ErrPendingApproval", err)
+	}
+	_, err = uc.Login(context.Background(), &dto.LoginRequest{EmailOrUsername: "jane", Password: "wrong-password"})
+	if err != ErrInvalidCredentials {
+		t.Errorf("login with wrong password: got %v, want ErrInvalidCredentials", err)
+	}
+}
+
+func TestRegisterWithoutApprovalIsActive(t *testing.T) {
+	uc, deps := newTestUseCase(t)
+
+	resp := registerPending(t, uc)
+	if resp.ApprovalPending || resp.AccessToken == "" {
+		t.Errorf("response = %+v, want tokens", resp)
+	}
+	if got := deps.events.names(); !reflect.DeepEqual(got, []string{"user.registered"}) {
+		t.Errorf("events = %v", got)
+	}
+}
+
+func TestAdminApproveUser(t *testing.T) {
+	uc, deps := newTestUseCaseWithConfig(t, testSecurityConfig(), WithRegistrationApproval(true))
+	audit := &fakeAuditLogger{}
+	admin := NewAdminUseCase(deps.users, deps.mailer, deps.events, audit, nil)
+	registerPending(t, uc)
+	user, _ := deps.users.GetByEmail(context.Background(), uuid.Nil, "jane@example.com")
+	actor := seedAdmin(t, deps.users, uuid.Nil)
+
+	if err := admin.ApproveUser(context.Background(), actor, user.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := uc.Login(context.Background(), &dto.LoginRequest{EmailOrUsername: "jane", Password: "correct-horse"}); err != nil {
+		t.Errorf("login after approval: %v", err)
+	}
+	if len(audit.events) != 1 || audit.events[0].Action != "user.approved" || audit.events[0].ActorID != actor {
+		t.Errorf("audit = %+v", audit.events)
+	}
+	if mail, _ := deps.mailer.Last(); mail.Template != MailTemplateAccountApproved || mail.To != "jane@example.com" {
+		t.Errorf("last mail = %+v", mail)
+	}
+	if err := admin.ApproveUser(context.Background(), actor, user.ID); err != ErrNotPendingApproval {
+		t.Errorf("second approval: got %v, want ErrNotPendingApproval", err)
+	}
+}
+
+func TestAdminRejectUser(t *testing.T) {
+	uc, deps := newTestUseCaseWithConfig(t, testSecurityConfig(), WithRegistrationApproval(true))
+	audit := &fakeAuditLogger{}
+	admin := NewAdminUseCase(deps.users, nil, deps.events, audit, nil)
+	registerPending(t, uc)
+	user, _ := deps.users.GetByEmail(context.Background(), uuid.Nil, "jane@example.com")
+
+	if err := admin.RejectUser(context.Background(), seedAdmin(t, deps.users, uuid.Nil), user.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := deps.users.GetByID(context.Background(), user.ID); err == nil {
+		t.Error("rejected user still exists")
+	}
+	if len(audit.events) != 1 || audit.events[0].Action != "user.rejected" {
+		t.Errorf("audit = %+v", audit.events)
+	}
+	if got := deps.events.names(); !reflect.DeepEqual(got, []string{"user.registered", "user.pending_approval", "user.rejected"}) {
+		t.Errorf("events = %v", got)
+	}
+}
+
+func TestAdminDecisionOnUnknownOrActiveUser(t *testing.T) {
+	uc, deps := newTestUseCase(t)
+	admin := NewAdminUseCase(deps.users, nil, nil, nil, nil)
+	active := seedUser(t, uc, deps, &domain.User{Email: "jane@example.com", Username: "jane", Status: domain.UserStatusActive}, "correct-horse")
+	actor := seedAdmin(t, deps.users, uuid.Nil)
+
+	if err := admin.RejectUser(context.Background(), actor, active.ID); err != ErrNotPendingApproval {
+		t.Errorf("active user: got %v, want ErrNotPendingApproval", err)
+	}
+	if err := admin.ApproveUser(context.Background(), actor, uuid.New()); err != ErrUserNotFound {
+		t.Errorf("unknown user: got %v, want ErrUserNotFound", err)
+	}
+}
+
+func TestAdminBulkVerifyEmails(t *testing.T) {
+	uc, deps := newTestUseCase(t)
+	audit := &fakeAuditLogger{}
+	admin := NewAdminUseCase(deps.users, deps.mailer, deps.events, audit, nil)
+	unverified := seedUser(t, uc, deps, &domain.User{Email: "jane@example.com", Username: "jane"}, "correct-horse")
+	verified := seedUser(t, uc, deps, &domain.User{Email: "john@example.com", Username: "john", IsVerified: true}, "correct-horse")
+	byEmail := seedUser(t, uc, deps, &domain.User{Email: "ann@example.com", Username: "ann"}, "correct-horse")
+
+	actor := seedAdmin(t, deps.users, uuid.Nil)
+
+	resp, err := admin.BulkVerifyEmails(context.Background(), actor, []string{
+		unverified.ID.String(),
+		verified.ID.String(),
+		"ann@example.com",
+		"nobody@example.com",
+		uuid.NewString(),
+		// Same user again, by email
+		"jane@example.com",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		dto.BulkVerifyStatusVerified,
+		dto.BulkVerifyStatusAlreadyVerified,
+		dto.BulkVerifyStatusVerified,
+		dto.BulkVerifyStatusNotFound,
+		dto.BulkVerifyStatusNotFound,
+		dto.BulkVerifyStatusVerified,
+	}
+	for i, result := range resp.Results {
+		if result.Status != want[i] {
+			t.Errorf("result %d (%s) = %s, want %s", i, result.User, result.Status, want[i])
+		}
+	}
+	if resp.Verified != 2 {
+		t.Errorf("verified = %d, want 2", resp.Verified)
+	}
+	if resp.Results[2].UserID != byEmail.ID.String() {
+		t.Errorf("user_id = %q, want %s", resp.Results[2].UserID, byEmail.ID)
+	}
+
+	for _, id := range []uuid.UUID{unverified.ID, byEmail.ID} {
+		user, _ := deps.users.GetByID(context.Background(), id)
+		if !user.IsVerified {
+			t.Errorf("%s was not verified", user.Email)
+		}
+	}
+	if len(audit.events) != 2 || audit.events[0].Action != "user.email_verified" {
+		t.Errorf("audit events = %+v", audit.events)
+	}
+
+	// Without the admin's organization nothing is looked up or verified
+	dbDown := errors.New("database is down")
+	deps.users.lookupErr = dbDown
+	if _, err := admin.BulkVerifyEmails(context.Background(), actor, []string{"jane@example.com"}); !errors.Is(err, dbDown) {
+		t.Errorf("got %v, want %v", err, dbDown)
+	}
+}
+
+func TestAdminListUsersByRole(t *testing.T) {
+	uc, deps := newTestUseCase(t)
+	admin := NewAdminUseCase(deps.users, nil, nil, nil, nil)
+	start := time.Now().Add(-time.Hour)
+	var actor uuid.UUID
+	for i, u := range []struct{ name, role string }{
+		{"ann", "admin"}, {"bob", "user"}, {"cat", "admin"}, {"dan", "admin"},
+	} {
+		user := seedUser(t, uc, deps, &domain.User{
+			Email:     u.name + "@example.com",
+			Username:  u.name,
+			Role:      u.role,
+			CreatedAt: start.Add(time.Duration(i) * time.Minute),
+		}, "correct-horse")
+		if u.name == "ann" {
+			actor = user.ID
+		}
+	}
+
+	resp, err := admin.ListUsers(context.Background(), actor, domain.UserFilter{Role: "admin", Page: 2, PageSize: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Total != 3 || resp.TotalPages != 2 || resp.Page != 2 || resp.PageSize != 2 {
+		t.Errorf("envelope = %+v", resp)
+	}
+	if len(resp.Users) != 1 || resp.Users[0].Username != "dan" || resp.Users[0].Role != "admin" {
+		t.Errorf("users = %+v", resp.Users)
+	}
+
+	// No role lists everyone; out-of-range paging falls back to the defaults
+	resp, err = admin.ListUsers(context.Background(), actor, domain.UserFilter{PageSize: 1000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Total != 4 || len(resp.Users) != 4 || resp.Page != 1 || resp.PageSize != maxUserListPageSize {
+		t.Errorf("envelope = %+v", resp)
+	}
+}
+
+func TestAdminListUsersFilters(t *testing.T) {
+	uc, deps := newTestUseCase(t)
+	admin := NewAdminUseCase(deps.users, nil, nil, nil, nil)
+	orgA, orgB := uuid.New(), uuid.New()
+	start := time.Now().Add(-time.Hour)
+	actors := map[uuid.UUID]uuid.UUID{}
+	for i, u := range []struct {
+		name             string
+		org              uuid.UUID
+		active, verified bool
+	}{
+		{"ann.smith", orgA, true, true},
+		{"bob", orgA, false, true},
+		{"cat_smith", orgA, true, false},
+		{"dan", orgB, true, true},
+	} {
+		user := seedUser(t, uc, deps, &domain.User{
+			OrganizationID: u.org,
+			Email:          u.name + "@example.com",
+			Username:       u.name,
+			Role:           "user",
+			IsVerified:     u.verified,
+			CreatedAt:      start.Add(time.Duration(i) * time.Minute),
+		}, "correct-horse")
+		deps.users.users[user.ID].IsActive = u.active
+		if _, ok := actors[u.org]; !ok {
+			actors[u.org] = user.ID
+		}
+	}
+
+	yes, no := true, false
+	tests := []struct {
+		name   string
+		org    uuid.UUID // the admin's organization
+		filter domain.UserFilter
+		want   []string
+	}{
+		{"organization only", orgA, domain.UserFilter{}, []string{"ann.smith", "bob", "cat_smith"}},
+		{"active", orgA, domain.UserFilter{IsActive: &yes}, []string{"ann.smith", "cat_smith"}},
+		{"inactive", orgA, domain.UserFilter{IsActive: &no}, []string{"bob"}},
+		{"unverified", orgA, domain.UserFilter{IsVerified: &no}, []string{"cat_smith"}},
+		{"search is case-insensitive and trimmed", orgA, domain.UserFilter{Search: " SMITH "}, []string{"ann.smith", "cat_smith"}},
+		{"combined", orgA, domain.UserFilter{IsVerified: &yes, Search: "smith"}, []string{"ann.smith"}},
+		{"other organization", orgB, domain.UserFilter{Search: "smith"}, nil},
+		// The admin's own organization wins over the one in the filter
+		{"organization in the filter is ignored", orgB, domain.UserFilter{OrganizationID: orgA}, []string{"dan"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := admin.ListUsers(context.Background(), actors[tt.org], tt.filter)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, u := range resp.Users {
+				got = append(got, u.Username)
+			}
+			if resp.Total != int64(len(tt.want)) || strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("users = %v (total %d), want %v", got, resp.Total, tt.want)
+			}
+		})
+	}
+}
+
+func TestAdminChangeRole(t *testing.T) {
+	uc, deps := newTestUseCase(t)
+	audit := &fakeAuditLogger{}
+	admin := NewAdminUseCase(deps.users, nil, nil, audit, nil)
+	registered, err := uc.Register(context.Background(), &dto.RegisterRequest{
+		Email: "jane@example.com", Username: "jane", Password: "correct-horse", FirstName: "Jane", LastName: "Doe",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if registered.User.Role != domain.RoleUser {
+		t.Fatalf("registered role = %q, want %q", registered.User.Role, domain.RoleUser)
+	}
+	userID := uuid.MustParse(registered.User.ID)
+	actor := seedAdmin(t, deps.users, uuid.Nil)
+
+	if err := admin.ChangeRole(context.Background(), actor, userID, "superuser"); err != ErrInvalidRole {
+		t.Errorf("unknown role: got %v, want ErrInvalidRole", err)
+	}
+	if err := admin.ChangeRole(context.Background(), actor, uuid.New(), domain.RoleAdmin); err != ErrUserNotFound {
+		t.Errorf("unknown user: got %v, want ErrUserNotFound", err)
+	}
+	if err := admin.ChangeRole(context.Background(), actor, userID, domain.RoleAdmin); err != nil {
+		t.Fatal(err)
+	}
+
+	// The next token carries the new role
+	login, err := uc.Login(context.Background(), &dto.LoginRequest{EmailOrUsername: "jane", Password: "correct-horse"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	claims, err := uc.jwtService.ValidateToken(login.AccessToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claims.Role != domain.RoleAdmin || login.User.Role != domain.RoleAdmin {
+		t.Errorf("role claim = %q, user info role = %q, want admin", claims.Role, login.User.Role)
+	}
+	if len(audit.events) != 1 || audit.events[0].Action != "user.role_changed" || audit.events[0].Details["to"] != domain.RoleAdmin {
+		t.Errorf("audit = %+v", audit.events)
+	}
+}
+
+func TestAdminActionsStayInOrganization(t *testing.T) {
+	ctx := context.Background()
+	uc, deps := newTestUseCase(t)
+	admin := NewAdminUseCase(deps.users, nil, nil, nil, nil)
+	pending := seedUser(t, uc, deps, &domain.User{Email: "jane@example.com", Username: "jane", Role: domain.RoleUser, Status: domain.UserStatusPendingApproval}, "correct-horse")
+	// An admin of another tenant
+	actor := seedAdmin(t, deps.users, uuid.New())
+
+	if err := admin.ApproveUser(ctx, actor, pending.ID); err != ErrUserNotFound {
+		t.Errorf("approve: got %v, want ErrUserNotFound", err)
+	}
+	if err := admin.RejectUser(ctx, actor, pending.ID); err != ErrUserNotFound {
+		t.Errorf("reject: got %v, want ErrUserNotFound", err)
+	}
+	if err := admin.ChangeRole(ctx, actor, pending.ID, domain.RoleAdmin); err != ErrUserNotFound {
+		t.Errorf("change role: got %v, want ErrUserNotFound", err)
+	}
+	resp, err := admin.BulkVerifyEmails(ctx, actor, []string{pending.ID.String()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.Results[0].Status != dto.BulkVerifyStatusNotFound {
+		t.Errorf("bulk verify by ID: status = %q, want not found", resp.Results[0].Status)
+	}
+
+	stored, _ := deps.users.GetByID(ctx, pending.ID)
+	if stored.Role != domain.RoleUser || stored.Status != domain.UserStatusPendingApproval || stored.IsVerified {
+		t.Errorf("user changed by another tenant's admin: %+v", stored)
+	}
+}
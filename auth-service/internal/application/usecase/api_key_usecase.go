@@ -0,0 +1,162 @@
+package usecase
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"auth-service/internal/application/dto"
+	"auth-service/internal/domain"
+	"auth-service/pkg/security"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrInvalidAPIKey - API key bilinmiyor, iptal edilmiş veya süresi dolmuş
+	ErrInvalidAPIKey = newAppError(http.StatusUnauthorized, "invalid_api_key", "Invalid, revoked or expired API key")
+
+	// ErrAPIKeyNotFound - Admin işleminde verilen ID ile (aktif) API key yok
+	ErrAPIKeyNotFound = newAppError(http.StatusNotFound, "api_key_not_found", "API key not found")
+
+	// ErrInvalidScope - Bilinmeyen scope (bkz. domain.IsValidScope)
+	ErrInvalidScope = newAppError(http.StatusBadRequest, "invalid_scope", "Unknown scope")
+
+	// ErrInvalidExpiry - expires_in bir süre değil veya pozitif değil
+	ErrInvalidExpiry = newAppError(http.StatusBadRequest, "invalid_expiry", "expires_in must be a positive duration such as 720h")
+)
+
+// apiKeyPrefix - Tüm key'lerin başı; log'da veya kodda görülen bir key'in ne olduğu anlaşılsın
+const apiKeyPrefix = "ak_"
+
+// apiKeyDisplayLength - Listede key'leri ayırt etmek için saklanan baş kısmın uzunluğu
+const apiKeyDisplayLength = 11
+
+// APIKeyUseCase - Servisler arası kimlik doğrulama için API key'leri yönetir
+// Kullanıcıların JWT'sinden ayrıdır: key bir servise aittir ve sadece verilen scope'ları kullanabilir.
+type APIKeyUseCase struct {
+	// repo - API key'ler (sadece SHA-256 hash'leri saklanır)
+	repo domain.APIKeyRepository
+
+	// audit - Key oluşturma/iptal kayıtları
+	audit AuditLogger
+}
+
+// NewAPIKeyUseCase - APIKeyUseCase oluşturan constructor
+// audit nil ise no-op kullanılır.
+func NewAPIKeyUseCase(repo domain.APIKeyRepository, audit AuditLogger) *APIKeyUseCase {
+	if audit == nil {
+		audit = nopAuditLogger{}
+	}
+	return &APIKeyUseCase{repo: repo, audit: audit}
+}
+
+// CreateAPIKey - Admin'in organizasyonu (orgID) için yeni bir API key üretir
+// Düz key sadece bu cevapta döner; veritabanında SHA-256 hash'i tutulur ve bir daha gösterilemez.
+func (uc *APIKeyUseCase) CreateAPIKey(ctx context.Context, actorID, orgID uuid.UUID, req *dto.CreateAPIKeyRequest) (*dto.CreateAPIKeyResponse, error) {
+	// ADIM 1: Scope'ları ve süreyi doğrula
+	for _, scope := range req.Scopes {
+		if !domain.IsValidScope(scope) {
+			return nil, ErrInvalidScope
+		}
+	}
+	var expiresAt *time.Time
+	if req.ExpiresIn != "" {
+		ttl, err := time.ParseDuration(req.ExpiresIn)
+		if err != nil || ttl <= 0 {
+			return nil, ErrInvalidExpiry
+		}
+		at := time.Now().Add(ttl)
+		expiresAt = &at
+	}
+
+	// ADIM 2: Rastgele key üret (32 byte = 256 bit)
+	secret, err := security.GenerateOpaqueToken(32)
+	if err != nil {
+		return nil, err
+	}
+	plaintext := apiKeyPrefix + secret
+
+	// ADIM 3: Sadece hash'i kaydet
+	key := &domain.APIKey{
+		Name:           req.Name,
+		KeyHash:        security.HashToken(plaintext),
+		Prefix:         plaintext[:apiKeyDisplayLength],
+		Scopes:         req.Scopes,
+		OwnerID:        actorID,
+		OrganizationID: orgID,
+		ExpiresAt:      expiresAt,
+	}
+	if err := uc.repo.Create(ctx, key); err != nil {
+		return nil, err
+	}
+	uc.audit.Log(ctx, AuditEvent{
+		Action:  "api_key.created",
+		ActorID: actorID,
+		Details: map[string]string{"api_key_id": key.ID.String(), "name": key.Name},
+	})
+
+	return &dto.CreateAPIKeyResponse{APIKeyInfo: *toAPIKeyInfo(key), Key: plaintext}, nil
+}
+
+// ListAPIKeys - Organizasyonun tüm API key'lerini (iptal edilmişler dahil) listeler; key'lerin kendisi dönmez
+func (uc *APIKeyUseCase) ListAPIKeys(ctx context.Context, orgID uuid.UUID) (*dto.APIKeyListResponse, error) {
+	keys, err := uc.repo.List(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	resp := &dto.APIKeyListResponse{Keys: make([]*dto.APIKeyInfo, 0, len(keys))}
+	for _, key := range keys {
+		resp.Keys = append(resp.Keys, toAPIKeyInfo(key))
+	}
+	return resp, nil
+}
+
+// RevokeAPIKey - Admin'in organizasyonundaki (orgID) API key'i iptal eder; key ile gelen istekler hemen reddedilir
+func (uc *APIKeyUseCase) RevokeAPIKey(ctx context.Context, actorID, orgID, id uuid.UUID) error {
+	// ADIM 1: Bilinmeyen, başka organizasyonun veya zaten iptal edilmiş key: ErrAPIKeyNotFound
+	key, err := uc.repo.GetByID(ctx, id)
+	if err != nil {
+		return notFoundAs(err, ErrAPIKeyNotFound)
+	}
+	if key.OrganizationID != orgID || key.IsRevoked() {
+		return ErrAPIKeyNotFound
+	}
+
+	// ADIM 2: İptal et ve kaydet (arada başka bir istek iptal ettiyse de ErrAPIKeyNotFound)
+	if err := uc.repo.Revoke(ctx, id, time.Now()); err != nil {
+		return notFoundAs(err, ErrAPIKeyNotFound)
+	}
+	uc.audit.Log(ctx, AuditEvent{
+		Action:  "api_key.revoked",
+		ActorID: actorID,
+		Details: map[string]string{"api_key_id": id.String(), "name": key.Name},
+	})
+	return nil
+}
+
+// AuthenticateAPIKey - X-API-Key header'ındaki key'i doğrular
+// Bilinmeyen, iptal edilmiş ve süresi dolmuş key'ler için aynı hata döner.
+func (uc *APIKeyUseCase) AuthenticateAPIKey(ctx context.Context, plaintext string) (*domain.APIKey, error) {
+	key, err := uc.repo.GetByHash(ctx, security.HashToken(plaintext))
+	if err != nil {
+		return nil, notFoundAs(err, ErrInvalidAPIKey)
+	}
+	if key.IsRevoked() || key.IsExpired() {
+		return nil, ErrInvalidAPIKey
+	}
+	return key, nil
+}
+
+func toAPIKeyInfo(key *domain.APIKey) *dto.APIKeyInfo {
+	return &dto.APIKeyInfo{
+		ID:        key.ID.String(),
+		Name:      key.Name,
+		Prefix:    key.Prefix,
+		Scopes:    key.Scopes,
+		OwnerID:   uuidString(key.OwnerID),
+		ExpiresAt: key.ExpiresAt,
+		RevokedAt: key.RevokedAt,
+		CreatedAt: key.CreatedAt,
+	}
+}
@@ -0,0 +1,130 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"auth-service/internal/application/dto"
+	"auth-service/internal/domain"
+	"auth-service/pkg/security"
+
+	"github.com/google/uuid"
+)
+
+func newAPIKeyRequest() *dto.CreateAPIKeyRequest {
+	return &dto.CreateAPIKeyRequest{Name: "billing", Scopes: []string{domain.ScopeTokenIntrospect}}
+}
+
+func TestCreateAPIKeyStoresOnlyHash(t *testing.T) {
+	repo := &fakeAPIKeyRepo{}
+	audit := &fakeAuditLogger{}
+	uc := NewAPIKeyUseCase(repo, audit)
+
+	resp, err := uc.CreateAPIKey(context.Background(), uuid.New(), uuid.Nil, newAPIKeyRequest())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(resp.Key, "ak_") || !strings.HasPrefix(resp.Key, resp.Prefix) {
+		t.Errorf("key %q / prefix %q", resp.Key, resp.Prefix)
+	}
+
+	stored := repo.keys[0]
+	if stored.KeyHash != security.HashToken(resp.Key) || strings.Contains(stored.KeyHash, resp.Key) {
+		t.Error("repository should hold only the SHA-256 of the key")
+	}
+	if len(audit.events) != 1 || audit.events[0].Action != "api_key.created" {
+		t.Errorf("audit events = %+v", audit.events)
+	}
+
+	list, err := uc.ListAPIKeys(context.Background(), uuid.Nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list.Keys) != 1 || list.Keys[0].ID != resp.ID {
+		t.Errorf("list = %+v", list.Keys)
+	}
+
+	// Admins of another organization do not see the key
+	if list, err = uc.ListAPIKeys(context.Background(), uuid.New()); err != nil || len(list.Keys) != 0 {
+		t.Errorf("other organization: list = %+v, err = %v", list, err)
+	}
+}
+
+func TestCreateAPIKeyValidation(t *testing.T) {
+	uc := NewAPIKeyUseCase(&fakeAPIKeyRepo{}, nil)
+
+	req := newAPIKeyRequest()
+	req.Scopes = []string{"users:delete"}
+	if _, err := uc.CreateAPIKey(context.Background(), uuid.New(), uuid.Nil, req); err != ErrInvalidScope {
+		t.Errorf("unknown scope: err = %v, want ErrInvalidScope", err)
+	}
+
+	for _, expiresIn := range []string{"soon", "-1h", "0s"} {
+		req := newAPIKeyRequest()
+		req.ExpiresIn = expiresIn
+		if _, err := uc.CreateAPIKey(context.Background(), uuid.New(), uuid.Nil, req); err != ErrInvalidExpiry {
+			t.Errorf("expires_in %q: err = %v, want ErrInvalidExpiry", expiresIn, err)
+		}
+	}
+}
+
+func TestAuthenticateAPIKey(t *testing.T) {
+	repo := &fakeAPIKeyRepo{}
+	uc := NewAPIKeyUseCase(repo, nil)
+	resp, err := uc.CreateAPIKey(context.Background(), uuid.New(), uuid.Nil, newAPIKeyRequest())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key, err := uc.AuthenticateAPIKey(context.Background(), resp.Key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !key.HasScope(domain.ScopeTokenIntrospect) {
+		t.Errorf("scopes = %q", key.Scopes)
+	}
+
+	if _, err := uc.AuthenticateAPIKey(context.Background(), "ak_unknown"); err != ErrInvalidAPIKey {
+		t.Errorf("unknown key: err = %v, want ErrInvalidAPIKey", err)
+	}
+
+	past := time.Now().Add(-time.Minute)
+	repo.keys[0].ExpiresAt = &past
+	if _, err := uc.AuthenticateAPIKey(context.Background(), resp.Key); err != ErrInvalidAPIKey {
+		t.Errorf("expired key: err = %v, want ErrInvalidAPIKey", err)
+	}
+}
+
+func TestRevokeAPIKey(t *testing.T) {
+	audit := &fakeAuditLogger{}
+	uc := NewAPIKeyUseCase(&fakeAPIKeyRepo{}, audit)
+	resp, err := uc.CreateAPIKey(context.Background(), uuid.New(), uuid.Nil, newAPIKeyRequest())
+	if err != nil {
+		t.Fatal(err)
+	}
+	id := uuid.MustParse(resp.ID)
+
+	// Admins of another organization cannot revoke it
+	if err := uc.RevokeAPIKey(context.Background(), uuid.New(), uuid.New(), id); err != ErrAPIKeyNotFound {
+		t.Errorf("other organization: err = %v, want ErrAPIKeyNotFound", err)
+	}
+
+	if err := uc.RevokeAPIKey(context.Background(), uuid.New(), uuid.Nil, id); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := uc.AuthenticateAPIKey(context.Background(), resp.Key); err != ErrInvalidAPIKey {
+		t.Errorf("revoked key: err = %v, want ErrInvalidAPIKey", err)
+	}
+	if got := audit.events[len(audit.events)-1].Action; got != "api_key.revoked" {
+		t.Errorf("last audit action = %q", got)
+	}
+
+	if err := uc.RevokeAPIKey(context.Background(), uuid.New(), uuid.Nil, id); err != ErrAPIKeyNotFound {
+		t.Errorf("second revoke: err = %v, want ErrAPIKeyNotFound", err)
+	}
+	if err := uc.RevokeAPIKey(context.Background(), uuid.New(), uuid.Nil, uuid.New()); err != ErrAPIKeyNotFound {
+		t.Errorf("unknown key: err = %v, want ErrAPIKeyNotFound", err)
+	}
+}
@@ -0,0 +1,62 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Audit action'ları - AuthUseCase'in kaydettiği güvenlik olayları
+// (Admin işlemleri "user.approved" gibi kendi action'larını kullanır)
+const (
+	AuditLoginSuccess    = "login_success"
+	AuditLoginFailed     = "login_failed"
+	AuditPasswordChanged = "password_changed"
+	AuditUsernameChanged = "username_changed"
+	AuditTokenReused     = "token_reused"
+	AuditLogout          = "logout"
+	AuditDeactivated     = "account_deactivated"
+	AuditDeleted         = "account_deleted"
+	AuditSessionsEvicted = "sessions_evicted"
+	AuditTokenRevoked    = "access_token_revoked"
+
+	AuditPasskeyRegistered    = "passkey_registered"
+	AuditPasskeyCloneDetected = "passkey_clone_detected"
+
+	AuditOAuthUnlinked = "oauth_account_unlinked"
+)
+
+// WithAuditLogger - Güvenlik olaylarının (login, logout, şifre değişikliği...) yazılacağı audit logger
+// Verilmezse olaylar kaydedilmez.
+func WithAuditLogger(audit AuditLogger) AuthUseCaseOption {
+	return func(uc *AuthUseCase) {
+		uc.auditLogger = audit
+	}
+}
+
+// logAudit - Kullanıcının kendi hesabındaki bir olayı kaydeder
+// IP ve User-Agent context'ten (ContextWithClient) alınır; userID bilinmiyorsa uuid.Nil
+func (uc *AuthUseCase) logAudit(ctx context.Context, action string, userID uuid.UUID, details map[string]string) {
+	client := clientFromContext(ctx)
+	uc.auditLogger.Log(ctx, AuditEvent{
+		Action:    action,
+		ActorID:   userID,
+		TargetID:  userID,
+		IPAddress: client.ipAddress,
+		UserAgent: client.userAgent,
+		RequestID: RequestIDFromContext(ctx),
+		Time:      time.Now().UTC(),
+		Details:   details,
+	})
+}
+
+// loginFailed - Başarısız login'i metric'e ve audit log'a yazar
+// Metric'te sadece sebep var; audit log'da hangi hesap ve nereden olduğu da tutulur.
+func (uc *AuthUseCase) loginFailed(ctx context.Context, userID uuid.UUID, identifier string, reason LoginFailureReason) {
+	uc.loginMetrics.LoginFailed(reason)
+	uc.logAudit(ctx, AuditLoginFailed, userID, map[string]string{
+		"reason":     string(reason),
+		"identifier": identifier,
+	})
+}
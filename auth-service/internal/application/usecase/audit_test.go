@@ -0,0 +1,122 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"auth-service/internal/application/dto"
+	"auth-service/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+func TestAuthUseCaseWritesAuditEvents(t *testing.T) {
+	audit := &fakeAuditLogger{}
+	uc, deps := newTestUseCaseWithConfig(t, testSecurityConfig(), WithAuditLogger(audit))
+	user := seedUser(t, uc, deps, &domain.User{Email: "jane@example.com", Username: "jane", IsVerified: true}, "correct-horse")
+	ctx := ContextWithClient(context.Background(), "Firefox/130.0", "203.0.113.7")
+
+	// Failed logins: unknown account and wrong password
+	if _, err := uc.Login(ctx, &dto.LoginRequest{EmailOrUsername: "nobody", Password: "x"}); err != ErrInvalidCredentials {
+		t.Fatalf("unknown user: err = %v", err)
+	}
+	if _, err := uc.Login(ctx, &dto.LoginRequest{EmailOrUsername: "jane", Password: "wrong"}); err != ErrInvalidCredentials {
+		t.Fatalf("wrong password: err = %v", err)
+	}
+
+	first, err := uc.Login(ctx, &dto.LoginRequest{EmailOrUsername: "jane", Password: "correct-horse"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := uc.ChangePassword(ctx, user.ID, "correct-horse", "Another-Str0ng-Passphrase"); err != nil {
+		t.Fatal(err)
+	}
+
+	// ChangePassword revoked the first session; presenting it again is reuse
+	if _, err := uc.RefreshToken(ctx, first.RefreshToken); err != ErrTokenReuseDetected {
+		t.Fatalf("reuse: err = %v", err)
+	}
+	if err := uc.Logout(ctx, user.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []struct {
+		action string
+		userID uuid.UUID
+		detail string
+	}{
+		{AuditLoginFailed, uuid.Nil, "user_not_found"},
+		{AuditLoginFailed, user.ID, "bad_password"},
+		{AuditLoginSuccess, user.ID, "password"},
+		{AuditPasswordChanged, user.ID, "change"},
+		{AuditTokenReused, user.ID, ""},
+		{AuditLogout, user.ID, ""},
+	}
+	if len(audit.events) != len(want) {
+		t.Fatalf("events = %+v", audit.events)
+	}
+	for i, w := range want {
+		got := audit.events[i]
+		if got.Action != w.action || got.ActorID != w.userID || got.TargetID != w.userID {
+			t.Errorf("event %d = %+v, want %s for %s", i, got, w.action, w.userID)
+		}
+		if got.IPAddress != "203.0.113.7" || got.UserAgent != "Firefox/130.0" || got.Time.IsZero() {
+			t.Errorf("event %d is missing client metadata or time: %+v", i, got)
+		}
+		if w.detail != "" && got.Details["reason"] != w.detail && got.Details["method"] != w.detail {
+			t.Errorf("event %d details = %v, want %s", i, got.Details, w.detail)
+		}
+	}
+	if audit.events[0].Details["identifier"] != "nobody" {
+		t.Errorf("failed login details = %v", audit.events[0].Details)
+	}
+}
+
+type stubAuditLogRepo struct {
+	logs   []*domain.AuditLog
+	filter domain.AuditLogFilter
+}
+
+func (r *stubAuditLogRepo) Create(ctx context.Context, log *domain.AuditLog) error {
+	r.logs = append(r.logs, log)
+	return nil
+}
+
+func (r *stubAuditLogRepo) List(ctx context.Context, filter domain.AuditLogFilter, page, pageSize int) ([]*domain.AuditLog, int64, error) {
+	r.filter = filter
+	return r.logs, int64(len(r.logs)), nil
+}
+
+func TestListAuditLogs(t *testing.T) {
+	userID := uuid.New()
+	repo := &stubAuditLogRepo{logs: []*domain.AuditLog{
+		{ID: uuid.New(), Action: AuditLoginFailed, IPAddress: "203.0.113.7", Details: map[string]string{"identifier": "nobody"}},
+		{ID: uuid.New(), Action: AuditLoginFailed, ActorID: userID, TargetID: userID},
+	}}
+	users := newFakeUserRepo()
+	orgID := uuid.New()
+	actorID := seedAdmin(t, users, orgID)
+	admin := NewAdminUseCase(users, nil, nil, nil, repo)
+
+	// The organization always comes from the admin, never from the caller
+	filter := domain.AuditLogFilter{UserID: userID, Action: AuditLoginFailed, OrganizationID: uuid.New()}
+	resp, err := admin.ListAuditLogs(context.Background(), actorID, filter, 0, 500)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (domain.AuditLogFilter{UserID: userID, Action: AuditLoginFailed, OrganizationID: orgID}); repo.filter != want {
+		t.Errorf("filter = %+v, want %+v", repo.filter, want)
+	}
+	if resp.Page != 1 || resp.PageSize != maxUserListPageSize || resp.Total != 2 || resp.TotalPages != 1 {
+		t.Errorf("paging = %+v", resp)
+	}
+	if resp.Logs[0].ActorID != "" || resp.Logs[1].TargetID != userID.String() || resp.Logs[0].Details["identifier"] != "nobody" {
+		t.Errorf("logs = %+v, %+v", resp.Logs[0], resp.Logs[1])
+	}
+
+	// Without a repository the list is empty rather than an error
+	resp, err = NewAdminUseCase(newFakeUserRepo(), nil, nil, nil, nil).ListAuditLogs(context.Background(), actorID, domain.AuditLogFilter{}, 1, 20)
+	if err != nil || resp.Logs == nil || len(resp.Logs) != 0 {
+		t.Errorf("resp = %+v, err = %v", resp, err)
+	}
+}
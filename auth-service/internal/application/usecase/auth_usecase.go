@@ -4,35 +4,101 @@
 package usecase
 
 import (
-	"context"     // Go'nun context paketi - timeout, cancel işlemleri için
-	"errors"      // Hata tanımlamaları için
-	"time"        // Zaman işlemleri için (token expiry vs.)
+	"context"  // Go'nun context paketi - timeout, cancel işlemleri için
+	"errors"   // Hata tanımlamaları için
+	"io"       // io.Discard: logger verilmezse log'lar atılır
+	"log/slog" // Structured logging
+	"net/http" // AppError status kodları
+	"sync"     // Arka plan işlerini beklemek için (WaitGroup)
+	"time"     // Zaman işlemleri için (token expiry vs.)
 
-	"auth-service/internal/application/dto"  // Data Transfer Objects - API request/response
-	"auth-service/internal/domain"           // Domain entities ve repository interfaces
-	"auth-service/pkg/security"              // JWT ve şifreleme servisleri
+	"auth-service/config"                   // Güvenlik ayarları (policy'ler, süreler)
+	"auth-service/internal/application/dto" // Data Transfer Objects - API request/response
+	"auth-service/internal/domain"          // Domain entities ve repository interfaces
+	"auth-service/pkg/security"             // JWT ve şifreleme servisleri
 
-	"github.com/google/uuid"  // UUID oluşturma ve parse için
+	"github.com/go-webauthn/webauthn/webauthn" // Passkey (WebAuthn) doğrulama
+	"github.com/google/uuid"                   // UUID oluşturma ve parse için
 )
 
 // Hata Tanımlamaları
-// Go'da error handling için custom error'lar oluşturuyoruz.
-// Bu error'ları handler'da yakalayıp uygun HTTP status code döneceğiz.
+// Her biri bir AppError: HTTP status'u, API hata kodu ve mesajı kendisiyle gelir,
+// handler'lar sadece respondError'a verir (bkz. errors.go).
 var (
 	// ErrInvalidCredentials - Email/username veya şifre yanlış
-	ErrInvalidCredentials = errors.New("invalid credentials")
-	
+	ErrInvalidCredentials = newAppError(http.StatusUnauthorized, "invalid_credentials", "Invalid email/username or password")
+
 	// ErrUserAlreadyExists - Kayıt olurken email veya username zaten kullanılıyor
-	ErrUserAlreadyExists  = errors.New("user already exists")
-	
+	ErrUserAlreadyExists = newAppError(http.StatusConflict, "user_exists", "User with this email or username already exists")
+
 	// ErrUserNotFound - Kullanıcı veritabanında bulunamadı
-	ErrUserNotFound       = errors.New("user not found")
-	
+	ErrUserNotFound = newAppError(http.StatusNotFound, "user_not_found", "User not found")
+
 	// ErrInvalidToken - JWT token geçersiz veya süresi dolmuş
-	ErrInvalidToken       = errors.New("invalid or expired token")
-	
+	ErrInvalidToken = newAppError(http.StatusUnauthorized, "invalid_token", "Invalid or expired token")
+
 	// ErrUserInactive - Kullanıcı hesabı pasif (banned veya deleted)
-	ErrUserInactive       = errors.New("user account is inactive")
+	ErrUserInactive = newAppError(http.StatusForbidden, "user_inactive", "User account is inactive")
+
+	// ErrTokenExpired - Tek kullanımlık token (şifre sıfırlama vs.) bulundu ama süresi dolmuş
+	ErrTokenExpired = newAppError(http.StatusGone, "token_expired", "The link has expired")
+
+	// ErrEmailNotVerified - Email doğrulanmamış ve UnverifiedLoginPolicy girişe izin vermiyor
+	ErrEmailNotVerified = newAppError(http.StatusForbidden, "email_not_verified", "Email address must be verified before logging in")
+
+	// ErrAlreadyVerified - Email zaten doğrulanmış, yeni doğrulama token'ı gerekmez
+	ErrAlreadyVerified = newAppError(http.StatusConflict, "already_verified", "Email address is already verified")
+
+	// ErrUsernameTaken - Yeni username organizasyonda başka bir kullanıcıya ait
+	ErrUsernameTaken = newAppError(http.StatusConflict, "username_taken", "Username is already taken")
+
+	// ErrUsernameChangeTooSoon - Son username değişikliğinden bu yana SecurityConfig.UsernameChangeCooldown geçmedi
+	ErrUsernameChangeTooSoon = newAppError(http.StatusTooManyRequests, "username_change_too_soon", "Username was changed too recently, try again later")
+
+	// ErrTooManyRequests - Aynı işlem bekleme süresi (cooldown) dolmadan tekrarlandı
+	ErrTooManyRequests = newAppError(http.StatusTooManyRequests, "rate_limited", "Too many requests, please try again later")
+
+	// ErrPasswordTooShort - Şifre SecurityConfig.PasswordMinLength'ten kısa
+	ErrPasswordTooShort = &AppError{Code: "weak_password", Message: "Password does not meet the minimum length requirement",
+		Status: http.StatusBadRequest, Details: map[string]string{"rule": PasswordRuleMinLength}}
+
+	// ErrPasswordTooWeak - Şifrenin tahmini gücü yetersiz (detaylar: PasswordStrengthError)
+	ErrPasswordTooWeak = newAppError(http.StatusBadRequest, "weak_password", "Password is too easy to guess")
+
+	// ErrWeakPassword - Şifre bir policy kuralına uymuyor (hangi kural: PasswordRuleError)
+	ErrWeakPassword = newAppError(http.StatusBadRequest, "weak_password", "Password does not meet the password policy")
+
+	// ErrSamePassword - Yeni şifre mevcut şifreyle aynı
+	ErrSamePassword = newAppError(http.StatusBadRequest, "same_password", "New password must differ from the current password")
+
+	// ErrPasswordReused - Yeni şifre son SecurityConfig.PasswordHistoryDepth şifreden biri
+	ErrPasswordReused = newAppError(http.StatusBadRequest, "password_reused", "New password must differ from your recent passwords")
+
+	// ErrPendingApproval - Hesap henüz bir admin tarafından onaylanmadı
+	ErrPendingApproval = newAppError(http.StatusForbidden, "pending_approval", "Account is waiting for admin approval")
+
+	// ErrNotPendingApproval - Onay/ret sadece onay bekleyen hesaplar için yapılabilir
+	ErrNotPendingApproval = newAppError(http.StatusConflict, "not_pending_approval", "User is not pending approval")
+
+	// ErrTokenReuseDetected - Daha önce rotate edilmiş (iptal) refresh token tekrar kullanıldı
+	// Token çalınmış olabilir: aynı login'den türeyen tüm token'lar iptal edilir
+	ErrTokenReuseDetected = newAppError(http.StatusUnauthorized, "token_reuse_detected", "Refresh token was already used; all sessions from this login have been signed out")
+
+	// ErrInvalidRole - Bilinmeyen rol (bkz. domain.IsValidRole)
+	ErrInvalidRole = newAppError(http.StatusBadRequest, "invalid_role", "Unknown role")
+
+	// ErrAccountLocked - Çok fazla hatalı giriş, hesap LockoutDuration boyunca kilitli
+	ErrAccountLocked = newAppError(http.StatusLocked, "account_locked", "Too many failed login attempts, try again later")
+
+	// ErrSessionNotFound - Oturum (refresh token) yok, iptal edilmiş veya başka kullanıcıya ait
+	ErrSessionNotFound = newAppError(http.StatusNotFound, "session_not_found", "Session not found")
+
+	// ErrUsernameNotAllowed - Username rezerve veya karıştırılabilir (sebep: UsernameNotAllowedError)
+	ErrUsernameNotAllowed = newAppError(http.StatusBadRequest, "username_not_allowed", "This username cannot be registered")
+
+	// ErrRequestTimeout - İstek iptal edildi (client bağlantıyı kapattı) veya süresi doldu
+	// Context bittikten sonra oluşan her hata bununla değiştirilir (bkz. translateContextError)
+	ErrRequestTimeout = newAppError(http.StatusGatewayTimeout, "request_timeout", "The request took too long, please try again")
 )
 
 // AuthUseCase - Kimlik doğrulama iş mantığını yöneten ana struct
@@ -41,29 +107,124 @@ var (
 type AuthUseCase struct {
 	// userRepo - Kullanıcı veritabanı işlemleri için interface (Dependency Injection)
 	// Interface kullanmamızın sebebi: test'lerde mock repository kullanabilmek
-	userRepo         domain.UserRepository
-	
+	userRepo domain.UserRepository
+
 	// refreshTokenRepo - Refresh token'ları veritabanında saklamak için
 	refreshTokenRepo domain.RefreshTokenRepository
-	
+
+	// passwordResetRepo - Şifre sıfırlama token'ları (sadece hash'leri saklanır)
+	passwordResetRepo domain.PasswordResetTokenRepository
+
+	// verificationRepo - Email doğrulama token'ları (sadece hash'leri saklanır)
+	verificationRepo domain.VerificationTokenRepository
+
 	// jwtService - JWT token oluşturma ve doğrulama servisi
 	// Pointer kullanıyoruz çünkü servis içinde state var (secret key vs.)
-	jwtService       *security.JWTService
-	
-	// passwordService - Şifre hash'leme ve karşılaştırma servisi (bcrypt)
-	passwordService  *security.PasswordService
-	
+	jwtService *security.JWTService
+
+	// passwordHasher - Şifre hash'leme ve karşılaştırma (Argon2id veya bcrypt)
+	// Interface: algoritma config'den seçilir, eski hash'ler her iki implementasyonda da doğrulanır
+	passwordHasher security.PasswordHasher
+
+	// dummyHash - Bulunamayan kullanıcıların login'inde karşılaştırılan hash (bkz. equalizeLoginTiming)
+	// İlk ihtiyaçta bir kez üretilir
+	dummyHash     string
+	dummyHashOnce sync.Once
+
 	// accessTokenTTL - Access token'ın ne kadar süre geçerli olacağı (örn: 15 dakika)
 	// time.Duration = Go'nun süre tipi (15*time.Minute gibi)
-	accessTokenTTL   time.Duration
-	
+	accessTokenTTL time.Duration
+
 	// refreshTokenTTL - Refresh token'ın ne kadar süre geçerli olacağı (örn: 7 gün)
-	refreshTokenTTL  time.Duration
+	refreshTokenTTL time.Duration
+
+	// rememberMeTTL - "Beni hatırla" ile yapılan login'lerin refresh token süresi (örn: 30 gün)
+	// 0 ise (WithRememberMe verilmediyse) refreshTokenTTL kullanılır
+	rememberMeTTL time.Duration
+	// refreshAbsoluteTTL - Rotation ile uzayan (sliding) oturumun login'den itibaren en fazla yaşayabileceği süre
+	// 0 ise (WithRefreshAbsoluteTTL verilmediyse) sınır yok: her refresh oturumu uzatır
+	refreshAbsoluteTTL time.Duration
+
+	// securityCfg - Güvenlik ayarları (örn: doğrulanmamış email ile login policy'si)
+	securityCfg config.SecurityConfig
+
+	// mailer - Email gönderimi (doğrulama link'i vs.), varsayılan no-op
+	mailer Mailer
+
+	// linkBaseURL - Email'lerdeki link'lerin başına eklenen frontend URL'i
+	linkBaseURL string
+
+	// events - Hesap olaylarını dışarı bildirir (webhook vs.), varsayılan no-op
+	events EventPublisher
+
+	// approvalRequired - true ise yeni kullanıcılar admin onayı bekler
+	approvalRequired bool
+
+	// registrationDisabled - true ise yeni hesap açılamaz (WithRegistrationEnabled(false))
+	registrationDisabled bool
+
+	// invites - Davet token'ları (sadece hash'leri saklanır); inviteRequired ise davetsiz kayıt yok
+	invites        domain.InviteRepository
+	inviteRequired bool
+
+	// loginMetrics - Başarısız login'leri sebebe göre sayar, varsayılan no-op
+	loginMetrics LoginMetrics
+
+	// userCache - RefreshToken'da token sahibinin okunduğu cache, varsayılan no-op
+	userCache UserCache
+
+	// tokenBlacklist - Logout edilen access token'lar (jti), varsayılan no-op
+	tokenBlacklist domain.TokenBlacklist
+
+	// oauthAccounts - Social login ile bağlanmış provider hesapları; nil ise social login kapalı
+	oauthAccounts domain.OAuthAccountRepository
+
+	// breachChecker - Sızıntıya uğramış şifre kontrolü; nil ise kapalı
+	breachChecker BreachChecker
+
+	// usernameValidator - Rezerve (admin, support...) ve karıştırılabilir (Kiril "аdmin") username'leri reddeder
+	// Yerleşik listeye SecurityConfig.UsernameBlocklist eklenir
+	usernameValidator *security.UsernameValidator
+
+	// passwordHistory - Kullanıcıların son şifre hash'leri; nil ise şifre tekrar kontrolü kapalı
+	passwordHistory domain.PasswordHistoryRepository
+
+	// magicLinkRepo - Şifresiz giriş token'ları (sadece hash'leri saklanır); nil ise magic link kapalı
+	magicLinkRepo domain.MagicLinkTokenRepository
+
+	// webauthn - Passkey ceremony'lerini doğrular; nil ise passkey kapalı
+	// credentials - Kayıtlı passkey'lerin public key'leri ve imza sayaçları
+	// passkeySessions - Başlamış ceremony'lerin challenge'ları, passkeySessionTTL kadar saklanır
+	webauthn          *webauthn.WebAuthn
+	credentials       domain.CredentialRepository
+	passkeySessions   PasskeySessionStore
+	passkeySessionTTL time.Duration
+
+	// organizations - Tenant'lar (organization_slug -> ID); nil ise tek tenant
+	organizations domain.OrganizationRepository
+
+	// transactions - Birlikte yapılması gereken yazmaları (user + ilk refresh token) tek transaction'da çalıştırır
+	// Varsayılan: transaction'sız (no-op)
+	transactions domain.Transactioner
+
+	// auditLogger - Güvenlik olaylarının kaydı (login, logout, şifre değişikliği), varsayılan no-op
+	auditLogger AuditLogger
+	// auditLogs - Kayıtlı audit log'ları okumak için (veri dışa aktarımı); nil ise export'ta audit geçmişi boş
+	auditLogs domain.AuditLogRepository
+
+	// logger - İşlemi başarısız yapmayan hataların log'u (email, webhook, last login...)
+	// Varsayılan: hiçbir şey yazmaz
+	logger *slog.Logger
+
+	// background - Arka plan işleri (email'ler, son giriş zamanı); Stop ve test'ler bitmesini bekler
+	background sync.WaitGroup
+	// lastLoginSlots - Bekleyen son giriş zamanı yazmalarını sınırlar (bkz. recordLastLogin)
+	lastLoginSlots chan struct{}
 }
 
 // NewAuthUseCase - AuthUseCase oluşturan constructor fonksiyon
 // Go'da constructor için New prefix'i kullanılır (convention)
-// 
+//
 // Dependency Injection Pattern:
 // Tüm bağımlılıklar (dependencies) dışarıdan parametre olarak veriliyor.
 // Bu sayede:
@@ -71,61 +232,122 @@ type AuthUseCase struct {
 // 2. Loose coupling (gevşek bağlılık)
 // 3. Değiştirilebilir implementasyonlar
 func NewAuthUseCase(
-	userRepo domain.UserRepository,              // Kullanıcı repository interface'i
+	userRepo domain.UserRepository, // Kullanıcı repository interface'i
 	refreshTokenRepo domain.RefreshTokenRepository, // Token repository interface'i
-	jwtService *security.JWTService,             // JWT servisi
-	passwordService *security.PasswordService,   // Password servisi
-	accessTokenTTL time.Duration,                // Access token süresi
-	refreshTokenTTL time.Duration,               // Refresh token süresi
-) *AuthUseCase {  // Pointer döndürüyoruz (struct büyük olduğu için memory efficient)
+	passwordResetRepo domain.PasswordResetTokenRepository, // Şifre sıfırlama token repository'si
+	verificationRepo domain.VerificationTokenRepository, // Email doğrulama token repository'si
+	jwtService *security.JWTService, // JWT servisi
+	passwordHasher security.PasswordHasher, // Şifre hash'leyici
+	accessTokenTTL time.Duration, // Access token süresi
+	refreshTokenTTL time.Duration, // Refresh token süresi
+	securityCfg config.SecurityConfig, // Güvenlik ayarları
+	opts ...AuthUseCaseOption, // Opsiyonel bağımlılıklar (mailer vs.)
+) *AuthUseCase { // Pointer döndürüyoruz (struct büyük olduğu için memory efficient)
 	// Struct'ı oluştur ve pointer'ını döndür
 	// & operatörü = pointer almak için kullanılır
-	return &AuthUseCase{
-		userRepo:         userRepo,
-		refreshTokenRepo: refreshTokenRepo,
-		jwtService:       jwtService,
-		passwordService:  passwordService,
-		accessTokenTTL:   accessTokenTTL,
-		refreshTokenTTL:  refreshTokenTTL,
+	uc := &AuthUseCase{
+		userRepo:          userRepo,
+		refreshTokenRepo:  refreshTokenRepo,
+		passwordResetRepo: passwordResetRepo,
+		verificationRepo:  verificationRepo,
+		jwtService:        jwtService,
+		passwordHasher:    passwordHasher,
+		accessTokenTTL:    accessTokenTTL,
+		refreshTokenTTL:   refreshTokenTTL,
+		securityCfg:       securityCfg,
+		usernameValidator: security.NewUsernameValidator(securityCfg.UsernameBlocklist...),
+		mailer:            nopMailer{},
+		events:            nopEventPublisher{},
+		loginMetrics:      nopLoginMetrics{},
+		userCache:         nopUserCache{},
+		tokenBlacklist:    nopTokenBlacklist{},
+		auditLogger:       nopAuditLogger{},
+		transactions:      nopTransactioner{},
+		logger:            slog.New(slog.NewTextHandler(io.Discard, nil)),
+		lastLoginSlots:    make(chan struct{}, maxPendingLastLogins),
+	}
+	// Opsiyonel bağımlılıkları uygula
+	for _, opt := range opts {
+		opt(uc)
 	}
+	return uc
 }
 
 // Register - Yeni kullanıcı kaydı oluşturur (Sign Up)
 // Method syntax: func (receiver) MethodName(params) (returns)
 // (uc *AuthUseCase) = Bu method AuthUseCase struct'ına aittir
 // uc = "use case" kısaltması (convention), receiver'ın adı
-func (uc *AuthUseCase) Register(ctx context.Context, req *dto.RegisterRequest) (*dto.AuthResponse, error) {
+func (uc *AuthUseCase) Register(ctx context.Context, req *dto.RegisterRequest) (_ *dto.AuthResponse, err error) {
+	defer translateContextError(ctx, &err)
+
 	// Context nedir?
 	// Go'da her request için context taşınır. İçinde:
 	// - Timeout bilgisi
 	// - Cancel signal
 	// - Request-scoped değerler (user ID, trace ID vs.)
-	
-	// ADIM 1: Email'in daha önce kullanılıp kullanılmadığını kontrol et
-	exists, err := uc.userRepo.ExistsByEmail(ctx, req.Email)
+
+	// Kayıt kapalıysa veritabanına hiç gidilmez
+	if uc.registrationDisabled {
+		return nil, ErrRegistrationDisabled
+	}
+
+	// Email büyük/küçük harf duyarsız: küçük harfe çevrilmiş haliyle aranır ve saklanır
+	// (User@example.com ile kayıt olan user@example.com ile de giriş yapabilir)
+	email := domain.NormalizeEmail(req.Email)
+
+	// ADIM 0: Daveti kontrol et ve organizasyonu bul
+	// Davetle gelen kullanıcı davetin organizasyonuna katılır (organization_slug yok sayılır),
+	// yoksa slug'ın organizasyonuna (slug yoksa varsayılan organizasyon).
+	// Email ve username sadece organizasyon içinde benzersizdir
+	invite, err := uc.registrationInvite(ctx, req.InviteToken, email)
+	if err != nil {
+		return nil, err
+	}
+	var orgID uuid.UUID
+	if invite != nil {
+		orgID = invite.OrganizationID
+	} else if orgID, err = uc.resolveOrganization(ctx, req.OrganizationSlug); err != nil {
+		return nil, err
+	}
+
+	// ADIM 1: Email'in bu organizasyonda daha önce kullanılıp kullanılmadığını kontrol et
+	exists, err := uc.userRepo.ExistsByEmail(ctx, orgID, email)
 	// Go'da error handling pattern:
 	// Fonksiyon (sonuç, error) şeklinde 2 değer döner
-	if err != nil {  // nil = Go'da "null" anlamına gelir
+	if err != nil { // nil = Go'da "null" anlamına gelir
 		// Database hatası varsa, hemen çık ve hatayı döndür
-		return nil, err  // nil = boş response, err = hata
+		return nil, err // nil = boş response, err = hata
 	}
 	if exists {
 		// Email zaten kayıtlı, custom error döndür
 		return nil, ErrUserAlreadyExists
 	}
 
-	// ADIM 2: Username'in daha önce kullanılıp kullanılmadığını kontrol et
-	exists, err = uc.userRepo.ExistsByUsername(ctx, req.Username)
+	// ADIM 2: Username'i normalize et, rezerve/karıştırılabilir mi kontrol et,
+	// sonra daha önce kullanılıp kullanılmadığına bak (harf büyüklüğü fark etmez)
+	username, err := uc.checkUsername(req.Username)
+	if err != nil {
+		return nil, err
+	}
+	exists, err = uc.userRepo.ExistsByUsername(ctx, orgID, username)
 	if err != nil {
 		return nil, err
 	}
 	if exists {
 		return nil, ErrUserAlreadyExists
 	}
+	// Telefon opsiyonel; verildiyse E.164'e çevrilir ve o da organizasyonda benzersiz olmalı
+	phone, err := uc.checkPhone(ctx, orgID, req.Phone)
+	if err != nil {
+		return nil, err
+	}
 
-	// ADIM 3: Şifreyi hash'le (bcrypt kullanarak)
+	// ADIM 3: Şifre policy'sini kontrol et, sonra hash'le (bcrypt kullanarak)
 	// Plain text şifre asla veritabanına kaydedilmez! Güvenlik 101
-	passwordHash, err := uc.passwordService.HashPassword(req.Password)
+	if err := uc.checkPasswordPolicy(ctx, req.Password, email, username); err != nil {
+		return nil, err
+	}
+	passwordHash, err := uc.passwordHasher.Hash(req.Password)
 	if err != nil {
 		return nil, err
 	}
@@ -134,72 +356,212 @@ func (uc *AuthUseCase) Register(ctx context.Context, req *dto.RegisterRequest) (
 	// & operatörü = struct'ın pointer'ını almak için
 	// Pointer kullanmamızın sebebi: büyük struct'ları kopyalamamak (performance)
 	user := &domain.User{
-		Email:        req.Email,          // Request'ten gelen email
-		Username:     req.Username,       // Request'ten gelen username
-		PasswordHash: passwordHash,       // Hash'lenmiş şifre (güvenli)
-		FirstName:    req.FirstName,      // İsim (opsiyonel)
-		LastName:     req.LastName,       // Soyisim (opsiyonel)
-		IsActive:     true,               // Yeni kullanıcı aktif olarak başlar
-		IsVerified:   false,              // Email doğrulaması yapılmamış
-	}
-
-	// ADIM 5: User'ı veritabanına kaydet
-	// Create fonksiyonu user'a ID, CreatedAt, UpdatedAt ekleyecek (GORM)
-	if err := uc.userRepo.Create(ctx, user); err != nil {
+		Email:        email,         // Küçük harfe çevrilmiş email
+		Username:     username,      // Normalize edilmiş username
+		Phone:        phone,         // E.164 telefon (opsiyonel, boş olabilir)
+		PasswordHash: passwordHash,  // Hash'lenmiş şifre (güvenli)
+		FirstName:    req.FirstName, // İsim (opsiyonel)
+		LastName:     req.LastName,  // Soyisim (opsiyonel)
+		IsActive:     true,          // Yeni kullanıcı aktif olarak başlar
+		IsVerified:   false,         // Email doğrulaması yapılmamış
+		Status:       domain.UserStatusActive,
+		Role:         domain.RoleUser, // Yeni kullanıcılar yetkisiz başlar; admin'i bir admin atar
+	}
+	user.OrganizationID = orgID
+	if invite != nil {
+		user.InvitedBy = &invite.InvitedBy
+	}
+	// Onay workflow'u açıksa kullanıcı admin onayını bekler
+	if uc.approvalRequired {
+		user.Status = domain.UserStatusPendingApproval
+	}
+
+	// ADIM 5: User'ı ve ilk oturumunu (refresh token) tek transaction'da kaydet
+	// Token kaydı başarısız olursa user da geri alınır; yoksa kullanıcı
+	// giriş yapamadığı bir hesapla ve "email zaten kayıtlı" hatasıyla kalırdı.
+	// Onay bekleyen kullanıcıya token verilmez, sadece user kaydedilir.
+	var resp *dto.AuthResponse
+	err = uc.transactions.WithinTransaction(ctx, func(ctx context.Context) error {
+		// Davet tek kullanımlık: aynı anda iki kayıtta kullanılırsa sadece biri tüketebilir
+		// (kayıt geri alınırsa davet de kullanılmamış olarak kalır)
+		if invite != nil {
+			if _, err := uc.invites.Consume(ctx, invite.TokenHash); err != nil {
+				return notFoundAs(err, ErrInvalidInvite)
+			}
+		}
+		// Create fonksiyonu user'a ID, CreatedAt, UpdatedAt ekleyecek (GORM)
+		if err := uc.userRepo.Create(ctx, user); err != nil {
+			return err
+		}
+		// İlk şifre sadece istenirse geçmişe yazılır (PasswordHistoryOnRegister)
+		if uc.securityCfg.PasswordHistoryOnRegister {
+			if err := uc.recordPasswordHistory(ctx, user); err != nil {
+				return err
+			}
+		}
+		if user.IsPendingApproval() {
+			return nil
+		}
+		// JWT token'ları oluştur: kullanıcı kayıt olduktan sonra otomatik login olur
+		resp, err = uc.generateAuthResponse(ctx, user, nil, false)
+		return err
+	})
+	if err != nil {
 		return nil, err
 	}
+	// Olay ve email commit'ten sonra gönderilir: geri alınan bir kayıt için gönderilmemeli
+	uc.publishUserEvent(ctx, EventUserRegistered, user, map[string]interface{}{"method": "password"})
 
-	// ADIM 6: JWT token'ları oluştur ve kullanıcıya döndür
-	// Bu sayede kullanıcı kayıt olduktan sonra otomatik login olur
-	return uc.generateAuthResponse(ctx, user)
+	// ADIM 6: Email doğrulama link'i gönder
+	// Kayıt doğrulamasız da başarılı olur; gönderim hatası kaydı başarısız yapmaz
+	// (kullanıcı /auth/resend-verification ile tekrar isteyebilir)
+	if _, err := uc.GenerateEmailVerification(ctx, user.ID); err != nil {
+		// Bu hata kritik değil, kaydı başarısız yapma
+		uc.logError(ctx, "send verification email", err, "user_id", user.ID)
+	}
+
+	// ADIM 7: Onay bekleyen kullanıcı için token verilmez
+	// Admin'ler webhook ile haberdar edilir; gönderim hatası kaydı başarısız yapmaz
+	if user.IsPendingApproval() {
+		if err := uc.events.Publish(ctx, "user.pending_approval", map[string]interface{}{
+			"user_id":  user.ID.String(),
+			"email":    user.Email,
+			"username": user.Username,
+		}); err != nil {
+			// Bu hata kritik değil, kaydı başarısız yapma
+			uc.logError(ctx, "publish user.pending_approval", err, "user_id", user.ID)
+		}
+		return &dto.AuthResponse{
+			User:            toUserInfo(user),
+			ApprovalPending: true,
+		}, nil
+	}
+
+	// ADIM 8: ADIM 5'te oluşturulan token'ları kullanıcıya döndür
+	return resp, nil
 }
 
 // Login - Kullanıcı girişi yapar (Sign In)
 // Email veya username ile giriş yapılabilir
-func (uc *AuthUseCase) Login(ctx context.Context, req *dto.LoginRequest) (*dto.AuthResponse, error) {
+func (uc *AuthUseCase) Login(ctx context.Context, req *dto.LoginRequest) (_ *dto.AuthResponse, err error) {
+	defer translateContextError(ctx, &err)
+
 	// ADIM 1: Kullanıcıyı bul (email veya username ile)
 	// Go'da variable declaration:
 	// var name type = değer
 	// var user *domain.User = pointer tipinde değişken
 	var user *domain.User
-	var err error  // error tipi Go'nun built-in tipi
-
-	// Önce email olarak dene
-	user, err = uc.userRepo.GetByEmail(ctx, req.EmailOrUsername)
-	// || = veya (OR) operatörü
-	if err != nil || user == nil {  // == nil = pointer boş mu kontrolü
-		// Email'le bulamadık, username olarak dene
-		user, err = uc.userRepo.GetByUsername(ctx, req.EmailOrUsername)
-		if err != nil || user == nil {
-			// İkisiyle de bulamadık, geçersiz credential
-			// Güvenlik notu: "Email bulunamadı" dememizin sebebi:
-			// Hacker'a hangi email'lerin kayıtlı olduğunu söylememek
-			return nil, ErrInvalidCredentials
+
+	// Kullanıcı sadece kendi organizasyonunda aranır
+	// Bilinmeyen organizasyon, bilinmeyen kullanıcı gibi davranır (organizasyonlar da sızdırılmaz)
+	orgID, err := uc.resolveOrganization(ctx, req.OrganizationSlug)
+	if errors.Is(err, ErrOrganizationNotFound) {
+		uc.equalizeLoginTiming(req.Password)
+		uc.loginFailed(ctx, uuid.Nil, req.EmailOrUsername, LoginFailureUserNotFound)
+		return nil, ErrInvalidCredentials
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Email ve username tek sorguda aranır (login'de tek DB round-trip)
+	user, err = uc.userRepo.GetByEmailOrUsername(ctx, orgID, req.EmailOrUsername)
+	// İkisi de değilse telefon numarası olabilir; E.164'e çevrilebiliyorsa onunla da aranır
+	// (email/username eşleşmesi önce gelir, "+" ile başlayan username'ler olmadığından çakışmaz)
+	if errors.Is(err, domain.ErrNotFound) {
+		if phone, ok := domain.NormalizePhone(req.EmailOrUsername); ok {
+			user, err = uc.userRepo.GetByPhone(ctx, orgID, phone)
 		}
 	}
+	if errors.Is(err, domain.ErrNotFound) {
+		// Bulamadık, geçersiz credential
+		// Güvenlik notu: "Email bulunamadı" dememizin sebebi:
+		// Hacker'a hangi email'lerin kayıtlı olduğunu söylememek
+		// Aynı sebeple şifre yine de hash'lenir: cevap süresi de kullanıcının varlığını sızdırmasın
+		uc.equalizeLoginTiming(req.Password)
+		uc.loginFailed(ctx, uuid.Nil, req.EmailOrUsername, LoginFailureUserNotFound)
+		return nil, ErrInvalidCredentials
+	}
+	if err != nil {
+		// Veritabanı hatası: şifre yanlış değil, servis sorunu (500)
+		return nil, err
+	}
+
+	// Silinmiş hesap (repository zaten atlar; başka bir implementasyona karşı ek güvence)
+	if user.IsDeleted() {
+		uc.loginFailed(ctx, user.ID, req.EmailOrUsername, LoginFailureUserNotFound)
+		return nil, ErrUserNotFound
+	}
 
 	// ADIM 2: Kullanıcı hesabı aktif mi kontrol et
 	// ! = değil (NOT) operatörü
 	if !user.IsActive {
 		// Hesap pasif (banned, deleted vs.)
+		uc.loginFailed(ctx, user.ID, req.EmailOrUsername, LoginFailureInactive)
 		return nil, ErrUserInactive
 	}
 
-	// ADIM 3: Şifreyi doğrula
+	// ADIM 3: Hesap kilitli mi? (çok fazla hatalı deneme)
+	// Kilitliyken şifre hiç kontrol edilmez: brute-force devam edemez
+	if user.IsLocked() {
+		uc.loginFailed(ctx, user.ID, req.EmailOrUsername, LoginFailureLocked)
+		return nil, ErrAccountLocked
+	}
+
+	// ADIM 4: Şifreyi doğrula
 	// bcrypt ile hash'lenmiş şifre karşılaştırılır
-	if !uc.passwordService.ComparePassword(user.PasswordHash, req.Password) {
-		// Şifre yanlış
+	if !uc.passwordHasher.Compare(user.PasswordHash, req.Password) {
+		// Şifre yanlış: sayacı artır, limit aşıldıysa hesabı kilitle
+		uc.loginFailed(ctx, user.ID, req.EmailOrUsername, LoginFailureBadPassword)
+		if err := uc.recordFailedLogin(ctx, user); err != nil {
+			return nil, err
+		}
 		return nil, ErrInvalidCredentials
 	}
 
-	// ADIM 4: Son giriş zamanını güncelle (analytics için)
-	if err := uc.userRepo.UpdateLastLogin(ctx, user.ID); err != nil {
-		// Bu hata kritik değil, login'i başarısız yapma
-		// Sadece log'la (production'da logging middleware yapacak)
+	// Başarılı şifre: önceki hatalı denemeleri sıfırla
+	if user.FailedLoginAttempts > 0 || user.LockedUntil != nil {
+		if err := uc.userRepo.SetLockout(ctx, user.ID, nil); err != nil {
+			return nil, err
+		}
+		user.FailedLoginAttempts, user.LockedUntil = 0, nil
+	}
+
+	// Hash eski algoritma/parametrelerle yapılmışsa şimdi (düz şifre elimizdeyken) yenile
+	uc.rehashPassword(ctx, user, req.Password)
+
+	// ADIM 5: Admin onayı bekleyen hesap login olamaz
+	// Şifre kontrolünden SONRA: hesap durumu sadece hesap sahibine gösterilir
+	if user.IsPendingApproval() {
+		uc.loginFailed(ctx, user.ID, req.EmailOrUsername, LoginFailurePendingApproval)
+		return nil, ErrPendingApproval
 	}
 
-	// ADIM 5: JWT token'ları oluştur ve döndür
-	return uc.generateAuthResponse(ctx, user)
+	// ADIM 6: Email doğrulama policy'sini uygula
+	// Şifre kontrolünden SONRA yapılır: doğrulama durumu sadece hesap sahibine gösterilir
+	verificationRequired, err := uc.checkUnverifiedLogin(user)
+	if err != nil {
+		uc.loginFailed(ctx, user.ID, req.EmailOrUsername, LoginFailureUnverified)
+		return nil, err
+	}
+
+	// ADIM 7: Yeni cihazdan giriş ise kullanıcıyı uyar (arka planda, login'i bekletmez)
+	// Son giriş zamanı güncellenmeden ÖNCE: ilk login'de uyarı gönderilmez
+	uc.notifyNewDevice(ctx, user)
+
+	// Son giriş zamanını güncelle (analytics için, arka planda: login DB yazmasını beklemez)
+	uc.recordLastLogin(ctx, user.ID)
+
+	// ADIM 8: JWT token'ları oluştur ve döndür
+	// RememberMe: refresh token daha uzun (rememberMeTTL) yaşar
+	response, err := uc.generateAuthResponse(ctx, user, nil, req.RememberMe)
+	if err != nil {
+		return nil, err
+	}
+	// Login'e izin verildi ama email hâlâ doğrulanmamış: client kullanıcıyı uyarabilsin
+	response.EmailVerificationRequired = verificationRequired
+	uc.logAudit(ctx, AuditLoginSuccess, user.ID, map[string]string{"method": "password"})
+	return response, nil
 }
 
 // RefreshToken - Eski refresh token ile yeni access token al
@@ -207,55 +569,114 @@ func (uc *AuthUseCase) Login(ctx context.Context, req *dto.LoginRequest) (*dto.A
 // - Access Token: Kısa ömürlü (15 dk), her istekte gönderilir
 // - Refresh Token: Uzun ömürlü (7 gün), sadece yenileme için kullanılır
 // Bu sayede access token çalınsa bile kısa sürede geçersiz olur
-func (uc *AuthUseCase) RefreshToken(ctx context.Context, refreshTokenString string) (*dto.AuthResponse, error) {
+func (uc *AuthUseCase) RefreshToken(ctx context.Context, refreshTokenString string) (_ *dto.AuthResponse, err error) {
+	defer translateContextError(ctx, &err)
+
 	// ADIM 1: Refresh token'ı veritabanında bul
 	// Refresh token'lar veritabanında saklanır (revoke edebilmek için)
-	refreshToken, err := uc.refreshTokenRepo.GetByToken(ctx, refreshTokenString)
-	if err != nil || refreshToken == nil {
-		// Token veritabanında yok veya hata var
-		return nil, ErrInvalidToken
+	// İptal edilmişler de gelir: reuse detection için gerekli
+	// Veritabanında sadece hash'i var (dump'tan çalınan değer token olarak kullanılamaz)
+	tokenHash := security.HashToken(refreshTokenString)
+	refreshToken, err := uc.refreshTokenRepo.GetByTokenHashIncludingRevoked(ctx, tokenHash)
+	if err != nil {
+		// Token veritabanında yok: ErrInvalidToken; veritabanı hatası olduğu gibi döner
+		return nil, notFoundAs(err, ErrInvalidToken)
 	}
 
-	// ADIM 2: Token geçerli mi kontrol et
-	// IsValid() method'u: expired mı, revoked mı kontrol eder
+	// ADIM 2: Reuse detection
+	// İptal edilmiş bir token tekrar geldiyse: ya eski bir kopya ya da çalınmış token.
+	// Token rotation'da meşru client her zaman en son token'ı kullanır, bu yüzden
+	// bunu hırsızlık sinyali sayıp token ailesinin (aynı login zinciri) tamamını iptal ediyoruz.
+	// Saldırgan da kullanıcı da tekrar login olmak zorunda kalır.
+	if refreshToken.IsRevoked {
+		if err := uc.revokeTokenFamily(ctx, refreshToken); err != nil {
+			return nil, err
+		}
+		uc.logAudit(ctx, AuditTokenReused, refreshToken.UserID, map[string]string{"family_id": refreshToken.FamilyID.String()})
+		return nil, ErrTokenReuseDetected
+	}
+
+	// ADIM 3: Token süresi dolmuş mu kontrol et
 	if !refreshToken.IsValid() {
-		// Token süresi dolmuş veya iptal edilmiş
+		return nil, ErrInvalidToken
+	}
+	// Mutlak süre: her refresh token'ı uzatır (sliding) ama oturum login'den itibaren
+	// refreshAbsoluteTTL'i geçemez; token "taze" olsa bile kullanıcı tekrar login olmalı
+	if uc.refreshAbsoluteTTL > 0 && time.Since(refreshToken.SessionStart()) >= uc.refreshAbsoluteTTL {
 		return nil, ErrInvalidToken
 	}
 
-	// ADIM 3: Token'ın sahibi olan kullanıcıyı bul
-	user, err := uc.userRepo.GetByID(ctx, refreshToken.UserID)
-	if err != nil || user == nil {
+	// ADIM 4: Token'ın sahibi olan kullanıcıyı bul
+	// Her refresh'te sorulduğu için cache'ten (UserCache); kullanıcı değişince cache'ten silinir
+	user, err := uc.cachedUser(ctx, refreshToken.UserID)
+	if err != nil {
 		// Kullanıcı silinmiş olabilir
-		return nil, ErrUserNotFound
+		return nil, notFoundAs(err, ErrUserNotFound)
 	}
 
-	// ADIM 4: Kullanıcı hesabı aktif mi kontrol et
+	// ADIM 5: Kullanıcı hesabı aktif mi kontrol et
 	if !user.IsActive {
 		// Hesap ban yemiş, yeni token verme
 		return nil, ErrUserInactive
 	}
 
-	// ADIM 5: Eski refresh token'ı iptal et (revoke)
+	// ADIM 6: Kullanım zamanını kaydet (kritik değil), sonra eski refresh token'ı iptal et (revoke)
 	// Güvenlik: Aynı refresh token tekrar kullanılamasın
 	// Token Rotation strategy: Her refresh'te yeni token ver
-	if err := uc.refreshTokenRepo.Revoke(ctx, refreshTokenString); err != nil {
+	if err := uc.refreshTokenRepo.Touch(ctx, tokenHash); err != nil {
+		uc.logError(ctx, "touch refresh token", err, "session_id", refreshToken.ID)
+	}
+	if err := uc.refreshTokenRepo.Revoke(ctx, tokenHash); err != nil {
 		// Bu hata kritik değil, devam et
+		uc.logError(ctx, "revoke refresh token", err, "session_id", refreshToken.ID)
 	}
 
-	// ADIM 6: Yeni access ve refresh token'lar oluştur
-	return uc.generateAuthResponse(ctx, user)
+	// ADIM 7: Yeni access ve refresh token'lar oluştur
+	// Yeni token aynı aileye (login zincirine) ait olur, "beni hatırla" tercihini ve login zamanını devralır
+	return uc.generateAuthResponse(ctx, user, refreshToken, refreshToken.RememberMe)
+}
+
+// revokeTokenFamily - Tekrar kullanılan token'ın ailesini iptal eder
+// family_id'si olmayan eski token'larda zincir bilinmediği için kullanıcının tüm oturumları kapatılır.
+func (uc *AuthUseCase) revokeTokenFamily(ctx context.Context, token *domain.RefreshToken) error {
+	if token.FamilyID == uuid.Nil {
+		return uc.refreshTokenRepo.RevokeAllByUserID(ctx, token.UserID)
+	}
+	return uc.refreshTokenRepo.RevokeFamily(ctx, token.FamilyID)
 }
 
 // Logout - Kullanıcının tüm refresh token'larını iptal eder
 // JWT'nin dezavantajı: Access token'lar stateless (server'da saklanmaz)
 // Bu yüzden logout yaptıktan sonra bile access token süresi dolana kadar geçerlidir.
-// Çözüm: Kısa ömürlü access token (15 dk) + blacklist (opsiyonel)
-func (uc *AuthUseCase) Logout(ctx context.Context, userID uuid.UUID) error {
-	// Kullanıcının tüm refresh token'larını iptal et
+// Çözüm: Kısa ömürlü access token (15 dk) + blacklist
+// İsteği yapan access token (ContextWithAccessToken) kalan ömrü boyunca blacklist'e alınır.
+func (uc *AuthUseCase) Logout(ctx context.Context, userID uuid.UUID) (err error) {
+	defer translateContextError(ctx, &err)
+
+	// ADIM 1: Kullanıcının tüm refresh token'larını iptal et
 	// Bu sayede yeni access token alamazlar
 	// uuid.UUID = Google'un UUID kütüphanesi, universally unique identifier
-	return uc.refreshTokenRepo.RevokeAllByUserID(ctx, userID)
+	if err := uc.refreshTokenRepo.RevokeAllByUserID(ctx, userID); err != nil {
+		return err
+	}
+	uc.logAudit(ctx, AuditLogout, userID, nil)
+
+	// ADIM 2: Mevcut access token'ı blacklist'e al
+	return uc.blacklistCurrentToken(ctx)
+}
+
+// blacklistCurrentToken - İsteği yapan access token'ı (ContextWithAccessToken) blacklist'e alır
+// TTL = token'ın kalan ömrü; süresi dolan token zaten reddedilir, kaydı tutmaya gerek yok
+func (uc *AuthUseCase) blacklistCurrentToken(ctx context.Context) error {
+	token, ok := accessTokenFromContext(ctx)
+	if !ok {
+		return nil
+	}
+	ttl := time.Until(token.expiresAt)
+	if ttl <= 0 {
+		return nil
+	}
+	return uc.tokenBlacklist.Add(ctx, token.id, ttl)
 }
 
 // generateAuthResponse - Token'ları oluşturup AuthResponse döndüren yardımcı fonksiyon
@@ -263,20 +684,22 @@ func (uc *AuthUseCase) Logout(ctx context.Context, userID uuid.UUID) error {
 // Go'da Access Control:
 // - Büyük harf = Public (exported): Register, Login vs.
 // - Küçük harf = Private (unexported): generateAuthResponse
-func (uc *AuthUseCase) generateAuthResponse(ctx context.Context, user *domain.User) (*dto.AuthResponse, error) {
-	// ADIM 1: JWT Access Token oluştur
-	// Access token içinde user bilgileri (claims) saklanır:
-	// - user_id: Kullanıcının ID'si
-	// - email: Email adresi
-	// - username: Kullanıcı adı
-	// - exp: Token ne zaman expire olacak (expiration)
-	accessToken, err := uc.jwtService.GenerateAccessToken(user.ID, user.Email, user.Username)
-	if err != nil {
-		// JWT oluşturma hatası (secret key problemi vs.)
-		return nil, err
+// parent = rotation'da yerine geçilen refresh token; nil ise yeni bir login zinciri başlar (login/register)
+// rememberMe = refresh token rememberMeTTL kadar yaşar; access token süresi değişmez
+func (uc *AuthUseCase) generateAuthResponse(ctx context.Context, user *domain.User, parent *domain.RefreshToken, rememberMe bool) (*dto.AuthResponse, error) {
+	// family_id'si olmayan eski token'lar yeni bir zincir başlatır
+	rotated := parent != nil && parent.FamilyID != uuid.Nil
+	familyID := uuid.New()
+	if rotated {
+		familyID = parent.FamilyID
+	}
+	// Oturumun başladığı login zamanı; rotation'da devralınır (mutlak süre buna göre hesaplanır)
+	authenticatedAt := time.Now()
+	if parent != nil {
+		authenticatedAt = parent.SessionStart()
 	}
 
-	// ADIM 2: Refresh Token string'i oluştur
+	// ADIM 1: Refresh Token string'i oluştur
 	// Refresh token = random, secure string (JWT değil)
 	// Veritabanında saklanacak
 	refreshTokenString, err := uc.jwtService.GenerateRefreshToken()
@@ -284,34 +707,101 @@ func (uc *AuthUseCase) generateAuthResponse(ctx context.Context, user *domain.Us
 		return nil, err
 	}
 
-	// ADIM 3: Refresh token entity'sini oluştur
+	// ADIM 2: Refresh token entity'sini oluştur
+	// ID'yi burada veriyoruz: access token'daki "sid" claim'i bu kayda işaret eder
+	// Cihaz bilgisi (User-Agent, IP) handler'ın ContextWithClient ile eklediği context'ten gelir
+	client := clientFromContext(ctx)
 	refreshToken := &domain.RefreshToken{
+		ID:        uuid.New(),                             // Oturum ID'si
 		UserID:    user.ID,                                // Hangi kullanıcıya ait
-		Token:     refreshTokenString,                     // Token string'i
-		ExpiresAt: time.Now().Add(uc.refreshTokenTTL),    // Şimdi + 7 gün (config'den gelir)
+		FamilyID:  familyID,                               // Login zinciri (reuse detection)
+		TokenHash: security.HashToken(refreshTokenString), // Sadece hash saklanır; düz token client'a döner
+		ExpiresAt: time.Now().Add(uc.refreshTokenTTL),     // Şimdi + 7 gün (config'den gelir)
 		IsRevoked: false,                                  // Aktif token
+		UserAgent: client.userAgent,                       // Oturumu açan/yenileyen cihaz
+		IPAddress: client.ipAddress,
+		// Rotation'da yeni token da aynı süreyi alır
+		RememberMe:      rememberMe,
+		AuthenticatedAt: &authenticatedAt,
+	}
+	// "Beni hatırla": refresh token daha uzun yaşar (örn: 30 gün)
+	if rememberMe && uc.rememberMeTTL > 0 {
+		refreshToken.ExpiresAt = time.Now().Add(uc.rememberMeTTL)
+	}
+	// Sliding expiration mutlak sınırı aşamaz: token en geç login + refreshAbsoluteTTL'de biter
+	if limit := authenticatedAt.Add(uc.refreshAbsoluteTTL); uc.refreshAbsoluteTTL > 0 && refreshToken.ExpiresAt.After(limit) {
+		refreshToken.ExpiresAt = limit
+	}
+	// Yeni cihaz tespiti (notifyNewDevice) bu parmak izine bakar
+	if client.known() {
+		refreshToken.DeviceFingerprint = domain.DeviceFingerprint(client.ipAddress, client.userAgent)
+	}
+	// Rotation: oturum şu an kullanıldı; oturum listesinde yeni token'la birlikte görünür
+	if rotated {
+		now := time.Now()
+		refreshToken.LastUsedAt = &now
 	}
 
-	// ADIM 4: Refresh token'ı veritabanına kaydet
+	// ADIM 3: Refresh token'ı veritabanına kaydet
+	// Tek oturum modunda önce diğer oturumlar kapatılır; yeni oturum sonra oluşturulduğu için açık kalır
+	if !rotated && uc.securityCfg.SingleSessionMode {
+		if err := uc.endOtherSessions(ctx, user); err != nil {
+			return nil, err
+		}
+	}
 	if err := uc.refreshTokenRepo.Create(ctx, refreshToken); err != nil {
 		return nil, err
 	}
+	// Eşzamanlı oturum limiti aşıldıysa en eski oturumlar kapatılır (yeni oturum her zaman kalır)
+	if !rotated {
+		uc.enforceSessionLimit(ctx, user.ID)
+	}
+
+	// ADIM 4: JWT Access Token oluştur
+	// Access token içinde user bilgileri (claims) saklanır:
+	// - user_id: Kullanıcının ID'si
+	// - email: Email adresi
+	// - username: Kullanıcı adı
+	// - sid: Oturum ID'si (refresh token kaydı)
+	// - org_id: Kullanıcının organizasyonu (tenant)
+	// - scopes: Rolden türetilen yetkiler; refresh'te de kullanıcının güncel rolünden yeniden üretilir
+	// - pwd_chg: Geçici şifreyle giriş; şifre değişene kadar sadece şifre değiştirilebilir
+	// - exp: Token ne zaman expire olacak (expiration)
+	accessToken, err := uc.jwtService.GenerateAccessToken(user.ID, user.Email, user.Username, user.Role, domain.ScopesForRole(user.Role), refreshToken.ID, user.OrganizationID, user.TokenVersion, user.MustChangePassword)
+	if err != nil {
+		// JWT oluşturma hatası (secret key problemi vs.)
+		return nil, err
+	}
 
 	// ADIM 5: AuthResponse DTO'sunu oluştur ve döndür
 	// & = struct'tan pointer oluşturma
 	return &dto.AuthResponse{
-		AccessToken:  accessToken,                          // JWT access token
-		RefreshToken: refreshTokenString,                   // Refresh token
-		TokenType:    "Bearer",                             // OAuth 2.0 standard: "Bearer" prefix
-		ExpiresIn:    int64(uc.accessTokenTTL.Seconds()),  // Kaç saniye sonra expire olur
+		AccessToken:  accessToken,                        // JWT access token
+		RefreshToken: refreshTokenString,                 // Refresh token
+		TokenType:    "Bearer",                           // OAuth 2.0 standard: "Bearer" prefix
+		ExpiresIn:    int64(uc.accessTokenTTL.Seconds()), // Kaç saniye sonra expire olur
+		// Cookie modunda refresh token cookie'si bu zamana kadar yaşar
+		RefreshTokenExpiresAt: refreshToken.ExpiresAt,
 		// User bilgilerini de dön (frontend'de kullanıcı bilgisini göstermek için)
-		User: &dto.UserInfo{
-			ID:        user.ID.String(),  // UUID'yi string'e çevir (JSON için)
-			Email:     user.Email,
-			Username:  user.Username,
-			FirstName: user.FirstName,
-			LastName:  user.LastName,
-			IsActive:  user.IsActive,
-		},
-	}, nil  // nil = hata yok
+		User: toUserInfo(user),
+		// Frontend kullanıcıyı doğrudan şifre değiştirme ekranına yönlendirir
+		MustChangePassword: user.MustChangePassword,
+	}, nil // nil = hata yok
+}
+
+// toUserInfo - Domain User'ı API'ye dönen UserInfo DTO'suna çevirir
+func toUserInfo(user *domain.User) *dto.UserInfo {
+	return &dto.UserInfo{
+		ID:        user.ID.String(), // UUID'yi string'e çevir (JSON için)
+		Email:     user.Email,
+		Username:  user.Username,
+		FirstName: user.FirstName,
+		LastName:  user.LastName,
+		Phone:     user.Phone,
+		IsActive:  user.IsActive,
+		Role:      user.Role,
+		// Tek tenant kurulumda (uuid.Nil) boş kalır
+		OrganizationID: uuidString(user.OrganizationID),
+		IsVerified:     user.IsVerified,
+	}
 }
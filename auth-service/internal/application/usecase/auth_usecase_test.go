@@ -0,0 +1,179 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"auth-service/config"
+	"auth-service/internal/application/dto"
+	"auth-service/internal/domain"
+	"auth-service/internal/infrastructure/blacklist"
+	"auth-service/internal/infrastructure/mailer"
+	"auth-service/pkg/security"
+)
+
+// testDeps holds the fakes behind a use case built by newTestUseCase
+type testDeps struct {
+	users         *fakeUserRepo
+	refreshTokens *fakeRefreshTokenRepo
+	resetTokens   *fakePasswordResetRepo
+	verifications *fakeVerificationRepo
+	mailer        *mailer.MemoryMailer
+	events        *fakeEventPublisher
+	blacklist     domain.TokenBlacklist
+}
+
+// testSecurityConfig returns the security settings used by newTestUseCase
+func testSecurityConfig() config.SecurityConfig {
+	cfg := config.DefaultSecurityConfig()
+	// bcrypt.MinCost keeps the tests fast
+	cfg.BcryptCost = 4
+	return cfg
+}
+
+func newTestUseCase(t *testing.T) (*AuthUseCase, *testDeps) {
+	t.Helper()
+	return newTestUseCaseWithConfig(t, testSecurityConfig())
+}
+
+func newTestUseCaseWithConfig(t *testing.T, securityCfg config.SecurityConfig, opts ...AuthUseCaseOption) (*AuthUseCase, *testDeps) {
+	t.Helper()
+
+	deps := &testDeps{
+		users:         newFakeUserRepo(),
+		refreshTokens: newFakeRefreshTokenRepo(),
+		resetTokens:   newFakePasswordResetRepo(),
+		verifications: newFakeVerificationRepo(),
+		mailer:        mailer.NewMemoryMailer(),
+		events:        &fakeEventPublisher{},
+		blacklist:     blacklist.NewMemoryTokenBlacklist(),
+	}
+	jwtService := security.NewJWTService("test-secret-key-that-is-long-enough", 15*time.Minute, 7*24*time.Hour)
+	passwordHasher := security.NewBcryptHasher(securityCfg.BcryptCost)
+
+	uc := NewAuthUseCase(
+		deps.users,
+		deps.refreshTokens,
+		deps.resetTokens,
+		deps.verifications,
+		jwtService,
+		passwordHasher,
+		15*time.Minute,
+		7*24*time.Hour,
+		securityCfg,
+		append([]AuthUseCaseOption{
+			WithMailer(deps.mailer, "https://app.example.com"),
+			WithEventPublisher(deps.events),
+			WithTokenBlacklist(deps.blacklist),
+		}, opts...)...,
+	)
+	return uc, deps
+}
+
+func TestRegisterRollsBackUserWhenTokenCreationFails(t *testing.T) {
+	uc, deps := newTestUseCase(t)
+	uc.transactions = &fakeTransactioner{users: deps.users, refreshTokens: deps.refreshTokens}
+	deps.refreshTokens.createErr = errors.New("insert failed")
+
+	req := &dto.RegisterRequest{Email: "jane@example.com", Username: "jane", Password: "correct-horse"}
+	if _, err := uc.Register(context.Background(), req); err == nil {
+		t.Fatal("Register succeeded, want the token insert error")
+	}
+	if n := len(deps.users.users); n != 0 {
+		t.Errorf("%d users left after rollback, want none", n)
+	}
+	if names := deps.events.names(); len(names) != 0 {
+		t.Errorf("events = %v, want none for a rolled back registration", names)
+	}
+	if sent := deps.mailer.Sent(); len(sent) != 0 {
+		t.Errorf("sent %d emails, want none", len(sent))
+	}
+
+	// The email is free again once the insert works
+	deps.refreshTokens.createErr = nil
+	if _, err := uc.Register(context.Background(), req); err != nil {
+		t.Fatalf("retry: %v", err)
+	}
+}
+
+func TestLoginLooksUpEmailOrUsername(t *testing.T) {
+	uc, deps := newTestUseCase(t)
+	seedUser(t, uc, deps, &domain.User{Email: "jane@example.com", Username: "jane", IsVerified: true}, "correct-horse")
+
+	for _, identifier := range []string{"jane@example.com", "jane"} {
+		resp, err := uc.Login(context.Background(), &dto.LoginRequest{EmailOrUsername: identifier, Password: "correct-horse"})
+		if err != nil {
+			t.Fatalf("login as %q: %v", identifier, err)
+		}
+		if resp.User.Email != "jane@example.com" {
+			t.Errorf("login as %q returned %s", identifier, resp.User.Email)
+		}
+	}
+
+	// An unknown user looks the same as a wrong password
+	_, err := uc.Login(context.Background(), &dto.LoginRequest{EmailOrUsername: "john", Password: "correct-horse"})
+	if err != ErrInvalidCredentials {
+		t.Errorf("unknown user: err = %v, want ErrInvalidCredentials", err)
+	}
+}
+
+func TestEmailIsCaseInsensitiveAndUsernameKeepsCase(t *testing.T) {
+	uc, deps := newTestUseCase(t)
+	ctx := context.Background()
+
+	resp, err := uc.Register(ctx, &dto.RegisterRequest{Email: "Jane.Doe@Example.com", Username: "JaneDoe", Password: "correct-horse"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.User.Email != "jane.doe@example.com" || resp.User.Username != "JaneDoe" {
+		t.Errorf("registered as %s / %s, want a lowercase email and the username as typed", resp.User.Email, resp.User.Username)
+	}
+	for _, u := range deps.users.users {
+		u.IsVerified = true
+	}
+
+	_, err = uc.Register(ctx, &dto.RegisterRequest{Email: "JANE.DOE@example.com", Username: "someone", Password: "correct-horse"})
+	if err != ErrUserAlreadyExists {
+		t.Errorf("same email in other case: err = %v, want ErrUserAlreadyExists", err)
+	}
+
+	for _, identifier := range []string{"jane.doe@example.com", "JANE.DOE@EXAMPLE.COM", "JaneDoe", "janedoe", "JANEDOE"} {
+		resp, err := uc.Login(ctx, &dto.LoginRequest{EmailOrUsername: identifier, Password: "correct-horse"})
+		if err != nil {
+			t.Errorf("login as %q: %v", identifier, err)
+			continue
+		}
+		if resp.User.Username != "JaneDoe" {
+			t.Errorf("login as %q returned username %q", identifier, resp.User.Username)
+		}
+	}
+}
+
+func TestLoginAndRefreshReportDatabaseFailures(t *testing.T) {
+	uc, deps := newTestUseCase(t)
+	seedUser(t, uc, deps, &domain.User{Email: "jane@example.com", Username: "jane", IsVerified: true}, "correct-horse")
+	resp, err := uc.Login(context.Background(), &dto.LoginRequest{EmailOrUsername: "jane", Password: "correct-horse"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A database outage is not a wrong password or an unknown token
+	dbDown := errors.New("connection refused")
+	deps.users.lookupErr = dbDown
+	if _, err := uc.Login(context.Background(), &dto.LoginRequest{EmailOrUsername: "jane", Password: "correct-horse"}); !errors.Is(err, dbDown) {
+		t.Errorf("login: err = %v, want the database error", err)
+	}
+	deps.users.lookupErr = nil
+
+	deps.refreshTokens.lookupErr = dbDown
+	if _, err := uc.RefreshToken(context.Background(), resp.RefreshToken); !errors.Is(err, dbDown) {
+		t.Errorf("refresh: err = %v, want the database error", err)
+	}
+	deps.refreshTokens.lookupErr = nil
+
+	if _, err := uc.RefreshToken(context.Background(), "unknown"); err != ErrInvalidToken {
+		t.Errorf("unknown refresh token: err = %v, want ErrInvalidToken", err)
+	}
+}
@@ -0,0 +1,54 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+
+	"auth-service/internal/application/dto"
+)
+
+// CheckAvailability - Kayıt formu için email / username'in alınıp alınmadığını söyler
+// Register ile aynı kontroller: aynı organizasyon, username aynı şekilde normalize edilir,
+// rezerve veya karıştırılabilir username'ler de "alınmış" sayılır (Register zaten reddederdi).
+// Sadece istenen alanlar cevapta bulunur. Enumeration'a açık olduğu için route'ta sıkı rate limit var.
+func (uc *AuthUseCase) CheckAvailability(ctx context.Context, req *dto.AvailabilityRequest) (_ *dto.AvailabilityResponse, err error) {
+	defer translateContextError(ctx, &err)
+
+	// ADIM 1: Organizasyonu bul (Register gibi: slug yoksa varsayılan organizasyon)
+	orgID, err := uc.resolveOrganization(ctx, req.OrganizationSlug)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &dto.AvailabilityResponse{}
+
+	// ADIM 2: Email bu organizasyonda kullanılıyor mu?
+	if req.Email != "" {
+		exists, err := uc.userRepo.ExistsByEmail(ctx, orgID, req.Email)
+		if err != nil {
+			return nil, err
+		}
+		available := !exists
+		resp.EmailAvailable = &available
+	}
+
+	// ADIM 3: Username'i normalize et; izin verilmiyorsa alınmış say, yoksa harf büyüklüğünden bağımsız ara
+	if req.Username != "" {
+		available := false
+		username, err := uc.checkUsername(req.Username)
+		switch {
+		case errors.Is(err, ErrUsernameNotAllowed):
+		case err != nil:
+			return nil, err
+		default:
+			exists, err := uc.userRepo.ExistsByUsername(ctx, orgID, username)
+			if err != nil {
+				return nil, err
+			}
+			available = !exists
+		}
+		resp.UsernameAvailable = &available
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,62 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"auth-service/internal/application/dto"
+	"auth-service/internal/domain"
+)
+
+func TestCheckAvailability(t *testing.T) {
+	uc, deps := newTestUseCase(t)
+	seedUser(t, uc, deps, &domain.User{Email: "jane@example.com", Username: "Jane"}, "correct-horse")
+
+	tests := []struct {
+		name         string
+		req          dto.AvailabilityRequest
+		wantUsername *bool
+		wantEmail    *bool
+	}{
+		{"both free", dto.AvailabilityRequest{Username: "john", Email: "john@example.com"}, boolPtr(true), boolPtr(true)},
+		{"both taken", dto.AvailabilityRequest{Username: "Jane", Email: "jane@example.com"}, boolPtr(false), boolPtr(false)},
+		{"username differs only by case", dto.AvailabilityRequest{Username: "JANE"}, boolPtr(false), nil},
+		{"username normalized like Register", dto.AvailabilityRequest{Username: " jane "}, boolPtr(false), nil},
+		{"reserved username", dto.AvailabilityRequest{Username: "admin"}, boolPtr(false), nil},
+		{"email only", dto.AvailabilityRequest{Email: "john@example.com"}, nil, boolPtr(true)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := uc.CheckAvailability(context.Background(), &tt.req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !equalBoolPtr(resp.UsernameAvailable, tt.wantUsername) || !equalBoolPtr(resp.EmailAvailable, tt.wantEmail) {
+				t.Errorf("got username=%v email=%v, want %v %v",
+					fmtBoolPtr(resp.UsernameAvailable), fmtBoolPtr(resp.EmailAvailable), fmtBoolPtr(tt.wantUsername), fmtBoolPtr(tt.wantEmail))
+			}
+		})
+	}
+
+	_, err := uc.CheckAvailability(context.Background(), &dto.AvailabilityRequest{Email: "john@example.com", OrganizationSlug: "nope"})
+	if !errors.Is(err, ErrOrganizationNotFound) {
+		t.Errorf("unknown organization: got %v, want ErrOrganizationNotFound", err)
+	}
+}
+
+func boolPtr(b bool) *bool { return &b }
+
+func equalBoolPtr(a, b *bool) bool {
+	return (a == nil) == (b == nil) && (a == nil || *a == *b)
+}
+
+func fmtBoolPtr(b *bool) string {
+	if b == nil {
+		return "omitted"
+	}
+	if *b {
+		return "true"
+	}
+	return "false"
+}
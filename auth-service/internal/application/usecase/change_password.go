@@ -0,0 +1,60 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// ChangePassword - Giriş yapmış kullanıcının kendi şifresini değiştirmesi
+// Mevcut şifre doğrulanır; başarılı olursa tüm access token'lar geçersiz olur ve
+// mevcut oturum HARİÇ tüm refresh token'lar iptal edilir. Mevcut oturum ContextWithSessionID ile context'ten gelir;
+// context'te oturum yoksa tüm oturumlar kapatılır.
+func (uc *AuthUseCase) ChangePassword(ctx context.Context, userID uuid.UUID, oldPassword, newPassword string) (err error) {
+	defer translateContextError(ctx, &err)
+
+	// ADIM 1: Kullanıcıyı bul
+	user, err := uc.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return notFoundAs(err, ErrUserNotFound)
+	}
+
+	// ADIM 2: Mevcut şifreyi doğrula
+	if !uc.passwordHasher.Compare(user.PasswordHash, oldPassword) {
+		return ErrInvalidCredentials
+	}
+
+	// ADIM 3: Yeni şifre eskisiyle aynı olamaz ve policy'ye uymalı
+	if newPassword == oldPassword {
+		return ErrSamePassword
+	}
+	if err := uc.checkPasswordPolicy(ctx, newPassword, user.Email, user.Username); err != nil {
+		return err
+	}
+	// Son şifrelerden biri de olamaz (PasswordHistoryDepth)
+	if err := uc.checkPasswordHistory(ctx, user, newPassword); err != nil {
+		return err
+	}
+
+	// ADIM 4: Yeni şifreyi hash'le, kaydet ve geçmişe ekle
+	passwordHash, err := uc.passwordHasher.Hash(newPassword)
+	if err != nil {
+		return err
+	}
+	if err := uc.updatePassword(ctx, user, passwordHash); err != nil {
+		return err
+	}
+
+	uc.logAudit(ctx, AuditPasswordChanged, user.ID, map[string]string{"method": "change"})
+	uc.publishUserEvent(ctx, EventPasswordChanged, user, map[string]interface{}{"method": "change"})
+
+	// ADIM 5: Tüm access token'ları geçersiz kıl, diğer oturumları kapat
+	// Mevcut oturum açık kalır: access token'ı reddedilir ama refresh ile yenisini alır
+	if err := uc.invalidateAccessTokens(ctx, user.ID); err != nil {
+		return err
+	}
+	if sessionID, ok := sessionIDFromContext(ctx); ok {
+		return uc.refreshTokenRepo.RevokeAllByUserIDExcept(ctx, user.ID, sessionID)
+	}
+	return uc.refreshTokenRepo.RevokeAllByUserID(ctx, user.ID)
+}
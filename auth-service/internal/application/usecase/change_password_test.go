@@ -0,0 +1,116 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"auth-service/internal/application/dto"
+	"auth-service/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+// loginSession logs in and returns the session ID carried in the access token
+func loginSession(t *testing.T, uc *AuthUseCase, login, password string) uuid.UUID {
+	t.Helper()
+	resp, err := uc.Login(context.Background(), &dto.LoginRequest{EmailOrUsername: login, Password: password})
+	if err != nil {
+		t.Fatal(err)
+	}
+	claims, err := uc.jwtService.ValidateToken(resp.AccessToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sessionID, err := uuid.Parse(claims.SessionID)
+	if err != nil {
+		t.Fatalf("access token has no session id: %v", err)
+	}
+	return sessionID
+}
+
+func activeSessions(t *testing.T, deps *testDeps, userID uuid.UUID) map[uuid.UUID]bool {
+	t.Helper()
+	tokens, _ := deps.refreshTokens.GetByUserID(context.Background(), userID)
+	out := map[uuid.UUID]bool{}
+	for _, rt := range tokens {
+		out[rt.ID] = true
+	}
+	return out
+}
+
+func TestChangePasswordKeepsCurrentSession(t *testing.T) {
+	uc, deps := newTestUseCase(t)
+	user := seedUser(t, uc, deps, &domain.User{Email: "jane@example.com", Username: "jane", IsVerified: true}, "correct-horse")
+
+	current := loginSession(t, uc, "jane", "correct-horse")
+	other := loginSession(t, uc, "jane", "correct-horse")
+
+	ctx := ContextWithSessionID(context.Background(), current)
+	if err := uc.ChangePassword(ctx, user.ID, "correct-horse", "battery-staple"); err != nil {
+		t.Fatalf("ChangePassword: %v", err)
+	}
+
+	sessions := activeSessions(t, deps, user.ID)
+	if !sessions[current] {
+		t.Error("current session was revoked")
+	}
+	if sessions[other] {
+		t.Error("other session is still active")
+	}
+
+	stored, _ := deps.users.GetByID(context.Background(), user.ID)
+	if !uc.passwordHasher.Compare(stored.PasswordHash, "battery-staple") {
+		t.Error("new password not saved")
+	}
+}
+
+func TestChangePasswordWithoutSessionRevokesAll(t *testing.T) {
+	uc, deps := newTestUseCase(t)
+	user := seedUser(t, uc, deps, &domain.User{Email: "jane@example.com", Username: "jane", IsVerified: true}, "correct-horse")
+	loginSession(t, uc, "jane", "correct-horse")
+
+	if err := uc.ChangePassword(context.Background(), user.ID, "correct-horse", "battery-staple"); err != nil {
+		t.Fatal(err)
+	}
+	if n := len(activeSessions(t, deps, user.ID)); n != 0 {
+		t.Errorf("%d sessions still active", n)
+	}
+}
+
+func TestChangePasswordRejections(t *testing.T) {
+	tests := []struct {
+		name     string
+		old, new string
+		wantErr  error
+	}{
+		{"wrong current password", "wrong-password", "battery-staple", ErrInvalidCredentials},
+		{"same password", "correct-horse", "correct-horse", ErrSamePassword},
+		{"too short", "correct-horse", "short", ErrPasswordTooShort},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc, deps := newTestUseCase(t)
+			user := seedUser(t, uc, deps, &domain.User{Email: "jane@example.com", Username: "jane"}, "correct-horse")
+			session := loginSession(t, uc, "jane", "correct-horse")
+
+			if err := uc.ChangePassword(context.Background(), user.ID, tt.old, tt.new); err != tt.wantErr {
+				t.Fatalf("got %v, want %v", err, tt.wantErr)
+			}
+			if !activeSessions(t, deps, user.ID)[session] {
+				t.Error("sessions must be untouched on failure")
+			}
+			stored, _ := deps.users.GetByID(context.Background(), user.ID)
+			if !uc.passwordHasher.Compare(stored.PasswordHash, "correct-horse") {
+				t.Error("password must be unchanged on failure")
+			}
+		})
+	}
+}
+
+func TestChangePasswordUnknownUser(t *testing.T) {
+	uc, _ := newTestUseCase(t)
+	if err := uc.ChangePassword(context.Background(), uuid.New(), "a", "b"); err != ErrUserNotFound {
+		t.Errorf("got %v, want ErrUserNotFound", err)
+	}
+}
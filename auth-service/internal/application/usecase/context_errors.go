@@ -0,0 +1,13 @@
+package usecase
+
+import "context"
+
+// translateContextError - İstek context'i bittiyse (iptal / deadline) dönen hatayı ErrRequestTimeout yapar
+// Context bittikten sonra repository'lerden gelen hata ham context.Canceled olabileceği gibi
+// ErrUserNotFound gibi yanıltıcı bir hataya da çevrilmiş olabilir; ikisi de gerçek sebebi gizler.
+// Kullanım: named error sonucu olan metodun başında defer translateContextError(ctx, &err)
+func translateContextError(ctx context.Context, err *error) {
+	if *err != nil && ctx.Err() != nil {
+		*err = ErrRequestTimeout
+	}
+}
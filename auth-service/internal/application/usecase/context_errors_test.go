@@ -0,0 +1,56 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"auth-service/internal/application/dto"
+	"auth-service/internal/domain"
+)
+
+func TestTranslateContextError(t *testing.T) {
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		err  error
+		want error
+	}{
+		{"cancelled", cancelled, context.Canceled, ErrRequestTimeout},
+		{"masked by a use case error", cancelled, ErrUserNotFound, ErrRequestTimeout},
+		{"success after cancellation", cancelled, nil, nil},
+		{"live context", context.Background(), ErrUserNotFound, ErrUserNotFound},
+	}
+	for _, tt := range tests {
+		err := tt.err
+		translateContextError(tt.ctx, &err)
+		if !errors.Is(err, tt.want) || (tt.want == nil && err != nil) {
+			t.Errorf("%s: got %v, want %v", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestUseCaseErrorsAfterCancellation(t *testing.T) {
+	uc, deps := newTestUseCase(t)
+	seedUser(t, uc, deps, &domain.User{Email: "jane@example.com", Username: "jane"}, "correct-horse")
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := uc.Login(ctx, &dto.LoginRequest{EmailOrUsername: "nobody", Password: "correct-horse"})
+	if err != ErrRequestTimeout {
+		t.Errorf("login: got %v, want ErrRequestTimeout", err)
+	}
+	_, err = uc.Register(ctx, &dto.RegisterRequest{Email: "jane@example.com", Username: "jane", Password: "correct-horse"})
+	if err != ErrRequestTimeout {
+		t.Errorf("register: got %v, want ErrRequestTimeout", err)
+	}
+
+	// Without cancellation the real error comes through
+	_, err = uc.Login(context.Background(), &dto.LoginRequest{EmailOrUsername: "nobody", Password: "correct-horse"})
+	if err != ErrInvalidCredentials {
+		t.Errorf("login: got %v, want ErrInvalidCredentials", err)
+	}
+}
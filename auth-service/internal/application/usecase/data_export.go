@@ -0,0 +1,68 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"auth-service/internal/application/dto"
+	"auth-service/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+// exportAuditLogPageSize - Audit geçmişi export'ta bu büyüklükte sayfalarla okunur
+const exportAuditLogPageSize = 500
+
+// WithAuditLogHistory - Kayıtlı audit log'ların okunacağı repository
+// ExportUserData kullanıcının audit geçmişini buradan alır; verilmezse export'ta audit_logs boş döner.
+func WithAuditLogHistory(auditLogs domain.AuditLogRepository) AuthUseCaseOption {
+	return func(uc *AuthUseCase) {
+		uc.auditLogs = auditLogs
+	}
+}
+
+// ExportUserData - Kullanıcının kendisi hakkında saklanan verileri tek bir pakette döner (KVKK/GDPR)
+// Profil, aktif oturumlar (sadece cihaz bilgisi) ve kullanıcının aktör veya hedef olduğu audit kayıtları.
+// Şifre hash'i ve token değerleri hiçbir zaman dahil edilmez: DTO'larda bu alanlar yok.
+func (uc *AuthUseCase) ExportUserData(ctx context.Context, userID uuid.UUID) (_ *dto.UserDataExport, err error) {
+	defer translateContextError(ctx, &err)
+
+	// ADIM 1: Profil
+	user, err := uc.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, notFoundAs(err, ErrUserNotFound)
+	}
+
+	// ADIM 2: Aktif oturumlar (ListSessions ile aynı görünüm)
+	tokens, err := uc.refreshTokenRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	currentID, _ := sessionIDFromContext(ctx)
+
+	export := &dto.UserDataExport{
+		ExportedAt: time.Now().UTC(),
+		Profile:    toUserInfo(user),
+		Sessions:   sessionInfos(tokens, currentID, false),
+		AuditLogs:  []*dto.AuditLogInfo{},
+	}
+
+	// ADIM 3: Audit geçmişinin tamamı, sayfa sayfa (en yeni en üstte)
+	if uc.auditLogs == nil {
+		return export, nil
+	}
+	filter := domain.AuditLogFilter{UserID: userID}
+	for page := 1; ; page++ {
+		logs, total, err := uc.auditLogs.List(ctx, filter, page, exportAuditLogPageSize)
+		if err != nil {
+			return nil, err
+		}
+		for _, log := range logs {
+			export.AuditLogs = append(export.AuditLogs, toAuditLogInfo(log))
+		}
+		if len(logs) < exportAuditLogPageSize || int64(len(export.AuditLogs)) >= total {
+			break
+		}
+	}
+	return export, nil
+}
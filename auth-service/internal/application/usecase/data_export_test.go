@@ -0,0 +1,61 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"auth-service/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+func TestExportUserData(t *testing.T) {
+	auditLogs := &stubAuditLogRepo{}
+	uc, deps := newTestUseCaseWithConfig(t, testSecurityConfig(), WithAuditLogHistory(auditLogs))
+	user := seedUser(t, uc, deps, &domain.User{Email: "jane@example.com", Username: "jane", IsVerified: true}, "correct-horse")
+	tokens := loginTokens(t, uc)
+	auditLogs.logs = []*domain.AuditLog{
+		{ID: uuid.New(), Action: AuditLoginSuccess, ActorID: user.ID, TargetID: user.ID, IPAddress: "203.0.113.7"},
+	}
+
+	export, err := uc.ExportUserData(context.Background(), user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if export.Profile.Email != "jane@example.com" || len(export.Sessions) != 1 || len(export.AuditLogs) != 1 {
+		t.Errorf("export = %+v", export)
+	}
+	if auditLogs.filter.UserID != user.ID {
+		t.Errorf("audit log filter = %+v", auditLogs.filter)
+	}
+
+	body, err := json.Marshal(export)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stored, _ := deps.users.GetByID(context.Background(), user.ID)
+	for _, secret := range []string{stored.PasswordHash, tokens.RefreshToken, tokens.AccessToken} {
+		if strings.Contains(string(body), secret) {
+			t.Errorf("export contains a secret: %s", body)
+		}
+	}
+}
+
+func TestExportUserDataWithoutAuditHistory(t *testing.T) {
+	uc, deps := newTestUseCase(t)
+	user := seedUser(t, uc, deps, &domain.User{Email: "jane@example.com", Username: "jane"}, "correct-horse")
+
+	export, err := uc.ExportUserData(context.Background(), user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if export.AuditLogs == nil || len(export.AuditLogs) != 0 || len(export.Sessions) != 0 {
+		t.Errorf("export = %+v", export)
+	}
+
+	if _, err := uc.ExportUserData(context.Background(), uuid.New()); err != ErrUserNotFound {
+		t.Errorf("unknown user: err = %v", err)
+	}
+}
@@ -0,0 +1,135 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"auth-service/internal/domain"
+	"auth-service/pkg/security"
+
+	"github.com/google/uuid"
+)
+
+// GenerateEmailVerification - Kullanıcı için tek kullanımlık email doğrulama token'ı oluşturur
+// ve doğrulama link'ini mailer ile gönderir.
+// Kullanıcının önceki (kullanılmamış) token'ları silinir: sadece en son link geçerlidir.
+// Plaintext token döndürülür; veritabanına sadece hash'i yazılır.
+// Aynı kullanıcıya VerificationResendCooldown dolmadan ikinci mail gönderilmez (ErrTooManyRequests):
+// aksi halde biri kurbanın email'iyle kayıt olup resend'i döngüde çağırarak inbox'ını doldurabilirdi.
+func (uc *AuthUseCase) GenerateEmailVerification(ctx context.Context, userID uuid.UUID) (_ string, err error) {
+	defer translateContextError(ctx, &err)
+
+	// ADIM 1: Kullanıcıyı bul
+	user, err := uc.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return "", notFoundAs(err, ErrUserNotFound)
+	}
+
+	// ADIM 2: Zaten doğrulanmışsa yeni token'a gerek yok
+	if user.IsVerified {
+		return "", ErrAlreadyVerified
+	}
+
+	// ADIM 3: Gönderim hakkını atomik olarak sahiplen (MarkVerificationSent koşullu UPDATE'tir):
+	// eşzamanlı iki istekten sadece biri mail gönderir
+	now := time.Now()
+	claimed, err := uc.userRepo.MarkVerificationSent(ctx, user.ID, now, now.Add(-uc.securityCfg.VerificationResendCooldown))
+	if err != nil {
+		return "", err
+	}
+	if !claimed {
+		return "", ErrTooManyRequests
+	}
+
+	// ADIM 4: Eski token'ları geçersiz kıl
+	if err := uc.verificationRepo.DeleteByUserID(ctx, user.ID); err != nil {
+		return "", err
+	}
+
+	// ADIM 5: Yeni random token üret ve hash'ini sakla
+	token, err := security.GenerateOpaqueToken(uc.securityCfg.VerificationToken.Bytes)
+	if err != nil {
+		return "", err
+	}
+	verificationToken := &domain.VerificationToken{
+		UserID:    user.ID,
+		TokenHash: security.HashToken(token),
+		ExpiresAt: time.Now().Add(uc.securityCfg.VerificationToken.TTL),
+	}
+	if err := uc.verificationRepo.Create(ctx, verificationToken); err != nil {
+		return "", err
+	}
+
+	// ADIM 6: Doğrulama link'ini gönder
+	if err := uc.mailer.Send(ctx, user.Email, MailTemplateVerifyEmail, map[string]any{
+		"Username":  user.Username,
+		"Link":      fmt.Sprintf("%s/verify-email?token=%s", uc.linkBaseURL, url.QueryEscape(token)),
+		"ExpiresIn": uc.securityCfg.VerificationToken.TTL,
+	}); err != nil {
+		return "", err
+	}
+
+	return token, nil
+}
+
+// ResendVerification - Doğrulama mail'ini giriş yapmadan, email adresiyle tekrar gönderir
+// Şifre sıfırlama gibi, email kayıtlı değilse, hesap pasifse veya zaten doğrulanmışsa da nil döner:
+// endpoint'ten hangi email'lerin kayıtlı olduğu öğrenilemez.
+// Aynı kullanıcıya VerificationResendCooldown dolmadan ikinci mail gönderilmez; böylece endpoint
+// bir kurbanın inbox'ını doldurmak için kullanılamaz. Bu durumda da nil döner: hata (429) dönülseydi
+// iki hızlı istekle email'in kayıtlı olduğu anlaşılırdı.
+func (uc *AuthUseCase) ResendVerification(ctx context.Context, orgSlug, email string) (err error) {
+	defer translateContextError(ctx, &err)
+
+	// ADIM 1: Kullanıcıyı organizasyonunda bul - bulunamazsa sessizce başarılı dön
+	orgID, err := uc.resolveOrganization(ctx, orgSlug)
+	if errors.Is(err, ErrOrganizationNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	user, err := uc.userRepo.GetByEmail(ctx, orgID, email)
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !user.IsActive || user.IsVerified {
+		return nil
+	}
+
+	// ADIM 2: Yeni link'i gönder (eski link'ler geçersiz olur)
+	// Cooldown içindeyse mail gönderilmez ve yine sessizce başarılı dönülür
+	_, err = uc.GenerateEmailVerification(ctx, user.ID)
+	if err != nil && err != ErrAlreadyVerified && err != ErrTooManyRequests {
+		return err
+	}
+	return nil
+}
+
+// VerifyEmail - Doğrulama token'ını tüketir ve kullanıcının email'ini doğrulanmış işaretler
+// Token atomik olarak tüketilir: aynı link ikinci kez kullanılamaz.
+func (uc *AuthUseCase) VerifyEmail(ctx context.Context, token string) (err error) {
+	defer translateContextError(ctx, &err)
+
+	// ADIM 1: Token'ı tüket (kullanılmamış ve süresi dolmamış olmalı)
+	verificationToken, err := uc.verificationRepo.Consume(ctx, security.HashToken(token))
+	if err != nil {
+		return notFoundAs(err, ErrInvalidToken)
+	}
+
+	// ADIM 2: Token'ın sahibini bul
+	user, err := uc.userRepo.GetByID(ctx, verificationToken.UserID)
+	if err != nil {
+		return notFoundAs(err, ErrUserNotFound)
+	}
+
+	// ADIM 3: Email'i doğrulanmış olarak işaretle
+	user.IsVerified = true
+	return uc.userRepo.Update(ctx, user)
+}
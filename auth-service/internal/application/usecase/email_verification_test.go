@@ -0,0 +1,220 @@
+package usecase
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"auth-service/internal/application/dto"
+	"auth-service/internal/domain"
+	"auth-service/internal/infrastructure/mailer"
+	"auth-service/pkg/security"
+
+	"github.com/google/uuid"
+)
+
+// tokenFromMail extracts the token from the link in a captured email
+// sent with the given template
+func tokenFromMail(t *testing.T, m mailer.SentMail, template, path string) string {
+	t.Helper()
+	if m.Template != template {
+		t.Fatalf("template = %q, want %q", m.Template, template)
+	}
+	link, _ := m.Data["Link"].(string)
+	if !strings.HasPrefix(link, "https://app.example.com"+path+"?") {
+		t.Fatalf("link = %q, want a %s link", link, path)
+	}
+	u, err := url.Parse(link)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return u.Query().Get("token")
+}
+
+func TestRegisterSendsVerificationEmail(t *testing.T) {
+	uc, deps := newTestUseCase(t)
+	ctx := context.Background()
+
+	_, err := uc.Register(ctx, &dto.RegisterRequest{
+		Email:     "jane@example.com",
+		Username:  "jane",
+		Password:  "correct-horse",
+		FirstName: "Jane",
+		LastName:  "Doe",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mail, ok := deps.mailer.Last()
+	if !ok {
+		t.Fatal("expected a verification email")
+	}
+	if mail.To != "jane@example.com" {
+		t.Errorf("mail sent to %q", mail.To)
+	}
+
+	if err := uc.VerifyEmail(ctx, tokenFromMail(t, mail, MailTemplateVerifyEmail, "/verify-email")); err != nil {
+		t.Fatalf("VerifyEmail: %v", err)
+	}
+	user, _ := deps.users.GetByEmail(ctx, uuid.Nil, "jane@example.com")
+	if !user.IsVerified {
+		t.Error("user should be verified")
+	}
+}
+
+func TestVerifyEmailTokenIsSingleUse(t *testing.T) {
+	uc, deps := newTestUseCase(t)
+	ctx := context.Background()
+	user := seedUser(t, uc, deps, &domain.User{Email: "jane@example.com", Username: "jane"}, "correct-horse")
+
+	token, err := uc.GenerateEmailVerification(ctx, user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := uc.VerifyEmail(ctx, token); err != nil {
+		t.Fatalf("first use: %v", err)
+	}
+	if err := uc.VerifyEmail(ctx, token); err != ErrInvalidToken {
+		t.Errorf("second use: got %v, want ErrInvalidToken", err)
+	}
+}
+
+func TestVerifyEmailRejectsExpiredAndUnknownTokens(t *testing.T) {
+	uc, deps := newTestUseCase(t)
+	ctx := context.Background()
+	user := seedUser(t, uc, deps, &domain.User{Email: "jane@example.com", Username: "jane"}, "correct-horse")
+
+	deps.verifications.Create(ctx, &domain.VerificationToken{
+		UserID:    user.ID,
+		TokenHash: security.HashToken("expired"),
+		ExpiresAt: time.Now().Add(-time.Minute),
+	})
+
+	for _, token := range []string{"expired", "unknown"} {
+		if err := uc.VerifyEmail(ctx, token); err != ErrInvalidToken {
+			t.Errorf("%s: got %v, want ErrInvalidToken", token, err)
+		}
+	}
+	stored, _ := deps.users.GetByID(ctx, user.ID)
+	if stored.IsVerified {
+		t.Error("user must stay unverified")
+	}
+}
+
+func TestGenerateEmailVerificationInvalidatesOlderTokens(t *testing.T) {
+	cfg := testSecurityConfig()
+	cfg.VerificationResendCooldown = 0
+	uc, deps := newTestUseCaseWithConfig(t, cfg)
+	ctx := context.Background()
+	user := seedUser(t, uc, deps, &domain.User{Email: "jane@example.com", Username: "jane"}, "correct-horse")
+
+	first, err := uc.GenerateEmailVerification(ctx, user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := uc.GenerateEmailVerification(ctx, user.ID); err != nil {
+		t.Fatal(err)
+	}
+	if err := uc.VerifyEmail(ctx, first); err != ErrInvalidToken {
+		t.Errorf("old token: got %v, want ErrInvalidToken", err)
+	}
+}
+
+func TestGenerateEmailVerificationAlreadyVerified(t *testing.T) {
+	uc, deps := newTestUseCase(t)
+	user := seedUser(t, uc, deps, &domain.User{Email: "jane@example.com", Username: "jane", IsVerified: true}, "correct-horse")
+
+	if _, err := uc.GenerateEmailVerification(context.Background(), user.ID); err != ErrAlreadyVerified {
+		t.Errorf("got %v, want ErrAlreadyVerified", err)
+	}
+	if _, ok := deps.mailer.Last(); ok {
+		t.Error("no email should be sent")
+	}
+}
+
+func TestResendVerificationCooldown(t *testing.T) {
+	cooldown := testSecurityConfig().VerificationResendCooldown
+	tests := []struct {
+		name     string
+		lastSent time.Duration // how long ago the previous email went out; 0 means never
+		wantSent bool
+	}{
+		{"never sent", 0, true},
+		{"inside the cooldown", cooldown - time.Second, false},
+		{"cooldown elapsed", cooldown + time.Second, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc, deps := newTestUseCase(t)
+			ctx := context.Background()
+			user := &domain.User{Email: "jane@example.com", Username: "jane"}
+			if tt.lastSent > 0 {
+				sentAt := time.Now().Add(-tt.lastSent)
+				user.VerificationSentAt = &sentAt
+			}
+			seedUser(t, uc, deps, user, "correct-horse")
+
+			// Inside the cooldown the answer is the same as for an unknown email
+			if err := uc.ResendVerification(ctx, "", "jane@example.com"); err != nil {
+				t.Fatalf("got %v, want nil", err)
+			}
+			if _, sent := deps.mailer.Last(); sent != tt.wantSent {
+				t.Errorf("email sent = %v, want %v", sent, tt.wantSent)
+			}
+		})
+	}
+}
+
+func TestGenerateEmailVerificationCooldown(t *testing.T) {
+	uc, deps := newTestUseCase(t)
+	ctx := context.Background()
+	user := seedUser(t, uc, deps, &domain.User{Email: "jane@example.com", Username: "jane"}, "correct-horse")
+
+	// The signed-in resend shares the cooldown; concurrent requests send one email
+	var wg sync.WaitGroup
+	errs := make(chan error, 5)
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := uc.GenerateEmailVerification(ctx, user.ID)
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	sent := 0
+	for err := range errs {
+		switch err {
+		case nil:
+			sent++
+		case ErrTooManyRequests:
+		default:
+			t.Fatal(err)
+		}
+	}
+	if sent != 1 || len(deps.mailer.Sent()) != 1 {
+		t.Errorf("successful calls = %d, emails = %d; want 1, 1", sent, len(deps.mailer.Sent()))
+	}
+}
+
+func TestResendVerificationIsSilentForUnknownAndVerifiedAccounts(t *testing.T) {
+	uc, deps := newTestUseCase(t)
+	ctx := context.Background()
+	seedUser(t, uc, deps, &domain.User{Email: "jane@example.com", Username: "jane", IsVerified: true}, "correct-horse")
+
+	for _, email := range []string{"jane@example.com", "nobody@example.com"} {
+		if err := uc.ResendVerification(ctx, "", email); err != nil {
+			t.Errorf("%s: got %v, want nil", email, err)
+		}
+	}
+	if _, ok := deps.mailer.Last(); ok {
+		t.Error("no email should be sent")
+	}
+}
@@ -0,0 +1,57 @@
+package usecase
+
+import "errors"
+
+// AppError - Use case'lerin döndürdüğü, API'ye olduğu gibi yansıyan hata
+// Handler'lar hata başına switch yazmaz: respondError Status, Code, Message ve
+// Details'i response'a yazar; AppError olmayan her hata 500 internal_error olur.
+// Sentinel'ler (ErrInvalidCredentials vs.) pointer olduğu için errors.Is ve
+// "switch err" eskisi gibi çalışır.
+type AppError struct {
+	Code    string            // ErrorResponse.Error (örn: "invalid_credentials"), GET /errors'ta listelenir
+	Message string            // Kullanıcıya gösterilebilecek açıklama
+	Status  int               // HTTP status
+	Details map[string]string // Opsiyonel ek bilgi (örn: ihlal edilen şifre kuralı)
+
+	// base - WithDetails ile türetildiyse kaynak sentinel (errors.Is için)
+	base *AppError
+}
+
+func newAppError(status int, code, message string) *AppError {
+	return &AppError{Code: code, Message: message, Status: status}
+}
+
+func (e *AppError) Error() string { return e.Message }
+
+func (e *AppError) Unwrap() error {
+	if e.base == nil {
+		return nil
+	}
+	return e.base
+}
+
+// WithDetails - Aynı kodla, bu olaya özel mesaj ve detaylar taşıyan kopya
+// errors.Is(kopya, e) true döner.
+func (e *AppError) WithDetails(message string, details map[string]string) *AppError {
+	return &AppError{Code: e.Code, Message: message, Status: e.Status, Details: details, base: e}
+}
+
+// appErrorCarrier - Kendi verisini (kural, sebep) AppError'a çevirebilen hata tipleri
+// (PasswordRuleError, PasswordStrengthError, UsernameNotAllowedError)
+type appErrorCarrier interface {
+	AppError() *AppError
+}
+
+// AsAppError - err zincirindeki AppError; yoksa nil (beklenmeyen hata -> 500)
+// Detay taşıyan hata tipleri sentinel'lerinden önce gelir.
+func AsAppError(err error) *AppError {
+	var carrier appErrorCarrier
+	if errors.As(err, &carrier) {
+		return carrier.AppError()
+	}
+	var appErr *AppError
+	if errors.As(err, &appErr) {
+		return appErr
+	}
+	return nil
+}
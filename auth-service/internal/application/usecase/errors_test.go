@@ -0,0 +1,29 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestAsAppError(t *testing.T) {
+	if got := AsAppError(fmt.Errorf("login: %w", ErrAccountLocked)); got != ErrAccountLocked {
+		t.Errorf("wrapped sentinel: got %v", got)
+	}
+	if got := AsAppError(errors.New("database down")); got != nil {
+		t.Errorf("unknown error: got %v, want nil", got)
+	}
+
+	// Typed errors contribute their own message and details
+	err := fmt.Errorf("register: %w", &UsernameNotAllowedError{Reason: "reserved", Message: "This username is reserved"})
+	got := AsAppError(err)
+	if got == nil || got.Code != "username_not_allowed" || got.Message != "This username is reserved" || got.Details["reason"] != "reserved" {
+		t.Fatalf("username error: got %+v", got)
+	}
+	if !errors.Is(got, ErrUsernameNotAllowed) {
+		t.Error("detailed copy does not match its sentinel")
+	}
+	if errors.Is(got, ErrWeakPassword) {
+		t.Error("detailed copy matches another sentinel")
+	}
+}
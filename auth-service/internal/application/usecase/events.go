@@ -0,0 +1,35 @@
+package usecase
+
+import (
+	"context"
+
+	"auth-service/internal/domain"
+)
+
+// Webhook olayları - AuthUseCase'in EventPublisher'a gönderdiği hesap olayları
+// (Admin işlemleri "user.approved", "user.banned" gibi kendi olaylarını gönderir)
+const (
+	EventUserRegistered  = "user.registered"
+	EventUserDeactivated = "user.deactivated"
+	EventUserDeleted     = "user.deleted"
+	EventPasswordChanged = "password.changed"
+	EventUsernameChanged = "username.changed"
+)
+
+// publishUserEvent - Kullanıcıyla ilgili bir olayı yayınlar (best-effort)
+// Gönderim hatası işlemi başarısız yapmaz, sadece loglanır.
+// extra: olaya özel ek alanlar (örn: şifrenin nasıl değiştiği)
+func (uc *AuthUseCase) publishUserEvent(ctx context.Context, event string, user *domain.User, extra map[string]interface{}) {
+	data := map[string]interface{}{
+		"user_id":         user.ID.String(),
+		"organization_id": user.OrganizationID.String(),
+		"email":           user.Email,
+		"username":        user.Username,
+	}
+	for k, v := range extra {
+		data[k] = v
+	}
+	if err := uc.events.Publish(ctx, event, data); err != nil {
+		uc.logError(ctx, "publish "+event, err, "user_id", user.ID)
+	}
+}
@@ -0,0 +1,89 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"auth-service/internal/domain"
+)
+
+// lastEvent returns the last published event, failing the test if it is not named want
+func lastEvent(t *testing.T, deps *testDeps, want string) publishedEvent {
+	t.Helper()
+	deps.events.mu.Lock()
+	defer deps.events.mu.Unlock()
+	if len(deps.events.events) == 0 {
+		t.Fatalf("no events published, want %s", want)
+	}
+	got := deps.events.events[len(deps.events.events)-1]
+	if got.Event != want {
+		t.Fatalf("last event = %s, want %s", got.Event, want)
+	}
+	return got
+}
+
+func TestRegisterPublishesUserRegistered(t *testing.T) {
+	uc, deps := newTestUseCase(t)
+
+	resp := registerPending(t, uc)
+
+	got := lastEvent(t, deps, EventUserRegistered)
+	if got.Data["user_id"] != resp.User.ID || got.Data["email"] != "jane@example.com" || got.Data["method"] != "password" {
+		t.Errorf("data = %v", got.Data)
+	}
+}
+
+func TestRegisterSucceedsWhenPublishFails(t *testing.T) {
+	uc, deps := newTestUseCase(t)
+	deps.events.err = errors.New("webhook down")
+
+	if resp := registerPending(t, uc); resp.AccessToken == "" {
+		t.Errorf("response = %+v, want tokens", resp)
+	}
+}
+
+func TestDeactivateAccountPublishesUserDeactivated(t *testing.T) {
+	uc, deps := newTestUseCase(t)
+	user := seedUser(t, uc, deps, &domain.User{Email: "jane@example.com", Username: "jane", IsVerified: true}, "correct-horse")
+
+	if err := uc.DeactivateAccount(context.Background(), user.ID, "correct-horse"); err != nil {
+		t.Fatal(err)
+	}
+	if got := lastEvent(t, deps, EventUserDeactivated); got.Data["user_id"] != user.ID.String() {
+		t.Errorf("data = %v", got.Data)
+	}
+}
+
+func TestPasswordChangesPublishPasswordChanged(t *testing.T) {
+	ctx := context.Background()
+	uc, deps := newTestUseCase(t)
+	user := seedUser(t, uc, deps, &domain.User{Email: "jane@example.com", Username: "jane", IsVerified: true}, "correct-horse")
+
+	if err := uc.ChangePassword(ctx, user.ID, "correct-horse", "battery-staple"); err != nil {
+		t.Fatal(err)
+	}
+	if got := lastEvent(t, deps, EventPasswordChanged); got.Data["method"] != "change" || got.Data["user_id"] != user.ID.String() {
+		t.Errorf("change: data = %v", got.Data)
+	}
+
+	token := requestResetToken(t, uc, deps, "jane@example.com")
+	if err := uc.ResetPassword(ctx, token, "another-staple"); err != nil {
+		t.Fatal(err)
+	}
+	if got := lastEvent(t, deps, EventPasswordChanged); got.Data["method"] != "reset" {
+		t.Errorf("reset: data = %v", got.Data)
+	}
+}
+
+func TestFailedPasswordChangePublishesNothing(t *testing.T) {
+	uc, deps := newTestUseCase(t)
+	user := seedUser(t, uc, deps, &domain.User{Email: "jane@example.com", Username: "jane", IsVerified: true}, "correct-horse")
+
+	if err := uc.ChangePassword(context.Background(), user.ID, "wrong", "battery-staple"); err != ErrInvalidCredentials {
+		t.Fatalf("err = %v", err)
+	}
+	if names := deps.events.names(); len(names) != 0 {
+		t.Errorf("events = %v", names)
+	}
+}
@@ -0,0 +1,975 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"auth-service/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+type fakeUserRepo struct {
+	mu        sync.Mutex
+	users     map[uuid.UUID]*domain.User
+	lookupErr error // Returned by every lookup, like a database that is down
+}
+
+func newFakeUserRepo() *fakeUserRepo {
+	return &fakeUserRepo{users: map[uuid.UUID]*domain.User{}}
+}
+
+func (r *fakeUserRepo) Create(ctx context.Context, user *domain.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if user.ID == uuid.Nil {
+		user.ID = uuid.New()
+	}
+	if user.CreatedAt.IsZero() {
+		user.CreatedAt = time.Now()
+	}
+	user.Email = domain.NormalizeEmail(user.Email)
+	u := *user
+	r.users[user.ID] = &u
+	return nil
+}
+
+func (r *fakeUserRepo) find(match func(*domain.User) bool) (*domain.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.lookupErr != nil {
+		return nil, r.lookupErr
+	}
+	for _, u := range r.users {
+		// Like the real repository, deleted users are never found
+		if !u.IsDeleted() && match(u) {
+			c := *u
+			return &c, nil
+		}
+	}
+	return nil, domain.ErrNotFound
+}
+
+func (r *fakeUserRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.User, error) {
+	return r.find(func(u *domain.User) bool { return u.ID == id })
+}
+
+func (r *fakeUserRepo) GetByEmail(ctx context.Context, orgID uuid.UUID, email string) (*domain.User, error) {
+	return r.find(func(u *domain.User) bool { return u.OrganizationID == orgID && u.Email == domain.NormalizeEmail(email) })
+}
+
+func (r *fakeUserRepo) GetByUsername(ctx context.Context, orgID uuid.UUID, username string) (*domain.User, error) {
+	return r.find(func(u *domain.User) bool { return u.OrganizationID == orgID && u.Username == username })
+}
+
+// GetByEmailOrUsername prefers an email match, then an exact username, and
+// ignores case for both, like the GORM repository
+func (r *fakeUserRepo) GetByEmailOrUsername(ctx context.Context, orgID uuid.UUID, identifier string) (*domain.User, error) {
+	if user, err := r.GetByEmail(ctx, orgID, identifier); err == nil {
+		return user, nil
+	}
+	if user, err := r.GetByUsername(ctx, orgID, identifier); err == nil {
+		return user, nil
+	}
+	return r.find(func(u *domain.User) bool {
+		return u.OrganizationID == orgID && strings.EqualFold(u.Username, identifier)
+	})
+}
+
+func (r *fakeUserRepo) GetByPhone(ctx context.Context, orgID uuid.UUID, phone string) (*domain.User, error) {
+	if phone == "" {
+		return nil, domain.ErrNotFound
+	}
+	return r.find(func(u *domain.User) bool { return u.OrganizationID == orgID && u.Phone == phone })
+}
+
+func (r *fakeUserRepo) BulkCreate(ctx context.Context, users []*domain.User) error {
+	for _, user := range users {
+		if err := r.Create(ctx, user); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Update keeps the stored TokenVersion, like the GORM repository
+func (r *fakeUserRepo) Update(ctx context.Context, user *domain.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	user.Email = domain.NormalizeEmail(user.Email)
+	u := *user
+	if stored, ok := r.users[user.ID]; ok {
+		u.TokenVersion = stored.TokenVersion
+	}
+	r.users[user.ID] = &u
+	return nil
+}
+
+func (r *fakeUserRepo) Delete(ctx context.Context, id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.users, id)
+	return nil
+}
+
+func (r *fakeUserRepo) ExistsByEmail(ctx context.Context, orgID uuid.UUID, email string) (bool, error) {
+	_, err := r.GetByEmail(ctx, orgID, email)
+	return err == nil, nil
+}
+
+// ExistsByUsername ignores case, like the GORM repository
+func (r *fakeUserRepo) ExistsByUsername(ctx context.Context, orgID uuid.UUID, username string) (bool, error) {
+	_, err := r.find(func(u *domain.User) bool { return u.OrganizationID == orgID && strings.EqualFold(u.Username, username) })
+	return err == nil, nil
+}
+
+func (r *fakeUserRepo) UpdateLastLogin(ctx context.Context, id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if u, ok := r.users[id]; ok {
+		now := time.Now()
+		u.LastLoginAt = &now
+	}
+	return nil
+}
+
+func (r *fakeUserRepo) RecordFailedLogin(ctx context.Context, id uuid.UUID) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	u, ok := r.users[id]
+	if !ok {
+		return 0, domain.ErrNotFound
+	}
+	u.FailedLoginAttempts++
+	return u.FailedLoginAttempts, nil
+}
+
+func (r *fakeUserRepo) GetTokenVersion(ctx context.Context, id uuid.UUID) (int, error) {
+	user, err := r.GetByID(ctx, id)
+	if err != nil {
+		return 0, err
+	}
+	return user.TokenVersion, nil
+}
+
+func (r *fakeUserRepo) IncrementTokenVersion(ctx context.Context, id uuid.UUID) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	u, ok := r.users[id]
+	if !ok {
+		return 0, domain.ErrNotFound
+	}
+	u.TokenVersion++
+	return u.TokenVersion, nil
+}
+
+func (r *fakeUserRepo) SetLockout(ctx context.Context, id uuid.UUID, until *time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if u, ok := r.users[id]; ok {
+		u.FailedLoginAttempts = 0
+		u.LockedUntil = until
+	}
+	return nil
+}
+
+func (r *fakeUserRepo) MarkLockoutNotified(ctx context.Context, id uuid.UUID, at, since time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	u, ok := r.users[id]
+	if !ok || (u.LockoutNotifiedAt != nil && u.LockoutNotifiedAt.After(since)) {
+		return false, nil
+	}
+	u.LockoutNotifiedAt = &at
+	return true, nil
+}
+
+func (r *fakeUserRepo) MarkVerificationSent(ctx context.Context, id uuid.UUID, at, since time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	u, ok := r.users[id]
+	if !ok || (u.VerificationSentAt != nil && u.VerificationSentAt.After(since)) {
+		return false, nil
+	}
+	u.VerificationSentAt = &at
+	return true, nil
+}
+
+func (r *fakeUserRepo) MarkVerified(ctx context.Context, ids []uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, id := range ids {
+		if u, ok := r.users[id]; ok {
+			u.IsVerified = true
+		}
+	}
+	return nil
+}
+
+func (r *fakeUserRepo) List(ctx context.Context, filter domain.UserFilter) ([]*domain.User, int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	search := strings.ToLower(filter.Search)
+	var matches []*domain.User
+	for _, u := range r.users {
+		switch {
+		case u.IsDeleted(),
+			u.OrganizationID != filter.OrganizationID,
+			filter.Role != "" && u.Role != filter.Role,
+			filter.IsActive != nil && u.IsActive != *filter.IsActive,
+			filter.IsVerified != nil && u.IsVerified != *filter.IsVerified,
+			search != "" && !strings.Contains(strings.ToLower(u.Email), search) &&
+				!strings.Contains(strings.ToLower(u.Username), search):
+			continue
+		}
+		c := *u
+		matches = append(matches, &c)
+	}
+	sort.Slice(matches, func(i, j int) bool { return matches[i].CreatedAt.Before(matches[j].CreatedAt) })
+
+	start := (filter.Page - 1) * filter.PageSize
+	if start > len(matches) {
+		start = len(matches)
+	}
+	end := start + filter.PageSize
+	if end > len(matches) {
+		end = len(matches)
+	}
+	return matches[start:end], int64(len(matches)), nil
+}
+
+type fakeRefreshTokenRepo struct {
+	mu        sync.Mutex
+	tokens    []*domain.RefreshToken
+	createErr error // Returned by Create without storing the token
+	lookupErr error // Returned by GetByTokenHashIncludingRevoked
+}
+
+func newFakeRefreshTokenRepo() *fakeRefreshTokenRepo {
+	return &fakeRefreshTokenRepo{}
+}
+
+func (r *fakeRefreshTokenRepo) Create(ctx context.Context, token *domain.RefreshToken) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.createErr != nil {
+		return r.createErr
+	}
+	if token.ID == uuid.Nil {
+		token.ID = uuid.New()
+	}
+	if token.CreatedAt.IsZero() {
+		token.CreatedAt = time.Now()
+	}
+	t := *token
+	r.tokens = append(r.tokens, &t)
+	return nil
+}
+
+func (r *fakeRefreshTokenRepo) HasSeenDevice(ctx context.Context, userID uuid.UUID, fingerprint string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, t := range r.tokens {
+		if t.UserID == userID && t.DeviceFingerprint == fingerprint {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *fakeRefreshTokenRepo) GetByTokenHash(ctx context.Context, tokenHash string) (*domain.RefreshToken, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, t := range r.tokens {
+		if t.TokenHash == tokenHash && !t.IsRevoked {
+			c := *t
+			return &c, nil
+		}
+	}
+	return nil, domain.ErrNotFound
+}
+
+func (r *fakeRefreshTokenRepo) GetByTokenHashIncludingRevoked(ctx context.Context, tokenHash string) (*domain.RefreshToken, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.lookupErr != nil {
+		return nil, r.lookupErr
+	}
+	for _, t := range r.tokens {
+		if t.TokenHash == tokenHash {
+			c := *t
+			return &c, nil
+		}
+	}
+	return nil, domain.ErrNotFound
+}
+
+func (r *fakeRefreshTokenRepo) GetByUserID(ctx context.Context, userID uuid.UUID) ([]*domain.RefreshToken, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []*domain.RefreshToken
+	for _, t := range r.tokens {
+		if t.UserID == userID && !t.IsRevoked {
+			c := *t
+			out = append(out, &c)
+		}
+	}
+	return out, nil
+}
+
+func (r *fakeRefreshTokenRepo) GetByUserIDIncludingRevoked(ctx context.Context, userID uuid.UUID) ([]*domain.RefreshToken, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []*domain.RefreshToken
+	for _, t := range r.tokens {
+		if t.UserID == userID {
+			c := *t
+			out = append(out, &c)
+		}
+	}
+	return out, nil
+}
+
+func (r *fakeRefreshTokenRepo) ActiveCount(ctx context.Context, userID uuid.UUID) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var count int64
+	for _, t := range r.tokens {
+		if t.UserID == userID && t.IsValid() {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (r *fakeRefreshTokenRepo) RevokeOldest(ctx context.Context, userID uuid.UUID, keep int) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var active []*domain.RefreshToken
+	for _, t := range r.tokens {
+		if t.UserID == userID && t.IsValid() {
+			active = append(active, t)
+		}
+	}
+	// Newest first; tokens created in the same instant keep insertion order
+	sort.SliceStable(active, func(i, j int) bool { return active[i].CreatedAt.After(active[j].CreatedAt) })
+	var revoked int64
+	for i := keep; i < len(active); i++ {
+		active[i].IsRevoked = true
+		revoked++
+	}
+	return revoked, nil
+}
+
+func (r *fakeRefreshTokenRepo) GetIssuedSince(ctx context.Context, userID uuid.UUID, since time.Time) ([]*domain.RefreshToken, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []*domain.RefreshToken
+	for _, t := range r.tokens {
+		if t.UserID == userID && t.CreatedAt.After(since) {
+			c := *t
+			out = append(out, &c)
+		}
+	}
+	return out, nil
+}
+
+func (r *fakeRefreshTokenRepo) Revoke(ctx context.Context, tokenHash string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, t := range r.tokens {
+		if t.TokenHash == tokenHash {
+			t.IsRevoked = true
+		}
+	}
+	return nil
+}
+
+func (r *fakeRefreshTokenRepo) RevokeAllByUserID(ctx context.Context, userID uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, t := range r.tokens {
+		if t.UserID == userID {
+			t.IsRevoked = true
+		}
+	}
+	return nil
+}
+
+func (r *fakeRefreshTokenRepo) RevokeAllByUserIDExcept(ctx context.Context, userID, keepID uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, t := range r.tokens {
+		if t.UserID == userID && t.ID != keepID {
+			t.IsRevoked = true
+		}
+	}
+	return nil
+}
+
+func (r *fakeRefreshTokenRepo) RevokeFamily(ctx context.Context, familyID uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, t := range r.tokens {
+		if t.FamilyID == familyID {
+			t.IsRevoked = true
+		}
+	}
+	return nil
+}
+
+func (r *fakeRefreshTokenRepo) Touch(ctx context.Context, tokenHash string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, t := range r.tokens {
+		if t.TokenHash == tokenHash {
+			now := time.Now()
+			t.LastUsedAt = &now
+		}
+	}
+	return nil
+}
+
+func (r *fakeRefreshTokenRepo) DeleteExpired(ctx context.Context, revokedBefore time.Time) (int64, error) {
+	return 0, nil
+}
+
+type fakePasswordResetRepo struct {
+	mu     sync.Mutex
+	tokens map[string]*domain.PasswordResetToken
+}
+
+func newFakePasswordResetRepo() *fakePasswordResetRepo {
+	return &fakePasswordResetRepo{tokens: map[string]*domain.PasswordResetToken{}}
+}
+
+func (r *fakePasswordResetRepo) Create(ctx context.Context, token *domain.PasswordResetToken) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if token.ID == uuid.Nil {
+		token.ID = uuid.New()
+	}
+	t := *token
+	r.tokens[token.TokenHash] = &t
+	return nil
+}
+
+func (r *fakePasswordResetRepo) GetByTokenHash(ctx context.Context, tokenHash string) (*domain.PasswordResetToken, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	t, ok := r.tokens[tokenHash]
+	if !ok {
+		return nil, domain.ErrNotFound
+	}
+	c := *t
+	return &c, nil
+}
+
+func (r *fakePasswordResetRepo) DeleteByUserID(ctx context.Context, userID uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for hash, t := range r.tokens {
+		if t.UserID == userID {
+			delete(r.tokens, hash)
+		}
+	}
+	return nil
+}
+
+func (r *fakePasswordResetRepo) Consume(ctx context.Context, tokenHash string) (*domain.PasswordResetToken, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	t, ok := r.tokens[tokenHash]
+	if !ok || t.UsedAt != nil || time.Now().After(t.ExpiresAt) {
+		return nil, domain.ErrNotFound
+	}
+	now := time.Now()
+	t.UsedAt = &now
+	c := *t
+	return &c, nil
+}
+
+type fakeVerificationRepo struct {
+	mu     sync.Mutex
+	tokens map[string]*domain.VerificationToken
+}
+
+func newFakeVerificationRepo() *fakeVerificationRepo {
+	return &fakeVerificationRepo{tokens: map[string]*domain.VerificationToken{}}
+}
+
+func (r *fakeVerificationRepo) Create(ctx context.Context, token *domain.VerificationToken) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if token.ID == uuid.Nil {
+		token.ID = uuid.New()
+	}
+	if token.CreatedAt.IsZero() {
+		token.CreatedAt = time.Now()
+	}
+	t := *token
+	r.tokens[token.TokenHash] = &t
+	return nil
+}
+
+func (r *fakeVerificationRepo) Consume(ctx context.Context, tokenHash string) (*domain.VerificationToken, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	t, ok := r.tokens[tokenHash]
+	if !ok || t.UsedAt != nil || time.Now().After(t.ExpiresAt) {
+		return nil, domain.ErrNotFound
+	}
+	now := time.Now()
+	t.UsedAt = &now
+	c := *t
+	return &c, nil
+}
+
+func (r *fakeVerificationRepo) DeleteByUserID(ctx context.Context, userID uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for hash, t := range r.tokens {
+		if t.UserID == userID {
+			delete(r.tokens, hash)
+		}
+	}
+	return nil
+}
+
+type fakeMagicLinkRepo struct {
+	mu     sync.Mutex
+	tokens map[string]*domain.MagicLinkToken
+}
+
+func newFakeMagicLinkRepo() *fakeMagicLinkRepo {
+	return &fakeMagicLinkRepo{tokens: map[string]*domain.MagicLinkToken{}}
+}
+
+func (r *fakeMagicLinkRepo) Create(ctx context.Context, token *domain.MagicLinkToken) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if token.ID == uuid.Nil {
+		token.ID = uuid.New()
+	}
+	t := *token
+	r.tokens[token.TokenHash] = &t
+	return nil
+}
+
+func (r *fakeMagicLinkRepo) Consume(ctx context.Context, tokenHash string) (*domain.MagicLinkToken, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	t, ok := r.tokens[tokenHash]
+	if !ok || t.UsedAt != nil || time.Now().After(t.ExpiresAt) {
+		return nil, domain.ErrNotFound
+	}
+	now := time.Now()
+	t.UsedAt = &now
+	c := *t
+	return &c, nil
+}
+
+func (r *fakeMagicLinkRepo) DeleteByUserID(ctx context.Context, userID uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for hash, t := range r.tokens {
+		if t.UserID == userID {
+			delete(r.tokens, hash)
+		}
+	}
+	return nil
+}
+
+type fakeOAuthAccountRepo struct {
+	mu       sync.Mutex
+	accounts []*domain.OAuthAccount
+}
+
+func (r *fakeOAuthAccountRepo) Create(ctx context.Context, account *domain.OAuthAccount) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if account.ID == uuid.Nil {
+		account.ID = uuid.New()
+	}
+	a := *account
+	r.accounts = append(r.accounts, &a)
+	return nil
+}
+
+func (r *fakeOAuthAccountRepo) GetByProviderUserID(ctx context.Context, provider, providerUserID string) (*domain.OAuthAccount, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, a := range r.accounts {
+		if a.Provider == provider && a.ProviderUserID == providerUserID {
+			c := *a
+			return &c, nil
+		}
+	}
+	return nil, domain.ErrNotFound
+}
+
+func (r *fakeOAuthAccountRepo) GetByUserID(ctx context.Context, userID uuid.UUID) ([]*domain.OAuthAccount, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var accounts []*domain.OAuthAccount
+	for _, a := range r.accounts {
+		if a.UserID == userID {
+			c := *a
+			accounts = append(accounts, &c)
+		}
+	}
+	return accounts, nil
+}
+
+func (r *fakeOAuthAccountRepo) Delete(ctx context.Context, userID uuid.UUID, provider string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, a := range r.accounts {
+		if a.UserID == userID && a.Provider == provider {
+			r.accounts = append(r.accounts[:i], r.accounts[i+1:]...)
+			return nil
+		}
+	}
+	return domain.ErrNotFound
+}
+
+type fakeInviteRepo struct {
+	mu      sync.Mutex
+	invites []*domain.Invite
+}
+
+func (r *fakeInviteRepo) Create(ctx context.Context, invite *domain.Invite) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if invite.ID == uuid.Nil {
+		invite.ID = uuid.New()
+	}
+	i := *invite
+	r.invites = append(r.invites, &i)
+	return nil
+}
+
+func (r *fakeInviteRepo) GetByTokenHash(ctx context.Context, tokenHash string) (*domain.Invite, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, i := range r.invites {
+		if i.TokenHash == tokenHash {
+			c := *i
+			return &c, nil
+		}
+	}
+	return nil, domain.ErrNotFound
+}
+
+func (r *fakeInviteRepo) Consume(ctx context.Context, tokenHash string) (*domain.Invite, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, i := range r.invites {
+		if i.TokenHash == tokenHash && i.UsedAt == nil && !i.IsExpired() {
+			now := time.Now()
+			i.UsedAt = &now
+			c := *i
+			return &c, nil
+		}
+	}
+	return nil, domain.ErrNotFound
+}
+
+type fakeCredentialRepo struct {
+	mu          sync.Mutex
+	credentials []*domain.Credential
+}
+
+func (r *fakeCredentialRepo) Create(ctx context.Context, credential *domain.Credential) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if credential.ID == uuid.Nil {
+		credential.ID = uuid.New()
+	}
+	c := *credential
+	r.credentials = append(r.credentials, &c)
+	return nil
+}
+
+func (r *fakeCredentialRepo) GetByUserID(ctx context.Context, userID uuid.UUID) ([]*domain.Credential, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []*domain.Credential
+	for _, c := range r.credentials {
+		if c.UserID == userID {
+			copied := *c
+			out = append(out, &copied)
+		}
+	}
+	return out, nil
+}
+
+func (r *fakeCredentialRepo) GetByCredentialID(ctx context.Context, credentialID []byte) (*domain.Credential, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, c := range r.credentials {
+		if bytes.Equal(c.CredentialID, credentialID) {
+			copied := *c
+			return &copied, nil
+		}
+	}
+	return nil, domain.ErrNotFound
+}
+
+func (r *fakeCredentialRepo) UpdateSignCount(ctx context.Context, id uuid.UUID, signCount uint32) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, c := range r.credentials {
+		if c.ID == id {
+			now := time.Now()
+			c.SignCount = signCount
+			c.LastUsedAt = &now
+			return nil
+		}
+	}
+	return domain.ErrNotFound
+}
+
+type fakeOrganizationRepo struct {
+	mu   sync.Mutex
+	orgs []*domain.Organization
+}
+
+func (r *fakeOrganizationRepo) Create(ctx context.Context, org *domain.Organization) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if org.ID == uuid.Nil {
+		org.ID = uuid.New()
+	}
+	o := *org
+	r.orgs = append(r.orgs, &o)
+	return nil
+}
+
+func (r *fakeOrganizationRepo) find(match func(*domain.Organization) bool) (*domain.Organization, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, o := range r.orgs {
+		if match(o) {
+			c := *o
+			return &c, nil
+		}
+	}
+	return nil, domain.ErrNotFound
+}
+
+func (r *fakeOrganizationRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.Organization, error) {
+	return r.find(func(o *domain.Organization) bool { return o.ID == id })
+}
+
+func (r *fakeOrganizationRepo) GetBySlug(ctx context.Context, slug string) (*domain.Organization, error) {
+	return r.find(func(o *domain.Organization) bool { return o.Slug == slug })
+}
+
+type fakeAPIKeyRepo struct {
+	mu   sync.Mutex
+	keys []*domain.APIKey
+}
+
+func (r *fakeAPIKeyRepo) Create(ctx context.Context, key *domain.APIKey) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if key.ID == uuid.Nil {
+		key.ID = uuid.New()
+	}
+	key.CreatedAt = time.Now()
+	k := *key
+	r.keys = append(r.keys, &k)
+	return nil
+}
+
+func (r *fakeAPIKeyRepo) find(match func(*domain.APIKey) bool) (*domain.APIKey, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, k := range r.keys {
+		if match(k) {
+			c := *k
+			return &c, nil
+		}
+	}
+	return nil, domain.ErrNotFound
+}
+
+func (r *fakeAPIKeyRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.APIKey, error) {
+	return r.find(func(k *domain.APIKey) bool { return k.ID == id })
+}
+
+func (r *fakeAPIKeyRepo) GetByHash(ctx context.Context, keyHash string) (*domain.APIKey, error) {
+	return r.find(func(k *domain.APIKey) bool { return k.KeyHash == keyHash })
+}
+
+func (r *fakeAPIKeyRepo) List(ctx context.Context, orgID uuid.UUID) ([]*domain.APIKey, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	keys := make([]*domain.APIKey, 0, len(r.keys))
+	for i := len(r.keys) - 1; i >= 0; i-- {
+		if r.keys[i].OrganizationID != orgID {
+			continue
+		}
+		c := *r.keys[i]
+		keys = append(keys, &c)
+	}
+	return keys, nil
+}
+
+func (r *fakeAPIKeyRepo) Revoke(ctx context.Context, id uuid.UUID, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, k := range r.keys {
+		if k.ID == id && k.RevokedAt == nil {
+			k.RevokedAt = &at
+			return nil
+		}
+	}
+	return domain.ErrNotFound
+}
+
+// publishedEvent is an event captured by fakeEventPublisher
+type publishedEvent struct {
+	Event string
+	Data  map[string]interface{}
+}
+
+// fakeTransactioner rolls back the user and refresh token fakes when fn fails
+type fakeTransactioner struct {
+	users         *fakeUserRepo
+	refreshTokens *fakeRefreshTokenRepo
+}
+
+func (f *fakeTransactioner) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	f.users.mu.Lock()
+	users := make(map[uuid.UUID]*domain.User, len(f.users.users))
+	for id, u := range f.users.users {
+		users[id] = u
+	}
+	f.users.mu.Unlock()
+	f.refreshTokens.mu.Lock()
+	tokens := append([]*domain.RefreshToken(nil), f.refreshTokens.tokens...)
+	f.refreshTokens.mu.Unlock()
+
+	err := fn(ctx)
+	if err != nil {
+		f.users.mu.Lock()
+		f.users.users = users
+		f.users.mu.Unlock()
+		f.refreshTokens.mu.Lock()
+		f.refreshTokens.tokens = tokens
+		f.refreshTokens.mu.Unlock()
+	}
+	return err
+}
+
+type fakeEventPublisher struct {
+	mu     sync.Mutex
+	events []publishedEvent
+	err    error // Returned by Publish after recording the event
+}
+
+func (p *fakeEventPublisher) Publish(ctx context.Context, event string, data map[string]interface{}) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.events = append(p.events, publishedEvent{Event: event, Data: data})
+	return p.err
+}
+
+func (p *fakeEventPublisher) names() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var out []string
+	for _, e := range p.events {
+		out = append(out, e.Event)
+	}
+	return out
+}
+
+type fakeAuditLogger struct {
+	mu     sync.Mutex
+	events []AuditEvent
+}
+
+func (l *fakeAuditLogger) Log(ctx context.Context, event AuditEvent) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.events = append(l.events, event)
+}
+
+type fakeLoginMetrics struct {
+	mu       sync.Mutex
+	failures map[LoginFailureReason]int
+}
+
+func newFakeLoginMetrics() *fakeLoginMetrics {
+	return &fakeLoginMetrics{failures: map[LoginFailureReason]int{}}
+}
+
+func (m *fakeLoginMetrics) LoginFailed(reason LoginFailureReason) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.failures[reason]++
+}
+
+type fakePasswordHistoryRepo struct {
+	mu      sync.Mutex
+	entries []*domain.PasswordHistory // Oldest first
+}
+
+func (r *fakePasswordHistoryRepo) Create(ctx context.Context, entry *domain.PasswordHistory) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	e := *entry
+	r.entries = append(r.entries, &e)
+	return nil
+}
+
+func (r *fakePasswordHistoryRepo) ListRecent(ctx context.Context, userID uuid.UUID, limit int) ([]*domain.PasswordHistory, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []*domain.PasswordHistory
+	for i := len(r.entries) - 1; i >= 0 && len(out) < limit; i-- {
+		if r.entries[i].UserID == userID {
+			out = append(out, r.entries[i])
+		}
+	}
+	return out, nil
+}
+
+func (r *fakePasswordHistoryRepo) Prune(ctx context.Context, userID uuid.UUID, keep int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var kept []*domain.PasswordHistory
+	seen := 0
+	for i := len(r.entries) - 1; i >= 0; i-- {
+		e := r.entries[i]
+		if e.UserID == userID {
+			if seen >= keep {
+				continue
+			}
+			seen++
+		}
+		kept = append([]*domain.PasswordHistory{e}, kept...)
+	}
+	r.entries = kept
+	return nil
+}
+
+// count returns the number of entries stored for the user
+func (r *fakePasswordHistoryRepo) count(userID uuid.UUID) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	n := 0
+	for _, e := range r.entries {
+		if e.UserID == userID {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,101 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"auth-service/internal/application/dto"
+
+	"github.com/google/uuid"
+)
+
+// IntrospectToken - RFC 7662 token introspection
+// Gateway'ler ve diğer servisler JWT mantığını bilmeden access token'ın geçerli olup olmadığını sorar.
+// Geçersiz, süresi dolmuş veya blacklist'teki (logout edilmiş) token hata değildir: {"active": false} döner.
+// Hata sadece blacklist veya token version okunamazsa döner; bu durumda token'a "aktif" diyemeyiz (fail closed).
+func (uc *AuthUseCase) IntrospectToken(ctx context.Context, token string) (_ *dto.IntrospectionResponse, err error) {
+	defer translateContextError(ctx, &err)
+
+	inactive := &dto.IntrospectionResponse{Active: false}
+
+	// ADIM 1: İmza, süre ve token tipi kontrolü (AuthMiddleware ile aynı doğrulama)
+	claims, err := uc.jwtService.ValidateToken(token)
+	if err != nil {
+		return inactive, nil
+	}
+
+	// ADIM 2: Logout ile veya oturumu kapatılarak (örn: ban) iptal edilmiş mi?
+	for _, id := range []string{claims.ID, claims.SessionID} {
+		if id == "" {
+			continue
+		}
+		revoked, err := uc.tokenBlacklist.Contains(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if revoked {
+			return inactive, nil
+		}
+	}
+
+	// ADIM 3: Token version'ı eskiyse (şifre değişti, force logout) veya kullanıcı silindiyse geçersiz
+	userID, err := uuid.Parse(claims.UserID)
+	if err != nil {
+		return inactive, nil
+	}
+	version, err := uc.CurrentTokenVersion(ctx, userID)
+	if errors.Is(err, ErrUserNotFound) {
+		return inactive, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if claims.TokenVersion < version {
+		return inactive, nil
+	}
+
+	// ADIM 4: Aktif token'ın bilgilerini dön
+	resp := &dto.IntrospectionResponse{
+		Active:   true,
+		Sub:      claims.UserID,
+		Username: claims.Username,
+		Email:    claims.Email,
+		Scope:    strings.Join(claims.Scopes, " "),
+	}
+	if claims.ExpiresAt != nil {
+		resp.Exp = claims.ExpiresAt.Unix()
+	}
+	if claims.IssuedAt != nil {
+		resp.Iat = claims.IssuedAt.Unix()
+	}
+	return resp, nil
+}
+
+// RevokeAccessToken - Başka bir servisin elindeki access token'ı süresi dolana kadar iptal eder (gRPC Revoke)
+// Logout'tan farkı: token context'ten değil parametreden gelir, refresh token'lara dokunulmaz.
+// Geçersiz veya süresi dolmuş token ErrInvalidToken döner (iptal edilecek bir şey yok).
+func (uc *AuthUseCase) RevokeAccessToken(ctx context.Context, token string) (err error) {
+	defer translateContextError(ctx, &err)
+
+	// ADIM 1: Token bizim mi ve hâlâ geçerli mi?
+	claims, err := uc.jwtService.ValidateToken(token)
+	if err != nil || claims.ID == "" || claims.ExpiresAt == nil {
+		return ErrInvalidToken
+	}
+
+	// ADIM 2: Kalan ömrü kadar blacklist'e al (blacklistCurrentToken ile aynı)
+	ttl := time.Until(claims.ExpiresAt.Time)
+	if ttl <= 0 {
+		return ErrInvalidToken
+	}
+	if err := uc.tokenBlacklist.Add(ctx, claims.ID, ttl); err != nil {
+		return err
+	}
+
+	if userID, err := uuid.Parse(claims.UserID); err == nil {
+		uc.logAudit(ctx, AuditTokenRevoked, userID, nil)
+	}
+	return nil
+}
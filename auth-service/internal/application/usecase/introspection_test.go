@@ -0,0 +1,99 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"auth-service/internal/application/dto"
+	"auth-service/internal/domain"
+)
+
+// unavailableBlacklist fails every lookup, like Redis being down
+type unavailableBlacklist struct{}
+
+func (unavailableBlacklist) Add(ctx context.Context, tokenID string, ttl time.Duration) error {
+	return errors.New("blacklist unavailable")
+}
+
+func (unavailableBlacklist) Contains(ctx context.Context, tokenID string) (bool, error) {
+	return false, errors.New("blacklist unavailable")
+}
+
+func TestIntrospectToken(t *testing.T) {
+	uc, deps := newTestUseCase(t)
+	user := seedUser(t, uc, deps, &domain.User{Email: "jane@example.com", Username: "jane", IsVerified: true}, "correct-horse")
+	login, err := uc.Login(context.Background(), &dto.LoginRequest{EmailOrUsername: "jane@example.com", Password: "correct-horse"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := uc.IntrospectToken(context.Background(), login.AccessToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !resp.Active || resp.Sub != user.ID.String() || resp.Username != "jane" || resp.Email != "jane@example.com" ||
+		resp.Exp <= resp.Iat || resp.Iat == 0 {
+		t.Errorf("active token: %+v", resp)
+	}
+
+	// Garbage and refresh tokens are inactive, not errors
+	for _, token := range []string{"not-a-jwt", login.RefreshToken} {
+		resp, err := uc.IntrospectToken(context.Background(), token)
+		if err != nil || resp.Active || resp.Sub != "" {
+			t.Errorf("IntrospectToken(%q) = %+v, %v; want inactive", token, resp, err)
+		}
+	}
+
+	// Logged-out tokens are inactive
+	claims, err := uc.jwtService.ValidateToken(login.AccessToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := deps.blacklist.Add(context.Background(), claims.ID, time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	if resp, err := uc.IntrospectToken(context.Background(), login.AccessToken); err != nil || resp.Active {
+		t.Errorf("blacklisted token = %+v, %v; want inactive", resp, err)
+	}
+}
+
+func TestIntrospectTokenFailsClosedWithoutBlacklist(t *testing.T) {
+	uc, deps := newTestUseCaseWithConfig(t, testSecurityConfig(), WithTokenBlacklist(unavailableBlacklist{}))
+	seedUser(t, uc, deps, &domain.User{Email: "jane@example.com", Username: "jane", IsVerified: true}, "correct-horse")
+	login, err := uc.Login(context.Background(), &dto.LoginRequest{EmailOrUsername: "jane", Password: "correct-horse"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if resp, err := uc.IntrospectToken(context.Background(), login.AccessToken); err == nil {
+		t.Errorf("got %+v, want an error when revocation can't be checked", resp)
+	}
+}
+
+func TestRevokeAccessToken(t *testing.T) {
+	uc, deps := newTestUseCase(t)
+	seedUser(t, uc, deps, &domain.User{Email: "jane@example.com", Username: "jane", IsVerified: true}, "correct-horse")
+	login, err := uc.Login(context.Background(), &dto.LoginRequest{EmailOrUsername: "jane@example.com", Password: "correct-horse"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := uc.RevokeAccessToken(context.Background(), login.AccessToken); err != nil {
+		t.Fatal(err)
+	}
+	if resp, err := uc.IntrospectToken(context.Background(), login.AccessToken); err != nil || resp.Active {
+		t.Errorf("revoked token = %+v, %v; want inactive", resp, err)
+	}
+	// The session itself is untouched: the refresh token still works
+	if _, err := uc.RefreshToken(context.Background(), login.RefreshToken); err != nil {
+		t.Errorf("refresh after revoking the access token: %v", err)
+	}
+
+	for _, token := range []string{"not-a-jwt", login.RefreshToken} {
+		if err := uc.RevokeAccessToken(context.Background(), token); !errors.Is(err, ErrInvalidToken) {
+			t.Errorf("RevokeAccessToken(%q) = %v, want ErrInvalidToken", token, err)
+		}
+	}
+}
@@ -0,0 +1,144 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"auth-service/internal/application/dto"
+	"auth-service/internal/domain"
+	"auth-service/pkg/security"
+
+	"github.com/google/uuid"
+)
+
+// inviteTokenBytes - Davet token'ının rastgele byte sayısı (base64url ile 43 karakter)
+const inviteTokenBytes = 32
+
+// ErrRegistrationDisabled - Kayıt kapalı (REGISTRATION_ENABLED=false)
+var ErrRegistrationDisabled = newAppError(http.StatusForbidden, "registration_disabled", "Registration is closed")
+
+// ErrInviteRequired - Kayıt sadece davetle (REGISTRATION_INVITE_REQUIRED=true) ve davet token'ı yok
+var ErrInviteRequired = newAppError(http.StatusForbidden, "invite_required", "Registration requires an invite")
+
+// ErrInvalidInvite - Davet yok, süresi dolmuş, kullanılmış veya başka bir email'e verilmiş
+// Hangisi olduğu söylenmez: token tahmin eden biri davetlerin durumunu öğrenemesin.
+var ErrInvalidInvite = newAppError(http.StatusBadRequest, "invalid_invite", "The invite is invalid, expired or has already been used")
+
+// errInvitesNotConfigured - WithInviteIssuing verilmeden davet oluşturulmaya çalışıldı
+var errInvitesNotConfigured = errors.New("invites are not configured")
+
+// WithRegistrationEnabled - false ise Register ErrRegistrationDisabled döner ve
+// social login sadece mevcut kullanıcıları içeri alır (REGISTRATION_ENABLED)
+func WithRegistrationEnabled(enabled bool) AuthUseCaseOption {
+	return func(uc *AuthUseCase) {
+		uc.registrationDisabled = !enabled
+	}
+}
+
+// WithInvites - Davet token'larının saklandığı repository
+// required true ise sadece geçerli davetle kayıt olunur (REGISTRATION_INVITE_REQUIRED);
+// false ise davet opsiyoneldir, verilirse yine kontrol edilir ve tüketilir.
+func WithInvites(invites domain.InviteRepository, required bool) AuthUseCaseOption {
+	return func(uc *AuthUseCase) {
+		uc.invites = invites
+		uc.inviteRequired = required
+	}
+}
+
+// RegistrationEnabled - Yeni hesap açılabiliyor mu? Handler'lar isteği okumadan reddedebilsin diye
+func (uc *AuthUseCase) RegistrationEnabled() bool {
+	return !uc.registrationDisabled
+}
+
+// registrationInvite - Kayıttaki davet token'ını kontrol eder (henüz tüketmez)
+// Token yoksa davet zorunlu değilse nil döner.
+func (uc *AuthUseCase) registrationInvite(ctx context.Context, token, email string) (*domain.Invite, error) {
+	if token == "" {
+		if uc.inviteRequired {
+			return nil, ErrInviteRequired
+		}
+		return nil, nil
+	}
+	if uc.invites == nil {
+		return nil, ErrInvalidInvite
+	}
+
+	invite, err := uc.invites.GetByTokenHash(ctx, security.HashToken(token))
+	if err != nil {
+		return nil, notFoundAs(err, ErrInvalidInvite)
+	}
+	if invite.UsedAt != nil || invite.IsExpired() {
+		return nil, ErrInvalidInvite
+	}
+	// Email'e verilmiş davet başkasına geçmez
+	if invite.Email != "" && invite.Email != email {
+		return nil, ErrInvalidInvite
+	}
+	return invite, nil
+}
+
+// checkAccountCreation - Social login'in yeni hesap açıp açamayacağı
+// Social login'de davet token'ı taşınmadığı için davet zorunluyken hesap açılmaz.
+func (uc *AuthUseCase) checkAccountCreation() error {
+	if uc.registrationDisabled {
+		return ErrRegistrationDisabled
+	}
+	if uc.inviteRequired {
+		return ErrInviteRequired
+	}
+	return nil
+}
+
+// WithInviteIssuing - Admin'in davet oluşturması için repository ve davetin geçerlilik süresi (INVITE_TTL)
+func WithInviteIssuing(invites domain.InviteRepository, ttl time.Duration) AdminUseCaseOption {
+	return func(uc *AdminUseCase) {
+		uc.invites = invites
+		uc.inviteTTL = ttl
+	}
+}
+
+// CreateInvite - Admin'in organizasyonuna tek kullanımlık kayıt daveti oluşturur
+// email verilirse sadece o adresle kayıt olunabilir. Token sadece burada döner
+// (hash'i saklanır); admin davet edilen kişiye kendisi iletir.
+func (uc *AdminUseCase) CreateInvite(ctx context.Context, actorID uuid.UUID, email string) (*dto.InviteResponse, error) {
+	if uc.invites == nil {
+		return nil, errInvitesNotConfigured
+	}
+
+	// ADIM 1: Davet admin'in organizasyonuna
+	actor, err := uc.userRepo.GetByID(ctx, actorID)
+	if err != nil {
+		return nil, notFoundAs(err, ErrUserNotFound)
+	}
+
+	// ADIM 2: Random token üret ve hash'ini sakla
+	token, err := security.GenerateOpaqueToken(inviteTokenBytes)
+	if err != nil {
+		return nil, err
+	}
+	invite := &domain.Invite{
+		OrganizationID: actor.OrganizationID,
+		InvitedBy:      actor.ID,
+		Email:          domain.NormalizeEmail(email),
+		TokenHash:      security.HashToken(token),
+		ExpiresAt:      time.Now().Add(uc.inviteTTL),
+	}
+	if err := uc.invites.Create(ctx, invite); err != nil {
+		return nil, err
+	}
+
+	// ADIM 3: Kaydet
+	uc.audit.Log(ctx, AuditEvent{Action: "invite.created", ActorID: actorID, Details: map[string]string{
+		"invite_id": invite.ID.String(),
+		"email":     invite.Email,
+	}})
+
+	return &dto.InviteResponse{
+		ID:        invite.ID.String(),
+		Token:     token,
+		Email:     invite.Email,
+		ExpiresAt: invite.ExpiresAt,
+	}, nil
+}
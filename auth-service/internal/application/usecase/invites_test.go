@@ -0,0 +1,128 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"auth-service/internal/application/dto"
+	"auth-service/internal/domain"
+	"auth-service/pkg/security"
+
+	"github.com/google/uuid"
+)
+
+func inviteRegisterRequest(email, username, invite string) *dto.RegisterRequest {
+	return &dto.RegisterRequest{
+		Email:       email,
+		Username:    username,
+		Password:    "correct-horse",
+		FirstName:   "Jane",
+		LastName:    "Doe",
+		InviteToken: invite,
+	}
+}
+
+func TestRegisterDisabled(t *testing.T) {
+	uc, deps := newTestUseCaseWithConfig(t, testSecurityConfig(), WithRegistrationEnabled(false))
+	// Closed registration must answer without a database lookup
+	deps.users.lookupErr = errors.New("database is down")
+
+	_, err := uc.Register(context.Background(), inviteRegisterRequest("jane@example.com", "jane", ""))
+	if err != ErrRegistrationDisabled {
+		t.Fatalf("err = %v, want ErrRegistrationDisabled", err)
+	}
+	if uc.RegistrationEnabled() {
+		t.Error("RegistrationEnabled() = true")
+	}
+}
+
+func TestRegisterWithInvite(t *testing.T) {
+	ctx := context.Background()
+	invites := &fakeInviteRepo{}
+	uc, deps := newTestUseCaseWithConfig(t, testSecurityConfig(), WithInvites(invites, true))
+	audit := &fakeAuditLogger{}
+	admin := NewAdminUseCase(deps.users, nil, nil, audit, nil, WithInviteIssuing(invites, time.Hour))
+	inviter := seedUser(t, uc, deps, &domain.User{Email: "admin@example.com", Username: "admin", Role: domain.RoleAdmin}, "correct-horse")
+	inviter.OrganizationID = uuid.New()
+	if err := deps.users.Update(ctx, inviter); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := uc.Register(ctx, inviteRegisterRequest("jane@example.com", "jane", "")); err != ErrInviteRequired {
+		t.Fatalf("no invite: got %v, want ErrInviteRequired", err)
+	}
+	if _, err := uc.Register(ctx, inviteRegisterRequest("jane@example.com", "jane", "made-up")); err != ErrInvalidInvite {
+		t.Fatalf("unknown invite: got %v, want ErrInvalidInvite", err)
+	}
+
+	invite, err := admin.CreateInvite(ctx, inviter.ID, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(audit.events) != 1 || audit.events[0].Action != "invite.created" {
+		t.Errorf("audit events = %+v", audit.events)
+	}
+	if stored := invites.invites[0]; stored.TokenHash != security.HashToken(invite.Token) || stored.InvitedBy != inviter.ID {
+		t.Errorf("stored invite = %+v", stored)
+	}
+
+	resp, err := uc.Register(ctx, inviteRegisterRequest("jane@example.com", "jane", invite.Token))
+	if err != nil {
+		t.Fatal(err)
+	}
+	user, err := deps.users.GetByID(ctx, uuid.MustParse(resp.User.ID))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The user joins the inviter's organization, not the default one
+	if user.OrganizationID != inviter.OrganizationID || user.InvitedBy == nil || *user.InvitedBy != inviter.ID {
+		t.Errorf("user organization = %s, invited by %v", user.OrganizationID, user.InvitedBy)
+	}
+
+	// Invites are single-use
+	if _, err := uc.Register(ctx, inviteRegisterRequest("john@example.com", "john", invite.Token)); err != ErrInvalidInvite {
+		t.Errorf("reused invite: got %v, want ErrInvalidInvite", err)
+	}
+}
+
+func TestRegisterWithInviteForEmail(t *testing.T) {
+	ctx := context.Background()
+	invites := &fakeInviteRepo{}
+	uc, _ := newTestUseCaseWithConfig(t, testSecurityConfig(), WithInvites(invites, true))
+	for _, invite := range []*domain.Invite{
+		{TokenHash: security.HashToken("for-jane"), Email: "jane@example.com", ExpiresAt: time.Now().Add(time.Hour)},
+		{TokenHash: security.HashToken("expired"), ExpiresAt: time.Now().Add(-time.Minute)},
+	} {
+		if err := invites.Create(ctx, invite); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := uc.Register(ctx, inviteRegisterRequest("john@example.com", "john", "for-jane")); err != ErrInvalidInvite {
+		t.Errorf("other email: got %v, want ErrInvalidInvite", err)
+	}
+	if _, err := uc.Register(ctx, inviteRegisterRequest("john@example.com", "john", "expired")); err != ErrInvalidInvite {
+		t.Errorf("expired invite: got %v, want ErrInvalidInvite", err)
+	}
+	if _, err := uc.Register(ctx, inviteRegisterRequest("Jane@Example.com", "jane", "for-jane")); err != nil {
+		t.Errorf("invited email: %v", err)
+	}
+}
+
+func TestOAuthLoginCannotCreateAccountsWhenInviteOnly(t *testing.T) {
+	ctx := context.Background()
+	uc, deps := newTestUseCaseWithConfig(t, testSecurityConfig(),
+		WithOAuthAccounts(&fakeOAuthAccountRepo{}), WithInvites(&fakeInviteRepo{}, true))
+	seedUser(t, uc, deps, &domain.User{Email: "jane@example.com", Username: "jane", IsVerified: true}, "correct-horse")
+
+	_, err := uc.LoginWithOAuth(ctx, "google", ExternalUser{ProviderID: "g-2", Email: "john@example.com", EmailVerified: true})
+	if err != ErrInviteRequired {
+		t.Errorf("new user: got %v, want ErrInviteRequired", err)
+	}
+	// Existing users can still link the provider and sign in
+	if _, err := uc.LoginWithOAuth(ctx, "google", ExternalUser{ProviderID: "g-1", Email: "jane@example.com", EmailVerified: true}); err != nil {
+		t.Errorf("existing user: %v", err)
+	}
+}
@@ -0,0 +1,51 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// lastLoginTimeout - Arka plandaki son giriş zamanı yazmasına verilen süre
+// İstekten bağımsız çalıştığı için kendi timeout'u olmalı; yoksa takılan bir DB yazması shutdown'ı bekletir.
+const lastLoginTimeout = 5 * time.Second
+
+// maxPendingLastLogins - Aynı anda bekleyebilecek son giriş zamanı yazması
+// DB yavaşken gelen login seli sınırsız goroutine açmasın; dolarsa güncelleme atlanır.
+const maxPendingLastLogins = 64
+
+var errLastLoginBacklog = errors.New("too many pending last login writes, update skipped")
+
+// recordLastLogin - Son giriş zamanını arka planda günceller (analytics için, kritik değil)
+// Login cevabı DB yazmasını beklemez; hata veya atlanan güncelleme sadece log'lanır.
+// Bekleyen yazmalar Stop'ta tamamlanır.
+func (uc *AuthUseCase) recordLastLogin(ctx context.Context, userID uuid.UUID) {
+	select {
+	case uc.lastLoginSlots <- struct{}{}:
+	default:
+		uc.logError(ctx, "update last login", errLastLoginBacklog, "user_id", userID)
+		return
+	}
+
+	// İstek bitince iptal edilen context'ten kopar; request ID log'lar için kalır
+	ctx = context.WithoutCancel(ctx)
+	uc.background.Add(1)
+	go func() {
+		defer uc.background.Done()
+		defer func() { <-uc.lastLoginSlots }()
+
+		ctx, cancel := context.WithTimeout(ctx, lastLoginTimeout)
+		defer cancel()
+		if err := uc.userRepo.UpdateLastLogin(ctx, userID); err != nil {
+			uc.logError(ctx, "update last login", err, "user_id", userID)
+		}
+	}()
+}
+
+// Stop - Arka plan işlerinin (email'ler, son giriş zamanı) bitmesini bekler
+// Graceful shutdown'da HTTP server durduktan sonra, veritabanı kapanmadan önce çağrılır.
+func (uc *AuthUseCase) Stop() {
+	uc.background.Wait()
+}
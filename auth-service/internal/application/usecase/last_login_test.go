@@ -0,0 +1,65 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"auth-service/internal/application/dto"
+	"auth-service/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+// slowLastLoginRepo blocks UpdateLastLogin until release is closed
+type slowLastLoginRepo struct {
+	domain.UserRepository
+	release chan struct{}
+	done    chan struct{}
+}
+
+func (r slowLastLoginRepo) UpdateLastLogin(ctx context.Context, id uuid.UUID) error {
+	defer close(r.done)
+	select {
+	case <-r.release:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	return r.UserRepository.UpdateLastLogin(ctx, id)
+}
+
+func TestLoginDoesNotWaitForLastLoginUpdate(t *testing.T) {
+	uc, deps := newTestUseCase(t)
+	user := seedUser(t, uc, deps, &domain.User{Email: "jane@example.com", Username: "jane", IsVerified: true}, "correct-horse")
+	repo := slowLastLoginRepo{UserRepository: deps.users, release: make(chan struct{}), done: make(chan struct{})}
+	uc.userRepo = repo
+
+	// The request context ends with the response; the write must survive it
+	ctx, cancel := context.WithCancel(context.Background())
+	loggedIn := make(chan error, 1)
+	go func() {
+		_, err := uc.Login(ctx, &dto.LoginRequest{EmailOrUsername: "jane", Password: "correct-horse"})
+		loggedIn <- err
+	}()
+	select {
+	case err := <-loggedIn:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("login waited for the last-login update")
+	}
+	cancel()
+
+	// Stop drains the pending write
+	close(repo.release)
+	uc.Stop()
+	select {
+	case <-repo.done:
+	default:
+		t.Fatal("Stop returned before the last-login update finished")
+	}
+	if stored, _ := deps.users.GetByID(context.Background(), user.ID); stored.LastLoginAt == nil {
+		t.Error("last login was not recorded")
+	}
+}
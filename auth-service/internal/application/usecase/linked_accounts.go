@@ -0,0 +1,84 @@
+package usecase
+
+import (
+	"context"
+	"net/http"
+
+	"auth-service/internal/application/dto"
+
+	"github.com/google/uuid"
+)
+
+// ErrOAuthAccountNotLinked - Kullanıcının bu provider'da bağlı hesabı yok
+var ErrOAuthAccountNotLinked = newAppError(http.StatusNotFound, "oauth_account_not_linked", "No account is linked at this provider")
+
+// ErrLastLoginMethod - Şifresi olmayan kullanıcı tek giriş yolunu kaldırmak istedi
+// Kaldırılsaydı hesaba bir daha giriş yapılamazdı.
+var ErrLastLoginMethod = newAppError(http.StatusConflict, "last_login_method", "This is your only way to sign in; set a password before unlinking it")
+
+// ListLinkedAccounts - Kullanıcının bağlı social login hesaplarını (provider + bağlanma zamanı) listeler
+// Provider'daki kullanıcı ID'si ve token'lar dönmez.
+func (uc *AuthUseCase) ListLinkedAccounts(ctx context.Context, userID uuid.UUID) (_ []dto.LinkedAccountInfo, err error) {
+	defer translateContextError(ctx, &err)
+
+	if uc.oauthAccounts == nil {
+		return nil, errOAuthNotConfigured
+	}
+
+	accounts, err := uc.oauthAccounts.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	linked := make([]dto.LinkedAccountInfo, 0, len(accounts))
+	for _, account := range accounts {
+		linked = append(linked, dto.LinkedAccountInfo{
+			Provider: account.Provider,
+			LinkedAt: account.CreatedAt,
+		})
+	}
+	return linked, nil
+}
+
+// UnlinkAccount - Kullanıcının provider'daki hesabıyla bağlantısını kaldırır
+// Şifresi hiç belirlenmemiş (social login ile açılmış) kullanıcının son bağlı
+// provider'ı kaldırılamaz: önce "şifremi unuttum" ile şifre belirlemesi gerekir.
+func (uc *AuthUseCase) UnlinkAccount(ctx context.Context, userID uuid.UUID, provider string) (err error) {
+	defer translateContextError(ctx, &err)
+
+	if uc.oauthAccounts == nil {
+		return errOAuthNotConfigured
+	}
+
+	// ADIM 1: Kullanıcıyı ve bağlı hesaplarını getir
+	user, err := uc.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return notFoundAs(err, ErrUserNotFound)
+	}
+	accounts, err := uc.oauthAccounts.GetByUserID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	// ADIM 2: Provider bağlı mı, kaldırınca giriş yolu kalıyor mu?
+	linked := false
+	for _, account := range accounts {
+		if account.Provider == provider {
+			linked = true
+			break
+		}
+	}
+	if !linked {
+		return ErrOAuthAccountNotLinked
+	}
+	if user.PasswordUnset && len(accounts) == 1 {
+		return ErrLastLoginMethod
+	}
+
+	// ADIM 3: Bağlantıyı kaldır ve kaydet
+	if err := uc.oauthAccounts.Delete(ctx, userID, provider); err != nil {
+		return notFoundAs(err, ErrOAuthAccountNotLinked)
+	}
+	uc.logAudit(ctx, AuditOAuthUnlinked, userID, map[string]string{"provider": provider})
+	return nil
+}
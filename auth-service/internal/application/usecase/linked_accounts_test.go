@@ -0,0 +1,100 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"auth-service/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+func TestListLinkedAccounts(t *testing.T) {
+	ctx := context.Background()
+	accounts := &fakeOAuthAccountRepo{}
+	uc, deps := newTestUseCaseWithConfig(t, testSecurityConfig(), WithOAuthAccounts(accounts))
+	user := seedUser(t, uc, deps, &domain.User{Email: "jane@example.com", Username: "jane", IsVerified: true}, "correct-horse")
+	other := seedUser(t, uc, deps, &domain.User{Email: "john@example.com", Username: "john", IsVerified: true}, "correct-horse")
+	for _, account := range []*domain.OAuthAccount{
+		{UserID: user.ID, Provider: "google", ProviderUserID: "g-1"},
+		{UserID: other.ID, Provider: "github", ProviderUserID: "gh-2"},
+	} {
+		if err := accounts.Create(ctx, account); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	linked, err := uc.ListLinkedAccounts(ctx, user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(linked) != 1 || linked[0].Provider != "google" {
+		t.Errorf("linked accounts = %+v", linked)
+	}
+}
+
+func TestUnlinkAccountKeepsLastLoginMethod(t *testing.T) {
+	ctx := context.Background()
+	accounts := &fakeOAuthAccountRepo{}
+	uc, deps := newTestUseCaseWithConfig(t, testSecurityConfig(), WithOAuthAccounts(accounts))
+
+	// Social login creates the user without a password they know
+	if _, err := uc.LoginWithOAuth(ctx, "google", ExternalUser{ProviderID: "g-1", Email: "jane@example.com", EmailVerified: true}); err != nil {
+		t.Fatal(err)
+	}
+	user, err := deps.users.GetByEmail(ctx, uuid.Nil, "jane@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !user.PasswordUnset {
+		t.Fatal("social login user should have no password set")
+	}
+
+	if err := uc.UnlinkAccount(ctx, user.ID, "github"); err != ErrOAuthAccountNotLinked {
+		t.Errorf("unlinked provider: got %v, want ErrOAuthAccountNotLinked", err)
+	}
+	if err := uc.UnlinkAccount(ctx, user.ID, "google"); err != ErrLastLoginMethod {
+		t.Fatalf("only provider: got %v, want ErrLastLoginMethod", err)
+	}
+
+	// With a second provider, either one can go, but not both
+	if err := accounts.Create(ctx, &domain.OAuthAccount{UserID: user.ID, Provider: "github", ProviderUserID: "gh-1"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := uc.UnlinkAccount(ctx, user.ID, "google"); err != nil {
+		t.Fatal(err)
+	}
+	if err := uc.UnlinkAccount(ctx, user.ID, "github"); err != ErrLastLoginMethod {
+		t.Fatalf("last provider: got %v, want ErrLastLoginMethod", err)
+	}
+
+	// Once a password is set, the last provider can be unlinked too
+	token := requestResetToken(t, uc, deps, "jane@example.com")
+	if err := uc.ResetPassword(ctx, token, "battery-staple"); err != nil {
+		t.Fatal(err)
+	}
+	if err := uc.UnlinkAccount(ctx, user.ID, "github"); err != nil {
+		t.Fatal(err)
+	}
+	if len(accounts.accounts) != 0 {
+		t.Errorf("accounts = %+v", accounts.accounts)
+	}
+}
+
+func TestUnlinkAccountWithPassword(t *testing.T) {
+	ctx := context.Background()
+	accounts := &fakeOAuthAccountRepo{}
+	uc, deps := newTestUseCaseWithConfig(t, testSecurityConfig(), WithOAuthAccounts(accounts))
+	user := seedUser(t, uc, deps, &domain.User{Email: "jane@example.com", Username: "jane", IsVerified: true}, "correct-horse")
+	if err := accounts.Create(ctx, &domain.OAuthAccount{UserID: user.ID, Provider: "google", ProviderUserID: "g-1"}); err != nil {
+		t.Fatal(err)
+	}
+
+	// The user registered with a password, so the only provider is not their only login method
+	if err := uc.UnlinkAccount(ctx, user.ID, "google"); err != nil {
+		t.Fatal(err)
+	}
+	if len(accounts.accounts) != 0 {
+		t.Errorf("accounts = %+v", accounts.accounts)
+	}
+}
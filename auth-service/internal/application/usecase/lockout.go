@@ -0,0 +1,59 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"auth-service/internal/domain"
+)
+
+// recordFailedLogin - Hatalı şifre denemesini sayar
+// MaxLoginAttempts'e ulaşılınca hesap LockoutDuration boyunca kilitlenir ve sayaç sıfırlanır.
+// Sayaç veritabanında atomik olarak artırılır: eşzamanlı denemeler birbirini ezmez.
+func (uc *AuthUseCase) recordFailedLogin(ctx context.Context, user *domain.User) error {
+	attempts, err := uc.userRepo.RecordFailedLogin(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+	if attempts < uc.securityCfg.MaxLoginAttempts {
+		return nil
+	}
+
+	lockedUntil := time.Now().Add(uc.securityCfg.LockoutDuration)
+	if err := uc.userRepo.SetLockout(ctx, user.ID, &lockedUntil); err != nil {
+		return err
+	}
+	uc.notifyLockout(ctx, user, lockedUntil)
+	return nil
+}
+
+// notifyLockout - Hesap kilitlendiğinde kullanıcıya son denemenin zamanı ve IP'siyle email gönderir
+// Gerçek sahibi hesabına saldırı olduğunu öğrenir. Aynı saldırı hesabı tekrar tekrar kilitleyebilir:
+// LockoutNotificationInterval içinde en fazla bir email gider (MarkLockoutNotified atomik olarak sahiplenir).
+// Best-effort: hata sadece log'lanır, email arka planda gönderilir; login cevabı değişmez.
+func (uc *AuthUseCase) notifyLockout(ctx context.Context, user *domain.User, lockedUntil time.Time) {
+	if !uc.securityCfg.LockoutNotification {
+		return
+	}
+	now := time.Now()
+	claimed, err := uc.userRepo.MarkLockoutNotified(ctx, user.ID, now, now.Add(-uc.securityCfg.LockoutNotificationInterval))
+	if err != nil {
+		uc.logError(ctx, "mark lockout notified", err, "user_id", user.ID)
+		return
+	}
+	if !claimed {
+		return
+	}
+
+	ipAddress := clientFromContext(ctx).ipAddress
+	if ipAddress == "" {
+		ipAddress = "unknown"
+	}
+	data := map[string]any{
+		"Username":    user.Username,
+		"IPAddress":   ipAddress,
+		"Time":        now.UTC().Format(time.RFC1123),
+		"LockedUntil": lockedUntil.UTC().Format(time.RFC1123),
+	}
+	uc.sendMailInBackground(ctx, "send lockout notification", user.ID, user.Email, MailTemplateAccountLocked, data)
+}
@@ -0,0 +1,136 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"auth-service/internal/application/dto"
+	"auth-service/internal/domain"
+)
+
+func login(uc *AuthUseCase, password string) error {
+	_, err := uc.Login(context.Background(), &dto.LoginRequest{EmailOrUsername: "jane", Password: password})
+	return err
+}
+
+func TestLoginLocksAccountAfterMaxAttempts(t *testing.T) {
+	cfg := testSecurityConfig()
+	cfg.MaxLoginAttempts = 3
+	uc, deps := newTestUseCaseWithConfig(t, cfg)
+	user := seedUser(t, uc, deps, &domain.User{Email: "jane@example.com", Username: "jane", IsVerified: true}, "correct-horse")
+
+	for i := 0; i < 3; i++ {
+		if err := login(uc, "wrong-password"); err != ErrInvalidCredentials {
+			t.Fatalf("attempt %d: got %v, want ErrInvalidCredentials", i+1, err)
+		}
+	}
+
+	// Even the correct password is rejected while locked
+	if err := login(uc, "correct-horse"); err != ErrAccountLocked {
+		t.Fatalf("got %v, want ErrAccountLocked", err)
+	}
+
+	stored, _ := deps.users.GetByID(context.Background(), user.ID)
+	if stored.LockedUntil == nil || time.Until(*stored.LockedUntil) > cfg.LockoutDuration {
+		t.Errorf("LockedUntil = %v", stored.LockedUntil)
+	}
+}
+
+func TestLoginAfterLockoutExpires(t *testing.T) {
+	uc, deps := newTestUseCase(t)
+	expired := time.Now().Add(-time.Minute)
+	user := seedUser(t, uc, deps, &domain.User{Email: "jane@example.com", Username: "jane", IsVerified: true, LockedUntil: &expired}, "correct-horse")
+
+	if err := login(uc, "correct-horse"); err != nil {
+		t.Fatalf("got %v, want success after lockout expired", err)
+	}
+	stored, _ := deps.users.GetByID(context.Background(), user.ID)
+	if stored.LockedUntil != nil {
+		t.Error("expired lockout should be cleared on successful login")
+	}
+}
+
+func TestSuccessfulLoginResetsFailedAttempts(t *testing.T) {
+	cfg := testSecurityConfig()
+	cfg.MaxLoginAttempts = 3
+	uc, deps := newTestUseCaseWithConfig(t, cfg)
+	seedUser(t, uc, deps, &domain.User{Email: "jane@example.com", Username: "jane", IsVerified: true}, "correct-horse")
+
+	login(uc, "wrong-password")
+	login(uc, "wrong-password")
+	if err := login(uc, "correct-horse"); err != nil {
+		t.Fatal(err)
+	}
+	// The counter starts over, so two more failures do not lock the account
+	login(uc, "wrong-password")
+	login(uc, "wrong-password")
+	if err := login(uc, "correct-horse"); err != nil {
+		t.Fatalf("got %v, want success", err)
+	}
+}
+
+func lockoutEmails(deps *testDeps) []map[string]any {
+	var data []map[string]any
+	for _, mail := range deps.mailer.Sent() {
+		if mail.Template == MailTemplateAccountLocked {
+			data = append(data, mail.Data)
+		}
+	}
+	return data
+}
+
+func TestLockoutNotificationIsDebounced(t *testing.T) {
+	cfg := testSecurityConfig()
+	cfg.MaxLoginAttempts = 2
+	// Every lockout expires at once, so each pair of wrong passwords locks again
+	cfg.LockoutDuration = time.Nanosecond
+	uc, deps := newTestUseCaseWithConfig(t, cfg)
+	seedUser(t, uc, deps, &domain.User{Email: "jane@example.com", Username: "jane", IsVerified: true}, "correct-horse")
+
+	ctx := ContextWithClient(context.Background(), "curl/8.0", "203.0.113.7")
+	for i := 0; i < 6; i++ {
+		if _, err := uc.Login(ctx, &dto.LoginRequest{EmailOrUsername: "jane", Password: "wrong-password"}); err != ErrInvalidCredentials {
+			t.Fatalf("attempt %d: got %v, want ErrInvalidCredentials", i+1, err)
+		}
+	}
+	uc.background.Wait()
+
+	emails := lockoutEmails(deps)
+	if len(emails) != 1 {
+		t.Fatalf("%d lockout emails for 3 lockouts, want 1", len(emails))
+	}
+	if emails[0]["IPAddress"] != "203.0.113.7" || emails[0]["Time"] == "" || emails[0]["LockedUntil"] == "" {
+		t.Errorf("email data = %v", emails[0])
+	}
+}
+
+func TestLockoutNotificationSettings(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		enabled  bool
+		interval time.Duration
+		want     int
+	}{
+		{"disabled", false, time.Hour, 0},
+		{"every lockout", true, 0, 3},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := testSecurityConfig()
+			cfg.MaxLoginAttempts = 1
+			cfg.LockoutDuration = time.Nanosecond
+			cfg.LockoutNotification = tt.enabled
+			cfg.LockoutNotificationInterval = tt.interval
+			uc, deps := newTestUseCaseWithConfig(t, cfg)
+			seedUser(t, uc, deps, &domain.User{Email: "jane@example.com", Username: "jane", IsVerified: true}, "correct-horse")
+
+			for i := 0; i < 3; i++ {
+				_ = login(uc, "wrong-password")
+			}
+			uc.background.Wait()
+			if got := len(lockoutEmails(deps)); got != tt.want {
+				t.Errorf("%d lockout emails, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,23 @@
+package usecase
+
+import (
+	"context"
+	"log/slog"
+)
+
+// WithLogger - Kritik olmayan hataların (email, webhook, last login güncellemesi...) yazılacağı logger
+// Verilmezse bu hatalar sessizce yutulur.
+func WithLogger(logger *slog.Logger) AuthUseCaseOption {
+	return func(uc *AuthUseCase) {
+		uc.logger = logger
+	}
+}
+
+// logError - İşlemi başarısız yapmayan bir hatayı log'lar
+// İsteğin ID'si (ContextWithRequestID) varsa satıra eklenir; böylece HTTP log'u ile eşleşir.
+func (uc *AuthUseCase) logError(ctx context.Context, msg string, err error, args ...any) {
+	if requestID := RequestIDFromContext(ctx); requestID != "" {
+		args = append(args, "request_id", requestID)
+	}
+	uc.logger.ErrorContext(ctx, msg, append(args, "error", err)...)
+}
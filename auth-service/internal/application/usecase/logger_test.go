@@ -0,0 +1,53 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"auth-service/internal/application/dto"
+	"auth-service/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+// failingLastLoginRepo fails UpdateLastLogin, which must not fail the login
+type failingLastLoginRepo struct {
+	domain.UserRepository
+}
+
+func (failingLastLoginRepo) UpdateLastLogin(ctx context.Context, id uuid.UUID) error {
+	return errors.New("connection reset")
+}
+
+func TestNonCriticalErrorsAreLogged(t *testing.T) {
+	var buf bytes.Buffer
+	audit := &fakeAuditLogger{}
+	uc, deps := newTestUseCaseWithConfig(t, testSecurityConfig(),
+		WithLogger(slog.New(slog.NewJSONHandler(&buf, nil))), WithAuditLogger(audit))
+	user := seedUser(t, uc, deps, &domain.User{Email: "jane@example.com", Username: "jane", IsVerified: true}, "correct-horse")
+	uc.userRepo = failingLastLoginRepo{deps.users}
+
+	ctx := ContextWithRequestID(context.Background(), "req-123")
+	if _, err := uc.Login(ctx, &dto.LoginRequest{EmailOrUsername: "jane", Password: "correct-horse"}); err != nil {
+		t.Fatalf("login failed on a last-login error: %v", err)
+	}
+	uc.Stop()
+
+	var line map[string]string
+	if err := json.Unmarshal(buf.Bytes(), &line); err != nil {
+		t.Fatalf("log output %q: %v", buf.String(), err)
+	}
+	if line["msg"] != "update last login" || line["error"] != "connection reset" ||
+		line["user_id"] != user.ID.String() || line["request_id"] != "req-123" {
+		t.Errorf("log line = %v", line)
+	}
+
+	// The request ID also ends up in the audit event
+	if len(audit.events) != 1 || audit.events[0].RequestID != "req-123" {
+		t.Errorf("audit events = %+v", audit.events)
+	}
+}
@@ -0,0 +1,34 @@
+package usecase
+
+// LoginFailureReason - Başarısız login'in sebebi (metric label'ı olarak kullanılır)
+// Client'a dönen hata bunlardan bağımsızdır: örn. bad_password ve user_not_found
+// ikisi de ErrInvalidCredentials döner, ayrım sadece metric'lerde görünür.
+type LoginFailureReason string
+
+const (
+	LoginFailureBadPassword     LoginFailureReason = "bad_password"
+	LoginFailureUserNotFound    LoginFailureReason = "user_not_found"
+	LoginFailureInactive        LoginFailureReason = "inactive"
+	LoginFailureLocked          LoginFailureReason = "locked"
+	LoginFailureUnverified      LoginFailureReason = "unverified"
+	LoginFailurePendingApproval LoginFailureReason = "pending_approval"
+)
+
+// LoginMetrics - Login sonuçlarını metric sistemine (Prometheus vs.) bildiren port
+// Sadece sebep bildirilir; kullanıcı/email/IP gibi bilgiler ASLA label olmaz,
+// böylece metric'ler üzerinden hangi hesapların var olduğu öğrenilemez.
+type LoginMetrics interface {
+	LoginFailed(reason LoginFailureReason)
+}
+
+// nopLoginMetrics - LoginMetrics verilmediğinde kullanılan boş implementasyon
+type nopLoginMetrics struct{}
+
+func (nopLoginMetrics) LoginFailed(reason LoginFailureReason) {}
+
+// WithLoginMetrics - Başarısız login'lerin sebebe göre sayılacağı metric implementasyonu
+func WithLoginMetrics(metrics LoginMetrics) AuthUseCaseOption {
+	return func(uc *AuthUseCase) {
+		uc.loginMetrics = metrics
+	}
+}
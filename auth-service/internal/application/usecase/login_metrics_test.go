@@ -0,0 +1,65 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"auth-service/config"
+	"auth-service/internal/application/dto"
+	"auth-service/internal/domain"
+)
+
+func TestLoginReportsFailureReason(t *testing.T) {
+	locked := time.Now().Add(time.Hour)
+
+	tests := []struct {
+		name     string
+		user     *domain.User
+		login    string
+		password string
+		policy   config.UnverifiedLoginPolicy
+		want     LoginFailureReason
+	}{
+		{"unknown user", nil, "nobody", "correct-horse", config.UnverifiedLoginAllow, LoginFailureUserNotFound},
+		{"bad password", &domain.User{IsVerified: true}, "jane", "wrong-password", config.UnverifiedLoginAllow, LoginFailureBadPassword},
+		{"locked", &domain.User{IsVerified: true, LockedUntil: &locked}, "jane", "correct-horse", config.UnverifiedLoginAllow, LoginFailureLocked},
+		{"unverified", &domain.User{}, "jane", "correct-horse", config.UnverifiedLoginBlock, LoginFailureUnverified},
+		{"pending approval", &domain.User{IsVerified: true, Status: domain.UserStatusPendingApproval}, "jane", "correct-horse", config.UnverifiedLoginAllow, LoginFailurePendingApproval},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := testSecurityConfig()
+			cfg.UnverifiedLoginPolicy = tt.policy
+			metrics := newFakeLoginMetrics()
+			uc, deps := newTestUseCaseWithConfig(t, cfg, WithLoginMetrics(metrics))
+			if tt.user != nil {
+				tt.user.Email, tt.user.Username = "jane@example.com", "jane"
+				seedUser(t, uc, deps, tt.user, "correct-horse")
+			}
+
+			if _, err := uc.Login(context.Background(), &dto.LoginRequest{EmailOrUsername: tt.login, Password: tt.password}); err == nil {
+				t.Fatal("login succeeded, want failure")
+			}
+			if len(metrics.failures) != 1 || metrics.failures[tt.want] != 1 {
+				t.Errorf("failures = %v, want one %s", metrics.failures, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoginReportsInactiveUser(t *testing.T) {
+	metrics := newFakeLoginMetrics()
+	uc, deps := newTestUseCaseWithConfig(t, testSecurityConfig(), WithLoginMetrics(metrics))
+	user := seedUser(t, uc, deps, &domain.User{Email: "jane@example.com", Username: "jane"}, "correct-horse")
+	user.IsActive = false
+	deps.users.Update(context.Background(), user)
+
+	if _, err := uc.Login(context.Background(), &dto.LoginRequest{EmailOrUsername: "jane", Password: "correct-horse"}); err != ErrUserInactive {
+		t.Fatalf("got %v, want ErrUserInactive", err)
+	}
+	if metrics.failures[LoginFailureInactive] != 1 {
+		t.Errorf("failures = %v", metrics.failures)
+	}
+}
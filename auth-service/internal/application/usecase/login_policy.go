@@ -0,0 +1,37 @@
+package usecase
+
+import (
+	"time"
+
+	"auth-service/config"
+	"auth-service/internal/domain"
+)
+
+// checkUnverifiedLogin - Doğrulanmamış email ile login için TEK karar noktası
+// Dönen bool: login'e izin verildi ama email doğrulanmamış (response'ta flag olarak döner)
+// Dönen error: login reddedildi (ErrEmailNotVerified)
+//
+// Policy'ler:
+// - block: Email doğrulanana kadar login yok
+// - allow: Login serbest, response'ta uyarı flag'i
+// - grace: Kayıttan sonra VerificationGracePeriod boyunca allow, sonra block
+func (uc *AuthUseCase) checkUnverifiedLogin(user *domain.User) (bool, error) {
+	// Doğrulanmış kullanıcı için policy'nin bir etkisi yok
+	if user.IsVerified {
+		return false, nil
+	}
+
+	switch uc.securityCfg.UnverifiedLoginPolicy {
+	case config.UnverifiedLoginBlock:
+		return false, ErrEmailNotVerified
+	case config.UnverifiedLoginGrace:
+		// Grace süresi doldu mu? (kayıt zamanından itibaren)
+		if time.Since(user.CreatedAt) > uc.securityCfg.VerificationGracePeriod {
+			return false, ErrEmailNotVerified
+		}
+		return true, nil
+	default:
+		// allow (ve tanımsız değerler): mevcut davranış, login serbest
+		return true, nil
+	}
+}
@@ -0,0 +1,84 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"auth-service/config"
+	"auth-service/internal/application/dto"
+	"auth-service/internal/domain"
+)
+
+// seedUser stores a user with the given password and returns it
+func seedUser(t *testing.T, uc *AuthUseCase, deps *testDeps, user *domain.User, password string) *domain.User {
+	t.Helper()
+	hash, err := uc.passwordHasher.Hash(password)
+	if err != nil {
+		t.Fatal(err)
+	}
+	user.PasswordHash = hash
+	user.IsActive = true
+	if err := deps.users.Create(context.Background(), user); err != nil {
+		t.Fatal(err)
+	}
+	return user
+}
+
+func TestLoginUnverifiedPolicy(t *testing.T) {
+	tests := []struct {
+		name         string
+		policy       config.UnverifiedLoginPolicy
+		verified     bool
+		registeredAt time.Time
+		wantErr      error
+		wantFlag     bool
+	}{
+		{"verified user ignores policy", config.UnverifiedLoginBlock, true, time.Now(), nil, false},
+		{"block rejects unverified", config.UnverifiedLoginBlock, false, time.Now(), ErrEmailNotVerified, false},
+		{"allow flags unverified", config.UnverifiedLoginAllow, false, time.Now().Add(-365 * 24 * time.Hour), nil, true},
+		{"grace allows within period", config.UnverifiedLoginGrace, false, time.Now().Add(-time.Hour), nil, true},
+		{"grace rejects after period", config.UnverifiedLoginGrace, false, time.Now().Add(-73 * time.Hour), ErrEmailNotVerified, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := testSecurityConfig()
+			cfg.UnverifiedLoginPolicy = tt.policy
+			uc, deps := newTestUseCaseWithConfig(t, cfg)
+			seedUser(t, uc, deps, &domain.User{
+				Email:      "jane@example.com",
+				Username:   "jane",
+				IsVerified: tt.verified,
+				CreatedAt:  tt.registeredAt,
+			}, "correct-horse")
+
+			resp, err := uc.Login(context.Background(), &dto.LoginRequest{
+				EmailOrUsername: "jane",
+				Password:        "correct-horse",
+			})
+			if err != tt.wantErr {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && resp.EmailVerificationRequired != tt.wantFlag {
+				t.Fatalf("EmailVerificationRequired = %v, want %v", resp.EmailVerificationRequired, tt.wantFlag)
+			}
+			// The client prompts for verification from the user in the response
+			if err == nil && resp.User.IsVerified != tt.verified {
+				t.Errorf("User.IsVerified = %v, want %v", resp.User.IsVerified, tt.verified)
+			}
+		})
+	}
+}
+
+func TestLoginUnverifiedPolicyChecksPasswordFirst(t *testing.T) {
+	cfg := testSecurityConfig()
+	cfg.UnverifiedLoginPolicy = config.UnverifiedLoginBlock
+	uc, deps := newTestUseCaseWithConfig(t, cfg)
+	seedUser(t, uc, deps, &domain.User{Email: "jane@example.com", Username: "jane"}, "correct-horse")
+
+	_, err := uc.Login(context.Background(), &dto.LoginRequest{EmailOrUsername: "jane", Password: "wrong"})
+	if err != ErrInvalidCredentials {
+		t.Fatalf("err = %v, want ErrInvalidCredentials so verification status is not leaked", err)
+	}
+}
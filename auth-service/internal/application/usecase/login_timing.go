@@ -0,0 +1,22 @@
+package usecase
+
+// dummyPassword - Zamanlama eşitlemesi için hash'lenen sabit şifre; hiçbir kullanıcının şifresi değildir
+const dummyPassword = "login-timing-equalization"
+
+// equalizeLoginTiming - Kullanıcı bulunamadığında da şifreyi sabit bir hash ile karşılaştırır
+// Aksi halde bilinmeyen kullanıcılar hash maliyeti olmadan hemen döner ve
+// saldırgan cevap süresinden hangi hesapların var olduğunu çıkarabilir.
+// Hash bir kez, mevcut hasher ile (aynı algoritma ve maliyet) üretilir.
+// SecurityConfig.LoginTimingEqualization kapalıysa hiçbir şey yapmaz.
+func (uc *AuthUseCase) equalizeLoginTiming(password string) {
+	if !uc.securityCfg.LoginTimingEqualization {
+		return
+	}
+	uc.dummyHashOnce.Do(func() {
+		uc.dummyHash, _ = uc.passwordHasher.Hash(dummyPassword)
+	})
+	if uc.dummyHash == "" {
+		return
+	}
+	uc.passwordHasher.Compare(uc.dummyHash, password)
+}
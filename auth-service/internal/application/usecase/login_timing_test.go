@@ -0,0 +1,44 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"auth-service/internal/application/dto"
+	"auth-service/internal/domain"
+	"auth-service/pkg/security"
+)
+
+// countingHasher counts the password comparisons made through it
+type countingHasher struct {
+	security.PasswordHasher
+	compares int
+}
+
+func (h *countingHasher) Compare(hash, password string) bool {
+	h.compares++
+	return h.PasswordHasher.Compare(hash, password)
+}
+
+func TestLoginHashesPasswordForUnknownUser(t *testing.T) {
+	for _, enabled := range []bool{true, false} {
+		cfg := testSecurityConfig()
+		cfg.LoginTimingEqualization = enabled
+		uc, deps := newTestUseCaseWithConfig(t, cfg)
+		seedUser(t, uc, deps, &domain.User{Email: "jane@example.com", Username: "jane", IsVerified: true}, "correct-horse")
+		hasher := &countingHasher{PasswordHasher: uc.passwordHasher}
+		uc.passwordHasher = hasher
+
+		_, err := uc.Login(context.Background(), &dto.LoginRequest{EmailOrUsername: "john", Password: "correct-horse"})
+		if err != ErrInvalidCredentials {
+			t.Fatalf("err = %v, want ErrInvalidCredentials", err)
+		}
+		want := 0
+		if enabled {
+			want = 1
+		}
+		if hasher.compares != want {
+			t.Errorf("enabled=%v: %d comparisons for an unknown user, want %d", enabled, hasher.compares, want)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"auth-service/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+func TestLogoutBlacklistsCurrentAccessToken(t *testing.T) {
+	uc, deps := newTestUseCase(t)
+	user := seedUser(t, uc, deps, &domain.User{Email: "jane@example.com", Username: "jane", IsVerified: true}, "correct-horse")
+
+	ctx := ContextWithAccessToken(context.Background(), "token-1", time.Now().Add(10*time.Minute))
+	if err := uc.Logout(ctx, user.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	revoked, err := deps.blacklist.Contains(context.Background(), "token-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !revoked {
+		t.Error("access token was not blacklisted")
+	}
+}
+
+func TestLogoutSkipsExpiredOrMissingAccessToken(t *testing.T) {
+	uc, deps := newTestUseCase(t)
+	userID := uuid.New()
+
+	if err := uc.Logout(context.Background(), userID); err != nil {
+		t.Fatalf("logout without token in context: %v", err)
+	}
+
+	ctx := ContextWithAccessToken(context.Background(), "expired", time.Now().Add(-time.Minute))
+	if err := uc.Logout(ctx, userID); err != nil {
+		t.Fatal(err)
+	}
+	if revoked, _ := deps.blacklist.Contains(context.Background(), "expired"); revoked {
+		t.Error("expired token should not be blacklisted")
+	}
+}
+
+func TestLogoutWithoutBlacklistConfigured(t *testing.T) {
+	uc, _ := newTestUseCaseWithConfig(t, testSecurityConfig(), WithTokenBlacklist(nopTokenBlacklist{}))
+
+	ctx := ContextWithAccessToken(context.Background(), "token-1", time.Now().Add(time.Minute))
+	if err := uc.Logout(ctx, uuid.New()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLogoutSessionRevokesOnlyThatSession(t *testing.T) {
+	uc, deps := newTestUseCase(t)
+	user := seedUser(t, uc, deps, &domain.User{Email: "jane@example.com", Username: "jane", IsVerified: true}, "correct-horse")
+	laptop := loginTokens(t, uc)
+	phone := loginTokens(t, uc)
+
+	ctx := ContextWithAccessToken(context.Background(), "laptop-access", time.Now().Add(10*time.Minute))
+	if err := uc.LogoutSession(ctx, user.ID, laptop.RefreshToken); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := uc.RefreshToken(context.Background(), laptop.RefreshToken); err == nil {
+		t.Error("signed out session can still refresh")
+	}
+	if _, err := uc.RefreshToken(context.Background(), phone.RefreshToken); err != nil {
+		t.Errorf("other device was signed out: %v", err)
+	}
+	if revoked, _ := deps.blacklist.Contains(context.Background(), "laptop-access"); !revoked {
+		t.Error("access token was not blacklisted")
+	}
+
+	// Already signed out
+	if err := uc.LogoutSession(context.Background(), user.ID, laptop.RefreshToken); err != ErrSessionNotFound {
+		t.Errorf("second logout = %v, want ErrSessionNotFound", err)
+	}
+}
+
+func TestLogoutSessionRejectsAnotherUsersToken(t *testing.T) {
+	uc, deps := newTestUseCase(t)
+	seedUser(t, uc, deps, &domain.User{Email: "jane@example.com", Username: "jane", IsVerified: true}, "correct-horse")
+	jane := loginTokens(t, uc)
+	mallory := seedUser(t, uc, deps, &domain.User{Email: "mallory@example.com", Username: "mallory", IsVerified: true}, "correct-horse")
+
+	if err := uc.LogoutSession(context.Background(), mallory.ID, jane.RefreshToken); err != ErrSessionNotFound {
+		t.Fatalf("err = %v, want ErrSessionNotFound", err)
+	}
+	if _, err := uc.RefreshToken(context.Background(), jane.RefreshToken); err != nil {
+		t.Errorf("another user's session was revoked: %v", err)
+	}
+}
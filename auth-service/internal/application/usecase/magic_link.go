@@ -0,0 +1,138 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"auth-service/internal/application/dto"
+	"auth-service/internal/domain"
+	"auth-service/pkg/security"
+
+	"github.com/google/uuid"
+)
+
+// errMagicLinkNotConfigured - WithMagicLinks verilmeden magic link login çağrıldı
+var errMagicLinkNotConfigured = errors.New("magic link login is not configured")
+
+// WithMagicLinks - Şifresiz giriş (magic link) token'larının saklandığı repository
+// Verilmezse RequestMagicLink ve LoginWithMagicLink hata döner.
+func WithMagicLinks(magicLinks domain.MagicLinkTokenRepository) AuthUseCaseOption {
+	return func(uc *AuthUseCase) {
+		uc.magicLinkRepo = magicLinks
+	}
+}
+
+// RequestMagicLink - Şifresiz giriş link'ini email ile gönderir
+// Şifre sıfırlama gibi, email kayıtlı değilse (veya hesap pasifse) de nil döner:
+// endpoint'ten hangi email'lerin kayıtlı olduğu öğrenilemez. Aynı sebeple mail arka planda
+// gönderilir (RequestPasswordReset gibi): SMTP hatası ve gönderim süresi cevaba yansımaz.
+// Token tek kullanımlık ve kısa ömürlüdür (MagicLinkToken.TTL); sadece SHA-256 hash'i saklanır.
+func (uc *AuthUseCase) RequestMagicLink(ctx context.Context, orgSlug, email string) (err error) {
+	defer translateContextError(ctx, &err)
+
+	if uc.magicLinkRepo == nil {
+		return errMagicLinkNotConfigured
+	}
+
+	// ADIM 1: Kullanıcıyı organizasyonunda bul - bulunamazsa sessizce başarılı dön
+	orgID, err := uc.resolveOrganization(ctx, orgSlug)
+	if errors.Is(err, ErrOrganizationNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	user, err := uc.userRepo.GetByEmail(ctx, orgID, email)
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !user.IsActive {
+		return nil
+	}
+
+	// ADIM 2: Önceki link'leri geçersiz kıl (sadece en son link çalışır)
+	if err := uc.magicLinkRepo.DeleteByUserID(ctx, user.ID); err != nil {
+		return err
+	}
+
+	// ADIM 3: Yeni random token üret ve hash'ini sakla
+	token, err := security.GenerateOpaqueToken(uc.securityCfg.MagicLinkToken.Bytes)
+	if err != nil {
+		return err
+	}
+	magicLink := &domain.MagicLinkToken{
+		UserID:    user.ID,
+		TokenHash: security.HashToken(token),
+		ExpiresAt: time.Now().Add(uc.securityCfg.MagicLinkToken.TTL),
+	}
+	if err := uc.magicLinkRepo.Create(ctx, magicLink); err != nil {
+		return err
+	}
+
+	// ADIM 4: Link'i arka planda gönder (hata sadece log'lanır)
+	uc.sendMailInBackground(ctx, "send magic link email", user.ID, user.Email, MailTemplateMagicLink, map[string]any{
+		"Username":  user.Username,
+		"Link":      fmt.Sprintf("%s/magic-link/callback?token=%s", uc.linkBaseURL, url.QueryEscape(token)),
+		"ExpiresIn": uc.securityCfg.MagicLinkToken.TTL,
+	})
+	return nil
+}
+
+// LoginWithMagicLink - Magic link token'ı ile giriş yapar
+// Token atomik olarak tüketilir: aynı link ikinci kez kullanılamaz.
+// Link'e tıklamak email'in sahibi olunduğunu kanıtlar, bu yüzden email doğrulanmış sayılır.
+func (uc *AuthUseCase) LoginWithMagicLink(ctx context.Context, token string) (_ *dto.AuthResponse, err error) {
+	defer translateContextError(ctx, &err)
+
+	if uc.magicLinkRepo == nil {
+		return nil, errMagicLinkNotConfigured
+	}
+
+	// ADIM 1: Token'ı atomik olarak tüket - eşzamanlı iki istekten sadece biri geçer
+	magicLink, err := uc.magicLinkRepo.Consume(ctx, security.HashToken(token))
+	if err != nil {
+		return nil, notFoundAs(err, ErrInvalidToken)
+	}
+
+	// ADIM 2: Kullanıcıyı bul
+	user, err := uc.userRepo.GetByID(ctx, magicLink.UserID)
+	if err != nil {
+		return nil, notFoundAs(err, ErrUserNotFound)
+	}
+
+	// ADIM 3: Şifreli login ile aynı hesap kontrolleri (şifre hariç)
+	if !user.IsActive {
+		return nil, ErrUserInactive
+	}
+	if user.IsLocked() {
+		return nil, ErrAccountLocked
+	}
+	if user.IsPendingApproval() {
+		return nil, ErrPendingApproval
+	}
+
+	// ADIM 4: Email doğrulanmamışsa şimdi doğrula (kritik değil)
+	if !user.IsVerified {
+		if err := uc.userRepo.MarkVerified(ctx, []uuid.UUID{user.ID}); err != nil {
+			uc.logError(ctx, "mark email verified", err, "user_id", user.ID)
+		} else {
+			user.IsVerified = true
+		}
+	}
+
+	// ADIM 5: Yeni cihaz uyarısı, son giriş zamanı (kritik değil) ve token'lar
+	uc.notifyNewDevice(ctx, user)
+	uc.recordLastLogin(ctx, user.ID)
+	response, err := uc.generateAuthResponse(ctx, user, nil, false)
+	if err != nil {
+		return nil, err
+	}
+	uc.logAudit(ctx, AuditLoginSuccess, user.ID, map[string]string{"method": "magic_link"})
+	return response, nil
+}
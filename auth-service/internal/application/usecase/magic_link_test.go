@@ -0,0 +1,142 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"auth-service/internal/domain"
+	"auth-service/pkg/security"
+)
+
+func newMagicLinkUseCase(t *testing.T, opts ...AuthUseCaseOption) (*AuthUseCase, *testDeps, *fakeMagicLinkRepo) {
+	t.Helper()
+	links := newFakeMagicLinkRepo()
+	uc, deps := newTestUseCaseWithConfig(t, testSecurityConfig(), append(opts, WithMagicLinks(links))...)
+	return uc, deps, links
+}
+
+// requestMagicLinkToken runs the magic link flow and returns the token from the emailed link
+func requestMagicLinkToken(t *testing.T, uc *AuthUseCase, deps *testDeps, email string) string {
+	t.Helper()
+	before := len(deps.mailer.Sent())
+	if err := uc.RequestMagicLink(context.Background(), "", email); err != nil {
+		t.Fatal(err)
+	}
+	uc.background.Wait()
+	if len(deps.mailer.Sent()) == before {
+		t.Fatal("expected a magic link email")
+	}
+	mail, _ := deps.mailer.Last()
+	return tokenFromMail(t, mail, MailTemplateMagicLink, "/magic-link/callback")
+}
+
+func TestRequestMagicLinkHidesMailFailures(t *testing.T) {
+	uc, deps, _ := newMagicLinkUseCase(t, WithMailer(failingMailer{}, "https://app.example.com"))
+	seedUser(t, uc, deps, &domain.User{Email: "jane@example.com", Username: "jane"}, "correct-horse")
+
+	// A registered email answers like an unknown one even when SMTP is down
+	if err := uc.RequestMagicLink(context.Background(), "", "jane@example.com"); err != nil {
+		t.Errorf("got %v, want nil", err)
+	}
+	uc.background.Wait()
+}
+
+func TestRequestMagicLinkUnknownEmail(t *testing.T) {
+	uc, deps, _ := newMagicLinkUseCase(t)
+
+	if err := uc.RequestMagicLink(context.Background(), "", "nobody@example.com"); err != nil {
+		t.Fatalf("got %v, want nil to avoid user enumeration", err)
+	}
+	if err := uc.RequestMagicLink(context.Background(), "acme", "nobody@example.com"); err != nil {
+		t.Fatalf("unknown organization: got %v, want nil", err)
+	}
+	if _, ok := deps.mailer.Last(); ok {
+		t.Error("no email should be sent")
+	}
+}
+
+func TestRequestMagicLinkStoresOnlyHash(t *testing.T) {
+	uc, deps, links := newMagicLinkUseCase(t)
+	seedUser(t, uc, deps, &domain.User{Email: "jane@example.com", Username: "jane"}, "correct-horse")
+
+	token := requestMagicLinkToken(t, uc, deps, "jane@example.com")
+	if len(links.tokens) != 1 {
+		t.Fatalf("stored %d tokens, want 1", len(links.tokens))
+	}
+	for hash, stored := range links.tokens {
+		if hash != security.HashToken(token) {
+			t.Errorf("unexpected stored hash %q", hash)
+		}
+		if ttl := time.Until(stored.ExpiresAt); ttl > uc.securityCfg.MagicLinkToken.TTL {
+			t.Errorf("token expires in %s, want at most %s", ttl, uc.securityCfg.MagicLinkToken.TTL)
+		}
+	}
+}
+
+func TestLoginWithMagicLink(t *testing.T) {
+	ctx := context.Background()
+	audit := &fakeAuditLogger{}
+	uc, deps, _ := newMagicLinkUseCase(t, WithAuditLogger(audit))
+	user := seedUser(t, uc, deps, &domain.User{Email: "jane@example.com", Username: "jane"}, "correct-horse")
+
+	token := requestMagicLinkToken(t, uc, deps, "jane@example.com")
+	resp, err := uc.LoginWithMagicLink(ctx, token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.AccessToken == "" || resp.RefreshToken == "" || resp.User.ID != user.ID.String() {
+		t.Errorf("response = %+v", resp)
+	}
+	if got, _ := deps.users.GetByID(ctx, user.ID); !got.IsVerified {
+		t.Error("magic link login should mark the email verified")
+	}
+	if len(audit.events) != 1 || audit.events[0].Details["method"] != "magic_link" {
+		t.Errorf("audit events = %+v", audit.events)
+	}
+
+	if _, err := uc.LoginWithMagicLink(ctx, token); err != ErrInvalidToken {
+		t.Errorf("reused link: err = %v, want ErrInvalidToken", err)
+	}
+}
+
+func TestLoginWithMagicLinkRejects(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("unknown token", func(t *testing.T) {
+		uc, _, _ := newMagicLinkUseCase(t)
+		if _, err := uc.LoginWithMagicLink(ctx, "nope"); err != ErrInvalidToken {
+			t.Errorf("err = %v, want ErrInvalidToken", err)
+		}
+	})
+
+	t.Run("expired token", func(t *testing.T) {
+		uc, deps, links := newMagicLinkUseCase(t)
+		seedUser(t, uc, deps, &domain.User{Email: "jane@example.com", Username: "jane"}, "correct-horse")
+		token := requestMagicLinkToken(t, uc, deps, "jane@example.com")
+		links.tokens[security.HashToken(token)].ExpiresAt = time.Now().Add(-time.Second)
+		if _, err := uc.LoginWithMagicLink(ctx, token); err != ErrInvalidToken {
+			t.Errorf("err = %v, want ErrInvalidToken", err)
+		}
+	})
+
+	t.Run("locked account", func(t *testing.T) {
+		uc, deps, _ := newMagicLinkUseCase(t)
+		lockedUntil := time.Now().Add(time.Hour)
+		seedUser(t, uc, deps, &domain.User{Email: "jane@example.com", Username: "jane", LockedUntil: &lockedUntil}, "correct-horse")
+		token := requestMagicLinkToken(t, uc, deps, "jane@example.com")
+		if _, err := uc.LoginWithMagicLink(ctx, token); err != ErrAccountLocked {
+			t.Errorf("err = %v, want ErrAccountLocked", err)
+		}
+	})
+}
+
+func TestMagicLinkNotConfigured(t *testing.T) {
+	uc, _ := newTestUseCase(t)
+	if err := uc.RequestMagicLink(context.Background(), "", "jane@example.com"); err != errMagicLinkNotConfigured {
+		t.Errorf("RequestMagicLink: err = %v", err)
+	}
+	if _, err := uc.LoginWithMagicLink(context.Background(), "token"); err != errMagicLinkNotConfigured {
+		t.Errorf("LoginWithMagicLink: err = %v", err)
+	}
+}
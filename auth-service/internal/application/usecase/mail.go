@@ -0,0 +1,21 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// sendMailInBackground - Email'i arka planda gönderir; çağıran gönderimi beklemez
+// Hata sadece log'lanır (action = log mesajı). İstek bitince iptal edilen context'ten kopar:
+// email cevaptan sonra da gidebilsin. Stop ve test'ler uc.background ile bitmesini bekler.
+func (uc *AuthUseCase) sendMailInBackground(ctx context.Context, action string, userID uuid.UUID, to, template string, data map[string]any) {
+	ctx = context.WithoutCancel(ctx)
+	uc.background.Add(1)
+	go func() {
+		defer uc.background.Done()
+		if err := uc.mailer.Send(ctx, to, template, data); err != nil {
+			uc.logError(ctx, action, err, "user_id", userID)
+		}
+	}()
+}
@@ -0,0 +1,39 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"auth-service/internal/domain"
+)
+
+// notifyNewDevice - Login daha önce görülmemiş bir cihazdan (IP + User-Agent) geldiyse
+// kullanıcıya "yeni giriş" uyarı email'i gönderir.
+// Yeni refresh token oluşturulmadan ÖNCE çağrılmalı, yoksa cihaz kendini "görülmüş" sayar.
+// Best-effort: kontrol veya gönderim hatası sadece log'lanır, email arka planda gönderilir;
+// login hiçbir zaman beklemez veya başarısız olmaz.
+func (uc *AuthUseCase) notifyNewDevice(ctx context.Context, user *domain.User) {
+	client := clientFromContext(ctx)
+	// Cihaz bilgisi yok (iç çağrı) veya ilk login: karşılaştırılacak bir şey yok
+	if !client.known() || user.LastLoginAt == nil {
+		return
+	}
+
+	fingerprint := domain.DeviceFingerprint(client.ipAddress, client.userAgent)
+	seen, err := uc.refreshTokenRepo.HasSeenDevice(ctx, user.ID, fingerprint)
+	if err != nil {
+		uc.logError(ctx, "check login device", err, "user_id", user.ID)
+		return
+	}
+	if seen {
+		return
+	}
+
+	data := map[string]any{
+		"Username":  user.Username,
+		"IPAddress": client.ipAddress,
+		"UserAgent": client.userAgent,
+		"Time":      time.Now().UTC().Format(time.RFC1123),
+	}
+	uc.sendMailInBackground(ctx, "send new sign-in alert", user.ID, user.Email, MailTemplateNewSignIn, data)
+}
@@ -0,0 +1,81 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"auth-service/internal/application/dto"
+	"auth-service/internal/domain"
+)
+
+// failingMailer rejects every email
+type failingMailer struct{}
+
+func (failingMailer) Send(ctx context.Context, to, template string, data map[string]any) error {
+	return errors.New("smtp unavailable")
+}
+
+func loginFrom(t *testing.T, uc *AuthUseCase, userAgent, ip string) {
+	t.Helper()
+	ctx := ContextWithClient(context.Background(), userAgent, ip)
+	if _, err := uc.Login(ctx, &dto.LoginRequest{EmailOrUsername: "jane", Password: "correct-horse"}); err != nil {
+		t.Fatal(err)
+	}
+	uc.background.Wait()
+}
+
+func newSignInAlerts(deps *testDeps) int {
+	n := 0
+	for _, mail := range deps.mailer.Sent() {
+		if mail.Template == MailTemplateNewSignIn {
+			n++
+		}
+	}
+	return n
+}
+
+func TestLoginFromNewDeviceSendsAlert(t *testing.T) {
+	uc, deps := newTestUseCase(t)
+	seedUser(t, uc, deps, &domain.User{Email: "jane@example.com", Username: "jane", IsVerified: true}, "correct-horse")
+
+	// The first login has no earlier device to compare with
+	loginFrom(t, uc, "Firefox", "198.51.100.1")
+	loginFrom(t, uc, "Firefox", "198.51.100.1")
+	if n := newSignInAlerts(deps); n != 0 {
+		t.Fatalf("known device: %d alerts, want 0", n)
+	}
+
+	loginFrom(t, uc, "Safari", "203.0.113.7")
+	mail, _ := deps.mailer.Last()
+	if mail.Template != MailTemplateNewSignIn || mail.To != "jane@example.com" ||
+		mail.Data["IPAddress"] != "203.0.113.7" || mail.Data["UserAgent"] != "Safari" {
+		t.Fatalf("last mail = %+v, want a new sign-in alert", mail)
+	}
+
+	// Same user agent from another IP is another device
+	loginFrom(t, uc, "Safari", "203.0.113.8")
+	loginFrom(t, uc, "Safari", "203.0.113.7")
+	if n := newSignInAlerts(deps); n != 2 {
+		t.Errorf("%d alerts, want 2", n)
+	}
+}
+
+func TestLoginWithoutClientInfoSendsNoAlert(t *testing.T) {
+	uc, deps := newTestUseCase(t)
+	seedUser(t, uc, deps, &domain.User{Email: "jane@example.com", Username: "jane", IsVerified: true}, "correct-horse")
+
+	loginFrom(t, uc, "Firefox", "198.51.100.1")
+	loginFrom(t, uc, "", "")
+	if n := newSignInAlerts(deps); n != 0 {
+		t.Errorf("%d alerts, want 0", n)
+	}
+}
+
+func TestNewDeviceAlertFailureDoesNotFailLogin(t *testing.T) {
+	uc, deps := newTestUseCaseWithConfig(t, testSecurityConfig(), WithMailer(failingMailer{}, "https://app.example.com"))
+	seedUser(t, uc, deps, &domain.User{Email: "jane@example.com", Username: "jane", IsVerified: true}, "correct-horse")
+
+	loginFrom(t, uc, "Firefox", "198.51.100.1")
+	loginFrom(t, uc, "Safari", "203.0.113.7")
+}
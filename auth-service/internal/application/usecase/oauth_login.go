@@ -0,0 +1,230 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"auth-service/internal/application/dto"
+	"auth-service/internal/domain"
+	"auth-service/pkg/security"
+
+	"github.com/google/uuid"
+)
+
+// ErrOAuthEmailNotVerified - Provider email'i doğrulanmamış; mevcut hesaba bağlanamaz / hesap açılamaz
+// Doğrulanmamış email ile bağlamak, başkasının email'ini provider'a yazan saldırgana hesabı teslim ederdi
+var ErrOAuthEmailNotVerified = newAppError(http.StatusForbidden, "oauth_email_not_verified", "The identity provider has not verified this email address")
+
+// ErrOAuthAccountUnverified - Aynı email'li yerel hesap var ama email'i doğrulanmamış; provider bağlanmaz
+// Saldırgan kurbanın email'iyle şifreli hesap açıp bekleyebilir (pre-account takeover): bağlansaydı
+// kurban Google ile girdiğinde saldırganın bildiği şifre de aynı hesapta geçerli kalırdı.
+var ErrOAuthAccountUnverified = newAppError(http.StatusConflict, "oauth_account_unverified", "An account with this email exists but its email is not verified; verify it before signing in with this provider")
+
+// errOAuthNotConfigured - WithOAuthAccounts verilmeden social login çağrıldı
+var errOAuthNotConfigured = errors.New("oauth login is not configured")
+
+// OAuthProvider - Harici kimlik sağlayıcı portu (Google, GitHub...)
+// Implementasyonlar infrastructure/oauth paketinde, her provider kendi dosyasında.
+type OAuthProvider interface {
+	// AuthURL - Kullanıcının yönlendirileceği onay ekranı; state callback'e aynen döner
+	AuthURL(state string) string
+	// Exchange - Authorization code'u provider access token'ı ile değiştirir
+	Exchange(ctx context.Context, code string) (string, error)
+	// FetchUser - Access token'ın sahibini normalize edilmiş haliyle döner
+	FetchUser(ctx context.Context, accessToken string) (*ExternalUser, error)
+}
+
+// ExternalUser - Provider'dan alınan, provider'dan bağımsız kullanıcı bilgisi
+type ExternalUser struct {
+	ProviderID    string // Provider'daki sabit kullanıcı ID'si (Google: "sub", GitHub: "id")
+	Email         string
+	EmailVerified bool
+	Username      string // Provider'daki kullanıcı adı (GitHub: "login"), yoksa boş
+	FirstName     string
+	LastName      string
+}
+
+// LoginWithOAuth - Social login ("Sign in with Google/GitHub") ile giriş yapar
+// Kullanıcı sırasıyla şöyle bulunur:
+// 1. Daha önce bağlanmış hesap (provider + provider_user_id)
+// 2. Aynı (doğrulanmış) email'e sahip mevcut kullanıcı -> provider hesabı bağlanır
+// 3. Hiçbiri yoksa yeni kullanıcı oluşturulur (email doğrulanmış, şifresi rastgele)
+// Sonunda normal login gibi kendi token'larımız döner.
+func (uc *AuthUseCase) LoginWithOAuth(ctx context.Context, provider string, external ExternalUser) (_ *dto.AuthResponse, err error) {
+	defer translateContextError(ctx, &err)
+
+	if uc.oauthAccounts == nil {
+		return nil, errOAuthNotConfigured
+	}
+
+	// ADIM 1: Kullanıcıyı bul, bağla veya oluştur
+	user, err := uc.oauthUser(ctx, provider, external)
+	if err != nil {
+		return nil, err
+	}
+
+	// ADIM 2: Şifreli login ile aynı hesap kontrolleri
+	// (şifre ve email doğrulaması yok: kimliği provider doğruladı)
+	if !user.IsActive {
+		return nil, ErrUserInactive
+	}
+	if user.IsLocked() {
+		return nil, ErrAccountLocked
+	}
+	if user.IsPendingApproval() {
+		return nil, ErrPendingApproval
+	}
+
+	// ADIM 3: Yeni cihaz uyarısı, son giriş zamanı (kritik değil) ve token'lar
+	uc.notifyNewDevice(ctx, user)
+	uc.recordLastLogin(ctx, user.ID)
+	response, err := uc.generateAuthResponse(ctx, user, nil, false)
+	if err != nil {
+		return nil, err
+	}
+	uc.logAudit(ctx, AuditLoginSuccess, user.ID, map[string]string{"method": "oauth", "provider": provider})
+	return response, nil
+}
+
+// oauthUser - Provider hesabına bağlı kullanıcıyı döner; yoksa bağlar veya oluşturur
+func (uc *AuthUseCase) oauthUser(ctx context.Context, provider string, external ExternalUser) (*domain.User, error) {
+	// Daha önce bağlanmış hesap
+	account, err := uc.oauthAccounts.GetByProviderUserID(ctx, provider, external.ProviderID)
+	if err == nil {
+		user, err := uc.userRepo.GetByID(ctx, account.UserID)
+		if err != nil {
+			return nil, notFoundAs(err, ErrUserNotFound)
+		}
+		return user, nil
+	}
+	if !errors.Is(err, domain.ErrNotFound) {
+		return nil, err
+	}
+
+	// Bağlama ve hesap açma sadece provider'ın doğruladığı email ile
+	if external.Email == "" || !external.EmailVerified {
+		return nil, ErrOAuthEmailNotVerified
+	}
+
+	// Social login kullanıcıları varsayılan organizasyondadır
+	orgID, err := uc.resolveOrganization(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+	user, err := uc.userRepo.GetByEmail(ctx, orgID, external.Email)
+	if errors.Is(err, domain.ErrNotFound) {
+		user, err = uc.createOAuthUser(ctx, orgID, external)
+	}
+	if err != nil {
+		return nil, err
+	}
+	// Sadece email'i doğrulanmış hesaba bağlanır: doğrulanmamış hesabı email'in sahibi açmamış olabilir
+	if !user.IsVerified {
+		return nil, ErrOAuthAccountUnverified
+	}
+
+	account = &domain.OAuthAccount{
+		UserID:         user.ID,
+		Provider:       provider,
+		ProviderUserID: external.ProviderID,
+		Email:          external.Email,
+	}
+	if err := uc.oauthAccounts.Create(ctx, account); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// createOAuthUser - Social login ile ilk kez gelen kullanıcı için hesap açar
+// Şifre rastgeledir (kimse bilmez): kullanıcı isterse "şifremi unuttum" ile şifre belirler.
+func (uc *AuthUseCase) createOAuthUser(ctx context.Context, orgID uuid.UUID, external ExternalUser) (*domain.User, error) {
+	// Kayıt kapalıysa veya davet gerekiyorsa social login sadece mevcut hesaplara girer
+	if err := uc.checkAccountCreation(); err != nil {
+		return nil, err
+	}
+
+	password, err := security.GenerateOpaqueToken(32)
+	if err != nil {
+		return nil, err
+	}
+	passwordHash, err := uc.passwordHasher.Hash(password)
+	if err != nil {
+		return nil, err
+	}
+	username, err := uc.availableUsername(ctx, orgID, external)
+	if err != nil {
+		return nil, err
+	}
+
+	user := &domain.User{
+		Email:         external.Email,
+		Username:      username,
+		PasswordHash:  passwordHash,
+		FirstName:     external.FirstName,
+		LastName:      external.LastName,
+		IsActive:      true,
+		IsVerified:    true, // Email'i provider doğruladı
+		PasswordUnset: true, // Şifreyi kimse bilmiyor; son provider bağlantısı kaldırılamaz
+		Status:        domain.UserStatusActive,
+		Role:          domain.RoleUser,
+	}
+	user.OrganizationID = orgID
+	if uc.approvalRequired {
+		user.Status = domain.UserStatusPendingApproval
+	}
+	if err := uc.userRepo.Create(ctx, user); err != nil {
+		return nil, err
+	}
+	uc.publishUserEvent(ctx, EventUserRegistered, user, map[string]interface{}{"method": "oauth"})
+
+	// Onay bekleyen hesap: admin'ler Register'daki gibi webhook ile haberdar edilir
+	if user.IsPendingApproval() {
+		if err := uc.events.Publish(ctx, "user.pending_approval", map[string]interface{}{
+			"user_id":  user.ID.String(),
+			"email":    user.Email,
+			"username": user.Username,
+		}); err != nil {
+			uc.logError(ctx, "publish user.pending_approval", err, "user_id", user.ID)
+		}
+	}
+	return user, nil
+}
+
+// availableUsername - Provider'daki kullanıcı adından, yoksa email'in @ öncesinden
+// orgID organizasyonunda kullanılabilir bir username türetir
+// "jane.doe@example.com" -> "jane.doe", alınmışsa "jane.doe2", "jane.doe3"...
+func (uc *AuthUseCase) availableUsername(ctx context.Context, orgID uuid.UUID, external ExternalUser) (string, error) {
+	base := external.Username
+	if base == "" {
+		base, _, _ = strings.Cut(external.Email, "@")
+	}
+	if len(base) < 3 {
+		base += "_user"
+	}
+	if len(base) > 40 {
+		base = base[:40]
+	}
+
+	for i := 1; i <= 100; i++ {
+		candidate := base
+		if i > 1 {
+			candidate = fmt.Sprintf("%s%d", base, i)
+		}
+		// Rezerve veya karıştırılabilir aday alınmış sayılır: "admin" -> "admin2"
+		candidate, err := uc.checkUsername(candidate)
+		if err != nil {
+			continue
+		}
+		exists, err := uc.userRepo.ExistsByUsername(ctx, orgID, candidate)
+		if err != nil {
+			return "", err
+		}
+		if !exists {
+			return candidate, nil
+		}
+	}
+	return "", ErrUserAlreadyExists
+}
@@ -0,0 +1,115 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"auth-service/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+func TestLoginWithOAuthCreatesVerifiedUser(t *testing.T) {
+	accounts := &fakeOAuthAccountRepo{}
+	uc, deps := newTestUseCaseWithConfig(t, testSecurityConfig(), WithOAuthAccounts(accounts))
+	// The derived username is taken, so a suffix is added
+	seedUser(t, uc, deps, &domain.User{Email: "other@example.com", Username: "jane"}, "correct-horse")
+
+	external := ExternalUser{ProviderID: "g-1", Email: "jane@example.com", EmailVerified: true, FirstName: "Jane"}
+	resp, err := uc.LoginWithOAuth(context.Background(), "google", external)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.AccessToken == "" || resp.User.Email != "jane@example.com" || resp.User.Username != "jane2" {
+		t.Errorf("response = %+v, user = %+v", resp, resp.User)
+	}
+	user, err := deps.users.GetByEmail(context.Background(), uuid.Nil, "jane@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !user.IsVerified || user.Role != domain.RoleUser || user.PasswordHash == "" {
+		t.Errorf("created user = %+v", user)
+	}
+
+	// The second login finds the linked account, even if the email changed at the provider
+	external.Email = "jane@new.example.com"
+	again, err := uc.LoginWithOAuth(context.Background(), "google", external)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.User.ID != resp.User.ID || len(accounts.accounts) != 1 {
+		t.Errorf("second login user = %s, accounts = %d", again.User.ID, len(accounts.accounts))
+	}
+}
+
+func TestLoginWithOAuthLinksExistingUserByVerifiedEmail(t *testing.T) {
+	accounts := &fakeOAuthAccountRepo{}
+	uc, deps := newTestUseCaseWithConfig(t, testSecurityConfig(), WithOAuthAccounts(accounts))
+	existing := seedUser(t, uc, deps, &domain.User{Email: "jane@example.com", Username: "jane", IsVerified: true}, "correct-horse")
+
+	// An unverified provider email must not take over the account
+	_, err := uc.LoginWithOAuth(context.Background(), "google", ExternalUser{ProviderID: "g-1", Email: "jane@example.com"})
+	if err != ErrOAuthEmailNotVerified {
+		t.Fatalf("unverified email: got %v, want ErrOAuthEmailNotVerified", err)
+	}
+
+	resp, err := uc.LoginWithOAuth(context.Background(), "google", ExternalUser{ProviderID: "g-1", Email: "jane@example.com", EmailVerified: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.User.ID != existing.ID.String() {
+		t.Errorf("logged in as %s, want existing user %s", resp.User.ID, existing.ID)
+	}
+	if len(accounts.accounts) != 1 || accounts.accounts[0].UserID != existing.ID || accounts.accounts[0].Provider != "google" {
+		t.Errorf("accounts = %+v", accounts.accounts)
+	}
+}
+
+func TestLoginWithOAuthDoesNotLinkUnverifiedUser(t *testing.T) {
+	accounts := &fakeOAuthAccountRepo{}
+	uc, deps := newTestUseCaseWithConfig(t, testSecurityConfig(), WithOAuthAccounts(accounts))
+	// Registered with the victim's email by someone who knows the password
+	seedUser(t, uc, deps, &domain.User{Email: "jane@example.com", Username: "jane"}, "attacker-password")
+
+	_, err := uc.LoginWithOAuth(context.Background(), "google", ExternalUser{ProviderID: "g-1", Email: "jane@example.com", EmailVerified: true})
+	if err != ErrOAuthAccountUnverified {
+		t.Fatalf("got %v, want ErrOAuthAccountUnverified", err)
+	}
+	if len(accounts.accounts) != 0 {
+		t.Errorf("accounts = %+v, want none", accounts.accounts)
+	}
+}
+
+func TestLoginWithOAuthRespectsAccountState(t *testing.T) {
+	uc, deps := newTestUseCaseWithConfig(t, testSecurityConfig(), WithOAuthAccounts(&fakeOAuthAccountRepo{}), WithRegistrationApproval(true))
+
+	_, err := uc.LoginWithOAuth(context.Background(), "google", ExternalUser{ProviderID: "g-1", Email: "jane@example.com", EmailVerified: true})
+	if err != ErrPendingApproval {
+		t.Errorf("new user with approval required: got %v, want ErrPendingApproval", err)
+	}
+	if names := deps.events.names(); len(names) != 2 || names[0] != "user.registered" || names[1] != "user.pending_approval" {
+		t.Errorf("events = %v", names)
+	}
+}
+
+func TestLoginWithOAuthPrefersProviderUsername(t *testing.T) {
+	accounts := &fakeOAuthAccountRepo{}
+	uc, _ := newTestUseCaseWithConfig(t, testSecurityConfig(), WithOAuthAccounts(accounts))
+
+	external := ExternalUser{ProviderID: "42", Email: "jane@example.com", EmailVerified: true, Username: "octojane"}
+	resp, err := uc.LoginWithOAuth(context.Background(), "github", external)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.User.Username != "octojane" {
+		t.Errorf("username = %q, want the GitHub login", resp.User.Username)
+	}
+
+	// The same provider user ID at another provider is a different account
+	if _, err := uc.LoginWithOAuth(context.Background(), "google", ExternalUser{ProviderID: "42", Email: "jane@example.com", EmailVerified: true}); err != nil {
+		t.Fatal(err)
+	}
+	if len(accounts.accounts) != 2 || accounts.accounts[1].Provider != "google" || accounts.accounts[1].UserID != accounts.accounts[0].UserID {
+		t.Errorf("accounts = %+v", accounts.accounts)
+	}
+}
@@ -0,0 +1,180 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"auth-service/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+// Mailer - Dışarıya email gönderen port (interface)
+// Use case'ler SMTP gibi detayları bilmez, sadece bu interface'i kullanır.
+// template = gönderilecek email'in adı (MailTemplate*), data = template'e verilen değerler.
+// Konu ve HTML/text gövdeler implementasyonda render edilir (infrastructure/mailer/templates).
+type Mailer interface {
+	Send(ctx context.Context, to, template string, data map[string]any) error
+}
+
+// Email template'leri - her birinin konusu ve gövdeleri infrastructure/mailer/templates'te
+const (
+	// MailTemplateVerifyEmail - data: Username, Link, ExpiresIn
+	MailTemplateVerifyEmail = "verify_email"
+	// MailTemplatePasswordReset - data: Username, Link, ExpiresIn
+	MailTemplatePasswordReset = "password_reset"
+	// MailTemplateMagicLink - data: Username, Link, ExpiresIn
+	MailTemplateMagicLink = "magic_link"
+	// MailTemplateAccountApproved - data: Username
+	MailTemplateAccountApproved = "account_approved"
+	// MailTemplateNewSignIn - data: Username, IPAddress, UserAgent, Time
+	MailTemplateNewSignIn = "new_sign_in"
+	// MailTemplateAccountLocked - data: Username, IPAddress, Time, LockedUntil
+	MailTemplateAccountLocked = "account_locked"
+	// MailTemplateTemporaryPassword - data: Username, Password
+	MailTemplateTemporaryPassword = "temporary_password"
+)
+
+// nopMailer - Mailer verilmediğinde kullanılan boş implementasyon
+type nopMailer struct{}
+
+func (nopMailer) Send(ctx context.Context, to, template string, data map[string]any) error {
+	return nil
+}
+
+// EventPublisher - Hesap olaylarını dış sistemlere (webhook vs.) bildiren port
+// event örn: "user.pending_approval", data JSON'a çevrilebilir olmalı.
+type EventPublisher interface {
+	Publish(ctx context.Context, event string, data map[string]interface{}) error
+}
+
+// nopEventPublisher - Publisher verilmediğinde olaylar sessizce atılır
+type nopEventPublisher struct{}
+
+func (nopEventPublisher) Publish(ctx context.Context, event string, data map[string]interface{}) error {
+	return nil
+}
+
+// BreachChecker - Şifrenin bilinen bir veri sızıntısında geçip geçmediğini söyleyen port
+// Implementasyon: infrastructure/hibp (HaveIBeenPwned k-anonymity range API)
+type BreachChecker interface {
+	CheckBreached(ctx context.Context, password string) (bool, error)
+}
+
+// AuditEvent - Güvenlik açısından önemli bir işlemin kaydı (kim, neyi, kime, nereden, ne zaman yaptı)
+type AuditEvent struct {
+	Action    string            // örn: "user.approved", "login_failed"
+	ActorID   uuid.UUID         // İşlemi yapan (admin veya kullanıcının kendisi)
+	TargetID  uuid.UUID         // İşlemden etkilenen kullanıcı
+	IPAddress string            // İsteğin geldiği IP (ContextWithClient)
+	UserAgent string            // İsteği yapan client
+	RequestID string            // İsteğin ID'si (X-Request-ID); boşsa logger context'ten alır
+	Time      time.Time         // Olay zamanı; boşsa logger kayıt anını kullanır
+	Details   map[string]string // Ek bilgiler (opsiyonel)
+}
+
+// AuditLogger - Audit kayıtlarını yazan port
+type AuditLogger interface {
+	Log(ctx context.Context, event AuditEvent)
+}
+
+// nopAuditLogger - AuditLogger verilmediğinde kullanılan boş implementasyon
+type nopAuditLogger struct{}
+
+func (nopAuditLogger) Log(ctx context.Context, event AuditEvent) {}
+
+// nopTokenBlacklist - Blacklist verilmediğinde access token'lar süresi dolana kadar geçerli kalır
+type nopTokenBlacklist struct{}
+
+func (nopTokenBlacklist) Add(ctx context.Context, tokenID string, ttl time.Duration) error {
+	return nil
+}
+
+func (nopTokenBlacklist) Contains(ctx context.Context, tokenID string) (bool, error) {
+	return false, nil
+}
+
+// nopTransactioner - Transactioner verilmediğinde fn transaction'sız çalışır
+// (yarıda kalan bir işlemin yazdıkları geri alınmaz)
+type nopTransactioner struct{}
+
+func (nopTransactioner) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+// AuthUseCaseOption - NewAuthUseCase'e opsiyonel bağımlılık vermek için (functional options pattern)
+// Zorunlu bağımlılıklar (repository'ler, servisler) constructor parametresidir;
+// opsiyonel olanlar (mailer vs.) bu option'larla verilir ve verilmezse no-op kullanılır.
+type AuthUseCaseOption func(*AuthUseCase)
+
+// WithMailer - Email gönderimi için mailer ve link'lerin üretileceği frontend URL'i
+// Örn: linkBaseURL = "https://app.example.com" -> https://app.example.com/verify-email?token=...
+func WithMailer(mailer Mailer, linkBaseURL string) AuthUseCaseOption {
+	return func(uc *AuthUseCase) {
+		uc.mailer = mailer
+		uc.linkBaseURL = linkBaseURL
+	}
+}
+
+// WithEventPublisher - Hesap olaylarının (örn: onay bekleyen yeni kullanıcı) gönderileceği publisher
+func WithEventPublisher(events EventPublisher) AuthUseCaseOption {
+	return func(uc *AuthUseCase) {
+		uc.events = events
+	}
+}
+
+// WithRegistrationApproval - true ise yeni kullanıcılar "pending_approval" durumunda oluşturulur
+// ve bir admin onaylayana kadar login olamaz
+func WithRegistrationApproval(required bool) AuthUseCaseOption {
+	return func(uc *AuthUseCase) {
+		uc.approvalRequired = required
+	}
+}
+
+// WithTokenBlacklist - Logout'ta mevcut access token'ın eklendiği blacklist
+// Verilmezse access token logout'tan sonra da süresi dolana kadar geçerlidir.
+func WithTokenBlacklist(blacklist domain.TokenBlacklist) AuthUseCaseOption {
+	return func(uc *AuthUseCase) {
+		uc.tokenBlacklist = blacklist
+	}
+}
+
+// WithRememberMe - "Beni hatırla" işaretli login'lerin refresh token süresi (örn: 30 gün)
+// Verilmezse remember_me yok sayılır ve tüm refresh token'lar refreshTokenTTL kadar yaşar.
+func WithRememberMe(ttl time.Duration) AuthUseCaseOption {
+	return func(uc *AuthUseCase) {
+		uc.rememberMeTTL = ttl
+	}
+}
+
+// WithRefreshAbsoluteTTL - Refresh ile uzayan oturumun login'den itibaren en uzun ömrü (örn: 90 gün)
+// Süre dolunca refresh ErrInvalidToken döner ve kullanıcı tekrar login olur. 0 = sınır yok.
+func WithRefreshAbsoluteTTL(ttl time.Duration) AuthUseCaseOption {
+	return func(uc *AuthUseCase) {
+		uc.refreshAbsoluteTTL = ttl
+	}
+}
+
+// WithOAuthAccounts - Social login (Google vs.) için provider hesaplarının saklandığı repository
+// Verilmezse LoginWithOAuth hata döner.
+func WithOAuthAccounts(accounts domain.OAuthAccountRepository) AuthUseCaseOption {
+	return func(uc *AuthUseCase) {
+		uc.oauthAccounts = accounts
+	}
+}
+
+// WithBreachChecker - Yeni şifreler sızıntı listesinde aranır (PASSWORD_BREACH_CHECK)
+// Verilmezse kontrol yapılmaz.
+func WithBreachChecker(checker BreachChecker) AuthUseCaseOption {
+	return func(uc *AuthUseCase) {
+		uc.breachChecker = checker
+	}
+}
+
+// WithTransactioner - Birden fazla yazma yapan işlemler (örn: Register) tek transaction'da çalışır
+// Verilmezse yazmalar tek tek yapılır ve hata olursa geri alınmaz.
+func WithTransactioner(transactions domain.Transactioner) AuthUseCaseOption {
+	return func(uc *AuthUseCase) {
+		uc.transactions = transactions
+	}
+}
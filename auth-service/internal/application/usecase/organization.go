@@ -0,0 +1,41 @@
+package usecase
+
+import (
+	"context"
+	"net/http"
+
+	"auth-service/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+// ErrOrganizationNotFound - Kayıtta verilen organization_slug ile bir organizasyon yok
+var ErrOrganizationNotFound = newAppError(http.StatusNotFound, "organization_not_found", "Organization not found")
+
+// WithOrganizations - Multi-tenancy: kullanıcılar organization_slug ile bir organizasyona kaydolur
+// Slug verilmezse varsayılan organizasyon (domain.DefaultOrganizationSlug) kullanılır.
+// Verilmezse tek tenant: tüm kullanıcılar uuid.Nil organizasyonundadır ve slug kabul edilmez.
+func WithOrganizations(organizations domain.OrganizationRepository) AuthUseCaseOption {
+	return func(uc *AuthUseCase) {
+		uc.organizations = organizations
+	}
+}
+
+// resolveOrganization - organization_slug'ı organizasyon ID'sine çevirir
+// Boş slug varsayılan organizasyondur; bilinmeyen slug ErrOrganizationNotFound döner.
+func (uc *AuthUseCase) resolveOrganization(ctx context.Context, slug string) (uuid.UUID, error) {
+	if uc.organizations == nil {
+		if slug != "" {
+			return uuid.Nil, ErrOrganizationNotFound
+		}
+		return uuid.Nil, nil
+	}
+	if slug == "" {
+		slug = domain.DefaultOrganizationSlug
+	}
+	org, err := uc.organizations.GetBySlug(ctx, slug)
+	if err != nil {
+		return uuid.Nil, notFoundAs(err, ErrOrganizationNotFound)
+	}
+	return org.ID, nil
+}
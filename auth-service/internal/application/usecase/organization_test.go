@@ -0,0 +1,95 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"auth-service/internal/application/dto"
+	"auth-service/internal/domain"
+)
+
+// newMultiTenantUseCase returns a use case with the default organization and "acme"
+func newMultiTenantUseCase(t *testing.T) (*AuthUseCase, *domain.Organization, *domain.Organization) {
+	t.Helper()
+	orgs := &fakeOrganizationRepo{}
+	defaultOrg := &domain.Organization{Name: "Default", Slug: domain.DefaultOrganizationSlug}
+	acme := &domain.Organization{Name: "Acme", Slug: "acme"}
+	for _, org := range []*domain.Organization{defaultOrg, acme} {
+		if err := orgs.Create(context.Background(), org); err != nil {
+			t.Fatal(err)
+		}
+	}
+	uc, _ := newTestUseCaseWithConfig(t, testSecurityConfig(), WithOrganizations(orgs))
+	return uc, defaultOrg, acme
+}
+
+func registerIn(uc *AuthUseCase, orgSlug string) (*dto.AuthResponse, error) {
+	return uc.Register(context.Background(), &dto.RegisterRequest{
+		Email: "jane@example.com", Username: "jane", Password: "correct-horse-battery",
+		FirstName: "Jane", LastName: "Doe", OrganizationSlug: orgSlug,
+	})
+}
+
+func TestRegisterUniquePerOrganization(t *testing.T) {
+	uc, defaultOrg, acme := newMultiTenantUseCase(t)
+
+	inDefault, err := registerIn(uc, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if inDefault.User.OrganizationID != defaultOrg.ID.String() {
+		t.Errorf("organization = %q, want the default organization", inDefault.User.OrganizationID)
+	}
+
+	// The same email and username are free in another organization...
+	inAcme, err := registerIn(uc, "acme")
+	if err != nil {
+		t.Fatalf("same email in another organization: %v", err)
+	}
+	if inAcme.User.OrganizationID != acme.ID.String() {
+		t.Errorf("organization = %q, want acme", inAcme.User.OrganizationID)
+	}
+	claims, err := uc.jwtService.ValidateToken(inAcme.AccessToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claims.OrgID != acme.ID.String() {
+		t.Errorf("org_id claim = %q, want acme", claims.OrgID)
+	}
+
+	// ...but taken within the same one
+	if _, err := registerIn(uc, "acme"); err != ErrUserAlreadyExists {
+		t.Errorf("duplicate in acme: err = %v, want ErrUserAlreadyExists", err)
+	}
+	if _, err := registerIn(uc, "nope"); err != ErrOrganizationNotFound {
+		t.Errorf("unknown organization: err = %v, want ErrOrganizationNotFound", err)
+	}
+}
+
+func TestLoginScopedToOrganization(t *testing.T) {
+	uc, _, acme := newMultiTenantUseCase(t)
+	if _, err := registerIn(uc, "acme"); err != nil {
+		t.Fatal(err)
+	}
+
+	login := func(orgSlug string) (*dto.AuthResponse, error) {
+		return uc.Login(context.Background(), &dto.LoginRequest{
+			EmailOrUsername: "jane", Password: "correct-horse-battery", OrganizationSlug: orgSlug,
+		})
+	}
+
+	resp, err := login("acme")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.User.OrganizationID != acme.ID.String() {
+		t.Errorf("organization = %q, want acme", resp.User.OrganizationID)
+	}
+
+	// Not registered in the default organization; unknown organizations look the same
+	for _, slug := range []string{"", "nope"} {
+		if _, err := login(slug); err != ErrInvalidCredentials {
+			t.Errorf("login in %q: err = %v, want ErrInvalidCredentials", slug, err)
+		}
+	}
+}
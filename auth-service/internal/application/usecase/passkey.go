@@ -0,0 +1,358 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"auth-service/internal/application/dto"
+	"auth-service/internal/domain"
+	"auth-service/pkg/security"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/google/uuid"
+)
+
+// passkeySessionIDBytes - Passkey ceremony session ID'sinin random byte uzunluğu
+const passkeySessionIDBytes = 32
+
+var (
+	// ErrPasskeySessionNotFound - Challenge bulunamadı: süresi doldu, zaten kullanıldı veya başka kullanıcıya ait
+	ErrPasskeySessionNotFound = newAppError(http.StatusBadRequest, "passkey_session_expired", "Passkey challenge is invalid or expired; start again")
+
+	// ErrPasskeyVerificationFailed - Tarayıcının döndürdüğü credential doğrulanamadı (imza, origin, bilinmeyen credential...)
+	ErrPasskeyVerificationFailed = newAppError(http.StatusUnauthorized, "passkey_verification_failed", "The passkey could not be verified")
+
+	// ErrPasskeyCloneDetected - Authenticator'ın imza sayacı geriye gitti; credential kopyalanmış olabilir
+	ErrPasskeyCloneDetected = newAppError(http.StatusUnauthorized, "passkey_clone_detected", "This passkey may have been copied; sign in another way")
+)
+
+// errPasskeysNotConfigured - WithPasskeys verilmeden passkey çağrıldı
+var errPasskeysNotConfigured = errors.New("passkey login is not configured")
+
+// PasskeySessionStore - Başlamış passkey ceremony'lerinin (challenge) kısa ömürlü saklandığı port
+// Begin ile finish farklı replikalara düşebilir, bu yüzden sunucu tarafında ortak saklanır.
+// Implementasyonlar: infrastructure/passkey (Redis, memory)
+type PasskeySessionStore interface {
+	Save(ctx context.Context, id string, data []byte, ttl time.Duration) error
+	// Take - Session'ı döner ve siler (tek kullanımlık); yoksa veya süresi dolduysa nil
+	Take(ctx context.Context, id string) ([]byte, error)
+}
+
+// WithPasskeys - WebAuthn (passkey) ile kayıt ve giriş
+// sessionTTL = challenge'ın cevaplanması için verilen süre
+// Verilmezse passkey method'ları hata döner.
+func WithPasskeys(wa *webauthn.WebAuthn, credentials domain.CredentialRepository, sessions PasskeySessionStore, sessionTTL time.Duration) AuthUseCaseOption {
+	return func(uc *AuthUseCase) {
+		uc.webauthn = wa
+		uc.credentials = credentials
+		uc.passkeySessions = sessions
+		uc.passkeySessionTTL = sessionTTL
+	}
+}
+
+// webauthnUser - domain.User'ı webauthn.User interface'ine uyarlar
+// WebAuthn user handle'ı = user ID'nin 16 byte'ı (email gibi kişisel bilgi değil)
+type webauthnUser struct {
+	user        *domain.User
+	credentials []webauthn.Credential
+}
+
+func (u *webauthnUser) WebAuthnID() []byte {
+	id := u.user.ID
+	return id[:]
+}
+
+func (u *webauthnUser) WebAuthnName() string {
+	return u.user.Email
+}
+
+func (u *webauthnUser) WebAuthnDisplayName() string {
+	if name := strings.TrimSpace(u.user.FirstName + " " + u.user.LastName); name != "" {
+		return name
+	}
+	return u.user.Username
+}
+
+func (u *webauthnUser) WebAuthnIcon() string {
+	return ""
+}
+
+func (u *webauthnUser) WebAuthnCredentials() []webauthn.Credential {
+	return u.credentials
+}
+
+// BeginPasskeyRegistration - Giriş yapmış kullanıcı için yeni passkey kaydını başlatır
+// Dönen options tarayıcıda navigator.credentials.create()'e verilir.
+// Kullanıcının mevcut passkey'leri hariç tutulur (aynı authenticator iki kez kaydedilemez).
+// Passkey "discoverable" olmak zorunda: login'de kullanıcı adı sorulmaz.
+func (uc *AuthUseCase) BeginPasskeyRegistration(ctx context.Context, userID uuid.UUID) (_ *dto.PasskeyOptionsResponse, err error) {
+	defer translateContextError(ctx, &err)
+
+	if uc.webauthn == nil {
+		return nil, errPasskeysNotConfigured
+	}
+
+	// ADIM 1: Kullanıcıyı ve mevcut passkey'lerini yükle
+	user, err := uc.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, notFoundAs(err, ErrUserNotFound)
+	}
+	waUser, err := uc.loadWebAuthnUser(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	exclusions := make([]protocol.CredentialDescriptor, 0, len(waUser.credentials))
+	for _, credential := range waUser.credentials {
+		exclusions = append(exclusions, credential.Descriptor())
+	}
+
+	// ADIM 2: Challenge üret
+	creation, session, err := uc.webauthn.BeginRegistration(waUser,
+		webauthn.WithResidentKeyRequirement(protocol.ResidentKeyRequirementRequired),
+		webauthn.WithExclusions(exclusions),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// ADIM 3: Challenge'ı sunucu tarafında sakla
+	sessionID, err := uc.savePasskeySession(ctx, session)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.PasskeyOptionsResponse{SessionID: sessionID, Options: creation}, nil
+}
+
+// FinishPasskeyRegistration - Tarayıcının oluşturduğu credential'ı doğrular ve saklar
+// credentialJSON = navigator.credentials.create() sonucunun JSON'u
+func (uc *AuthUseCase) FinishPasskeyRegistration(ctx context.Context, userID uuid.UUID, sessionID string, credentialJSON []byte) (err error) {
+	defer translateContextError(ctx, &err)
+
+	if uc.webauthn == nil {
+		return errPasskeysNotConfigured
+	}
+
+	// ADIM 1: Challenge'ı tüket - başka kullanıcı için başlatılmışsa kabul etme
+	session, err := uc.takePasskeySession(ctx, sessionID)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(session.UserID, userID[:]) {
+		return ErrPasskeySessionNotFound
+	}
+
+	// ADIM 2: Kullanıcıyı yükle
+	user, err := uc.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return notFoundAs(err, ErrUserNotFound)
+	}
+	waUser, err := uc.loadWebAuthnUser(ctx, user)
+	if err != nil {
+		return err
+	}
+
+	// ADIM 3: Attestation'ı doğrula (challenge, origin, RP ID)
+	parsed, err := protocol.ParseCredentialCreationResponseBody(bytes.NewReader(credentialJSON))
+	if err != nil {
+		return ErrPasskeyVerificationFailed
+	}
+	credential, err := uc.webauthn.CreateCredential(waUser, *session, parsed)
+	if err != nil {
+		return ErrPasskeyVerificationFailed
+	}
+
+	// ADIM 4: Public key'i sakla
+	transports := make([]string, 0, len(credential.Transport))
+	for _, transport := range credential.Transport {
+		transports = append(transports, string(transport))
+	}
+	stored := &domain.Credential{
+		UserID:          user.ID,
+		CredentialID:    credential.ID,
+		PublicKey:       credential.PublicKey,
+		AttestationType: credential.AttestationType,
+		Transports:      strings.Join(transports, ","),
+		AAGUID:          credential.Authenticator.AAGUID,
+		SignCount:       credential.Authenticator.SignCount,
+	}
+	if err := uc.credentials.Create(ctx, stored); err != nil {
+		return err
+	}
+	uc.logAudit(ctx, AuditPasskeyRegistered, user.ID, map[string]string{"credential_id": stored.ID.String()})
+	return nil
+}
+
+// BeginPasskeyLogin - Passkey ile girişi başlatır
+// Kullanıcı adı istenmez: tarayıcı bu site için kayıtlı passkey'leri kendisi listeler.
+// Dönen options tarayıcıda navigator.credentials.get()'e verilir.
+func (uc *AuthUseCase) BeginPasskeyLogin(ctx context.Context) (_ *dto.PasskeyOptionsResponse, err error) {
+	defer translateContextError(ctx, &err)
+
+	if uc.webauthn == nil {
+		return nil, errPasskeysNotConfigured
+	}
+
+	assertion, session, err := uc.webauthn.BeginDiscoverableLogin(
+		webauthn.WithUserVerification(protocol.VerificationPreferred),
+	)
+	if err != nil {
+		return nil, err
+	}
+	sessionID, err := uc.savePasskeySession(ctx, session)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.PasskeyOptionsResponse{SessionID: sessionID, Options: assertion}, nil
+}
+
+// FinishPasskeyLogin - Passkey imzasını doğrular ve normal login gibi token'ları döner
+// credentialJSON = navigator.credentials.get() sonucunun JSON'u
+// İmza sayacı geriye giderse (kopyalanmış authenticator işareti) giriş reddedilir ve audit'e yazılır.
+func (uc *AuthUseCase) FinishPasskeyLogin(ctx context.Context, sessionID string, credentialJSON []byte) (_ *dto.AuthResponse, err error) {
+	defer translateContextError(ctx, &err)
+
+	if uc.webauthn == nil {
+		return nil, errPasskeysNotConfigured
+	}
+
+	// ADIM 1: Challenge'ı tüket
+	session, err := uc.takePasskeySession(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	// ADIM 2: İmzayı doğrula - kullanıcı, authenticator'ın döndürdüğü user handle'dan bulunur
+	parsed, err := protocol.ParseCredentialRequestResponseBody(bytes.NewReader(credentialJSON))
+	if err != nil {
+		return nil, ErrPasskeyVerificationFailed
+	}
+	var waUser *webauthnUser
+	credential, err := uc.webauthn.ValidateDiscoverableLogin(func(rawID, userHandle []byte) (webauthn.User, error) {
+		userID, err := uuid.FromBytes(userHandle)
+		if err != nil {
+			return nil, err
+		}
+		user, err := uc.userRepo.GetByID(ctx, userID)
+		if err != nil {
+			return nil, notFoundAs(err, ErrUserNotFound)
+		}
+		if waUser, err = uc.loadWebAuthnUser(ctx, user); err != nil {
+			return nil, err
+		}
+		return waUser, nil
+	}, *session, parsed)
+	if err != nil {
+		return nil, ErrPasskeyVerificationFailed
+	}
+	user := waUser.user
+
+	stored, err := uc.credentials.GetByCredentialID(ctx, credential.ID)
+	if err != nil {
+		return nil, notFoundAs(err, ErrPasskeyVerificationFailed)
+	}
+	if stored.UserID != user.ID {
+		return nil, ErrPasskeyVerificationFailed
+	}
+
+	// ADIM 3: Sayaç geriye gittiyse aynı private key'in başka bir kopyası kullanılıyor olabilir
+	// Saklanan sayaç güncellenmez: gerçek authenticator'ın sonraki girişi yine çalışır
+	if credential.Authenticator.CloneWarning {
+		uc.logAudit(ctx, AuditPasskeyCloneDetected, user.ID, map[string]string{
+			"credential_id":   stored.ID.String(),
+			"stored_count":    strconv.FormatUint(uint64(stored.SignCount), 10),
+			"presented_count": strconv.FormatUint(uint64(parsed.Response.AuthenticatorData.Counter), 10),
+		})
+		return nil, ErrPasskeyCloneDetected
+	}
+	if err := uc.credentials.UpdateSignCount(ctx, stored.ID, credential.Authenticator.SignCount); err != nil {
+		return nil, err
+	}
+
+	// ADIM 4: Şifreli login ile aynı hesap kontrolleri (şifre hariç)
+	if !user.IsActive {
+		return nil, ErrUserInactive
+	}
+	if user.IsLocked() {
+		return nil, ErrAccountLocked
+	}
+	if user.IsPendingApproval() {
+		return nil, ErrPendingApproval
+	}
+
+	// ADIM 5: Yeni cihaz uyarısı, son giriş zamanı (kritik değil) ve token'lar
+	uc.notifyNewDevice(ctx, user)
+	uc.recordLastLogin(ctx, user.ID)
+	response, err := uc.generateAuthResponse(ctx, user, nil, false)
+	if err != nil {
+		return nil, err
+	}
+	uc.logAudit(ctx, AuditLoginSuccess, user.ID, map[string]string{"method": "passkey"})
+	return response, nil
+}
+
+// loadWebAuthnUser - Kullanıcıyı kayıtlı passkey'leriyle birlikte webauthn.User'a çevirir
+func (uc *AuthUseCase) loadWebAuthnUser(ctx context.Context, user *domain.User) (*webauthnUser, error) {
+	stored, err := uc.credentials.GetByUserID(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	credentials := make([]webauthn.Credential, 0, len(stored))
+	for _, c := range stored {
+		var transports []protocol.AuthenticatorTransport
+		for _, t := range strings.Split(c.Transports, ",") {
+			if t != "" {
+				transports = append(transports, protocol.AuthenticatorTransport(t))
+			}
+		}
+		credentials = append(credentials, webauthn.Credential{
+			ID:              c.CredentialID,
+			PublicKey:       c.PublicKey,
+			AttestationType: c.AttestationType,
+			Transport:       transports,
+			Authenticator: webauthn.Authenticator{
+				AAGUID:    c.AAGUID,
+				SignCount: c.SignCount,
+			},
+		})
+	}
+	return &webauthnUser{user: user, credentials: credentials}, nil
+}
+
+// savePasskeySession - Ceremony'nin session verisini random bir ID ile saklar
+func (uc *AuthUseCase) savePasskeySession(ctx context.Context, session *webauthn.SessionData) (string, error) {
+	data, err := json.Marshal(session)
+	if err != nil {
+		return "", err
+	}
+	sessionID, err := security.GenerateOpaqueToken(passkeySessionIDBytes)
+	if err != nil {
+		return "", err
+	}
+	if err := uc.passkeySessions.Save(ctx, sessionID, data, uc.passkeySessionTTL); err != nil {
+		return "", err
+	}
+	return sessionID, nil
+}
+
+// takePasskeySession - Session'ı tek kullanımlık olarak alır
+func (uc *AuthUseCase) takePasskeySession(ctx context.Context, sessionID string) (*webauthn.SessionData, error) {
+	data, err := uc.passkeySessions.Take(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+	if data == nil {
+		return nil, ErrPasskeySessionNotFound
+	}
+	var session webauthn.SessionData
+	if err := json.Unmarshal(data, &session); err != nil {
+		return nil, ErrPasskeySessionNotFound
+	}
+	return &session, nil
+}
@@ -0,0 +1,292 @@
+package usecase
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"auth-service/internal/domain"
+	"auth-service/internal/infrastructure/passkey"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/protocol/webauthncbor"
+	"github.com/go-webauthn/webauthn/webauthn"
+)
+
+const (
+	testRPID   = "app.example.com"
+	testOrigin = "https://app.example.com"
+)
+
+func newPasskeyUseCase(t *testing.T, opts ...AuthUseCaseOption) (*AuthUseCase, *testDeps, *fakeCredentialRepo) {
+	t.Helper()
+	wa, err := webauthn.New(&webauthn.Config{RPID: testRPID, RPDisplayName: "Test", RPOrigins: []string{testOrigin}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	credentials := &fakeCredentialRepo{}
+	uc, deps := newTestUseCaseWithConfig(t, testSecurityConfig(),
+		append(opts, WithPasskeys(wa, credentials, passkey.NewMemorySessionStore(), time.Minute))...)
+	return uc, deps, credentials
+}
+
+// testAuthenticator is a software authenticator with a single ES256 key
+type testAuthenticator struct {
+	t            *testing.T
+	key          *ecdsa.PrivateKey
+	credentialID []byte
+	counter      uint32
+}
+
+func newTestAuthenticator(t *testing.T) *testAuthenticator {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	id := make([]byte, 16)
+	_, _ = rand.Read(id)
+	return &testAuthenticator{t: t, key: key, credentialID: id}
+}
+
+func (a *testAuthenticator) clientData(ceremony string, challenge []byte) []byte {
+	data, _ := json.Marshal(map[string]string{
+		"type":      ceremony,
+		"challenge": base64.RawURLEncoding.EncodeToString(challenge),
+		"origin":    testOrigin,
+	})
+	return data
+}
+
+// authData returns the authenticator data; flags 0x05 = user present + verified
+func (a *testAuthenticator) authData(flags byte, attested []byte) []byte {
+	rpIDHash := sha256.Sum256([]byte(testRPID))
+	data := append(rpIDHash[:], flags)
+	data = binary.BigEndian.AppendUint32(data, a.counter)
+	return append(data, attested...)
+}
+
+// create answers navigator.credentials.create() with "none" attestation
+func (a *testAuthenticator) create(options interface{}) []byte {
+	a.t.Helper()
+	creation := options.(*protocol.CredentialCreation)
+
+	publicKey, err := webauthncbor.Marshal(map[int]interface{}{
+		1: 2, 3: -7, -1: 1, // EC2, ES256, P-256
+		-2: a.key.X.FillBytes(make([]byte, 32)),
+		-3: a.key.Y.FillBytes(make([]byte, 32)),
+	})
+	if err != nil {
+		a.t.Fatal(err)
+	}
+	attested := make([]byte, 16) // AAGUID
+	attested = binary.BigEndian.AppendUint16(attested, uint16(len(a.credentialID)))
+	attested = append(attested, a.credentialID...)
+	attested = append(attested, publicKey...)
+
+	attestation, err := webauthncbor.Marshal(map[string]interface{}{
+		"fmt":      "none",
+		"attStmt":  map[string]interface{}{},
+		"authData": a.authData(0x45, attested), // + attested credential data
+	})
+	if err != nil {
+		a.t.Fatal(err)
+	}
+	return a.credentialJSON(map[string]string{
+		"clientDataJSON":    encode(a.clientData("webauthn.create", creation.Response.Challenge)),
+		"attestationObject": encode(attestation),
+	})
+}
+
+// get answers navigator.credentials.get() for the user with userHandle
+func (a *testAuthenticator) get(options interface{}, userHandle []byte) []byte {
+	a.t.Helper()
+	assertion := options.(*protocol.CredentialAssertion)
+
+	authData := a.authData(0x05, nil)
+	clientData := a.clientData("webauthn.get", assertion.Response.Challenge)
+	clientDataHash := sha256.Sum256(clientData)
+	digest := sha256.Sum256(append(append([]byte{}, authData...), clientDataHash[:]...))
+	signature, err := ecdsa.SignASN1(rand.Reader, a.key, digest[:])
+	if err != nil {
+		a.t.Fatal(err)
+	}
+	return a.credentialJSON(map[string]string{
+		"clientDataJSON":    encode(clientData),
+		"authenticatorData": encode(authData),
+		"signature":         encode(signature),
+		"userHandle":        encode(userHandle),
+	})
+}
+
+func (a *testAuthenticator) credentialJSON(response map[string]string) []byte {
+	data, _ := json.Marshal(map[string]interface{}{
+		"id":       encode(a.credentialID),
+		"rawId":    encode(a.credentialID),
+		"type":     "public-key",
+		"response": response,
+	})
+	return data
+}
+
+func encode(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// registerPasskey runs the registration ceremony for user with authenticator
+func registerPasskey(t *testing.T, uc *AuthUseCase, user *domain.User, authenticator *testAuthenticator) {
+	t.Helper()
+	ctx := context.Background()
+	begin, err := uc.BeginPasskeyRegistration(ctx, user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := uc.FinishPasskeyRegistration(ctx, user.ID, begin.SessionID, authenticator.create(begin.Options)); err != nil {
+		t.Fatalf("FinishPasskeyRegistration: %v", err)
+	}
+}
+
+// loginWithPasskey runs the login ceremony with authenticator
+func loginWithPasskey(uc *AuthUseCase, user *domain.User, authenticator *testAuthenticator) error {
+	ctx := context.Background()
+	begin, err := uc.BeginPasskeyLogin(ctx)
+	if err != nil {
+		return err
+	}
+	_, err = uc.FinishPasskeyLogin(ctx, begin.SessionID, authenticator.get(begin.Options, user.ID[:]))
+	return err
+}
+
+func TestPasskeyRegistrationAndLogin(t *testing.T) {
+	audit := &fakeAuditLogger{}
+	uc, deps, credentials := newPasskeyUseCase(t, WithAuditLogger(audit))
+	user := seedUser(t, uc, deps, &domain.User{Email: "jane@example.com", Username: "jane"}, "correct-horse")
+	authenticator := newTestAuthenticator(t)
+
+	registerPasskey(t, uc, user, authenticator)
+	if len(credentials.credentials) != 1 || credentials.credentials[0].UserID != user.ID {
+		t.Fatalf("stored credentials = %+v, want one for the user", credentials.credentials)
+	}
+
+	ctx := context.Background()
+	begin, err := uc.BeginPasskeyLogin(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	authenticator.counter = 1
+	response, err := uc.FinishPasskeyLogin(ctx, begin.SessionID, authenticator.get(begin.Options, user.ID[:]))
+	if err != nil {
+		t.Fatalf("FinishPasskeyLogin: %v", err)
+	}
+	if response.AccessToken == "" || response.RefreshToken == "" {
+		t.Error("expected tokens")
+	}
+	if got := credentials.credentials[0].SignCount; got != 1 {
+		t.Errorf("stored sign count = %d, want 1", got)
+	}
+
+	var methods []string
+	for _, e := range audit.events {
+		if e.Action == AuditLoginSuccess {
+			methods = append(methods, e.Details["method"])
+		}
+	}
+	if len(methods) != 1 || methods[0] != "passkey" {
+		t.Errorf("login audit methods = %q, want [passkey]", methods)
+	}
+}
+
+func TestPasskeyLoginRejectsSignCountRegression(t *testing.T) {
+	audit := &fakeAuditLogger{}
+	uc, deps, credentials := newPasskeyUseCase(t, WithAuditLogger(audit))
+	user := seedUser(t, uc, deps, &domain.User{Email: "jane@example.com", Username: "jane"}, "correct-horse")
+	authenticator := newTestAuthenticator(t)
+	registerPasskey(t, uc, user, authenticator)
+
+	authenticator.counter = 5
+	if err := loginWithPasskey(uc, user, authenticator); err != nil {
+		t.Fatal(err)
+	}
+
+	// A copy of the key that has signed fewer times than the original
+	authenticator.counter = 3
+	if err := loginWithPasskey(uc, user, authenticator); !errors.Is(err, ErrPasskeyCloneDetected) {
+		t.Fatalf("got %v, want ErrPasskeyCloneDetected", err)
+	}
+	if got := credentials.credentials[0].SignCount; got != 5 {
+		t.Errorf("stored sign count = %d, want 5 kept", got)
+	}
+	var flagged bool
+	for _, e := range audit.events {
+		flagged = flagged || (e.Action == AuditPasskeyCloneDetected && e.TargetID == user.ID)
+	}
+	if !flagged {
+		t.Error("expected a clone detection audit event")
+	}
+
+	// The original authenticator still works
+	authenticator.counter = 6
+	if err := loginWithPasskey(uc, user, authenticator); err != nil {
+		t.Errorf("login after rejected clone: %v", err)
+	}
+}
+
+func TestPasskeySessionIsSingleUseAndBoundToUser(t *testing.T) {
+	uc, deps, _ := newPasskeyUseCase(t)
+	jane := seedUser(t, uc, deps, &domain.User{Email: "jane@example.com", Username: "jane"}, "correct-horse")
+	john := seedUser(t, uc, deps, &domain.User{Email: "john@example.com", Username: "john"}, "correct-horse")
+	authenticator := newTestAuthenticator(t)
+	ctx := context.Background()
+
+	begin, err := uc.BeginPasskeyRegistration(ctx, jane.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	credential := authenticator.create(begin.Options)
+	if err := uc.FinishPasskeyRegistration(ctx, john.ID, begin.SessionID, credential); !errors.Is(err, ErrPasskeySessionNotFound) {
+		t.Errorf("another user's session: got %v, want ErrPasskeySessionNotFound", err)
+	}
+
+	begin, err = uc.BeginPasskeyRegistration(ctx, jane.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	credential = authenticator.create(begin.Options)
+	if err := uc.FinishPasskeyRegistration(ctx, jane.ID, begin.SessionID, credential); err != nil {
+		t.Fatal(err)
+	}
+	if err := uc.FinishPasskeyRegistration(ctx, jane.ID, begin.SessionID, credential); !errors.Is(err, ErrPasskeySessionNotFound) {
+		t.Errorf("replayed session: got %v, want ErrPasskeySessionNotFound", err)
+	}
+}
+
+func TestPasskeyLoginChecksAccountState(t *testing.T) {
+	uc, deps, _ := newPasskeyUseCase(t)
+	user := seedUser(t, uc, deps, &domain.User{Email: "jane@example.com", Username: "jane"}, "correct-horse")
+	authenticator := newTestAuthenticator(t)
+	registerPasskey(t, uc, user, authenticator)
+
+	user.IsActive = false
+	if err := deps.users.Update(context.Background(), user); err != nil {
+		t.Fatal(err)
+	}
+	authenticator.counter = 1
+	if err := loginWithPasskey(uc, user, authenticator); !errors.Is(err, ErrUserInactive) {
+		t.Errorf("got %v, want ErrUserInactive", err)
+	}
+}
+
+func TestPasskeysNotConfigured(t *testing.T) {
+	uc, _ := newTestUseCase(t)
+	if _, err := uc.BeginPasskeyLogin(context.Background()); !errors.Is(err, errPasskeysNotConfigured) {
+		t.Errorf("got %v, want errPasskeysNotConfigured", err)
+	}
+}
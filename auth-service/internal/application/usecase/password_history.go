@@ -0,0 +1,74 @@
+package usecase
+
+import (
+	"context"
+
+	"auth-service/internal/domain"
+)
+
+// WithPasswordHistory - Kullanıcıların son şifrelerinin (hash) saklandığı repository
+// Son SecurityConfig.PasswordHistoryDepth şifre, şifre değiştirme ve sıfırlamada tekrar kullanılamaz.
+// Verilmezse (veya depth 0 ise) geçmiş tutulmaz ve kontrol yapılmaz.
+func WithPasswordHistory(history domain.PasswordHistoryRepository) AuthUseCaseOption {
+	return func(uc *AuthUseCase) {
+		uc.passwordHistory = history
+	}
+}
+
+// passwordHistoryEnabled - Şifre geçmişi tutuluyor mu
+func (uc *AuthUseCase) passwordHistoryEnabled() bool {
+	return uc.passwordHistory != nil && uc.securityCfg.PasswordHistoryDepth > 0
+}
+
+// checkPasswordHistory - Yeni şifre mevcut şifre veya son PasswordHistoryDepth şifreden biriyse ErrPasswordReused
+// Hash'ler salt'lı olduğu için şifre her hash ile tek tek karşılaştırılır (PasswordHistoryDepth kadar hash maliyeti).
+func (uc *AuthUseCase) checkPasswordHistory(ctx context.Context, user *domain.User, password string) error {
+	if !uc.passwordHistoryEnabled() {
+		return nil
+	}
+	if uc.passwordHasher.Compare(user.PasswordHash, password) {
+		return ErrPasswordReused
+	}
+
+	entries, err := uc.passwordHistory.ListRecent(ctx, user.ID, uc.securityCfg.PasswordHistoryDepth)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if uc.passwordHasher.Compare(entry.PasswordHash, password) {
+			return ErrPasswordReused
+		}
+	}
+	return nil
+}
+
+// recordPasswordHistory - Kullanıcının yeni şifre hash'ini geçmişe ekler, en yeni PasswordHistoryDepth kaydı bırakır
+func (uc *AuthUseCase) recordPasswordHistory(ctx context.Context, user *domain.User) error {
+	if !uc.passwordHistoryEnabled() {
+		return nil
+	}
+	if err := uc.passwordHistory.Create(ctx, &domain.PasswordHistory{UserID: user.ID, PasswordHash: user.PasswordHash}); err != nil {
+		return err
+	}
+	return uc.passwordHistory.Prune(ctx, user.ID, uc.securityCfg.PasswordHistoryDepth)
+}
+
+// updatePassword - Yeni şifre hash'ini kaydeder ve geçmişe ekler (tek transaction'da)
+// Kullanıcının kendi seçtiği şifre olduğu için geçici şifre (MustChangePassword) ve
+// şifresiz social login hesabı (PasswordUnset) işaretleri kalkar.
+func (uc *AuthUseCase) updatePassword(ctx context.Context, user *domain.User, passwordHash string) error {
+	previous, mustChange, unset := user.PasswordHash, user.MustChangePassword, user.PasswordUnset
+	user.PasswordHash = passwordHash
+	user.MustChangePassword = false
+	user.PasswordUnset = false
+	err := uc.transactions.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := uc.userRepo.Update(ctx, user); err != nil {
+			return err
+		}
+		return uc.recordPasswordHistory(ctx, user)
+	})
+	if err != nil {
+		user.PasswordHash, user.MustChangePassword, user.PasswordUnset = previous, mustChange, unset
+	}
+	return err
+}
@@ -0,0 +1,106 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"auth-service/internal/application/dto"
+	"auth-service/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+func newPasswordHistoryUseCase(t *testing.T, depth int, onRegister bool) (*AuthUseCase, *testDeps, *fakePasswordHistoryRepo) {
+	t.Helper()
+	cfg := testSecurityConfig()
+	cfg.PasswordHistoryDepth = depth
+	cfg.PasswordHistoryOnRegister = onRegister
+	history := &fakePasswordHistoryRepo{}
+	uc, deps := newTestUseCaseWithConfig(t, cfg, WithPasswordHistory(history))
+	return uc, deps, history
+}
+
+func TestChangePasswordRejectsRecentPasswords(t *testing.T) {
+	ctx := context.Background()
+	uc, deps, history := newPasswordHistoryUseCase(t, 2, false)
+	user := seedUser(t, uc, deps, &domain.User{Email: "jane@example.com", Username: "jane", IsVerified: true}, "password-one")
+
+	for _, change := range [][2]string{{"password-one", "password-two"}, {"password-two", "password-three"}} {
+		if err := uc.ChangePassword(ctx, user.ID, change[0], change[1]); err != nil {
+			t.Fatalf("change to %s: %v", change[1], err)
+		}
+	}
+	// password-two is one of the last 2; password-one was never recorded
+	if err := uc.ChangePassword(ctx, user.ID, "password-three", "password-two"); err != ErrPasswordReused {
+		t.Errorf("reusing password-two: err = %v, want ErrPasswordReused", err)
+	}
+
+	if err := uc.ChangePassword(ctx, user.ID, "password-three", "password-four"); err != nil {
+		t.Fatal(err)
+	}
+	if n := history.count(user.ID); n != 2 {
+		t.Errorf("%d history entries, want 2 after pruning", n)
+	}
+	// password-two has dropped out of the last 2
+	if err := uc.ChangePassword(ctx, user.ID, "password-four", "password-two"); err != nil {
+		t.Errorf("password-two beyond the history depth: %v", err)
+	}
+}
+
+func TestRegisterRecordsPasswordHistoryOnlyWhenConfigured(t *testing.T) {
+	for _, onRegister := range []bool{false, true} {
+		uc, _, history := newPasswordHistoryUseCase(t, 5, onRegister)
+		resp, err := uc.Register(context.Background(), &dto.RegisterRequest{
+			Email: "jane@example.com", Username: "jane", Password: "password-one", FirstName: "Jane", LastName: "Doe",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		want := 0
+		if onRegister {
+			want = 1
+		}
+		if n := history.count(uuid.MustParse(resp.User.ID)); n != want {
+			t.Errorf("onRegister=%v: %d history entries, want %d", onRegister, n, want)
+		}
+	}
+}
+
+func TestResetPasswordRejectsRecentPasswordAndKeepsToken(t *testing.T) {
+	ctx := context.Background()
+	uc, deps, _ := newPasswordHistoryUseCase(t, 5, false)
+	user := seedUser(t, uc, deps, &domain.User{Email: "jane@example.com", Username: "jane", IsVerified: true}, "password-one")
+	if err := uc.ChangePassword(ctx, user.ID, "password-one", "password-two"); err != nil {
+		t.Fatal(err)
+	}
+
+	token := requestResetToken(t, uc, deps, "jane@example.com")
+	// The current password counts as used too
+	if err := uc.ResetPassword(ctx, token, "password-two"); err != ErrPasswordReused {
+		t.Fatalf("err = %v, want ErrPasswordReused", err)
+	}
+	if err := uc.ResetPassword(ctx, token, "password-three"); err != nil {
+		t.Fatalf("token should still be usable: %v", err)
+	}
+}
+
+func TestResetPasswordFailsWhenHistoryCannotBeChecked(t *testing.T) {
+	ctx := context.Background()
+	uc, deps, _ := newPasswordHistoryUseCase(t, 5, false)
+	seedUser(t, uc, deps, &domain.User{Email: "jane@example.com", Username: "jane", IsVerified: true}, "password-one")
+	token := requestResetToken(t, uc, deps, "jane@example.com")
+
+	// A database error must not skip the history check and let a reused password through
+	dbDown := errors.New("connection refused")
+	deps.users.lookupErr = dbDown
+	if err := uc.ResetPassword(ctx, token, "password-one"); !errors.Is(err, dbDown) {
+		t.Fatalf("err = %v, want the lookup error", err)
+	}
+
+	deps.users.lookupErr = nil
+	if err := uc.ResetPassword(ctx, token, "password-one"); err != ErrPasswordReused {
+		t.Errorf("token should still be usable and the password rejected: err = %v", err)
+	}
+}
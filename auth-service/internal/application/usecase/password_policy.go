@@ -0,0 +1,173 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"auth-service/config"
+	"auth-service/internal/application/dto"
+	"auth-service/pkg/security"
+)
+
+// PasswordStrengthError - Şifrenin tahmini gücü PasswordMinScore'un altında
+// errors.Is(err, ErrPasswordTooWeak) true döner; uyarı ve öneriler AppError() ile response'a eklenir.
+type PasswordStrengthError struct {
+	Strength security.PasswordStrength
+	MinScore int
+}
+
+func (e *PasswordStrengthError) Error() string { return ErrPasswordTooWeak.Error() }
+
+func (e *PasswordStrengthError) Unwrap() error { return ErrPasswordTooWeak }
+
+// AppError - Skor, uyarı ve öneriler response detaylarına yazılır
+func (e *PasswordStrengthError) AppError() *AppError {
+	details := map[string]string{
+		"rule":      "strength",
+		"score":     strconv.Itoa(e.Strength.Score),
+		"min_score": strconv.Itoa(e.MinScore),
+	}
+	if e.Strength.Warning != "" {
+		details["warning"] = e.Strength.Warning
+	}
+	if len(e.Strength.Suggestions) > 0 {
+		details["suggestions"] = strings.Join(e.Strength.Suggestions, "; ")
+	}
+	return ErrPasswordTooWeak.WithDetails(ErrPasswordTooWeak.Message, details)
+}
+
+// Policy kuralları - PasswordRuleError.Rule değerleri, response detaylarında "rule"
+const (
+	PasswordRuleMinLength = "min_length" // ErrPasswordTooShort
+	PasswordRuleMaxLength = "max_length"
+	PasswordRuleUpper     = "uppercase"
+	PasswordRuleLower     = "lowercase"
+	PasswordRuleDigit     = "digit"
+	PasswordRuleSymbol    = "symbol"
+	PasswordRuleBreached  = "breached"
+)
+
+// PasswordRuleError - Şifre belirli bir policy kuralına uymuyor
+// errors.Is(err, ErrWeakPassword) true döner; Rule hangi kuralın ihlal edildiğini söyler.
+type PasswordRuleError struct {
+	Rule    string // örn: PasswordRuleDigit
+	Message string // Kullanıcıya gösterilebilecek açıklama
+}
+
+func (e *PasswordRuleError) Error() string { return ErrWeakPassword.Error() + ": " + e.Rule }
+
+func (e *PasswordRuleError) Unwrap() error { return ErrWeakPassword }
+
+// AppError - İhlal edilen kural response detaylarına yazılır
+func (e *PasswordRuleError) AppError() *AppError {
+	return ErrWeakPassword.WithDetails(e.Message, map[string]string{"rule": e.Rule})
+}
+
+// checkPasswordPolicy - Yeni şifreler için TEK kontrol noktası
+// Register ve şifre değiştirme/sıfırlama akışları bu fonksiyonu kullanır.
+// Önce yerel kurallar (checkPasswordRules), en son (ağ isteği olduğu için) sızıntı kontrolü.
+// userInputs: şifrede geçmemesi gereken kullanıcı bilgileri (email, username)
+func (uc *AuthUseCase) checkPasswordPolicy(ctx context.Context, password string, userInputs ...string) error {
+	if err := uc.checkPasswordRules(password, userInputs...); err != nil {
+		return err
+	}
+	if uc.breachChecker == nil {
+		return nil
+	}
+
+	// HaveIBeenPwned'e ulaşılamazsa şifre kabul edilir (fail open):
+	// dış servisin kesintisi kayıt ve şifre sıfırlamayı durdurmamalı
+	breached, err := uc.breachChecker.CheckBreached(ctx, password)
+	if err == nil && breached {
+		return &PasswordRuleError{
+			Rule:    PasswordRuleBreached,
+			Message: "This password has appeared in a data breach; choose a different one",
+		}
+	}
+	return nil
+}
+
+// checkPasswordRules - Ağ gerektirmeyen policy kuralları
+// Kurallar SecurityConfig'ten gelir (örn: PASSWORD_MIN_LENGTH, PASSWORD_POLICY).
+//
+// Policy'ler:
+// - length: Sadece minimum uzunluk
+// - strength: Sadece tahmini güç skoru (zxcvbn benzeri)
+// - both: İkisi birden
+// Maksimum uzunluk ve zorunlu karakter sınıfları her policy'de uygulanır.
+func (uc *AuthUseCase) checkPasswordRules(password string, userInputs ...string) error {
+	policy := uc.securityCfg.PasswordPolicy
+
+	// len() byte sayar; çok byte'lı karakterler için rune sayısı kullanılır
+	length := len([]rune(password))
+	if policy != config.PasswordPolicyStrength && length < uc.securityCfg.PasswordMinLength {
+		return ErrPasswordTooShort
+	}
+	if length > uc.securityCfg.PasswordMaxLength {
+		return &PasswordRuleError{
+			Rule:    PasswordRuleMaxLength,
+			Message: fmt.Sprintf("Password must be at most %d characters", uc.securityCfg.PasswordMaxLength),
+		}
+	}
+	if err := uc.checkCharacterClasses(password); err != nil {
+		return err
+	}
+
+	if policy == config.PasswordPolicyStrength || policy == config.PasswordPolicyBoth {
+		strength := security.EstimatePasswordStrength(password, userInputs...)
+		if strength.Score < uc.securityCfg.PasswordMinScore {
+			return &PasswordStrengthError{Strength: strength, MinScore: uc.securityCfg.PasswordMinScore}
+		}
+	}
+	return nil
+}
+
+// checkCharacterClasses - Config'te zorunlu kılınan karakter sınıflarını kontrol eder
+// Sembol: harf ve rakam dışındaki her karakter (boşluk dahil)
+func (uc *AuthUseCase) checkCharacterClasses(password string) error {
+	var upper, lower, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsDigit(r):
+			digit = true
+		case !unicode.IsLetter(r):
+			symbol = true
+		}
+	}
+
+	cfg := uc.securityCfg
+	for _, class := range []struct {
+		required, present bool
+		rule, name        string
+	}{
+		{cfg.PasswordRequireUpper, upper, PasswordRuleUpper, "an uppercase letter"},
+		{cfg.PasswordRequireLower, lower, PasswordRuleLower, "a lowercase letter"},
+		{cfg.PasswordRequireDigit, digit, PasswordRuleDigit, "a digit"},
+		{cfg.PasswordRequireSymbol, symbol, PasswordRuleSymbol, "a symbol"},
+	} {
+		if class.required && !class.present {
+			return &PasswordRuleError{Rule: class.rule, Message: "Password must contain " + class.name}
+		}
+	}
+	return nil
+}
+
+// EstimatePasswordStrength - Şifrenin tahmini gücünü ve policy'ye uyup uymadığını döner
+// Kayıt/şifre formlarında kullanıcı yazarken gücü göstermek için; şifre saklanmaz.
+func (uc *AuthUseCase) EstimatePasswordStrength(password string, userInputs ...string) *dto.PasswordStrengthResponse {
+	strength := security.EstimatePasswordStrength(password, userInputs...)
+	return &dto.PasswordStrengthResponse{
+		Score:       strength.Score,
+		MinScore:    uc.securityCfg.PasswordMinScore,
+		Acceptable:  uc.checkPasswordRules(password, userInputs...) == nil, // Sızıntı kontrolü yok: anonim endpoint
+		Warning:     strength.Warning,
+		Suggestions: strength.Suggestions,
+	}
+}
@@ -0,0 +1,190 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"auth-service/config"
+	"auth-service/internal/application/dto"
+	"auth-service/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+func TestRegisterEnforcesPasswordMinLength(t *testing.T) {
+	cfg := testSecurityConfig()
+	cfg.PasswordMinLength = 12
+	uc, deps := newTestUseCaseWithConfig(t, cfg)
+
+	req := &dto.RegisterRequest{
+		Email:     "jane@example.com",
+		Username:  "jane",
+		Password:  "elevenchars",
+		FirstName: "Jane",
+		LastName:  "Doe",
+	}
+	if _, err := uc.Register(context.Background(), req); err != ErrPasswordTooShort {
+		t.Fatalf("got %v, want ErrPasswordTooShort", err)
+	}
+	if exists, _ := deps.users.ExistsByEmail(context.Background(), uuid.Nil, req.Email); exists {
+		t.Error("user must not be created")
+	}
+
+	req.Password = "twelve chars"
+	if _, err := uc.Register(context.Background(), req); err != nil {
+		t.Fatalf("got %v, want success", err)
+	}
+}
+
+func TestPasswordStrengthPolicy(t *testing.T) {
+	tests := []struct {
+		policy   config.PasswordPolicy
+		password string
+		want     error
+	}{
+		{config.PasswordPolicyLength, "P@ssw0rd1!", nil},
+		{config.PasswordPolicyStrength, "P@ssw0rd1!", ErrPasswordTooWeak},
+		{config.PasswordPolicyStrength, "correct horse battery", nil},
+		{config.PasswordPolicyBoth, "short", ErrPasswordTooShort},
+		{config.PasswordPolicyBoth, "jane12345678", ErrPasswordTooWeak},
+		{config.PasswordPolicyBoth, "correct horse battery", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.policy)+"/"+tt.password, func(t *testing.T) {
+			cfg := testSecurityConfig()
+			cfg.PasswordPolicy = tt.policy
+			uc, _ := newTestUseCaseWithConfig(t, cfg)
+
+			_, err := uc.Register(context.Background(), &dto.RegisterRequest{
+				Email:     "jane@example.com",
+				Username:  "jane",
+				Password:  tt.password,
+				FirstName: "Jane",
+				LastName:  "Doe",
+			})
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("got %v, want %v", err, tt.want)
+			}
+
+			var weak *PasswordStrengthError
+			if errors.As(err, &weak) && (weak.MinScore != cfg.PasswordMinScore || len(weak.Strength.Suggestions) == 0) {
+				t.Errorf("strength error = %+v", weak)
+			}
+		})
+	}
+}
+
+func TestResetPasswordStrengthUsesAccountDetails(t *testing.T) {
+	cfg := testSecurityConfig()
+	cfg.PasswordPolicy = config.PasswordPolicyStrength
+	uc, deps := newTestUseCaseWithConfig(t, cfg)
+	seedUser(t, uc, deps, &domain.User{Email: "zygmunt@example.com", Username: "zygmuntkowalczyk"}, "original password")
+	token := requestResetToken(t, uc, deps, "zygmunt@example.com")
+
+	// Strong on its own, weak once it is matched against the username
+	password := "zygmuntkowalczyk1"
+	if !uc.EstimatePasswordStrength(password).Acceptable {
+		t.Fatal("password must be acceptable without user inputs")
+	}
+
+	var weak *PasswordStrengthError
+	if err := uc.ResetPassword(context.Background(), token, password); !errors.As(err, &weak) {
+		t.Fatalf("got %v, want *PasswordStrengthError", err)
+	}
+	if err := uc.ResetPassword(context.Background(), token, "correct horse battery"); err != nil {
+		t.Fatalf("token must survive the rejected attempt: %v", err)
+	}
+}
+
+func TestEstimatePasswordStrengthReportsPolicy(t *testing.T) {
+	cfg := testSecurityConfig()
+	cfg.PasswordPolicy = config.PasswordPolicyBoth
+	uc, _ := newTestUseCaseWithConfig(t, cfg)
+
+	weak := uc.EstimatePasswordStrength("jane1234", "jane@example.com", "jane")
+	if weak.Acceptable || weak.Score != 0 || weak.Warning == "" || weak.MinScore != cfg.PasswordMinScore {
+		t.Errorf("weak = %+v", weak)
+	}
+	strong := uc.EstimatePasswordStrength("correct horse battery")
+	if !strong.Acceptable {
+		t.Errorf("strong = %+v", strong)
+	}
+}
+
+func TestPasswordRules(t *testing.T) {
+	tests := []struct {
+		name     string
+		modify   func(*config.SecurityConfig)
+		password string
+		wantRule string
+	}{
+		{"too long", func(c *config.SecurityConfig) { c.PasswordMaxLength = 10 }, "eleven-char", PasswordRuleMaxLength},
+		{"missing uppercase", func(c *config.SecurityConfig) { c.PasswordRequireUpper = true }, "all lowercase", PasswordRuleUpper},
+		{"missing lowercase", func(c *config.SecurityConfig) { c.PasswordRequireLower = true }, "ALL UPPERCASE", PasswordRuleLower},
+		{"missing digit", func(c *config.SecurityConfig) { c.PasswordRequireDigit = true }, "no digits here", PasswordRuleDigit},
+		{"missing symbol", func(c *config.SecurityConfig) { c.PasswordRequireSymbol = true }, "NoSymbols123", PasswordRuleSymbol},
+		{"all classes present", func(c *config.SecurityConfig) {
+			c.PasswordRequireUpper, c.PasswordRequireLower, c.PasswordRequireDigit, c.PasswordRequireSymbol = true, true, true, true
+		}, "Tr0ub4dor&3", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := testSecurityConfig()
+			tt.modify(&cfg)
+			uc, deps := newTestUseCaseWithConfig(t, cfg)
+			seedUser(t, uc, deps, &domain.User{Email: "jane@example.com", Username: "jane"}, "original password")
+			token := requestResetToken(t, uc, deps, "jane@example.com")
+
+			err := uc.ResetPassword(context.Background(), token, tt.password)
+			var rule *PasswordRuleError
+			if tt.wantRule == "" {
+				if errors.As(err, &rule) {
+					t.Fatalf("rejected by rule %q", rule.Rule)
+				}
+				return
+			}
+			if !errors.As(err, &rule) || rule.Rule != tt.wantRule || !errors.Is(err, ErrWeakPassword) || rule.Message == "" {
+				t.Fatalf("got %v, want rule %q", err, tt.wantRule)
+			}
+		})
+	}
+}
+
+// fakeBreachChecker reports the passwords in breached; err simulates an outage
+type fakeBreachChecker struct {
+	breached map[string]bool
+	err      error
+	calls    int
+}
+
+func (f *fakeBreachChecker) CheckBreached(ctx context.Context, password string) (bool, error) {
+	f.calls++
+	return f.breached[password], f.err
+}
+
+func TestRegisterRejectsBreachedPassword(t *testing.T) {
+	checker := &fakeBreachChecker{breached: map[string]bool{"password123": true}}
+	uc, _ := newTestUseCaseWithConfig(t, testSecurityConfig(), WithBreachChecker(checker))
+
+	req := &dto.RegisterRequest{Email: "jane@example.com", Username: "jane", Password: "password123", FirstName: "Jane", LastName: "Doe"}
+	_, err := uc.Register(context.Background(), req)
+	var rule *PasswordRuleError
+	if !errors.As(err, &rule) || rule.Rule != PasswordRuleBreached {
+		t.Fatalf("got %v, want the breached rule", err)
+	}
+
+	// The anonymous strength endpoint never calls out
+	calls := checker.calls
+	if !uc.EstimatePasswordStrength("password123").Acceptable || checker.calls != calls {
+		t.Error("strength estimate must not check breaches")
+	}
+
+	// An unreachable breach API does not block registration
+	checker.err = errors.New("timeout")
+	if _, err := uc.Register(context.Background(), req); err != nil {
+		t.Fatalf("got %v, want success while the breach API is down", err)
+	}
+}
@@ -0,0 +1,27 @@
+package usecase
+
+import (
+	"context"
+
+	"auth-service/internal/domain"
+)
+
+// rehashPassword - Şifre hash'i güncel algoritma/parametrelerle yapılmamışsa yeniden hash'ler
+// BCRYPT_COST artırıldığında veya Argon2id'ye geçildiğinde eski hash'ler sonsuza kadar
+// zayıf kalmasın diye login'de çağrılır: düz şifre sadece bu anda elimizde.
+// Best-effort: hata olursa eski hash kalır, login başarısız olmaz.
+func (uc *AuthUseCase) rehashPassword(ctx context.Context, user *domain.User, password string) {
+	if !uc.passwordHasher.NeedsRehash(user.PasswordHash) {
+		return
+	}
+
+	passwordHash, err := uc.passwordHasher.Hash(password)
+	if err != nil {
+		return
+	}
+	previous := user.PasswordHash
+	user.PasswordHash = passwordHash
+	if err := uc.userRepo.Update(ctx, user); err != nil {
+		user.PasswordHash = previous
+	}
+}
@@ -0,0 +1,103 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"auth-service/internal/application/dto"
+	"auth-service/internal/domain"
+	"auth-service/pkg/security"
+)
+
+// failingUpdateUserRepo rejects every Update
+type failingUpdateUserRepo struct {
+	*fakeUserRepo
+}
+
+func (failingUpdateUserRepo) Update(ctx context.Context, user *domain.User) error {
+	return errors.New("database down")
+}
+
+func TestLoginRehashesOutdatedPassword(t *testing.T) {
+	uc, deps := newTestUseCase(t)
+	// Hashed before the cost was lowered to the test cost of 4
+	oldHash, err := security.NewBcryptHasher(5).Hash("correct-horse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	user := &domain.User{Email: "jane@example.com", Username: "jane", IsVerified: true, IsActive: true, PasswordHash: oldHash, FailedLoginAttempts: 2}
+	if err := deps.users.Create(context.Background(), user); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := uc.Login(context.Background(), &dto.LoginRequest{EmailOrUsername: "jane", Password: "correct-horse"}); err != nil {
+		t.Fatal(err)
+	}
+	stored, _ := deps.users.GetByID(context.Background(), user.ID)
+	if stored.PasswordHash == oldHash || !strings.HasPrefix(stored.PasswordHash, "$2a$04$") {
+		t.Errorf("hash = %q, want a cost 4 bcrypt hash", stored.PasswordHash)
+	}
+	// The full-row update must not bring back the reset failure counter
+	if stored.FailedLoginAttempts != 0 {
+		t.Errorf("FailedLoginAttempts = %d, want 0", stored.FailedLoginAttempts)
+	}
+	if !uc.passwordHasher.Compare(stored.PasswordHash, "correct-horse") {
+		t.Error("new hash does not match the password")
+	}
+
+	// A current hash is left alone
+	if _, err := uc.Login(context.Background(), &dto.LoginRequest{EmailOrUsername: "jane", Password: "correct-horse"}); err != nil {
+		t.Fatal(err)
+	}
+	again, _ := deps.users.GetByID(context.Background(), user.ID)
+	if again.PasswordHash != stored.PasswordHash {
+		t.Error("current hash was rehashed")
+	}
+}
+
+func TestLoginUpgradesBcryptToPreHashedBcrypt(t *testing.T) {
+	uc, deps := newTestUseCase(t)
+	uc.passwordHasher = security.NewBcryptHasher(4, security.WithBcryptPreHash())
+	// Hashed before BCRYPT_PREHASH was turned on
+	oldHash, err := security.NewBcryptHasher(4).Hash("correct-horse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	user := &domain.User{Email: "jane@example.com", Username: "jane", IsVerified: true, IsActive: true, PasswordHash: oldHash}
+	if err := deps.users.Create(context.Background(), user); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := uc.Login(context.Background(), &dto.LoginRequest{EmailOrUsername: "jane", Password: "correct-horse"}); err != nil {
+		t.Fatal(err)
+	}
+	stored, _ := deps.users.GetByID(context.Background(), user.ID)
+	if !strings.HasPrefix(stored.PasswordHash, "$bcrypt-sha256$") {
+		t.Errorf("hash = %q, want a pre-hashed bcrypt hash", stored.PasswordHash)
+	}
+	if _, err := uc.Login(context.Background(), &dto.LoginRequest{EmailOrUsername: "jane", Password: "correct-horse"}); err != nil {
+		t.Errorf("login with the upgraded hash: %v", err)
+	}
+}
+
+func TestLoginSucceedsWhenRehashFails(t *testing.T) {
+	uc, deps := newTestUseCase(t)
+	oldHash, err := security.NewBcryptHasher(5).Hash("correct-horse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	user := &domain.User{Email: "jane@example.com", Username: "jane", IsVerified: true, IsActive: true, PasswordHash: oldHash}
+	if err := deps.users.Create(context.Background(), user); err != nil {
+		t.Fatal(err)
+	}
+	uc.userRepo = failingUpdateUserRepo{deps.users}
+
+	if _, err := uc.Login(context.Background(), &dto.LoginRequest{EmailOrUsername: "jane", Password: "correct-horse"}); err != nil {
+		t.Fatalf("login failed because of the rehash: %v", err)
+	}
+	if stored, _ := deps.users.GetByID(context.Background(), user.ID); stored.PasswordHash != oldHash {
+		t.Error("hash changed although the update failed")
+	}
+}
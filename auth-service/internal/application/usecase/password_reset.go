@@ -0,0 +1,150 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"auth-service/internal/domain"
+	"auth-service/pkg/security"
+)
+
+// RequestPasswordReset - "Şifremi unuttum" akışını başlatır
+// Email kayıtlı değilse (veya hesap pasifse) de nil döner: böylece endpoint'ten
+// hangi email'lerin kayıtlı olduğu öğrenilemez (user enumeration koruması).
+// Aynı sebeple mail arka planda gönderilir: SMTP hatası (500) veya gönderim süresi
+// sadece kayıtlı email'lerde görülürdü.
+// Token'ın sadece SHA-256 hash'i saklanır; DB sızıntısında geçerli link'ler açığa çıkmaz.
+func (uc *AuthUseCase) RequestPasswordReset(ctx context.Context, orgSlug, email string) (err error) {
+	defer translateContextError(ctx, &err)
+
+	// ADIM 1: Kullanıcıyı organizasyonunda bul - organizasyon veya kullanıcı bulunamazsa sessizce başarılı dön
+	orgID, err := uc.resolveOrganization(ctx, orgSlug)
+	if errors.Is(err, ErrOrganizationNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	user, err := uc.userRepo.GetByEmail(ctx, orgID, email)
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !user.IsActive {
+		return nil
+	}
+
+	// ADIM 2: Önceki reset link'lerini geçersiz kıl (sadece en son link çalışır)
+	if err := uc.passwordResetRepo.DeleteByUserID(ctx, user.ID); err != nil {
+		return err
+	}
+
+	// ADIM 3: Yeni random token üret ve hash'ini sakla
+	token, err := security.GenerateOpaqueToken(uc.securityCfg.PasswordResetToken.Bytes)
+	if err != nil {
+		return err
+	}
+	resetToken := &domain.PasswordResetToken{
+		UserID:    user.ID,
+		TokenHash: security.HashToken(token),
+		ExpiresAt: time.Now().Add(uc.securityCfg.PasswordResetToken.TTL),
+	}
+	if err := uc.passwordResetRepo.Create(ctx, resetToken); err != nil {
+		return err
+	}
+
+	// ADIM 4: Reset link'ini arka planda gönder (hata sadece log'lanır)
+	uc.sendMailInBackground(ctx, "send password reset email", user.ID, user.Email, MailTemplatePasswordReset, map[string]any{
+		"Username":  user.Username,
+		"Link":      fmt.Sprintf("%s/reset-password?token=%s", uc.linkBaseURL, url.QueryEscape(token)),
+		"ExpiresIn": uc.securityCfg.PasswordResetToken.TTL,
+	})
+	return nil
+}
+
+// ResetPassword - Reset token'ı ile yeni şifre belirler
+// Başarılı olursa kullanıcının TÜM refresh token'ları iptal edilir:
+// şifreyi çalan biri varsa açık oturumları da kapanır.
+func (uc *AuthUseCase) ResetPassword(ctx context.Context, token, newPassword string) (err error) {
+	defer translateContextError(ctx, &err)
+
+	// ADIM 1: Anlamlı hata için önce tüketmeden kontrol et (expired vs invalid)
+	pending, err := uc.ValidatePasswordResetToken(ctx, token)
+	if err != nil {
+		return err
+	}
+
+	// ADIM 2: Token'ın sahibini bul. Kullanıcı okunamazsa reset durur:
+	// aksi halde policy ve geçmiş kontrolü kullanıcı bilgisi olmadan yapılırdı.
+	user, err := uc.userRepo.GetByID(ctx, pending.UserID)
+	if err != nil {
+		return notFoundAs(err, ErrInvalidToken)
+	}
+
+	// ADIM 3: Şifre policy'si (email/username'den türetilen şifreler zayıf sayılır)
+	// ve son şifreler (PasswordHistoryDepth) - token'ı tüketmeden önce kontrol et,
+	// böylece zayıf şifre denemesi link'i yakmaz
+	if err := uc.checkPasswordPolicy(ctx, newPassword, user.Email, user.Username); err != nil {
+		return err
+	}
+	if err := uc.checkPasswordHistory(ctx, user, newPassword); err != nil {
+		return err
+	}
+
+	// ADIM 4: Token'ı atomik olarak tüket - eşzamanlı iki istekten sadece biri geçer
+	if _, err := uc.passwordResetRepo.Consume(ctx, security.HashToken(token)); err != nil {
+		return notFoundAs(err, ErrInvalidToken)
+	}
+
+	// ADIM 5: Yeni şifreyi hash'le, kaydet ve geçmişe ekle
+	passwordHash, err := uc.passwordHasher.Hash(newPassword)
+	if err != nil {
+		return err
+	}
+	if err := uc.updatePassword(ctx, user, passwordHash); err != nil {
+		return err
+	}
+	uc.logAudit(ctx, AuditPasswordChanged, user.ID, map[string]string{"method": "reset"})
+	uc.publishUserEvent(ctx, EventPasswordChanged, user, map[string]interface{}{"method": "reset"})
+
+	// ADIM 6: Kalan reset link'lerini, tüm access token'ları ve tüm oturumları iptal et
+	if err := uc.passwordResetRepo.DeleteByUserID(ctx, user.ID); err != nil {
+		return err
+	}
+	if err := uc.invalidateAccessTokens(ctx, user.ID); err != nil {
+		return err
+	}
+	return uc.refreshTokenRepo.RevokeAllByUserID(ctx, user.ID)
+}
+
+// ValidatePasswordResetToken - Şifre sıfırlama token'ının geçerli olup olmadığını kontrol eder
+// Token'ı TÜKETMEZ (consume etmez): sadece okur.
+// Reset sayfası açılırken çağrılır, böylece kullanıcı yeni şifreyi yazmadan önce
+// link'in bozuk veya süresi dolmuş olduğunu görür.
+// Token'ı gerçekten tek seferlik kullanan işlem repository'deki atomik Consume'dur.
+func (uc *AuthUseCase) ValidatePasswordResetToken(ctx context.Context, token string) (_ *domain.PasswordResetToken, err error) {
+	defer translateContextError(ctx, &err)
+
+	// Veritabanında sadece hash saklanır, bu yüzden gelen token'ı hash'leyip arıyoruz
+	resetToken, err := uc.passwordResetRepo.GetByTokenHash(ctx, security.HashToken(token))
+	if err != nil {
+		return nil, notFoundAs(err, ErrInvalidToken)
+	}
+
+	// Kullanılmış token tekrar geçerli sayılmaz
+	if resetToken.IsUsed() {
+		return nil, ErrInvalidToken
+	}
+
+	// Süresi dolmuş token'ı ayrı hata ile döndür (UI farklı mesaj gösterebilsin)
+	if resetToken.IsExpired() {
+		return nil, ErrTokenExpired
+	}
+
+	return resetToken, nil
+}
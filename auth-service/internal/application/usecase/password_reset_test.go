@@ -0,0 +1,178 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"auth-service/internal/domain"
+	"auth-service/pkg/security"
+
+	"github.com/google/uuid"
+)
+
+func TestValidatePasswordResetToken(t *testing.T) {
+	ctx := context.Background()
+	uc, deps := newTestUseCase(t)
+	usedAt := time.Now().Add(-time.Minute)
+
+	seed := map[string]*domain.PasswordResetToken{
+		"valid":   {ExpiresAt: time.Now().Add(time.Hour)},
+		"expired": {ExpiresAt: time.Now().Add(-time.Minute)},
+		"used":    {ExpiresAt: time.Now().Add(time.Hour), UsedAt: &usedAt},
+	}
+	for token, rt := range seed {
+		rt.UserID = uuid.New()
+		rt.TokenHash = security.HashToken(token)
+		if err := deps.resetTokens.Create(ctx, rt); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		token   string
+		wantErr error
+	}{
+		{"valid", nil},
+		{"expired", ErrTokenExpired},
+		{"used", ErrInvalidToken},
+		{"unknown", ErrInvalidToken},
+	}
+	for _, tt := range tests {
+		t.Run(tt.token, func(t *testing.T) {
+			_, err := uc.ValidatePasswordResetToken(ctx, tt.token)
+			if err != tt.wantErr {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidatePasswordResetTokenDoesNotConsume(t *testing.T) {
+	ctx := context.Background()
+	uc, deps := newTestUseCase(t)
+
+	hash := security.HashToken("reset-me")
+	if err := deps.resetTokens.Create(ctx, &domain.PasswordResetToken{
+		UserID:    uuid.New(),
+		TokenHash: hash,
+		ExpiresAt: time.Now().Add(time.Hour),
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 3; i++ {
+		if _, err := uc.ValidatePasswordResetToken(ctx, "reset-me"); err != nil {
+			t.Fatalf("validation %d failed: %v", i, err)
+		}
+	}
+
+	// The token must still be consumable exactly once afterwards
+	if _, err := deps.resetTokens.Consume(ctx, hash); err != nil {
+		t.Fatalf("first consume failed: %v", err)
+	}
+	if _, err := deps.resetTokens.Consume(ctx, hash); err == nil {
+		t.Fatal("second consume succeeded, want failure")
+	}
+}
+
+// requestResetToken runs the forgot-password flow and returns the token from the emailed link
+func requestResetToken(t *testing.T, uc *AuthUseCase, deps *testDeps, email string) string {
+	t.Helper()
+	before := len(deps.mailer.Sent())
+	if err := uc.RequestPasswordReset(context.Background(), "", email); err != nil {
+		t.Fatal(err)
+	}
+	uc.background.Wait()
+	if len(deps.mailer.Sent()) == before {
+		t.Fatal("expected a reset email")
+	}
+	mail, _ := deps.mailer.Last()
+	return tokenFromMail(t, mail, MailTemplatePasswordReset, "/reset-password")
+}
+
+func TestRequestPasswordResetUnknownEmail(t *testing.T) {
+	uc, deps := newTestUseCase(t)
+
+	if err := uc.RequestPasswordReset(context.Background(), "", "nobody@example.com"); err != nil {
+		t.Fatalf("got %v, want nil to avoid user enumeration", err)
+	}
+	if _, ok := deps.mailer.Last(); ok {
+		t.Error("no email should be sent")
+	}
+}
+
+func TestRequestPasswordResetHidesMailFailures(t *testing.T) {
+	uc, deps := newTestUseCaseWithConfig(t, testSecurityConfig(), WithMailer(failingMailer{}, "https://app.example.com"))
+	seedUser(t, uc, deps, &domain.User{Email: "jane@example.com", Username: "jane"}, "correct-horse")
+
+	// A registered email answers like an unknown one even when SMTP is down
+	if err := uc.RequestPasswordReset(context.Background(), "", "jane@example.com"); err != nil {
+		t.Errorf("got %v, want nil", err)
+	}
+	uc.background.Wait()
+}
+
+func TestRequestPasswordResetStoresOnlyHash(t *testing.T) {
+	uc, deps := newTestUseCase(t)
+	seedUser(t, uc, deps, &domain.User{Email: "jane@example.com", Username: "jane"}, "correct-horse")
+
+	token := requestResetToken(t, uc, deps, "jane@example.com")
+	for hash := range deps.resetTokens.tokens {
+		if hash == token {
+			t.Fatal("plaintext token stored")
+		}
+		if hash != security.HashToken(token) {
+			t.Errorf("unexpected stored hash %q", hash)
+		}
+	}
+}
+
+func TestResetPassword(t *testing.T) {
+	ctx := context.Background()
+	uc, deps := newTestUseCase(t)
+	user := seedUser(t, uc, deps, &domain.User{Email: "jane@example.com", Username: "jane"}, "correct-horse")
+	deps.refreshTokens.Create(ctx, &domain.RefreshToken{UserID: user.ID, TokenHash: security.HashToken("session"), ExpiresAt: time.Now().Add(time.Hour)})
+
+	token := requestResetToken(t, uc, deps, "jane@example.com")
+	if err := uc.ResetPassword(ctx, token, "battery-staple"); err != nil {
+		t.Fatalf("ResetPassword: %v", err)
+	}
+
+	stored, _ := deps.users.GetByID(ctx, user.ID)
+	if !uc.passwordHasher.Compare(stored.PasswordHash, "battery-staple") {
+		t.Error("new password not saved")
+	}
+	if sessions, _ := deps.refreshTokens.GetByUserID(ctx, user.ID); len(sessions) != 0 {
+		t.Errorf("%d refresh tokens still active", len(sessions))
+	}
+	if err := uc.ResetPassword(ctx, token, "another-password"); err != ErrInvalidToken {
+		t.Errorf("second use: got %v, want ErrInvalidToken", err)
+	}
+}
+
+func TestResetPasswordInvalidatesOlderLinks(t *testing.T) {
+	uc, deps := newTestUseCase(t)
+	seedUser(t, uc, deps, &domain.User{Email: "jane@example.com", Username: "jane"}, "correct-horse")
+
+	first := requestResetToken(t, uc, deps, "jane@example.com")
+	requestResetToken(t, uc, deps, "jane@example.com")
+
+	if err := uc.ResetPassword(context.Background(), first, "battery-staple"); err != ErrInvalidToken {
+		t.Errorf("got %v, want ErrInvalidToken", err)
+	}
+}
+
+func TestResetPasswordWeakPasswordKeepsToken(t *testing.T) {
+	ctx := context.Background()
+	uc, deps := newTestUseCase(t)
+	seedUser(t, uc, deps, &domain.User{Email: "jane@example.com", Username: "jane"}, "correct-horse")
+
+	token := requestResetToken(t, uc, deps, "jane@example.com")
+	if err := uc.ResetPassword(ctx, token, "short"); err != ErrPasswordTooShort {
+		t.Fatalf("got %v, want ErrPasswordTooShort", err)
+	}
+	if err := uc.ResetPassword(ctx, token, "battery-staple"); err != nil {
+		t.Fatalf("token should still be usable: %v", err)
+	}
+}
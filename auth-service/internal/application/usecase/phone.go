@@ -0,0 +1,34 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"auth-service/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+// ErrInvalidPhone - Telefon numarası E.164'e çevrilemedi (ülke kodu eksik, geçersiz karakter vs.)
+var ErrInvalidPhone = newAppError(http.StatusBadRequest, "invalid_phone", "Phone number must be in international format, e.g. +905551234567")
+
+// checkPhone - Kayıtta verilen telefonu E.164'e çevirir ve organizasyonda kullanılıyor mu bakar
+// Telefon opsiyoneldir: boş verilirse boş döner ve benzersizlik kontrolü yapılmaz.
+func (uc *AuthUseCase) checkPhone(ctx context.Context, orgID uuid.UUID, phone string) (string, error) {
+	if phone == "" {
+		return "", nil
+	}
+	normalized, ok := domain.NormalizePhone(phone)
+	if !ok {
+		return "", ErrInvalidPhone
+	}
+	_, err := uc.userRepo.GetByPhone(ctx, orgID, normalized)
+	if err == nil {
+		return "", ErrUserAlreadyExists
+	}
+	if !errors.Is(err, domain.ErrNotFound) {
+		return "", err
+	}
+	return normalized, nil
+}
@@ -0,0 +1,60 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"auth-service/internal/application/dto"
+)
+
+func TestRegisterNormalizesPhoneAndLoginAcceptsIt(t *testing.T) {
+	uc, deps := newTestUseCase(t)
+
+	resp, err := uc.Register(context.Background(), &dto.RegisterRequest{
+		Email: "jane@example.com", Username: "jane", Password: "correct-horse", Phone: "+90 (555) 123-45-67",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.User.Phone != "+905551234567" {
+		t.Errorf("phone = %q, want +905551234567", resp.User.Phone)
+	}
+
+	// Any spelling of the number logs in; email and username still work
+	for _, identifier := range []string{"+905551234567", "0090 555 123 45 67", "jane", "jane@example.com"} {
+		if _, err := uc.Login(context.Background(), &dto.LoginRequest{EmailOrUsername: identifier, Password: "correct-horse"}); err != nil {
+			t.Errorf("login with %q: %v", identifier, err)
+		}
+	}
+	if _, err := uc.Login(context.Background(), &dto.LoginRequest{EmailOrUsername: "+905551234568", Password: "correct-horse"}); err != ErrInvalidCredentials {
+		t.Errorf("login with another number: err = %v, want ErrInvalidCredentials", err)
+	}
+
+	// Phones are unique, but users without one do not collide
+	_, err = uc.Register(context.Background(), &dto.RegisterRequest{
+		Email: "john@example.com", Username: "john", Password: "correct-horse", Phone: "00905551234567",
+	})
+	if err != ErrUserAlreadyExists {
+		t.Errorf("duplicate phone: err = %v, want ErrUserAlreadyExists", err)
+	}
+	for _, username := range []string{"john", "jack"} {
+		req := &dto.RegisterRequest{Email: username + "@example.com", Username: username, Password: "correct-horse"}
+		if _, err := uc.Register(context.Background(), req); err != nil {
+			t.Errorf("register %s without phone: %v", username, err)
+		}
+	}
+	if n := len(deps.users.users); n != 3 {
+		t.Errorf("%d users, want 3", n)
+	}
+}
+
+func TestRegisterRejectsInvalidPhone(t *testing.T) {
+	uc, _ := newTestUseCase(t)
+
+	_, err := uc.Register(context.Background(), &dto.RegisterRequest{
+		Email: "jane@example.com", Username: "jane", Password: "correct-horse", Phone: "0555 123 45 67",
+	})
+	if err != ErrInvalidPhone {
+		t.Errorf("err = %v, want ErrInvalidPhone", err)
+	}
+}
@@ -0,0 +1,71 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+
+	"auth-service/internal/application/dto"
+	"auth-service/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+// GetProfile - Kullanıcının güncel hesap durumunu veritabanından döner
+// Token'daki claim'ler token oluşturulduğu andaki değerlerdir; rol, scope'lar ve
+// bağlı provider'lar her zaman güncel olmalı. PasswordHash DTO'ya hiç girmez.
+func (uc *AuthUseCase) GetProfile(ctx context.Context, userID uuid.UUID) (_ *dto.MeResponse, err error) {
+	defer translateContextError(ctx, &err)
+
+	// ADIM 1: Kullanıcıyı bul
+	user, err := uc.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, notFoundAs(err, ErrUserNotFound)
+	}
+
+	// ADIM 2: Bağlı social login provider'ları (social login kapalıysa boş liste)
+	providers := []string{}
+	if uc.oauthAccounts != nil {
+		accounts, err := uc.oauthAccounts.GetByUserID(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		for _, account := range accounts {
+			providers = append(providers, account.Provider)
+		}
+	}
+
+	// ADIM 3: Scope'lar token'dakiyle aynı kaynaktan (rol) türetilir
+	scopes := domain.ScopesForRole(user.Role)
+	if scopes == nil {
+		scopes = []string{}
+	}
+
+	return &dto.MeResponse{
+		UserInfo:        *toUserInfo(user),
+		Scopes:          scopes,
+		CreatedAt:       user.CreatedAt,
+		LinkedProviders: providers,
+	}, nil
+}
+
+// UpdateProfile - Kullanıcının ad/soyadını günceller ve güncel profili döner
+// Email ve username burada değiştirilemez: ikisi de login kimliğidir ve
+// doğrulama/benzersizlik kontrolleri gerektirir (username için: ChangeUsername).
+func (uc *AuthUseCase) UpdateProfile(ctx context.Context, userID uuid.UUID, req *dto.UpdateProfileRequest) (_ *dto.UserInfo, err error) {
+	defer translateContextError(ctx, &err)
+
+	// ADIM 1: Kullanıcıyı bul
+	user, err := uc.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, notFoundAs(err, ErrUserNotFound)
+	}
+
+	// ADIM 2: Sadece isim alanlarını güncelle
+	user.FirstName = strings.TrimSpace(req.FirstName)
+	user.LastName = strings.TrimSpace(req.LastName)
+	if err := uc.userRepo.Update(ctx, user); err != nil {
+		return nil, err
+	}
+
+	return toUserInfo(user), nil
+}
@@ -0,0 +1,80 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"auth-service/internal/application/dto"
+	"auth-service/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+func TestUpdateProfileChangesOnlyNames(t *testing.T) {
+	uc, deps := newTestUseCase(t)
+	user := seedUser(t, uc, deps, &domain.User{Email: "jane@example.com", Username: "jane", FirstName: "Jane", LastName: "Doe"}, "correct-horse")
+
+	updated, err := uc.UpdateProfile(context.Background(), user.ID, &dto.UpdateProfileRequest{FirstName: " Janet ", LastName: "Smith"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.FirstName != "Janet" || updated.LastName != "Smith" || updated.Email != "jane@example.com" || updated.Username != "jane" {
+		t.Errorf("updated = %+v", updated)
+	}
+
+	// GetProfile reads the stored user, not token claims
+	profile, err := uc.GetProfile(context.Background(), user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if profile.UserInfo != *updated {
+		t.Errorf("profile = %+v, want %+v", profile, updated)
+	}
+}
+
+func TestGetProfileAccountState(t *testing.T) {
+	ctx := context.Background()
+	accounts := &fakeOAuthAccountRepo{}
+	uc, deps := newTestUseCaseWithConfig(t, testSecurityConfig(), WithOAuthAccounts(accounts))
+	user := seedUser(t, uc, deps, &domain.User{Email: "jane@example.com", Username: "jane", Role: domain.RoleUser, IsVerified: true}, "correct-horse")
+	if err := accounts.Create(ctx, &domain.OAuthAccount{UserID: user.ID, Provider: "github", ProviderUserID: "gh-1"}); err != nil {
+		t.Fatal(err)
+	}
+
+	profile, err := uc.GetProfile(ctx, user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !profile.IsVerified || profile.Role != domain.RoleUser || profile.TwoFactorEnabled {
+		t.Errorf("profile = %+v", profile)
+	}
+	if len(profile.Scopes) != 2 || profile.Scopes[0] != domain.ScopeProfileRead {
+		t.Errorf("scopes = %v, want the user role's scopes", profile.Scopes)
+	}
+	if len(profile.LinkedProviders) != 1 || profile.LinkedProviders[0] != "github" {
+		t.Errorf("linked providers = %v", profile.LinkedProviders)
+	}
+
+	// A promotion shows up without a new token
+	user.Role = domain.RoleAdmin
+	if err := deps.users.Update(ctx, user); err != nil {
+		t.Fatal(err)
+	}
+	if profile, err = uc.GetProfile(ctx, user.ID); err != nil {
+		t.Fatal(err)
+	}
+	if profile.Role != domain.RoleAdmin || len(profile.Scopes) != len(domain.ScopesForRole(domain.RoleAdmin)) {
+		t.Errorf("after promotion: role = %q, scopes = %v", profile.Role, profile.Scopes)
+	}
+}
+
+func TestProfileUnknownUser(t *testing.T) {
+	uc, _ := newTestUseCase(t)
+
+	if _, err := uc.GetProfile(context.Background(), uuid.New()); err != ErrUserNotFound {
+		t.Errorf("GetProfile err = %v", err)
+	}
+	if _, err := uc.UpdateProfile(context.Background(), uuid.New(), &dto.UpdateProfileRequest{FirstName: "A", LastName: "B"}); err != ErrUserNotFound {
+		t.Errorf("UpdateProfile err = %v", err)
+	}
+}
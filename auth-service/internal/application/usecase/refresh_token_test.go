@@ -0,0 +1,144 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"auth-service/internal/application/dto"
+	"auth-service/internal/domain"
+	"auth-service/pkg/security"
+)
+
+func loginTokens(t *testing.T, uc *AuthUseCase) *dto.AuthResponse {
+	t.Helper()
+	resp, err := uc.Login(context.Background(), &dto.LoginRequest{EmailOrUsername: "jane", Password: "correct-horse"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return resp
+}
+
+func TestRefreshTokenRotationKeepsFamily(t *testing.T) {
+	uc, deps := newTestUseCase(t)
+	seedUser(t, uc, deps, &domain.User{Email: "jane@example.com", Username: "jane", IsVerified: true}, "correct-horse")
+
+	first := loginTokens(t, uc)
+	second, err := uc.RefreshToken(context.Background(), first.RefreshToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	old, _ := deps.refreshTokens.GetByTokenHashIncludingRevoked(context.Background(), security.HashToken(first.RefreshToken))
+	rotated, _ := deps.refreshTokens.GetByTokenHash(context.Background(), security.HashToken(second.RefreshToken))
+	if !old.IsRevoked {
+		t.Error("rotated token should be revoked")
+	}
+	if rotated.FamilyID != old.FamilyID {
+		t.Errorf("family = %s, want %s", rotated.FamilyID, old.FamilyID)
+	}
+}
+
+func TestRefreshTokensAreStoredHashed(t *testing.T) {
+	uc, deps := newTestUseCase(t)
+	seedUser(t, uc, deps, &domain.User{Email: "jane@example.com", Username: "jane", IsVerified: true}, "correct-horse")
+
+	first := loginTokens(t, uc)
+	second, err := uc.RefreshToken(context.Background(), first.RefreshToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	plaintexts := []string{first.RefreshToken, second.RefreshToken}
+	stored := map[string]bool{}
+	for _, token := range deps.refreshTokens.tokens {
+		stored[token.TokenHash] = true
+		record, _ := json.Marshal(token)
+		for _, plaintext := range plaintexts {
+			if token.TokenHash == plaintext || strings.Contains(string(record), plaintext) {
+				t.Errorf("plaintext refresh token persisted: %+v", token)
+			}
+		}
+	}
+	for _, plaintext := range plaintexts {
+		if !stored[security.HashToken(plaintext)] {
+			t.Errorf("no record stored under the hash of %q", plaintext)
+		}
+	}
+}
+
+func TestRefreshTokenReuseRevokesFamilyOnly(t *testing.T) {
+	uc, deps := newTestUseCase(t)
+	seedUser(t, uc, deps, &domain.User{Email: "jane@example.com", Username: "jane", IsVerified: true}, "correct-horse")
+
+	// Two independent logins, e.g. laptop and phone
+	stolen := loginTokens(t, uc)
+	other := loginTokens(t, uc)
+
+	current, err := uc.RefreshToken(context.Background(), stolen.RefreshToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The already rotated token is replayed
+	if _, err := uc.RefreshToken(context.Background(), stolen.RefreshToken); err != ErrTokenReuseDetected {
+		t.Fatalf("got %v, want ErrTokenReuseDetected", err)
+	}
+
+	// The latest token of the compromised chain is dead...
+	if _, err := uc.RefreshToken(context.Background(), current.RefreshToken); err != ErrTokenReuseDetected {
+		t.Errorf("current token of the family: got %v, want ErrTokenReuseDetected", err)
+	}
+	// ...but the other login is untouched
+	if _, err := uc.RefreshToken(context.Background(), other.RefreshToken); err != nil {
+		t.Errorf("other family: %v", err)
+	}
+}
+
+func TestRefreshTokenUnknown(t *testing.T) {
+	uc, _ := newTestUseCase(t)
+
+	if _, err := uc.RefreshToken(context.Background(), "does-not-exist"); err != ErrInvalidToken {
+		t.Errorf("got %v, want ErrInvalidToken", err)
+	}
+}
+
+func TestRefreshTokenReuseWithoutFamilyRevokesAllSessions(t *testing.T) {
+	uc, deps := newTestUseCase(t)
+	user := seedUser(t, uc, deps, &domain.User{Email: "jane@example.com", Username: "jane", IsVerified: true}, "correct-horse")
+	loginTokens(t, uc)
+
+	// A token issued before family IDs existed
+	legacy := &domain.RefreshToken{UserID: user.ID, TokenHash: security.HashToken("legacy"), ExpiresAt: time.Now().Add(time.Hour), IsRevoked: true}
+	deps.refreshTokens.Create(context.Background(), legacy)
+
+	if _, err := uc.RefreshToken(context.Background(), "legacy"); err != ErrTokenReuseDetected {
+		t.Fatalf("got %v, want ErrTokenReuseDetected", err)
+	}
+	if active, _ := deps.refreshTokens.GetByUserID(context.Background(), user.ID); len(active) != 0 {
+		t.Errorf("%d sessions still active", len(active))
+	}
+}
+
+func TestRefreshTokenKeepsRoleScopes(t *testing.T) {
+	uc, deps := newTestUseCase(t)
+	seedUser(t, uc, deps, &domain.User{Email: "jane@example.com", Username: "jane", Role: domain.RoleAdmin, IsVerified: true}, "correct-horse")
+
+	first := loginTokens(t, uc)
+	second, err := uc.RefreshToken(context.Background(), first.RefreshToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, token := range map[string]string{"login": first.AccessToken, "refresh": second.AccessToken} {
+		claims, err := uc.jwtService.ValidateToken(token)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !security.HasScope(claims, domain.ScopeUsersWrite) || !security.HasScope(claims, domain.ScopeProfileRead) {
+			t.Errorf("%s token scopes = %q, want the admin scopes", name, claims.Scopes)
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"auth-service/internal/application/dto"
+	"auth-service/internal/domain"
+	"auth-service/pkg/security"
+)
+
+// storedRefreshToken returns the repository record of a refresh token string
+func storedRefreshToken(t *testing.T, deps *testDeps, token string) *domain.RefreshToken {
+	t.Helper()
+	stored, err := deps.refreshTokens.GetByTokenHash(context.Background(), security.HashToken(token))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return stored
+}
+
+func TestRememberMeRefreshTokenTTL(t *testing.T) {
+	const rememberMeTTL = 30 * 24 * time.Hour
+	const refreshTTL = 7 * 24 * time.Hour // newTestUseCaseWithConfig
+
+	tests := []struct {
+		name       string
+		opts       []AuthUseCaseOption
+		rememberMe bool
+		wantTTL    time.Duration
+	}{
+		{"normal login", []AuthUseCaseOption{WithRememberMe(rememberMeTTL)}, false, refreshTTL},
+		{"remember me", []AuthUseCaseOption{WithRememberMe(rememberMeTTL)}, true, rememberMeTTL},
+		{"remember me not configured", nil, true, refreshTTL},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc, deps := newTestUseCaseWithConfig(t, testSecurityConfig(), tt.opts...)
+			seedUser(t, uc, deps, &domain.User{Email: "jane@example.com", Username: "jane", IsVerified: true}, "correct-horse")
+
+			resp, err := uc.Login(context.Background(), &dto.LoginRequest{
+				EmailOrUsername: "jane", Password: "correct-horse", RememberMe: tt.rememberMe,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			// ExpiresIn is always the access token lifetime
+			if resp.ExpiresIn != int64((15 * time.Minute).Seconds()) {
+				t.Errorf("expires_in = %d, want the access token TTL", resp.ExpiresIn)
+			}
+			assertRefreshTTL(t, storedRefreshToken(t, deps, resp.RefreshToken), tt.wantTTL)
+
+			// Rotation keeps the lifetime of the original login
+			rotated, err := uc.RefreshToken(context.Background(), resp.RefreshToken)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if rotated.ExpiresIn != resp.ExpiresIn {
+				t.Errorf("rotated expires_in = %d", rotated.ExpiresIn)
+			}
+			assertRefreshTTL(t, storedRefreshToken(t, deps, rotated.RefreshToken), tt.wantTTL)
+		})
+	}
+}
+
+func TestRefreshSlidesUpToTheAbsoluteTTL(t *testing.T) {
+	const refreshTTL = 7 * 24 * time.Hour // newTestUseCaseWithConfig
+	const absoluteTTL = 30 * 24 * time.Hour
+	uc, deps := newTestUseCaseWithConfig(t, testSecurityConfig(), WithRefreshAbsoluteTTL(absoluteTTL))
+	seedUser(t, uc, deps, &domain.User{Email: "jane@example.com", Username: "jane", IsVerified: true}, "correct-horse")
+	ctx := context.Background()
+
+	// Logged in 5 days ago; the token is about to expire
+	first := loginTokens(t, uc)
+	stored := storedRefreshToken(t, deps, first.RefreshToken)
+	loggedInAt := time.Now().Add(-5 * 24 * time.Hour)
+	for _, token := range deps.refreshTokens.tokens {
+		token.AuthenticatedAt = &loggedInAt
+		token.ExpiresAt = time.Now().Add(time.Hour)
+	}
+
+	// Sliding: the rotated token lives a full refresh TTL again
+	second, err := uc.RefreshToken(ctx, first.RefreshToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rotated := storedRefreshToken(t, deps, second.RefreshToken)
+	assertRefreshTTL(t, rotated, refreshTTL)
+	if !rotated.SessionStart().Equal(loggedInAt) || rotated.FamilyID != stored.FamilyID {
+		t.Errorf("rotated token started at %s in family %s, want the login's", rotated.SessionStart(), rotated.FamilyID)
+	}
+
+	// Near the cap the rotated token ends at the cap, not a refresh TTL later
+	loggedInAt = time.Now().Add(-absoluteTTL + 24*time.Hour)
+	for _, token := range deps.refreshTokens.tokens {
+		token.AuthenticatedAt = &loggedInAt
+	}
+	third, err := uc.RefreshToken(ctx, second.RefreshToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertRefreshTTL(t, storedRefreshToken(t, deps, third.RefreshToken), 24*time.Hour)
+}
+
+func TestRefreshFailsAfterTheAbsoluteTTL(t *testing.T) {
+	const absoluteTTL = 30 * 24 * time.Hour
+	tests := []struct {
+		name string
+		opts []AuthUseCaseOption
+		want error
+	}{
+		{"absolute TTL reached", []AuthUseCaseOption{WithRefreshAbsoluteTTL(absoluteTTL)}, ErrInvalidToken},
+		{"no absolute TTL", nil, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uc, deps := newTestUseCaseWithConfig(t, testSecurityConfig(), tt.opts...)
+			seedUser(t, uc, deps, &domain.User{Email: "jane@example.com", Username: "jane", IsVerified: true}, "correct-horse")
+
+			// The token itself is still fresh, but the login was 31 days ago
+			resp := loginTokens(t, uc)
+			loggedInAt := time.Now().Add(-absoluteTTL - 24*time.Hour)
+			deps.refreshTokens.tokens[0].AuthenticatedAt = &loggedInAt
+
+			if _, err := uc.RefreshToken(context.Background(), resp.RefreshToken); err != tt.want {
+				t.Errorf("got %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func assertRefreshTTL(t *testing.T, token *domain.RefreshToken, want time.Duration) {
+	t.Helper()
+	if got := time.Until(token.ExpiresAt); got > want || got < want-time.Minute {
+		t.Errorf("refresh token expires in %s, want %s", got.Round(time.Hour), want)
+	}
+}
@@ -0,0 +1,17 @@
+package usecase
+
+import (
+	"errors"
+
+	"auth-service/internal/domain"
+)
+
+// notFoundAs - Repository'den gelen domain.ErrNotFound'u use case'in sentinel hatasına (target) çevirir
+// Diğer hatalar (DB bağlantısı koptu vs.) olduğu gibi döner; handler bunları 500 olarak raporlar.
+// Böylece "veritabanı çöktü" durumu "kullanıcı yok" / "şifre yanlış" gibi görünmez.
+func notFoundAs(err, target error) error {
+	if errors.Is(err, domain.ErrNotFound) {
+		return target
+	}
+	return err
+}
@@ -0,0 +1,96 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// sessionIDKey - Context'te mevcut oturum ID'sini taşımak için private key tipi
+// Private tip kullanıyoruz: başka paketler aynı key ile çakışamaz
+type sessionIDKey struct{}
+
+// ContextWithSessionID - Mevcut oturumun ID'sini (access token'daki "sid") context'e ekler
+// Handler'lar, "bu oturum hariç" çalışan use case'leri çağırmadan önce kullanır.
+func ContextWithSessionID(ctx context.Context, sessionID uuid.UUID) context.Context {
+	return context.WithValue(ctx, sessionIDKey{}, sessionID)
+}
+
+// sessionIDFromContext - Context'teki oturum ID'sini döndürür (yoksa false)
+func sessionIDFromContext(ctx context.Context) (uuid.UUID, bool) {
+	sessionID, ok := ctx.Value(sessionIDKey{}).(uuid.UUID)
+	if !ok || sessionID == uuid.Nil {
+		return uuid.Nil, false
+	}
+	return sessionID, true
+}
+
+// accessTokenKey - Context'te mevcut access token'ın jti ve bitiş zamanını taşır
+type accessTokenKey struct{}
+
+type accessToken struct {
+	id        string
+	expiresAt time.Time
+}
+
+// ContextWithAccessToken - İsteği yapan access token'ın ID'sini (jti) ve bitiş zamanını context'e ekler
+// Logout bu bilgiyle token'ı süresi dolana kadar blacklist'e alır.
+func ContextWithAccessToken(ctx context.Context, tokenID string, expiresAt time.Time) context.Context {
+	return context.WithValue(ctx, accessTokenKey{}, accessToken{id: tokenID, expiresAt: expiresAt})
+}
+
+// accessTokenFromContext - Context'teki access token bilgisini döndürür (yoksa false)
+func accessTokenFromContext(ctx context.Context) (accessToken, bool) {
+	token, ok := ctx.Value(accessTokenKey{}).(accessToken)
+	if !ok || token.id == "" {
+		return accessToken{}, false
+	}
+	return token, true
+}
+
+// clientKey - Context'te isteği yapan cihazın bilgilerini taşır
+type clientKey struct{}
+
+// maxUserAgentLength - refresh_tokens.user_agent kolon boyutu
+const maxUserAgentLength = 512
+
+type client struct {
+	userAgent string
+	ipAddress string
+}
+
+// ContextWithClient - İsteğin User-Agent'ını ve IP adresini context'e ekler
+// Token veren use case'ler (login, refresh...) bunları oturum kaydına yazar.
+func ContextWithClient(ctx context.Context, userAgent, ipAddress string) context.Context {
+	if len(userAgent) > maxUserAgentLength {
+		userAgent = userAgent[:maxUserAgentLength]
+	}
+	return context.WithValue(ctx, clientKey{}, client{userAgent: userAgent, ipAddress: ipAddress})
+}
+
+// known - İstekte cihaz bilgisi (IP veya User-Agent) var mı
+func (c client) known() bool {
+	return c.ipAddress != "" || c.userAgent != ""
+}
+
+// clientFromContext - Context'teki cihaz bilgisini döndürür (yoksa boş)
+func clientFromContext(ctx context.Context) client {
+	c, _ := ctx.Value(clientKey{}).(client)
+	return c
+}
+
+// requestIDKey - Context'te isteğin ID'sini (X-Request-ID) taşır
+type requestIDKey struct{}
+
+// ContextWithRequestID - İsteğin ID'sini context'e ekler
+// Audit kayıtları ve log satırları bu ID ile isteğe bağlanır.
+func ContextWithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestIDFromContext - Context'teki istek ID'sini döndürür (yoksa boş)
+func RequestIDFromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
+}
@@ -0,0 +1,126 @@
+package usecase
+
+import (
+	"context"
+	"sort"
+	"strconv"
+
+	"auth-service/internal/application/dto"
+	"auth-service/internal/domain"
+	"auth-service/pkg/security"
+
+	"github.com/google/uuid"
+)
+
+// ListSessions - Kullanıcının aktif oturumlarını (iptal edilmemiş refresh token'ları) listeler
+// Cihaz bilgisi (User-Agent, IP, son kullanım) sayesinde kullanıcı cihazlarını tanıyabilir.
+// İsteği yapan oturum (ContextWithSessionID) "current" olarak işaretlenir.
+func (uc *AuthUseCase) ListSessions(ctx context.Context, userID uuid.UUID) (_ []dto.SessionInfo, err error) {
+	defer translateContextError(ctx, &err)
+
+	// ADIM 1: Aktif refresh token'ları getir
+	tokens, err := uc.refreshTokenRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// ADIM 2: DTO'ya çevir; süresi dolmuşlar (henüz temizlenmemiş olsa da) listelenmez
+	// En yeni oturum en üstte
+	currentID, _ := sessionIDFromContext(ctx)
+	return sessionInfos(tokens, currentID, false), nil
+}
+
+// sessionInfos - Refresh token kayıtlarını en yeni en üstte olacak şekilde SessionInfo'ya çevirir
+// includeInactive false ise süresi dolmuş ve iptal edilmiş kayıtlar atlanır.
+func sessionInfos(tokens []*domain.RefreshToken, currentID uuid.UUID, includeInactive bool) []dto.SessionInfo {
+	sessions := make([]dto.SessionInfo, 0, len(tokens))
+	for _, token := range tokens {
+		if !includeInactive && (token.IsExpired() || token.IsRevoked) {
+			continue
+		}
+		sessions = append(sessions, dto.SessionInfo{
+			ID:         token.ID.String(),
+			UserAgent:  token.UserAgent,
+			IPAddress:  token.IPAddress,
+			CreatedAt:  token.CreatedAt,
+			LastUsedAt: token.LastUsedAt,
+			ExpiresAt:  token.ExpiresAt,
+			Current:    token.ID == currentID,
+			Revoked:    token.IsRevoked,
+		})
+	}
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].CreatedAt.After(sessions[j].CreatedAt)
+	})
+	return sessions
+}
+
+// LogoutSession - Sadece verilen refresh token'ın oturumunu kapatır (diğer cihazlar açık kalır)
+// Token başka bir kullanıcıya aitse ErrSessionNotFound döner: hiçbir şey iptal edilmez
+// ve token'ın var olup olmadığı da belli edilmez.
+func (uc *AuthUseCase) LogoutSession(ctx context.Context, userID uuid.UUID, refreshToken string) (err error) {
+	defer translateContextError(ctx, &err)
+
+	// ADIM 1: Token'ı bul ve sahibini kontrol et
+	tokenHash := security.HashToken(refreshToken)
+	token, err := uc.refreshTokenRepo.GetByTokenHash(ctx, tokenHash)
+	if err != nil {
+		return notFoundAs(err, ErrSessionNotFound)
+	}
+	if token.UserID != userID {
+		return ErrSessionNotFound
+	}
+
+	// ADIM 2: Sadece bu oturumu iptal et
+	if err := uc.refreshTokenRepo.Revoke(ctx, tokenHash); err != nil {
+		return err
+	}
+	uc.logAudit(ctx, AuditLogout, userID, map[string]string{"session_id": token.ID.String()})
+
+	// ADIM 3: Mevcut access token'ı blacklist'e al
+	return uc.blacklistCurrentToken(ctx)
+}
+
+// enforceSessionLimit - Kullanıcının aktif oturumu SecurityConfig.MaxSessionsPerUser'ı aşıyorsa
+// en eski (CreatedAt) oturumları iptal eder; 0 limitsizdir.
+// Hata kritik değil: login başarılı olur, sadece loglanır.
+func (uc *AuthUseCase) enforceSessionLimit(ctx context.Context, userID uuid.UUID) {
+	limit := uc.securityCfg.MaxSessionsPerUser
+	if limit <= 0 {
+		return
+	}
+	active, err := uc.refreshTokenRepo.ActiveCount(ctx, userID)
+	if err != nil {
+		uc.logError(ctx, "count active sessions", err, "user_id", userID)
+		return
+	}
+	if active <= int64(limit) {
+		return
+	}
+	evicted, err := uc.refreshTokenRepo.RevokeOldest(ctx, userID, limit)
+	if err != nil {
+		uc.logError(ctx, "revoke oldest sessions", err, "user_id", userID)
+		return
+	}
+	uc.logAudit(ctx, AuditSessionsEvicted, userID, map[string]string{"count": strconv.FormatInt(evicted, 10)})
+}
+
+// endOtherSessions - Tek oturum modu (SecurityConfig.SingleSessionMode): yeni login'den önce
+// kullanıcının tüm oturumlarını kapatır ve o ana kadarki access token'larını geçersiz kılar.
+// user.TokenVersion güncellenir, böylece yeni oturumun access token'ı kabul edilir.
+func (uc *AuthUseCase) endOtherSessions(ctx context.Context, user *domain.User) error {
+	active, err := uc.refreshTokenRepo.ActiveCount(ctx, user.ID)
+	if err != nil || active == 0 {
+		return err
+	}
+	if err := uc.refreshTokenRepo.RevokeAllByUserID(ctx, user.ID); err != nil {
+		return err
+	}
+	version, err := uc.userRepo.IncrementTokenVersion(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+	user.TokenVersion = version
+	uc.logAudit(ctx, AuditSessionsEvicted, user.ID, map[string]string{"count": strconv.FormatInt(active, 10), "reason": "single_session"})
+	return nil
+}
@@ -0,0 +1,161 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"auth-service/internal/application/dto"
+	"auth-service/internal/domain"
+	"auth-service/pkg/security"
+)
+
+func TestSessionsRecordDeviceMetadata(t *testing.T) {
+	uc, deps := newTestUseCase(t)
+	user := seedUser(t, uc, deps, &domain.User{Email: "jane@example.com", Username: "jane", IsVerified: true}, "correct-horse")
+
+	laptop := ContextWithClient(context.Background(), "Firefox/130.0", "203.0.113.7")
+	first, err := uc.Login(laptop, &dto.LoginRequest{EmailOrUsername: "jane", Password: "correct-horse"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	created, _ := deps.refreshTokens.GetByTokenHash(context.Background(), security.HashToken(first.RefreshToken))
+	if created.UserAgent != "Firefox/130.0" || created.IPAddress != "203.0.113.7" || created.LastUsedAt != nil {
+		t.Errorf("new session = %+v", created)
+	}
+
+	// The laptop refreshes from another network
+	moved := ContextWithClient(context.Background(), "Firefox/130.0", "198.51.100.20")
+	second, err := uc.RefreshToken(moved, first.RefreshToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	old, _ := deps.refreshTokens.GetByTokenHashIncludingRevoked(context.Background(), security.HashToken(first.RefreshToken))
+	if old.LastUsedAt == nil {
+		t.Error("refresh did not touch the presented token")
+	}
+	rotated, _ := deps.refreshTokens.GetByTokenHash(context.Background(), security.HashToken(second.RefreshToken))
+	if rotated.IPAddress != "198.51.100.20" || rotated.LastUsedAt == nil {
+		t.Errorf("rotated session = %+v", rotated)
+	}
+
+	// A phone logs in too; the laptop asks for the list
+	phone := ContextWithClient(context.Background(), "Mobile Safari", "192.0.2.1")
+	if _, err := uc.Login(phone, &dto.LoginRequest{EmailOrUsername: "jane", Password: "correct-horse"}); err != nil {
+		t.Fatal(err)
+	}
+	sessions, err := uc.ListSessions(ContextWithSessionID(context.Background(), rotated.ID), user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sessions) != 2 {
+		t.Fatalf("sessions = %+v, want 2", sessions)
+	}
+	if sessions[0].UserAgent != "Mobile Safari" || sessions[0].Current {
+		t.Errorf("newest session = %+v, want the phone", sessions[0])
+	}
+	if sessions[1].ID != rotated.ID.String() || !sessions[1].Current {
+		t.Errorf("second session = %+v, want the current laptop session", sessions[1])
+	}
+}
+
+func TestLoginEvictsOldestSessionsOverLimit(t *testing.T) {
+	const limit = 3
+	cfg := testSecurityConfig()
+	cfg.MaxSessionsPerUser = limit
+	audit := &fakeAuditLogger{}
+	uc, deps := newTestUseCaseWithConfig(t, cfg, WithAuditLogger(audit))
+	user := seedUser(t, uc, deps, &domain.User{Email: "jane@example.com", Username: "jane", IsVerified: true}, "correct-horse")
+
+	var tokens []string
+	for i := 0; i < limit+1; i++ {
+		resp, err := uc.Login(context.Background(), &dto.LoginRequest{EmailOrUsername: "jane", Password: "correct-horse"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		tokens = append(tokens, resp.RefreshToken)
+	}
+
+	if active, _ := deps.refreshTokens.ActiveCount(context.Background(), user.ID); active != limit {
+		t.Errorf("active sessions = %d, want %d", active, limit)
+	}
+	if _, err := uc.RefreshToken(context.Background(), tokens[0]); err != ErrTokenReuseDetected {
+		t.Errorf("oldest session: err = %v, want it revoked", err)
+	}
+	for _, token := range tokens[2:] {
+		if _, err := deps.refreshTokens.GetByTokenHash(context.Background(), security.HashToken(token)); err != nil {
+			t.Errorf("newer session revoked: %v", err)
+		}
+	}
+
+	var evicted bool
+	for _, e := range audit.events {
+		evicted = evicted || (e.Action == AuditSessionsEvicted && e.Details["count"] == "1")
+	}
+	if !evicted {
+		t.Error("expected a sessions_evicted audit event")
+	}
+}
+
+func TestSingleSessionModeRevokesPreviousLogin(t *testing.T) {
+	cfg := testSecurityConfig()
+	cfg.SingleSessionMode = true
+	uc, deps := newTestUseCaseWithConfig(t, cfg)
+	user := seedUser(t, uc, deps, &domain.User{Email: "jane@example.com", Username: "jane", IsVerified: true}, "correct-horse")
+
+	first := loginTokens(t, uc)
+	second := loginTokens(t, uc)
+
+	if _, err := deps.refreshTokens.GetByTokenHash(context.Background(), security.HashToken(first.RefreshToken)); err == nil {
+		t.Error("first session still active after the second login")
+	}
+	if resp, err := uc.IntrospectToken(context.Background(), first.AccessToken); err != nil || resp.Active {
+		t.Errorf("first access token: active = %v, err = %v", resp != nil && resp.Active, err)
+	}
+
+	// The new login is created after the revoke, so it survives
+	if active, _ := deps.refreshTokens.ActiveCount(context.Background(), user.ID); active != 1 {
+		t.Errorf("active sessions = %d, want 1", active)
+	}
+	if resp, err := uc.IntrospectToken(context.Background(), second.AccessToken); err != nil || !resp.Active {
+		t.Errorf("second access token: active = %v, err = %v", resp != nil && resp.Active, err)
+	}
+	// Refreshing is not a new login and keeps the session
+	if _, err := uc.RefreshToken(context.Background(), second.RefreshToken); err != nil {
+		t.Errorf("refresh of the current session: %v", err)
+	}
+	if active, _ := deps.refreshTokens.ActiveCount(context.Background(), user.ID); active != 1 {
+		t.Errorf("active sessions after refresh = %d, want 1", active)
+	}
+}
+
+func TestRefreshDoesNotCountAgainstSessionLimit(t *testing.T) {
+	cfg := testSecurityConfig()
+	cfg.MaxSessionsPerUser = 1
+	uc, deps := newTestUseCaseWithConfig(t, cfg)
+	user := seedUser(t, uc, deps, &domain.User{Email: "jane@example.com", Username: "jane", IsVerified: true}, "correct-horse")
+
+	resp, err := uc.Login(context.Background(), &dto.LoginRequest{EmailOrUsername: "jane", Password: "correct-horse"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp, err = uc.RefreshToken(context.Background(), resp.RefreshToken); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := deps.refreshTokens.GetByTokenHash(context.Background(), security.HashToken(resp.RefreshToken)); err != nil {
+		t.Errorf("rotated token revoked: %v", err)
+	}
+	if active, _ := deps.refreshTokens.ActiveCount(context.Background(), user.ID); active != 1 {
+		t.Errorf("active sessions = %d, want 1", active)
+	}
+}
+
+func TestContextWithClientTruncatesUserAgent(t *testing.T) {
+	ctx := ContextWithClient(context.Background(), strings.Repeat("a", 2000), "203.0.113.7")
+	if got := clientFromContext(ctx); len(got.userAgent) != maxUserAgentLength {
+		t.Errorf("user agent length = %d, want %d", len(got.userAgent), maxUserAgentLength)
+	}
+	if got := clientFromContext(context.Background()); got != (client{}) {
+		t.Errorf("empty context = %+v", got)
+	}
+}
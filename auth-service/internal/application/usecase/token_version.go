@@ -0,0 +1,26 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// CurrentTokenVersion - Kullanıcının güncel token version'ı (AuthMiddleware "tv" claim'ini bununla karşılaştırır)
+// Daha küçük version taşıyan access token'lar invalidateAccessTokens ile geçersiz kılınmıştır.
+// Silinmiş veya bulunamayan kullanıcı ErrUserNotFound döner.
+func (uc *AuthUseCase) CurrentTokenVersion(ctx context.Context, userID uuid.UUID) (int, error) {
+	version, err := uc.userRepo.GetTokenVersion(ctx, userID)
+	if err != nil {
+		return 0, notFoundAs(err, ErrUserNotFound)
+	}
+	return version, nil
+}
+
+// invalidateAccessTokens - Kullanıcının token version'ını artırır
+// O ana kadar verilmiş tüm access token'lar (stateless olsalar da) hemen reddedilir;
+// refresh token'ı iptal edilmemiş oturumlar refresh ile yeni version'lı token alır.
+func (uc *AuthUseCase) invalidateAccessTokens(ctx context.Context, userID uuid.UUID) error {
+	_, err := uc.userRepo.IncrementTokenVersion(ctx, userID)
+	return err
+}
@@ -0,0 +1,51 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"auth-service/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+func TestChangePasswordInvalidatesAccessTokens(t *testing.T) {
+	uc, deps := newTestUseCase(t)
+	user := seedUser(t, uc, deps, &domain.User{Email: "jane@example.com", Username: "jane", IsVerified: true}, "correct-horse")
+	current := loginTokens(t, uc)
+	other := loginTokens(t, uc)
+
+	claims, err := uc.jwtService.ValidateToken(current.AccessToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := ContextWithSessionID(context.Background(), uuid.MustParse(claims.SessionID))
+	if err := uc.ChangePassword(ctx, user.ID, "correct-horse", "battery-staple"); err != nil {
+		t.Fatal(err)
+	}
+
+	if version, err := uc.CurrentTokenVersion(context.Background(), user.ID); err != nil || version != 1 {
+		t.Fatalf("CurrentTokenVersion = %d, %v; want 1", version, err)
+	}
+	for _, token := range []string{current.AccessToken, other.AccessToken} {
+		if resp, err := uc.IntrospectToken(context.Background(), token); err != nil || resp.Active {
+			t.Errorf("access token from before the change: active = %v, err = %v", resp != nil && resp.Active, err)
+		}
+	}
+
+	// The current session gets a token with the new version on refresh
+	refreshed, err := uc.RefreshToken(context.Background(), current.RefreshToken)
+	if err != nil {
+		t.Fatalf("refresh of the current session: %v", err)
+	}
+	if resp, err := uc.IntrospectToken(context.Background(), refreshed.AccessToken); err != nil || !resp.Active {
+		t.Errorf("refreshed access token: active = %v, err = %v", resp != nil && resp.Active, err)
+	}
+}
+
+func TestCurrentTokenVersionOfUnknownUser(t *testing.T) {
+	uc, _ := newTestUseCase(t)
+	if _, err := uc.CurrentTokenVersion(context.Background(), uuid.New()); err != ErrUserNotFound {
+		t.Errorf("err = %v, want ErrUserNotFound", err)
+	}
+}
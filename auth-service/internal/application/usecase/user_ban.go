@@ -0,0 +1,133 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"auth-service/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+// errSessionRevocationNotConfigured - WithSessionRevocation verilmeden kullanıcı banlanmaya çalışıldı
+var errSessionRevocationNotConfigured = errors.New("session revocation is not configured")
+
+// WithSessionRevocation - Banlanan kullanıcının oturumlarını kapatmak için gereken bağımlılıklar
+// accessTokenTTL, AuthUseCase'in access token ömrüyle aynı olmalı: bir oturumun
+// son access token'ı en geç bu süre sonunda kendiliğinden geçersiz olur.
+// Verilmezse SetUserActive(false) hata döner; blacklist nil ise sadece refresh token'lar iptal edilir.
+func WithSessionRevocation(refreshTokens domain.RefreshTokenRepository, blacklist domain.TokenBlacklist, accessTokenTTL time.Duration) AdminUseCaseOption {
+	return func(uc *AdminUseCase) {
+		uc.refreshTokens = refreshTokens
+		if blacklist != nil {
+			uc.tokenBlacklist = blacklist
+		}
+		uc.accessTokenTTL = accessTokenTTL
+	}
+}
+
+// SetUserActive - Kullanıcıyı banlar (active=false) veya banını kaldırır (active=true)
+// Banlanan kullanıcının tüm oturumları hemen kapanır: refresh token'lar iptal edilir,
+// hâlâ geçerli access token'ların oturumları (sid) blacklist'e alınır.
+// Login ve refresh zaten IsActive kontrol ettiği için ErrUserInactive döner.
+func (uc *AdminUseCase) SetUserActive(ctx context.Context, actorID, userID uuid.UUID, active bool) error {
+	if !active && uc.refreshTokens == nil {
+		return errSessionRevocationNotConfigured
+	}
+
+	// ADIM 1: Kullanıcıyı bul (başka organizasyonun kullanıcısı bulunamaz); zaten istenen durumdaysa bir şey yapma
+	user, err := uc.orgUser(ctx, actorID, userID)
+	if err != nil {
+		return err
+	}
+	if user.IsActive == active {
+		return nil
+	}
+
+	// ADIM 2: Durumu güncelle
+	user.IsActive = active
+	if err := uc.userRepo.Update(ctx, user); err != nil {
+		return err
+	}
+
+	// ADIM 3: Banlandıysa oturumlarını kapat
+	// Kullanıcı güncellendikten sonra yapılır: arada refresh olursa IsActive kontrolüne takılır
+	if !active {
+		if err := uc.revokeSessions(ctx, user.ID); err != nil {
+			return err
+		}
+	}
+
+	// ADIM 4: Kararı kaydet ve bildir
+	action := "user.unbanned"
+	if !active {
+		action = "user.banned"
+	}
+	uc.audit.Log(ctx, AuditEvent{Action: action, ActorID: actorID, TargetID: user.ID})
+	uc.publish(ctx, action, actorID, user)
+
+	return nil
+}
+
+// ForceLogout - Kullanıcının tüm oturumlarını kapatır, hesabı aktif kalır
+// Ele geçirilmiş hesaplar için: refresh token'lar iptal edilir, token version artırılır ve
+// hâlâ geçerli access token'ların oturumları (sid) blacklist'e alınır; kullanıcı tekrar login olmalı.
+func (uc *AdminUseCase) ForceLogout(ctx context.Context, actorID, userID uuid.UUID) error {
+	if uc.refreshTokens == nil {
+		return errSessionRevocationNotConfigured
+	}
+
+	// ADIM 1: Kullanıcıyı bul (başka organizasyonun kullanıcısı bulunamaz)
+	user, err := uc.orgUser(ctx, actorID, userID)
+	if err != nil {
+		return err
+	}
+
+	// ADIM 2: Oturumları kapat
+	if err := uc.revokeSessions(ctx, user.ID); err != nil {
+		return err
+	}
+
+	// ADIM 3: Kararı kaydet ve bildir
+	uc.audit.Log(ctx, AuditEvent{Action: "user.force_logout", ActorID: actorID, TargetID: user.ID})
+	uc.publish(ctx, "user.force_logout", actorID, user)
+
+	return nil
+}
+
+// revokeSessions - Kullanıcının refresh token'larını iptal eder, token version'ını artırır ve
+// hâlâ geçerli access token'ı olabilecek oturumları blacklist'e alır
+// Access token'ın jti'si saklanmaz ama "sid" claim'i refresh token kaydının ID'sidir.
+// Rotation'da eski kayıt iptal edilse de access token'ı yaşıyor olabilir; bu yüzden
+// son accessTokenTTL içinde oluşturulan tüm kayıtlar (iptal edilmişler dahil) alınır.
+func (uc *AdminUseCase) revokeSessions(ctx context.Context, userID uuid.UUID) error {
+	// ADIM 1: Canlı access token'ı olabilecek oturumları bul
+	tokens, err := uc.refreshTokens.GetIssuedSince(ctx, userID, time.Now().Add(-uc.accessTokenTTL))
+	if err != nil {
+		return err
+	}
+
+	// ADIM 2: Yeni access token alınamasın
+	if err := uc.refreshTokens.RevokeAllByUserID(ctx, userID); err != nil {
+		return err
+	}
+
+	// ADIM 3: Mevcut access token'lar da hemen reddedilsin
+	// Token version artınca daha önce verilen tüm access token'lar reddedilir (AuthMiddleware);
+	// "tv" kontrol etmeyen doğrulayıcılar için oturumlar ayrıca blacklist'e alınır.
+	// TTL = oturumun son access token'ının kalan ömrü
+	if _, err := uc.userRepo.IncrementTokenVersion(ctx, userID); err != nil {
+		return err
+	}
+	for _, token := range tokens {
+		ttl := time.Until(token.CreatedAt.Add(uc.accessTokenTTL))
+		if ttl <= 0 {
+			continue
+		}
+		if err := uc.tokenBlacklist.Add(ctx, token.ID.String(), ttl); err != nil {
+			return err
+		}
+	}
+	return nil
+}
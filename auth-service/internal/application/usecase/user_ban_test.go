@@ -0,0 +1,164 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"auth-service/internal/application/dto"
+	"auth-service/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+func newBanTestUseCases(t *testing.T) (*AuthUseCase, *AdminUseCase, *testDeps, *fakeAuditLogger) {
+	t.Helper()
+	uc, deps := newTestUseCase(t)
+	audit := &fakeAuditLogger{}
+	admin := NewAdminUseCase(deps.users, nil, nil, audit, nil,
+		WithSessionRevocation(deps.refreshTokens, deps.blacklist, 15*time.Minute))
+	return uc, admin, deps, audit
+}
+
+func TestBanRevokesSessionsAndAccessTokens(t *testing.T) {
+	uc, admin, deps, audit := newBanTestUseCases(t)
+	user := seedUser(t, uc, deps, &domain.User{Email: "jane@example.com", Username: "jane", IsVerified: true}, "correct-horse")
+	login := &dto.LoginRequest{EmailOrUsername: "jane", Password: "correct-horse"}
+
+	// Two devices; the first one has refreshed, so its old access token
+	// belongs to an already-rotated (revoked) session
+	first, err := uc.Login(context.Background(), login)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rotated, err := uc.RefreshToken(context.Background(), first.RefreshToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := uc.Login(context.Background(), login)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	actor := seedAdmin(t, deps.users, uuid.Nil)
+	if err := admin.SetUserActive(context.Background(), actor, user.ID, false); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, token := range []string{first.AccessToken, rotated.AccessToken, second.AccessToken} {
+		resp, err := uc.IntrospectToken(context.Background(), token)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.Active {
+			t.Error("access token of a banned user is still active")
+		}
+	}
+	for _, token := range []string{rotated.RefreshToken, second.RefreshToken} {
+		if _, err := uc.RefreshToken(context.Background(), token); err == nil {
+			t.Error("banned user refreshed a session")
+		}
+	}
+	if _, err := uc.Login(context.Background(), login); err != ErrUserInactive {
+		t.Errorf("login while banned: err = %v, want ErrUserInactive", err)
+	}
+	if len(audit.events) != 1 || audit.events[0].Action != "user.banned" {
+		t.Errorf("audit events = %+v", audit.events)
+	}
+
+	// Banning again is a no-op
+	if err := admin.SetUserActive(context.Background(), actor, user.ID, false); err != nil || len(audit.events) != 1 {
+		t.Errorf("second ban: err = %v, audit events = %+v", err, audit.events)
+	}
+
+	if err := admin.SetUserActive(context.Background(), actor, user.ID, true); err != nil {
+		t.Fatal(err)
+	}
+	again, err := uc.Login(context.Background(), login)
+	if err != nil {
+		t.Fatalf("login after unban: %v", err)
+	}
+	if resp, err := uc.IntrospectToken(context.Background(), again.AccessToken); err != nil || !resp.Active {
+		t.Errorf("new access token after unban: active = %v, err = %v", resp != nil && resp.Active, err)
+	}
+	if got := audit.events[len(audit.events)-1].Action; got != "user.unbanned" {
+		t.Errorf("last audit action = %q", got)
+	}
+}
+
+func TestForceLogoutEndsSessionsButKeepsTheAccount(t *testing.T) {
+	uc, admin, deps, audit := newBanTestUseCases(t)
+	user := seedUser(t, uc, deps, &domain.User{Email: "jane@example.com", Username: "jane", IsVerified: true}, "correct-horse")
+	login := &dto.LoginRequest{EmailOrUsername: "jane", Password: "correct-horse"}
+
+	session, err := uc.Login(context.Background(), login)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// An admin of another tenant cannot end the user's sessions
+	if err := admin.ForceLogout(context.Background(), seedAdmin(t, deps.users, uuid.New()), user.ID); err != ErrUserNotFound {
+		t.Errorf("other organization: err = %v, want ErrUserNotFound", err)
+	}
+	if resp, err := uc.IntrospectToken(context.Background(), session.AccessToken); err != nil || !resp.Active {
+		t.Errorf("access token after another tenant's force logout: active = %v, err = %v", resp != nil && resp.Active, err)
+	}
+
+	actor := seedAdmin(t, deps.users, uuid.Nil)
+	if err := admin.ForceLogout(context.Background(), actor, user.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	if resp, err := uc.IntrospectToken(context.Background(), session.AccessToken); err != nil || resp.Active {
+		t.Errorf("access token after force logout: active = %v, err = %v", resp != nil && resp.Active, err)
+	}
+	if _, err := uc.RefreshToken(context.Background(), session.RefreshToken); err == nil {
+		t.Error("refreshed a session after force logout")
+	}
+	if !deps.users.users[user.ID].IsActive {
+		t.Error("force logout deactivated the account")
+	}
+	if got := deps.users.users[user.ID].TokenVersion; got != 1 {
+		t.Errorf("token version = %d, want 1", got)
+	}
+	if len(audit.events) != 1 || audit.events[0].Action != "user.force_logout" || audit.events[0].TargetID != user.ID {
+		t.Errorf("audit events = %+v", audit.events)
+	}
+
+	// The user can sign in again right away
+	if _, err := uc.Login(context.Background(), login); err != nil {
+		t.Errorf("login after force logout: %v", err)
+	}
+
+	if err := admin.ForceLogout(context.Background(), actor, uuid.New()); err != ErrUserNotFound {
+		t.Errorf("unknown user: err = %v, want ErrUserNotFound", err)
+	}
+}
+
+func TestSetUserActiveErrors(t *testing.T) {
+	uc, admin, deps, _ := newBanTestUseCases(t)
+	actor := seedAdmin(t, deps.users, uuid.Nil)
+	if err := admin.SetUserActive(context.Background(), actor, uuid.New(), false); err != ErrUserNotFound {
+		t.Errorf("unknown user: err = %v, want ErrUserNotFound", err)
+	}
+
+	// An admin of another tenant can neither ban nor unban the user
+	user := seedUser(t, uc, deps, &domain.User{Email: "jane@example.com", Username: "jane"}, "correct-horse")
+	foreign := seedAdmin(t, deps.users, uuid.New())
+	for _, active := range []bool{false, true} {
+		if err := admin.SetUserActive(context.Background(), foreign, user.ID, active); err != ErrUserNotFound {
+			t.Errorf("other organization, active=%v: err = %v, want ErrUserNotFound", active, err)
+		}
+	}
+	if !deps.users.users[user.ID].IsActive {
+		t.Error("user was banned by another tenant's admin")
+	}
+
+	unconfigured := NewAdminUseCase(deps.users, nil, nil, nil, nil)
+	if err := unconfigured.SetUserActive(context.Background(), actor, user.ID, false); err != errSessionRevocationNotConfigured {
+		t.Errorf("without session revocation: err = %v", err)
+	}
+	if !deps.users.users[user.ID].IsActive {
+		t.Error("user was deactivated although their sessions could not be revoked")
+	}
+}
@@ -0,0 +1,55 @@
+package usecase
+
+import (
+	"context"
+
+	"auth-service/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+// UserCache - Kullanıcıları ID ile kısa süre saklayan cache (Redis)
+// RefreshToken her refresh'te token sahibini Postgres'ten okumasın diye.
+// Cache'ten gelen kullanıcının PasswordHash'i boştur: şifre kontrolü yapan yerler
+// kullanıcıyı her zaman repository'den okur.
+// Kullanıcı değişince kaydı silmek cache'i dolduranın değil, user repository'nin işidir
+// (usercache.NewInvalidatingUserRepository): Update, ban, şifre değişikliği hepsi oradan geçer.
+type UserCache interface {
+	// Get - Cache'teki kullanıcı; ok false ise cache miss
+	Get(ctx context.Context, id uuid.UUID) (user *domain.User, ok bool, err error)
+	// Set - Veritabanından okunan kullanıcıyı cache'e yazar
+	Set(ctx context.Context, user *domain.User) error
+}
+
+// nopUserCache - UserCache verilmediğinde kullanılan boş implementasyon (her okuma DB'ye gider)
+type nopUserCache struct{}
+
+func (nopUserCache) Get(ctx context.Context, id uuid.UUID) (*domain.User, bool, error) {
+	return nil, false, nil
+}
+
+func (nopUserCache) Set(ctx context.Context, user *domain.User) error { return nil }
+
+// WithUserCache - RefreshToken'ın token sahibini okuduğu cache
+// Verilmezse her refresh kullanıcıyı veritabanından okur.
+func WithUserCache(cache UserCache) AuthUseCaseOption {
+	return func(uc *AuthUseCase) {
+		uc.userCache = cache
+	}
+}
+
+// cachedUser - Kullanıcıyı önce cache'ten, yoksa veritabanından okuyup cache'e yazar
+// Veritabanı esas kaynak: cache hataları sadece bir sorguya mal olur.
+func (uc *AuthUseCase) cachedUser(ctx context.Context, id uuid.UUID) (*domain.User, error) {
+	if user, ok, err := uc.userCache.Get(ctx, id); err == nil && ok {
+		return user, nil
+	}
+	user, err := uc.userRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.userCache.Set(ctx, user); err != nil {
+		uc.logError(ctx, "cache user", err, "user_id", id)
+	}
+	return user, nil
+}
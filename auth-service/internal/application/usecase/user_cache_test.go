@@ -0,0 +1,74 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"auth-service/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+// fakeUserCache keeps users in memory and counts lookups
+type fakeUserCache struct {
+	users        map[uuid.UUID]domain.User
+	hits, misses int
+	err          error // Returned by every call, like Redis being down
+}
+
+func (c *fakeUserCache) Get(ctx context.Context, id uuid.UUID) (*domain.User, bool, error) {
+	if c.err != nil {
+		return nil, false, c.err
+	}
+	user, ok := c.users[id]
+	if !ok {
+		c.misses++
+		return nil, false, nil
+	}
+	c.hits++
+	return &user, true, nil
+}
+
+func (c *fakeUserCache) Set(ctx context.Context, user *domain.User) error {
+	if c.err != nil {
+		return c.err
+	}
+	c.users[user.ID] = *user
+	return nil
+}
+
+func TestRefreshTokenReadsUserThroughCache(t *testing.T) {
+	cache := &fakeUserCache{users: map[uuid.UUID]domain.User{}}
+	uc, deps := newTestUseCaseWithConfig(t, testSecurityConfig(), WithUserCache(cache))
+	user := seedUser(t, uc, deps, &domain.User{Email: "jane@example.com", Username: "jane", IsVerified: true}, "correct-horse")
+
+	tokens := loginTokens(t, uc)
+	for i := 0; i < 3; i++ {
+		var err error
+		if tokens, err = uc.RefreshToken(context.Background(), tokens.RefreshToken); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if cache.misses != 1 || cache.hits != 2 {
+		t.Errorf("hits = %d, misses = %d; want 2, 1", cache.hits, cache.misses)
+	}
+
+	// The cached user decides: an entry for a banned user refuses the refresh
+	banned := cache.users[user.ID]
+	banned.IsActive = false
+	cache.users[user.ID] = banned
+	if _, err := uc.RefreshToken(context.Background(), tokens.RefreshToken); err != ErrUserInactive {
+		t.Errorf("err = %v, want ErrUserInactive", err)
+	}
+}
+
+func TestRefreshTokenWorksWhenUserCacheFails(t *testing.T) {
+	cache := &fakeUserCache{users: map[uuid.UUID]domain.User{}, err: errors.New("cache down")}
+	uc, deps := newTestUseCaseWithConfig(t, testSecurityConfig(), WithUserCache(cache))
+	seedUser(t, uc, deps, &domain.User{Email: "jane@example.com", Username: "jane", IsVerified: true}, "correct-horse")
+
+	if _, err := uc.RefreshToken(context.Background(), loginTokens(t, uc).RefreshToken); err != nil {
+		t.Fatalf("refresh with a broken cache: %v", err)
+	}
+}
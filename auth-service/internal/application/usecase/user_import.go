@@ -0,0 +1,100 @@
+package usecase
+
+import (
+	"context"
+	"strings"
+
+	"auth-service/internal/application/dto"
+	"auth-service/internal/domain"
+	"auth-service/pkg/security"
+
+	"github.com/google/uuid"
+)
+
+// ImportUsers - Başka bir sistemden taşınan kullanıcıları mevcut şifre hash'leriyle oluşturur
+// Kullanıcılar admin'in organizasyonuna eklenir. Hash'in algoritması (bcrypt/Argon2id) hash'ten
+// tespit edilir; Login zaten ikisini de doğrular ve ilk başarılı login'de güncel ayarlarla yeniden hash'ler.
+// Her giriş için ayrı sonuç döner; email'i veya username'i alınmış olanlar atlanır,
+// kalanlar tek transaction'da (atomik) oluşturulur.
+func (uc *AdminUseCase) ImportUsers(ctx context.Context, actorID uuid.UUID, users []dto.ImportUser) (*dto.ImportUsersResponse, error) {
+	resp := &dto.ImportUsersResponse{Results: make([]dto.ImportUserResult, len(users))}
+
+	// ADIM 1: Admin'in organizasyonu (email ve username organizasyon içinde benzersiz)
+	// Admin okunamazsa import durur: aksi halde kullanıcılar uuid.Nil organizasyonuna eklenirdi
+	orgID, err := uc.actorOrganization(ctx, actorID)
+	if err != nil {
+		return nil, err
+	}
+
+	// ADIM 2: Her girişi kontrol et
+	// Aynı email/username batch içinde iki kez gelirse ilki oluşturulur, ikincisi duplicate olur
+	var toCreate []*domain.User
+	seenEmails := make(map[string]bool)
+	seenUsernames := make(map[string]bool)
+	for i, entry := range users {
+		result := &resp.Results[i]
+		email := domain.NormalizeEmail(entry.Email)
+		result.Email, result.Username = email, entry.Username
+
+		// Login'in doğrulayamayacağı hash'ler alınmaz
+		algorithm := security.HashAlgorithm(entry.PasswordHash)
+		if algorithm == "" || (entry.Algorithm != "" && entry.Algorithm != algorithm) {
+			result.Status = dto.ImportStatusInvalidHash
+			continue
+		}
+
+		// Username karşılaştırması ExistsByUsername gibi büyük/küçük harf duyarsız
+		usernameKey := strings.ToLower(entry.Username)
+		duplicate := seenEmails[email] || seenUsernames[usernameKey]
+		if !duplicate {
+			emailTaken, err := uc.userRepo.ExistsByEmail(ctx, orgID, email)
+			if err != nil {
+				return nil, err
+			}
+			usernameTaken, err := uc.userRepo.ExistsByUsername(ctx, orgID, entry.Username)
+			if err != nil {
+				return nil, err
+			}
+			duplicate = emailTaken || usernameTaken
+		}
+		if duplicate {
+			result.Status = dto.ImportStatusDuplicate
+			continue
+		}
+		seenEmails[email], seenUsernames[usernameKey] = true, true
+
+		user := &domain.User{
+			ID:             uuid.New(),
+			OrganizationID: orgID,
+			Email:          email,
+			Username:       entry.Username,
+			PasswordHash:   entry.PasswordHash,
+			IsActive:       true,
+			IsVerified:     entry.IsVerified,
+			Status:         domain.UserStatusActive,
+			Role:           domain.RoleUser,
+		}
+		toCreate = append(toCreate, user)
+		result.UserID = user.ID.String()
+		result.Status = dto.ImportStatusCreated
+	}
+
+	// ADIM 3: Hepsini tek transaction'da oluştur (hata olursa hiçbiri oluşturulmaz)
+	if err := uc.userRepo.BulkCreate(ctx, toCreate); err != nil {
+		return nil, err
+	}
+
+	// ADIM 4: Her oluşturulan kullanıcı için audit kaydı
+	for _, user := range toCreate {
+		uc.audit.Log(ctx, AuditEvent{
+			Action:   "user.imported",
+			ActorID:  actorID,
+			TargetID: user.ID,
+			Details:  map[string]string{"email": user.Email, "source": "admin_import"},
+		})
+	}
+	resp.Created = len(toCreate)
+	resp.Skipped = len(users) - len(toCreate)
+
+	return resp, nil
+}
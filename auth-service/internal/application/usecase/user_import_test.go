@@ -0,0 +1,84 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"auth-service/internal/application/dto"
+	"auth-service/internal/domain"
+	"auth-service/pkg/security"
+
+	"github.com/google/uuid"
+)
+
+func TestImportUsers(t *testing.T) {
+	uc, deps := newTestUseCase(t)
+	audit := &fakeAuditLogger{}
+	admin := NewAdminUseCase(deps.users, nil, nil, audit, nil)
+	seedUser(t, uc, deps, &domain.User{Email: "jane@example.com", Username: "jane"}, "correct-horse")
+
+	bcryptHash, _ := security.NewBcryptHasher(4).Hash("old-system-password")
+	argon2Hash, _ := security.NewArgon2idHasher(security.Argon2Params{Memory: 64, Time: 1, Parallelism: 1}).Hash("old-system-password")
+
+	resp, err := admin.ImportUsers(context.Background(), seedAdmin(t, deps.users, uuid.Nil), []dto.ImportUser{
+		{Email: "John@Example.com", Username: "john", PasswordHash: bcryptHash, IsVerified: true},
+		{Email: "mary@example.com", Username: "mary", PasswordHash: argon2Hash, Algorithm: "argon2id"},
+		{Email: "JANE@example.com", Username: "jane2", PasswordHash: bcryptHash},      // email taken
+		{Email: "other@example.com", Username: "JOHN", PasswordHash: bcryptHash},      // username earlier in the batch
+		{Email: "md5@example.com", Username: "md5", PasswordHash: "5f4dcc3b5aa765d6"}, // unsupported hash
+		{Email: "wrong@example.com", Username: "wrong", PasswordHash: bcryptHash, Algorithm: "argon2id"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		dto.ImportStatusCreated, dto.ImportStatusCreated, dto.ImportStatusDuplicate,
+		dto.ImportStatusDuplicate, dto.ImportStatusInvalidHash, dto.ImportStatusInvalidHash,
+	}
+	for i, result := range resp.Results {
+		if result.Status != want[i] {
+			t.Errorf("result %d (%s) = %q, want %q", i, result.Email, result.Status, want[i])
+		}
+		if (result.UserID != "") != (want[i] == dto.ImportStatusCreated) {
+			t.Errorf("result %d user ID = %q", i, result.UserID)
+		}
+	}
+	if resp.Created != 2 || resp.Skipped != 4 {
+		t.Errorf("created = %d, skipped = %d; want 2, 4", resp.Created, resp.Skipped)
+	}
+	if len(audit.events) != 2 || audit.events[0].Action != "user.imported" {
+		t.Errorf("audit events = %+v", audit.events)
+	}
+
+	// Imported users sign in with their old passwords
+	for _, login := range []string{"john@example.com", "mary"} {
+		resp, err := uc.Login(context.Background(), &dto.LoginRequest{EmailOrUsername: login, Password: "old-system-password"})
+		if err != nil {
+			t.Errorf("login as %s: %v", login, err)
+			continue
+		}
+		if login == "john@example.com" && !resp.User.IsVerified {
+			t.Error("is_verified was not imported")
+		}
+	}
+}
+
+func TestImportUsersFailsWhenActorCannotBeLoaded(t *testing.T) {
+	_, deps := newTestUseCase(t)
+	admin := NewAdminUseCase(deps.users, nil, nil, nil, nil)
+	hash, _ := security.NewBcryptHasher(4).Hash("old-system-password")
+
+	dbDown := errors.New("database is down")
+	deps.users.lookupErr = dbDown
+	_, err := admin.ImportUsers(context.Background(), uuid.New(), []dto.ImportUser{
+		{Email: "john@example.com", Username: "john", PasswordHash: hash},
+	})
+	if !errors.Is(err, dbDown) {
+		t.Fatalf("got %v, want %v", err, dbDown)
+	}
+	if len(deps.users.users) != 0 {
+		t.Error("users must not be imported without the admin's organization")
+	}
+}
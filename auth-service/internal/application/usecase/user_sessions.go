@@ -0,0 +1,38 @@
+package usecase
+
+import (
+	"context"
+
+	"auth-service/internal/application/dto"
+
+	"github.com/google/uuid"
+)
+
+// ListUserSessions - Admin için bir kullanıcının oturumlarını cihaz bilgisiyle listeler (kötüye kullanım incelemesi)
+// ListSessions ile aynı dönüşümü kullanır; sahiplik yerine hedefin admin'in (actorID) organizasyonunda olması aranır.
+// includeRevoked true ise iptal edilmiş ve süresi dolmuş (henüz temizlenmemiş) oturumlar da döner,
+// böylece bir hesabın oturum geçmişi zaman çizelgesi olarak incelenebilir.
+func (uc *AdminUseCase) ListUserSessions(ctx context.Context, actorID, userID uuid.UUID, includeRevoked bool) ([]dto.SessionInfo, error) {
+	if uc.refreshTokens == nil {
+		return nil, errSessionRevocationNotConfigured
+	}
+
+	// ADIM 1: Kullanıcıyı bul (başka organizasyonun kullanıcısı bulunamaz)
+	user, err := uc.orgUser(ctx, actorID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// ADIM 2: Oturumları getir
+	getTokens := uc.refreshTokens.GetByUserID
+	if includeRevoked {
+		getTokens = uc.refreshTokens.GetByUserIDIncludingRevoked
+	}
+	tokens, err := getTokens(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// ADIM 3: DTO'ya çevir; admin'in kendi oturumu bu listede olmadığı için "current" yok
+	return sessionInfos(tokens, uuid.Nil, includeRevoked), nil
+}
@@ -0,0 +1,61 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+
+	"auth-service/internal/application/dto"
+	"auth-service/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+func TestListUserSessions(t *testing.T) {
+	uc, admin, deps, _ := newBanTestUseCases(t)
+	ctx := context.Background()
+	user := seedUser(t, uc, deps, &domain.User{Email: "jane@example.com", Username: "jane", IsVerified: true}, "correct-horse")
+	login := &dto.LoginRequest{EmailOrUsername: "jane", Password: "correct-horse"}
+
+	first, err := uc.Login(ctx, login)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := uc.Login(ctx, login); err != nil {
+		t.Fatal(err)
+	}
+	if err := uc.LogoutSession(ctx, user.ID, first.RefreshToken); err != nil {
+		t.Fatal(err)
+	}
+
+	actor := seedAdmin(t, deps.users, uuid.Nil)
+	active, err := admin.ListUserSessions(ctx, actor, user.ID, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(active) != 1 || active[0].Revoked {
+		t.Errorf("active sessions = %+v, want the one still signed in", active)
+	}
+
+	all, err := admin.ListUserSessions(ctx, actor, user.ID, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	revoked := 0
+	for _, s := range all {
+		if s.Revoked {
+			revoked++
+		}
+	}
+	if len(all) != 2 || revoked != 1 {
+		t.Errorf("sessions with include_revoked = %+v, want both, one revoked", all)
+	}
+
+	if _, err := admin.ListUserSessions(ctx, actor, uuid.New(), false); err != ErrUserNotFound {
+		t.Errorf("unknown user: err = %v, want ErrUserNotFound", err)
+	}
+
+	// An admin of another tenant sees neither the sessions nor that the user exists
+	if _, err := admin.ListUserSessions(ctx, seedAdmin(t, deps.users, uuid.New()), user.ID, true); err != ErrUserNotFound {
+		t.Errorf("other organization: err = %v, want ErrUserNotFound", err)
+	}
+}
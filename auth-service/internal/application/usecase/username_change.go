@@ -0,0 +1,69 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"auth-service/internal/application/dto"
+	"auth-service/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+// ChangeUsername - Giriş yapmış kullanıcının username'ini değiştirir ve güncel profili döner
+// Yeni username Register'daki kurallardan geçer ve organizasyonda benzersiz olmalı.
+// Access token'lar username claim'ini taşıdığı için değişiklikten sonra hepsi geçersiz olur;
+// oturumlar kapanmaz, refresh ile yeni username'li token alınır.
+func (uc *AuthUseCase) ChangeUsername(ctx context.Context, userID uuid.UUID, newUsername string) (_ *dto.UserInfo, err error) {
+	defer translateContextError(ctx, &err)
+
+	// ADIM 1: Kullanıcıyı bul
+	user, err := uc.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, notFoundAs(err, ErrUserNotFound)
+	}
+
+	// ADIM 2: Normalize et ve rezerve/karıştırılabilir username'leri reddet
+	username, err := uc.checkUsername(newUsername)
+	if err != nil {
+		return nil, err
+	}
+	if username == user.Username {
+		return toUserInfo(user), nil
+	}
+
+	// ADIM 3: Cooldown - username'ler sık değişirse mention/link'ler ve audit geçmişi takip edilemez
+	now := time.Now()
+	if cooldown := uc.securityCfg.UsernameChangeCooldown; cooldown > 0 && user.UsernameChangedAt != nil &&
+		now.Sub(*user.UsernameChangedAt) < cooldown {
+		return nil, ErrUsernameChangeTooSoon
+	}
+
+	// ADIM 4: Benzersizlik - sadece harf büyüklüğü değişiyorsa (jane -> Jane) eşleşen kayıt kullanıcının kendisidir
+	if domain.NormalizeUsername(username) != domain.NormalizeUsername(user.Username) {
+		exists, err := uc.userRepo.ExistsByUsername(ctx, user.OrganizationID, username)
+		if err != nil {
+			return nil, err
+		}
+		if exists {
+			return nil, ErrUsernameTaken
+		}
+	}
+
+	// ADIM 5: Kaydet
+	oldUsername := user.Username
+	user.Username = username
+	user.UsernameChangedAt = &now
+	if err := uc.userRepo.Update(ctx, user); err != nil {
+		return nil, err
+	}
+
+	uc.logAudit(ctx, AuditUsernameChanged, user.ID, map[string]string{"old_username": oldUsername, "new_username": username})
+	uc.publishUserEvent(ctx, EventUsernameChanged, user, map[string]interface{}{"old_username": oldUsername})
+
+	// ADIM 6: Eski username'i taşıyan access token'ları geçersiz kıl
+	if err := uc.invalidateAccessTokens(ctx, user.ID); err != nil {
+		return nil, err
+	}
+	return toUserInfo(user), nil
+}
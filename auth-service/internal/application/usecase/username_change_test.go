@@ -0,0 +1,106 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"auth-service/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+func TestChangeUsername(t *testing.T) {
+	audit := &fakeAuditLogger{}
+	uc, deps := newTestUseCaseWithConfig(t, testSecurityConfig(), WithAuditLogger(audit))
+	user := seedUser(t, uc, deps, &domain.User{Email: "jane@example.com", Username: "jane", IsVerified: true}, "correct-horse")
+	seedUser(t, uc, deps, &domain.User{Email: "john@example.com", Username: "john"}, "correct-horse")
+	tokens := loginTokens(t, uc)
+
+	if _, err := uc.ChangeUsername(context.Background(), user.ID, "JOHN"); err != ErrUsernameTaken {
+		t.Errorf("username of another user (other case): err = %v, want ErrUsernameTaken", err)
+	}
+	var notAllowed *UsernameNotAllowedError
+	if _, err := uc.ChangeUsername(context.Background(), user.ID, "admin"); !errors.As(err, &notAllowed) {
+		t.Errorf("reserved username: err = %v, want UsernameNotAllowedError", err)
+	}
+
+	profile, err := uc.ChangeUsername(context.Background(), user.ID, " janet ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if profile.Username != "janet" {
+		t.Errorf("profile username = %q, want janet", profile.Username)
+	}
+	stored, _ := deps.users.GetByID(context.Background(), user.ID)
+	if stored.Username != "janet" || stored.UsernameChangedAt == nil {
+		t.Errorf("stored user = %+v", stored)
+	}
+
+	// Access tokens carry the old username and must be refreshed
+	if resp, err := uc.IntrospectToken(context.Background(), tokens.AccessToken); err != nil || resp.Active {
+		t.Errorf("access token from before the change: active = %v, err = %v", resp != nil && resp.Active, err)
+	}
+	refreshed, err := uc.RefreshToken(context.Background(), tokens.RefreshToken)
+	if err != nil {
+		t.Fatalf("refresh after the change: %v", err)
+	}
+	if claims, err := uc.jwtService.ValidateToken(refreshed.AccessToken); err != nil || claims.Username != "janet" {
+		t.Errorf("refreshed token username = %v, %v", claims, err)
+	}
+
+	if last := audit.events[len(audit.events)-1]; last.Action != AuditUsernameChanged || last.Details["old_username"] != "jane" {
+		t.Errorf("last audit event = %+v", last)
+	}
+	if names := deps.events.names(); len(names) == 0 || names[len(names)-1] != EventUsernameChanged {
+		t.Errorf("events = %v", names)
+	}
+}
+
+func TestChangeUsernameCooldown(t *testing.T) {
+	cfg := testSecurityConfig()
+	cfg.UsernameChangeCooldown = 30 * 24 * time.Hour
+	uc, deps := newTestUseCaseWithConfig(t, cfg)
+	user := seedUser(t, uc, deps, &domain.User{Email: "jane@example.com", Username: "jane"}, "correct-horse")
+
+	// The username chosen at registration can be changed right away
+	if _, err := uc.ChangeUsername(context.Background(), user.ID, "janet"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := uc.ChangeUsername(context.Background(), user.ID, "jane"); err != ErrUsernameChangeTooSoon {
+		t.Errorf("second change: err = %v, want ErrUsernameChangeTooSoon", err)
+	}
+	// Submitting the current username is not a change
+	if _, err := uc.ChangeUsername(context.Background(), user.ID, "janet"); err != nil {
+		t.Errorf("unchanged username: err = %v", err)
+	}
+
+	stored, _ := deps.users.GetByID(context.Background(), user.ID)
+	changedAt := time.Now().Add(-cfg.UsernameChangeCooldown)
+	stored.UsernameChangedAt = &changedAt
+	if err := deps.users.Update(context.Background(), stored); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := uc.ChangeUsername(context.Background(), user.ID, "jane"); err != nil {
+		t.Errorf("change after the cooldown: err = %v", err)
+	}
+}
+
+func TestChangeUsernameCaseOnly(t *testing.T) {
+	uc, deps := newTestUseCase(t)
+	user := seedUser(t, uc, deps, &domain.User{Email: "jane@example.com", Username: "jane"}, "correct-horse")
+
+	// The only user holding "jane" is the caller
+	profile, err := uc.ChangeUsername(context.Background(), user.ID, "Jane")
+	if err != nil || profile.Username != "Jane" {
+		t.Errorf("ChangeUsername = %+v, %v", profile, err)
+	}
+}
+
+func TestChangeUsernameUnknownUser(t *testing.T) {
+	uc, _ := newTestUseCase(t)
+	if _, err := uc.ChangeUsername(context.Background(), uuid.New(), "janet"); err != ErrUserNotFound {
+		t.Errorf("err = %v, want ErrUserNotFound", err)
+	}
+}
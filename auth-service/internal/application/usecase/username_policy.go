@@ -0,0 +1,37 @@
+package usecase
+
+import (
+	"errors"
+
+	"auth-service/pkg/security"
+)
+
+// UsernameNotAllowedError - Username rezerve (örn: admin) veya başka bir username ile karıştırılabilir
+// errors.Is(err, ErrUsernameNotAllowed) true döner; Reason AppError() ile response'a yazılır.
+type UsernameNotAllowedError struct {
+	Reason  string // örn: security.UsernameReasonReserved
+	Message string // Kullanıcıya gösterilebilecek açıklama
+}
+
+func (e *UsernameNotAllowedError) Error() string {
+	return ErrUsernameNotAllowed.Error() + ": " + e.Reason
+}
+
+func (e *UsernameNotAllowedError) Unwrap() error { return ErrUsernameNotAllowed }
+
+// AppError - Sebep response detaylarına yazılır
+func (e *UsernameNotAllowedError) AppError() *AppError {
+	return ErrUsernameNotAllowed.WithDetails(e.Message, map[string]string{"reason": e.Reason})
+}
+
+// checkUsername - Yeni username'ler için kontrol noktası (Register, social login)
+// Normalize edilmiş (trim + NFKC) username'i döner; büyük/küçük harf korunur.
+// Sadece harf büyüklüğüyle ayrılan username'ler ExistsByUsername'de aynı sayılır.
+func (uc *AuthUseCase) checkUsername(username string) (string, error) {
+	normalized, err := uc.usernameValidator.Validate(username)
+	var rejected *security.UsernameError
+	if errors.As(err, &rejected) {
+		return "", &UsernameNotAllowedError{Reason: rejected.Reason, Message: rejected.Message}
+	}
+	return normalized, err
+}
@@ -0,0 +1,85 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"auth-service/internal/application/dto"
+	"auth-service/pkg/security"
+
+	"github.com/google/uuid"
+)
+
+func registerRequest(username string) *dto.RegisterRequest {
+	return &dto.RegisterRequest{
+		Email:     username + "@example.com",
+		Username:  username,
+		Password:  "correct-horse",
+		FirstName: "Jane",
+		LastName:  "Doe",
+	}
+}
+
+func TestRegisterRejectsDisallowedUsernames(t *testing.T) {
+	cfg := testSecurityConfig()
+	cfg.UsernameBlocklist = []string{"acme"}
+	uc, deps := newTestUseCaseWithConfig(t, cfg)
+
+	tests := []struct {
+		username string
+		reason   string
+	}{
+		{"Admin", security.UsernameReasonReserved},
+		{"sup_port", security.UsernameReasonReserved},
+		{"ACME", security.UsernameReasonReserved},
+		{"раураӏ", security.UsernameReasonConfusable},
+		{"pаypal", security.UsernameReasonMixedScript},
+	}
+	for _, tt := range tests {
+		t.Run(tt.username, func(t *testing.T) {
+			req := registerRequest(tt.username)
+			req.Email = "someone@example.com"
+			_, err := uc.Register(context.Background(), req)
+			var rejected *UsernameNotAllowedError
+			if !errors.As(err, &rejected) || !errors.Is(err, ErrUsernameNotAllowed) || rejected.Reason != tt.reason {
+				t.Fatalf("err = %v, want reason %q", err, tt.reason)
+			}
+			if exists, _ := deps.users.ExistsByEmail(context.Background(), uuid.Nil, req.Email); exists {
+				t.Error("user must not be created")
+			}
+		})
+	}
+}
+
+func TestRegisterNormalizesUsername(t *testing.T) {
+	uc, _ := newTestUseCase(t)
+
+	resp, err := uc.Register(context.Background(), registerRequest("  ＪａｎｅＤｏｅ "))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.User.Username != "JaneDoe" {
+		t.Errorf("username = %q, want the trimmed NFKC form with its case", resp.User.Username)
+	}
+
+	// Differs only by case: the same username
+	req := registerRequest("janedoe")
+	req.Email = "other@example.com"
+	if _, err := uc.Register(context.Background(), req); err != ErrUserAlreadyExists {
+		t.Errorf("case-only duplicate: err = %v, want ErrUserAlreadyExists", err)
+	}
+}
+
+func TestLoginWithOAuthSkipsReservedUsernames(t *testing.T) {
+	uc, _ := newTestUseCaseWithConfig(t, testSecurityConfig(), WithOAuthAccounts(&fakeOAuthAccountRepo{}))
+
+	resp, err := uc.LoginWithOAuth(context.Background(), "github",
+		ExternalUser{ProviderID: "42", Email: "admin@example.com", EmailVerified: true, Username: "admin"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.User.Username != "admin2" {
+		t.Errorf("username = %q, want admin2", resp.User.Username)
+	}
+}
@@ -0,0 +1,75 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Scopes an API key can be granted. A scope names one capability, so a key
+// only gets what its service needs.
+const (
+	// ScopeTokenIntrospect allows POST /api/auth/introspect and the
+	// ValidateToken RPC
+	ScopeTokenIntrospect = "token:introspect"
+	// ScopeTokenRevoke allows the Revoke RPC. It is separate from
+	// token:introspect so a service that only validates tokens cannot log
+	// users out.
+	ScopeTokenRevoke = "token:revoke"
+)
+
+// apiKeyScopes lists the scopes an API key can be granted. User scopes
+// (see ScopesForRole) are not among them: admin routes need a user's token.
+var apiKeyScopes = map[string]bool{
+	ScopeTokenIntrospect: true,
+	ScopeTokenRevoke:     true,
+}
+
+// IsValidScope reports whether scope can be granted to an API key
+func IsValidScope(scope string) bool {
+	return apiKeyScopes[scope]
+}
+
+// APIKey authenticates another service calling this one. Only the SHA-256
+// hash of the key is stored; the plaintext is shown once at creation.
+type APIKey struct {
+	ID   uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	Name string    `json:"name" gorm:"type:varchar(100);not null"`
+	// KeyHash is the hex SHA-256 of the key, used to look it up
+	KeyHash string `json:"-" gorm:"type:varchar(64);uniqueIndex;not null"`
+	// Prefix is the start of the key, so admins can tell keys apart
+	Prefix string   `json:"prefix" gorm:"type:varchar(16);not null"`
+	Scopes []string `json:"scopes" gorm:"type:jsonb;serializer:json"`
+	// OwnerID is the admin who created the key
+	OwnerID uuid.UUID `json:"owner_id" gorm:"type:uuid;index"`
+	// OrganizationID is the owner's tenant; only its admins see the key
+	OrganizationID uuid.UUID  `json:"organization_id" gorm:"type:uuid;index"`
+	ExpiresAt      *time.Time `json:"expires_at"`
+	RevokedAt      *time.Time `json:"revoked_at"`
+	CreatedAt      time.Time  `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// TableName specifies the table name for GORM
+func (APIKey) TableName() string {
+	return "api_keys"
+}
+
+// IsExpired checks if the key has an expiry that has passed
+func (k *APIKey) IsExpired() bool {
+	return k.ExpiresAt != nil && time.Now().After(*k.ExpiresAt)
+}
+
+// IsRevoked checks if the key was revoked
+func (k *APIKey) IsRevoked() bool {
+	return k.RevokedAt != nil
+}
+
+// HasScope checks if the key was granted scope
+func (k *APIKey) HasScope(scope string) bool {
+	for _, s := range k.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
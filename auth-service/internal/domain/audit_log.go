@@ -0,0 +1,39 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AuditLog is a stored security event: who did what to which account, from
+// where and when
+type AuditLog struct {
+	ID     uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	Action string    `json:"action" gorm:"type:varchar(64);not null;index"`
+	// ActorID is the user who acted and TargetID the affected user; both are
+	// the same user for the user's own actions, and uuid.Nil when unknown
+	// (e.g. a failed login for an account that does not exist)
+	ActorID   uuid.UUID         `json:"actor_id" gorm:"type:uuid;index"`
+	TargetID  uuid.UUID         `json:"target_id" gorm:"type:uuid;index"`
+	IPAddress string            `json:"ip_address" gorm:"type:varchar(45)"`
+	UserAgent string            `json:"user_agent" gorm:"type:varchar(512)"`
+	RequestID string            `json:"request_id" gorm:"type:varchar(64);index"`
+	Details   map[string]string `json:"details,omitempty" gorm:"type:jsonb;serializer:json"`
+	CreatedAt time.Time         `json:"created_at" gorm:"not null;index"`
+}
+
+// TableName specifies the table name for GORM
+func (AuditLog) TableName() string {
+	return "audit_logs"
+}
+
+// AuditLogFilter selects audit log entries; zero fields match everything
+type AuditLogFilter struct {
+	// UserID matches entries where the user is the actor or the target
+	UserID uuid.UUID
+	Action string
+	// OrganizationID matches entries whose actor or target belongs to the
+	// organization
+	OrganizationID uuid.UUID
+}
@@ -0,0 +1,32 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Credential is a WebAuthn public key credential (passkey) registered by a
+// user. Only the public key is stored; the private key never leaves the
+// authenticator.
+type Credential struct {
+	ID     uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	UserID uuid.UUID `json:"user_id" gorm:"type:uuid;not null;index"`
+	// CredentialID is the authenticator-chosen ID sent back on every login
+	CredentialID    []byte `json:"-" gorm:"not null;uniqueIndex"`
+	PublicKey       []byte `json:"-" gorm:"not null"`
+	AttestationType string `json:"-"`
+	// Transports is a comma-separated list such as "internal,hybrid"
+	Transports string `json:"-"`
+	AAGUID     []byte `json:"-"`
+	// SignCount is the authenticator's signature counter at the last login.
+	// A counter that does not increase may mean the credential was cloned.
+	SignCount  uint32     `json:"-" gorm:"not null;default:0"`
+	CreatedAt  time.Time  `json:"created_at" gorm:"autoCreateTime"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+}
+
+// TableName specifies the table name for GORM
+func (Credential) TableName() string {
+	return "webauthn_credentials"
+}
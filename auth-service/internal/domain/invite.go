@@ -0,0 +1,33 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Invite is a single-use token an admin issues so one person can register
+// while registration is invite-only. The new user joins the invite's
+// organization and records the admin as their inviter. Only a SHA-256 hash
+// of the token is stored.
+type Invite struct {
+	ID             uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	OrganizationID uuid.UUID `json:"organization_id" gorm:"type:uuid;not null;index"`
+	InvitedBy      uuid.UUID `json:"invited_by" gorm:"type:uuid;not null"`
+	// Email, if set, is the only address that can register with the invite
+	Email     string     `json:"email,omitempty"`
+	TokenHash string     `json:"-" gorm:"uniqueIndex;not null"`
+	ExpiresAt time.Time  `json:"expires_at" gorm:"not null"`
+	UsedAt    *time.Time `json:"used_at"`
+	CreatedAt time.Time  `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// TableName specifies the table name for GORM
+func (Invite) TableName() string {
+	return "invites"
+}
+
+// IsExpired checks if the invite is expired
+func (i *Invite) IsExpired() bool {
+	return time.Now().After(i.ExpiresAt)
+}
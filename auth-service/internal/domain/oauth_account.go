@@ -0,0 +1,23 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OAuthAccount links an account at an external identity provider (e.g.
+// Google) to a user. A user may have several linked providers.
+type OAuthAccount struct {
+	ID             uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	UserID         uuid.UUID `json:"user_id" gorm:"type:uuid;not null;index"`
+	Provider       string    `json:"provider" gorm:"type:varchar(32);not null;uniqueIndex:idx_oauth_accounts_provider_user"`
+	ProviderUserID string    `json:"provider_user_id" gorm:"not null;uniqueIndex:idx_oauth_accounts_provider_user"`
+	Email          string    `json:"email"`
+	CreatedAt      time.Time `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// TableName specifies the table name for GORM
+func (OAuthAccount) TableName() string {
+	return "oauth_accounts"
+}
@@ -0,0 +1,25 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DefaultOrganizationSlug is the organization of users that registered
+// without naming one, including every user from before multi-tenancy
+const DefaultOrganizationSlug = "default"
+
+// Organization is a tenant. Emails and usernames are unique per organization,
+// so the same address can have an account in several organizations.
+type Organization struct {
+	ID        uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	Name      string    `json:"name" gorm:"type:varchar(100);not null"`
+	Slug      string    `json:"slug" gorm:"type:varchar(64);uniqueIndex;not null"`
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// TableName specifies the table name for GORM
+func (Organization) TableName() string {
+	return "organizations"
+}
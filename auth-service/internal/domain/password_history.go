@@ -0,0 +1,21 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PasswordHistory is the hash of a password a user has set, kept so the
+// user cannot switch back to one of their recent passwords
+type PasswordHistory struct {
+	ID           uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	UserID       uuid.UUID `json:"user_id" gorm:"type:uuid;not null;index:idx_password_history_user_created,priority:1"`
+	PasswordHash string    `json:"-" gorm:"not null"`
+	CreatedAt    time.Time `json:"created_at" gorm:"autoCreateTime;index:idx_password_history_user_created,priority:2"`
+}
+
+// TableName specifies the table name for GORM
+func (PasswordHistory) TableName() string {
+	return "password_history"
+}
@@ -2,29 +2,210 @@ package domain
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	"github.com/google/uuid"
 )
 
-// UserRepository defines the interface for user data operations
+// ErrNotFound is returned by repository lookups that match no row. Any other
+// error from a repository is a storage failure, not a miss.
+var ErrNotFound = errors.New("record not found")
+
+// UserRepository defines the interface for user data operations. Lookups
+// skip soft-deleted users (see User.DeletedAt). Emails and usernames are
+// unique per organization, so lookups by them take the organization ID.
 type UserRepository interface {
 	Create(ctx context.Context, user *User) error
 	GetByID(ctx context.Context, id uuid.UUID) (*User, error)
-	GetByEmail(ctx context.Context, email string) (*User, error)
-	GetByUsername(ctx context.Context, username string) (*User, error)
+	GetByEmail(ctx context.Context, orgID uuid.UUID, email string) (*User, error)
+	GetByUsername(ctx context.Context, orgID uuid.UUID, username string) (*User, error)
+	// GetByEmailOrUsername finds the user whose email or username is
+	// identifier in a single query; an email match takes precedence
+	GetByEmailOrUsername(ctx context.Context, orgID uuid.UUID, identifier string) (*User, error)
+	// GetByPhone finds the user with the E.164 phone number (see NormalizePhone)
+	GetByPhone(ctx context.Context, orgID uuid.UUID, phone string) (*User, error)
+	// BulkCreate inserts users in batches within one transaction; either
+	// every user is created or none is
+	BulkCreate(ctx context.Context, users []*User) error
 	Update(ctx context.Context, user *User) error
+	// Delete removes the user row permanently; accounts deleted by their
+	// owner are soft-deleted through Update instead
 	Delete(ctx context.Context, id uuid.UUID) error
-	ExistsByEmail(ctx context.Context, email string) (bool, error)
-	ExistsByUsername(ctx context.Context, username string) (bool, error)
+	ExistsByEmail(ctx context.Context, orgID uuid.UUID, email string) (bool, error)
+	// ExistsByUsername ignores case: "Jane" is taken once "jane" exists
+	ExistsByUsername(ctx context.Context, orgID uuid.UUID, username string) (bool, error)
 	UpdateLastLogin(ctx context.Context, id uuid.UUID) error
+	// RecordFailedLogin atomically increments the failed login counter and
+	// returns the new value
+	RecordFailedLogin(ctx context.Context, id uuid.UUID) (int, error)
+	// SetLockout sets LockedUntil and resets the failed login counter; a nil
+	// until clears the lockout
+	SetLockout(ctx context.Context, id uuid.UUID, until *time.Time) error
+	// MarkLockoutNotified sets LockoutNotifiedAt to at unless the user was
+	// already notified after since; claimed is false when another lockout
+	// email was sent in the meantime
+	MarkLockoutNotified(ctx context.Context, id uuid.UUID, at, since time.Time) (claimed bool, err error)
+	// MarkVerificationSent sets VerificationSentAt to at unless a verification
+	// email was already sent after since; claimed is false within the cooldown
+	MarkVerificationSent(ctx context.Context, id uuid.UUID, at, since time.Time) (claimed bool, err error)
+	// GetTokenVersion returns the user's current TokenVersion
+	GetTokenVersion(ctx context.Context, id uuid.UUID) (int, error)
+	// IncrementTokenVersion atomically increments the user's TokenVersion and
+	// returns the new value
+	IncrementTokenVersion(ctx context.Context, id uuid.UUID) (int, error)
+	// MarkVerified marks all given users as email-verified in one batch;
+	// either every user is updated or none is
+	MarkVerified(ctx context.Context, ids []uuid.UUID) error
+	// List returns the filter's page of matching users, oldest first, and the
+	// total number of matches
+	List(ctx context.Context, filter UserFilter) ([]*User, int64, error)
+}
+
+// OrganizationRepository defines the interface for organization (tenant) operations
+type OrganizationRepository interface {
+	Create(ctx context.Context, org *Organization) error
+	GetByID(ctx context.Context, id uuid.UUID) (*Organization, error)
+	GetBySlug(ctx context.Context, slug string) (*Organization, error)
 }
 
 // RefreshTokenRepository defines the interface for refresh token operations
 type RefreshTokenRepository interface {
 	Create(ctx context.Context, token *RefreshToken) error
-	GetByToken(ctx context.Context, token string) (*RefreshToken, error)
+	GetByTokenHash(ctx context.Context, tokenHash string) (*RefreshToken, error)
+	// GetByTokenHashIncludingRevoked also returns revoked tokens, so a
+	// replayed rotated token can be told apart from an unknown one
+	GetByTokenHashIncludingRevoked(ctx context.Context, tokenHash string) (*RefreshToken, error)
 	GetByUserID(ctx context.Context, userID uuid.UUID) ([]*RefreshToken, error)
-	Revoke(ctx context.Context, token string) error
+	// GetByUserIDIncludingRevoked returns all of the user's tokens that have
+	// not been cleaned up yet, revoked and expired ones included
+	GetByUserIDIncludingRevoked(ctx context.Context, userID uuid.UUID) ([]*RefreshToken, error)
+	// ActiveCount returns how many of the user's tokens are neither revoked
+	// nor expired, i.e. the number of signed-in sessions
+	ActiveCount(ctx context.Context, userID uuid.UUID) (int64, error)
+	// RevokeOldest revokes the user's active tokens except the keep most
+	// recently created ones and returns how many it revoked
+	RevokeOldest(ctx context.Context, userID uuid.UUID, keep int) (int64, error)
+	// GetIssuedSince returns the user's tokens created after since, revoked
+	// ones included; their access tokens ("sid") may still be valid
+	GetIssuedSince(ctx context.Context, userID uuid.UUID, since time.Time) ([]*RefreshToken, error)
+	Revoke(ctx context.Context, tokenHash string) error
 	RevokeAllByUserID(ctx context.Context, userID uuid.UUID) error
-	DeleteExpired(ctx context.Context) error
+	// RevokeAllByUserIDExcept revokes every session of the user but keepID
+	RevokeAllByUserIDExcept(ctx context.Context, userID, keepID uuid.UUID) error
+	// RevokeFamily revokes every token rotated from the same login
+	RevokeFamily(ctx context.Context, familyID uuid.UUID) error
+	// Touch records that the token was just used
+	Touch(ctx context.Context, tokenHash string) error
+	// HasSeenDevice reports whether any token of the user, revoked ones
+	// included, was issued to the device fingerprint
+	HasSeenDevice(ctx context.Context, userID uuid.UUID, fingerprint string) (bool, error)
+	// DeleteExpired deletes expired tokens and, unless revokedBefore is zero,
+	// revoked tokens created before it. It returns the number of deleted rows.
+	DeleteExpired(ctx context.Context, revokedBefore time.Time) (int64, error)
+}
+
+// PasswordResetTokenRepository defines the interface for password reset token operations
+type PasswordResetTokenRepository interface {
+	Create(ctx context.Context, token *PasswordResetToken) error
+	GetByTokenHash(ctx context.Context, tokenHash string) (*PasswordResetToken, error)
+	// Consume atomically marks an unused, unexpired token as used and returns it.
+	// Concurrent calls for the same token succeed at most once; the others get
+	// ErrNotFound.
+	Consume(ctx context.Context, tokenHash string) (*PasswordResetToken, error)
+	DeleteByUserID(ctx context.Context, userID uuid.UUID) error
+}
+
+// PasswordHistoryRepository defines the interface for password history operations
+type PasswordHistoryRepository interface {
+	Create(ctx context.Context, entry *PasswordHistory) error
+	// ListRecent returns the user's limit newest entries, newest first
+	ListRecent(ctx context.Context, userID uuid.UUID, limit int) ([]*PasswordHistory, error)
+	// Prune deletes all but the user's keep newest entries
+	Prune(ctx context.Context, userID uuid.UUID, keep int) error
+}
+
+// TokenBlacklist stores revoked access token IDs (the JWT "jti" claim) and
+// revoked session IDs (the "sid" claim) until the tokens would have expired on
+// their own
+type TokenBlacklist interface {
+	Add(ctx context.Context, tokenID string, ttl time.Duration) error
+	Contains(ctx context.Context, tokenID string) (bool, error)
+}
+
+// OAuthAccountRepository defines the interface for linked external identity operations
+type OAuthAccountRepository interface {
+	Create(ctx context.Context, account *OAuthAccount) error
+	GetByProviderUserID(ctx context.Context, provider, providerUserID string) (*OAuthAccount, error)
+	// GetByUserID returns the user's linked accounts, oldest first
+	GetByUserID(ctx context.Context, userID uuid.UUID) ([]*OAuthAccount, error)
+	// Delete unlinks the user's account at provider; ErrNotFound if none is linked
+	Delete(ctx context.Context, userID uuid.UUID, provider string) error
+}
+
+// CredentialRepository defines the interface for WebAuthn credential (passkey) operations
+type CredentialRepository interface {
+	Create(ctx context.Context, credential *Credential) error
+	GetByUserID(ctx context.Context, userID uuid.UUID) ([]*Credential, error)
+	GetByCredentialID(ctx context.Context, credentialID []byte) (*Credential, error)
+	// UpdateSignCount stores the signature counter of a login and marks the
+	// credential as just used
+	UpdateSignCount(ctx context.Context, id uuid.UUID, signCount uint32) error
+}
+
+// AuditLogRepository defines the interface for audit log operations
+type AuditLogRepository interface {
+	Create(ctx context.Context, log *AuditLog) error
+	// List returns one page (1-based) of matching entries, newest first, and
+	// the total number of matches
+	List(ctx context.Context, filter AuditLogFilter, page, pageSize int) ([]*AuditLog, int64, error)
+}
+
+// VerificationTokenRepository defines the interface for email verification token operations
+type VerificationTokenRepository interface {
+	Create(ctx context.Context, token *VerificationToken) error
+	// Consume atomically marks an unused, unexpired token as used and returns it
+	Consume(ctx context.Context, tokenHash string) (*VerificationToken, error)
+	DeleteByUserID(ctx context.Context, userID uuid.UUID) error
+}
+
+// MagicLinkTokenRepository defines the interface for passwordless login token operations
+type MagicLinkTokenRepository interface {
+	Create(ctx context.Context, token *MagicLinkToken) error
+	// Consume atomically marks an unused, unexpired token as used and returns it
+	Consume(ctx context.Context, tokenHash string) (*MagicLinkToken, error)
+	DeleteByUserID(ctx context.Context, userID uuid.UUID) error
+}
+
+// InviteRepository defines the interface for registration invite operations
+type InviteRepository interface {
+	Create(ctx context.Context, invite *Invite) error
+	// GetByTokenHash also returns used and expired invites; callers check them
+	GetByTokenHash(ctx context.Context, tokenHash string) (*Invite, error)
+	// Consume atomically marks an unused, unexpired invite as used and returns it
+	Consume(ctx context.Context, tokenHash string) (*Invite, error)
+}
+
+// APIKeyRepository defines the interface for service API key operations
+type APIKeyRepository interface {
+	Create(ctx context.Context, key *APIKey) error
+	GetByID(ctx context.Context, id uuid.UUID) (*APIKey, error)
+	// GetByHash also returns revoked and expired keys; callers check them
+	GetByHash(ctx context.Context, keyHash string) (*APIKey, error)
+	// List returns the keys of an organization, newest first
+	List(ctx context.Context, orgID uuid.UUID) ([]*APIKey, error)
+	// Revoke sets RevokedAt on a key that is not revoked yet
+	Revoke(ctx context.Context, id uuid.UUID, at time.Time) error
+}
+
+// WebhookDeliveryRepository stores the webhook delivery log
+type WebhookDeliveryRepository interface {
+	Create(ctx context.Context, delivery *WebhookDelivery) error
+}
+
+// Transactioner runs several repository calls atomically. Calls made with the
+// ctx passed to fn join the transaction, which is committed when fn returns
+// nil and rolled back otherwise.
+type Transactioner interface {
+	WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error
 }
@@ -1,24 +1,130 @@
 package domain
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
+	"golang.org/x/text/cases"
 )
 
 // User represents the user entity in the domain layer
 type User struct {
 	ID           uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
-	Email        string     `json:"email" gorm:"uniqueIndex;not null"`
-	Username     string     `json:"username" gorm:"uniqueIndex;not null"`
+	Email        string     `json:"email" gorm:"not null;uniqueIndex:idx_users_org_email,priority:2"`
+	Username     string     `json:"username" gorm:"not null;uniqueIndex:idx_users_org_username,priority:2"`
 	PasswordHash string     `json:"-" gorm:"not null"`
 	FirstName    string     `json:"first_name"`
 	LastName     string     `json:"last_name"`
 	IsActive     bool       `json:"is_active" gorm:"default:true"`
 	IsVerified   bool       `json:"is_verified" gorm:"default:false"`
+	Status       UserStatus `json:"status" gorm:"type:varchar(32);not null;default:active;index"`
+	Role         string     `json:"role" gorm:"type:varchar(32);not null;default:user;index:idx_users_role_created_at,priority:1"`
 	LastLoginAt  *time.Time `json:"last_login_at"`
-	CreatedAt    time.Time  `json:"created_at" gorm:"autoCreateTime"`
+	CreatedAt    time.Time  `json:"created_at" gorm:"autoCreateTime;index:idx_users_role_created_at,priority:2"`
 	UpdatedAt    time.Time  `json:"updated_at" gorm:"autoUpdateTime"`
+
+	// OrganizationID is the user's tenant. Email and username are unique
+	// within an organization, not globally
+	OrganizationID uuid.UUID `json:"organization_id" gorm:"type:uuid;uniqueIndex:idx_users_org_email,priority:1;uniqueIndex:idx_users_org_username,priority:1;uniqueIndex:idx_users_org_username_normalized,priority:1;uniqueIndex:idx_users_org_phone,priority:1"`
+
+	// Phone is an optional E.164 number (see NormalizePhone) that can be
+	// used to log in. It is unique within an organization; the index only
+	// covers users who set one
+	Phone string `json:"phone,omitempty" gorm:"type:varchar(16);not null;default:'';uniqueIndex:idx_users_org_phone,priority:2,where:phone <> ''"`
+
+	// FailedLoginAttempts counts consecutive failed logins since the last
+	// success or lockout
+	FailedLoginAttempts int        `json:"-" gorm:"not null;default:0"`
+	LockedUntil         *time.Time `json:"-"`
+	// LockoutNotifiedAt is when the user was last emailed about a lockout
+	LockoutNotifiedAt *time.Time `json:"-"`
+	// VerificationSentAt is when the user was last sent a verification email
+	VerificationSentAt *time.Time `json:"-"`
+
+	// TokenVersion is copied into every access token as the "tv" claim.
+	// Incrementing it (UserRepository.IncrementTokenVersion) invalidates all
+	// access tokens issued before; Update never writes it.
+	TokenVersion int `json:"-" gorm:"not null;default:0"`
+
+	// MustChangePassword is set when an admin issued a temporary password.
+	// Access tokens carry it, and the user is limited to changing the
+	// password until they do; updating the password clears it.
+	MustChangePassword bool `json:"-" gorm:"not null;default:false"`
+
+	// PasswordUnset marks accounts created through social login, whose
+	// random password nobody knows. Setting a password clears it; until
+	// then the last linked provider cannot be unlinked.
+	PasswordUnset bool `json:"-" gorm:"not null;default:false"`
+
+	// InvitedBy is the admin whose invite the user registered with; nil for
+	// open registration and social login
+	InvitedBy *uuid.UUID `json:"-" gorm:"type:uuid"`
+
+	// UsernameNormalized is the case-folded username, only set when
+	// case-insensitive usernames are enabled (see NormalizeUsername)
+	UsernameNormalized *string `json:"-" gorm:"uniqueIndex:idx_users_org_username_normalized,priority:2"`
+
+	// UsernameChangedAt is when the user last changed their username; nil
+	// if it is still the one chosen at registration
+	UsernameChangedAt *time.Time `json:"-"`
+
+	// DeletedAt is set when the user deletes their account. The row is kept
+	// for the audit history, with email and username anonymized; repository
+	// lookups skip deleted users.
+	DeletedAt *time.Time `json:"-" gorm:"index"`
+}
+
+// UserStatus is the lifecycle state of a user account
+type UserStatus string
+
+const (
+	// UserStatusActive is a regular account that may log in
+	UserStatusActive UserStatus = "active"
+	// UserStatusPendingApproval is a new account waiting for an admin decision
+	UserStatusPendingApproval UserStatus = "pending_approval"
+)
+
+// Roles a user can have; Role is compared as a plain string so a token's
+// "role" claim can be checked without loading the user
+const (
+	// RoleUser is the default role of every registered user
+	RoleUser = "user"
+	// RoleAdmin may use the admin endpoints
+	RoleAdmin = "admin"
+)
+
+// IsValidRole reports whether role is one of the known roles
+func IsValidRole(role string) bool {
+	return role == RoleUser || role == RoleAdmin
+}
+
+// Scopes carried by a user's access token. RequireScope checks them per
+// route, so an endpoint states the one capability it needs rather than a role.
+const (
+	// ScopeProfileRead allows reading the user's own profile and sessions
+	ScopeProfileRead = "profile:read"
+	// ScopeProfileWrite allows changing the user's own profile, password and sessions
+	ScopeProfileWrite = "profile:write"
+	// ScopeUsersRead allows listing users and reading the audit log
+	ScopeUsersRead = "users:read"
+	// ScopeUsersWrite allows approving, verifying and changing the role of users
+	ScopeUsersWrite = "users:write"
+	// ScopeAPIKeysManage allows creating, listing and revoking API keys
+	ScopeAPIKeysManage = "api_keys:manage"
+)
+
+// roleScopes are the default scopes of each role
+var roleScopes = map[string][]string{
+	RoleUser:  {ScopeProfileRead, ScopeProfileWrite},
+	RoleAdmin: {ScopeProfileRead, ScopeProfileWrite, ScopeUsersRead, ScopeUsersWrite, ScopeAPIKeysManage},
+}
+
+// ScopesForRole returns the default scopes of role; unknown roles get none
+func ScopesForRole(role string) []string {
+	return append([]string(nil), roleScopes[role]...)
 }
 
 // TableName specifies the table name for GORM
@@ -26,14 +132,113 @@ func (User) TableName() string {
 	return "users"
 }
 
+// UserFilter selects users for the admin list; zero fields match everything
+type UserFilter struct {
+	// OrganizationID limits the list to one tenant; it is always applied,
+	// uuid.Nil included
+	OrganizationID uuid.UUID
+	Role           string
+	IsActive       *bool
+	IsVerified     *bool
+	// Search matches a substring of the email or username, case-insensitively
+	Search string
+	// Page is 1-based
+	Page     int
+	PageSize int
+}
+
+// NormalizeUsername case-folds a username for case-insensitive comparison,
+// e.g. "Jane" and "JANE" both become "jane"
+func NormalizeUsername(username string) string {
+	return cases.Fold().String(username)
+}
+
+// NormalizeEmail lowercases an email address; addresses are stored and
+// looked up in this form, so "Jane@Example.com" and "jane@example.com" are
+// the same account
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// NormalizePhone converts a phone number to E.164 ("+" and up to 15
+// digits, e.g. "+905551234567"). Spaces, dots, dashes and parentheses are
+// ignored and a leading "00" counts as "+"; ok is false for numbers without
+// a country code or with other characters
+func NormalizePhone(phone string) (normalized string, ok bool) {
+	var b strings.Builder
+	for i, r := range strings.TrimSpace(phone) {
+		switch {
+		case r >= '0' && r <= '9':
+			b.WriteRune(r)
+		case r == '+' && i == 0:
+			b.WriteRune(r)
+		case r == ' ' || r == '.' || r == '-' || r == '(' || r == ')':
+		default:
+			return "", false
+		}
+	}
+	normalized = b.String()
+	if strings.HasPrefix(normalized, "00") {
+		normalized = "+" + normalized[2:]
+	}
+	// Country codes never start with 0; the shortest numbers have 8 digits
+	if len(normalized) < 9 || len(normalized) > 16 || normalized[0] != '+' || normalized[1] == '0' {
+		return "", false
+	}
+	return normalized, true
+}
+
+// IsLocked checks if the account is temporarily locked after too many failed logins
+func (u *User) IsLocked() bool {
+	return u.LockedUntil != nil && time.Now().Before(*u.LockedUntil)
+}
+
+// IsDeleted checks if the account was deleted by its owner
+func (u *User) IsDeleted() bool {
+	return u.DeletedAt != nil
+}
+
+// IsPendingApproval checks if the account still waits for admin approval
+func (u *User) IsPendingApproval() bool {
+	return u.Status == UserStatusPendingApproval
+}
+
 // RefreshToken represents a refresh token in the system
 type RefreshToken struct {
-	ID        uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
-	UserID    uuid.UUID `json:"user_id" gorm:"type:uuid;not null;index"`
-	Token     string    `json:"token" gorm:"uniqueIndex;not null"`
+	ID uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	// UserID and IsRevoked share idx_refresh_tokens_user_active, which backs
+	// the active-session lookups; it also serves lookups by user_id alone
+	UserID uuid.UUID `json:"user_id" gorm:"type:uuid;not null;index:idx_refresh_tokens_user_active,priority:1"`
+	// TokenHash is the SHA-256 of the token handed to the client
+	// (security.HashToken); the plaintext is never stored
+	TokenHash string    `json:"-" gorm:"uniqueIndex;not null"`
 	ExpiresAt time.Time `json:"expires_at" gorm:"not null"`
-	IsRevoked bool      `json:"is_revoked" gorm:"default:false"`
+	IsRevoked bool      `json:"is_revoked" gorm:"default:false;index:idx_refresh_tokens_user_active,priority:2"`
 	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
+
+	// FamilyID is shared by all tokens rotated from the same login; replaying
+	// a rotated token revokes its whole family
+	FamilyID uuid.UUID `json:"family_id" gorm:"type:uuid;index"`
+
+	// Device metadata, shown in the sessions list so users can recognize
+	// their devices. Rotated tokens take over the values of the latest request.
+	UserAgent  string     `json:"user_agent" gorm:"type:varchar(512)"`
+	IPAddress  string     `json:"ip_address" gorm:"type:varchar(45)"`
+	LastUsedAt *time.Time `json:"last_used_at"`
+
+	// DeviceFingerprint identifies the IP + user-agent combination the token
+	// was issued to (see DeviceFingerprint); logins from an unseen
+	// fingerprint trigger a sign-in alert
+	DeviceFingerprint string `json:"-" gorm:"type:varchar(64);index"`
+
+	// RememberMe marks a login that asked to stay signed in; the token and
+	// the tokens rotated from it live for the longer remember-me TTL
+	RememberMe bool `json:"remember_me" gorm:"default:false"`
+
+	// AuthenticatedAt is when the login that started the family happened;
+	// rotated tokens inherit it, so the absolute session lifetime counts
+	// from the login rather than the last refresh
+	AuthenticatedAt *time.Time `json:"authenticated_at"`
 }
 
 // TableName specifies the table name for GORM
@@ -41,6 +246,15 @@ func (RefreshToken) TableName() string {
 	return "refresh_tokens"
 }
 
+// SessionStart returns when the session of the token began. Tokens issued
+// before AuthenticatedAt existed count from their own creation.
+func (rt *RefreshToken) SessionStart() time.Time {
+	if rt.AuthenticatedAt == nil {
+		return rt.CreatedAt
+	}
+	return *rt.AuthenticatedAt
+}
+
 // IsExpired checks if the refresh token is expired
 func (rt *RefreshToken) IsExpired() bool {
 	return time.Now().After(rt.ExpiresAt)
@@ -50,3 +264,10 @@ func (rt *RefreshToken) IsExpired() bool {
 func (rt *RefreshToken) IsValid() bool {
 	return !rt.IsExpired() && !rt.IsRevoked
 }
+
+// DeviceFingerprint returns a stable hex SHA-256 of an IP address and
+// user-agent pair, so devices can be compared without storing them twice
+func DeviceFingerprint(ipAddress, userAgent string) string {
+	sum := sha256.Sum256([]byte(ipAddress + "\n" + userAgent))
+	return hex.EncodeToString(sum[:])
+}
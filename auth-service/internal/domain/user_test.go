@@ -0,0 +1,72 @@
+package domain
+
+import "testing"
+
+func TestNormalizeUsername(t *testing.T) {
+	tests := []struct{ a, b string }{
+		{"Jane", "jane"},
+		{"JANE_DOE", "jane_doe"},
+		{"Straße", "STRASSE"},
+	}
+	for _, tt := range tests {
+		if NormalizeUsername(tt.a) != NormalizeUsername(tt.b) {
+			t.Errorf("NormalizeUsername(%q) = %q, NormalizeUsername(%q) = %q, want equal",
+				tt.a, NormalizeUsername(tt.a), tt.b, NormalizeUsername(tt.b))
+		}
+	}
+	if NormalizeUsername("jane") == NormalizeUsername("jane2") {
+		t.Error("different usernames normalized to the same value")
+	}
+}
+
+func TestNormalizeEmail(t *testing.T) {
+	if got := NormalizeEmail(" Jane.Doe@Example.COM "); got != "jane.doe@example.com" {
+		t.Errorf("NormalizeEmail = %q, want jane.doe@example.com", got)
+	}
+}
+
+func TestNormalizePhone(t *testing.T) {
+	tests := []struct {
+		phone string
+		want  string
+		ok    bool
+	}{
+		{"+905551234567", "+905551234567", true},
+		{" +1 (415) 555-0132 ", "+14155550132", true},
+		{"0049.30.1234567", "+49301234567", true},
+		{"05551234567", "", false},
+		{"+0123456789", "", false},
+		{"+1234567", "", false},
+		{"+1234567890123456", "", false},
+		{"+90 555 CALL NOW", "", false},
+		{"90+5551234567", "", false},
+		{"", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := NormalizePhone(tt.phone)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("NormalizePhone(%q) = %q, %v, want %q, %v", tt.phone, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestIsValidRole(t *testing.T) {
+	for role, want := range map[string]bool{RoleUser: true, RoleAdmin: true, "": false, "Admin": false, "root": false} {
+		if got := IsValidRole(role); got != want {
+			t.Errorf("IsValidRole(%q) = %v, want %v", role, got, want)
+		}
+	}
+}
+
+func TestDeviceFingerprint(t *testing.T) {
+	fp := DeviceFingerprint("203.0.113.7", "Firefox")
+	if len(fp) != 64 || fp != DeviceFingerprint("203.0.113.7", "Firefox") {
+		t.Fatalf("fingerprint %q is not a stable SHA-256", fp)
+	}
+	// The separator keeps shifted boundaries apart
+	for _, other := range [][2]string{{"203.0.113.7", "Safari"}, {"203.0.113.8", "Firefox"}, {"203.0.113.7F", "irefox"}} {
+		if DeviceFingerprint(other[0], other[1]) == fp {
+			t.Errorf("DeviceFingerprint(%q, %q) collides", other[0], other[1])
+		}
+	}
+}
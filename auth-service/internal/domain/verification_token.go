@@ -0,0 +1,28 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// VerificationToken represents a single-use email verification token.
+// Only a SHA-256 hash of the token is stored.
+type VerificationToken struct {
+	ID        uuid.UUID  `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	UserID    uuid.UUID  `json:"user_id" gorm:"type:uuid;not null;index"`
+	TokenHash string     `json:"-" gorm:"uniqueIndex;not null"`
+	ExpiresAt time.Time  `json:"expires_at" gorm:"not null"`
+	UsedAt    *time.Time `json:"used_at"`
+	CreatedAt time.Time  `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// TableName specifies the table name for GORM
+func (VerificationToken) TableName() string {
+	return "verification_tokens"
+}
+
+// IsExpired checks if the verification token is expired
+func (t *VerificationToken) IsExpired() bool {
+	return time.Now().After(t.ExpiresAt)
+}
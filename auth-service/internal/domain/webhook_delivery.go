@@ -0,0 +1,28 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WebhookDelivery records the outcome of delivering one event to one
+// webhook URL, after all retries
+type WebhookDelivery struct {
+	ID uuid.UUID `json:"id" gorm:"type:uuid;primary_key;default:gen_random_uuid()"`
+	// EventID is the same for every URL the event was sent to; receivers see
+	// it as the payload "id"
+	EventID    uuid.UUID `json:"event_id" gorm:"type:uuid;not null;index"`
+	Event      string    `json:"event" gorm:"type:varchar(64);not null;index"`
+	URL        string    `json:"url" gorm:"type:varchar(2048);not null"`
+	Attempts   int       `json:"attempts" gorm:"not null"`
+	Delivered  bool      `json:"delivered" gorm:"not null;index"`
+	StatusCode int       `json:"status_code,omitempty"` // Last response status, 0 when no response
+	Error      string    `json:"error,omitempty" gorm:"type:text"`
+	CreatedAt  time.Time `json:"created_at" gorm:"not null;index"`
+}
+
+// TableName specifies the table name for GORM
+func (WebhookDelivery) TableName() string {
+	return "webhook_deliveries"
+}
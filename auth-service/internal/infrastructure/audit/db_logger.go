@@ -0,0 +1,38 @@
+package audit
+
+import (
+	"context"
+	"log"
+
+	"auth-service/internal/application/usecase"
+	"auth-service/internal/domain"
+)
+
+// DBLogger stores audit events in the database so they can be queried
+// through the admin API
+type DBLogger struct {
+	repo domain.AuditLogRepository
+}
+
+// NewDBLogger creates a new database-backed audit logger
+func NewDBLogger(repo domain.AuditLogRepository) *DBLogger {
+	return &DBLogger{repo: repo}
+}
+
+// Log stores the event. A failed write must not fail the audited action, so
+// the error is only logged.
+func (l *DBLogger) Log(ctx context.Context, event usecase.AuditEvent) {
+	err := l.repo.Create(ctx, &domain.AuditLog{
+		Action:    event.Action,
+		ActorID:   event.ActorID,
+		TargetID:  event.TargetID,
+		IPAddress: event.IPAddress,
+		UserAgent: event.UserAgent,
+		RequestID: eventRequestID(ctx, event),
+		Details:   event.Details,
+		CreatedAt: eventTime(event),
+	})
+	if err != nil {
+		log.Printf("audit: failed to store %s event: %v", event.Action, err)
+	}
+}
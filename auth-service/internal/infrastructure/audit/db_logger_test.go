@@ -0,0 +1,71 @@
+package audit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"auth-service/internal/application/usecase"
+	"auth-service/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+type memoryAuditLogRepo struct {
+	logs []*domain.AuditLog
+	err  error
+}
+
+func (r *memoryAuditLogRepo) Create(ctx context.Context, log *domain.AuditLog) error {
+	if r.err != nil {
+		return r.err
+	}
+	r.logs = append(r.logs, log)
+	return nil
+}
+
+func (r *memoryAuditLogRepo) List(ctx context.Context, filter domain.AuditLogFilter, page, pageSize int) ([]*domain.AuditLog, int64, error) {
+	return r.logs, int64(len(r.logs)), nil
+}
+
+func TestDBLoggerStoresEvent(t *testing.T) {
+	repo := &memoryAuditLogRepo{}
+	userID := uuid.New()
+	at := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	NewDBLogger(repo).Log(context.Background(), usecase.AuditEvent{
+		Action:    usecase.AuditLoginFailed,
+		ActorID:   userID,
+		TargetID:  userID,
+		IPAddress: "203.0.113.7",
+		UserAgent: "Firefox/130.0",
+		RequestID: "req-123",
+		Time:      at,
+		Details:   map[string]string{"reason": "bad_password"},
+	})
+
+	if len(repo.logs) != 1 {
+		t.Fatalf("stored %d logs, want 1", len(repo.logs))
+	}
+	got := repo.logs[0]
+	if got.Action != "login_failed" || got.TargetID != userID || got.IPAddress != "203.0.113.7" ||
+		got.UserAgent != "Firefox/130.0" || got.RequestID != "req-123" || !got.CreatedAt.Equal(at) || got.Details["reason"] != "bad_password" {
+		t.Errorf("stored log = %+v", got)
+	}
+}
+
+func TestDBLoggerDefaultsTimeAndSurvivesErrors(t *testing.T) {
+	repo := &memoryAuditLogRepo{}
+	ctx := usecase.ContextWithRequestID(context.Background(), "req-456")
+	NewDBLogger(repo).Log(ctx, usecase.AuditEvent{Action: "user.approved"})
+	if repo.logs[0].CreatedAt.IsZero() {
+		t.Error("CreatedAt not set for an event without a time")
+	}
+	if repo.logs[0].RequestID != "req-456" {
+		t.Errorf("RequestID = %q, want the one from the context", repo.logs[0].RequestID)
+	}
+
+	// A failing store is logged, not panicked on or returned
+	NewDBLogger(&memoryAuditLogRepo{err: errors.New("db down")}).Log(context.Background(), usecase.AuditEvent{Action: "logout"})
+}
@@ -0,0 +1,67 @@
+// Package audit contains implementations of the use case AuditLogger port
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"time"
+
+	"auth-service/internal/application/usecase"
+)
+
+// entry is the JSON shape of one audit line
+type entry struct {
+	Action    string            `json:"action"`
+	ActorID   string            `json:"actor_id"`
+	TargetID  string            `json:"target_id"`
+	IPAddress string            `json:"ip_address,omitempty"`
+	UserAgent string            `json:"user_agent,omitempty"`
+	RequestID string            `json:"request_id,omitempty"`
+	Details   map[string]string `json:"details,omitempty"`
+	Time      time.Time         `json:"time"`
+}
+
+// LogLogger writes audit events as JSON lines to the application log
+type LogLogger struct{}
+
+// NewLogLogger creates a new log-backed audit logger
+func NewLogLogger() *LogLogger {
+	return &LogLogger{}
+}
+
+// Log writes the event
+func (l *LogLogger) Log(ctx context.Context, event usecase.AuditEvent) {
+	line, err := json.Marshal(entry{
+		Action:    event.Action,
+		ActorID:   event.ActorID.String(),
+		TargetID:  event.TargetID.String(),
+		IPAddress: event.IPAddress,
+		UserAgent: event.UserAgent,
+		RequestID: eventRequestID(ctx, event),
+		Details:   event.Details,
+		Time:      eventTime(event),
+	})
+	if err != nil {
+		log.Printf("audit: failed to encode %s event: %v", event.Action, err)
+		return
+	}
+	log.Printf("audit: %s", line)
+}
+
+// eventTime is when the event happened, or now if the caller did not say
+func eventTime(event usecase.AuditEvent) time.Time {
+	if event.Time.IsZero() {
+		return time.Now().UTC()
+	}
+	return event.Time.UTC()
+}
+
+// eventRequestID is the ID of the request that caused the event. Events built
+// without one (e.g. by the admin use case) take it from the context.
+func eventRequestID(ctx context.Context, event usecase.AuditEvent) string {
+	if event.RequestID != "" {
+		return event.RequestID
+	}
+	return usecase.RequestIDFromContext(ctx)
+}
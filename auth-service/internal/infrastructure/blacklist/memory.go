@@ -0,0 +1,47 @@
+package blacklist
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"auth-service/internal/domain"
+)
+
+// MemoryTokenBlacklist keeps revoked token IDs in process memory. It is meant
+// for tests and single-instance development setups; entries are not shared
+// between replicas.
+type MemoryTokenBlacklist struct {
+	mu      sync.Mutex
+	entries map[string]time.Time
+}
+
+// NewMemoryTokenBlacklist creates a new in-memory token blacklist
+func NewMemoryTokenBlacklist() domain.TokenBlacklist {
+	return &MemoryTokenBlacklist{entries: make(map[string]time.Time)}
+}
+
+// Add blacklists a token ID for ttl
+func (b *MemoryTokenBlacklist) Add(ctx context.Context, tokenID string, ttl time.Duration) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := time.Now()
+	// Drop expired entries so the map stays bounded by the number of live tokens
+	for id, expiresAt := range b.entries {
+		if !now.Before(expiresAt) {
+			delete(b.entries, id)
+		}
+	}
+	b.entries[tokenID] = now.Add(ttl)
+	return nil
+}
+
+// Contains reports whether a token ID is blacklisted
+func (b *MemoryTokenBlacklist) Contains(ctx context.Context, tokenID string) (bool, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	expiresAt, ok := b.entries[tokenID]
+	return ok && time.Now().Before(expiresAt), nil
+}
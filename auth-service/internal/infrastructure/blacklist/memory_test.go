@@ -0,0 +1,29 @@
+package blacklist
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMemoryTokenBlacklist(t *testing.T) {
+	ctx := context.Background()
+	b := NewMemoryTokenBlacklist()
+
+	if err := b.Add(ctx, "revoked", time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Add(ctx, "expired", -time.Second); err != nil {
+		t.Fatal(err)
+	}
+
+	for id, want := range map[string]bool{"revoked": true, "expired": false, "unknown": false} {
+		got, err := b.Contains(ctx, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Errorf("Contains(%q) = %v, want %v", id, got, want)
+		}
+	}
+}
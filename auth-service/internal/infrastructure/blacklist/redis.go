@@ -0,0 +1,39 @@
+// Package blacklist contains implementations of domain.TokenBlacklist
+package blacklist
+
+import (
+	"context"
+	"time"
+
+	"auth-service/internal/domain"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const keyPrefix = "auth:blacklist:"
+
+// RedisTokenBlacklist stores revoked token IDs as Redis keys that expire
+// together with the token, so the blacklist never grows past the set of
+// still-valid tokens
+type RedisTokenBlacklist struct {
+	client *redis.Client
+}
+
+// NewRedisTokenBlacklist creates a new Redis-backed token blacklist
+func NewRedisTokenBlacklist(client *redis.Client) domain.TokenBlacklist {
+	return &RedisTokenBlacklist{client: client}
+}
+
+// Add blacklists a token ID for ttl
+func (b *RedisTokenBlacklist) Add(ctx context.Context, tokenID string, ttl time.Duration) error {
+	return b.client.Set(ctx, keyPrefix+tokenID, 1, ttl).Err()
+}
+
+// Contains reports whether a token ID is blacklisted
+func (b *RedisTokenBlacklist) Contains(ctx context.Context, tokenID string) (bool, error) {
+	n, err := b.client.Exists(ctx, keyPrefix+tokenID).Result()
+	if err != nil {
+		return false, err
+	}
+	return n > 0, nil
+}
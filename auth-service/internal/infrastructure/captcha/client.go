@@ -0,0 +1,98 @@
+// Package captcha verifies CAPTCHA response tokens with reCAPTCHA, hCaptcha
+// or Cloudflare Turnstile. The three providers share the same siteverify
+// protocol and differ only in the endpoint.
+package captcha
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Supported providers
+const (
+	ProviderRecaptcha = "recaptcha"
+	ProviderHCaptcha  = "hcaptcha"
+	ProviderTurnstile = "turnstile"
+)
+
+// verifyURLs are the siteverify endpoints of the providers
+var verifyURLs = map[string]string{
+	ProviderRecaptcha: "https://www.google.com/recaptcha/api/siteverify",
+	ProviderHCaptcha:  "https://api.hcaptcha.com/siteverify",
+	ProviderTurnstile: "https://challenges.cloudflare.com/turnstile/v0/siteverify",
+}
+
+// Client checks tokens against a provider's siteverify endpoint
+type Client struct {
+	client    *http.Client
+	secret    string
+	verifyURL string // overridden in tests
+}
+
+// NewClient creates a client for the given provider
+func NewClient(provider, secret string, timeout time.Duration) (*Client, error) {
+	verifyURL, ok := verifyURLs[provider]
+	if !ok {
+		return nil, fmt.Errorf("unknown CAPTCHA provider %q", provider)
+	}
+	return &Client{
+		client:    &http.Client{Timeout: timeout},
+		secret:    secret,
+		verifyURL: verifyURL,
+	}, nil
+}
+
+// verifyResponse is the part of the siteverify answer we use
+type verifyResponse struct {
+	Success    bool     `json:"success"`
+	ErrorCodes []string `json:"error-codes"`
+}
+
+// Verify reports whether token is a valid, unused solution. Tokens are
+// single-use at every provider, so a replayed token is rejected.
+func (c *Client) Verify(ctx context.Context, token, remoteIP string) (bool, error) {
+	form := url.Values{"secret": {c.secret}, "response": {token}}
+	if remoteIP != "" {
+		form.Set("remoteip", remoteIP)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.verifyURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("captcha: unexpected status %d", resp.StatusCode)
+	}
+
+	var result verifyResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return false, fmt.Errorf("captcha: decode response: %w", err)
+	}
+	// A bad secret is our misconfiguration, not a failed challenge
+	for _, code := range result.ErrorCodes {
+		if code == "invalid-input-secret" || code == "missing-input-secret" {
+			return false, fmt.Errorf("captcha: provider rejected the secret (%s)", code)
+		}
+	}
+	return result.Success, nil
+}
+
+// NoopVerifier accepts every token. It lets local development and end-to-end
+// tests exercise the CAPTCHA flow without a provider account.
+type NoopVerifier struct{}
+
+// Verify always succeeds
+func (NoopVerifier) Verify(ctx context.Context, token, remoteIP string) (bool, error) {
+	return true, nil
+}
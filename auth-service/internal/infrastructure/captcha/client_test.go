@@ -0,0 +1,58 @@
+package captcha
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestVerify(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Fatal(err)
+		}
+		if r.PostForm.Get("secret") != "test-secret" || r.PostForm.Get("remoteip") != "203.0.113.7" {
+			t.Errorf("form = %v", r.PostForm)
+		}
+		switch r.PostForm.Get("response") {
+		case "solved":
+			fmt.Fprint(w, `{"success": true}`)
+		case "broken-secret":
+			fmt.Fprint(w, `{"success": false, "error-codes": ["invalid-input-secret"]}`)
+		default:
+			fmt.Fprint(w, `{"success": false, "error-codes": ["invalid-input-response"]}`)
+		}
+	}))
+	defer server.Close()
+
+	c, err := NewClient(ProviderTurnstile, "test-secret", time.Second)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.verifyURL = server.URL
+
+	tests := []struct {
+		token   string
+		want    bool
+		wantErr bool
+	}{
+		{"solved", true, false},
+		{"forged", false, false},
+		{"broken-secret", false, true},
+	}
+	for _, tt := range tests {
+		ok, err := c.Verify(context.Background(), tt.token, "203.0.113.7")
+		if ok != tt.want || (err != nil) != tt.wantErr {
+			t.Errorf("%s: ok = %v, err = %v", tt.token, ok, err)
+		}
+	}
+}
+
+func TestNewClientRejectsUnknownProvider(t *testing.T) {
+	if _, err := NewClient("captchaland", "secret", time.Second); err == nil {
+		t.Error("expected an error")
+	}
+}
@@ -0,0 +1,93 @@
+// Package cleanup contains background jobs that remove dead data
+package cleanup
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"auth-service/internal/domain"
+)
+
+// TokenCleaner periodically deletes expired refresh tokens and, with a
+// retention set, old revoked ones
+type TokenCleaner struct {
+	repo      domain.RefreshTokenRepository
+	interval  time.Duration
+	retention time.Duration
+	now       func() time.Time // replaced in tests
+
+	mu     sync.Mutex
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewTokenCleaner creates a cleaner that runs every interval. A zero
+// retention keeps revoked tokens until they expire.
+func NewTokenCleaner(repo domain.RefreshTokenRepository, interval, retention time.Duration) *TokenCleaner {
+	return &TokenCleaner{repo: repo, interval: interval, retention: retention, now: time.Now}
+}
+
+// Start runs a cleanup right away and then every interval until ctx is
+// cancelled or Stop is called. Calling Start on a running cleaner does nothing.
+func (c *TokenCleaner) Start(ctx context.Context) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.done != nil {
+		return
+	}
+
+	ctx, c.cancel = context.WithCancel(ctx)
+	c.done = make(chan struct{})
+	go c.run(ctx, c.done)
+}
+
+// Stop stops the cleaner and waits for a running cleanup to finish
+func (c *TokenCleaner) Stop() {
+	c.mu.Lock()
+	cancel, done := c.cancel, c.done
+	c.cancel, c.done = nil, nil
+	c.mu.Unlock()
+
+	if cancel == nil {
+		return
+	}
+	cancel()
+	<-done
+}
+
+func (c *TokenCleaner) run(ctx context.Context, done chan struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	for {
+		c.cleanup(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// cleanup runs one pass; errors are logged and retried on the next tick
+func (c *TokenCleaner) cleanup(ctx context.Context) {
+	var revokedBefore time.Time
+	if c.retention > 0 {
+		revokedBefore = c.now().Add(-c.retention)
+	}
+
+	deleted, err := c.repo.DeleteExpired(ctx, revokedBefore)
+	if err != nil {
+		if ctx.Err() == nil {
+			log.Printf("token cleanup: %v", err)
+		}
+		return
+	}
+	if deleted > 0 {
+		log.Printf("token cleanup: removed %d refresh tokens", deleted)
+	}
+}
@@ -0,0 +1,105 @@
+package cleanup
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"auth-service/internal/domain"
+)
+
+// recordingRepo records DeleteExpired calls; other methods panic through
+// the nil embedded interface
+type recordingRepo struct {
+	domain.RefreshTokenRepository
+
+	mu    sync.Mutex
+	calls []time.Time
+	err   error
+	ran   chan struct{}
+}
+
+func (r *recordingRepo) DeleteExpired(ctx context.Context, revokedBefore time.Time) (int64, error) {
+	r.mu.Lock()
+	r.calls = append(r.calls, revokedBefore)
+	err := r.err
+	r.mu.Unlock()
+	select {
+	case r.ran <- struct{}{}:
+	default:
+	}
+	return 3, err
+}
+
+func (r *recordingRepo) callCount() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.calls)
+}
+
+func waitForRun(t *testing.T, repo *recordingRepo) {
+	t.Helper()
+	select {
+	case <-repo.ran:
+	case <-time.After(time.Second):
+		t.Fatal("cleanup did not run")
+	}
+}
+
+func TestTokenCleanerRunsPeriodically(t *testing.T) {
+	repo := &recordingRepo{ran: make(chan struct{}, 1)}
+	c := NewTokenCleaner(repo, 10*time.Millisecond, 0)
+
+	c.Start(context.Background())
+	// Runs immediately, then on every tick; failures do not stop it
+	waitForRun(t, repo)
+	repo.mu.Lock()
+	repo.err = errors.New("db down")
+	repo.mu.Unlock()
+	waitForRun(t, repo)
+	waitForRun(t, repo)
+	c.Stop()
+
+	after := repo.callCount()
+	time.Sleep(30 * time.Millisecond)
+	if repo.callCount() != after {
+		t.Error("cleanup ran after Stop")
+	}
+	if !repo.calls[0].IsZero() {
+		t.Errorf("revokedBefore = %v, want zero without a retention", repo.calls[0])
+	}
+
+	// Stopping twice is harmless
+	c.Stop()
+}
+
+func TestTokenCleanerStopsOnContextCancel(t *testing.T) {
+	repo := &recordingRepo{ran: make(chan struct{}, 1)}
+	c := NewTokenCleaner(repo, time.Hour, 0)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	c.Start(ctx)
+	waitForRun(t, repo)
+	done := c.done
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("cleaner still running after the context was cancelled")
+	}
+}
+
+func TestTokenCleanerRevokedRetention(t *testing.T) {
+	repo := &recordingRepo{}
+	now := time.Date(2024, 1, 8, 0, 0, 0, 0, time.UTC)
+	c := NewTokenCleaner(repo, time.Hour, 7*24*time.Hour)
+	c.now = func() time.Time { return now }
+
+	c.cleanup(context.Background())
+	if want := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC); !repo.calls[0].Equal(want) {
+		t.Errorf("revokedBefore = %v, want %v", repo.calls[0], want)
+	}
+}
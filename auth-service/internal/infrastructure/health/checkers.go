@@ -0,0 +1,110 @@
+package health
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+)
+
+// PostgresChecker pings the database connection pool
+type PostgresChecker struct {
+	db *gorm.DB
+}
+
+// NewPostgresChecker creates a new Postgres checker
+func NewPostgresChecker(db *gorm.DB) *PostgresChecker {
+	return &PostgresChecker{db: db}
+}
+
+func (c *PostgresChecker) Name() string { return "postgres" }
+
+func (c *PostgresChecker) Check(ctx context.Context) error {
+	sqlDB, err := c.db.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.PingContext(ctx)
+}
+
+// RedisChecker sends a PING to Redis
+type RedisChecker struct {
+	client *redis.Client
+}
+
+// NewRedisChecker creates a new Redis checker
+func NewRedisChecker(client *redis.Client) *RedisChecker {
+	return &RedisChecker{client: client}
+}
+
+func (c *RedisChecker) Name() string { return "redis" }
+
+func (c *RedisChecker) Check(ctx context.Context) error {
+	return c.client.Ping(ctx).Err()
+}
+
+// TCPChecker verifies that a TCP endpoint (e.g. an SMTP relay) accepts connections
+type TCPChecker struct {
+	name string
+	addr string
+}
+
+// NewTCPChecker creates a new TCP reachability checker
+func NewTCPChecker(name, addr string) *TCPChecker {
+	return &TCPChecker{name: name, addr: addr}
+}
+
+func (c *TCPChecker) Name() string { return c.name }
+
+func (c *TCPChecker) Check(ctx context.Context) error {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "tcp", c.addr)
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
+
+// HTTPChecker verifies that an HTTP endpoint (e.g. an OAuth provider's discovery
+// document) responds without a server error
+type HTTPChecker struct {
+	name   string
+	url    string
+	client *http.Client
+}
+
+// NewHTTPChecker creates a new HTTP reachability checker. It uses its own
+// client that does not follow redirects, so the probe reports the provider's
+// own response instead of wherever it redirects to.
+func NewHTTPChecker(name, url string) *HTTPChecker {
+	return &HTTPChecker{
+		name: name,
+		url:  url,
+		client: &http.Client{
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}
+}
+
+func (c *HTTPChecker) Name() string { return c.name }
+
+func (c *HTTPChecker) Check(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+// Package health runs dependency checks (database, cache, mail, OAuth providers)
+// and aggregates their results into a single report.
+package health
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Status values reported per dependency and for the overall report
+const (
+	StatusHealthy   = "healthy"
+	StatusDegraded  = "degraded"
+	StatusUnhealthy = "unhealthy"
+)
+
+// Checker checks a single dependency
+type Checker interface {
+	Name() string
+	Check(ctx context.Context) error
+}
+
+// CheckResult is the outcome of a single dependency check
+type CheckResult struct {
+	Name      string    `json:"name"`
+	Status    string    `json:"status"`
+	Critical  bool      `json:"critical"`
+	LatencyMs int64     `json:"latency_ms"`
+	Error     string    `json:"error,omitempty"`
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+// Report is the aggregated result of all registered checks
+type Report struct {
+	Status    string        `json:"status"`
+	Checks    []CheckResult `json:"checks"`
+	CheckedAt time.Time     `json:"checked_at"`
+}
+
+type registration struct {
+	checker  Checker
+	critical bool
+}
+
+// Service runs registered checks concurrently, each bounded by its own timeout
+type Service struct {
+	timeout time.Duration
+
+	mu     sync.RWMutex
+	checks []registration
+}
+
+// NewService creates a new health service with the given per-check timeout
+func NewService(timeout time.Duration) *Service {
+	if timeout <= 0 {
+		timeout = 2 * time.Second
+	}
+	return &Service{timeout: timeout}
+}
+
+// Register adds a check. A failing critical check makes the service unhealthy,
+// a failing non-critical check only degrades it.
+func (s *Service) Register(checker Checker, critical bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.checks = append(s.checks, registration{checker: checker, critical: critical})
+}
+
+// Run executes all checks and aggregates them into a report
+func (s *Service) Run(ctx context.Context) *Report {
+	s.mu.RLock()
+	checks := make([]registration, len(s.checks))
+	copy(checks, s.checks)
+	s.mu.RUnlock()
+
+	results := make([]CheckResult, len(checks))
+	var wg sync.WaitGroup
+	for i, reg := range checks {
+		wg.Add(1)
+		go func(i int, reg registration) {
+			defer wg.Done()
+			results[i] = s.runOne(ctx, reg)
+		}(i, reg)
+	}
+	wg.Wait()
+
+	return &Report{
+		Status:    aggregate(results),
+		Checks:    results,
+		CheckedAt: time.Now().UTC(),
+	}
+}
+
+// runOne runs a single check with its own timeout. The check runs in its own
+// goroutine so a checker that ignores its context still cannot hang the report.
+func (s *Service) runOne(ctx context.Context, reg registration) CheckResult {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() { done <- reg.checker.Check(ctx) }()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	result := CheckResult{
+		Name:      reg.checker.Name(),
+		Status:    StatusHealthy,
+		Critical:  reg.critical,
+		LatencyMs: time.Since(start).Milliseconds(),
+		CheckedAt: time.Now().UTC(),
+	}
+	if err != nil {
+		result.Status = StatusUnhealthy
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// aggregate derives the overall status from individual results
+func aggregate(results []CheckResult) string {
+	status := StatusHealthy
+	for _, r := range results {
+		if r.Status == StatusHealthy {
+			continue
+		}
+		if r.Critical {
+			return StatusUnhealthy
+		}
+		status = StatusDegraded
+	}
+	return status
+}
@@ -0,0 +1,66 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+type fakeChecker struct {
+	name  string
+	err   error
+	delay time.Duration
+}
+
+func (f *fakeChecker) Name() string { return f.name }
+
+func (f *fakeChecker) Check(ctx context.Context) error {
+	if f.delay > 0 {
+		time.Sleep(f.delay)
+	}
+	return f.err
+}
+
+func TestRunAggregatesStatus(t *testing.T) {
+	tests := []struct {
+		name     string
+		critical error
+		optional error
+		want     string
+	}{
+		{"all healthy", nil, nil, StatusHealthy},
+		{"optional failing", nil, errors.New("down"), StatusDegraded},
+		{"critical failing", errors.New("down"), nil, StatusUnhealthy},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := NewService(time.Second)
+			svc.Register(&fakeChecker{name: "db", err: tt.critical}, true)
+			svc.Register(&fakeChecker{name: "smtp", err: tt.optional}, false)
+
+			report := svc.Run(context.Background())
+			if report.Status != tt.want {
+				t.Fatalf("status = %q, want %q", report.Status, tt.want)
+			}
+			if len(report.Checks) != 2 {
+				t.Fatalf("got %d checks, want 2", len(report.Checks))
+			}
+		})
+	}
+}
+
+func TestRunTimesOutSlowCheck(t *testing.T) {
+	svc := NewService(50 * time.Millisecond)
+	svc.Register(&fakeChecker{name: "slow", delay: time.Second}, false)
+
+	start := time.Now()
+	report := svc.Run(context.Background())
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Fatalf("Run took %v, expected the slow check to be cut off", elapsed)
+	}
+	if report.Checks[0].Status != StatusUnhealthy || report.Status != StatusDegraded {
+		t.Fatalf("unexpected report: %+v", report)
+	}
+}
@@ -0,0 +1,66 @@
+// Package hibp checks passwords against the HaveIBeenPwned breach corpus
+package hibp
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// rangeURL is the k-anonymity range endpoint of the Pwned Passwords API
+const rangeURL = "https://api.pwnedpasswords.com/range/"
+
+// Client queries the Pwned Passwords range API. Only the first 5 hex
+// characters of the password's SHA-1 are sent; the matching suffixes come
+// back and are compared locally, so neither the password nor its full hash
+// leaves the service.
+type Client struct {
+	client   *http.Client
+	rangeURL string // overridden in tests
+}
+
+// NewClient creates a new Pwned Passwords client
+func NewClient(timeout time.Duration) *Client {
+	return &Client{
+		client:   &http.Client{Timeout: timeout},
+		rangeURL: rangeURL,
+	}
+}
+
+// CheckBreached reports whether the password appears in a known breach
+func (c *Client) CheckBreached(ctx context.Context, password string) (bool, error) {
+	sum := sha1.Sum([]byte(password))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+	prefix, suffix := hash[:5], hash[5:]
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.rangeURL+prefix, nil)
+	if err != nil {
+		return false, err
+	}
+	// Padding hides the real number of matches from anyone watching the traffic
+	req.Header.Set("Add-Padding", "true")
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return false, fmt.Errorf("pwned passwords: unexpected status %d", resp.StatusCode)
+	}
+
+	// Each line is "SUFFIX:COUNT"; padding entries have a count of 0
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		candidate, count, ok := strings.Cut(strings.TrimSpace(scanner.Text()), ":")
+		if ok && strings.EqualFold(candidate, suffix) && count != "0" {
+			return true, nil
+		}
+	}
+	return false, scanner.Err()
+}
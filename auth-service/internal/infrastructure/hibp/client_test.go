@@ -0,0 +1,62 @@
+package hibp
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCheckBreached(t *testing.T) {
+	// SHA-1("password") = 5BAA61E4C9B93F3F0682250B6CF8331B7EE68FD8
+	var requested []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = append(requested, r.URL.Path)
+		if r.Header.Get("Add-Padding") != "true" {
+			t.Error("padding not requested")
+		}
+		fmt.Fprintln(w, "0018A45C4D1DEF81644B54AB7F969B88D65:1")
+		fmt.Fprintln(w, "1E4C9B93F3F0682250B6CF8331B7EE68FD8:9659365")
+		// Padding entry for an unrelated suffix
+		fmt.Fprintln(w, "A0F2E3B1C4D5E6F708192A3B4C5D6E7F809:0")
+	}))
+	defer server.Close()
+
+	c := NewClient(time.Second)
+	c.rangeURL = server.URL + "/range/"
+
+	breached, err := c.CheckBreached(context.Background(), "password")
+	if err != nil || !breached {
+		t.Errorf("password: breached = %v, err = %v", breached, err)
+	}
+	breached, err = c.CheckBreached(context.Background(), "a passphrase nobody has used")
+	if err != nil || breached {
+		t.Errorf("unique passphrase: breached = %v, err = %v", breached, err)
+	}
+
+	// Only the 5-character prefix is sent
+	if requested[0] != "/range/5BAA6" {
+		t.Errorf("requested %q", requested[0])
+	}
+	for _, path := range requested {
+		if strings.Contains(path, "1E4C9B93F3F0682250B6CF8331B7EE68FD8") {
+			t.Errorf("full hash sent upstream: %q", path)
+		}
+	}
+}
+
+func TestCheckBreachedReportsUpstreamErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	c := NewClient(time.Second)
+	c.rangeURL = server.URL + "/range/"
+	if _, err := c.CheckBreached(context.Background(), "password"); err == nil {
+		t.Error("expected an error for a 503 response")
+	}
+}
@@ -0,0 +1,61 @@
+package idempotency
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// MemoryStore keeps idempotent responses in process memory. It is meant for
+// tests and single-instance development setups; claims are not shared
+// between replicas.
+type MemoryStore struct {
+	mu      sync.Mutex
+	entries map[string]memoryEntry
+	now     func() time.Time // replaced in tests
+}
+
+type memoryEntry struct {
+	value     []byte // nil while claimed
+	expiresAt time.Time
+}
+
+// NewMemoryStore creates a new in-memory idempotency store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{entries: make(map[string]memoryEntry), now: time.Now}
+}
+
+// Reserve claims key unless it is already claimed or saved
+func (s *MemoryStore) Reserve(ctx context.Context, key string, lockTTL time.Duration) (bool, []byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	// Drop expired entries so the map stays bounded by the live keys
+	for k, e := range s.entries {
+		if !now.Before(e.expiresAt) {
+			delete(s.entries, k)
+		}
+	}
+	if e, ok := s.entries[key]; ok {
+		return false, e.value, nil
+	}
+	s.entries[key] = memoryEntry{expiresAt: now.Add(lockTTL)}
+	return true, nil, nil
+}
+
+// Save stores the response for key
+func (s *MemoryStore) Save(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries[key] = memoryEntry{value: value, expiresAt: s.now().Add(ttl)}
+	return nil
+}
+
+// Release drops the claim on key
+func (s *MemoryStore) Release(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.entries, key)
+	return nil
+}
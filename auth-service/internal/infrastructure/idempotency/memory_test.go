@@ -0,0 +1,47 @@
+package idempotency
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMemoryStoreClaimsKeyOnce(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemoryStore()
+
+	if claimed, _, _ := s.Reserve(ctx, "k", time.Minute); !claimed {
+		t.Fatal("first reserve should claim the key")
+	}
+	if claimed, saved, _ := s.Reserve(ctx, "k", time.Minute); claimed || saved != nil {
+		t.Errorf("second reserve = %v, %q; want in progress", claimed, saved)
+	}
+
+	s.Save(ctx, "k", []byte("response"), time.Hour)
+	if claimed, saved, _ := s.Reserve(ctx, "k", time.Minute); claimed || string(saved) != "response" {
+		t.Errorf("reserve after save = %v, %q; want the saved response", claimed, saved)
+	}
+}
+
+func TestMemoryStoreReleaseAndExpiry(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemoryStore()
+	now := time.Now()
+	s.now = func() time.Time { return now }
+
+	s.Reserve(ctx, "released", time.Minute)
+	s.Release(ctx, "released")
+	if claimed, _, _ := s.Reserve(ctx, "released", time.Minute); !claimed {
+		t.Error("released key should be claimable again")
+	}
+
+	s.Reserve(ctx, "stuck", time.Minute)
+	s.Save(ctx, "saved", []byte("response"), time.Hour)
+	now = now.Add(2 * time.Minute)
+	if claimed, _, _ := s.Reserve(ctx, "stuck", time.Minute); !claimed {
+		t.Error("claim should expire after the lock TTL")
+	}
+	if claimed, _, _ := s.Reserve(ctx, "saved", time.Minute); claimed {
+		t.Error("saved response expired before its TTL")
+	}
+}
@@ -0,0 +1,55 @@
+// Package idempotency contains implementations of middleware.IdempotencyStore
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const keyPrefix = "auth:idempotency:"
+
+// RedisStore keeps idempotent responses in Redis, shared by all replicas. A
+// claim is an empty value set with SET NX, so only one request per key runs
+// even when retries reach different replicas.
+type RedisStore struct {
+	client *redis.Client
+}
+
+// NewRedisStore creates a new Redis-backed idempotency store
+func NewRedisStore(client *redis.Client) *RedisStore {
+	return &RedisStore{client: client}
+}
+
+// Reserve claims key unless it is already claimed or saved
+func (s *RedisStore) Reserve(ctx context.Context, key string, lockTTL time.Duration) (bool, []byte, error) {
+	claimed, err := s.client.SetNX(ctx, keyPrefix+key, "", lockTTL).Result()
+	if err != nil || claimed {
+		return claimed, nil, err
+	}
+
+	saved, err := s.client.Get(ctx, keyPrefix+key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		// Expired between SETNX and GET; the caller retries
+		return false, nil, nil
+	}
+	if err != nil {
+		return false, nil, err
+	}
+	if len(saved) == 0 {
+		return false, nil, nil
+	}
+	return false, saved, nil
+}
+
+// Save stores the response for key
+func (s *RedisStore) Save(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return s.client.Set(ctx, keyPrefix+key, value, ttl).Err()
+}
+
+// Release drops the claim on key
+func (s *RedisStore) Release(ctx context.Context, key string) error {
+	return s.client.Del(ctx, keyPrefix+key).Err()
+}
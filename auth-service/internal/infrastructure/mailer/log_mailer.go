@@ -0,0 +1,35 @@
+// Package mailer contains implementations of the use case Mailer port
+package mailer
+
+import (
+	"context"
+	"log"
+
+	"auth-service/internal/infrastructure/mailer/templates"
+)
+
+// LogMailer writes outgoing emails to the application log instead of sending
+// them. It is meant for local development; message bodies (which contain
+// single-use links) are only logged when logBody is true.
+type LogMailer struct {
+	logBody bool
+}
+
+// NewLogMailer creates a new log mailer
+func NewLogMailer(logBody bool) *LogMailer {
+	return &LogMailer{logBody: logBody}
+}
+
+// Send renders the template and logs the email
+func (m *LogMailer) Send(ctx context.Context, to, template string, data map[string]any) error {
+	msg, err := templates.Render(template, data)
+	if err != nil {
+		return err
+	}
+	if m.logBody {
+		log.Printf("📧 Email to %s: %s\n%s", to, msg.Subject, msg.Text)
+		return nil
+	}
+	log.Printf("📧 Email to %s: %s", to, msg.Subject)
+	return nil
+}
@@ -0,0 +1,52 @@
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func captureLog(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	t.Cleanup(func() { log.SetOutput(os.Stderr) })
+	return &buf
+}
+
+func testData() map[string]any {
+	return map[string]any{
+		"Username":  "jane",
+		"Link":      "https://app.example.com/verify-email?token=secret-token",
+		"ExpiresIn": time.Hour,
+	}
+}
+
+func TestLogMailerOmitsBodyByDefault(t *testing.T) {
+	buf := captureLog(t)
+
+	if err := NewLogMailer(false).Send(context.Background(), "jane@example.com", "verify_email", testData()); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "jane@example.com") {
+		t.Errorf("recipient missing from log: %q", buf.String())
+	}
+	if strings.Contains(buf.String(), "secret-token") {
+		t.Errorf("body must not be logged: %q", buf.String())
+	}
+}
+
+func TestLogMailerLogsBodyWhenEnabled(t *testing.T) {
+	buf := captureLog(t)
+
+	if err := NewLogMailer(true).Send(context.Background(), "jane@example.com", "verify_email", testData()); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "secret-token") {
+		t.Errorf("body missing from log: %q", buf.String())
+	}
+}
@@ -0,0 +1,56 @@
+package mailer
+
+import (
+	"context"
+	"sync"
+
+	"auth-service/internal/infrastructure/mailer/templates"
+)
+
+// SentMail is an email captured by MemoryMailer
+type SentMail struct {
+	To       string
+	Template string
+	Data     map[string]any
+}
+
+// MemoryMailer records emails instead of sending them, so tests can assert
+// which emails were queued. Templates are still rendered: an unknown
+// template or missing data fails Send like it would in production.
+type MemoryMailer struct {
+	mu   sync.Mutex
+	sent []SentMail
+}
+
+// NewMemoryMailer creates an empty memory mailer
+func NewMemoryMailer() *MemoryMailer {
+	return &MemoryMailer{}
+}
+
+// Send renders the template and records the email
+func (m *MemoryMailer) Send(ctx context.Context, to, template string, data map[string]any) error {
+	if _, err := templates.Render(template, data); err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.sent = append(m.sent, SentMail{To: to, Template: template, Data: data})
+	return nil
+}
+
+// Sent returns a copy of the recorded emails, oldest first
+func (m *MemoryMailer) Sent() []SentMail {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]SentMail(nil), m.sent...)
+}
+
+// Last returns the most recent email, if any
+func (m *MemoryMailer) Last() (SentMail, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if len(m.sent) == 0 {
+		return SentMail{}, false
+	}
+	return m.sent[len(m.sent)-1], true
+}
@@ -0,0 +1,29 @@
+package mailer
+
+import (
+	"context"
+	"testing"
+)
+
+func TestMemoryMailerRecordsEmails(t *testing.T) {
+	m := NewMemoryMailer()
+	if _, ok := m.Last(); ok {
+		t.Fatal("new mailer has emails")
+	}
+
+	if err := m.Send(context.Background(), "jane@example.com", "verify_email", testData()); err != nil {
+		t.Fatal(err)
+	}
+	last, ok := m.Last()
+	if !ok || last.To != "jane@example.com" || last.Template != "verify_email" || last.Data["Username"] != "jane" {
+		t.Errorf("last = %+v", last)
+	}
+
+	// Missing data fails like it would in production
+	if err := m.Send(context.Background(), "jane@example.com", "verify_email", map[string]any{"Username": "jane"}); err == nil {
+		t.Error("email with missing data was recorded")
+	}
+	if got := len(m.Sent()); got != 1 {
+		t.Errorf("sent %d emails, want 1", got)
+	}
+}
@@ -0,0 +1,102 @@
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"time"
+
+	"auth-service/internal/infrastructure/mailer/templates"
+)
+
+// SMTPMailer renders templates and sends them through an SMTP relay as
+// multipart/alternative messages with a plain-text and an HTML part
+type SMTPMailer struct {
+	addr string
+	host string
+	auth smtp.Auth
+	from string
+	// send is smtp.SendMail; replaced in tests
+	send func(addr string, a smtp.Auth, from string, to []string, msg []byte) error
+}
+
+// NewSMTPMailer creates a mailer for the relay at addr (host:port). PLAIN
+// authentication is used when username is set; net/smtp only sends
+// credentials over TLS or to localhost.
+func NewSMTPMailer(addr, username, password, from string) *SMTPMailer {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		host = addr
+	}
+	m := &SMTPMailer{addr: addr, host: host, from: from, send: smtp.SendMail}
+	if username != "" {
+		m.auth = smtp.PlainAuth("", username, password, host)
+	}
+	return m
+}
+
+// Send renders the template and delivers the message. smtp.SendMail does not
+// take a context, so ctx is only checked before connecting.
+func (m *SMTPMailer) Send(ctx context.Context, to, template string, data map[string]any) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	msg, err := templates.Render(template, data)
+	if err != nil {
+		return err
+	}
+	body, err := m.buildMessage(to, msg)
+	if err != nil {
+		return err
+	}
+	if err := m.send(m.addr, m.auth, m.from, []string{to}, body); err != nil {
+		return fmt.Errorf("send %s email: %w", template, err)
+	}
+	return nil
+}
+
+// buildMessage encodes the headers and both bodies as a MIME message
+func (m *SMTPMailer) buildMessage(to string, msg *templates.Message) ([]byte, error) {
+	var buf bytes.Buffer
+	parts := multipart.NewWriter(&buf)
+
+	fmt.Fprintf(&buf, "From: %s\r\n", m.from)
+	fmt.Fprintf(&buf, "To: %s\r\n", to)
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	fmt.Fprintf(&buf, "MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&buf, "Content-Type: multipart/alternative; boundary=%s\r\n\r\n", parts.Boundary())
+
+	for _, part := range []struct {
+		contentType string
+		body        string
+	}{
+		{"text/plain; charset=utf-8", msg.Text},
+		{"text/html; charset=utf-8", msg.HTML},
+	} {
+		w, err := parts.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		qp := quotedprintable.NewWriter(w)
+		if _, err := qp.Write([]byte(part.body)); err != nil {
+			return nil, err
+		}
+		if err := qp.Close(); err != nil {
+			return nil, err
+		}
+	}
+	if err := parts.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,77 @@
+package mailer
+
+import (
+	"context"
+	"errors"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/mail"
+	"net/smtp"
+	"strings"
+	"testing"
+)
+
+func TestSMTPMailerSendsMultipartMessage(t *testing.T) {
+	m := NewSMTPMailer("smtp.example.com:587", "user", "pass", "no-reply@example.com")
+	var gotAddr, gotFrom string
+	var gotTo []string
+	var raw []byte
+	m.send = func(addr string, a smtp.Auth, from string, to []string, msg []byte) error {
+		gotAddr, gotFrom, gotTo, raw = addr, from, to, msg
+		return nil
+	}
+
+	if err := m.Send(context.Background(), "jane@example.com", "verify_email", testData()); err != nil {
+		t.Fatal(err)
+	}
+	if gotAddr != "smtp.example.com:587" || gotFrom != "no-reply@example.com" || len(gotTo) != 1 || gotTo[0] != "jane@example.com" {
+		t.Errorf("envelope = %s %s %v", gotAddr, gotFrom, gotTo)
+	}
+
+	msg, err := mail.ReadMessage(strings.NewReader(string(raw)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if subject, _ := new(mime.WordDecoder).DecodeHeader(msg.Header.Get("Subject")); subject != "Verify your email address" {
+		t.Errorf("subject = %q", subject)
+	}
+	mediaType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if err != nil || mediaType != "multipart/alternative" {
+		t.Fatalf("content type = %q (%v)", mediaType, err)
+	}
+
+	reader := multipart.NewReader(msg.Body, params["boundary"])
+	var types []string
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := io.ReadAll(part)
+		if !strings.Contains(string(body), "secret-token") {
+			t.Errorf("%s part has no link: %q", part.Header.Get("Content-Type"), body)
+		}
+		types = append(types, part.Header.Get("Content-Type"))
+	}
+	if len(types) != 2 || !strings.HasPrefix(types[0], "text/plain") || !strings.HasPrefix(types[1], "text/html") {
+		t.Errorf("parts = %q, want text then html", types)
+	}
+}
+
+func TestSMTPMailerErrors(t *testing.T) {
+	m := NewSMTPMailer("localhost:25", "", "", "no-reply@example.com")
+	m.send = func(addr string, a smtp.Auth, from string, to []string, msg []byte) error {
+		return errors.New("connection refused")
+	}
+
+	if err := m.Send(context.Background(), "jane@example.com", "nope", nil); err == nil {
+		t.Error("unknown template was sent")
+	}
+	if err := m.Send(context.Background(), "jane@example.com", "verify_email", testData()); err == nil {
+		t.Error("relay error was swallowed")
+	}
+}
@@ -0,0 +1,61 @@
+// Package templates renders outgoing emails. Every template has a subject
+// and both a plain-text and an HTML body; the bodies live next to this file
+// as <name>.txt.tmpl and <name>.html.tmpl.
+package templates
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	texttemplate "text/template"
+)
+
+// ErrUnknownTemplate is returned for a template name without bodies
+var ErrUnknownTemplate = errors.New("unknown email template")
+
+//go:embed *.tmpl
+var files embed.FS
+
+// subjects lists every template by the name the use cases send
+// (usecase.MailTemplate* constants)
+var subjects = map[string]string{
+	"verify_email":       "Verify your email address",
+	"password_reset":     "Reset your password",
+	"magic_link":         "Your sign-in link",
+	"account_approved":   "Your account has been approved",
+	"new_sign_in":        "New sign-in to your account",
+	"account_locked":     "Your account was temporarily locked",
+	"temporary_password": "Your temporary password",
+}
+
+// Missing data keys fail rendering instead of printing "<no value>"
+var (
+	textBodies = texttemplate.Must(texttemplate.New("").Option("missingkey=error").ParseFS(files, "*.txt.tmpl"))
+	htmlBodies = htmltemplate.Must(htmltemplate.New("").Option("missingkey=error").ParseFS(files, "*.html.tmpl"))
+)
+
+// Message is a rendered email
+type Message struct {
+	Subject string
+	Text    string
+	HTML    string
+}
+
+// Render executes both bodies of the named template with data
+func Render(name string, data map[string]any) (*Message, error) {
+	subject, ok := subjects[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownTemplate, name)
+	}
+
+	var text, html bytes.Buffer
+	if err := textBodies.ExecuteTemplate(&text, name+".txt.tmpl", data); err != nil {
+		return nil, fmt.Errorf("render %s text body: %w", name, err)
+	}
+	if err := htmlBodies.ExecuteTemplate(&html, name+".html.tmpl", data); err != nil {
+		return nil, fmt.Errorf("render %s html body: %w", name, err)
+	}
+	return &Message{Subject: subject, Text: text.String(), HTML: html.String()}, nil
+}
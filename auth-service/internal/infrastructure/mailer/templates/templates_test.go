@@ -0,0 +1,61 @@
+package templates
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"auth-service/internal/application/usecase"
+)
+
+func TestRenderEveryUseCaseTemplate(t *testing.T) {
+	data := map[string]any{
+		"Username":    "jane",
+		"Link":        "https://app.example.com/verify-email?token=abc&x=<y>",
+		"ExpiresIn":   time.Hour,
+		"IPAddress":   "203.0.113.7",
+		"UserAgent":   "Mozilla/5.0",
+		"Time":        "Mon, 02 Jan 2006 15:04:05 UTC",
+		"LockedUntil": "Mon, 02 Jan 2006 15:19:05 UTC",
+		"Password":    "tmp-Secret",
+	}
+	for _, name := range []string{
+		usecase.MailTemplateVerifyEmail,
+		usecase.MailTemplatePasswordReset,
+		usecase.MailTemplateMagicLink,
+		usecase.MailTemplateAccountApproved,
+		usecase.MailTemplateNewSignIn,
+		usecase.MailTemplateAccountLocked,
+		usecase.MailTemplateTemporaryPassword,
+	} {
+		t.Run(name, func(t *testing.T) {
+			msg, err := Render(name, data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if msg.Subject == "" || !strings.Contains(msg.Text, "jane") || !strings.Contains(msg.HTML, "jane") {
+				t.Errorf("message = %+v", msg)
+			}
+		})
+	}
+}
+
+func TestRenderEscapesHTML(t *testing.T) {
+	msg, err := Render("account_approved", map[string]any{"Username": "<script>"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(msg.HTML, "<script>") || !strings.Contains(msg.Text, "<script>") {
+		t.Errorf("html = %q, text = %q", msg.HTML, msg.Text)
+	}
+}
+
+func TestRenderErrors(t *testing.T) {
+	if _, err := Render("nope", nil); !errors.Is(err, ErrUnknownTemplate) {
+		t.Errorf("unknown template: err = %v", err)
+	}
+	if _, err := Render("verify_email", map[string]any{"Username": "jane"}); err == nil {
+		t.Error("missing data rendered")
+	}
+}
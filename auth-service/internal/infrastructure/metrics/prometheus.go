@@ -0,0 +1,102 @@
+// Package metrics exposes Prometheus metrics for the service
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"auth-service/internal/application/usecase"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// loginFailureReasons are pre-registered so every series exists (at 0) from
+// startup and dashboards can build a complete heatmap
+var loginFailureReasons = []usecase.LoginFailureReason{
+	usecase.LoginFailureBadPassword,
+	usecase.LoginFailureUserNotFound,
+	usecase.LoginFailureInactive,
+	usecase.LoginFailureLocked,
+	usecase.LoginFailureUnverified,
+	usecase.LoginFailurePendingApproval,
+}
+
+// Metrics holds the service's Prometheus collectors on a private registry.
+// Labels are limited to low-cardinality values (route pattern, status,
+// failure reason); identifiers such as user, email or IP are never used as
+// labels, so the metrics cannot be used to enumerate accounts.
+type Metrics struct {
+	registry      *prometheus.Registry
+	httpRequests  *prometheus.CounterVec
+	httpDuration  *prometheus.HistogramVec
+	loginFailures *prometheus.CounterVec
+	userCache     *prometheus.CounterVec
+}
+
+// New creates and registers the service metrics
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		httpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "http_requests_total",
+			Help: "HTTP requests by method, route pattern and status code.",
+		}, []string{"method", "route", "status"}),
+		httpDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "http_request_duration_seconds",
+			Help:    "HTTP request latency by method and route pattern.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"method", "route"}),
+		loginFailures: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "auth_login_failures_total",
+			Help: "Failed logins by reason. Aggregated only; no per-account labels.",
+		}, []string{"reason"}),
+		userCache: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "auth_user_cache_lookups_total",
+			Help: "User cache lookups on token refresh by result (hit or miss).",
+		}, []string{"result"}),
+	}
+
+	m.registry.MustRegister(
+		m.httpRequests,
+		m.httpDuration,
+		m.loginFailures,
+		m.userCache,
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+	for _, reason := range loginFailureReasons {
+		m.loginFailures.WithLabelValues(string(reason))
+	}
+	m.userCache.WithLabelValues("hit")
+	m.userCache.WithLabelValues("miss")
+	return m
+}
+
+// LoginFailed implements usecase.LoginMetrics
+func (m *Metrics) LoginFailed(reason usecase.LoginFailureReason) {
+	m.loginFailures.WithLabelValues(string(reason)).Inc()
+}
+
+// UserCacheLookup implements usercache.Metrics
+func (m *Metrics) UserCacheLookup(hit bool) {
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	m.userCache.WithLabelValues(result).Inc()
+}
+
+// ObserveHTTPRequest records one handled request. route must be the route
+// pattern (e.g. "/api/admin/users/:id/approve"), not the raw path.
+func (m *Metrics) ObserveHTTPRequest(method, route string, status int, duration time.Duration) {
+	m.httpRequests.WithLabelValues(method, route, strconv.Itoa(status)).Inc()
+	m.httpDuration.WithLabelValues(method, route).Observe(duration.Seconds())
+}
+
+// Handler serves the metrics in the Prometheus exposition format
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
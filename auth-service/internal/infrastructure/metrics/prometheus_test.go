@@ -0,0 +1,60 @@
+package metrics
+
+import (
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"auth-service/internal/application/usecase"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestLoginFailuresCountedByReason(t *testing.T) {
+	m := New()
+	m.LoginFailed(usecase.LoginFailureBadPassword)
+	m.LoginFailed(usecase.LoginFailureBadPassword)
+	m.LoginFailed(usecase.LoginFailureUserNotFound)
+
+	if got := testutil.ToFloat64(m.loginFailures.WithLabelValues("bad_password")); got != 2 {
+		t.Errorf("bad_password = %v, want 2", got)
+	}
+	if got := testutil.ToFloat64(m.loginFailures.WithLabelValues("user_not_found")); got != 1 {
+		t.Errorf("user_not_found = %v, want 1", got)
+	}
+}
+
+func TestHandlerExposesAllReasonsWithoutAccountLabels(t *testing.T) {
+	m := New()
+	m.ObserveHTTPRequest("POST", "/api/auth/login", 401, 10*time.Millisecond)
+
+	w := httptest.NewRecorder()
+	m.Handler().ServeHTTP(w, httptest.NewRequest("GET", "/metrics", nil))
+	body, _ := io.ReadAll(w.Body)
+	out := string(body)
+
+	for _, reason := range loginFailureReasons {
+		if !strings.Contains(out, `auth_login_failures_total{reason="`+string(reason)+`"} 0`) {
+			t.Errorf("missing zero series for %s", reason)
+		}
+	}
+	if !strings.Contains(out, `http_requests_total{method="POST",route="/api/auth/login",status="401"} 1`) {
+		t.Error("missing http request series")
+	}
+}
+
+func TestUserCacheLookupsCountedByResult(t *testing.T) {
+	m := New()
+	m.UserCacheLookup(true)
+	m.UserCacheLookup(true)
+	m.UserCacheLookup(false)
+
+	if got := testutil.ToFloat64(m.userCache.WithLabelValues("hit")); got != 2 {
+		t.Errorf("hit = %v, want 2", got)
+	}
+	if got := testutil.ToFloat64(m.userCache.WithLabelValues("miss")); got != 1 {
+		t.Errorf("miss = %v, want 1", got)
+	}
+}
@@ -0,0 +1,77 @@
+package oauth
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"auth-service/config"
+	"auth-service/internal/application/usecase"
+)
+
+// GitHub OAuth app endpoints
+const (
+	githubAuthURL   = "https://github.com/login/oauth/authorize"
+	githubTokenURL  = "https://github.com/login/oauth/access_token"
+	githubAPIURL    = "https://api.github.com"
+	githubUserScope = "read:user user:email"
+)
+
+// GitHub runs the authorization code flow against GitHub
+type GitHub struct {
+	codeFlow
+	apiURL string
+}
+
+// NewGitHub creates a GitHub OAuth2 client
+func NewGitHub(cfg config.OAuthClientConfig) *GitHub {
+	return &GitHub{
+		codeFlow: newCodeFlow(cfg, githubAuthURL, githubTokenURL),
+		apiURL:   githubAPIURL,
+	}
+}
+
+// AuthURL returns GitHub's authorization page URL
+func (g *GitHub) AuthURL(state string) string {
+	return g.authCodeURL(state, githubUserScope)
+}
+
+// FetchUser returns the profile of the user the access token belongs to.
+// The email comes from /user/emails: the public profile email may be empty
+// and carries no verification flag.
+func (g *GitHub) FetchUser(ctx context.Context, accessToken string) (*usecase.ExternalUser, error) {
+	var user struct {
+		ID    int64  `json:"id"`
+		Login string `json:"login"`
+		Name  string `json:"name"`
+	}
+	if err := g.getJSON(ctx, g.apiURL+"/user", accessToken, &user); err != nil {
+		return nil, err
+	}
+	if user.ID == 0 {
+		return nil, fmt.Errorf("github user: missing id")
+	}
+
+	var emails []struct {
+		Email    string `json:"email"`
+		Primary  bool   `json:"primary"`
+		Verified bool   `json:"verified"`
+	}
+	if err := g.getJSON(ctx, g.apiURL+"/user/emails", accessToken, &emails); err != nil {
+		return nil, err
+	}
+
+	external := &usecase.ExternalUser{
+		ProviderID: strconv.FormatInt(user.ID, 10),
+		Username:   user.Login,
+	}
+	external.FirstName, external.LastName, _ = strings.Cut(user.Name, " ")
+	for _, email := range emails {
+		if email.Primary {
+			external.Email = email.Email
+			external.EmailVerified = email.Verified
+		}
+	}
+	return external, nil
+}
@@ -0,0 +1,66 @@
+package oauth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"auth-service/config"
+)
+
+func newTestGitHub(t *testing.T) *GitHub {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/login/oauth/access_token", func(w http.ResponseWriter, r *http.Request) {
+		// GitHub reports a bad code with 200 and an error field
+		if err := r.ParseForm(); err != nil || r.PostForm.Get("code") != "good-code" {
+			json.NewEncoder(w).Encode(map[string]string{"error": "bad_verification_code"})
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]string{"access_token": "github-token"})
+	})
+	mux.HandleFunc("/user", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer github-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"id": 583231, "login": "octojane", "name": "Jane Doe", "email": nil})
+	})
+	mux.HandleFunc("/user/emails", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode([]map[string]interface{}{
+			{"email": "old@example.com", "primary": false, "verified": true},
+			{"email": "jane@example.com", "primary": true, "verified": true},
+		})
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	g := NewGitHub(config.OAuthClientConfig{ClientID: "client", ClientSecret: "secret"})
+	g.tokenURL = server.URL + "/login/oauth/access_token"
+	g.apiURL = server.URL
+	return g
+}
+
+func TestGitHubExchangeAndFetchUser(t *testing.T) {
+	g := newTestGitHub(t)
+
+	token, err := g.Exchange(context.Background(), "good-code")
+	if err != nil {
+		t.Fatal(err)
+	}
+	user, err := g.FetchUser(context.Background(), token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.ProviderID != "583231" || user.Username != "octojane" || user.Email != "jane@example.com" ||
+		!user.EmailVerified || user.FirstName != "Jane" || user.LastName != "Doe" {
+		t.Errorf("user = %+v", user)
+	}
+
+	if _, err := g.Exchange(context.Background(), "bad-code"); !errors.Is(err, ErrExchangeFailed) {
+		t.Errorf("bad code: got %v, want ErrExchangeFailed", err)
+	}
+}
@@ -0,0 +1,61 @@
+package oauth
+
+import (
+	"context"
+	"fmt"
+
+	"auth-service/config"
+	"auth-service/internal/application/usecase"
+)
+
+// Google OAuth2 / OpenID Connect endpoints
+const (
+	googleAuthURL     = "https://accounts.google.com/o/oauth2/v2/auth"
+	googleTokenURL    = "https://oauth2.googleapis.com/token"
+	googleUserInfoURL = "https://openidconnect.googleapis.com/v1/userinfo"
+)
+
+// Google runs the authorization code flow against Google
+type Google struct {
+	codeFlow
+	userInfoURL string
+}
+
+// NewGoogle creates a Google OAuth2 client
+func NewGoogle(cfg config.OAuthClientConfig) *Google {
+	return &Google{
+		codeFlow:    newCodeFlow(cfg, googleAuthURL, googleTokenURL),
+		userInfoURL: googleUserInfoURL,
+	}
+}
+
+// AuthURL returns Google's consent screen URL
+func (g *Google) AuthURL(state string) string {
+	return g.authCodeURL(state, "openid email profile")
+}
+
+// FetchUser returns the profile of the user the access token belongs to
+func (g *Google) FetchUser(ctx context.Context, accessToken string) (*usecase.ExternalUser, error) {
+	var user struct {
+		Sub           string `json:"sub"`
+		Email         string `json:"email"`
+		EmailVerified bool   `json:"email_verified"`
+		GivenName     string `json:"given_name"`
+		FamilyName    string `json:"family_name"`
+	}
+	if err := g.getJSON(ctx, g.userInfoURL, accessToken, &user); err != nil {
+		return nil, err
+	}
+	if user.Sub == "" {
+		return nil, fmt.Errorf("google userinfo: missing subject")
+	}
+
+	// Google has no usernames; one is derived from the email when needed
+	return &usecase.ExternalUser{
+		ProviderID:    user.Sub,
+		Email:         user.Email,
+		EmailVerified: user.EmailVerified,
+		FirstName:     user.GivenName,
+		LastName:      user.FamilyName,
+	}, nil
+}
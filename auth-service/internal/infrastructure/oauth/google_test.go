@@ -0,0 +1,76 @@
+package oauth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"auth-service/config"
+)
+
+func newTestGoogle(t *testing.T) *Google {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil || r.PostForm.Get("code") != "good-code" ||
+			r.PostForm.Get("client_secret") != "secret" || r.PostForm.Get("grant_type") != "authorization_code" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]string{"access_token": "google-token"})
+	})
+	mux.HandleFunc("/userinfo", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer google-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"sub": "1234", "email": "jane@example.com", "email_verified": true, "given_name": "Jane",
+		})
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	g := NewGoogle(config.OAuthClientConfig{ClientID: "client", ClientSecret: "secret", RedirectURL: "https://app.example.com/cb"})
+	g.tokenURL = server.URL + "/token"
+	g.userInfoURL = server.URL + "/userinfo"
+	return g
+}
+
+func TestGoogleAuthURL(t *testing.T) {
+	g := NewGoogle(config.OAuthClientConfig{ClientID: "client", RedirectURL: "https://app.example.com/cb"})
+
+	u, err := url.Parse(g.AuthURL("state-123"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	q := u.Query()
+	if q.Get("state") != "state-123" || q.Get("client_id") != "client" ||
+		q.Get("redirect_uri") != "https://app.example.com/cb" || q.Get("response_type") != "code" {
+		t.Errorf("auth URL = %s", u)
+	}
+}
+
+func TestGoogleExchangeAndFetchUser(t *testing.T) {
+	g := newTestGoogle(t)
+
+	token, err := g.Exchange(context.Background(), "good-code")
+	if err != nil {
+		t.Fatal(err)
+	}
+	user, err := g.FetchUser(context.Background(), token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.ProviderID != "1234" || user.Email != "jane@example.com" || !user.EmailVerified || user.FirstName != "Jane" {
+		t.Errorf("user = %+v", user)
+	}
+
+	if _, err := g.Exchange(context.Background(), "bad-code"); !errors.Is(err, ErrExchangeFailed) {
+		t.Errorf("bad code: got %v, want ErrExchangeFailed", err)
+	}
+}
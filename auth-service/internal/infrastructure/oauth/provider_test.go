@@ -0,0 +1,14 @@
+package oauth
+
+import (
+	"testing"
+
+	"auth-service/config"
+)
+
+func TestProvidersSkipsUnconfigured(t *testing.T) {
+	providers := Providers(config.OAuthConfig{GitHub: config.OAuthClientConfig{ClientID: "client"}})
+	if _, ok := providers["github"]; !ok || len(providers) != 1 {
+		t.Errorf("providers = %v, want only github", providers)
+	}
+}
@@ -0,0 +1,57 @@
+package passkey
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type memorySession struct {
+	data      []byte
+	expiresAt time.Time
+}
+
+// MemorySessionStore keeps pending WebAuthn ceremonies in process memory. It
+// is meant for tests and single-instance development setups; sessions are
+// not shared between replicas.
+type MemorySessionStore struct {
+	mu       sync.Mutex
+	sessions map[string]memorySession
+}
+
+// NewMemorySessionStore creates a new in-memory passkey session store
+func NewMemorySessionStore() *MemorySessionStore {
+	return &MemorySessionStore{sessions: make(map[string]memorySession)}
+}
+
+// Save stores a ceremony's session data for ttl
+func (s *MemorySessionStore) Save(ctx context.Context, id string, data []byte, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	// Drop abandoned ceremonies so the map stays bounded
+	for key, session := range s.sessions {
+		if !now.Before(session.expiresAt) {
+			delete(s.sessions, key)
+		}
+	}
+	s.sessions[id] = memorySession{data: data, expiresAt: now.Add(ttl)}
+	return nil
+}
+
+// Take returns and deletes a session; a missing or expired session returns nil
+func (s *MemorySessionStore) Take(ctx context.Context, id string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	session, ok := s.sessions[id]
+	if !ok {
+		return nil, nil
+	}
+	delete(s.sessions, id)
+	if !time.Now().Before(session.expiresAt) {
+		return nil, nil
+	}
+	return session.data, nil
+}
@@ -0,0 +1,30 @@
+package passkey
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMemorySessionStoreTakeIsSingleUse(t *testing.T) {
+	ctx := context.Background()
+	s := NewMemorySessionStore()
+
+	if err := s.Save(ctx, "pending", []byte("challenge"), time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Save(ctx, "expired", []byte("challenge"), -time.Second); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := s.Take(ctx, "pending")
+	if err != nil || string(data) != "challenge" {
+		t.Fatalf("Take(pending) = %q, %v, want challenge", data, err)
+	}
+	for _, id := range []string{"pending", "expired", "unknown"} {
+		data, err := s.Take(ctx, id)
+		if err != nil || data != nil {
+			t.Errorf("Take(%q) = %q, %v, want nil", id, data, err)
+		}
+	}
+}
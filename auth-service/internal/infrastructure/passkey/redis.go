@@ -0,0 +1,38 @@
+// Package passkey contains implementations of usecase.PasskeySessionStore
+package passkey
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const keyPrefix = "auth:passkey:"
+
+// RedisSessionStore keeps pending WebAuthn ceremonies in Redis, so the
+// finish request may reach a different replica than the begin request
+type RedisSessionStore struct {
+	client *redis.Client
+}
+
+// NewRedisSessionStore creates a new Redis-backed passkey session store
+func NewRedisSessionStore(client *redis.Client) *RedisSessionStore {
+	return &RedisSessionStore{client: client}
+}
+
+// Save stores a ceremony's session data for ttl
+func (s *RedisSessionStore) Save(ctx context.Context, id string, data []byte, ttl time.Duration) error {
+	return s.client.Set(ctx, keyPrefix+id, data, ttl).Err()
+}
+
+// Take returns and deletes a session with GETDEL, so a challenge can be
+// answered only once. A missing or expired session returns nil.
+func (s *RedisSessionStore) Take(ctx context.Context, id string) ([]byte, error) {
+	data, err := s.client.GetDel(ctx, keyPrefix+id).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	return data, err
+}
@@ -0,0 +1,53 @@
+package ratelimit
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+const keyPrefix = "auth:ratelimit:"
+
+// slidingWindowScript counts the requests of the last window in a sorted set
+// (score = request time in ms) and records the new one if it fits. It runs
+// atomically, so concurrent requests on several replicas cannot overshoot.
+// Returns {allowed, retry after in ms}.
+var slidingWindowScript = redis.NewScript(`
+local key, now, window, limit, member = KEYS[1], tonumber(ARGV[1]), tonumber(ARGV[2]), tonumber(ARGV[3]), ARGV[4]
+redis.call("ZREMRANGEBYSCORE", key, 0, now - window)
+if redis.call("ZCARD", key) < limit then
+	redis.call("ZADD", key, now, member)
+	redis.call("PEXPIRE", key, window)
+	return {1, 0}
+end
+local oldest = redis.call("ZRANGE", key, 0, 0, "WITHSCORES")
+return {0, tonumber(oldest[2]) + window - now}
+`)
+
+// RedisSlidingWindow is a rate limiter shared by all replicas. It allows at
+// most limit requests in any window-long period.
+type RedisSlidingWindow struct {
+	client *redis.Client
+}
+
+// NewRedisSlidingWindow creates a new Redis-backed sliding window limiter
+func NewRedisSlidingWindow(client *redis.Client) *RedisSlidingWindow {
+	return &RedisSlidingWindow{client: client}
+}
+
+// Allow records a request for key if it is within the limit
+func (l *RedisSlidingWindow) Allow(ctx context.Context, key string, limit int, window time.Duration) (bool, time.Duration, error) {
+	now := time.Now().UnixMilli()
+	result, err := slidingWindowScript.Run(ctx, l.client, []string{keyPrefix + key},
+		now, window.Milliseconds(), limit, strconv.FormatInt(now, 10)+"-"+uuid.NewString()).Int64Slice()
+	if err != nil {
+		return false, 0, err
+	}
+	if result[0] == 1 {
+		return true, 0, nil
+	}
+	return false, time.Duration(result[1]) * time.Millisecond, nil
+}
@@ -0,0 +1,76 @@
+// Package ratelimit contains implementations of middleware.RateLimiter
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// TokenBucket is an in-process rate limiter. Each key gets a bucket of limit
+// tokens that refills continuously at limit per window, so short bursts are
+// allowed and the long-run rate is capped. Buckets are not shared between
+// replicas; use RedisSlidingWindow when running more than one instance.
+type TokenBucket struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+	now       func() time.Time // replaced in tests
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// NewTokenBucket creates a new in-memory token bucket limiter
+func NewTokenBucket() *TokenBucket {
+	return &TokenBucket{buckets: map[string]*bucket{}, now: time.Now}
+}
+
+// Allow takes a token from key's bucket. When the bucket is empty it reports
+// how long until the next token is available.
+func (l *TokenBucket) Allow(ctx context.Context, key string, limit int, window time.Duration) (bool, time.Duration, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	l.sweep(now, window)
+
+	capacity := float64(limit)
+	rate := capacity / window.Seconds() // tokens per second
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: capacity, last: now}
+		l.buckets[key] = b
+	}
+
+	// Refill for the time since the last request, up to the capacity
+	b.tokens += now.Sub(b.last).Seconds() * rate
+	if b.tokens > capacity {
+		b.tokens = capacity
+	}
+	b.last = now
+
+	if b.tokens < 1 {
+		wait := time.Duration((1 - b.tokens) / rate * float64(time.Second))
+		return false, wait, nil
+	}
+	b.tokens--
+	return true, 0, nil
+}
+
+// sweep drops buckets idle for a whole window: they would be full again, so
+// forgetting them changes nothing and keeps the map bounded
+func (l *TokenBucket) sweep(now time.Time, window time.Duration) {
+	if now.Sub(l.lastSweep) < window {
+		return
+	}
+	for key, b := range l.buckets {
+		if now.Sub(b.last) >= window {
+			delete(l.buckets, key)
+		}
+	}
+	l.lastSweep = now
+}
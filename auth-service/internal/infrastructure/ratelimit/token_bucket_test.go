@@ -0,0 +1,82 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func newTestBucket() (*TokenBucket, *time.Time) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	l := NewTokenBucket()
+	l.now = func() time.Time { return now }
+	return l, &now
+}
+
+func TestTokenBucketRefill(t *testing.T) {
+	l, now := newTestBucket()
+	ctx := context.Background()
+
+	// A full bucket allows a burst of limit requests
+	for i := 0; i < 3; i++ {
+		if ok, _, _ := l.Allow(ctx, "ip:1", 3, 3*time.Second); !ok {
+			t.Fatalf("request %d rejected", i+1)
+		}
+	}
+	ok, retryAfter, _ := l.Allow(ctx, "ip:1", 3, 3*time.Second)
+	if ok || retryAfter != time.Second {
+		t.Fatalf("4th request: allowed = %v, retryAfter = %v, want rejected with 1s", ok, retryAfter)
+	}
+
+	// Half a token is not enough
+	*now = now.Add(500 * time.Millisecond)
+	ok, retryAfter, _ = l.Allow(ctx, "ip:1", 3, 3*time.Second)
+	if ok || retryAfter != 500*time.Millisecond {
+		t.Fatalf("after 0.5s: allowed = %v, retryAfter = %v", ok, retryAfter)
+	}
+
+	// One token per second comes back
+	*now = now.Add(500 * time.Millisecond)
+	if ok, _, _ := l.Allow(ctx, "ip:1", 3, 3*time.Second); !ok {
+		t.Fatal("request after refill rejected")
+	}
+	if ok, _, _ := l.Allow(ctx, "ip:1", 3, 3*time.Second); ok {
+		t.Fatal("refill granted more than one token")
+	}
+
+	// A long pause refills up to the capacity, not beyond
+	*now = now.Add(time.Hour)
+	for i := 0; i < 3; i++ {
+		if ok, _, _ := l.Allow(ctx, "ip:1", 3, 3*time.Second); !ok {
+			t.Fatalf("request %d after pause rejected", i+1)
+		}
+	}
+	if ok, _, _ := l.Allow(ctx, "ip:1", 3, 3*time.Second); ok {
+		t.Fatal("bucket refilled beyond its capacity")
+	}
+}
+
+func TestTokenBucketKeysAreIndependent(t *testing.T) {
+	l, _ := newTestBucket()
+	ctx := context.Background()
+
+	if ok, _, _ := l.Allow(ctx, "ip:1", 1, time.Minute); !ok {
+		t.Fatal("first key rejected")
+	}
+	if ok, _, _ := l.Allow(ctx, "ip:2", 1, time.Minute); !ok {
+		t.Fatal("second key rejected after first key was used")
+	}
+}
+
+func TestTokenBucketSweepsIdleBuckets(t *testing.T) {
+	l, now := newTestBucket()
+	ctx := context.Background()
+
+	l.Allow(ctx, "ip:1", 5, time.Minute)
+	*now = now.Add(time.Minute)
+	l.Allow(ctx, "ip:2", 5, time.Minute)
+
+	if _, ok := l.buckets["ip:1"]; ok || len(l.buckets) != 1 {
+		t.Errorf("buckets = %v, want only ip:2", l.buckets)
+	}
+}
@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"auth-service/internal/domain"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// APIKeyRepositoryImpl implements the APIKeyRepository interface
+type APIKeyRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewAPIKeyRepository creates a new API key repository
+func NewAPIKeyRepository(db *gorm.DB) domain.APIKeyRepository {
+	return &APIKeyRepositoryImpl{db: db}
+}
+
+func (r *APIKeyRepositoryImpl) Create(ctx context.Context, key *domain.APIKey) error {
+	return dbFromContext(ctx, r.db).Create(key).Error
+}
+
+func (r *APIKeyRepositoryImpl) GetByID(ctx context.Context, id uuid.UUID) (*domain.APIKey, error) {
+	var key domain.APIKey
+	if err := dbFromContext(ctx, r.db).Where("id = ?", id).First(&key).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &key, nil
+}
+
+func (r *APIKeyRepositoryImpl) GetByHash(ctx context.Context, keyHash string) (*domain.APIKey, error) {
+	var key domain.APIKey
+	if err := dbFromContext(ctx, r.db).Where("key_hash = ?", keyHash).First(&key).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &key, nil
+}
+
+func (r *APIKeyRepositoryImpl) List(ctx context.Context, orgID uuid.UUID) ([]*domain.APIKey, error) {
+	var keys []*domain.APIKey
+	err := dbFromContext(ctx, r.db).Where("organization_id = ?", orgID).Order("created_at DESC, id").Find(&keys).Error
+	return keys, err
+}
+
+func (r *APIKeyRepositoryImpl) Revoke(ctx context.Context, id uuid.UUID, at time.Time) error {
+	result := dbFromContext(ctx, r.db).Model(&domain.APIKey{}).
+		Where("id = ? AND revoked_at IS NULL", id).Update("revoked_at", at)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
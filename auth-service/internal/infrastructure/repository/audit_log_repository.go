@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"context"
+
+	"auth-service/internal/domain"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// AuditLogRepositoryImpl implements the AuditLogRepository interface
+type AuditLogRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewAuditLogRepository creates a new audit log repository
+func NewAuditLogRepository(db *gorm.DB) domain.AuditLogRepository {
+	return &AuditLogRepositoryImpl{db: db}
+}
+
+func (r *AuditLogRepositoryImpl) Create(ctx context.Context, log *domain.AuditLog) error {
+	return dbFromContext(ctx, r.db).Create(log).Error
+}
+
+func (r *AuditLogRepositoryImpl) List(ctx context.Context, filter domain.AuditLogFilter, page, pageSize int) ([]*domain.AuditLog, int64, error) {
+	query := dbFromContext(ctx, r.db).Model(&domain.AuditLog{})
+	if filter.UserID != uuid.Nil {
+		query = query.Where("actor_id = ? OR target_id = ?", filter.UserID, filter.UserID)
+	}
+	if filter.Action != "" {
+		query = query.Where("action = ?", filter.Action)
+	}
+	if filter.OrganizationID != uuid.Nil {
+		// Deleted accounts keep their row (see User.DeletedAt), so their history stays visible
+		members := dbFromContext(ctx, r.db).Model(&domain.User{}).Select("id").Where("organization_id = ?", filter.OrganizationID)
+		query = query.Where("actor_id IN (?) OR target_id IN (?)", members, members)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var logs []*domain.AuditLog
+	err := query.Order("created_at DESC, id").Offset((page - 1) * pageSize).Limit(pageSize).Find(&logs).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return logs, total, nil
+}
@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"auth-service/internal/domain"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// CredentialRepositoryImpl implements the CredentialRepository interface
+type CredentialRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewCredentialRepository creates a new WebAuthn credential repository
+func NewCredentialRepository(db *gorm.DB) domain.CredentialRepository {
+	return &CredentialRepositoryImpl{db: db}
+}
+
+func (r *CredentialRepositoryImpl) Create(ctx context.Context, credential *domain.Credential) error {
+	return dbFromContext(ctx, r.db).Create(credential).Error
+}
+
+func (r *CredentialRepositoryImpl) GetByUserID(ctx context.Context, userID uuid.UUID) ([]*domain.Credential, error) {
+	var credentials []*domain.Credential
+	err := dbFromContext(ctx, r.db).Where("user_id = ?", userID).Order("created_at ASC").Find(&credentials).Error
+	return credentials, err
+}
+
+func (r *CredentialRepositoryImpl) GetByCredentialID(ctx context.Context, credentialID []byte) (*domain.Credential, error) {
+	var credential domain.Credential
+	err := dbFromContext(ctx, r.db).Where("credential_id = ?", credentialID).First(&credential).Error
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return &credential, nil
+}
+
+func (r *CredentialRepositoryImpl) UpdateSignCount(ctx context.Context, id uuid.UUID, signCount uint32) error {
+	return dbFromContext(ctx, r.db).Model(&domain.Credential{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{"sign_count": signCount, "last_used_at": time.Now()}).Error
+}
@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"auth-service/internal/domain"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// InviteRepositoryImpl implements the InviteRepository interface
+type InviteRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewInviteRepository creates a new invite repository
+func NewInviteRepository(db *gorm.DB) domain.InviteRepository {
+	return &InviteRepositoryImpl{db: db}
+}
+
+func (r *InviteRepositoryImpl) Create(ctx context.Context, invite *domain.Invite) error {
+	return dbFromContext(ctx, r.db).Create(invite).Error
+}
+
+func (r *InviteRepositoryImpl) GetByTokenHash(ctx context.Context, tokenHash string) (*domain.Invite, error) {
+	var invite domain.Invite
+	err := dbFromContext(ctx, r.db).Where("token_hash = ?", tokenHash).First(&invite).Error
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return &invite, nil
+}
+
+// Consume uses a single conditional UPDATE so an invite can be used only once
+func (r *InviteRepositoryImpl) Consume(ctx context.Context, tokenHash string) (*domain.Invite, error) {
+	var invite domain.Invite
+	now := time.Now()
+	result := dbFromContext(ctx, r.db).Model(&invite).
+		Clauses(clause.Returning{}).
+		Where("token_hash = ? AND used_at IS NULL AND expires_at > ?", tokenHash, now).
+		Update("used_at", now)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, domain.ErrNotFound
+	}
+	return &invite, nil
+}
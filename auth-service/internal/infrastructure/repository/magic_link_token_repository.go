@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"auth-service/internal/domain"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// MagicLinkTokenRepositoryImpl implements the MagicLinkTokenRepository interface
+type MagicLinkTokenRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewMagicLinkTokenRepository creates a new magic link token repository
+func NewMagicLinkTokenRepository(db *gorm.DB) domain.MagicLinkTokenRepository {
+	return &MagicLinkTokenRepositoryImpl{db: db}
+}
+
+func (r *MagicLinkTokenRepositoryImpl) Create(ctx context.Context, token *domain.MagicLinkToken) error {
+	return dbFromContext(ctx, r.db).Create(token).Error
+}
+
+// Consume uses a single conditional UPDATE so a token can be used only once
+func (r *MagicLinkTokenRepositoryImpl) Consume(ctx context.Context, tokenHash string) (*domain.MagicLinkToken, error) {
+	var token domain.MagicLinkToken
+	now := time.Now()
+	result := dbFromContext(ctx, r.db).Model(&token).
+		Clauses(clause.Returning{}).
+		Where("token_hash = ? AND used_at IS NULL AND expires_at > ?", tokenHash, now).
+		Update("used_at", now)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, domain.ErrNotFound
+	}
+	return &token, nil
+}
+
+func (r *MagicLinkTokenRepositoryImpl) DeleteByUserID(ctx context.Context, userID uuid.UUID) error {
+	return dbFromContext(ctx, r.db).Where("user_id = ?", userID).Delete(&domain.MagicLinkToken{}).Error
+}
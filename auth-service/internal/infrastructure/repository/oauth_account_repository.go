@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"context"
+
+	"auth-service/internal/domain"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// OAuthAccountRepositoryImpl implements the OAuthAccountRepository interface
+type OAuthAccountRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewOAuthAccountRepository creates a new OAuth account repository
+func NewOAuthAccountRepository(db *gorm.DB) domain.OAuthAccountRepository {
+	return &OAuthAccountRepositoryImpl{db: db}
+}
+
+func (r *OAuthAccountRepositoryImpl) Create(ctx context.Context, account *domain.OAuthAccount) error {
+	return dbFromContext(ctx, r.db).Create(account).Error
+}
+
+func (r *OAuthAccountRepositoryImpl) GetByProviderUserID(ctx context.Context, provider, providerUserID string) (*domain.OAuthAccount, error) {
+	var account domain.OAuthAccount
+	err := dbFromContext(ctx, r.db).Where("provider = ? AND provider_user_id = ?", provider, providerUserID).First(&account).Error
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return &account, nil
+}
+
+func (r *OAuthAccountRepositoryImpl) GetByUserID(ctx context.Context, userID uuid.UUID) ([]*domain.OAuthAccount, error) {
+	var accounts []*domain.OAuthAccount
+	err := dbFromContext(ctx, r.db).Where("user_id = ?", userID).Order("created_at").Find(&accounts).Error
+	return accounts, err
+}
+
+func (r *OAuthAccountRepositoryImpl) Delete(ctx context.Context, userID uuid.UUID, provider string) error {
+	result := dbFromContext(ctx, r.db).Where("user_id = ? AND provider = ?", userID, provider).Delete(&domain.OAuthAccount{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"context"
+
+	"auth-service/internal/domain"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// OrganizationRepositoryImpl implements the OrganizationRepository interface
+type OrganizationRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewOrganizationRepository creates a new organization repository
+func NewOrganizationRepository(db *gorm.DB) domain.OrganizationRepository {
+	return &OrganizationRepositoryImpl{db: db}
+}
+
+func (r *OrganizationRepositoryImpl) Create(ctx context.Context, org *domain.Organization) error {
+	return dbFromContext(ctx, r.db).Create(org).Error
+}
+
+func (r *OrganizationRepositoryImpl) GetByID(ctx context.Context, id uuid.UUID) (*domain.Organization, error) {
+	var org domain.Organization
+	if err := dbFromContext(ctx, r.db).Where("id = ?", id).First(&org).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &org, nil
+}
+
+func (r *OrganizationRepositoryImpl) GetBySlug(ctx context.Context, slug string) (*domain.Organization, error) {
+	var org domain.Organization
+	if err := dbFromContext(ctx, r.db).Where("slug = ?", slug).First(&org).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &org, nil
+}
@@ -0,0 +1,42 @@
+package repository
+
+import (
+	"context"
+
+	"auth-service/internal/domain"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// PasswordHistoryRepositoryImpl implements the PasswordHistoryRepository interface
+type PasswordHistoryRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewPasswordHistoryRepository creates a new password history repository
+func NewPasswordHistoryRepository(db *gorm.DB) domain.PasswordHistoryRepository {
+	return &PasswordHistoryRepositoryImpl{db: db}
+}
+
+func (r *PasswordHistoryRepositoryImpl) Create(ctx context.Context, entry *domain.PasswordHistory) error {
+	return dbFromContext(ctx, r.db).Create(entry).Error
+}
+
+func (r *PasswordHistoryRepositoryImpl) ListRecent(ctx context.Context, userID uuid.UUID, limit int) ([]*domain.PasswordHistory, error) {
+	var entries []*domain.PasswordHistory
+	err := dbFromContext(ctx, r.db).Where("user_id = ?", userID).
+		Order("created_at DESC, id").Limit(limit).Find(&entries).Error
+	if err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// Prune keeps the entries ListRecent(keep) would return and deletes the rest
+func (r *PasswordHistoryRepositoryImpl) Prune(ctx context.Context, userID uuid.UUID, keep int) error {
+	db := dbFromContext(ctx, r.db)
+	newest := db.Model(&domain.PasswordHistory{}).Select("id").
+		Where("user_id = ?", userID).Order("created_at DESC, id").Limit(keep)
+	return db.Where("user_id = ? AND id NOT IN (?)", userID, newest).Delete(&domain.PasswordHistory{}).Error
+}
@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"auth-service/internal/domain"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// PasswordResetTokenRepositoryImpl implements the PasswordResetTokenRepository interface
+type PasswordResetTokenRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewPasswordResetTokenRepository creates a new password reset token repository
+func NewPasswordResetTokenRepository(db *gorm.DB) domain.PasswordResetTokenRepository {
+	return &PasswordResetTokenRepositoryImpl{db: db}
+}
+
+func (r *PasswordResetTokenRepositoryImpl) Create(ctx context.Context, token *domain.PasswordResetToken) error {
+	return dbFromContext(ctx, r.db).Create(token).Error
+}
+
+func (r *PasswordResetTokenRepositoryImpl) GetByTokenHash(ctx context.Context, tokenHash string) (*domain.PasswordResetToken, error) {
+	var token domain.PasswordResetToken
+	err := dbFromContext(ctx, r.db).Where("token_hash = ?", tokenHash).First(&token).Error
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return &token, nil
+}
+
+// Consume uses a single conditional UPDATE so two concurrent resets with the
+// same token cannot both succeed.
+func (r *PasswordResetTokenRepositoryImpl) Consume(ctx context.Context, tokenHash string) (*domain.PasswordResetToken, error) {
+	var token domain.PasswordResetToken
+	now := time.Now()
+	result := dbFromContext(ctx, r.db).Model(&token).
+		Clauses(clause.Returning{}).
+		Where("token_hash = ? AND used_at IS NULL AND expires_at > ?", tokenHash, now).
+		Update("used_at", now)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, domain.ErrNotFound
+	}
+	return &token, nil
+}
+
+func (r *PasswordResetTokenRepositoryImpl) DeleteByUserID(ctx context.Context, userID uuid.UUID) error {
+	return dbFromContext(ctx, r.db).Where("user_id = ?", userID).Delete(&domain.PasswordResetToken{}).Error
+}
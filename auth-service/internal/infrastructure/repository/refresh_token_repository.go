@@ -21,32 +21,96 @@ func NewRefreshTokenRepository(db *gorm.DB) domain.RefreshTokenRepository {
 }
 
 func (r *RefreshTokenRepositoryImpl) Create(ctx context.Context, token *domain.RefreshToken) error {
-	return r.db.WithContext(ctx).Create(token).Error
+	return dbFromContext(ctx, r.db).Create(token).Error
 }
 
-func (r *RefreshTokenRepositoryImpl) GetByToken(ctx context.Context, token string) (*domain.RefreshToken, error) {
+func (r *RefreshTokenRepositoryImpl) GetByTokenHash(ctx context.Context, tokenHash string) (*domain.RefreshToken, error) {
 	var refreshToken domain.RefreshToken
-	err := r.db.WithContext(ctx).Where("token = ? AND is_revoked = false", token).First(&refreshToken).Error
+	err := dbFromContext(ctx, r.db).Where("token_hash = ? AND is_revoked = false", tokenHash).First(&refreshToken).Error
 	if err != nil {
-		return nil, err
+		return nil, translateError(err)
+	}
+	return &refreshToken, nil
+}
+
+func (r *RefreshTokenRepositoryImpl) GetByTokenHashIncludingRevoked(ctx context.Context, tokenHash string) (*domain.RefreshToken, error) {
+	var refreshToken domain.RefreshToken
+	err := dbFromContext(ctx, r.db).Where("token_hash = ?", tokenHash).First(&refreshToken).Error
+	if err != nil {
+		return nil, translateError(err)
 	}
 	return &refreshToken, nil
 }
 
 func (r *RefreshTokenRepositoryImpl) GetByUserID(ctx context.Context, userID uuid.UUID) ([]*domain.RefreshToken, error) {
 	var tokens []*domain.RefreshToken
-	err := r.db.WithContext(ctx).Where("user_id = ? AND is_revoked = false", userID).Find(&tokens).Error
+	err := dbFromContext(ctx, r.db).Where("user_id = ? AND is_revoked = false", userID).Find(&tokens).Error
+	return tokens, err
+}
+
+func (r *RefreshTokenRepositoryImpl) GetByUserIDIncludingRevoked(ctx context.Context, userID uuid.UUID) ([]*domain.RefreshToken, error) {
+	var tokens []*domain.RefreshToken
+	err := dbFromContext(ctx, r.db).Where("user_id = ?", userID).Find(&tokens).Error
 	return tokens, err
 }
 
-func (r *RefreshTokenRepositoryImpl) Revoke(ctx context.Context, token string) error {
-	return r.db.WithContext(ctx).Model(&domain.RefreshToken{}).Where("token = ?", token).Update("is_revoked", true).Error
+func (r *RefreshTokenRepositoryImpl) ActiveCount(ctx context.Context, userID uuid.UUID) (int64, error) {
+	var count int64
+	err := dbFromContext(ctx, r.db).Model(&domain.RefreshToken{}).
+		Where("user_id = ? AND is_revoked = false AND expires_at > ?", userID, time.Now()).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *RefreshTokenRepositoryImpl) RevokeOldest(ctx context.Context, userID uuid.UUID, keep int) (int64, error) {
+	db := dbFromContext(ctx, r.db)
+	evicted := db.Model(&domain.RefreshToken{}).Select("id").
+		Where("user_id = ? AND is_revoked = false AND expires_at > ?", userID, time.Now()).
+		Order("created_at DESC, id").
+		Offset(keep)
+	result := db.Model(&domain.RefreshToken{}).Where("id IN (?)", evicted).Update("is_revoked", true)
+	return result.RowsAffected, result.Error
+}
+
+func (r *RefreshTokenRepositoryImpl) GetIssuedSince(ctx context.Context, userID uuid.UUID, since time.Time) ([]*domain.RefreshToken, error) {
+	var tokens []*domain.RefreshToken
+	err := dbFromContext(ctx, r.db).Where("user_id = ? AND created_at > ?", userID, since).Find(&tokens).Error
+	return tokens, err
+}
+
+func (r *RefreshTokenRepositoryImpl) Revoke(ctx context.Context, tokenHash string) error {
+	return dbFromContext(ctx, r.db).Model(&domain.RefreshToken{}).Where("token_hash = ?", tokenHash).Update("is_revoked", true).Error
 }
 
 func (r *RefreshTokenRepositoryImpl) RevokeAllByUserID(ctx context.Context, userID uuid.UUID) error {
-	return r.db.WithContext(ctx).Model(&domain.RefreshToken{}).Where("user_id = ?", userID).Update("is_revoked", true).Error
+	return dbFromContext(ctx, r.db).Model(&domain.RefreshToken{}).Where("user_id = ?", userID).Update("is_revoked", true).Error
 }
 
-func (r *RefreshTokenRepositoryImpl) DeleteExpired(ctx context.Context) error {
-	return r.db.WithContext(ctx).Where("expires_at < ?", time.Now()).Delete(&domain.RefreshToken{}).Error
+func (r *RefreshTokenRepositoryImpl) RevokeAllByUserIDExcept(ctx context.Context, userID, keepID uuid.UUID) error {
+	return dbFromContext(ctx, r.db).Model(&domain.RefreshToken{}).Where("user_id = ? AND id <> ?", userID, keepID).Update("is_revoked", true).Error
+}
+
+func (r *RefreshTokenRepositoryImpl) RevokeFamily(ctx context.Context, familyID uuid.UUID) error {
+	return dbFromContext(ctx, r.db).Model(&domain.RefreshToken{}).Where("family_id = ?", familyID).Update("is_revoked", true).Error
+}
+
+func (r *RefreshTokenRepositoryImpl) Touch(ctx context.Context, tokenHash string) error {
+	return dbFromContext(ctx, r.db).Model(&domain.RefreshToken{}).Where("token_hash = ?", tokenHash).Update("last_used_at", time.Now()).Error
+}
+
+func (r *RefreshTokenRepositoryImpl) HasSeenDevice(ctx context.Context, userID uuid.UUID, fingerprint string) (bool, error) {
+	var count int64
+	err := dbFromContext(ctx, r.db).Model(&domain.RefreshToken{}).
+		Where("user_id = ? AND device_fingerprint = ?", userID, fingerprint).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func (r *RefreshTokenRepositoryImpl) DeleteExpired(ctx context.Context, revokedBefore time.Time) (int64, error) {
+	query := dbFromContext(ctx, r.db).Where("expires_at < ?", time.Now())
+	if !revokedBefore.IsZero() {
+		query = query.Or("is_revoked = ? AND created_at < ?", true, revokedBefore)
+	}
+	result := query.Delete(&domain.RefreshToken{})
+	return result.RowsAffected, result.Error
 }
@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"sync"
+	"testing"
+
+	"auth-service/internal/domain"
+
+	"gorm.io/gorm/schema"
+)
+
+func TestRefreshTokenActiveSessionIndex(t *testing.T) {
+	s, err := schema.Parse(&domain.RefreshToken{}, &sync.Map{}, schema.NamingStrategy{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	index, ok := s.ParseIndexes()["idx_refresh_tokens_user_active"]
+	if !ok {
+		t.Fatal("idx_refresh_tokens_user_active not declared")
+	}
+	var columns []string
+	for _, field := range index.Fields {
+		columns = append(columns, field.DBName)
+	}
+	if len(columns) != 2 || columns[0] != "user_id" || columns[1] != "is_revoked" {
+		t.Errorf("index columns = %q, want [user_id is_revoked]", columns)
+	}
+}
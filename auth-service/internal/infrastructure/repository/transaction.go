@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"auth-service/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// txKey is the context key of the running transaction
+type txKey struct{}
+
+// GormTransactioner implements the Transactioner interface
+type GormTransactioner struct {
+	db *gorm.DB
+}
+
+// NewTransactioner creates a transactioner whose transactions are joined by
+// every repository in this package
+func NewTransactioner(db *gorm.DB) domain.Transactioner {
+	return &GormTransactioner{db: db}
+}
+
+// WithinTransaction runs fn in a transaction. Called inside another
+// transaction it creates a savepoint, so only fn's writes are rolled back.
+func (t *GormTransactioner) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return dbFromContext(ctx, t.db).Transaction(func(tx *gorm.DB) error {
+		return fn(context.WithValue(ctx, txKey{}, tx))
+	})
+}
+
+// dbFromContext returns the transaction running in ctx, or db outside of one
+func dbFromContext(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if tx, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		return tx.WithContext(ctx)
+	}
+	return db.WithContext(ctx)
+}
+
+// translateError reports a lookup that matched no row as domain.ErrNotFound
+func translateError(err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return domain.ErrNotFound
+	}
+	return err
+}
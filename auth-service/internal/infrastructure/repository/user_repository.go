@@ -2,76 +2,309 @@ package repository
 
 import (
 	"context"
+	"strings"
 	"time"
 
 	"auth-service/internal/domain"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// notDeleted excludes soft-deleted users from lookups
+const notDeleted = "deleted_at IS NULL"
+
 // UserRepositoryImpl implements the UserRepository interface
 type UserRepositoryImpl struct {
 	db *gorm.DB
+
+	// caseInsensitiveUsernames looks usernames up by username_normalized
+	caseInsensitiveUsernames bool
+}
+
+// UserRepositoryOption configures optional user repository behaviour
+type UserRepositoryOption func(*UserRepositoryImpl)
+
+// WithCaseInsensitiveUsernames stores and queries a case-folded copy of the
+// username; the username_normalized column must be backfilled first
+// (see database.NewPostgresDB)
+func WithCaseInsensitiveUsernames() UserRepositoryOption {
+	return func(r *UserRepositoryImpl) {
+		r.caseInsensitiveUsernames = true
+	}
 }
 
 // NewUserRepository creates a new user repository
-func NewUserRepository(db *gorm.DB) domain.UserRepository {
-	return &UserRepositoryImpl{db: db}
+func NewUserRepository(db *gorm.DB, opts ...UserRepositoryOption) domain.UserRepository {
+	r := &UserRepositoryImpl{db: db}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
 }
 
 func (r *UserRepositoryImpl) Create(ctx context.Context, user *domain.User) error {
-	return r.db.WithContext(ctx).Create(user).Error
+	user.Email = domain.NormalizeEmail(user.Email)
+	r.normalizeUsername(user)
+	return dbFromContext(ctx, r.db).Create(user).Error
+}
+
+// bulkCreateBatchSize is the number of rows per INSERT in BulkCreate
+const bulkCreateBatchSize = 100
+
+// BulkCreate joins a running transaction or starts its own, so a failing
+// batch rolls back the batches before it
+func (r *UserRepositoryImpl) BulkCreate(ctx context.Context, users []*domain.User) error {
+	if len(users) == 0 {
+		return nil
+	}
+	for _, user := range users {
+		user.Email = domain.NormalizeEmail(user.Email)
+		r.normalizeUsername(user)
+	}
+	return dbFromContext(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		return tx.CreateInBatches(users, bulkCreateBatchSize).Error
+	})
+}
+
+// normalizeUsername keeps username_normalized in sync on every write. With the
+// option off it is cleared, so stale values can't survive a rename and are
+// backfilled again if the option is turned back on
+func (r *UserRepositoryImpl) normalizeUsername(user *domain.User) {
+	if !r.caseInsensitiveUsernames {
+		user.UsernameNormalized = nil
+		return
+	}
+	normalized := domain.NormalizeUsername(user.Username)
+	user.UsernameNormalized = &normalized
+}
+
+// usernameCondition is the WHERE clause for a username lookup
+func (r *UserRepositoryImpl) usernameCondition(username string) (string, string) {
+	if r.caseInsensitiveUsernames {
+		return "username_normalized = ?", domain.NormalizeUsername(username)
+	}
+	return "username = ?", username
+}
+
+// loginUsernameCondition matches usernames regardless of case, even when
+// lookups are otherwise case-sensitive; the display username keeps its case.
+// LOWER(username) is backed by idx_users_org_username_lower.
+func (r *UserRepositoryImpl) loginUsernameCondition(username string) (string, string) {
+	if r.caseInsensitiveUsernames {
+		return r.usernameCondition(username)
+	}
+	return "LOWER(username) = ?", strings.ToLower(username)
 }
 
 func (r *UserRepositoryImpl) GetByID(ctx context.Context, id uuid.UUID) (*domain.User, error) {
 	var user domain.User
-	err := r.db.WithContext(ctx).Where("id = ?", id).First(&user).Error
+	err := dbFromContext(ctx, r.db).Where("id = ?", id).Where(notDeleted).First(&user).Error
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return &user, nil
+}
+
+func (r *UserRepositoryImpl) GetByEmail(ctx context.Context, orgID uuid.UUID, email string) (*domain.User, error) {
+	var user domain.User
+	err := dbFromContext(ctx, r.db).Where("organization_id = ? AND email = ?", orgID, domain.NormalizeEmail(email)).Where(notDeleted).First(&user).Error
 	if err != nil {
-		return nil, err
+		return nil, translateError(err)
 	}
 	return &user, nil
 }
 
-func (r *UserRepositoryImpl) GetByEmail(ctx context.Context, email string) (*domain.User, error) {
+func (r *UserRepositoryImpl) GetByUsername(ctx context.Context, orgID uuid.UUID, username string) (*domain.User, error) {
 	var user domain.User
-	err := r.db.WithContext(ctx).Where("email = ?", email).First(&user).Error
+	query, arg := r.usernameCondition(username)
+	err := dbFromContext(ctx, r.db).Where("organization_id = ?", orgID).Where(query, arg).Where(notDeleted).First(&user).Error
 	if err != nil {
-		return nil, err
+		return nil, translateError(err)
 	}
 	return &user, nil
 }
 
-func (r *UserRepositoryImpl) GetByUsername(ctx context.Context, username string) (*domain.User, error) {
+// GetByPhone expects a normalized phone number; an empty one matches nobody
+func (r *UserRepositoryImpl) GetByPhone(ctx context.Context, orgID uuid.UUID, phone string) (*domain.User, error) {
+	if phone == "" {
+		return nil, domain.ErrNotFound
+	}
 	var user domain.User
-	err := r.db.WithContext(ctx).Where("username = ?", username).First(&user).Error
+	err := dbFromContext(ctx, r.db).Where("organization_id = ? AND phone = ?", orgID, phone).Where(notDeleted).First(&user).Error
 	if err != nil {
-		return nil, err
+		return nil, translateError(err)
 	}
 	return &user, nil
 }
 
+// GetByEmailOrUsername uses one round-trip for the login lookup. Both the
+// email and the username match regardless of case. If the identifier is one
+// user's email and another's username, the email match is returned, as it
+// was when the two were looked up one after the other; among usernames that
+// differ only in case, an exact match wins
+func (r *UserRepositoryImpl) GetByEmailOrUsername(ctx context.Context, orgID uuid.UUID, identifier string) (*domain.User, error) {
+	var user domain.User
+	email := domain.NormalizeEmail(identifier)
+	query, arg := r.loginUsernameCondition(identifier)
+	err := dbFromContext(ctx, r.db).
+		Where("organization_id = ?", orgID).
+		Where("(email = ? OR "+query+")", email, arg).
+		Where(notDeleted).
+		Order(clause.OrderBy{Expression: clause.Expr{SQL: "email = ? DESC, username = ? DESC", Vars: []interface{}{email, identifier}}}).
+		Take(&user).Error
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return &user, nil
+}
+
+// Update saves every column except token_version, so saving a user loaded
+// before a concurrent IncrementTokenVersion cannot roll the version back
 func (r *UserRepositoryImpl) Update(ctx context.Context, user *domain.User) error {
-	return r.db.WithContext(ctx).Save(user).Error
+	user.Email = domain.NormalizeEmail(user.Email)
+	r.normalizeUsername(user)
+	return dbFromContext(ctx, r.db).Omit("token_version").Save(user).Error
 }
 
 func (r *UserRepositoryImpl) Delete(ctx context.Context, id uuid.UUID) error {
-	return r.db.WithContext(ctx).Delete(&domain.User{}, id).Error
+	return dbFromContext(ctx, r.db).Delete(&domain.User{}, id).Error
 }
 
-func (r *UserRepositoryImpl) ExistsByEmail(ctx context.Context, email string) (bool, error) {
+func (r *UserRepositoryImpl) ExistsByEmail(ctx context.Context, orgID uuid.UUID, email string) (bool, error) {
 	var count int64
-	err := r.db.WithContext(ctx).Model(&domain.User{}).Where("email = ?", email).Count(&count).Error
+	err := dbFromContext(ctx, r.db).Model(&domain.User{}).Where("organization_id = ? AND email = ?", orgID, domain.NormalizeEmail(email)).Where(notDeleted).Count(&count).Error
 	return count > 0, err
 }
 
-func (r *UserRepositoryImpl) ExistsByUsername(ctx context.Context, username string) (bool, error) {
+// ExistsByUsername ignores case even when lookups are case-sensitive, so a
+// new username can't differ from a taken one only by case. Without the
+// username_normalized column this is not index-backed, but it only runs on
+// registration.
+func (r *UserRepositoryImpl) ExistsByUsername(ctx context.Context, orgID uuid.UUID, username string) (bool, error) {
 	var count int64
-	err := r.db.WithContext(ctx).Model(&domain.User{}).Where("username = ?", username).Count(&count).Error
+	query, arg := "LOWER(username) = ?", strings.ToLower(username)
+	if r.caseInsensitiveUsernames {
+		query, arg = r.usernameCondition(username)
+	}
+	err := dbFromContext(ctx, r.db).Model(&domain.User{}).Where("organization_id = ?", orgID).Where(query, arg).Where(notDeleted).Count(&count).Error
 	return count > 0, err
 }
 
 func (r *UserRepositoryImpl) UpdateLastLogin(ctx context.Context, id uuid.UUID) error {
 	now := time.Now()
-	return r.db.WithContext(ctx).Model(&domain.User{}).Where("id = ?", id).Update("last_login_at", now).Error
+	return dbFromContext(ctx, r.db).Model(&domain.User{}).Where("id = ?", id).Update("last_login_at", now).Error
+}
+
+// RecordFailedLogin increments in the database so concurrent failed logins
+// cannot overwrite each other's count
+func (r *UserRepositoryImpl) RecordFailedLogin(ctx context.Context, id uuid.UUID) (int, error) {
+	var user domain.User
+	result := dbFromContext(ctx, r.db).Model(&user).
+		Clauses(clause.Returning{Columns: []clause.Column{{Name: "failed_login_attempts"}}}).
+		Where("id = ?", id).
+		Update("failed_login_attempts", gorm.Expr("failed_login_attempts + 1"))
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return 0, domain.ErrNotFound
+	}
+	return user.FailedLoginAttempts, nil
+}
+
+func (r *UserRepositoryImpl) GetTokenVersion(ctx context.Context, id uuid.UUID) (int, error) {
+	var user domain.User
+	err := dbFromContext(ctx, r.db).Select("token_version").Where("id = ?", id).Where(notDeleted).Take(&user).Error
+	if err != nil {
+		return 0, translateError(err)
+	}
+	return user.TokenVersion, nil
+}
+
+// IncrementTokenVersion increments in the database, like RecordFailedLogin,
+// so two concurrent increments both take effect
+func (r *UserRepositoryImpl) IncrementTokenVersion(ctx context.Context, id uuid.UUID) (int, error) {
+	var user domain.User
+	result := dbFromContext(ctx, r.db).Model(&user).
+		Clauses(clause.Returning{Columns: []clause.Column{{Name: "token_version"}}}).
+		Where("id = ?", id).
+		Update("token_version", gorm.Expr("token_version + 1"))
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return 0, domain.ErrNotFound
+	}
+	return user.TokenVersion, nil
+}
+
+func (r *UserRepositoryImpl) SetLockout(ctx context.Context, id uuid.UUID, until *time.Time) error {
+	return dbFromContext(ctx, r.db).Model(&domain.User{}).Where("id = ?", id).Updates(map[string]interface{}{
+		"failed_login_attempts": 0,
+		"locked_until":          until,
+	}).Error
+}
+
+// MarkLockoutNotified is a conditional UPDATE, so of two concurrent lockouts
+// only one claims the notification
+func (r *UserRepositoryImpl) MarkLockoutNotified(ctx context.Context, id uuid.UUID, at, since time.Time) (bool, error) {
+	result := dbFromContext(ctx, r.db).Model(&domain.User{}).
+		Where("id = ? AND (lockout_notified_at IS NULL OR lockout_notified_at <= ?)", id, since).
+		Update("lockout_notified_at", at)
+	return result.RowsAffected > 0, result.Error
+}
+
+// MarkVerificationSent is a conditional UPDATE, so of two concurrent resend
+// requests only one sends an email
+func (r *UserRepositoryImpl) MarkVerificationSent(ctx context.Context, id uuid.UUID, at, since time.Time) (bool, error) {
+	result := dbFromContext(ctx, r.db).Model(&domain.User{}).
+		Where("id = ? AND (verification_sent_at IS NULL OR verification_sent_at <= ?)", id, since).
+		Update("verification_sent_at", at)
+	return result.RowsAffected > 0, result.Error
+}
+
+// MarkVerified is a single UPDATE, so the batch is applied atomically
+func (r *UserRepositoryImpl) MarkVerified(ctx context.Context, ids []uuid.UUID) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return dbFromContext(ctx, r.db).Model(&domain.User{}).Where("id IN ?", ids).Update("is_verified", true).Error
+}
+
+// likeEscaper escapes LIKE wildcards so a search term matches literally
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// List orders by created_at; filtered by role that matches the
+// (role, created_at) index, so pages are read from the index instead of sorting
+func (r *UserRepositoryImpl) List(ctx context.Context, filter domain.UserFilter) ([]*domain.User, int64, error) {
+	// Always one tenant: in a single-tenant setup every user is in uuid.Nil
+	query := dbFromContext(ctx, r.db).Model(&domain.User{}).Where(notDeleted).
+		Where("organization_id = ?", filter.OrganizationID)
+	if filter.Role != "" {
+		query = query.Where("role = ?", filter.Role)
+	}
+	if filter.IsActive != nil {
+		query = query.Where("is_active = ?", *filter.IsActive)
+	}
+	if filter.IsVerified != nil {
+		query = query.Where("is_verified = ?", *filter.IsVerified)
+	}
+	if filter.Search != "" {
+		pattern := "%" + likeEscaper.Replace(filter.Search) + "%"
+		query = query.Where("(email ILIKE ? OR username ILIKE ?)", pattern, pattern)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var users []*domain.User
+	err := query.Order("created_at, id").Offset((filter.Page - 1) * filter.PageSize).Limit(filter.PageSize).Find(&users).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return users, total, nil
 }
@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"auth-service/internal/domain"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm/schema"
+)
+
+func TestUserRepositoryNormalizesUsernames(t *testing.T) {
+	r := NewUserRepository(nil, WithCaseInsensitiveUsernames()).(*UserRepositoryImpl)
+
+	user := &domain.User{Username: "JaneDoe"}
+	r.normalizeUsername(user)
+	if user.Username != "JaneDoe" {
+		t.Errorf("display username changed to %q", user.Username)
+	}
+	if user.UsernameNormalized == nil || *user.UsernameNormalized != "janedoe" {
+		t.Errorf("UsernameNormalized = %v, want janedoe", user.UsernameNormalized)
+	}
+	if query, arg := r.usernameCondition("JANEDOE"); query != "username_normalized = ?" || arg != "janedoe" {
+		t.Errorf("condition = %q, %q", query, arg)
+	}
+}
+
+func TestUserRepositoryKeepsExactUsernamesByDefault(t *testing.T) {
+	r := NewUserRepository(nil).(*UserRepositoryImpl)
+
+	// A stale value from an earlier case-insensitive run is cleared on write
+	stale := "old"
+	user := &domain.User{Username: "JaneDoe", UsernameNormalized: &stale}
+	r.normalizeUsername(user)
+	if user.UsernameNormalized != nil {
+		t.Errorf("UsernameNormalized = %q, want nil", *user.UsernameNormalized)
+	}
+	if query, arg := r.usernameCondition("JaneDoe"); query != "username = ?" || arg != "JaneDoe" {
+		t.Errorf("condition = %q, %q", query, arg)
+	}
+}
+
+func TestUserRepositoryMatchesLoginUsernamesInAnyCase(t *testing.T) {
+	if query, arg := NewUserRepository(nil).(*UserRepositoryImpl).loginUsernameCondition("JaneDoe"); query != "LOWER(username) = ?" || arg != "janedoe" {
+		t.Errorf("condition = %q, %q", query, arg)
+	}
+	r := NewUserRepository(nil, WithCaseInsensitiveUsernames()).(*UserRepositoryImpl)
+	if query, arg := r.loginUsernameCondition("JaneDoe"); query != "username_normalized = ?" || arg != "janedoe" {
+		t.Errorf("case-insensitive condition = %q, %q", query, arg)
+	}
+}
+
+func TestLikeEscaperMatchesWildcardsLiterally(t *testing.T) {
+	if got := likeEscaper.Replace(`50%_off\`); got != `50\%\_off\\` {
+		t.Errorf("escaped = %q", got)
+	}
+}
+
+func TestUserPhoneIndexOnlyCoversSetPhones(t *testing.T) {
+	s, err := schema.Parse(&domain.User{}, &sync.Map{}, schema.NamingStrategy{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	index, ok := s.ParseIndexes()["idx_users_org_phone"]
+	if !ok {
+		t.Fatal("idx_users_org_phone not declared")
+	}
+	var columns []string
+	for _, field := range index.Fields {
+		columns = append(columns, field.DBName)
+	}
+	if len(columns) != 2 || columns[0] != "organization_id" || columns[1] != "phone" {
+		t.Errorf("index columns = %q, want [organization_id phone]", columns)
+	}
+	// Users without a phone share the empty value, so they must not be unique
+	if index.Class != "UNIQUE" || index.Where != "phone <> ''" {
+		t.Errorf("index class = %q, where = %q, want a unique index on set phones", index.Class, index.Where)
+	}
+}
+
+func TestUserRepositoryGetByEmptyPhoneMatchesNobody(t *testing.T) {
+	_, err := NewUserRepository(nil).GetByPhone(context.Background(), uuid.Nil, "")
+	if !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("err = %v, want domain.ErrNotFound", err)
+	}
+}
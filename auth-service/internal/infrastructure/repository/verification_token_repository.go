@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"auth-service/internal/domain"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// VerificationTokenRepositoryImpl implements the VerificationTokenRepository interface
+type VerificationTokenRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewVerificationTokenRepository creates a new verification token repository
+func NewVerificationTokenRepository(db *gorm.DB) domain.VerificationTokenRepository {
+	return &VerificationTokenRepositoryImpl{db: db}
+}
+
+func (r *VerificationTokenRepositoryImpl) Create(ctx context.Context, token *domain.VerificationToken) error {
+	return dbFromContext(ctx, r.db).Create(token).Error
+}
+
+// Consume uses a single conditional UPDATE so a token can be used only once
+func (r *VerificationTokenRepositoryImpl) Consume(ctx context.Context, tokenHash string) (*domain.VerificationToken, error) {
+	var token domain.VerificationToken
+	now := time.Now()
+	result := dbFromContext(ctx, r.db).Model(&token).
+		Clauses(clause.Returning{}).
+		Where("token_hash = ? AND used_at IS NULL AND expires_at > ?", tokenHash, now).
+		Update("used_at", now)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, domain.ErrNotFound
+	}
+	return &token, nil
+}
+
+func (r *VerificationTokenRepositoryImpl) DeleteByUserID(ctx context.Context, userID uuid.UUID) error {
+	return dbFromContext(ctx, r.db).Where("user_id = ?", userID).Delete(&domain.VerificationToken{}).Error
+}
@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"context"
+
+	"auth-service/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+// WebhookDeliveryRepositoryImpl implements the WebhookDeliveryRepository interface
+type WebhookDeliveryRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewWebhookDeliveryRepository creates a new webhook delivery log repository
+func NewWebhookDeliveryRepository(db *gorm.DB) domain.WebhookDeliveryRepository {
+	return &WebhookDeliveryRepositoryImpl{db: db}
+}
+
+func (r *WebhookDeliveryRepositoryImpl) Create(ctx context.Context, delivery *domain.WebhookDelivery) error {
+	return dbFromContext(ctx, r.db).Create(delivery).Error
+}
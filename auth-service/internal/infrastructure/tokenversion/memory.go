@@ -0,0 +1,73 @@
+package tokenversion
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type memoryEntry struct {
+	version   int
+	expiresAt time.Time
+}
+
+// MemoryCache keeps token versions in process memory. It is meant for tests
+// and single-instance development setups; increments on one replica are not
+// seen by the others until their entries expire.
+type MemoryCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[uuid.UUID]memoryEntry
+}
+
+// NewMemoryCache creates a new in-memory token version cache
+func NewMemoryCache(ttl time.Duration) *MemoryCache {
+	return &MemoryCache{ttl: ttl, entries: make(map[uuid.UUID]memoryEntry)}
+}
+
+// Get returns the cached version of the user
+func (c *MemoryCache) Get(ctx context.Context, userID uuid.UUID) (int, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[userID]
+	if !ok || !time.Now().Before(entry.expiresAt) {
+		return 0, false, nil
+	}
+	return entry.version, true, nil
+}
+
+// Fill caches version unless an unexpired value is cached
+func (c *MemoryCache) Fill(ctx context.Context, userID uuid.UUID, version int) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if entry, ok := c.entries[userID]; ok && time.Now().Before(entry.expiresAt) {
+		return nil
+	}
+	c.set(userID, version)
+	return nil
+}
+
+// Set caches version, replacing any cached value
+func (c *MemoryCache) Set(ctx context.Context, userID uuid.UUID, version int) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.set(userID, version)
+	return nil
+}
+
+// set stores version; the caller holds mu
+func (c *MemoryCache) set(userID uuid.UUID, version int) {
+	now := time.Now()
+	// Drop expired entries so the map stays bounded by the recently checked users
+	for id, entry := range c.entries {
+		if !now.Before(entry.expiresAt) {
+			delete(c.entries, id)
+		}
+	}
+	c.entries[userID] = memoryEntry{version: version, expiresAt: now.Add(c.ttl)}
+}
@@ -0,0 +1,44 @@
+package tokenversion
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestMemoryCache(t *testing.T) {
+	ctx := context.Background()
+	c := NewMemoryCache(time.Minute)
+	userID := uuid.New()
+
+	if _, ok, err := c.Get(ctx, userID); ok || err != nil {
+		t.Fatalf("Get before caching: ok = %v, err = %v", ok, err)
+	}
+
+	// Fill does not overwrite a cached value, Set does
+	if err := c.Set(ctx, userID, 2); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Fill(ctx, userID, 1); err != nil {
+		t.Fatal(err)
+	}
+	if version, ok, _ := c.Get(ctx, userID); !ok || version != 2 {
+		t.Errorf("after Fill: version = %d, ok = %v; want 2", version, ok)
+	}
+	if err := c.Set(ctx, userID, 3); err != nil {
+		t.Fatal(err)
+	}
+	if version, _, _ := c.Get(ctx, userID); version != 3 {
+		t.Errorf("after Set: version = %d, want 3", version)
+	}
+
+	expired := NewMemoryCache(-time.Second)
+	if err := expired.Set(ctx, userID, 1); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok, _ := expired.Get(ctx, userID); ok {
+		t.Error("expired entry was returned")
+	}
+}
@@ -0,0 +1,46 @@
+package tokenversion
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+const keyPrefix = "auth:token_version:"
+
+// RedisCache keeps token versions in Redis, shared by all replicas, so an
+// increment on one replica is seen by the others immediately
+type RedisCache struct {
+	client *redis.Client
+	ttl    time.Duration
+}
+
+// NewRedisCache creates a new Redis-backed token version cache
+func NewRedisCache(client *redis.Client, ttl time.Duration) *RedisCache {
+	return &RedisCache{client: client, ttl: ttl}
+}
+
+// Get returns the cached version of the user
+func (c *RedisCache) Get(ctx context.Context, userID uuid.UUID) (int, bool, error) {
+	version, err := c.client.Get(ctx, keyPrefix+userID.String()).Int()
+	if errors.Is(err, redis.Nil) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	return version, true, nil
+}
+
+// Fill caches version with SET NX, keeping a value stored by Set
+func (c *RedisCache) Fill(ctx context.Context, userID uuid.UUID, version int) error {
+	return c.client.SetNX(ctx, keyPrefix+userID.String(), version, c.ttl).Err()
+}
+
+// Set caches version, replacing any cached value
+func (c *RedisCache) Set(ctx context.Context, userID uuid.UUID, version int) error {
+	return c.client.Set(ctx, keyPrefix+userID.String(), version, c.ttl).Err()
+}
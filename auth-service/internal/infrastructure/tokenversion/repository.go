@@ -0,0 +1,66 @@
+// Package tokenversion caches users' token versions (domain.User.TokenVersion)
+// so checking the "tv" claim of an access token does not query the database on
+// every request
+package tokenversion
+
+import (
+	"context"
+	"time"
+
+	"auth-service/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+// DefaultTTL is how long a token version stays cached. An increment replaces
+// the cached value right away, so the TTL only bounds how long a stale value
+// survives an increment whose cache write failed.
+const DefaultTTL = 5 * time.Minute
+
+// Cache stores token versions by user ID
+type Cache interface {
+	// Get returns the cached version; ok is false on a cache miss
+	Get(ctx context.Context, userID uuid.UUID) (version int, ok bool, err error)
+	// Fill caches a version read from the database unless one is already
+	// cached, so a slow reader cannot overwrite a newer version stored by Set
+	Fill(ctx context.Context, userID uuid.UUID, version int) error
+	// Set caches a version that was just incremented
+	Set(ctx context.Context, userID uuid.UUID, version int) error
+}
+
+// CachedUserRepository reads token versions through a cache and updates the
+// cache on every increment. All other methods go to the wrapped repository.
+type CachedUserRepository struct {
+	domain.UserRepository
+	cache Cache
+}
+
+// NewCachedUserRepository wraps users with a token version cache
+func NewCachedUserRepository(users domain.UserRepository, cache Cache) domain.UserRepository {
+	return &CachedUserRepository{UserRepository: users, cache: cache}
+}
+
+// GetTokenVersion returns the cached version and falls back to the database.
+// The database is authoritative, so cache errors only cost a query.
+func (r *CachedUserRepository) GetTokenVersion(ctx context.Context, id uuid.UUID) (int, error) {
+	if version, ok, err := r.cache.Get(ctx, id); err == nil && ok {
+		return version, nil
+	}
+	version, err := r.UserRepository.GetTokenVersion(ctx, id)
+	if err != nil {
+		return 0, err
+	}
+	_ = r.cache.Fill(ctx, id, version)
+	return version, nil
+}
+
+// IncrementTokenVersion increments the version in the database, then caches
+// the new value. If caching fails the increment is kept but the error is
+// returned: other replicas may accept older tokens until DefaultTTL passes.
+func (r *CachedUserRepository) IncrementTokenVersion(ctx context.Context, id uuid.UUID) (int, error) {
+	version, err := r.UserRepository.IncrementTokenVersion(ctx, id)
+	if err != nil {
+		return 0, err
+	}
+	return version, r.cache.Set(ctx, id, version)
+}
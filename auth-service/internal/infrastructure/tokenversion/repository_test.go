@@ -0,0 +1,101 @@
+package tokenversion
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"auth-service/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+// countingUserRepo stores token versions and counts database reads
+type countingUserRepo struct {
+	domain.UserRepository
+	versions map[uuid.UUID]int
+	reads    int
+}
+
+func (r *countingUserRepo) GetTokenVersion(ctx context.Context, id uuid.UUID) (int, error) {
+	r.reads++
+	version, ok := r.versions[id]
+	if !ok {
+		return 0, domain.ErrNotFound
+	}
+	return version, nil
+}
+
+func (r *countingUserRepo) IncrementTokenVersion(ctx context.Context, id uuid.UUID) (int, error) {
+	if _, ok := r.versions[id]; !ok {
+		return 0, domain.ErrNotFound
+	}
+	r.versions[id]++
+	return r.versions[id], nil
+}
+
+// brokenCache fails every call, like Redis being down
+type brokenCache struct{}
+
+var errCacheDown = errors.New("cache down")
+
+func (brokenCache) Get(ctx context.Context, userID uuid.UUID) (int, bool, error) {
+	return 0, false, errCacheDown
+}
+
+func (brokenCache) Fill(ctx context.Context, userID uuid.UUID, version int) error {
+	return errCacheDown
+}
+
+func (brokenCache) Set(ctx context.Context, userID uuid.UUID, version int) error {
+	return errCacheDown
+}
+
+func TestCachedUserRepository(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	users := &countingUserRepo{versions: map[uuid.UUID]int{userID: 0}}
+	r := NewCachedUserRepository(users, NewMemoryCache(time.Minute))
+
+	for i := 0; i < 3; i++ {
+		if version, err := r.GetTokenVersion(ctx, userID); err != nil || version != 0 {
+			t.Fatalf("GetTokenVersion = %d, %v; want 0", version, err)
+		}
+	}
+	if users.reads != 1 {
+		t.Errorf("database reads = %d, want 1", users.reads)
+	}
+
+	// An increment replaces the cached value without another read
+	if version, err := r.IncrementTokenVersion(ctx, userID); err != nil || version != 1 {
+		t.Fatalf("IncrementTokenVersion = %d, %v; want 1", version, err)
+	}
+	if version, _ := r.GetTokenVersion(ctx, userID); version != 1 || users.reads != 1 {
+		t.Errorf("after increment: version = %d, reads = %d; want 1, 1", version, users.reads)
+	}
+
+	if _, err := r.GetTokenVersion(ctx, uuid.New()); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("unknown user: err = %v, want ErrNotFound", err)
+	}
+}
+
+func TestCachedUserRepositoryWithoutCache(t *testing.T) {
+	ctx := context.Background()
+	userID := uuid.New()
+	users := &countingUserRepo{versions: map[uuid.UUID]int{userID: 4}}
+	r := NewCachedUserRepository(users, brokenCache{})
+
+	// Reads fall back to the database
+	if version, err := r.GetTokenVersion(ctx, userID); err != nil || version != 4 {
+		t.Errorf("GetTokenVersion = %d, %v; want 4", version, err)
+	}
+
+	// The increment is kept, but the caller learns that the cache is stale
+	if _, err := r.IncrementTokenVersion(ctx, userID); !errors.Is(err, errCacheDown) {
+		t.Errorf("IncrementTokenVersion err = %v, want the cache error", err)
+	}
+	if users.versions[userID] != 5 {
+		t.Errorf("stored version = %d, want 5", users.versions[userID])
+	}
+}
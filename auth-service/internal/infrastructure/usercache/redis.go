@@ -0,0 +1,87 @@
+package usercache
+
+import (
+	"bytes"
+	"context"
+	"encoding/gob"
+	"errors"
+	"time"
+
+	"auth-service/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+const keyPrefix = "auth:user:"
+
+// RedisCache keeps users in Redis, shared by all replicas, so a write on one
+// replica invalidates the entry for all of them
+type RedisCache struct {
+	client  *redis.Client
+	ttl     time.Duration
+	metrics Metrics
+}
+
+// NewRedisCache creates a new Redis-backed user cache. metrics may be nil.
+func NewRedisCache(client *redis.Client, ttl time.Duration, metrics Metrics) *RedisCache {
+	if metrics == nil {
+		metrics = nopMetrics{}
+	}
+	return &RedisCache{client: client, ttl: ttl, metrics: metrics}
+}
+
+// Get returns the cached user
+func (c *RedisCache) Get(ctx context.Context, id uuid.UUID) (*domain.User, bool, error) {
+	data, err := c.client.Get(ctx, keyPrefix+id.String()).Bytes()
+	if errors.Is(err, redis.Nil) {
+		c.metrics.UserCacheLookup(false)
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	user, err := decode(data)
+	if err != nil {
+		// An entry written by an older version of the struct; read the database instead
+		c.metrics.UserCacheLookup(false)
+		return nil, false, nil
+	}
+	c.metrics.UserCacheLookup(true)
+	return user, true, nil
+}
+
+// Set caches the user without its password hash
+func (c *RedisCache) Set(ctx context.Context, user *domain.User) error {
+	data, err := encode(user)
+	if err != nil {
+		return err
+	}
+	return c.client.Set(ctx, keyPrefix+user.ID.String(), data, c.ttl).Err()
+}
+
+// Delete removes the cached user
+func (c *RedisCache) Delete(ctx context.Context, id uuid.UUID) error {
+	return c.client.Del(ctx, keyPrefix+id.String()).Err()
+}
+
+// encode serializes a copy of user with gob, which unlike JSON keeps the
+// fields hidden from API responses (token version, lockout, ...). The
+// password hash is left out so Redis never holds it.
+func encode(user *domain.User) ([]byte, error) {
+	cached := *user
+	cached.PasswordHash = ""
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(&cached); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func decode(data []byte) (*domain.User, error) {
+	var user domain.User
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
@@ -0,0 +1,131 @@
+// Package usercache caches users by ID in Redis for hot read paths such as
+// token refresh, and clears an entry whenever the user is written
+package usercache
+
+import (
+	"context"
+	"time"
+
+	"auth-service/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+// DefaultTTL is how long a user stays cached. Every write through
+// InvalidatingUserRepository removes the entry right away, so the TTL only
+// bounds how long a stale entry survives a failed delete or a read that
+// raced with a write.
+const DefaultTTL = 30 * time.Second
+
+// Invalidator removes cached users
+type Invalidator interface {
+	Delete(ctx context.Context, id uuid.UUID) error
+}
+
+// Metrics records cache lookups; only the outcome is counted, never the user
+type Metrics interface {
+	UserCacheLookup(hit bool)
+}
+
+type nopMetrics struct{}
+
+func (nopMetrics) UserCacheLookup(hit bool) {}
+
+// InvalidatingUserRepository removes a user from the cache after every write
+// to it (profile updates, bans, password changes, lockouts...). Reads go to
+// the wrapped repository unchanged. The write has already succeeded when the
+// cache is cleared, so a failed delete is not returned; the entry expires
+// after DefaultTTL.
+type InvalidatingUserRepository struct {
+	domain.UserRepository
+	cache Invalidator
+}
+
+// NewInvalidatingUserRepository wraps users so writes clear cached entries
+func NewInvalidatingUserRepository(users domain.UserRepository, cache Invalidator) domain.UserRepository {
+	return &InvalidatingUserRepository{UserRepository: users, cache: cache}
+}
+
+func (r *InvalidatingUserRepository) invalidate(ctx context.Context, ids ...uuid.UUID) {
+	for _, id := range ids {
+		_ = r.cache.Delete(ctx, id)
+	}
+}
+
+func (r *InvalidatingUserRepository) Update(ctx context.Context, user *domain.User) error {
+	if err := r.UserRepository.Update(ctx, user); err != nil {
+		return err
+	}
+	r.invalidate(ctx, user.ID)
+	return nil
+}
+
+func (r *InvalidatingUserRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	if err := r.UserRepository.Delete(ctx, id); err != nil {
+		return err
+	}
+	r.invalidate(ctx, id)
+	return nil
+}
+
+func (r *InvalidatingUserRepository) UpdateLastLogin(ctx context.Context, id uuid.UUID) error {
+	if err := r.UserRepository.UpdateLastLogin(ctx, id); err != nil {
+		return err
+	}
+	r.invalidate(ctx, id)
+	return nil
+}
+
+func (r *InvalidatingUserRepository) RecordFailedLogin(ctx context.Context, id uuid.UUID) (int, error) {
+	attempts, err := r.UserRepository.RecordFailedLogin(ctx, id)
+	if err != nil {
+		return 0, err
+	}
+	r.invalidate(ctx, id)
+	return attempts, nil
+}
+
+func (r *InvalidatingUserRepository) IncrementTokenVersion(ctx context.Context, id uuid.UUID) (int, error) {
+	version, err := r.UserRepository.IncrementTokenVersion(ctx, id)
+	// The version may have been incremented even if a wrapped cache failed
+	r.invalidate(ctx, id)
+	return version, err
+}
+
+func (r *InvalidatingUserRepository) SetLockout(ctx context.Context, id uuid.UUID, until *time.Time) error {
+	if err := r.UserRepository.SetLockout(ctx, id, until); err != nil {
+		return err
+	}
+	r.invalidate(ctx, id)
+	return nil
+}
+
+func (r *InvalidatingUserRepository) MarkLockoutNotified(ctx context.Context, id uuid.UUID, at, since time.Time) (bool, error) {
+	marked, err := r.UserRepository.MarkLockoutNotified(ctx, id, at, since)
+	if err != nil {
+		return false, err
+	}
+	if marked {
+		r.invalidate(ctx, id)
+	}
+	return marked, nil
+}
+
+func (r *InvalidatingUserRepository) MarkVerificationSent(ctx context.Context, id uuid.UUID, at, since time.Time) (bool, error) {
+	marked, err := r.UserRepository.MarkVerificationSent(ctx, id, at, since)
+	if err != nil {
+		return false, err
+	}
+	if marked {
+		r.invalidate(ctx, id)
+	}
+	return marked, nil
+}
+
+func (r *InvalidatingUserRepository) MarkVerified(ctx context.Context, ids []uuid.UUID) error {
+	if err := r.UserRepository.MarkVerified(ctx, ids); err != nil {
+		return err
+	}
+	r.invalidate(ctx, ids...)
+	return nil
+}
@@ -0,0 +1,137 @@
+package usercache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"auth-service/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+// recordingInvalidator records deleted user IDs
+type recordingInvalidator struct {
+	deleted []uuid.UUID
+}
+
+func (r *recordingInvalidator) Delete(ctx context.Context, id uuid.UUID) error {
+	r.deleted = append(r.deleted, id)
+	return errors.New("cache down") // Must not fail the write
+}
+
+// stubUserRepo accepts every write; failing makes them all fail
+type stubUserRepo struct {
+	domain.UserRepository
+	failing bool
+}
+
+func (r stubUserRepo) err() error {
+	if r.failing {
+		return errors.New("database down")
+	}
+	return nil
+}
+
+func (r stubUserRepo) Update(ctx context.Context, user *domain.User) error { return r.err() }
+func (r stubUserRepo) Delete(ctx context.Context, id uuid.UUID) error      { return r.err() }
+func (r stubUserRepo) UpdateLastLogin(ctx context.Context, id uuid.UUID) error {
+	return r.err()
+}
+func (r stubUserRepo) RecordFailedLogin(ctx context.Context, id uuid.UUID) (int, error) {
+	return 1, r.err()
+}
+func (r stubUserRepo) IncrementTokenVersion(ctx context.Context, id uuid.UUID) (int, error) {
+	return 1, r.err()
+}
+func (r stubUserRepo) SetLockout(ctx context.Context, id uuid.UUID, until *time.Time) error {
+	return r.err()
+}
+func (r stubUserRepo) MarkLockoutNotified(ctx context.Context, id uuid.UUID, at, since time.Time) (bool, error) {
+	return true, r.err()
+}
+func (r stubUserRepo) MarkVerificationSent(ctx context.Context, id uuid.UUID, at, since time.Time) (bool, error) {
+	return true, r.err()
+}
+func (r stubUserRepo) MarkVerified(ctx context.Context, ids []uuid.UUID) error { return r.err() }
+
+func TestInvalidatingUserRepositoryClearsEveryWrite(t *testing.T) {
+	ctx := context.Background()
+	id := uuid.New()
+	writes := map[string]func(domain.UserRepository) error{
+		"Update":          func(r domain.UserRepository) error { return r.Update(ctx, &domain.User{ID: id}) },
+		"Delete":          func(r domain.UserRepository) error { return r.Delete(ctx, id) },
+		"UpdateLastLogin": func(r domain.UserRepository) error { return r.UpdateLastLogin(ctx, id) },
+		"RecordFailedLogin": func(r domain.UserRepository) error {
+			_, err := r.RecordFailedLogin(ctx, id)
+			return err
+		},
+		"IncrementTokenVersion": func(r domain.UserRepository) error {
+			_, err := r.IncrementTokenVersion(ctx, id)
+			return err
+		},
+		"SetLockout": func(r domain.UserRepository) error { return r.SetLockout(ctx, id, nil) },
+		"MarkLockoutNotified": func(r domain.UserRepository) error {
+			_, err := r.MarkLockoutNotified(ctx, id, time.Now(), time.Now())
+			return err
+		},
+		"MarkVerificationSent": func(r domain.UserRepository) error {
+			_, err := r.MarkVerificationSent(ctx, id, time.Now(), time.Now())
+			return err
+		},
+		"MarkVerified": func(r domain.UserRepository) error { return r.MarkVerified(ctx, []uuid.UUID{id}) },
+	}
+	for name, write := range writes {
+		t.Run(name, func(t *testing.T) {
+			cache := &recordingInvalidator{}
+			if err := write(NewInvalidatingUserRepository(stubUserRepo{}, cache)); err != nil {
+				t.Fatalf("a failed cache delete must not fail the write: %v", err)
+			}
+			if len(cache.deleted) != 1 || cache.deleted[0] != id {
+				t.Errorf("deleted = %v, want [%s]", cache.deleted, id)
+			}
+		})
+	}
+
+	// A failed update changed nothing, so the entry stays
+	cache := &recordingInvalidator{}
+	if err := NewInvalidatingUserRepository(stubUserRepo{failing: true}, cache).Update(ctx, &domain.User{ID: id}); err == nil {
+		t.Fatal("database error was not returned")
+	}
+	if len(cache.deleted) != 0 {
+		t.Errorf("deleted = %v after a failed update", cache.deleted)
+	}
+}
+
+func TestEncodeKeepsHiddenFieldsButNotPasswordHash(t *testing.T) {
+	lockedUntil := time.Now().Add(time.Hour).UTC()
+	user := &domain.User{
+		ID:                 uuid.New(),
+		Email:              "jane@example.com",
+		PasswordHash:       "$2a$12$secret",
+		IsActive:           true,
+		TokenVersion:       3,
+		MustChangePassword: true,
+		LockedUntil:        &lockedUntil,
+	}
+
+	data, err := encode(user)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cached, err := decode(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cached.PasswordHash != "" {
+		t.Error("password hash was cached")
+	}
+	if cached.ID != user.ID || cached.TokenVersion != 3 || !cached.MustChangePassword || !cached.IsActive ||
+		cached.LockedUntil == nil || !cached.LockedUntil.Equal(lockedUntil) {
+		t.Errorf("cached user = %+v", cached)
+	}
+	if user.PasswordHash == "" {
+		t.Error("encode modified the caller's user")
+	}
+}
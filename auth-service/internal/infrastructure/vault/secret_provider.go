@@ -0,0 +1,95 @@
+// Package vault reads the JWT signing secrets from a HashiCorp Vault KV v2
+// secret, so they can be rotated in Vault without redeploying the service
+package vault
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ErrSecretMissing is returned when the Vault secret has no "current" field
+var ErrSecretMissing = errors.New("vault: secret has no current signing key")
+
+// SecretProvider implements security.SecretProvider on top of a KV v2
+// secret with two fields: "current", the signing secret, and "previous",
+// the comma-separated secrets it replaced, newest first
+type SecretProvider struct {
+	client *http.Client
+	url    string
+	token  string
+}
+
+// NewSecretProvider creates a provider reading path (for example
+// "secret/data/auth-service/jwt") from the Vault server at addr
+func NewSecretProvider(addr, token, path string, timeout time.Duration) *SecretProvider {
+	return &SecretProvider{
+		client: &http.Client{Timeout: timeout},
+		url:    strings.TrimRight(addr, "/") + "/v1/" + strings.TrimLeft(path, "/"),
+		token:  token,
+	}
+}
+
+// CurrentSigningKey returns the secret new tokens are signed with
+func (p *SecretProvider) CurrentSigningKey(ctx context.Context) (string, error) {
+	secret, err := p.read(ctx)
+	if err != nil {
+		return "", err
+	}
+	if secret.Current == "" {
+		return "", ErrSecretMissing
+	}
+	return secret.Current, nil
+}
+
+// VerificationKeys returns the previous secrets, still accepted for verification
+func (p *SecretProvider) VerificationKeys(ctx context.Context) ([]string, error) {
+	secret, err := p.read(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var keys []string
+	for _, key := range strings.Split(secret.Previous, ",") {
+		if key = strings.TrimSpace(key); key != "" {
+			keys = append(keys, key)
+		}
+	}
+	return keys, nil
+}
+
+type jwtSecret struct {
+	Current  string `json:"current"`
+	Previous string `json:"previous"`
+}
+
+func (p *SecretProvider) read(ctx context.Context) (*jwtSecret, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Vault-Token", p.token)
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("vault: unexpected status %d", resp.StatusCode)
+	}
+
+	// KV v2 wraps the secret's fields in data.data
+	var body struct {
+		Data struct {
+			Data jwtSecret `json:"data"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("vault: decode secret: %w", err)
+	}
+	return &body.Data.Data, nil
+}
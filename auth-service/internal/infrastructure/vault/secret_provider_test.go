@@ -0,0 +1,56 @@
+package vault
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSecretProvider(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/secret/data/auth-service/jwt" {
+			t.Errorf("requested %q", r.URL.Path)
+		}
+		if r.Header.Get("X-Vault-Token") != "s.token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		fmt.Fprint(w, `{"data":{"data":{"current":"new-secret","previous":"old-secret, older-secret"},"metadata":{"version":3}}}`)
+	}))
+	defer server.Close()
+
+	p := NewSecretProvider(server.URL+"/", "s.token", "/secret/data/auth-service/jwt", time.Second)
+	current, err := p.CurrentSigningKey(context.Background())
+	if err != nil || current != "new-secret" {
+		t.Errorf("CurrentSigningKey = %q, %v", current, err)
+	}
+	previous, err := p.VerificationKeys(context.Background())
+	if err != nil || !reflect.DeepEqual(previous, []string{"old-secret", "older-secret"}) {
+		t.Errorf("VerificationKeys = %q, %v", previous, err)
+	}
+
+	p = NewSecretProvider(server.URL, "wrong", "secret/data/auth-service/jwt", time.Second)
+	if _, err := p.CurrentSigningKey(context.Background()); err == nil {
+		t.Error("expected an error for a rejected token")
+	}
+}
+
+func TestSecretProviderRequiresCurrentKey(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"data":{"data":{"previous":"old-secret"}}}`)
+	}))
+	defer server.Close()
+
+	p := NewSecretProvider(server.URL, "s.token", "secret/data/jwt", time.Second)
+	if _, err := p.CurrentSigningKey(context.Background()); !errors.Is(err, ErrSecretMissing) {
+		t.Errorf("err = %v, want ErrSecretMissing", err)
+	}
+	if previous, err := p.VerificationKeys(context.Background()); err != nil || len(previous) != 1 {
+		t.Errorf("VerificationKeys = %q, %v", previous, err)
+	}
+}
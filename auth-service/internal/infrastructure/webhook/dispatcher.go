@@ -0,0 +1,206 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"auth-service/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// queueSize is how many events wait for delivery before new ones are dropped
+	queueSize = 1000
+	// dispatchWorkers deliver queued events in parallel, so one slow receiver
+	// does not hold up every other event
+	dispatchWorkers = 4
+)
+
+var (
+	errQueueFull = errors.New("delivery queue full")
+	errStopped   = errors.New("dispatcher stopped before delivery")
+)
+
+// Dispatcher delivers events to every configured URL in the background.
+// Publish only queues the event, so callers never wait for a receiver. Failed
+// attempts are retried with exponential backoff and every outcome is written
+// to the delivery log; errors are never returned to the caller.
+type Dispatcher struct {
+	publishers  []*Publisher
+	deliveries  domain.WebhookDeliveryRepository
+	maxAttempts int
+	backoff     time.Duration
+
+	queue chan Payload
+
+	mu     sync.Mutex
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewDispatcher creates a dispatcher for urls. Deliveries are signed with
+// secret like Publisher's. Each URL gets up to maxAttempts tries, waiting
+// backoff, 2*backoff, 4*backoff, ... between them. A nil deliveries
+// repository only logs failures.
+func NewDispatcher(urls []string, secret string, timeout time.Duration, maxAttempts int, backoff time.Duration, deliveries domain.WebhookDeliveryRepository) *Dispatcher {
+	d := &Dispatcher{
+		deliveries:  deliveries,
+		maxAttempts: max(maxAttempts, 1),
+		backoff:     backoff,
+		queue:       make(chan Payload, queueSize),
+	}
+	for _, u := range urls {
+		d.publishers = append(d.publishers, NewPublisher(u, secret, timeout))
+	}
+	return d
+}
+
+// Publish queues the event and returns right away. Without URLs the event is
+// dropped; when the queue is full it is recorded as not delivered.
+func (d *Dispatcher) Publish(ctx context.Context, event string, data map[string]interface{}) error {
+	if len(d.publishers) == 0 {
+		return nil
+	}
+
+	payload := Payload{ID: uuid.NewString(), Event: event, OccurredAt: time.Now().UTC(), Data: data}
+	select {
+	case d.queue <- payload:
+	default:
+		for _, p := range d.publishers {
+			d.record(payload, p.url, 0, 0, errQueueFull)
+		}
+	}
+	return nil
+}
+
+// Start runs the delivery workers until ctx is cancelled or Stop is called.
+// Calling Start on a running dispatcher does nothing.
+func (d *Dispatcher) Start(ctx context.Context) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.done != nil {
+		return
+	}
+
+	ctx, d.cancel = context.WithCancel(ctx)
+	d.done = make(chan struct{})
+	go d.run(ctx, d.done)
+}
+
+// Stop waits for running attempts to finish and records the events still
+// queued as not delivered
+func (d *Dispatcher) Stop() {
+	d.mu.Lock()
+	cancel, done := d.cancel, d.done
+	d.cancel, d.done = nil, nil
+	d.mu.Unlock()
+
+	if cancel != nil {
+		cancel()
+		<-done
+	}
+
+	for {
+		select {
+		case payload := <-d.queue:
+			for _, p := range d.publishers {
+				d.record(payload, p.url, 0, 0, errStopped)
+			}
+		default:
+			return
+		}
+	}
+}
+
+func (d *Dispatcher) run(ctx context.Context, done chan struct{}) {
+	defer close(done)
+
+	var wg sync.WaitGroup
+	for i := 0; i < dispatchWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case payload := <-d.queue:
+					for _, p := range d.publishers {
+						d.deliver(ctx, p, payload)
+					}
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+// deliver sends payload to one URL, retrying until it succeeds, the attempts
+// run out or the dispatcher stops, and records the outcome
+func (d *Dispatcher) deliver(ctx context.Context, p *Publisher, payload Payload) {
+	wait := d.backoff
+	var (
+		attempts int
+		status   int
+		err      error
+	)
+	for attempts < d.maxAttempts {
+		if attempts > 0 {
+			select {
+			case <-ctx.Done():
+				d.record(payload, p.url, attempts, status, err)
+				return
+			case <-time.After(wait):
+			}
+			wait *= 2
+		}
+
+		attempts++
+		// Not ctx: stopping must not cut off an attempt half way; the
+		// client timeout bounds it
+		status, err = p.deliver(context.Background(), payload)
+		if err == nil || !retryable(status) {
+			break
+		}
+	}
+	d.record(payload, p.url, attempts, status, err)
+}
+
+// retryable reports whether a failed attempt may succeed later: no response
+// at all, a server error, a timeout or rate limiting. Other client errors
+// will fail the same way again.
+func retryable(status int) bool {
+	return status == 0 || status >= 500 || status == http.StatusRequestTimeout || status == http.StatusTooManyRequests
+}
+
+// record writes the delivery log. A failed write must not stop deliveries,
+// so the error is only logged.
+func (d *Dispatcher) record(payload Payload, url string, attempts, status int, deliveryErr error) {
+	delivery := &domain.WebhookDelivery{
+		Event:      payload.Event,
+		URL:        url,
+		Attempts:   attempts,
+		Delivered:  deliveryErr == nil,
+		StatusCode: status,
+		CreatedAt:  time.Now(),
+	}
+	if id, err := uuid.Parse(payload.ID); err == nil {
+		delivery.EventID = id
+	}
+	if deliveryErr != nil {
+		delivery.Error = deliveryErr.Error()
+		log.Printf("webhook: %s to %s not delivered after %d attempts: %v", payload.Event, url, attempts, deliveryErr)
+	}
+
+	if d.deliveries == nil {
+		return
+	}
+	if err := d.deliveries.Create(context.Background(), delivery); err != nil {
+		log.Printf("webhook: failed to record %s delivery: %v", payload.Event, err)
+	}
+}
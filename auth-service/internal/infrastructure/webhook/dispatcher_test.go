@@ -0,0 +1,204 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"auth-service/internal/domain"
+	"auth-service/pkg/security"
+)
+
+type memoryDeliveries struct {
+	mu         sync.Mutex
+	deliveries []*domain.WebhookDelivery
+}
+
+func (m *memoryDeliveries) Create(ctx context.Context, delivery *domain.WebhookDelivery) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.deliveries = append(m.deliveries, delivery)
+	return nil
+}
+
+func (m *memoryDeliveries) list() []*domain.WebhookDelivery {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]*domain.WebhookDelivery(nil), m.deliveries...)
+}
+
+// waitForDeliveries polls the log until n deliveries are recorded
+func waitForDeliveries(t *testing.T, log *memoryDeliveries, n int) []*domain.WebhookDelivery {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if got := log.list(); len(got) >= n {
+			return got
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	t.Fatalf("got %d deliveries, want %d", len(log.list()), n)
+	return nil
+}
+
+func TestDispatcherDeliversSignedEventToEveryURL(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		received = map[string]Payload{}
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		ts, _ := strconv.ParseInt(r.Header.Get(SignatureTimestampHeader), 10, 64)
+		if !security.VerifyRequestSignature([]byte("hook-secret"), r.Method, r.URL.RequestURI(), body, ts, r.Header.Get(SignatureHeader)) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		var p Payload
+		json.Unmarshal(body, &p)
+		mu.Lock()
+		received[r.URL.Path] = p
+		mu.Unlock()
+	}))
+	defer srv.Close()
+
+	deliveries := &memoryDeliveries{}
+	d := NewDispatcher([]string{srv.URL + "/a", srv.URL + "/b"}, "hook-secret", time.Second, 3, time.Millisecond, deliveries)
+	d.Start(context.Background())
+	defer d.Stop()
+
+	if err := d.Publish(context.Background(), "user.registered", map[string]interface{}{"user_id": "42"}); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, delivery := range waitForDeliveries(t, deliveries, 2) {
+		if !delivery.Delivered || delivery.Attempts != 1 || delivery.StatusCode != http.StatusOK {
+			t.Errorf("delivery = %+v", delivery)
+		}
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	a, b := received["/a"], received["/b"]
+	if a.Event != "user.registered" || a.Data["user_id"] != "42" {
+		t.Errorf("payload = %+v", a)
+	}
+	if a.ID == "" || a.ID != b.ID {
+		t.Errorf("event IDs = %q, %q; want the same ID for every URL", a.ID, b.ID)
+	}
+}
+
+func TestDispatcherRetriesFailedDeliveries(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer srv.Close()
+
+	deliveries := &memoryDeliveries{}
+	d := NewDispatcher([]string{srv.URL}, "", time.Second, 5, time.Millisecond, deliveries)
+	d.Start(context.Background())
+	defer d.Stop()
+
+	d.Publish(context.Background(), "password.changed", nil)
+
+	got := waitForDeliveries(t, deliveries, 1)[0]
+	if !got.Delivered || got.Attempts != 3 {
+		t.Errorf("delivery = %+v, want delivered on the 3rd attempt", got)
+	}
+}
+
+func TestDispatcherRecordsFailureAfterLastAttempt(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	deliveries := &memoryDeliveries{}
+	d := NewDispatcher([]string{srv.URL}, "", time.Second, 3, time.Millisecond, deliveries)
+	d.Start(context.Background())
+	defer d.Stop()
+
+	d.Publish(context.Background(), "user.deactivated", nil)
+
+	got := waitForDeliveries(t, deliveries, 1)[0]
+	if got.Delivered || got.Attempts != 3 || got.StatusCode != http.StatusBadGateway || got.Error == "" {
+		t.Errorf("delivery = %+v", got)
+	}
+	if calls.Load() != 3 {
+		t.Errorf("receiver called %d times, want 3", calls.Load())
+	}
+}
+
+func TestDispatcherDoesNotRetryClientErrors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer srv.Close()
+
+	deliveries := &memoryDeliveries{}
+	d := NewDispatcher([]string{srv.URL}, "", time.Second, 5, time.Millisecond, deliveries)
+	d.Start(context.Background())
+	defer d.Stop()
+
+	d.Publish(context.Background(), "user.registered", nil)
+
+	if got := waitForDeliveries(t, deliveries, 1)[0]; got.Delivered || got.Attempts != 1 {
+		t.Errorf("delivery = %+v, want a single failed attempt", got)
+	}
+}
+
+func TestDispatcherPublishDoesNotWaitForReceiver(t *testing.T) {
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer srv.Close()
+
+	d := NewDispatcher([]string{srv.URL}, "", 5*time.Second, 1, time.Millisecond, nil)
+	d.Start(context.Background())
+	defer d.Stop()
+	defer close(release)
+
+	start := time.Now()
+	d.Publish(context.Background(), "user.registered", nil)
+	if elapsed := time.Since(start); elapsed > 100*time.Millisecond {
+		t.Errorf("Publish took %v", elapsed)
+	}
+}
+
+func TestDispatcherStopRecordsQueuedEvents(t *testing.T) {
+	deliveries := &memoryDeliveries{}
+	d := NewDispatcher([]string{"http://hooks.example.com"}, "", time.Second, 3, time.Millisecond, deliveries)
+
+	// Not started, so the event is still queued when the service shuts down
+	d.Publish(context.Background(), "user.registered", nil)
+	d.Stop()
+
+	got := deliveries.list()
+	if len(got) != 1 || got[0].Delivered || got[0].Attempts != 0 || got[0].Error == "" {
+		t.Errorf("deliveries = %+v, want one undelivered entry", got)
+	}
+}
+
+func TestDispatcherDisabledWithoutURLs(t *testing.T) {
+	deliveries := &memoryDeliveries{}
+	d := NewDispatcher(nil, "", time.Second, 3, time.Millisecond, deliveries)
+	if err := d.Publish(context.Background(), "user.registered", nil); err != nil {
+		t.Fatal(err)
+	}
+	d.Start(context.Background())
+	d.Stop()
+	if got := deliveries.list(); len(got) != 0 {
+		t.Errorf("deliveries = %+v, want none", got)
+	}
+}
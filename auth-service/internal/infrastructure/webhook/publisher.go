@@ -0,0 +1,99 @@
+// Package webhook delivers account events to an external HTTP endpoint
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"auth-service/pkg/security"
+
+	"github.com/google/uuid"
+)
+
+// Headers set on every delivery
+const (
+	EventHeader              = "X-Webhook-Event"
+	SignatureHeader          = "X-Signature"
+	SignatureTimestampHeader = "X-Signature-Timestamp"
+)
+
+// Payload is the JSON body of a delivery
+type Payload struct {
+	// ID identifies the event; retries and other URLs get the same ID so
+	// receivers can drop duplicates
+	ID         string                 `json:"id"`
+	Event      string                 `json:"event"`
+	OccurredAt time.Time              `json:"occurred_at"`
+	Data       map[string]interface{} `json:"data"`
+}
+
+// Publisher posts events as JSON. When a secret is set, deliveries are
+// signed with security.SignRequest so receivers can verify them the same way
+// internal requests are verified.
+type Publisher struct {
+	url    string
+	secret []byte
+	client *http.Client
+}
+
+// NewPublisher creates a new webhook publisher. An empty url disables
+// delivery.
+func NewPublisher(url, secret string, timeout time.Duration) *Publisher {
+	return &Publisher{
+		url:    url,
+		secret: []byte(secret),
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+// Publish delivers a single event. Non-2xx responses are returned as errors.
+func (p *Publisher) Publish(ctx context.Context, event string, data map[string]interface{}) error {
+	if p.url == "" {
+		return nil
+	}
+	_, err := p.deliver(ctx, Payload{ID: uuid.NewString(), Event: event, OccurredAt: time.Now().UTC(), Data: data})
+	return err
+}
+
+// deliver makes one delivery attempt and returns the response status, or 0
+// when no response was received
+func (p *Publisher) deliver(ctx context.Context, payload Payload) (int, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return 0, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, payload.Event)
+	if len(p.secret) > 0 {
+		target, err := url.Parse(p.url)
+		if err != nil {
+			return 0, err
+		}
+		// Signed at send time so retries stay within the receiver's max skew
+		ts := time.Now().Unix()
+		req.Header.Set(SignatureTimestampHeader, strconv.FormatInt(ts, 10))
+		req.Header.Set(SignatureHeader, security.SignRequest(p.secret, http.MethodPost, target.RequestURI(), body, ts))
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("webhook %s: unexpected status %d", payload.Event, resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
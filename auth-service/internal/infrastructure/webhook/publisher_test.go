@@ -0,0 +1,63 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"auth-service/pkg/security"
+)
+
+func TestPublisherDeliversSignedEvent(t *testing.T) {
+	var (
+		got     Payload
+		headers http.Header
+		valid   bool
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		headers = r.Header
+		ts, _ := strconv.ParseInt(r.Header.Get(SignatureTimestampHeader), 10, 64)
+		valid = security.VerifyRequestSignature([]byte("hook-secret"), r.Method, r.URL.RequestURI(), body, ts, r.Header.Get(SignatureHeader))
+		json.Unmarshal(body, &got)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	p := NewPublisher(srv.URL+"/hooks?source=auth", "hook-secret", time.Second)
+	if err := p.Publish(context.Background(), "user.pending_approval", map[string]interface{}{"user_id": "42"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if got.Event != "user.pending_approval" || got.Data["user_id"] != "42" {
+		t.Errorf("payload = %+v", got)
+	}
+	if headers.Get(EventHeader) != "user.pending_approval" {
+		t.Errorf("event header = %q", headers.Get(EventHeader))
+	}
+	if !valid {
+		t.Error("signature did not verify")
+	}
+}
+
+func TestPublisherReturnsErrorOnFailureStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	if err := NewPublisher(srv.URL, "", time.Second).Publish(context.Background(), "user.approved", nil); err == nil {
+		t.Error("expected an error for a 502 response")
+	}
+}
+
+func TestPublisherDisabledWithoutURL(t *testing.T) {
+	if err := NewPublisher("", "", time.Second).Publish(context.Background(), "user.approved", nil); err != nil {
+		t.Errorf("got %v, want nil", err)
+	}
+}
@@ -0,0 +1,82 @@
+// Package authctx carries the authenticated caller of a request through the
+// gin context. AuthMiddleware parses the token claims once and stores them
+// with Set; handlers and later middleware read them back with From instead
+// of looking up and parsing individual string keys.
+package authctx
+
+import (
+	"fmt"
+	"time"
+
+	"auth-service/pkg/security"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// contextKey is the gin context key of the *AuthContext
+const contextKey = "authctx"
+
+// AuthContext is the caller authenticated by an access token
+type AuthContext struct {
+	UserID   uuid.UUID
+	Email    string
+	Username string
+	Role     string
+	Scopes   []string
+	// OrgID is uuid.Nil for tokens issued before multi-tenancy
+	OrgID uuid.UUID
+	// SessionID is the refresh token record the access token belongs to;
+	// uuid.Nil for tokens without a sid claim
+	SessionID uuid.UUID
+	// TokenID is the jti claim, used to revoke the access token
+	TokenID   string
+	ExpiresAt time.Time
+	// TokenVersion is the tv claim; 0 for tokens without it
+	TokenVersion int
+	// MustChangePassword is the pwd_chg claim: the user signed in with a
+	// temporary password and has not changed it yet
+	MustChangePassword bool
+}
+
+// FromClaims parses validated token claims. It fails when the user ID is not
+// a UUID; malformed optional IDs are treated as absent.
+func FromClaims(claims *security.JWTClaims) (*AuthContext, error) {
+	userID, err := uuid.Parse(claims.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user ID in token: %w", err)
+	}
+	auth := &AuthContext{
+		UserID:       userID,
+		Email:        claims.Email,
+		Username:     claims.Username,
+		Role:         claims.Role,
+		Scopes:       claims.Scopes,
+		TokenID:      claims.ID,
+		TokenVersion: claims.TokenVersion,
+
+		MustChangePassword: claims.PasswordChangeRequired,
+	}
+	auth.OrgID, _ = uuid.Parse(claims.OrgID)
+	auth.SessionID, _ = uuid.Parse(claims.SessionID)
+	if claims.ExpiresAt != nil {
+		auth.ExpiresAt = claims.ExpiresAt.Time
+	}
+	return auth, nil
+}
+
+// Set stores the authenticated caller of the request
+func Set(c *gin.Context, auth *AuthContext) {
+	c.Set(contextKey, auth)
+}
+
+// From returns the authenticated caller of the request; ok is false when
+// the request did not pass AuthMiddleware
+func From(c *gin.Context) (auth *AuthContext, ok bool) {
+	value, exists := c.Get(contextKey)
+	if !exists {
+		return nil, false
+	}
+	auth, ok = value.(*AuthContext)
+	return auth, ok && auth != nil
+}
@@ -0,0 +1,68 @@
+package authctx
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"auth-service/pkg/security"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+func TestFromClaims(t *testing.T) {
+	userID, orgID, sessionID := uuid.New(), uuid.New(), uuid.New()
+	expiresAt := time.Now().Add(time.Minute).Truncate(time.Second)
+	auth, err := FromClaims(&security.JWTClaims{
+		UserID:       userID.String(),
+		Email:        "jane@example.com",
+		Username:     "jane",
+		Role:         "admin",
+		Scopes:       []string{"users:read"},
+		OrgID:        orgID.String(),
+		SessionID:    sessionID.String(),
+		TokenVersion: 2,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        "jti-1",
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if auth.UserID != userID || auth.OrgID != orgID || auth.SessionID != sessionID || auth.Role != "admin" ||
+		auth.TokenID != "jti-1" || !auth.ExpiresAt.Equal(expiresAt) || len(auth.Scopes) != 1 || auth.TokenVersion != 2 {
+		t.Errorf("auth context = %+v", auth)
+	}
+
+	// Tokens from before multi-tenancy and sessions carry neither claim
+	auth, err = FromClaims(&security.JWTClaims{UserID: userID.String()})
+	if err != nil || auth.OrgID != uuid.Nil || auth.SessionID != uuid.Nil {
+		t.Errorf("without optional claims: %+v, %v", auth, err)
+	}
+
+	if _, err := FromClaims(&security.JWTClaims{UserID: "user-1"}); err == nil {
+		t.Error("expected an error for a user ID that is not a UUID")
+	}
+}
+
+func TestSetAndFrom(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+
+	if _, ok := From(c); ok {
+		t.Error("From succeeded before Set")
+	}
+	c.Set(contextKey, "not an auth context")
+	if _, ok := From(c); ok {
+		t.Error("From accepted a value of another type")
+	}
+
+	want := &AuthContext{UserID: uuid.New()}
+	Set(c, want)
+	if got, ok := From(c); !ok || got != want {
+		t.Errorf("From = %v, %v; want %v", got, ok, want)
+	}
+}
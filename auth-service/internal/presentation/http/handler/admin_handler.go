@@ -0,0 +1,461 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"auth-service/internal/application/dto"
+	"auth-service/internal/application/usecase"
+	"auth-service/internal/domain"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// AdminHandler handles admin HTTP requests
+type AdminHandler struct {
+	adminUseCase *usecase.AdminUseCase
+}
+
+// NewAdminHandler creates a new admin handler
+func NewAdminHandler(adminUseCase *usecase.AdminUseCase) *AdminHandler {
+	return &AdminHandler{adminUseCase: adminUseCase}
+}
+
+// ApproveUser godoc
+// @Summary Approve a pending user
+// @Description Activate an account created while registration approval is required and notify the user
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Success 200 {object} dto.SuccessResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Router /api/admin/users/{id}/approve [post]
+func (h *AdminHandler) ApproveUser(c *gin.Context) {
+	h.decide(c, h.adminUseCase.ApproveUser, "User approved")
+}
+
+// RejectUser godoc
+// @Summary Reject a pending user
+// @Description Reject and delete an account that is pending approval
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Success 200 {object} dto.SuccessResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Router /api/admin/users/{id}/reject [post]
+func (h *AdminHandler) RejectUser(c *gin.Context) {
+	h.decide(c, h.adminUseCase.RejectUser, "User rejected")
+}
+
+// BanUser godoc
+// @Summary Ban a user
+// @Description Deactivate an account and end all of its sessions; its access tokens are rejected immediately
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Success 200 {object} dto.SuccessResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Router /api/admin/users/{id}/ban [post]
+func (h *AdminHandler) BanUser(c *gin.Context) {
+	h.decide(c, func(ctx context.Context, actorID, userID uuid.UUID) error {
+		return h.adminUseCase.SetUserActive(ctx, actorID, userID, false)
+	}, "User banned")
+}
+
+// UnbanUser godoc
+// @Summary Unban a user
+// @Description Reactivate a banned account. The user has to log in again
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Success 200 {object} dto.SuccessResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Router /api/admin/users/{id}/unban [post]
+func (h *AdminHandler) UnbanUser(c *gin.Context) {
+	h.decide(c, func(ctx context.Context, actorID, userID uuid.UUID) error {
+		return h.adminUseCase.SetUserActive(ctx, actorID, userID, true)
+	}, "User unbanned")
+}
+
+// ForceLogout godoc
+// @Summary Log a user out everywhere
+// @Description End all sessions of an account without deactivating it, e.g. when it was compromised. Its access tokens are rejected immediately; without a token blacklist they expire within their TTL
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Success 200 {object} dto.SuccessResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Router /api/admin/users/{id}/logout [post]
+func (h *AdminHandler) ForceLogout(c *gin.Context) {
+	h.decide(c, h.adminUseCase.ForceLogout, "User logged out")
+}
+
+// ResetPassword godoc
+// @Summary Issue a temporary password
+// @Description Replace a user's password with a random temporary one, end all their sessions and email it to them. After signing in with it, the user can only change their password (other endpoints answer 403 password_change_required)
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Success 200 {object} dto.ResetPasswordResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Router /api/admin/users/{id}/reset-password [post]
+func (h *AdminHandler) ResetPassword(c *gin.Context) {
+	auth, ok := authenticated(c)
+	if !ok {
+		return
+	}
+
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_user_id",
+			Message: "Invalid user ID",
+		})
+		return
+	}
+
+	password, err := h.adminUseCase.AdminResetPassword(c.Request.Context(), auth.UserID, userID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.ResetPasswordResponse{TemporaryPassword: password})
+}
+
+// ListUserSessions godoc
+// @Summary List a user's sessions
+// @Description Sessions of a user of the admin's organization with IP, user agent and last use, newest first, for abuse investigation. include_revoked=true also lists revoked and expired sessions that have not been cleaned up yet
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Param include_revoked query bool false "Include revoked and expired sessions"
+// @Success 200 {object} dto.SessionListResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Router /api/admin/users/{id}/sessions [get]
+func (h *AdminHandler) ListUserSessions(c *gin.Context) {
+	auth, ok := authenticated(c)
+	if !ok {
+		return
+	}
+
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_user_id",
+			Message: "Invalid user ID",
+		})
+		return
+	}
+
+	var query dto.UserSessionsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "validation_error",
+			Message: "Invalid query parameters",
+			Details: validationDetails(err),
+		})
+		return
+	}
+
+	sessions, err := h.adminUseCase.ListUserSessions(c.Request.Context(), auth.UserID, userID, query.IncludeRevoked)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SessionListResponse{Sessions: sessions})
+}
+
+// decide runs an admin action on the user in the :id path parameter on
+// behalf of the authenticated admin
+func (h *AdminHandler) decide(c *gin.Context, action func(ctx context.Context, actorID, userID uuid.UUID) error, message string) {
+	auth, ok := authenticated(c)
+	if !ok {
+		return
+	}
+
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_user_id",
+			Message: "Invalid user ID",
+		})
+		return
+	}
+
+	if err := action(c.Request.Context(), auth.UserID, userID); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse{Message: message})
+}
+
+// BulkVerifyEmails godoc
+// @Summary Bulk-verify user emails
+// @Description Mark users imported from a trusted system as email-verified. Entries may be user IDs or emails; each gets its own result
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.BulkVerifyRequest true "User IDs or emails"
+// @Success 200 {object} dto.BulkVerifyResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Router /api/admin/users/verify [post]
+func (h *AdminHandler) BulkVerifyEmails(c *gin.Context) {
+	auth, ok := authenticated(c)
+	if !ok {
+		return
+	}
+
+	var req dto.BulkVerifyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "validation_error",
+			Message: "Invalid request payload",
+			Details: validationDetails(err),
+		})
+		return
+	}
+
+	response, err := h.adminUseCase.BulkVerifyEmails(c.Request.Context(), auth.UserID, req.Users)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// ImportUsers godoc
+// @Summary Import users
+// @Description Create up to 500 users migrated from another system with their existing bcrypt or Argon2id password hashes. Users whose email or username is taken are skipped; each entry gets its own result
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.ImportUsersRequest true "Users to import"
+// @Success 200 {object} dto.ImportUsersResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Router /api/admin/users/import [post]
+func (h *AdminHandler) ImportUsers(c *gin.Context) {
+	auth, ok := authenticated(c)
+	if !ok {
+		return
+	}
+
+	var req dto.ImportUsersRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "validation_error",
+			Message: "Invalid request payload",
+			Details: validationDetails(err),
+		})
+		return
+	}
+
+	response, err := h.adminUseCase.ImportUsers(c.Request.Context(), auth.UserID, req.Users)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// ListUsers godoc
+// @Summary List users
+// @Description Page through the users of the caller's organization, oldest first, optionally filtered
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param role query string false "Only users with this role"
+// @Param is_active query bool false "Only active (true) or deactivated (false) users"
+// @Param is_verified query bool false "Only users with (true) or without (false) a verified email"
+// @Param search query string false "Case-insensitive substring of the email or username"
+// @Param page query int false "Page number, starting at 1" default(1)
+// @Param page_size query int false "Users per page (max 100)" default(20)
+// @Success 200 {object} dto.UserListResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Router /api/admin/users [get]
+func (h *AdminHandler) ListUsers(c *gin.Context) {
+	auth, ok := authenticated(c)
+	if !ok {
+		return
+	}
+
+	var query dto.ListUsersQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "validation_error",
+			Message: "Invalid query parameters",
+			Details: validationDetails(err),
+		})
+		return
+	}
+
+	// The organization is the admin's, loaded by the use case
+	response, err := h.adminUseCase.ListUsers(c.Request.Context(), auth.UserID, domain.UserFilter{
+		Role:       query.Role,
+		IsActive:   query.IsActive,
+		IsVerified: query.IsVerified,
+		Search:     query.Search,
+		Page:       query.Page,
+		PageSize:   query.PageSize,
+	})
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// ChangeRole godoc
+// @Summary Change a user's role
+// @Description Set a user's role. The new role applies from the user's next access token
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "User ID"
+// @Param request body dto.ChangeRoleRequest true "New role"
+// @Success 200 {object} dto.SuccessResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Router /api/admin/users/{id}/role [put]
+func (h *AdminHandler) ChangeRole(c *gin.Context) {
+	var req dto.ChangeRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "validation_error",
+			Message: "Invalid request payload",
+			Details: validationDetails(err),
+		})
+		return
+	}
+
+	auth, ok := authenticated(c)
+	if !ok {
+		return
+	}
+
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_user_id",
+			Message: "Invalid user ID",
+		})
+		return
+	}
+
+	if err := h.adminUseCase.ChangeRole(c.Request.Context(), auth.UserID, userID, req.Role); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse{Message: "Role updated"})
+}
+
+// CreateInvite godoc
+// @Summary Create a registration invite
+// @Description Issue a single-use invite token for the admin's organization. Registering with it (invite_token) is required while registration is invite-only. With an email, only that address can use it
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.CreateInviteRequest false "Invited email (optional)"
+// @Success 201 {object} dto.InviteResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Router /api/admin/invites [post]
+func (h *AdminHandler) CreateInvite(c *gin.Context) {
+	var req dto.CreateInviteRequest
+	// The body is optional: an invite without an email can be used by anyone
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error:   "validation_error",
+				Message: "Invalid request payload",
+				Details: validationDetails(err),
+			})
+			return
+		}
+	}
+
+	auth, ok := authenticated(c)
+	if !ok {
+		return
+	}
+
+	invite, err := h.adminUseCase.CreateInvite(c.Request.Context(), auth.UserID, req.Email)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, invite)
+}
+
+// ListAuditLogs godoc
+// @Summary List audit logs
+// @Description Page through security events (logins, logouts, password changes, admin actions) of the admin's organization, newest first
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param user_id query string false "Only events where this user is the actor or the target"
+// @Param action query string false "Only events with this action, e.g. login_failed"
+// @Param page query int false "Page number, starting at 1" default(1)
+// @Param page_size query int false "Entries per page (max 100)" default(20)
+// @Success 200 {object} dto.AuditLogListResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Router /api/admin/audit-logs [get]
+func (h *AdminHandler) ListAuditLogs(c *gin.Context) {
+	auth, ok := authenticated(c)
+	if !ok {
+		return
+	}
+
+	var query dto.ListAuditLogsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "validation_error",
+			Message: "Invalid query parameters",
+			Details: validationDetails(err),
+		})
+		return
+	}
+
+	filter := domain.AuditLogFilter{Action: query.Action}
+	if query.UserID != "" {
+		// Already validated by the binding
+		filter.UserID = uuid.MustParse(query.UserID)
+	}
+
+	response, err := h.adminUseCase.ListAuditLogs(c.Request.Context(), auth.UserID, filter, query.Page, query.PageSize)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
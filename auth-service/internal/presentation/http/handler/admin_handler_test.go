@@ -0,0 +1,533 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"auth-service/internal/application/dto"
+	"auth-service/internal/application/usecase"
+	"auth-service/internal/domain"
+	"auth-service/internal/infrastructure/blacklist"
+	"auth-service/internal/presentation/http/authctx"
+	"auth-service/pkg/security"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// stubUserRepo implements the lookups the admin handler tests need; other
+// UserRepository methods panic through the nil embedded interface
+type stubUserRepo struct {
+	domain.UserRepository
+	users    map[string]*domain.User
+	verified []uuid.UUID
+	// listed is the filter of the last List call
+	listed domain.UserFilter
+}
+
+func (r *stubUserRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.User, error) {
+	for _, u := range r.users {
+		if u.ID == id {
+			return u, nil
+		}
+	}
+	return nil, domain.ErrNotFound
+}
+
+func (r *stubUserRepo) GetByEmail(ctx context.Context, orgID uuid.UUID, email string) (*domain.User, error) {
+	if u, ok := r.users[email]; ok {
+		return u, nil
+	}
+	return nil, domain.ErrNotFound
+}
+
+func (r *stubUserRepo) ExistsByEmail(ctx context.Context, orgID uuid.UUID, email string) (bool, error) {
+	_, ok := r.users[email]
+	return ok, nil
+}
+
+func (r *stubUserRepo) ExistsByUsername(ctx context.Context, orgID uuid.UUID, username string) (bool, error) {
+	for _, u := range r.users {
+		if strings.EqualFold(u.Username, username) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *stubUserRepo) IncrementTokenVersion(ctx context.Context, id uuid.UUID) (int, error) {
+	u, err := r.GetByID(ctx, id)
+	if err != nil {
+		return 0, err
+	}
+	u.TokenVersion++
+	return u.TokenVersion, nil
+}
+
+func (r *stubUserRepo) BulkCreate(ctx context.Context, users []*domain.User) error {
+	for _, u := range users {
+		r.users[u.Email] = u
+	}
+	return nil
+}
+
+func (r *stubUserRepo) MarkVerified(ctx context.Context, ids []uuid.UUID) error {
+	r.verified = append(r.verified, ids...)
+	return nil
+}
+
+func (r *stubUserRepo) MarkVerificationSent(ctx context.Context, id uuid.UUID, at, since time.Time) (bool, error) {
+	u, err := r.GetByID(ctx, id)
+	if err != nil || (u.VerificationSentAt != nil && u.VerificationSentAt.After(since)) {
+		return false, err
+	}
+	u.VerificationSentAt = &at
+	return true, nil
+}
+
+func (r *stubUserRepo) List(ctx context.Context, filter domain.UserFilter) ([]*domain.User, int64, error) {
+	r.listed = filter
+	var users []*domain.User
+	for _, u := range r.users {
+		if filter.Role == "" || u.Role == filter.Role {
+			users = append(users, u)
+		}
+	}
+	return users, int64(len(users)), nil
+}
+
+func TestAdminHandlerBulkVerifyEmails(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	admin := &domain.User{ID: uuid.New(), Email: "admin@example.com", Username: "admin", Role: domain.RoleAdmin}
+	repo := &stubUserRepo{users: map[string]*domain.User{
+		"jane@example.com": {ID: uuid.New(), Email: "jane@example.com"},
+		admin.Email:        admin,
+	}}
+	h := NewAdminHandler(usecase.NewAdminUseCase(repo, nil, nil, nil, nil))
+
+	router := gin.New()
+	router.POST("/admin/users/verify", func(c *gin.Context) {
+		authctx.Set(c, &authctx.AuthContext{UserID: admin.ID})
+	}, h.BulkVerifyEmails)
+
+	tests := []struct {
+		name string
+		body string
+		want int
+	}{
+		{"empty list", `{"users":[]}`, http.StatusBadRequest},
+		{"blank entry", `{"users":[""]}`, http.StatusBadRequest},
+		{"per-item results", `{"users":["jane@example.com","nobody@example.com"]}`, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/admin/users/verify", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tt.want {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.want, rec.Body)
+			}
+			if rec.Code != http.StatusOK {
+				return
+			}
+
+			var resp dto.BulkVerifyResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatal(err)
+			}
+			if resp.Verified != 1 || len(resp.Results) != 2 ||
+				resp.Results[0].Status != dto.BulkVerifyStatusVerified ||
+				resp.Results[1].Status != dto.BulkVerifyStatusNotFound {
+				t.Errorf("response = %+v", resp)
+			}
+			if len(repo.verified) != 1 {
+				t.Errorf("verified ids = %v", repo.verified)
+			}
+		})
+	}
+}
+
+func TestAdminHandlerImportUsers(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	admin := &domain.User{ID: uuid.New(), Email: "admin@example.com", Username: "admin", Role: domain.RoleAdmin}
+	repo := &stubUserRepo{users: map[string]*domain.User{
+		"jane@example.com": {ID: uuid.New(), Email: "jane@example.com", Username: "jane"},
+		admin.Email:        admin,
+	}}
+	h := NewAdminHandler(usecase.NewAdminUseCase(repo, nil, nil, nil, nil))
+
+	router := gin.New()
+	router.POST("/admin/users/import", func(c *gin.Context) {
+		authctx.Set(c, &authctx.AuthContext{UserID: admin.ID})
+	}, h.ImportUsers)
+
+	hash, _ := security.NewBcryptHasher(4).Hash("old-system-password")
+	tests := []struct {
+		name string
+		body string
+		want int
+	}{
+		{"empty list", `{"users":[]}`, http.StatusBadRequest},
+		{"missing hash", `{"users":[{"email":"john@example.com","username":"john"}]}`, http.StatusBadRequest},
+		{"unknown algorithm", `{"users":[{"email":"john@example.com","username":"john","password_hash":"x","algorithm":"md5"}]}`, http.StatusBadRequest},
+		{"per-item results", `{"users":[{"email":"john@example.com","username":"john","password_hash":"` + hash + `"},` +
+			`{"email":"jane@example.com","username":"jane","password_hash":"` + hash + `"}]}`, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/admin/users/import", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tt.want {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.want, rec.Body)
+			}
+			if rec.Code != http.StatusOK {
+				return
+			}
+
+			var resp dto.ImportUsersResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatal(err)
+			}
+			if resp.Created != 1 || len(resp.Results) != 2 ||
+				resp.Results[0].Status != dto.ImportStatusCreated ||
+				resp.Results[1].Status != dto.ImportStatusDuplicate {
+				t.Errorf("response = %+v", resp)
+			}
+			if repo.users["john@example.com"] == nil {
+				t.Error("imported user was not stored")
+			}
+		})
+	}
+}
+
+func TestAdminHandlerListUsers(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	orgID := uuid.New()
+	admin := &domain.User{ID: uuid.New(), OrganizationID: orgID, Email: "jane@example.com", Role: "admin", PasswordHash: "$2a$10$secret-hash"}
+	repo := &stubUserRepo{users: map[string]*domain.User{
+		admin.Email:        admin,
+		"john@example.com": {ID: uuid.New(), OrganizationID: orgID, Email: "john@example.com", Role: "user"},
+	}}
+	router := gin.New()
+	router.GET("/admin/users", func(c *gin.Context) {
+		// The organization comes from the admin's record, not from the token
+		authctx.Set(c, &authctx.AuthContext{UserID: admin.ID, OrgID: uuid.Nil})
+	}, NewAdminHandler(usecase.NewAdminUseCase(repo, nil, nil, nil, nil)).ListUsers)
+
+	tests := []struct {
+		name  string
+		query string
+		want  int
+	}{
+		{"bad page", "?page=-1", http.StatusBadRequest},
+		{"page size too large", "?page_size=101", http.StatusBadRequest},
+		{"bad is_active", "?is_active=maybe", http.StatusBadRequest},
+		{"search too long", "?search=" + strings.Repeat("a", 101), http.StatusBadRequest},
+		{"by role", "?role=admin&is_active=true&search=jane&page=1&page_size=10", http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/admin/users"+tt.query, nil))
+
+			if rec.Code != tt.want {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.want, rec.Body)
+			}
+			if rec.Code != http.StatusOK {
+				return
+			}
+
+			var resp dto.UserListResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatal(err)
+			}
+			if resp.Total != 1 || resp.PageSize != 10 || len(resp.Users) != 1 || resp.Users[0].Role != "admin" {
+				t.Errorf("response = %+v", resp)
+			}
+			if strings.Contains(rec.Body.String(), "secret-hash") || strings.Contains(rec.Body.String(), "password") {
+				t.Errorf("response exposes the password hash: %s", rec.Body)
+			}
+			f := repo.listed
+			if f.OrganizationID != orgID || f.IsActive == nil || !*f.IsActive || f.IsVerified != nil || f.Search != "jane" {
+				t.Errorf("filter = %+v", f)
+			}
+		})
+	}
+}
+
+func (r *stubUserRepo) Update(ctx context.Context, user *domain.User) error {
+	r.users[user.Email] = user
+	return nil
+}
+
+func TestAdminHandlerChangeRole(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	user := &domain.User{ID: uuid.New(), Email: "jane@example.com", Role: domain.RoleUser}
+	admin := &domain.User{ID: uuid.New(), Email: "admin@example.com", Role: domain.RoleAdmin}
+	repo := &stubUserRepo{users: map[string]*domain.User{user.Email: user, admin.Email: admin}}
+	router := gin.New()
+	router.PUT("/admin/users/:id/role", func(c *gin.Context) {
+		authctx.Set(c, &authctx.AuthContext{UserID: admin.ID})
+	}, NewAdminHandler(usecase.NewAdminUseCase(repo, nil, nil, nil, nil)).ChangeRole)
+
+	tests := []struct {
+		name string
+		id   string
+		body string
+		want int
+	}{
+		{"unknown role", user.ID.String(), `{"role":"root"}`, http.StatusBadRequest},
+		{"bad id", "nope", `{"role":"admin"}`, http.StatusBadRequest},
+		{"unknown user", uuid.NewString(), `{"role":"admin"}`, http.StatusNotFound},
+		{"promote", user.ID.String(), `{"role":"admin"}`, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPut, "/admin/users/"+tt.id+"/role", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tt.want {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.want, rec.Body)
+			}
+		})
+	}
+	if repo.users[user.Email].Role != domain.RoleAdmin {
+		t.Errorf("role = %q, want admin", repo.users[user.Email].Role)
+	}
+}
+
+func TestAdminHandlerBanUser(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	user := &domain.User{ID: uuid.New(), Email: "jane@example.com", IsActive: true}
+	actor := &domain.User{ID: uuid.New(), Email: "admin@example.com", Role: domain.RoleAdmin}
+	repo := &stubUserRepo{users: map[string]*domain.User{user.Email: user, actor.Email: actor}}
+	session := &domain.RefreshToken{ID: uuid.New(), UserID: user.ID, TokenHash: security.HashToken("refresh"), CreatedAt: time.Now()}
+	refreshTokens := newStubRefreshTokenRepo(session)
+	revoked := blacklist.NewMemoryTokenBlacklist()
+	h := NewAdminHandler(usecase.NewAdminUseCase(repo, nil, nil, nil, nil,
+		usecase.WithSessionRevocation(refreshTokens, revoked, 15*time.Minute)))
+
+	router := gin.New()
+	admin := router.Group("/admin/users", func(c *gin.Context) {
+		authctx.Set(c, &authctx.AuthContext{UserID: actor.ID})
+	})
+	admin.POST("/:id/ban", h.BanUser)
+	admin.POST("/:id/unban", h.UnbanUser)
+
+	tests := []struct {
+		name       string
+		path       string
+		want       int
+		wantActive bool
+	}{
+		{"bad id", "/admin/users/nope/ban", http.StatusBadRequest, true},
+		{"unknown user", "/admin/users/" + uuid.NewString() + "/ban", http.StatusNotFound, true},
+		{"ban", "/admin/users/" + user.ID.String() + "/ban", http.StatusOK, false},
+		{"unban", "/admin/users/" + user.ID.String() + "/unban", http.StatusOK, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, tt.path, nil))
+			if rec.Code != tt.want {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.want, rec.Body)
+			}
+			if repo.users[user.Email].IsActive != tt.wantActive {
+				t.Errorf("is_active = %v, want %v", repo.users[user.Email].IsActive, tt.wantActive)
+			}
+		})
+	}
+
+	if !refreshTokens.revokedAll {
+		t.Error("ban did not revoke the user's refresh tokens")
+	}
+	if ok, _ := revoked.Contains(context.Background(), session.ID.String()); !ok {
+		t.Error("ban did not blacklist the user's session")
+	}
+}
+
+func TestAdminHandlerListUserSessions(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	user := &domain.User{ID: uuid.New(), Email: "jane@example.com", IsActive: true}
+	admin := &domain.User{ID: uuid.New(), Email: "admin@example.com", Role: domain.RoleAdmin}
+	repo := &stubUserRepo{users: map[string]*domain.User{user.Email: user, admin.Email: admin}}
+	session := &domain.RefreshToken{ID: uuid.New(), UserID: user.ID, TokenHash: security.HashToken("refresh"),
+		IPAddress: "203.0.113.7", UserAgent: "curl/8.0", IsRevoked: true, CreatedAt: time.Now(), ExpiresAt: time.Now().Add(time.Hour)}
+	h := NewAdminHandler(usecase.NewAdminUseCase(repo, nil, nil, nil, nil,
+		usecase.WithSessionRevocation(newStubRefreshTokenRepo(session), nil, 15*time.Minute)))
+
+	router := gin.New()
+	router.GET("/admin/users/:id/sessions", func(c *gin.Context) {
+		authctx.Set(c, &authctx.AuthContext{UserID: admin.ID})
+	}, h.ListUserSessions)
+
+	tests := []struct {
+		name string
+		path string
+		want int
+	}{
+		{"bad id", "/admin/users/nope/sessions", http.StatusBadRequest},
+		{"bad flag", "/admin/users/" + user.ID.String() + "/sessions?include_revoked=maybe", http.StatusBadRequest},
+		{"unknown user", "/admin/users/" + uuid.NewString() + "/sessions", http.StatusNotFound},
+		{"with revoked", "/admin/users/" + user.ID.String() + "/sessions?include_revoked=true", http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+			if rec.Code != tt.want {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.want, rec.Body)
+			}
+			if rec.Code != http.StatusOK {
+				return
+			}
+			var body dto.SessionListResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatal(err)
+			}
+			if len(body.Sessions) != 1 || !body.Sessions[0].Revoked || body.Sessions[0].IPAddress != "203.0.113.7" {
+				t.Errorf("sessions = %+v", body.Sessions)
+			}
+		})
+	}
+}
+
+func TestAdminHandlerForceLogout(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	user := &domain.User{ID: uuid.New(), Email: "jane@example.com", IsActive: true}
+	admin := &domain.User{ID: uuid.New(), Email: "admin@example.com", Role: domain.RoleAdmin}
+	repo := &stubUserRepo{users: map[string]*domain.User{user.Email: user, admin.Email: admin}}
+	session := &domain.RefreshToken{ID: uuid.New(), UserID: user.ID, TokenHash: security.HashToken("refresh"), CreatedAt: time.Now()}
+	refreshTokens := newStubRefreshTokenRepo(session)
+	revoked := blacklist.NewMemoryTokenBlacklist()
+	h := NewAdminHandler(usecase.NewAdminUseCase(repo, nil, nil, nil, nil,
+		usecase.WithSessionRevocation(refreshTokens, revoked, 15*time.Minute)))
+
+	router := gin.New()
+	router.POST("/admin/users/:id/logout", func(c *gin.Context) {
+		authctx.Set(c, &authctx.AuthContext{UserID: admin.ID})
+	}, h.ForceLogout)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/admin/users/"+uuid.NewString()+"/logout", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("unknown user: status = %d, want 404", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/admin/users/"+user.ID.String()+"/logout", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body)
+	}
+	if !refreshTokens.revokedAll {
+		t.Error("force logout did not revoke the user's refresh tokens")
+	}
+	if ok, _ := revoked.Contains(context.Background(), session.ID.String()); !ok {
+		t.Error("force logout did not blacklist the user's session")
+	}
+	if !user.IsActive {
+		t.Error("force logout deactivated the account")
+	}
+}
+
+func TestAdminHandlerResetPassword(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	hasher := security.NewBcryptHasher(4)
+	user := &domain.User{ID: uuid.New(), Email: "jane@example.com", IsActive: true}
+	admin := &domain.User{ID: uuid.New(), Email: "admin@example.com", Role: domain.RoleAdmin}
+	repo := &stubUserRepo{users: map[string]*domain.User{user.Email: user, admin.Email: admin}}
+	refreshTokens := newStubRefreshTokenRepo()
+	h := NewAdminHandler(usecase.NewAdminUseCase(repo, nil, nil, nil, nil,
+		usecase.WithSessionRevocation(refreshTokens, nil, 15*time.Minute),
+		usecase.WithPasswordHasher(hasher)))
+
+	router := gin.New()
+	router.POST("/admin/users/:id/reset-password", func(c *gin.Context) {
+		authctx.Set(c, &authctx.AuthContext{UserID: admin.ID})
+	}, h.ResetPassword)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/admin/users/"+uuid.NewString()+"/reset-password", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("unknown user: status = %d, want 404", rec.Code)
+	}
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/admin/users/"+user.ID.String()+"/reset-password", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body)
+	}
+	var resp dto.ResetPasswordResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if !hasher.Compare(user.PasswordHash, resp.TemporaryPassword) {
+		t.Error("returned password does not match the stored hash")
+	}
+	if !user.MustChangePassword || !refreshTokens.revokedAll {
+		t.Errorf("must change password = %v, sessions revoked = %v", user.MustChangePassword, refreshTokens.revokedAll)
+	}
+}
+
+type stubAuditLogRepo struct {
+	filter domain.AuditLogFilter
+}
+
+func (r *stubAuditLogRepo) Create(ctx context.Context, log *domain.AuditLog) error { return nil }
+
+func (r *stubAuditLogRepo) List(ctx context.Context, filter domain.AuditLogFilter, page, pageSize int) ([]*domain.AuditLog, int64, error) {
+	r.filter = filter
+	return []*domain.AuditLog{{ID: uuid.New(), Action: filter.Action, TargetID: filter.UserID}}, 1, nil
+}
+
+func TestAdminHandlerListAuditLogs(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	logs := &stubAuditLogRepo{}
+	orgID := uuid.New()
+	admin := &domain.User{ID: uuid.New(), Email: "admin@example.com", OrganizationID: orgID}
+	repo := &stubUserRepo{users: map[string]*domain.User{admin.Email: admin}}
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		authctx.Set(c, &authctx.AuthContext{UserID: admin.ID, OrgID: orgID})
+	})
+	router.GET("/admin/audit-logs", NewAdminHandler(usecase.NewAdminUseCase(repo, nil, nil, nil, logs)).ListAuditLogs)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/admin/audit-logs?user_id=not-a-uuid", nil))
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("invalid user_id: status = %d", rec.Code)
+	}
+
+	userID := uuid.New()
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/admin/audit-logs?user_id="+userID.String()+"&action=logout", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", rec.Code, rec.Body)
+	}
+	if logs.filter.UserID != userID || logs.filter.Action != "logout" || logs.filter.OrganizationID != orgID {
+		t.Errorf("filter = %+v", logs.filter)
+	}
+	var resp dto.AuditLogListResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Total != 1 || len(resp.Logs) != 1 || resp.Logs[0].Action != "logout" || resp.Logs[0].TargetID != userID.String() {
+		t.Errorf("response = %+v", resp)
+	}
+}
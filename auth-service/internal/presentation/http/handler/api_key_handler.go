@@ -0,0 +1,114 @@
+package handler
+
+import (
+	"net/http"
+
+	"auth-service/internal/application/dto"
+	"auth-service/internal/application/usecase"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// APIKeyHandler handles the admin API key endpoints
+type APIKeyHandler struct {
+	apiKeyUseCase *usecase.APIKeyUseCase
+}
+
+// NewAPIKeyHandler creates a new API key handler
+func NewAPIKeyHandler(apiKeyUseCase *usecase.APIKeyUseCase) *APIKeyHandler {
+	return &APIKeyHandler{apiKeyUseCase: apiKeyUseCase}
+}
+
+// CreateAPIKey godoc
+// @Summary Create an API key
+// @Description Create a key for service-to-service calls. The plaintext key is in this response only; the service stores just its SHA-256 hash
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.CreateAPIKeyRequest true "Key name, scopes and optional lifetime"
+// @Success 201 {object} dto.CreateAPIKeyResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Router /api/admin/api-keys [post]
+func (h *APIKeyHandler) CreateAPIKey(c *gin.Context) {
+	auth, ok := authenticated(c)
+	if !ok {
+		return
+	}
+
+	var req dto.CreateAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "validation_error",
+			Message: "Invalid request payload",
+			Details: validationDetails(err),
+		})
+		return
+	}
+
+	response, err := h.apiKeyUseCase.CreateAPIKey(c.Request.Context(), auth.UserID, auth.OrgID, &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, response)
+}
+
+// ListAPIKeys godoc
+// @Summary List API keys
+// @Description List every API key of the admin's organization, including revoked ones. The keys themselves are never returned
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} dto.APIKeyListResponse
+// @Router /api/admin/api-keys [get]
+func (h *APIKeyHandler) ListAPIKeys(c *gin.Context) {
+	auth, ok := authenticated(c)
+	if !ok {
+		return
+	}
+
+	response, err := h.apiKeyUseCase.ListAPIKeys(c.Request.Context(), auth.OrgID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// RevokeAPIKey godoc
+// @Summary Revoke an API key
+// @Description Revoke a key; requests presenting it are rejected from now on
+// @Tags admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "API key ID"
+// @Success 200 {object} dto.SuccessResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Router /api/admin/api-keys/{id} [delete]
+func (h *APIKeyHandler) RevokeAPIKey(c *gin.Context) {
+	auth, ok := authenticated(c)
+	if !ok {
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_api_key_id",
+			Message: "Invalid API key ID",
+		})
+		return
+	}
+
+	if err := h.apiKeyUseCase.RevokeAPIKey(c.Request.Context(), auth.UserID, auth.OrgID, id); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse{Message: "API key revoked"})
+}
@@ -0,0 +1,137 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"auth-service/internal/application/dto"
+	"auth-service/internal/application/usecase"
+	"auth-service/internal/domain"
+	"auth-service/internal/presentation/http/authctx"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// stubAPIKeyRepo keeps API keys in memory for the handler tests
+type stubAPIKeyRepo struct {
+	keys []*domain.APIKey
+}
+
+func (r *stubAPIKeyRepo) Create(ctx context.Context, key *domain.APIKey) error {
+	key.ID = uuid.New()
+	r.keys = append(r.keys, key)
+	return nil
+}
+
+func (r *stubAPIKeyRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.APIKey, error) {
+	for _, k := range r.keys {
+		if k.ID == id {
+			return k, nil
+		}
+	}
+	return nil, domain.ErrNotFound
+}
+
+func (r *stubAPIKeyRepo) GetByHash(ctx context.Context, keyHash string) (*domain.APIKey, error) {
+	for _, k := range r.keys {
+		if k.KeyHash == keyHash {
+			return k, nil
+		}
+	}
+	return nil, domain.ErrNotFound
+}
+
+func (r *stubAPIKeyRepo) List(ctx context.Context, orgID uuid.UUID) ([]*domain.APIKey, error) {
+	return r.keys, nil
+}
+
+func (r *stubAPIKeyRepo) Revoke(ctx context.Context, id uuid.UUID, at time.Time) error {
+	k, err := r.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	k.RevokedAt = &at
+	return nil
+}
+
+func newTestAPIKeyRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	h := NewAPIKeyHandler(usecase.NewAPIKeyUseCase(&stubAPIKeyRepo{}, nil))
+
+	router := gin.New()
+	admin := router.Group("/admin", func(c *gin.Context) {
+		authctx.Set(c, &authctx.AuthContext{UserID: uuid.New()})
+	})
+	admin.POST("/api-keys", h.CreateAPIKey)
+	admin.GET("/api-keys", h.ListAPIKeys)
+	admin.DELETE("/api-keys/:id", h.RevokeAPIKey)
+	return router
+}
+
+func TestAPIKeyHandlerCreateReturnsKeyOnce(t *testing.T) {
+	router := newTestAPIKeyRouter()
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/admin/api-keys",
+		strings.NewReader(`{"name":"billing","scopes":["token:introspect"]}`)))
+	if w.Code != http.StatusCreated {
+		t.Fatalf("create status = %d, body %s", w.Code, w.Body)
+	}
+	var created dto.CreateAPIKeyResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &created); err != nil {
+		t.Fatal(err)
+	}
+	if created.Key == "" {
+		t.Fatal("create response has no key")
+	}
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/api-keys", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("list status = %d", w.Code)
+	}
+	if strings.Contains(w.Body.String(), created.Key) {
+		t.Error("list response exposes the plaintext key")
+	}
+}
+
+func TestAPIKeyHandlerErrors(t *testing.T) {
+	router := newTestAPIKeyRouter()
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		want   int
+		code   string
+	}{
+		{"no scopes", http.MethodPost, "/admin/api-keys", `{"name":"billing","scopes":[]}`, http.StatusBadRequest, "validation_error"},
+		{"unknown scope", http.MethodPost, "/admin/api-keys", `{"name":"billing","scopes":["users:delete"]}`, http.StatusBadRequest, "invalid_scope"},
+		{"bad expiry", http.MethodPost, "/admin/api-keys", `{"name":"billing","scopes":["token:introspect"],"expires_in":"-1h"}`, http.StatusBadRequest, "invalid_expiry"},
+		{"bad id", http.MethodDelete, "/admin/api-keys/nope", "", http.StatusBadRequest, "invalid_api_key_id"},
+		{"unknown key", http.MethodDelete, "/admin/api-keys/" + uuid.NewString(), "", http.StatusNotFound, "api_key_not_found"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body)))
+			if w.Code != tt.want {
+				t.Fatalf("status = %d, want %d (body %s)", w.Code, tt.want, w.Body)
+			}
+			var resp dto.ErrorResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatal(err)
+			}
+			if resp.Error != tt.code {
+				t.Errorf("error = %q, want %q", resp.Error, tt.code)
+			}
+		})
+	}
+}
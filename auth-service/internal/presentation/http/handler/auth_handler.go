@@ -1,11 +1,16 @@
 package handler
 
 import (
+	"context"
+	"errors"
+	"io"
 	"net/http"
 	"strings"
+	"time"
 
 	"auth-service/internal/application/dto"
 	"auth-service/internal/application/usecase"
+	"auth-service/internal/presentation/http/authctx"
 	"auth-service/pkg/security"
 
 	"github.com/gin-gonic/gin"
@@ -16,52 +21,64 @@ import (
 type AuthHandler struct {
 	authUseCase *usecase.AuthUseCase
 	jwtService  *security.JWTService
+
+	// refreshTokenCookie puts refresh tokens in a cookie for every client
+	refreshTokenCookie bool
 }
 
 // NewAuthHandler creates a new auth handler
-func NewAuthHandler(authUseCase *usecase.AuthUseCase, jwtService *security.JWTService) *AuthHandler {
-	return &AuthHandler{
+func NewAuthHandler(authUseCase *usecase.AuthUseCase, jwtService *security.JWTService, opts ...AuthHandlerOption) *AuthHandler {
+	h := &AuthHandler{
 		authUseCase: authUseCase,
 		jwtService:  jwtService,
 	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
 }
 
 // Register godoc
 // @Summary Register a new user
-// @Description Create a new user account
+// @Description Create a new user account in the organization named by organization_slug, or the default organization. With an invite_token the user joins the invite's organization; while registration is invite-only, one is required
 // @Tags auth
 // @Accept json
 // @Produce json
 // @Param request body dto.RegisterRequest true "Registration request"
 // @Success 201 {object} dto.AuthResponse
+// @Success 202 {object} dto.AuthResponse "Account created, pending admin approval"
 // @Failure 400 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
 // @Failure 409 {object} dto.ErrorResponse
-// @Router /auth/register [post]
+// @Failure 503 {object} dto.ErrorResponse
+// @Router /api/auth/register [post]
 func (h *AuthHandler) Register(c *gin.Context) {
 	var req dto.RegisterRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
 			Error:   "validation_error",
 			Message: "Invalid request payload",
-			Details: map[string]string{"validation": err.Error()},
+			Details: validationDetails(err),
 		})
 		return
 	}
 
-	response, err := h.authUseCase.Register(c.Request.Context(), &req)
+	// Closed registration is rejected without touching the database
+	if !h.authUseCase.RegistrationEnabled() {
+		respondError(c, usecase.ErrRegistrationDisabled)
+		return
+	}
+
+	response, err := h.authUseCase.Register(clientContext(c), &req)
 	if err != nil {
-		switch err {
-		case usecase.ErrUserAlreadyExists:
-			c.JSON(http.StatusConflict, dto.ErrorResponse{
-				Error:   "user_exists",
-				Message: "User with this email or username already exists",
-			})
-		default:
-			c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
-				Error:   "internal_error",
-				Message: "Failed to register user",
-			})
-		}
+		respondError(c, err)
+		return
+	}
+
+	// Accounts awaiting admin approval get no tokens yet
+	if response.ApprovalPending {
+		c.JSON(http.StatusAccepted, response)
 		return
 	}
 
@@ -70,119 +87,154 @@ func (h *AuthHandler) Register(c *gin.Context) {
 
 // Login godoc
 // @Summary User login
-// @Description Authenticate user and return tokens
+// @Description Authenticate user and return tokens. With "X-Client-Type: web" the refresh token is set as an HttpOnly cookie and left out of the body
 // @Tags auth
 // @Accept json
 // @Produce json
 // @Param request body dto.LoginRequest true "Login request"
+// @Param X-Client-Type header string false "web to receive the refresh token as a cookie"
 // @Success 200 {object} dto.AuthResponse
 // @Failure 400 {object} dto.ErrorResponse
 // @Failure 401 {object} dto.ErrorResponse
-// @Router /auth/login [post]
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 423 {object} dto.ErrorResponse
+// @Router /api/auth/login [post]
 func (h *AuthHandler) Login(c *gin.Context) {
 	var req dto.LoginRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
 			Error:   "validation_error",
 			Message: "Invalid request payload",
+			Details: validationDetails(err),
 		})
 		return
 	}
 
-	response, err := h.authUseCase.Login(c.Request.Context(), &req)
+	response, err := h.authUseCase.Login(clientContext(c), &req)
 	if err != nil {
-		switch err {
-		case usecase.ErrInvalidCredentials:
-			c.JSON(http.StatusUnauthorized, dto.ErrorResponse{
-				Error:   "invalid_credentials",
-				Message: "Invalid email/username or password",
-			})
-		case usecase.ErrUserInactive:
-			c.JSON(http.StatusForbidden, dto.ErrorResponse{
-				Error:   "user_inactive",
-				Message: "User account is inactive",
-			})
-		default:
-			c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
-				Error:   "internal_error",
-				Message: "Failed to authenticate user",
-			})
+		// A deleted account looks the same as a wrong password
+		if errors.Is(err, usecase.ErrUserNotFound) {
+			err = usecase.ErrInvalidCredentials
 		}
+		respondError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, response)
+	h.respondWithTokens(c, http.StatusOK, response)
 }
 
 // RefreshToken godoc
 // @Summary Refresh access token
-// @Description Get new access token using refresh token
+// @Description Get new access token using refresh token. Replaying an already rotated refresh token revokes every token from the same login. Web clients ("X-Client-Type: web") may send the refresh token cookie instead of the body and get the new one as a cookie
 // @Tags auth
 // @Accept json
 // @Produce json
-// @Param request body dto.RefreshTokenRequest true "Refresh token request"
+// @Param request body dto.RefreshTokenRequest false "Refresh token request"
+// @Param X-Client-Type header string false "web to use the refresh token cookie"
 // @Success 200 {object} dto.AuthResponse
 // @Failure 400 {object} dto.ErrorResponse
 // @Failure 401 {object} dto.ErrorResponse
-// @Router /auth/refresh [post]
+// @Router /api/auth/refresh [post]
 func (h *AuthHandler) RefreshToken(c *gin.Context) {
+	// The body is optional for web clients, who send the cookie instead
 	var req dto.RefreshTokenRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
 		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
 			Error:   "validation_error",
 			Message: "Invalid request payload",
+			Details: validationDetails(err),
+		})
+		return
+	}
+	if req.RefreshToken == "" {
+		req.RefreshToken = h.refreshTokenFromCookie(c)
+	}
+	if req.RefreshToken == "" {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "validation_error",
+			Message: "Invalid request payload",
+			Details: map[string]string{"refresh_token": "is required"},
 		})
 		return
 	}
 
-	response, err := h.authUseCase.RefreshToken(c.Request.Context(), req.RefreshToken)
+	response, err := h.authUseCase.RefreshToken(clientContext(c), req.RefreshToken)
 	if err != nil {
-		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{
-			Error:   "invalid_token",
-			Message: "Invalid or expired refresh token",
-		})
+		// The cookie is useless now; drop it so the browser stops sending it
+		if h.cookieMode(c) && usecase.AsAppError(err) != nil && !errors.Is(err, usecase.ErrRequestTimeout) {
+			clearRefreshTokenCookie(c)
+		}
+		if errors.Is(err, usecase.ErrUserNotFound) {
+			err = usecase.ErrInvalidToken
+		}
+		respondError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, response)
+	h.respondWithTokens(c, http.StatusOK, response)
 }
 
 // Logout godoc
 // @Summary User logout
-// @Description Revoke all refresh tokens for the user
+// @Description Sign out the session of the given refresh token (web clients: the refresh token cookie), or every session with all=true. The access token used for the request is revoked either way.
 // @Tags auth
+// @Accept json
 // @Produce json
 // @Security BearerAuth
+// @Param request body dto.LogoutRequest false "Session to sign out"
+// @Param all query bool false "Sign out every session"
 // @Success 200 {object} dto.SuccessResponse
+// @Failure 400 {object} dto.ErrorResponse
 // @Failure 401 {object} dto.ErrorResponse
-// @Router /auth/logout [post]
+// @Failure 404 {object} dto.ErrorResponse
+// @Router /api/auth/logout [post]
 func (h *AuthHandler) Logout(c *gin.Context) {
-	userID, exists := c.Get("userID")
-	if !exists {
-		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{
-			Error:   "unauthorized",
-			Message: "User not authenticated",
+	// The body is optional; an empty one means "no refresh token"
+	var req dto.LogoutRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "validation_error",
+			Message: "Invalid request payload",
 		})
 		return
 	}
-
-	id, err := uuid.Parse(userID.(string))
-	if err != nil {
+	if req.RefreshToken == "" {
+		req.RefreshToken = h.refreshTokenFromCookie(c)
+	}
+	all := c.Query("all") == "true"
+	if req.RefreshToken == "" && !all {
 		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
-			Error:   "invalid_user_id",
-			Message: "Invalid user ID",
+			Error:   "invalid_request",
+			Message: "refresh_token is required to sign out this device; use all=true to sign out everywhere",
 		})
 		return
 	}
 
-	if err := h.authUseCase.Logout(c.Request.Context(), id); err != nil {
-		c.JSON(http.StatusInternalServerError, dto.ErrorResponse{
-			Error:   "internal_error",
-			Message: "Failed to logout user",
-		})
+	auth, ok := authenticated(c)
+	if !ok {
 		return
 	}
 
+	// Blacklist the access token the request was made with
+	ctx := clientContext(c)
+	if !auth.ExpiresAt.IsZero() {
+		ctx = usecase.ContextWithAccessToken(ctx, auth.TokenID, auth.ExpiresAt)
+	}
+
+	var err error
+	if all {
+		err = h.authUseCase.Logout(ctx, auth.UserID)
+	} else {
+		err = h.authUseCase.LogoutSession(ctx, auth.UserID, req.RefreshToken)
+	}
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	if h.cookieMode(c) {
+		clearRefreshTokenCookie(c)
+	}
 	c.JSON(http.StatusOK, dto.SuccessResponse{
 		Message: "Successfully logged out",
 	})
@@ -190,22 +242,661 @@ func (h *AuthHandler) Logout(c *gin.Context) {
 
 // Me godoc
 // @Summary Get current user
-// @Description Get current authenticated user information
+// @Description Get the current authenticated user's profile and account state (role, scopes, verification, linked social login providers), loaded fresh from the database
 // @Tags auth
 // @Produce json
 // @Security BearerAuth
-// @Success 200 {object} dto.UserInfo
+// @Success 200 {object} dto.MeResponse
 // @Failure 401 {object} dto.ErrorResponse
-// @Router /auth/me [get]
+// @Router /api/auth/me [get]
 func (h *AuthHandler) Me(c *gin.Context) {
-	// Extract user info from context (set by auth middleware)
-	userInfo := map[string]interface{}{
-		"id":       c.GetString("userID"),
-		"email":    c.GetString("email"),
-		"username": c.GetString("username"),
+	auth, ok := authenticated(c)
+	if !ok {
+		return
+	}
+
+	profile, err := h.authUseCase.GetProfile(c.Request.Context(), auth.UserID)
+	if err != nil {
+		respondProfileError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, profile)
+}
+
+// UpdateProfile godoc
+// @Summary Update current user
+// @Description Update the current user's first and last name. Email and username cannot be changed here
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.UpdateProfileRequest true "New first and last name"
+// @Success 200 {object} dto.UserInfo
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Router /api/auth/me [put]
+func (h *AuthHandler) UpdateProfile(c *gin.Context) {
+	auth, ok := authenticated(c)
+	if !ok {
+		return
+	}
+
+	var req dto.UpdateProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "validation_error",
+			Message: "Invalid request payload",
+			Details: validationDetails(err),
+		})
+		return
+	}
+
+	profile, err := h.authUseCase.UpdateProfile(c.Request.Context(), auth.UserID, &req)
+	if err != nil {
+		respondProfileError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, profile)
+}
+
+// ChangeUsername godoc
+// @Summary Change username
+// @Description Change the current user's username. It must pass the same rules as at registration and be unused in the organization, and can only be changed once per USERNAME_CHANGE_COOLDOWN. Access tokens carry the username, so all of them must be refreshed afterwards
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.ChangeUsernameRequest true "New username"
+// @Success 200 {object} dto.UserInfo
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 429 {object} dto.ErrorResponse
+// @Router /api/auth/username [put]
+func (h *AuthHandler) ChangeUsername(c *gin.Context) {
+	auth, ok := authenticated(c)
+	if !ok {
+		return
+	}
+
+	var req dto.ChangeUsernameRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "validation_error",
+			Message: "Invalid request payload",
+			Details: validationDetails(err),
+		})
+		return
+	}
+
+	profile, err := h.authUseCase.ChangeUsername(c.Request.Context(), auth.UserID, req.Username)
+	if err != nil {
+		respondProfileError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, profile)
+}
+
+// ExportUserData godoc
+// @Summary Export my data
+// @Description Download everything stored about the current user as JSON: profile, active sessions (device metadata only) and the audit log entries where the user is the actor or target. Password hashes and token values are never included. Rate limited per user
+// @Tags auth
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} dto.UserDataExport
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 429 {object} dto.ErrorResponse
+// @Router /api/auth/me/export [get]
+func (h *AuthHandler) ExportUserData(c *gin.Context) {
+	auth, ok := authenticated(c)
+	if !ok {
+		return
+	}
+
+	// Mark the session the request was made with
+	ctx := c.Request.Context()
+	if auth.SessionID != uuid.Nil {
+		ctx = usecase.ContextWithSessionID(ctx, auth.SessionID)
+	}
+
+	export, err := h.authUseCase.ExportUserData(ctx, auth.UserID)
+	if err != nil {
+		respondProfileError(c, err)
+		return
+	}
+
+	// Browsers save the response as a file instead of displaying it
+	c.Header("Content-Disposition", `attachment; filename="user-data-`+export.ExportedAt.Format("2006-01-02")+`.json"`)
+	c.JSON(http.StatusOK, export)
+}
+
+// DeactivateAccount godoc
+// @Summary Deactivate current user
+// @Description Deactivate the current user's account and sign out everywhere. Requires the current password
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.ConfirmPasswordRequest true "Current password"
+// @Success 200 {object} dto.SuccessResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Router /api/auth/deactivate [post]
+func (h *AuthHandler) DeactivateAccount(c *gin.Context) {
+	h.closeAccount(c, h.authUseCase.DeactivateAccount, "Account deactivated")
+}
+
+// DeleteAccount godoc
+// @Summary Delete current user
+// @Description Delete the current user's account and sign out everywhere. Email and username are anonymized and can be registered again. Requires the current password
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.ConfirmPasswordRequest true "Current password"
+// @Success 200 {object} dto.SuccessResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Router /api/auth/me [delete]
+func (h *AuthHandler) DeleteAccount(c *gin.Context) {
+	h.closeAccount(c, h.authUseCase.DeleteAccount, "Account deleted")
+}
+
+// closeAccount runs a password-confirmed account action for the
+// authenticated user; the access token of the request is revoked as well
+func (h *AuthHandler) closeAccount(c *gin.Context, action func(ctx context.Context, userID uuid.UUID, password string) error, message string) {
+	auth, ok := authenticated(c)
+	if !ok {
+		return
+	}
+
+	var req dto.ConfirmPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "validation_error",
+			Message: "Invalid request payload",
+			Details: validationDetails(err),
+		})
+		return
+	}
+
+	ctx := clientContext(c)
+	if !auth.ExpiresAt.IsZero() {
+		ctx = usecase.ContextWithAccessToken(ctx, auth.TokenID, auth.ExpiresAt)
+	}
+
+	if err := action(ctx, auth.UserID, req.Password); err != nil {
+		if errors.Is(err, usecase.ErrInvalidCredentials) {
+			err = errIncorrectPassword
+		}
+		respondProfileError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse{Message: message})
+}
+
+// respondProfileError responds to a profile use case error; a deleted user's
+// token is treated as unauthenticated
+func respondProfileError(c *gin.Context, err error) {
+	if errors.Is(err, usecase.ErrUserNotFound) {
+		err = errUnauthorized
+	}
+	respondError(c, err)
+}
+
+// ListSessions godoc
+// @Summary List active sessions
+// @Description List the devices the user is signed in on, with user agent, IP address and last use
+// @Tags auth
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} dto.SessionListResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Router /api/auth/sessions [get]
+func (h *AuthHandler) ListSessions(c *gin.Context) {
+	auth, ok := authenticated(c)
+	if !ok {
+		return
+	}
+
+	// Mark the session the request was made with
+	ctx := c.Request.Context()
+	if auth.SessionID != uuid.Nil {
+		ctx = usecase.ContextWithSessionID(ctx, auth.SessionID)
+	}
+
+	sessions, err := h.authUseCase.ListSessions(ctx, auth.UserID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SessionListResponse{Sessions: sessions})
+}
+
+// CurrentSession godoc
+// @Summary Check the current session
+// @Description Cheap keep-alive probe: 200 while the access token is valid and not revoked, 401 otherwise. Nothing is loaded from the database
+// @Tags auth
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} dto.SessionStatusResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Router /api/auth/session [get]
+func (h *AuthHandler) CurrentSession(c *gin.Context) {
+	auth, ok := authenticated(c)
+	if !ok {
+		return
+	}
+
+	response := dto.SessionStatusResponse{
+		UserID:    auth.UserID.String(),
+		ExpiresAt: auth.ExpiresAt.UTC(),
+		ExpiresIn: max(int64(time.Until(auth.ExpiresAt).Seconds()), 0),
+	}
+	if auth.SessionID != uuid.Nil {
+		response.SessionID = auth.SessionID.String()
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// ListLinkedAccounts godoc
+// @Summary List linked social login accounts
+// @Description List the providers (Google, GitHub...) the user can sign in with and when each was linked
+// @Tags oauth
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} dto.LinkedAccountListResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Router /api/auth/linked-accounts [get]
+func (h *AuthHandler) ListLinkedAccounts(c *gin.Context) {
+	auth, ok := authenticated(c)
+	if !ok {
+		return
+	}
+
+	accounts, err := h.authUseCase.ListLinkedAccounts(c.Request.Context(), auth.UserID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.LinkedAccountListResponse{LinkedAccounts: accounts})
+}
+
+// UnlinkAccount godoc
+// @Summary Unlink a social login account
+// @Description Stop signing in with the provider. A user without a password cannot unlink their last provider; they have to set a password first (forgot password)
+// @Tags oauth
+// @Produce json
+// @Security BearerAuth
+// @Param provider path string true "Provider, e.g. google"
+// @Success 200 {object} dto.SuccessResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Router /api/auth/linked-accounts/{provider} [delete]
+func (h *AuthHandler) UnlinkAccount(c *gin.Context) {
+	auth, ok := authenticated(c)
+	if !ok {
+		return
+	}
+
+	if err := h.authUseCase.UnlinkAccount(c.Request.Context(), auth.UserID, c.Param("provider")); err != nil {
+		respondProfileError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse{Message: "Account unlinked"})
+}
+
+// Introspect godoc
+// @Summary Introspect a token (RFC 7662)
+// @Description Tell trusted services whether an access token is active. Invalid, expired and revoked tokens return {"active": false} with 200
+// @Tags auth
+// @Accept x-www-form-urlencoded
+// @Produce json
+// @Param token formData string true "Access token"
+// @Param token_type_hint formData string false "Ignored; only access tokens can be introspected"
+// @Security ApiKeyAuth
+// @Success 200 {object} dto.IntrospectionResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 503 {object} dto.ErrorResponse
+// @Router /api/auth/introspect [post]
+func (h *AuthHandler) Introspect(c *gin.Context) {
+	var req dto.IntrospectionRequest
+	if err := c.ShouldBind(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_request",
+			Message: "The token parameter is required",
+		})
+		return
+	}
+
+	response, err := h.authUseCase.IntrospectToken(c.Request.Context(), req.Token)
+	if err != nil {
+		if !errors.Is(err, usecase.ErrRequestTimeout) {
+			err = errRevocationCheckFailed
+		}
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// ChangePassword godoc
+// @Summary Change password
+// @Description Change the current user's password. Other sessions are signed out and every access token is invalidated; the current session stays active and gets a new access token on refresh
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.ChangePasswordRequest true "Current and new password"
+// @Success 200 {object} dto.SuccessResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Router /api/auth/password [put]
+func (h *AuthHandler) ChangePassword(c *gin.Context) {
+	auth, ok := authenticated(c)
+	if !ok {
+		return
+	}
+
+	var req dto.ChangePasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "validation_error",
+			Message: "Invalid request payload",
+			Details: validationDetails(err),
+		})
+		return
+	}
+
+	// Keep the session the request was made with; tokens without a sid sign out everywhere
+	ctx := clientContext(c)
+	if auth.SessionID != uuid.Nil {
+		ctx = usecase.ContextWithSessionID(ctx, auth.SessionID)
+	}
+
+	if err := h.authUseCase.ChangePassword(ctx, auth.UserID, req.CurrentPassword, req.NewPassword); err != nil {
+		if errors.Is(err, usecase.ErrInvalidCredentials) {
+			err = errIncorrectPassword
+		}
+		respondProfileError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse{
+		Message: "Password changed successfully",
+	})
+}
+
+// ForgotPassword godoc
+// @Summary Request a password reset
+// @Description Email a password reset link. Always succeeds so registered emails cannot be discovered
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body dto.ForgotPasswordRequest true "Account email"
+// @Success 200 {object} dto.SuccessResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 503 {object} dto.ErrorResponse
+// @Router /api/auth/forgot-password [post]
+func (h *AuthHandler) ForgotPassword(c *gin.Context) {
+	var req dto.ForgotPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "validation_error",
+			Message: "Invalid request payload",
+			Details: validationDetails(err),
+		})
+		return
+	}
+
+	if err := h.authUseCase.RequestPasswordReset(c.Request.Context(), req.OrganizationSlug, req.Email); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse{
+		Message: "If an account with that email exists, a password reset link has been sent",
+	})
+}
+
+// RequestMagicLink godoc
+// @Summary Request a magic sign-in link
+// @Description Email a single-use passwordless sign-in link. Always succeeds so registered emails cannot be discovered
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body dto.MagicLinkRequest true "Account email"
+// @Success 200 {object} dto.SuccessResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 429 {object} dto.ErrorResponse
+// @Router /api/auth/magic-link [post]
+func (h *AuthHandler) RequestMagicLink(c *gin.Context) {
+	var req dto.MagicLinkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "validation_error",
+			Message: "Invalid request payload",
+			Details: validationDetails(err),
+		})
+		return
+	}
+
+	if err := h.authUseCase.RequestMagicLink(c.Request.Context(), req.OrganizationSlug, req.Email); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse{
+		Message: "If an account with that email exists, a sign-in link has been sent",
+	})
+}
+
+// MagicLinkCallback godoc
+// @Summary Sign in with a magic link
+// @Description Consume a magic link token and return access and refresh tokens
+// @Tags auth
+// @Produce json
+// @Param token query string true "Magic link token"
+// @Success 200 {object} dto.AuthResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 423 {object} dto.ErrorResponse
+// @Router /api/auth/magic-link/callback [get]
+func (h *AuthHandler) MagicLinkCallback(c *gin.Context) {
+	token := c.Query("token")
+	if token == "" {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "validation_error",
+			Message: "Query parameter 'token' is required",
+		})
+		return
+	}
+
+	response, err := h.authUseCase.LoginWithMagicLink(clientContext(c), token)
+	if err != nil {
+		if errors.Is(err, usecase.ErrUserNotFound) {
+			err = usecase.ErrInvalidToken
+		}
+		respondError(c, err)
+		return
+	}
+
+	h.respondWithTokens(c, http.StatusOK, response)
+}
+
+// ResetPassword godoc
+// @Summary Reset password
+// @Description Set a new password using a password reset token. Signs the user out everywhere
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body dto.ResetPasswordRequest true "Reset token and new password"
+// @Success 200 {object} dto.SuccessResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 410 {object} dto.ErrorResponse
+// @Router /api/auth/reset-password [post]
+func (h *AuthHandler) ResetPassword(c *gin.Context) {
+	var req dto.ResetPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "validation_error",
+			Message: "Invalid request payload",
+			Details: validationDetails(err),
+		})
+		return
+	}
+
+	if err := h.authUseCase.ResetPassword(clientContext(c), req.Token, req.NewPassword); err != nil {
+		if errors.Is(err, usecase.ErrInvalidToken) || errors.Is(err, usecase.ErrUserNotFound) {
+			err = errInvalidLink
+		}
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse{
+		Message: "Password has been reset",
+	})
+}
+
+// ValidateResetToken godoc
+// @Summary Validate password reset token
+// @Description Check whether a password reset token is valid without consuming it
+// @Tags auth
+// @Produce json
+// @Param token query string true "Password reset token"
+// @Success 200 {object} dto.ResetTokenValidationResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 410 {object} dto.ErrorResponse
+// @Router /api/auth/reset-password/validate [get]
+func (h *AuthHandler) ValidateResetToken(c *gin.Context) {
+	token := c.Query("token")
+	if token == "" {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "validation_error",
+			Message: "Query parameter 'token' is required",
+		})
+		return
+	}
+
+	resetToken, err := h.authUseCase.ValidatePasswordResetToken(c.Request.Context(), token)
+	if err != nil {
+		if errors.Is(err, usecase.ErrInvalidToken) || errors.Is(err, usecase.ErrUserNotFound) {
+			err = errInvalidLink
+		}
+		respondError(c, err)
+		return
 	}
 
-	c.JSON(http.StatusOK, userInfo)
+	c.JSON(http.StatusOK, dto.ResetTokenValidationResponse{
+		Valid:     true,
+		ExpiresAt: resetToken.ExpiresAt,
+	})
+}
+
+// VerifyEmail godoc
+// @Summary Verify email address
+// @Description Consume an email verification token and mark the user's email as verified
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body dto.VerifyEmailRequest true "Verification token"
+// @Success 200 {object} dto.SuccessResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Router /api/auth/verify-email [post]
+func (h *AuthHandler) VerifyEmail(c *gin.Context) {
+	var req dto.VerifyEmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "validation_error",
+			Message: "Invalid request payload",
+			Details: validationDetails(err),
+		})
+		return
+	}
+
+	if err := h.authUseCase.VerifyEmail(c.Request.Context(), req.Token); err != nil {
+		if errors.Is(err, usecase.ErrInvalidToken) || errors.Is(err, usecase.ErrUserNotFound) {
+			err = errInvalidLink
+		}
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse{
+		Message: "Email successfully verified",
+	})
+}
+
+// ResendVerification godoc
+// @Summary Resend verification email
+// @Description Issue a new email verification link for the current user, invalidating older ones. At most one email is sent per VERIFICATION_RESEND_COOLDOWN
+// @Tags auth
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} dto.SuccessResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 429 {object} dto.ErrorResponse "Inside VERIFICATION_RESEND_COOLDOWN or over the per-user rate limit"
+// @Router /api/auth/resend-verification [post]
+func (h *AuthHandler) ResendVerification(c *gin.Context) {
+	auth, ok := authenticated(c)
+	if !ok {
+		return
+	}
+
+	if _, err := h.authUseCase.GenerateEmailVerification(c.Request.Context(), auth.UserID); err != nil {
+		respondProfileError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse{
+		Message: "Verification email sent",
+	})
+}
+
+// RequestVerificationEmail godoc
+// @Summary Resend verification email by address
+// @Description Email a new verification link without signing in. Always succeeds for unknown or already verified addresses so registered emails cannot be discovered; within VERIFICATION_RESEND_COOLDOWN of the last email no new one is sent, with the same response
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body dto.ResendVerificationRequest true "Account email"
+// @Success 200 {object} dto.SuccessResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 429 {object} dto.ErrorResponse "Too many requests from this client for this email (RATE_LIMIT_REQUESTS)"
+// @Router /api/auth/verify-email/resend [post]
+func (h *AuthHandler) RequestVerificationEmail(c *gin.Context) {
+	var req dto.ResendVerificationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "validation_error",
+			Message: "Invalid request payload",
+			Details: validationDetails(err),
+		})
+		return
+	}
+
+	if err := h.authUseCase.ResendVerification(c.Request.Context(), req.OrganizationSlug, req.Email); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.SuccessResponse{
+		Message: "If an unverified account with that email exists, a verification link has been sent",
+	})
 }
 
 // Health godoc
@@ -236,3 +927,130 @@ func extractTokenFromHeader(c *gin.Context) string {
 
 	return parts[1]
 }
+
+// PasswordStrength godoc
+// @Summary Estimate password strength
+// @Description Score a password (0-4) and report whether it satisfies the password policy, with suggestions. Nothing is stored
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body dto.PasswordStrengthRequest true "Password to score"
+// @Success 200 {object} dto.PasswordStrengthResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Router /api/auth/password-strength [post]
+func (h *AuthHandler) PasswordStrength(c *gin.Context) {
+	var req dto.PasswordStrengthRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "validation_error",
+			Message: "Invalid request payload",
+			Details: validationDetails(err),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, h.authUseCase.EstimatePasswordStrength(req.Password, req.Email, req.Username))
+}
+
+// CheckAvailability godoc
+// @Summary Check whether a username or email is free
+// @Description Tell a signup form whether the username and/or email can still be registered. Reserved usernames are reported as taken. Only the fields asked about are returned. Strictly rate limited per IP
+// @Tags auth
+// @Produce json
+// @Param username query string false "Username to check"
+// @Param email query string false "Email to check"
+// @Param organization query string false "Organization slug; empty checks the default organization"
+// @Success 200 {object} dto.AvailabilityResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 429 {object} dto.ErrorResponse
+// @Router /api/auth/availability [get]
+func (h *AuthHandler) CheckAvailability(c *gin.Context) {
+	var req dto.AvailabilityRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "validation_error",
+			Message: "Invalid query parameters",
+			Details: validationDetails(err),
+		})
+		return
+	}
+	if req.Username == "" && req.Email == "" {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "validation_error",
+			Message: "username or email is required",
+		})
+		return
+	}
+
+	response, err := h.authUseCase.CheckAvailability(c.Request.Context(), &req)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// clientContext adds the caller's user agent and IP address to the request
+// context, so sessions created by the request record the device
+func clientContext(c *gin.Context) context.Context {
+	return usecase.ContextWithClient(c.Request.Context(), c.Request.UserAgent(), c.ClientIP())
+}
+
+// statusClientClosedRequest is nginx's non-standard status for a request the
+// client gave up on before the response was ready
+const statusClientClosedRequest = 499
+
+// authenticated returns the caller put in the context by AuthMiddleware. It
+// answers 401 and returns false when there is none, e.g. on a route
+// registered without the middleware.
+func authenticated(c *gin.Context) (*authctx.AuthContext, bool) {
+	auth, ok := authctx.From(c)
+	if !ok {
+		c.JSON(http.StatusUnauthorized, dto.ErrorResponse{
+			Error:   "unauthorized",
+			Message: "User not authenticated",
+		})
+	}
+	return auth, ok
+}
+
+// respondError writes the response for a use case error: the status, code,
+// message and details of its AppError, or 500 internal_error for an error
+// the use cases did not anticipate
+func respondError(c *gin.Context, err error) {
+	if respondRequestTimeout(c, err) {
+		return
+	}
+	appErr := usecase.AsAppError(err)
+	if appErr == nil {
+		appErr = errInternal
+	}
+	c.JSON(appErr.Status, dto.ErrorResponse{
+		Error:   appErr.Code,
+		Message: appErr.Message,
+		Details: appErr.Details,
+	})
+}
+
+// respondRequestTimeout writes the response for a use case that stopped
+// because the request context ended: 499 when the client went away, 504 when
+// a deadline ran out. It reports whether err was such an error.
+func respondRequestTimeout(c *gin.Context, err error) bool {
+	if !errors.Is(err, usecase.ErrRequestTimeout) {
+		return false
+	}
+	if errors.Is(c.Request.Context().Err(), context.Canceled) {
+		c.JSON(statusClientClosedRequest, dto.ErrorResponse{
+			Error:   "request_cancelled",
+			Message: "The request was cancelled by the client",
+		})
+		return true
+	}
+	c.JSON(http.StatusGatewayTimeout, dto.ErrorResponse{
+		Error:   "request_timeout",
+		Message: "The request took too long, please try again",
+	})
+	return true
+}
@@ -0,0 +1,581 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"auth-service/config"
+	"auth-service/internal/application/dto"
+	"auth-service/internal/application/usecase"
+	"auth-service/internal/domain"
+	"auth-service/internal/presentation/http/authctx"
+	"auth-service/pkg/security"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func TestRespondErrorWeakPassword(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	rec := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(rec)
+	c.Request = httptest.NewRequest(http.MethodPost, "/", nil)
+	err := fmt.Errorf("register: %w", &usecase.PasswordStrengthError{
+		Strength: security.EstimatePasswordStrength("password"),
+		MinScore: 3,
+	})
+
+	respondError(c, err)
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d", rec.Code)
+	}
+	var resp dto.ErrorResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Error != "weak_password" || resp.Details["score"] != "0" || resp.Details["min_score"] != "3" ||
+		resp.Details["warning"] == "" || resp.Details["suggestions"] == "" {
+		t.Errorf("response = %+v", resp)
+	}
+
+}
+
+func TestRespondErrorUnknownError(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	rec := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(rec)
+	c.Request = httptest.NewRequest(http.MethodPost, "/", nil)
+	respondError(c, errors.New("database down"))
+
+	var resp dto.ErrorResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	// The error text must not leak into the response
+	if rec.Code != http.StatusInternalServerError || resp.Error != "internal_error" || strings.Contains(resp.Message, "database") {
+		t.Errorf("status = %d, response = %+v", rec.Code, resp)
+	}
+}
+
+func TestRespondErrorNamesPasswordRule(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		err      error
+		wantRule string
+	}{
+		{&usecase.PasswordRuleError{Rule: usecase.PasswordRuleBreached, Message: "breached"}, "breached"},
+		{usecase.ErrPasswordTooShort, "min_length"},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(rec)
+		c.Request = httptest.NewRequest(http.MethodPost, "/", nil)
+		respondError(c, fmt.Errorf("reset: %w", tt.err))
+		var resp dto.ErrorResponse
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+			t.Fatal(err)
+		}
+		if rec.Code != http.StatusBadRequest || resp.Error != "weak_password" || resp.Details["rule"] != tt.wantRule {
+			t.Errorf("%v: status = %d, response = %+v", tt.err, rec.Code, resp)
+		}
+	}
+}
+
+func TestRespondRequestTimeout(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	expired, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancel()
+
+	tests := []struct {
+		name       string
+		ctx        context.Context
+		wantStatus int
+		wantCode   string
+	}{
+		{"client went away", cancelled, statusClientClosedRequest, "request_cancelled"},
+		{"deadline exceeded", expired, http.StatusGatewayTimeout, "request_timeout"},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(rec)
+		c.Request = httptest.NewRequest(http.MethodGet, "/", nil).WithContext(tt.ctx)
+
+		if !respondRequestTimeout(c, usecase.ErrRequestTimeout) {
+			t.Fatalf("%s: not handled", tt.name)
+		}
+		var resp dto.ErrorResponse
+		json.Unmarshal(rec.Body.Bytes(), &resp)
+		if rec.Code != tt.wantStatus || resp.Error != tt.wantCode {
+			t.Errorf("%s: got %d %q, want %d %q", tt.name, rec.Code, resp.Error, tt.wantStatus, tt.wantCode)
+		}
+	}
+
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	if respondRequestTimeout(c, usecase.ErrUserNotFound) {
+		t.Error("unrelated error was handled")
+	}
+}
+
+func TestMeWithCancelledRequest(t *testing.T) {
+	// The lookup fails because the client went away, not because the user is gone
+	router := newTestProfileRouter(&stubUserRepo{users: map[string]*domain.User{}}, uuid.New())
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/auth/me", nil).WithContext(ctx))
+	if rec.Code != statusClientClosedRequest {
+		t.Errorf("status = %d, want 499", rec.Code)
+	}
+}
+
+func TestIntrospectRequiresToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/auth/introspect", (&AuthHandler{}).Introspect)
+
+	req := httptest.NewRequest(http.MethodPost, "/auth/introspect", strings.NewReader("token_type_hint=access_token"))
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, want 400", rec.Code)
+	}
+}
+
+func newTestProfileRouter(repo *stubUserRepo, userID uuid.UUID) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	uc := usecase.NewAuthUseCase(repo, nil, nil, nil, nil, nil, 0, 0, config.SecurityConfig{})
+	h := NewAuthHandler(uc, nil)
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		// Stale claim from when the token was issued
+		authctx.Set(c, &authctx.AuthContext{UserID: userID, Email: "old@example.com"})
+	})
+	router.GET("/api/auth/me", h.Me)
+	router.PUT("/api/auth/me", h.UpdateProfile)
+	router.PUT("/api/auth/username", h.ChangeUsername)
+	return router
+}
+
+func TestMeReturnsStoredProfile(t *testing.T) {
+	user := &domain.User{ID: uuid.New(), Email: "jane@example.com", Username: "jane", FirstName: "Jane", LastName: "Doe", Role: domain.RoleUser}
+	router := newTestProfileRouter(&stubUserRepo{users: map[string]*domain.User{user.Email: user}}, user.ID)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/auth/me", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", rec.Code, rec.Body)
+	}
+	var profile dto.MeResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &profile); err != nil {
+		t.Fatal(err)
+	}
+	if profile.Email != "jane@example.com" || profile.FirstName != "Jane" || profile.LastName != "Doe" || len(profile.Scopes) == 0 {
+		t.Errorf("profile = %+v", profile)
+	}
+	if strings.Contains(rec.Body.String(), "password") {
+		t.Errorf("response leaks password data: %s", rec.Body)
+	}
+
+	// A token whose user was deleted
+	router = newTestProfileRouter(&stubUserRepo{users: map[string]*domain.User{}}, uuid.New())
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/auth/me", nil))
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("deleted user: status = %d", rec.Code)
+	}
+}
+
+func TestUpdateProfile(t *testing.T) {
+	user := &domain.User{ID: uuid.New(), Email: "jane@example.com", Username: "jane", FirstName: "Jane", LastName: "Doe"}
+	repo := &stubUserRepo{users: map[string]*domain.User{user.Email: user}}
+	router := newTestProfileRouter(repo, user.ID)
+
+	tests := []struct {
+		name string
+		body string
+		want int
+	}{
+		{"missing last name", `{"first_name":"Janet"}`, http.StatusBadRequest},
+		{"name too long", `{"first_name":"` + strings.Repeat("a", 101) + `","last_name":"Smith"}`, http.StatusBadRequest},
+		// Email and username in the payload are ignored
+		{"names", `{"first_name":"Janet","last_name":"Smith","email":"evil@example.com","username":"root"}`, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPut, "/api/auth/me", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+			if rec.Code != tt.want {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.want, rec.Body)
+			}
+		})
+	}
+
+	stored := repo.users["jane@example.com"]
+	if stored == nil || stored.FirstName != "Janet" || stored.LastName != "Smith" || stored.Username != "jane" {
+		t.Errorf("stored user = %+v", stored)
+	}
+}
+
+func TestChangeUsername(t *testing.T) {
+	user := &domain.User{ID: uuid.New(), Email: "jane@example.com", Username: "jane"}
+	other := &domain.User{ID: uuid.New(), Email: "john@example.com", Username: "john"}
+	repo := &stubUserRepo{users: map[string]*domain.User{user.Email: user, other.Email: other}}
+	router := newTestProfileRouter(repo, user.ID)
+
+	tests := []struct {
+		name      string
+		body      string
+		want      int
+		wantError string
+	}{
+		{"missing username", `{}`, http.StatusBadRequest, "validation_error"},
+		{"reserved", `{"username":"admin"}`, http.StatusBadRequest, "username_not_allowed"},
+		{"taken", `{"username":"John"}`, http.StatusConflict, "username_taken"},
+		{"changed", `{"username":"janet"}`, http.StatusOK, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPut, "/api/auth/username", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+			if rec.Code != tt.want {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.want, rec.Body)
+			}
+			if tt.wantError != "" && !strings.Contains(rec.Body.String(), `"error":"`+tt.wantError+`"`) {
+				t.Errorf("body = %s, want error %q", rec.Body, tt.wantError)
+			}
+		})
+	}
+
+	if stored := repo.users["jane@example.com"]; stored.Username != "janet" || stored.TokenVersion != 1 {
+		t.Errorf("stored user = %+v", stored)
+	}
+}
+
+func TestCloseAccountRequiresPassword(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	hasher := security.NewBcryptHasher(4)
+	hash, _ := hasher.Hash("correct-horse")
+	user := &domain.User{ID: uuid.New(), Email: "jane@example.com", Username: "jane", PasswordHash: hash, IsActive: true}
+	repo := &stubUserRepo{users: map[string]*domain.User{user.Email: user}}
+	h := NewAuthHandler(usecase.NewAuthUseCase(repo, nil, nil, nil, nil, hasher, 0, 0, config.SecurityConfig{}), nil)
+
+	router := gin.New()
+	router.Use(func(c *gin.Context) { authctx.Set(c, &authctx.AuthContext{UserID: user.ID}) })
+	router.POST("/api/auth/deactivate", h.DeactivateAccount)
+	router.DELETE("/api/auth/me", h.DeleteAccount)
+
+	tests := []struct {
+		method, path, body string
+		want               int
+		wantError          string
+	}{
+		{http.MethodPost, "/api/auth/deactivate", `{}`, http.StatusBadRequest, "validation_error"},
+		{http.MethodPost, "/api/auth/deactivate", `{"password":"wrong"}`, http.StatusBadRequest, "invalid_credentials"},
+		{http.MethodDelete, "/api/auth/me", `{"password":"wrong"}`, http.StatusBadRequest, "invalid_credentials"},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+		req.Header.Set("Content-Type", "application/json")
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		var resp dto.ErrorResponse
+		_ = json.Unmarshal(rec.Body.Bytes(), &resp)
+		if rec.Code != tt.want || resp.Error != tt.wantError {
+			t.Errorf("%s %s %s: status = %d, error = %q", tt.method, tt.path, tt.body, rec.Code, resp.Error)
+		}
+	}
+	if !user.IsActive || user.DeletedAt != nil {
+		t.Errorf("account changed without the right password: %+v", user)
+	}
+}
+
+func TestLogoutRequiresRefreshTokenOrAll(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	userID := uuid.New()
+	repo := newStubRefreshTokenRepo(&domain.RefreshToken{ID: uuid.New(), UserID: userID, TokenHash: security.HashToken("mine")},
+		&domain.RefreshToken{ID: uuid.New(), UserID: uuid.New(), TokenHash: security.HashToken("theirs")})
+	uc := usecase.NewAuthUseCase(nil, repo, nil, nil, nil, nil, 0, 0, config.SecurityConfig{})
+	router := gin.New()
+	router.Use(func(c *gin.Context) { authctx.Set(c, &authctx.AuthContext{UserID: userID}) })
+	router.POST("/api/auth/logout", NewAuthHandler(uc, nil).Logout)
+
+	tests := []struct {
+		name, query, body string
+		want              int
+		wantRevokedAll    bool
+	}{
+		{"no body", "", "", http.StatusBadRequest, false},
+		{"empty token", "", `{"refresh_token":""}`, http.StatusBadRequest, false},
+		{"malformed body", "", `{`, http.StatusBadRequest, false},
+		{"another user's token", "", `{"refresh_token":"theirs"}`, http.StatusNotFound, false},
+		{"own token", "", `{"refresh_token":"mine"}`, http.StatusOK, false},
+		{"all", "?all=true", "", http.StatusOK, true},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/auth/logout"+tt.query, strings.NewReader(tt.body)))
+		if rec.Code != tt.want {
+			t.Errorf("%s: status = %d, want %d: %s", tt.name, rec.Code, tt.want, rec.Body)
+		}
+		if repo.revokedAll != tt.wantRevokedAll {
+			t.Errorf("%s: revoked all = %v", tt.name, repo.revokedAll)
+		}
+	}
+	if !repo.revoked[security.HashToken("mine")] || repo.revoked[security.HashToken("theirs")] {
+		t.Errorf("revoked = %v, want only mine", repo.revoked)
+	}
+}
+
+// stubRefreshTokenRepo keeps tokens in memory by hash and records revocations
+type stubRefreshTokenRepo struct {
+	domain.RefreshTokenRepository
+	tokens     map[string]*domain.RefreshToken
+	revoked    map[string]bool
+	revokedAll bool
+	// looked are the hashes passed to GetByTokenHashIncludingRevoked
+	looked []string
+}
+
+func newStubRefreshTokenRepo(tokens ...*domain.RefreshToken) *stubRefreshTokenRepo {
+	r := &stubRefreshTokenRepo{tokens: map[string]*domain.RefreshToken{}, revoked: map[string]bool{}}
+	for _, t := range tokens {
+		r.tokens[t.TokenHash] = t
+	}
+	return r
+}
+
+func (r *stubRefreshTokenRepo) GetByTokenHash(ctx context.Context, tokenHash string) (*domain.RefreshToken, error) {
+	t, ok := r.tokens[tokenHash]
+	if !ok || r.revoked[tokenHash] {
+		return nil, domain.ErrNotFound
+	}
+	return t, nil
+}
+
+func (r *stubRefreshTokenRepo) GetByTokenHashIncludingRevoked(ctx context.Context, tokenHash string) (*domain.RefreshToken, error) {
+	r.looked = append(r.looked, tokenHash)
+	if t, ok := r.tokens[tokenHash]; ok {
+		return t, nil
+	}
+	return nil, domain.ErrNotFound
+}
+
+func (r *stubRefreshTokenRepo) GetByUserIDIncludingRevoked(ctx context.Context, userID uuid.UUID) ([]*domain.RefreshToken, error) {
+	var tokens []*domain.RefreshToken
+	for _, t := range r.tokens {
+		if t.UserID == userID {
+			tokens = append(tokens, t)
+		}
+	}
+	return tokens, nil
+}
+
+func (r *stubRefreshTokenRepo) Revoke(ctx context.Context, tokenHash string) error {
+	r.revoked[tokenHash] = true
+	return nil
+}
+
+func (r *stubRefreshTokenRepo) RevokeAllByUserID(ctx context.Context, userID uuid.UUID) error {
+	r.revokedAll = true
+	return nil
+}
+
+func (r *stubRefreshTokenRepo) GetIssuedSince(ctx context.Context, userID uuid.UUID, since time.Time) ([]*domain.RefreshToken, error) {
+	var tokens []*domain.RefreshToken
+	for _, t := range r.tokens {
+		if t.UserID == userID && t.CreatedAt.After(since) {
+			tokens = append(tokens, t)
+		}
+	}
+	return tokens, nil
+}
+
+// stubMagicLinkRepo never finds a token
+type stubMagicLinkRepo struct {
+	domain.MagicLinkTokenRepository
+}
+
+func (stubMagicLinkRepo) Consume(ctx context.Context, tokenHash string) (*domain.MagicLinkToken, error) {
+	return nil, domain.ErrNotFound
+}
+
+func TestMagicLinkEndpoints(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	uc := usecase.NewAuthUseCase(&stubUserRepo{users: map[string]*domain.User{}}, nil, nil, nil, nil, nil, 0, 0,
+		config.SecurityConfig{}, usecase.WithMagicLinks(stubMagicLinkRepo{}))
+	h := NewAuthHandler(uc, nil)
+	router := gin.New()
+	router.POST("/auth/magic-link", h.RequestMagicLink)
+	router.GET("/auth/magic-link/callback", h.MagicLinkCallback)
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		want   int
+	}{
+		// Unknown emails get the same answer as registered ones
+		{"unknown email", http.MethodPost, "/auth/magic-link", `{"email":"nobody@example.com"}`, http.StatusOK},
+		{"invalid email", http.MethodPost, "/auth/magic-link", `{"email":"nope"}`, http.StatusBadRequest},
+		{"missing token", http.MethodGet, "/auth/magic-link/callback", "", http.StatusBadRequest},
+		{"unknown token", http.MethodGet, "/auth/magic-link/callback?token=nope", "", http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body)))
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d (body %s)", rec.Code, tt.want, rec.Body)
+			}
+		})
+	}
+}
+
+func TestRegisterDisabled(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	// No repositories: a closed registration must not reach them
+	uc := usecase.NewAuthUseCase(nil, nil, nil, nil, nil, nil, 0, 0, config.SecurityConfig{}, usecase.WithRegistrationEnabled(false))
+	router := gin.New()
+	router.POST("/auth/register", NewAuthHandler(uc, nil).Register)
+
+	body := `{"email":"jane@example.com","username":"jane","password":"correct-horse","first_name":"Jane","last_name":"Doe"}`
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/auth/register", strings.NewReader(body)))
+	if rec.Code != http.StatusForbidden || !strings.Contains(rec.Body.String(), "registration_disabled") {
+		t.Errorf("status = %d, body = %s; want 403 registration_disabled", rec.Code, rec.Body)
+	}
+}
+
+func TestRequestVerificationEmail(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	// A verification email was just sent, so the cooldown is running
+	sentAt := time.Now()
+	user := &domain.User{ID: uuid.New(), Email: "jane@example.com", Username: "jane", IsActive: true, VerificationSentAt: &sentAt}
+	uc := usecase.NewAuthUseCase(&stubUserRepo{users: map[string]*domain.User{user.Email: user}}, nil, nil, nil, nil, nil, 0, 0,
+		config.SecurityConfig{VerificationResendCooldown: time.Minute})
+	router := gin.New()
+	router.POST("/auth/verify-email/resend", NewAuthHandler(uc, nil).RequestVerificationEmail)
+
+	post := func(body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/auth/verify-email/resend", strings.NewReader(body)))
+		return rec
+	}
+
+	if rec := post(`{"email":"nope"}`); rec.Code != http.StatusBadRequest {
+		t.Errorf("invalid email: status = %d, want 400", rec.Code)
+	}
+
+	// A registered account inside the cooldown answers exactly like an
+	// unknown email, so the endpoint does not reveal which emails exist
+	unknown := post(`{"email":"nobody@example.com"}`)
+	coolingDown := post(`{"email":"jane@example.com"}`)
+	if unknown.Code != http.StatusOK || coolingDown.Code != unknown.Code || coolingDown.Body.String() != unknown.Body.String() {
+		t.Errorf("unknown email: %d %s; inside the cooldown: %d %s", unknown.Code, unknown.Body, coolingDown.Code, coolingDown.Body)
+	}
+}
+
+func TestResendVerificationCooldown(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	sentAt := time.Now()
+	user := &domain.User{ID: uuid.New(), Email: "jane@example.com", Username: "jane", IsActive: true, VerificationSentAt: &sentAt}
+	uc := usecase.NewAuthUseCase(&stubUserRepo{users: map[string]*domain.User{user.Email: user}}, nil, nil, nil, nil, nil, 0, 0,
+		config.SecurityConfig{VerificationResendCooldown: time.Minute})
+	router := gin.New()
+	router.POST("/auth/resend-verification", func(c *gin.Context) {
+		authctx.Set(c, &authctx.AuthContext{UserID: user.ID})
+	}, NewAuthHandler(uc, nil).ResendVerification)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/auth/resend-verification", nil))
+	if rec.Code != http.StatusTooManyRequests {
+		t.Errorf("status = %d, want 429: %s", rec.Code, rec.Body)
+	}
+}
+
+func TestCheckAvailability(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	user := &domain.User{ID: uuid.New(), Email: "jane@example.com", Username: "jane"}
+	uc := usecase.NewAuthUseCase(&stubUserRepo{users: map[string]*domain.User{user.Email: user}}, nil, nil, nil, nil, nil, 0, 0, config.SecurityConfig{})
+	router := gin.New()
+	router.GET("/auth/availability", NewAuthHandler(uc, nil).CheckAvailability)
+
+	tests := []struct {
+		name  string
+		query string
+		want  int
+		body  string
+	}{
+		{"both", "?username=Jane&email=john@example.com", http.StatusOK, `{"username_available":false,"email_available":true}`},
+		{"username only", "?username=john", http.StatusOK, `{"username_available":true}`},
+		{"neither", "", http.StatusBadRequest, ""},
+		{"invalid email", "?email=nope", http.StatusBadRequest, ""},
+		{"unknown organization", "?email=john@example.com&organization=acme", http.StatusNotFound, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/auth/availability"+tt.query, nil))
+			if rec.Code != tt.want {
+				t.Fatalf("status = %d, want %d (body %s)", rec.Code, tt.want, rec.Body)
+			}
+			if tt.body != "" && rec.Body.String() != tt.body {
+				t.Errorf("body = %s, want %s", rec.Body, tt.body)
+			}
+		})
+	}
+}
+
+func TestCurrentSession(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	auth := &authctx.AuthContext{UserID: uuid.New(), SessionID: uuid.New(), ExpiresAt: time.Now().Add(10 * time.Minute)}
+	router := gin.New()
+	// No use case: the endpoint must answer from the claims alone
+	h := &AuthHandler{}
+	router.GET("/auth/session", func(c *gin.Context) {
+		authctx.Set(c, auth)
+	}, h.CurrentSession)
+	router.GET("/auth/session/anonymous", h.CurrentSession)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/auth/session", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", rec.Code, rec.Body)
+	}
+	var resp dto.SessionStatusResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.UserID != auth.UserID.String() || resp.SessionID != auth.SessionID.String() || !resp.ExpiresAt.Equal(auth.ExpiresAt) ||
+		resp.ExpiresIn < 590 || resp.ExpiresIn > 600 {
+		t.Errorf("response = %+v", resp)
+	}
+
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/auth/session/anonymous", nil))
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("without claims: status = %d, want 401", rec.Code)
+	}
+}
@@ -0,0 +1,125 @@
+package handler
+
+import (
+	"net/http"
+
+	"auth-service/internal/application/dto"
+	"auth-service/internal/application/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Errors the handlers raise themselves. The variants reuse a catalog code
+// with the status and message of one endpoint.
+var (
+	errInternal     = &usecase.AppError{Code: "internal_error", Status: http.StatusInternalServerError, Message: "An unexpected error occurred"}
+	errUnauthorized = &usecase.AppError{Code: "unauthorized", Status: http.StatusUnauthorized, Message: "User not authenticated"}
+
+	// errIncorrectPassword is a wrong password re-entered by a signed-in
+	// user; 401 would make clients discard their tokens
+	errIncorrectPassword = &usecase.AppError{Code: "invalid_credentials", Status: http.StatusBadRequest, Message: "Current password is incorrect"}
+	// errInvalidLink is an unknown or used email link token
+	errInvalidLink           = &usecase.AppError{Code: "invalid_token", Status: http.StatusBadRequest, Message: "The link is invalid or has already been used"}
+	errRevocationCheckFailed = &usecase.AppError{Code: "service_unavailable", Status: http.StatusServiceUnavailable, Message: "Could not check token revocation"}
+)
+
+// appErrors is the catalog served by GET /errors: every code the API
+// returns in ErrorResponse.Error with its usual status and default message.
+// Use case errors are listed by their sentinel. Add an entry here when a
+// new error code or use case error is introduced.
+var appErrors = []*usecase.AppError{
+	// Request errors
+	{Code: "validation_error", Status: http.StatusBadRequest, Message: "Invalid request payload"},
+	{Code: "invalid_request", Status: http.StatusBadRequest, Message: "A required parameter is missing"},
+	{Code: "invalid_user_id", Status: http.StatusBadRequest, Message: "Invalid user ID"},
+	usecase.ErrInvalidRole,
+	{Code: "invalid_api_key_id", Status: http.StatusBadRequest, Message: "Invalid API key ID"},
+	usecase.ErrInvalidScope,
+	usecase.ErrUsernameNotAllowed,
+	usecase.ErrInvalidPhone,
+	usecase.ErrInvalidInvite,
+	usecase.ErrInvalidExpiry,
+	{Code: "invalid_idempotency_key", Status: http.StatusBadRequest, Message: "Idempotency-Key must be at most 255 characters"},
+	{Code: "idempotency_key_reused", Status: http.StatusUnprocessableEntity, Message: "Idempotency-Key was already used for a different request"},
+	{Code: "request_too_large", Status: http.StatusRequestEntityTooLarge, Message: "Request body is too large"},
+	{Code: "captcha_required", Status: http.StatusBadRequest, Message: "A CAPTCHA solution is required (captcha_token)"},
+	{Code: "captcha_invalid", Status: http.StatusBadRequest, Message: "CAPTCHA verification failed; solve it again"},
+
+	// Authentication
+	{Code: "missing_token", Status: http.StatusUnauthorized, Message: "Authorization header is required"},
+	{Code: "invalid_token_format", Status: http.StatusUnauthorized, Message: "Authorization header format must be 'Bearer {token}'"},
+	usecase.ErrInvalidToken,
+	{Code: "access_token_expired", Status: http.StatusUnauthorized, Message: "Access token has expired; refresh it"},
+	usecase.ErrTokenExpired,
+	{Code: "token_revoked", Status: http.StatusUnauthorized, Message: "Token has been revoked"},
+	{Code: "access_token_outdated", Status: http.StatusUnauthorized, Message: "Access token was issued before the user's sessions were reset; refresh it"},
+	usecase.ErrTokenReuseDetected,
+	{Code: "invalid_signature", Status: http.StatusUnauthorized, Message: "Request signature is missing or invalid"},
+	errUnauthorized,
+	{Code: "missing_api_key", Status: http.StatusUnauthorized, Message: "X-API-Key header is required"},
+	usecase.ErrInvalidAPIKey,
+	{Code: "invalid_oauth_state", Status: http.StatusBadRequest, Message: "OAuth state is missing or does not match; start the login again"},
+	{Code: "oauth_provider_not_found", Status: http.StatusNotFound, Message: "Unknown or disabled login provider"},
+	{Code: "oauth_failed", Status: http.StatusBadGateway, Message: "Could not complete login with the identity provider"},
+	usecase.ErrOAuthEmailNotVerified,
+	usecase.ErrOAuthAccountUnverified,
+	usecase.ErrPasskeySessionNotFound,
+	usecase.ErrPasskeyVerificationFailed,
+	usecase.ErrPasskeyCloneDetected,
+	usecase.ErrInvalidCredentials,
+
+	// Account state
+	usecase.ErrUserAlreadyExists,
+	usecase.ErrUsernameTaken,
+	usecase.ErrUsernameChangeTooSoon,
+	usecase.ErrUserNotFound,
+	usecase.ErrUserInactive,
+	usecase.ErrEmailNotVerified,
+	usecase.ErrAlreadyVerified,
+	usecase.ErrPendingApproval,
+	usecase.ErrNotPendingApproval,
+	usecase.ErrOrganizationNotFound,
+	usecase.ErrSessionNotFound,
+	usecase.ErrOAuthAccountNotLinked,
+	usecase.ErrLastLoginMethod,
+	usecase.ErrAPIKeyNotFound,
+	usecase.ErrAccountLocked,
+	usecase.ErrTooManyRequests,
+	{Code: "idempotency_key_in_use", Status: http.StatusConflict, Message: "A request with this Idempotency-Key is still being processed"},
+
+	// Passwords
+	usecase.ErrWeakPassword,
+	usecase.ErrSamePassword,
+	usecase.ErrPasswordReused,
+
+	// Access and availability
+	usecase.ErrRegistrationDisabled,
+	usecase.ErrInviteRequired,
+	{Code: "forbidden", Status: http.StatusForbidden, Message: "Access to this endpoint is not allowed"},
+	{Code: "organization_mismatch", Status: http.StatusForbidden, Message: "The token belongs to another organization"},
+	{Code: "insufficient_scope", Status: http.StatusForbidden, Message: "The credentials lack the scope this endpoint requires"},
+	{Code: "password_change_required", Status: http.StatusForbidden, Message: "Change your temporary password before using this endpoint"},
+	{Code: "captcha_unavailable", Status: http.StatusServiceUnavailable, Message: "CAPTCHA verification is temporarily unavailable"},
+	{Code: "service_unavailable", Status: http.StatusServiceUnavailable, Message: "A dependency is unavailable, try again later"},
+	usecase.ErrRequestTimeout,
+	{Code: "request_cancelled", Status: statusClientClosedRequest, Message: "The request was cancelled by the client"},
+	errInternal,
+}
+
+// ErrorCatalog godoc
+// @Summary List error codes
+// @Description Every error code the API can return in the "error" field, with its HTTP status and default message
+// @Tags meta
+// @Produce json
+// @Success 200 {object} dto.ErrorCatalogResponse
+// @Router /errors [get]
+func ErrorCatalog(c *gin.Context) {
+	response := dto.ErrorCatalogResponse{Errors: make([]dto.ErrorCatalogEntry, len(appErrors))}
+	for i, e := range appErrors {
+		response.Errors[i] = dto.ErrorCatalogEntry{Code: e.Code, Status: e.Status, Message: e.Message}
+	}
+
+	// The catalog only changes with a deploy
+	c.Header("Cache-Control", "public, max-age=3600")
+	c.JSON(http.StatusOK, response)
+}
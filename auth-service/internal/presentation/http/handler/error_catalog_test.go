@@ -0,0 +1,124 @@
+package handler
+
+import (
+	"encoding/json"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"auth-service/internal/application/dto"
+
+	"github.com/gin-gonic/gin"
+)
+
+// sentinelErrors returns the codes of the exported Err* AppErrors declared
+// in the use case package, by variable name
+func sentinelErrors(t *testing.T) map[string]string {
+	t.Helper()
+	dir := filepath.Join("..", "..", "..", "application", "usecase")
+	pkgs, err := parser.ParseDir(token.NewFileSet(), dir, func(fi os.FileInfo) bool {
+		return !strings.HasSuffix(fi.Name(), "_test.go")
+	}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sentinels := map[string]string{}
+	for _, pkg := range pkgs {
+		ast.Inspect(pkg, func(n ast.Node) bool {
+			spec, ok := n.(*ast.ValueSpec)
+			if !ok {
+				return true
+			}
+			for i, name := range spec.Names {
+				if !strings.HasPrefix(name.Name, "Err") || i >= len(spec.Values) {
+					continue
+				}
+				if code := appErrorCode(spec.Values[i]); code != "" {
+					sentinels[name.Name] = code
+				}
+			}
+			return true
+		})
+	}
+	if len(sentinels) == 0 {
+		t.Fatal("no sentinel errors found")
+	}
+	return sentinels
+}
+
+// appErrorCode returns the code of newAppError(status, "code", message) or
+// &AppError{Code: "code", ...}
+func appErrorCode(expr ast.Expr) string {
+	var lit ast.Expr
+	switch v := expr.(type) {
+	case *ast.CallExpr:
+		if len(v.Args) == 3 {
+			lit = v.Args[1]
+		}
+	case *ast.UnaryExpr:
+		composite, ok := v.X.(*ast.CompositeLit)
+		if !ok {
+			return ""
+		}
+		for _, elt := range composite.Elts {
+			if kv, ok := elt.(*ast.KeyValueExpr); ok {
+				if key, ok := kv.Key.(*ast.Ident); ok && key.Name == "Code" {
+					lit = kv.Value
+				}
+			}
+		}
+	}
+	basic, ok := lit.(*ast.BasicLit)
+	if !ok {
+		return ""
+	}
+	code, _ := strconv.Unquote(basic.Value)
+	return code
+}
+
+func TestErrorCatalogListsEverySentinelError(t *testing.T) {
+	codes := map[string]bool{}
+	for _, e := range appErrors {
+		if codes[e.Code] {
+			t.Errorf("code %q listed twice", e.Code)
+		}
+		codes[e.Code] = true
+	}
+
+	for name, code := range sentinelErrors(t) {
+		if !codes[code] {
+			t.Errorf("usecase.%s (%s) is missing from the error catalog", name, code)
+		}
+	}
+}
+
+func TestErrorCatalogHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/errors", ErrorCatalog)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/errors", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d", rec.Code)
+	}
+	if cc := rec.Header().Get("Cache-Control"); !strings.Contains(cc, "public") {
+		t.Errorf("Cache-Control = %q, want public", cc)
+	}
+	var resp dto.ErrorCatalogResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Errors) != len(appErrors) || resp.Errors[0].Code == "" || resp.Errors[0].Status == 0 {
+		t.Errorf("response = %+v", resp)
+	}
+}
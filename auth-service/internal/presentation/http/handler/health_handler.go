@@ -0,0 +1,57 @@
+package handler
+
+import (
+	"net/http"
+
+	"auth-service/internal/infrastructure/health"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HealthHandler handles readiness and detailed dependency health requests
+type HealthHandler struct {
+	healthService *health.Service
+	readiness     *health.Service
+}
+
+// NewHealthHandler creates a new health handler. readiness holds the checks
+// the service cannot serve requests without (the database).
+func NewHealthHandler(healthService, readiness *health.Service) *HealthHandler {
+	return &HealthHandler{healthService: healthService, readiness: readiness}
+}
+
+// Ready godoc
+// @Summary Readiness check
+// @Description Check whether the service can serve requests (the database is reachable). Unlike /health, a failing check takes the instance out of load balancing instead of restarting it.
+// @Tags health
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 503 {object} map[string]interface{}
+// @Router /ready [get]
+func (h *HealthHandler) Ready(c *gin.Context) {
+	// Only the overall status: /ready is public, dependency details are on /health/detailed
+	if h.readiness.Run(c.Request.Context()).Status == health.StatusUnhealthy {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"status": "not_ready"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "ready"})
+}
+
+// Detailed godoc
+// @Summary Detailed health check
+// @Description Check every dependency (Postgres, Redis, SMTP, OAuth providers) and report status, latency and check time
+// @Tags health
+// @Produce json
+// @Success 200 {object} health.Report
+// @Failure 503 {object} health.Report
+// @Router /health/detailed [get]
+func (h *HealthHandler) Detailed(c *gin.Context) {
+	report := h.healthService.Run(c.Request.Context())
+
+	status := http.StatusOK
+	if report.Status == health.StatusUnhealthy {
+		status = http.StatusServiceUnavailable
+	}
+
+	c.JSON(status, report)
+}
@@ -0,0 +1,102 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"auth-service/internal/infrastructure/health"
+
+	"github.com/gin-gonic/gin"
+)
+
+type stubChecker struct {
+	name string
+	err  error
+}
+
+func (s stubChecker) Name() string                    { return s.name }
+func (s stubChecker) Check(ctx context.Context) error { return s.err }
+
+func TestHealthHandlerDetailed(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	down := errors.New("connection refused")
+
+	tests := []struct {
+		name       string
+		dbErr      error
+		smtpErr    error
+		wantCode   int
+		wantStatus string
+	}{
+		{"healthy", nil, nil, http.StatusOK, health.StatusHealthy},
+		{"degraded", nil, down, http.StatusOK, health.StatusDegraded},
+		{"unhealthy", down, nil, http.StatusServiceUnavailable, health.StatusUnhealthy},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := health.NewService(time.Second)
+			svc.Register(stubChecker{name: "postgres", err: tt.dbErr}, true)
+			svc.Register(stubChecker{name: "smtp", err: tt.smtpErr}, false)
+
+			router := gin.New()
+			router.GET("/health/detailed", NewHealthHandler(svc, health.NewService(time.Second)).Detailed)
+
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/health/detailed", nil))
+
+			if rec.Code != tt.wantCode {
+				t.Fatalf("code = %d, want %d", rec.Code, tt.wantCode)
+			}
+			var report health.Report
+			if err := json.Unmarshal(rec.Body.Bytes(), &report); err != nil {
+				t.Fatalf("invalid JSON: %v", err)
+			}
+			if report.Status != tt.wantStatus {
+				t.Fatalf("status = %q, want %q", report.Status, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestHealthHandlerReady(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	for _, tt := range []struct {
+		name       string
+		dbErr      error
+		wantCode   int
+		wantStatus string
+	}{
+		{"database up", nil, http.StatusOK, "ready"},
+		{"database down", errors.New("connection refused"), http.StatusServiceUnavailable, "not_ready"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			readiness := health.NewService(time.Second)
+			readiness.Register(stubChecker{name: "postgres", err: tt.dbErr}, true)
+
+			router := gin.New()
+			router.GET("/ready", NewHealthHandler(health.NewService(time.Second), readiness).Ready)
+
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ready", nil))
+
+			if rec.Code != tt.wantCode {
+				t.Fatalf("code = %d, want %d", rec.Code, tt.wantCode)
+			}
+			var body map[string]interface{}
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatal(err)
+			}
+			// The public probe does not leak the error
+			if body["status"] != tt.wantStatus || len(body) != 1 {
+				t.Errorf("body = %v", body)
+			}
+		})
+	}
+}
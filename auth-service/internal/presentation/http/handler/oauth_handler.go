@@ -0,0 +1,160 @@
+package handler
+
+import (
+	"crypto/subtle"
+	"errors"
+	"net/http"
+
+	"auth-service/internal/application/dto"
+	"auth-service/internal/application/usecase"
+	"auth-service/internal/infrastructure/oauth"
+	"auth-service/pkg/security"
+
+	"github.com/gin-gonic/gin"
+)
+
+// oauthStateCookie holds the CSRF state between the login redirect and the
+// callback. It is short-lived and only sent to the provider's OAuth routes.
+const (
+	oauthStateCookie = "oauth_state"
+	oauthStatePath   = "/api/auth/oauth/"
+	oauthStateMaxAge = 10 * 60
+)
+
+// OAuthHandler handles social login HTTP requests
+type OAuthHandler struct {
+	authUseCase *usecase.AuthUseCase
+	providers   map[string]usecase.OAuthProvider
+}
+
+// NewOAuthHandler creates a new OAuth handler. providers are keyed by the
+// name used in the URL, e.g. "google" for /auth/oauth/google/login.
+func NewOAuthHandler(authUseCase *usecase.AuthUseCase, providers map[string]usecase.OAuthProvider) *OAuthHandler {
+	return &OAuthHandler{authUseCase: authUseCase, providers: providers}
+}
+
+// Login godoc
+// @Summary Sign in with an external provider
+// @Description Redirect to the provider's consent screen. The callback returns our normal tokens
+// @Tags oauth
+// @Param provider path string true "Provider name" Enums(google, github)
+// @Success 302
+// @Failure 404 {object} dto.ErrorResponse
+// @Router /api/auth/oauth/{provider}/login [get]
+func (h *OAuthHandler) Login(c *gin.Context) {
+	name, provider, ok := h.provider(c)
+	if !ok {
+		return
+	}
+
+	state, err := security.GenerateOpaqueToken(32)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(oauthStateCookie, state, oauthStateMaxAge, oauthStatePath+name, "", secureCookie(c), true)
+	c.Redirect(http.StatusFound, provider.AuthURL(state))
+}
+
+// Callback godoc
+// @Summary External provider login callback
+// @Description Exchange the authorization code, then log in the matching user or create one
+// @Tags oauth
+// @Produce json
+// @Param provider path string true "Provider name" Enums(google, github)
+// @Param code query string true "Authorization code"
+// @Param state query string true "State from the login redirect"
+// @Success 200 {object} dto.AuthResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 502 {object} dto.ErrorResponse
+// @Router /api/auth/oauth/{provider}/callback [get]
+func (h *OAuthHandler) Callback(c *gin.Context) {
+	name, provider, ok := h.provider(c)
+	if !ok {
+		return
+	}
+
+	// The state must match the cookie set by Login, otherwise the callback
+	// may have been started by someone else (login CSRF). The cookie path is
+	// per provider, so a state issued for one provider is not sent to another.
+	expected, _ := c.Cookie(oauthStateCookie)
+	c.SetCookie(oauthStateCookie, "", -1, oauthStatePath+name, "", secureCookie(c), true)
+	state := c.Query("state")
+	if expected == "" || subtle.ConstantTimeCompare([]byte(expected), []byte(state)) != 1 {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "invalid_oauth_state",
+			Message: "OAuth state is missing or does not match; start the login again",
+		})
+		return
+	}
+
+	code := c.Query("code")
+	if code == "" {
+		// The user denied consent or the provider reported an error
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "oauth_failed",
+			Message: "Login with the identity provider was cancelled or failed",
+			Details: map[string]string{"error": c.Query("error")},
+		})
+		return
+	}
+
+	accessToken, err := provider.Exchange(c.Request.Context(), code)
+	if err != nil {
+		respondOAuthProviderError(c, err)
+		return
+	}
+	external, err := provider.FetchUser(c.Request.Context(), accessToken)
+	if err != nil {
+		respondOAuthProviderError(c, err)
+		return
+	}
+
+	response, err := h.authUseCase.LoginWithOAuth(clientContext(c), name, *external)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// provider looks up the provider named in the URL and responds 404 when it
+// is unknown or not configured
+func (h *OAuthHandler) provider(c *gin.Context) (string, usecase.OAuthProvider, bool) {
+	name := c.Param("provider")
+	provider, ok := h.providers[name]
+	if !ok {
+		c.JSON(http.StatusNotFound, dto.ErrorResponse{
+			Error:   "oauth_provider_not_found",
+			Message: "Unknown or disabled login provider",
+		})
+		return "", nil, false
+	}
+	return name, provider, true
+}
+
+// respondOAuthProviderError reports a failed call to the identity provider
+func respondOAuthProviderError(c *gin.Context, err error) {
+	if errors.Is(err, oauth.ErrExchangeFailed) {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "oauth_failed",
+			Message: "Authorization code is invalid or expired; start the login again",
+		})
+		return
+	}
+	c.JSON(http.StatusBadGateway, dto.ErrorResponse{
+		Error:   "oauth_failed",
+		Message: "Could not reach the identity provider",
+	})
+}
+
+// secureCookie marks cookies Secure on TLS requests and in release mode,
+// where TLS is usually terminated by a proxy in front of the service
+func secureCookie(c *gin.Context) bool {
+	return c.Request.TLS != nil || gin.Mode() == gin.ReleaseMode
+}
@@ -0,0 +1,108 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"auth-service/internal/application/dto"
+	"auth-service/internal/application/usecase"
+	"auth-service/internal/infrastructure/oauth"
+
+	"github.com/gin-gonic/gin"
+)
+
+// stubProvider rejects every authorization code
+type stubProvider struct{}
+
+func (stubProvider) AuthURL(state string) string {
+	return "https://accounts.example.com/auth?state=" + url.QueryEscape(state)
+}
+
+func (stubProvider) Exchange(ctx context.Context, code string) (string, error) {
+	return "", oauth.ErrExchangeFailed
+}
+
+func (stubProvider) FetchUser(ctx context.Context, accessToken string) (*usecase.ExternalUser, error) {
+	return nil, oauth.ErrExchangeFailed
+}
+
+func newTestOAuthRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	h := NewOAuthHandler(nil, map[string]usecase.OAuthProvider{"google": stubProvider{}, "github": stubProvider{}})
+	router := gin.New()
+	router.GET("/api/auth/oauth/:provider/login", h.Login)
+	router.GET("/api/auth/oauth/:provider/callback", h.Callback)
+	return router
+}
+
+func TestOAuthStateCheck(t *testing.T) {
+	router := newTestOAuthRouter()
+
+	// The login redirect carries the same state as the cookie
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/auth/oauth/google/login", nil))
+	if rec.Code != http.StatusFound {
+		t.Fatalf("login status = %d", rec.Code)
+	}
+	location, err := url.Parse(rec.Header().Get("Location"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	state := location.Query().Get("state")
+	cookies := rec.Result().Cookies()
+	if state == "" || len(cookies) != 1 || cookies[0].Value != state || !cookies[0].HttpOnly ||
+		cookies[0].Path != "/api/auth/oauth/google" {
+		t.Fatalf("state = %q, cookies = %+v", state, cookies)
+	}
+
+	tests := []struct {
+		name      string
+		cookie    string
+		state     string
+		wantError string
+	}{
+		{"no cookie", "", state, "invalid_oauth_state"},
+		{"state mismatch", state, "forged", "invalid_oauth_state"},
+		// State passes; the stub provider then rejects the code
+		{"matching state", state, state, "oauth_failed"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/auth/oauth/google/callback?code=abc&state="+url.QueryEscape(tt.state), nil)
+			if tt.cookie != "" {
+				req.AddCookie(&http.Cookie{Name: oauthStateCookie, Value: tt.cookie})
+			}
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			var resp dto.ErrorResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+				t.Fatal(err)
+			}
+			if rec.Code != http.StatusBadRequest || resp.Error != tt.wantError {
+				t.Errorf("status = %d, error = %q, want 400 %q", rec.Code, resp.Error, tt.wantError)
+			}
+		})
+	}
+}
+
+func TestOAuthUnknownProvider(t *testing.T) {
+	router := newTestOAuthRouter()
+
+	for _, path := range []string{"/api/auth/oauth/facebook/login", "/api/auth/oauth/facebook/callback?code=abc&state=x"} {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+
+		var resp dto.ErrorResponse
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+			t.Fatal(err)
+		}
+		if rec.Code != http.StatusNotFound || resp.Error != "oauth_provider_not_found" {
+			t.Errorf("%s: status = %d, error = %q", path, rec.Code, resp.Error)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package handler
+
+import (
+	"net/http"
+
+	"auth-service/internal/application/dto"
+
+	"github.com/gin-gonic/gin"
+)
+
+// BeginPasskeyRegistration godoc
+// @Summary Start registering a passkey
+// @Description Return WebAuthn options for navigator.credentials.create() and the session ID to send back with the result
+// @Tags passkey
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} dto.PasskeyOptionsResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Router /api/auth/passkeys/register/begin [post]
+func (h *AuthHandler) BeginPasskeyRegistration(c *gin.Context) {
+	auth, ok := authenticated(c)
+	if !ok {
+		return
+	}
+
+	response, err := h.authUseCase.BeginPasskeyRegistration(c.Request.Context(), auth.UserID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// FinishPasskeyRegistration godoc
+// @Summary Finish registering a passkey
+// @Description Verify the credential created by the browser and store its public key
+// @Tags passkey
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.PasskeyFinishRequest true "Session ID and PublicKeyCredential"
+// @Success 201 {object} dto.SuccessResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Router /api/auth/passkeys/register/finish [post]
+func (h *AuthHandler) FinishPasskeyRegistration(c *gin.Context) {
+	auth, ok := authenticated(c)
+	if !ok {
+		return
+	}
+
+	var req dto.PasskeyFinishRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "validation_error",
+			Message: "Invalid request payload",
+			Details: validationDetails(err),
+		})
+		return
+	}
+
+	err := h.authUseCase.FinishPasskeyRegistration(clientContext(c), auth.UserID, req.SessionID, req.Credential)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, dto.SuccessResponse{Message: "Passkey registered"})
+}
+
+// BeginPasskeyLogin godoc
+// @Summary Start signing in with a passkey
+// @Description Return WebAuthn options for navigator.credentials.get() and the session ID to send back with the result. No username is needed
+// @Tags passkey
+// @Produce json
+// @Success 200 {object} dto.PasskeyOptionsResponse
+// @Router /api/auth/passkeys/login/begin [post]
+func (h *AuthHandler) BeginPasskeyLogin(c *gin.Context) {
+	response, err := h.authUseCase.BeginPasskeyLogin(c.Request.Context())
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// FinishPasskeyLogin godoc
+// @Summary Finish signing in with a passkey
+// @Description Verify the passkey assertion and return tokens like /auth/login
+// @Tags passkey
+// @Accept json
+// @Produce json
+// @Param request body dto.PasskeyFinishRequest true "Session ID and PublicKeyCredential"
+// @Param X-Client-Type header string false "web to receive the refresh token as a cookie"
+// @Success 200 {object} dto.AuthResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 423 {object} dto.ErrorResponse
+// @Router /api/auth/passkeys/login/finish [post]
+func (h *AuthHandler) FinishPasskeyLogin(c *gin.Context) {
+	var req dto.PasskeyFinishRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+			Error:   "validation_error",
+			Message: "Invalid request payload",
+			Details: validationDetails(err),
+		})
+		return
+	}
+
+	response, err := h.authUseCase.FinishPasskeyLogin(clientContext(c), req.SessionID, req.Credential)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	h.respondWithTokens(c, http.StatusOK, response)
+}
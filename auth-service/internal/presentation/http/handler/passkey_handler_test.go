@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"auth-service/config"
+	"auth-service/internal/application/dto"
+	"auth-service/internal/application/usecase"
+	"auth-service/internal/infrastructure/passkey"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-webauthn/webauthn/webauthn"
+)
+
+func TestPasskeyLoginEndpoints(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	wa, err := webauthn.New(&webauthn.Config{RPID: "example.com", RPDisplayName: "Test", RPOrigins: []string{"https://example.com"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	uc := usecase.NewAuthUseCase(nil, nil, nil, nil, nil, nil, 0, 0, config.SecurityConfig{},
+		usecase.WithPasskeys(wa, nil, passkey.NewMemorySessionStore(), time.Minute))
+	h := NewAuthHandler(uc, nil)
+	router := gin.New()
+	router.POST("/auth/passkeys/login/begin", h.BeginPasskeyLogin)
+	router.POST("/auth/passkeys/login/finish", h.FinishPasskeyLogin)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/auth/passkeys/login/begin", nil))
+	var begin struct {
+		SessionID string `json:"session_id"`
+		Options   struct {
+			PublicKey struct {
+				Challenge string `json:"challenge"`
+				RPID      string `json:"rpId"`
+			} `json:"publicKey"`
+		} `json:"options"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &begin); err != nil || rec.Code != http.StatusOK {
+		t.Fatalf("begin: %d %s", rec.Code, rec.Body)
+	}
+	if begin.SessionID == "" || begin.Options.PublicKey.Challenge == "" || begin.Options.PublicKey.RPID != "example.com" {
+		t.Errorf("begin response = %s", rec.Body)
+	}
+
+	tests := []struct {
+		name     string
+		body     string
+		want     int
+		wantCode string
+	}{
+		{"missing credential", `{"session_id":"` + begin.SessionID + `"}`, http.StatusBadRequest, "validation_error"},
+		{"malformed credential", `{"session_id":"` + begin.SessionID + `","credential":{"id":"x"}}`, http.StatusUnauthorized, "passkey_verification_failed"},
+		// The session was used by the attempt above
+		{"used session", `{"session_id":"` + begin.SessionID + `","credential":{"id":"x"}}`, http.StatusBadRequest, "passkey_session_expired"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/auth/passkeys/login/finish", strings.NewReader(tt.body)))
+			var resp dto.ErrorResponse
+			json.Unmarshal(rec.Body.Bytes(), &resp)
+			if rec.Code != tt.want || resp.Error != tt.wantCode {
+				t.Errorf("got %d %q, want %d %q", rec.Code, resp.Error, tt.want, tt.wantCode)
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"auth-service/internal/application/dto"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Refresh token cookie mode: browsers get the refresh token in an HttpOnly
+// cookie, out of reach of scripts, instead of the JSON body
+const (
+	// ClientTypeHeader set to "web" switches a request to cookie mode
+	ClientTypeHeader = "X-Client-Type"
+	clientTypeWeb    = "web"
+
+	refreshTokenCookie = "refresh_token"
+	// refreshTokenCookiePath limits the cookie to the auth endpoints that
+	// read it (/auth/refresh and /auth/logout)
+	refreshTokenCookiePath = "/api/auth"
+)
+
+// AuthHandlerOption configures optional auth handler behaviour
+type AuthHandlerOption func(*AuthHandler)
+
+// WithRefreshTokenCookie puts the refresh token in a cookie for every
+// client, not only those sending "X-Client-Type: web"
+func WithRefreshTokenCookie() AuthHandlerOption {
+	return func(h *AuthHandler) {
+		h.refreshTokenCookie = true
+	}
+}
+
+// cookieMode reports whether the request's refresh token travels in a cookie
+func (h *AuthHandler) cookieMode(c *gin.Context) bool {
+	return h.refreshTokenCookie || c.GetHeader(ClientTypeHeader) == clientTypeWeb
+}
+
+// respondWithTokens writes response; in cookie mode the refresh token is
+// moved from the body into the cookie
+func (h *AuthHandler) respondWithTokens(c *gin.Context, status int, response *dto.AuthResponse) {
+	if h.cookieMode(c) && response.RefreshToken != "" {
+		setRefreshTokenCookie(c, response.RefreshToken, response.RefreshTokenExpiresAt)
+		body := *response
+		body.RefreshToken = ""
+		response = &body
+	}
+	c.JSON(status, response)
+}
+
+// refreshTokenFromCookie returns the refresh token cookie of a cookie-mode
+// request, or "" if there is none
+func (h *AuthHandler) refreshTokenFromCookie(c *gin.Context) string {
+	if !h.cookieMode(c) {
+		return ""
+	}
+	token, _ := c.Cookie(refreshTokenCookie)
+	return token
+}
+
+func setRefreshTokenCookie(c *gin.Context, token string, expiresAt time.Time) {
+	http.SetCookie(c.Writer, &http.Cookie{
+		Name:     refreshTokenCookie,
+		Value:    token,
+		Path:     refreshTokenCookiePath,
+		Expires:  expiresAt,
+		MaxAge:   int(time.Until(expiresAt).Seconds()),
+		Secure:   true,
+		HttpOnly: true,
+		SameSite: http.SameSiteStrictMode,
+	})
+}
+
+func clearRefreshTokenCookie(c *gin.Context) {
+	http.SetCookie(c.Writer, &http.Cookie{
+		Name:     refreshTokenCookie,
+		Path:     refreshTokenCookiePath,
+		MaxAge:   -1,
+		Secure:   true,
+		HttpOnly: true,
+		SameSite: http.SameSiteStrictMode,
+	})
+}
@@ -0,0 +1,146 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"auth-service/config"
+	"auth-service/internal/application/dto"
+	"auth-service/internal/application/usecase"
+	"auth-service/internal/domain"
+	"auth-service/internal/presentation/http/authctx"
+	"auth-service/pkg/security"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// refreshCookie returns the refresh token cookie set on rec, or nil
+func refreshCookie(rec *httptest.ResponseRecorder) *http.Cookie {
+	for _, cookie := range rec.Result().Cookies() {
+		if cookie.Name == refreshTokenCookie {
+			return cookie
+		}
+	}
+	return nil
+}
+
+func TestRespondWithTokensCookieMode(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	response := &dto.AuthResponse{AccessToken: "access", RefreshToken: "refresh", RefreshTokenExpiresAt: time.Now().Add(time.Hour)}
+
+	tests := []struct {
+		name       string
+		opts       []AuthHandlerOption
+		clientType string
+		wantCookie bool
+	}{
+		{"api client", nil, "", false},
+		{"web client", nil, "web", true},
+		{"cookie mode for everyone", []AuthHandlerOption{WithRefreshTokenCookie()}, "", true},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(rec)
+		c.Request = httptest.NewRequest(http.MethodPost, "/api/auth/login", nil)
+		if tt.clientType != "" {
+			c.Request.Header.Set(ClientTypeHeader, tt.clientType)
+		}
+		NewAuthHandler(nil, nil, tt.opts...).respondWithTokens(c, http.StatusOK, response)
+
+		var body dto.AuthResponse
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+			t.Fatal(err)
+		}
+		if body.AccessToken != "access" {
+			t.Errorf("%s: access token missing from body", tt.name)
+		}
+		cookie := refreshCookie(rec)
+		if !tt.wantCookie {
+			if cookie != nil || body.RefreshToken != "refresh" {
+				t.Errorf("%s: cookie = %v, body refresh token = %q", tt.name, cookie, body.RefreshToken)
+			}
+			continue
+		}
+		if body.RefreshToken != "" || strings.Contains(rec.Body.String(), "refresh_token") {
+			t.Errorf("%s: refresh token in body: %s", tt.name, rec.Body)
+		}
+		if cookie == nil || cookie.Value != "refresh" || !cookie.HttpOnly || !cookie.Secure ||
+			cookie.SameSite != http.SameSiteStrictMode || cookie.Path != refreshTokenCookiePath || cookie.MaxAge <= 0 {
+			t.Errorf("%s: Set-Cookie = %q", tt.name, rec.Header().Get("Set-Cookie"))
+		}
+	}
+	if response.RefreshToken != "refresh" {
+		t.Error("respondWithTokens modified the use case response")
+	}
+}
+
+func TestRefreshTokenReadsCookie(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	repo := newStubRefreshTokenRepo()
+	uc := usecase.NewAuthUseCase(nil, repo, nil, nil, nil, nil, 0, 0, config.SecurityConfig{})
+	router := gin.New()
+	router.POST("/api/auth/refresh", NewAuthHandler(uc, nil).RefreshToken)
+
+	tests := []struct {
+		name, clientType, body string
+		want                   int
+		wantLookup             string
+	}{
+		// The cookie is only read in cookie mode
+		{"api client with cookie", "", "", http.StatusBadRequest, ""},
+		{"web client with cookie", "web", "", http.StatusUnauthorized, security.HashToken("from-cookie")},
+		{"body wins over cookie", "web", `{"refresh_token":"from-body"}`, http.StatusUnauthorized, security.HashToken("from-body")},
+	}
+	for _, tt := range tests {
+		repo.looked = nil
+		req := httptest.NewRequest(http.MethodPost, "/api/auth/refresh", strings.NewReader(tt.body))
+		req.AddCookie(&http.Cookie{Name: refreshTokenCookie, Value: "from-cookie"})
+		if tt.clientType != "" {
+			req.Header.Set(ClientTypeHeader, tt.clientType)
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		if rec.Code != tt.want {
+			t.Errorf("%s: status = %d, want %d: %s", tt.name, rec.Code, tt.want, rec.Body)
+		}
+		if got := strings.Join(repo.looked, ","); got != tt.wantLookup {
+			t.Errorf("%s: looked up %q, want %q", tt.name, got, tt.wantLookup)
+		}
+		// A rejected cookie is cleared
+		if cookie := refreshCookie(rec); tt.clientType == "web" && (cookie == nil || cookie.MaxAge >= 0) {
+			t.Errorf("%s: cookie not cleared: %q", tt.name, rec.Header().Get("Set-Cookie"))
+		}
+	}
+}
+
+func TestLogoutReadsAndClearsCookie(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	userID := uuid.New()
+	repo := newStubRefreshTokenRepo(&domain.RefreshToken{ID: uuid.New(), UserID: userID, TokenHash: security.HashToken("mine")})
+	uc := usecase.NewAuthUseCase(nil, repo, nil, nil, nil, nil, 0, 0, config.SecurityConfig{})
+	router := gin.New()
+	router.Use(func(c *gin.Context) { authctx.Set(c, &authctx.AuthContext{UserID: userID}) })
+	router.POST("/api/auth/logout", NewAuthHandler(uc, nil).Logout)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/auth/logout", nil)
+	req.Header.Set(ClientTypeHeader, "web")
+	req.AddCookie(&http.Cookie{Name: refreshTokenCookie, Value: "mine"})
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", rec.Code, rec.Body)
+	}
+	if !repo.revoked[security.HashToken("mine")] {
+		t.Error("session from the cookie was not revoked")
+	}
+	if cookie := refreshCookie(rec); cookie == nil || cookie.MaxAge >= 0 {
+		t.Errorf("cookie not cleared: %q", rec.Header().Get("Set-Cookie"))
+	}
+}
@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"auth-service/internal/application/dto"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestValidationErrorsNameJSONFields(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	h := &AuthHandler{}
+	router := gin.New()
+	router.POST("/register", h.Register)
+	router.POST("/login", h.Login)
+	router.POST("/refresh", h.RefreshToken)
+
+	tests := []struct {
+		path, body string
+		want       map[string]string
+	}{
+		{"/register", `{"email":"jane","username":"jd","password":"short","last_name":"Doe"}`, map[string]string{
+			"email":      "must be a valid email",
+			"username":   "must be at least 3 characters",
+			"password":   "must be at least 8 characters",
+			"first_name": "is required",
+		}},
+		{"/login", `{"password":"x"}`, map[string]string{"email_or_username": "is required"}},
+		{"/login", `{"email_or_username":"jane","password":"x","remember_me":"yes"}`, map[string]string{"remember_me": "must be a boolean"}},
+		{"/refresh", `{}`, map[string]string{"refresh_token": "is required"}},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, tt.path, strings.NewReader(tt.body)))
+
+		var resp dto.ErrorResponse
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+			t.Fatal(err)
+		}
+		if rec.Code != http.StatusBadRequest || resp.Error != "validation_error" {
+			t.Errorf("%s %s: got %d %s", tt.path, tt.body, rec.Code, rec.Body)
+		}
+		if !reflect.DeepEqual(resp.Details, tt.want) {
+			t.Errorf("%s %s: details = %v, want %v", tt.path, tt.body, resp.Details, tt.want)
+		}
+	}
+}
+
+func TestValidationDetailsForMalformedJSON(t *testing.T) {
+	var req dto.LoginRequest
+	err := json.Unmarshal([]byte(`{`), &req)
+	if got := validationDetails(err); got["validation"] == "" || len(got) != 1 {
+		t.Errorf("details = %v, want the parse error under validation", got)
+	}
+}
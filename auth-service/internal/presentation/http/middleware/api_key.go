@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+
+	"auth-service/internal/application/dto"
+	"auth-service/internal/domain"
+
+	"github.com/gin-gonic/gin"
+)
+
+// APIKeyHeader carries the API key of a calling service
+const APIKeyHeader = "X-API-Key"
+
+// APIKeyAuthenticator resolves a presented API key. It returns an error for
+// unknown, revoked and expired keys.
+type APIKeyAuthenticator interface {
+	AuthenticateAPIKey(ctx context.Context, key string) (*domain.APIKey, error)
+}
+
+// APIKeyMiddleware authenticates service-to-service requests by the
+// X-API-Key header. The key's ID and scopes are put in the context as
+// "apiKeyID" and "scopes" for RequireScope.
+func APIKeyMiddleware(keys APIKeyAuthenticator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		presented := c.GetHeader(APIKeyHeader)
+		if presented == "" {
+			c.JSON(http.StatusUnauthorized, dto.ErrorResponse{
+				Error:   "missing_api_key",
+				Message: "X-API-Key header is required",
+			})
+			c.Abort()
+			return
+		}
+
+		key, err := keys.AuthenticateAPIKey(c.Request.Context(), presented)
+		if err != nil {
+			c.JSON(http.StatusUnauthorized, dto.ErrorResponse{
+				Error:   "invalid_api_key",
+				Message: "Invalid, revoked or expired API key",
+			})
+			c.Abort()
+			return
+		}
+
+		c.Set("apiKeyID", key.ID.String())
+		c.Set("scopes", key.Scopes)
+		c.Next()
+	}
+}
@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"auth-service/internal/domain"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type stubAPIKeys map[string]*domain.APIKey
+
+func (s stubAPIKeys) AuthenticateAPIKey(ctx context.Context, key string) (*domain.APIKey, error) {
+	if k, ok := s[key]; ok {
+		return k, nil
+	}
+	return nil, errors.New("invalid API key")
+}
+
+func TestAPIKeyMiddlewareAndRequireScope(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	keys := stubAPIKeys{
+		"ak_gateway": {ID: uuid.New(), Scopes: []string{domain.ScopeTokenIntrospect}},
+		"ak_other":   {ID: uuid.New(), Scopes: []string{"something:else"}},
+	}
+	router := gin.New()
+	router.POST("/introspect", APIKeyMiddleware(keys), RequireScope(domain.ScopeTokenIntrospect), func(c *gin.Context) {
+		c.String(http.StatusOK, c.GetString("apiKeyID"))
+	})
+
+	tests := []struct {
+		name string
+		key  string
+		want int
+	}{
+		{"granted scope", "ak_gateway", http.StatusOK},
+		{"missing scope", "ak_other", http.StatusForbidden},
+		{"unknown key", "ak_unknown", http.StatusUnauthorized},
+		{"no key", "", http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodPost, "/introspect", nil)
+		if tt.key != "" {
+			req.Header.Set(APIKeyHeader, tt.key)
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		if rec.Code != tt.want {
+			t.Errorf("%s: status = %d, want %d", tt.name, rec.Code, tt.want)
+		}
+		if tt.want == http.StatusOK && rec.Body.String() != keys[tt.key].ID.String() {
+			t.Errorf("%s: apiKeyID = %q", tt.name, rec.Body)
+		}
+	}
+}
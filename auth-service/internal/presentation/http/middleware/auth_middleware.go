@@ -1,17 +1,44 @@
 package middleware
 
 import (
+	"context"
+	"errors"
 	"net/http"
 	"strings"
+	"time"
 
 	"auth-service/internal/application/dto"
+	"auth-service/internal/application/usecase"
+	"auth-service/internal/domain"
+	"auth-service/internal/presentation/http/authctx"
 	"auth-service/pkg/security"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 )
 
-// AuthMiddleware validates JWT tokens
-func AuthMiddleware(jwtService *security.JWTService) gin.HandlerFunc {
+// TokenVersionSource returns a user's current token version. Access tokens
+// whose "tv" claim is older were invalidated, e.g. by a password change.
+type TokenVersionSource interface {
+	CurrentTokenVersion(ctx context.Context, userID uuid.UUID) (int, error)
+}
+
+// AuthMiddleware validates JWT tokens and rejects tokens revoked through the
+// blacklist or invalidated by a newer token version. A nil blacklist or
+// versions skips that check.
+func AuthMiddleware(jwtService *security.JWTService, blacklist domain.TokenBlacklist, versions TokenVersionSource) gin.HandlerFunc {
+	return authenticate(jwtService, blacklist, versions, 0)
+}
+
+// ReadOnlyAuthMiddleware is AuthMiddleware for safe, read-only routes: it also
+// accepts access tokens that expired less than grace ago, so a client whose
+// refresh is still in flight does not get a spurious 401. Never use it on
+// routes that change state.
+func ReadOnlyAuthMiddleware(jwtService *security.JWTService, blacklist domain.TokenBlacklist, versions TokenVersionSource, grace time.Duration) gin.HandlerFunc {
+	return authenticate(jwtService, blacklist, versions, grace)
+}
+
+func authenticate(jwtService *security.JWTService, blacklist domain.TokenBlacklist, versions TokenVersionSource, grace time.Duration) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// Extract token from Authorization header
 		authHeader := c.GetHeader("Authorization")
@@ -38,20 +65,94 @@ func AuthMiddleware(jwtService *security.JWTService) gin.HandlerFunc {
 		tokenString := parts[1]
 
 		// Validate token
-		claims, err := jwtService.ValidateToken(tokenString)
+		claims, err := jwtService.ValidateTokenWithGrace(tokenString, grace)
+		if err != nil {
+			// An expired token only needs a refresh; anything else needs a new login
+			if errors.Is(err, security.ErrExpiredToken) {
+				c.JSON(http.StatusUnauthorized, dto.ErrorResponse{
+					Error:   "access_token_expired",
+					Message: "Access token has expired; refresh it",
+				})
+				c.Abort()
+				return
+			}
+			c.JSON(http.StatusUnauthorized, dto.ErrorResponse{
+				Error:   "invalid_token",
+				Message: "Invalid token",
+			})
+			c.Abort()
+			return
+		}
+
+		auth, err := authctx.FromClaims(claims)
 		if err != nil {
 			c.JSON(http.StatusUnauthorized, dto.ErrorResponse{
 				Error:   "invalid_token",
-				Message: "Invalid or expired token",
+				Message: "Invalid token",
 			})
 			c.Abort()
 			return
 		}
 
+		// Reject tokens revoked by logout, or whose session was revoked (e.g. a
+		// banned user). Fail closed: if the blacklist cannot be read, the token
+		// cannot be trusted either
+		for _, id := range []string{claims.ID, claims.SessionID} {
+			if blacklist == nil || id == "" {
+				continue
+			}
+			revoked, err := blacklist.Contains(c.Request.Context(), id)
+			if err != nil {
+				c.JSON(http.StatusServiceUnavailable, dto.ErrorResponse{
+					Error:   "service_unavailable",
+					Message: "Unable to verify token",
+				})
+				c.Abort()
+				return
+			}
+			if revoked {
+				c.JSON(http.StatusUnauthorized, dto.ErrorResponse{
+					Error:   "token_revoked",
+					Message: "Token has been revoked",
+				})
+				c.Abort()
+				return
+			}
+		}
+
+		// Reject tokens issued before the user's token version was incremented
+		// (password change, force logout) and tokens of deleted users. The
+		// session may still be alive, so the client should try a refresh
+		if versions != nil {
+			version, err := versions.CurrentTokenVersion(c.Request.Context(), auth.UserID)
+			if errors.Is(err, usecase.ErrUserNotFound) {
+				c.JSON(http.StatusUnauthorized, dto.ErrorResponse{
+					Error:   "token_revoked",
+					Message: "Token has been revoked",
+				})
+				c.Abort()
+				return
+			}
+			if err != nil {
+				c.JSON(http.StatusServiceUnavailable, dto.ErrorResponse{
+					Error:   "service_unavailable",
+					Message: "Unable to verify token",
+				})
+				c.Abort()
+				return
+			}
+			if auth.TokenVersion < version {
+				c.JSON(http.StatusUnauthorized, dto.ErrorResponse{
+					Error:   "access_token_outdated",
+					Message: "Access token was issued before the user's sessions were reset; refresh it",
+				})
+				c.Abort()
+				return
+			}
+		}
+
 		// Set user info in context
-		c.Set("userID", claims.UserID)
-		c.Set("email", claims.Email)
-		c.Set("username", claims.Username)
+		authctx.Set(c, auth)
 
 		c.Next()
 	}
@@ -0,0 +1,252 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"auth-service/internal/application/usecase"
+	"auth-service/internal/domain"
+	"auth-service/internal/infrastructure/blacklist"
+	"auth-service/internal/presentation/http/authctx"
+	"auth-service/pkg/security"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+type failingBlacklist struct{}
+
+func (failingBlacklist) Add(ctx context.Context, tokenID string, ttl time.Duration) error {
+	return errors.New("redis down")
+}
+
+func (failingBlacklist) Contains(ctx context.Context, tokenID string) (bool, error) {
+	return false, errors.New("redis down")
+}
+
+func TestAuthMiddlewareBlacklist(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	jwtService := security.NewJWTService("test-secret", time.Minute, time.Hour)
+
+	token, err := jwtService.GenerateAccessToken(uuid.New(), "jane@example.com", "jane", "user", nil, uuid.New(), uuid.Nil, 0, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	claims, err := jwtService.ValidateToken(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	revoked := blacklist.NewMemoryTokenBlacklist()
+	if err := revoked.Add(context.Background(), claims.ID, time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	// A revoked session (e.g. a banned user) rejects every access token issued for it
+	revokedSession := blacklist.NewMemoryTokenBlacklist()
+	if err := revokedSession.Add(context.Background(), claims.SessionID, time.Minute); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		blacklist domain.TokenBlacklist
+		want      int
+	}{
+		{"no blacklist", nil, http.StatusOK},
+		{"not revoked", blacklist.NewMemoryTokenBlacklist(), http.StatusOK},
+		{"revoked", revoked, http.StatusUnauthorized},
+		{"session revoked", revokedSession, http.StatusUnauthorized},
+		{"blacklist unavailable", failingBlacklist{}, http.StatusServiceUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.GET("/me", AuthMiddleware(jwtService, tt.blacklist, nil), func(c *gin.Context) {
+				auth, ok := authctx.From(c)
+				if !ok || auth.TokenID != claims.ID || auth.ExpiresAt.IsZero() || auth.SessionID.String() != claims.SessionID {
+					t.Errorf("auth context = %+v, want the token's claims", auth)
+				}
+				c.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/me", nil)
+			req.Header.Set("Authorization", "Bearer "+token)
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tt.want {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadOnlyAuthMiddlewareGrace(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	// The token expired 5 seconds ago; no clock skew leeway
+	jwtService := security.NewJWTService("test-secret", -5*time.Second, time.Hour, security.WithLeeway(0))
+	token, err := jwtService.GenerateAccessToken(uuid.New(), "jane@example.com", "jane", "user", nil, uuid.New(), uuid.Nil, 0, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	router := gin.New()
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	router.GET("/me", ReadOnlyAuthMiddleware(jwtService, nil, nil, 30*time.Second), ok)
+	router.GET("/me-no-grace", ReadOnlyAuthMiddleware(jwtService, nil, nil, 0), ok)
+	router.PUT("/password", AuthMiddleware(jwtService, nil, nil), ok)
+
+	for _, tt := range []struct {
+		method, path string
+		want         int
+	}{
+		{http.MethodGet, "/me", http.StatusOK},
+		{http.MethodGet, "/me-no-grace", http.StatusUnauthorized},
+		{http.MethodPut, "/password", http.StatusUnauthorized},
+	} {
+		req := httptest.NewRequest(tt.method, tt.path, nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+
+		if rec.Code != tt.want {
+			t.Errorf("%s %s: status = %d, want %d", tt.method, tt.path, rec.Code, tt.want)
+		}
+	}
+}
+
+func TestAuthMiddlewareRejectsNonAccessTokens(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	jwtService := security.NewJWTService("test-secret", time.Minute, time.Hour)
+
+	// Correctly signed, but meant for the refresh flow
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, &security.JWTClaims{
+		UserID:   uuid.NewString(),
+		TokenUse: "refresh",
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+		},
+	}).SignedString([]byte("test-secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	router := gin.New()
+	router.GET("/me", AuthMiddleware(jwtService, nil, nil), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	req := httptest.NewRequest(http.MethodGet, "/me", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusUnauthorized)
+	}
+}
+
+func TestAuthMiddlewareReportsExpiredTokens(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	expired := security.NewJWTService("test-secret", -time.Minute, time.Hour, security.WithLeeway(0))
+	expiredToken, err := expired.GenerateAccessToken(uuid.New(), "jane@example.com", "jane", "user", nil, uuid.New(), uuid.Nil, 0, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other := security.NewJWTService("other-secret", time.Minute, time.Hour)
+	forgedToken, err := other.GenerateAccessToken(uuid.New(), "jane@example.com", "jane", "user", nil, uuid.New(), uuid.Nil, 0, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	jwtService := security.NewJWTService("test-secret", time.Minute, time.Hour, security.WithLeeway(0))
+	router := gin.New()
+	router.GET("/me", AuthMiddleware(jwtService, nil, nil), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	tests := []struct {
+		name  string
+		token string
+		code  string
+	}{
+		{"expired", expiredToken, "access_token_expired"},
+		{"wrong signature", forgedToken, "invalid_token"},
+		{"malformed", "not-a-jwt", "invalid_token"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/me", nil)
+			req.Header.Set("Authorization", "Bearer "+tt.token)
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != http.StatusUnauthorized {
+				t.Errorf("status = %d, want %d", rec.Code, http.StatusUnauthorized)
+			}
+			if !strings.Contains(rec.Body.String(), `"error":"`+tt.code+`"`) {
+				t.Errorf("body = %s, want error %q", rec.Body.String(), tt.code)
+			}
+		})
+	}
+}
+
+// stubTokenVersions returns version for known users and err for all calls when set
+type stubTokenVersions struct {
+	versions map[uuid.UUID]int
+	err      error
+}
+
+func (s stubTokenVersions) CurrentTokenVersion(ctx context.Context, userID uuid.UUID) (int, error) {
+	if s.err != nil {
+		return 0, s.err
+	}
+	version, ok := s.versions[userID]
+	if !ok {
+		return 0, usecase.ErrUserNotFound
+	}
+	return version, nil
+}
+
+func TestAuthMiddlewareTokenVersion(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	jwtService := security.NewJWTService("test-secret", time.Minute, time.Hour)
+	userID := uuid.New()
+	token, err := jwtService.GenerateAccessToken(userID, "jane@example.com", "jane", "user", nil, uuid.New(), uuid.Nil, 1, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		versions  TokenVersionSource
+		want      int
+		wantError string
+	}{
+		{"no version check", nil, http.StatusOK, ""},
+		{"current version", stubTokenVersions{versions: map[uuid.UUID]int{userID: 1}}, http.StatusOK, ""},
+		{"incremented since", stubTokenVersions{versions: map[uuid.UUID]int{userID: 2}}, http.StatusUnauthorized, "access_token_outdated"},
+		{"deleted user", stubTokenVersions{}, http.StatusUnauthorized, "token_revoked"},
+		{"versions unavailable", stubTokenVersions{err: errors.New("database down")}, http.StatusServiceUnavailable, "service_unavailable"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.GET("/me", AuthMiddleware(jwtService, nil, tt.versions), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+			req := httptest.NewRequest(http.MethodGet, "/me", nil)
+			req.Header.Set("Authorization", "Bearer "+token)
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tt.want {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.want)
+			}
+			if tt.wantError != "" && !strings.Contains(rec.Body.String(), `"error":"`+tt.wantError+`"`) {
+				t.Errorf("body = %s, want error %q", rec.Body, tt.wantError)
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"context"
+	"log"
+	"net/http"
+
+	"auth-service/internal/application/dto"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CaptchaTokenField is the JSON body field carrying the CAPTCHA solution
+const CaptchaTokenField = "captcha_token"
+
+// CaptchaVerifier checks a CAPTCHA solution with the provider
+type CaptchaVerifier interface {
+	// Verify reports whether token is a valid solution submitted from remoteIP
+	Verify(ctx context.Context, token, remoteIP string) (bool, error)
+}
+
+// Captcha requires a solved CAPTCHA in the captcha_token field of the JSON
+// body. It is a no-op when verifier is nil. Unlike RateLimit it fails
+// closed: while the provider is unreachable requests get 503, since letting
+// them through would open the endpoint to the bots it is meant to stop.
+func Captcha(verifier CaptchaVerifier) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if verifier == nil {
+			c.Next()
+			return
+		}
+
+		token, err := jsonBodyField(c, CaptchaTokenField)
+		if err != nil || token == "" {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error:   "captcha_required",
+				Message: "A CAPTCHA solution is required (captcha_token)",
+			})
+			c.Abort()
+			return
+		}
+
+		ok, err := verifier.Verify(c.Request.Context(), token, c.ClientIP())
+		if err != nil {
+			log.Printf("captcha: %v", err)
+			c.JSON(http.StatusServiceUnavailable, dto.ErrorResponse{
+				Error:   "captcha_unavailable",
+				Message: "CAPTCHA verification is temporarily unavailable",
+			})
+			c.Abort()
+			return
+		}
+		if !ok {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error:   "captcha_invalid",
+				Message: "CAPTCHA verification failed; solve it again",
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,62 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// stubCaptcha accepts the token "solved"
+type stubCaptcha struct {
+	err error
+}
+
+func (s stubCaptcha) Verify(ctx context.Context, token, remoteIP string) (bool, error) {
+	return token == "solved", s.err
+}
+
+func TestCaptcha(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name     string
+		verifier CaptchaVerifier
+		body     string
+		want     int
+		wantCode string
+	}{
+		{"disabled", nil, `{"email":"jane@example.com"}`, http.StatusOK, ""},
+		{"solved", stubCaptcha{}, `{"email":"jane@example.com","captcha_token":"solved"}`, http.StatusOK, ""},
+		{"missing token", stubCaptcha{}, `{"email":"jane@example.com"}`, http.StatusBadRequest, "captcha_required"},
+		{"failed", stubCaptcha{}, `{"captcha_token":"guess"}`, http.StatusBadRequest, "captcha_invalid"},
+		{"provider down", stubCaptcha{err: errors.New("timeout")}, `{"captcha_token":"solved"}`, http.StatusServiceUnavailable, "captcha_unavailable"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.POST("/register", Captcha(tt.verifier), func(c *gin.Context) {
+				// The handler still sees the whole body
+				body, _ := io.ReadAll(c.Request.Body)
+				c.String(http.StatusOK, string(body))
+			})
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/register", strings.NewReader(tt.body)))
+
+			if w.Code != tt.want {
+				t.Fatalf("status = %d, want %d (body %s)", w.Code, tt.want, w.Body)
+			}
+			if tt.wantCode != "" && !strings.Contains(w.Body.String(), tt.wantCode) {
+				t.Errorf("body = %s, want %s", w.Body, tt.wantCode)
+			}
+			if tt.want == http.StatusOK && w.Body.String() != tt.body {
+				t.Errorf("handler got body %q", w.Body)
+			}
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CORSConfig configures cross-origin access. Nothing is allowed by default:
+// origins, methods and headers must be listed explicitly.
+type CORSConfig struct {
+	// AllowedOrigins are exact origins ("https://app.example.com"). "*"
+	// allows any origin, but only without AllowCredentials.
+	AllowedOrigins []string
+	AllowedMethods []string
+	// AllowedHeaders are the request headers a browser may send, e.g.
+	// Authorization and X-Request-ID
+	AllowedHeaders []string
+	// ExposedHeaders are the response headers scripts may read
+	ExposedHeaders []string
+	// AllowCredentials lets browsers send cookies and HTTP auth
+	AllowCredentials bool
+	// MaxAge is how long browsers may cache a preflight response
+	MaxAge time.Duration
+}
+
+// CORS answers preflight requests and adds CORS headers to requests from
+// allowed origins. Requests from other origins get no CORS headers, so the
+// browser blocks the response; their preflights are rejected with 403.
+// Requests without an Origin header (same origin, server to server) pass
+// through untouched.
+func CORS(config CORSConfig) gin.HandlerFunc {
+	origins := make(map[string]bool, len(config.AllowedOrigins))
+	anyOrigin := false
+	for _, origin := range config.AllowedOrigins {
+		origin = strings.ToLower(strings.TrimRight(strings.TrimSpace(origin), "/"))
+		if origin == "*" {
+			// The spec forbids "*" with credentials; never reflect every origin
+			anyOrigin = !config.AllowCredentials
+			continue
+		}
+		origins[origin] = true
+	}
+	methods := upperSet(config.AllowedMethods)
+	headers := make(map[string]bool, len(config.AllowedHeaders))
+	for _, header := range config.AllowedHeaders {
+		headers[http.CanonicalHeaderKey(strings.TrimSpace(header))] = true
+	}
+
+	allowMethods := strings.Join(config.AllowedMethods, ", ")
+	allowHeaders := strings.Join(config.AllowedHeaders, ", ")
+	exposeHeaders := strings.Join(config.ExposedHeaders, ", ")
+	maxAge := strconv.Itoa(int(config.MaxAge.Seconds()))
+
+	return func(c *gin.Context) {
+		origin := c.GetHeader("Origin")
+		if origin == "" {
+			c.Next()
+			return
+		}
+		// The response depends on Origin; caches must not share it across origins
+		c.Writer.Header().Add("Vary", "Origin")
+
+		preflight := c.Request.Method == http.MethodOptions && c.GetHeader("Access-Control-Request-Method") != ""
+		if !anyOrigin && !origins[strings.ToLower(origin)] {
+			if preflight {
+				c.AbortWithStatus(http.StatusForbidden)
+				return
+			}
+			c.Next()
+			return
+		}
+		// Preflight: every requested method and header must be allowed
+		if preflight && (!methods[strings.ToUpper(c.GetHeader("Access-Control-Request-Method"))] ||
+			!allHeadersAllowed(c.GetHeader("Access-Control-Request-Headers"), headers)) {
+			c.AbortWithStatus(http.StatusForbidden)
+			return
+		}
+
+		h := c.Writer.Header()
+		if anyOrigin {
+			h.Set("Access-Control-Allow-Origin", "*")
+		} else {
+			h.Set("Access-Control-Allow-Origin", origin)
+		}
+		if config.AllowCredentials {
+			h.Set("Access-Control-Allow-Credentials", "true")
+		}
+
+		if !preflight {
+			if exposeHeaders != "" {
+				h.Set("Access-Control-Expose-Headers", exposeHeaders)
+			}
+			c.Next()
+			return
+		}
+
+		h.Add("Vary", "Access-Control-Request-Method")
+		h.Add("Vary", "Access-Control-Request-Headers")
+		h.Set("Access-Control-Allow-Methods", allowMethods)
+		if allowHeaders != "" {
+			h.Set("Access-Control-Allow-Headers", allowHeaders)
+		}
+		if config.MaxAge > 0 {
+			h.Set("Access-Control-Max-Age", maxAge)
+		}
+		c.AbortWithStatus(http.StatusNoContent)
+	}
+}
+
+// allHeadersAllowed checks a comma-separated Access-Control-Request-Headers value
+func allHeadersAllowed(requested string, allowed map[string]bool) bool {
+	for _, header := range strings.Split(requested, ",") {
+		header = strings.TrimSpace(header)
+		if header != "" && !allowed[http.CanonicalHeaderKey(header)] {
+			return false
+		}
+	}
+	return true
+}
+
+func upperSet(values []string) map[string]bool {
+	set := make(map[string]bool, len(values))
+	for _, v := range values {
+		set[strings.ToUpper(strings.TrimSpace(v))] = true
+	}
+	return set
+}
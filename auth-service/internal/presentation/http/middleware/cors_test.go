@@ -0,0 +1,131 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func newCORSRouter(config CORSConfig) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(CORS(config))
+	router.POST("/api/auth/login", func(c *gin.Context) { c.Status(http.StatusOK) })
+	return router
+}
+
+func corsRequest(router *gin.Engine, method, origin string, headers map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, "/api/auth/login", nil)
+	if origin != "" {
+		req.Header.Set("Origin", origin)
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+var testCORSConfig = CORSConfig{
+	AllowedOrigins:   []string{"https://app.example.com/"},
+	AllowedMethods:   []string{"GET", "POST"},
+	AllowedHeaders:   []string{"Content-Type", "Authorization", "X-Request-ID"},
+	ExposedHeaders:   []string{"X-Request-ID"},
+	AllowCredentials: true,
+	MaxAge:           time.Hour,
+}
+
+func TestCORSPreflight(t *testing.T) {
+	router := newCORSRouter(testCORSConfig)
+
+	tests := []struct {
+		name     string
+		origin   string
+		method   string
+		headers  string
+		wantCode int
+	}{
+		{"allowed", "https://app.example.com", "POST", "authorization, x-request-id, content-type", http.StatusNoContent},
+		{"origin case", "HTTPS://APP.example.com", "POST", "", http.StatusNoContent},
+		{"unknown origin", "https://evil.example.com", "POST", "", http.StatusForbidden},
+		{"method not allowed", "https://app.example.com", "DELETE", "", http.StatusForbidden},
+		{"header not allowed", "https://app.example.com", "POST", "Authorization, X-Custom", http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		w := corsRequest(router, http.MethodOptions, tt.origin, map[string]string{
+			"Access-Control-Request-Method":  tt.method,
+			"Access-Control-Request-Headers": tt.headers,
+		})
+		if w.Code != tt.wantCode {
+			t.Errorf("%s: code = %d, want %d", tt.name, w.Code, tt.wantCode)
+			continue
+		}
+		if tt.wantCode != http.StatusNoContent {
+			if got := w.Header().Get("Access-Control-Allow-Origin"); got != "" {
+				t.Errorf("%s: Allow-Origin = %q on a rejected preflight", tt.name, got)
+			}
+			continue
+		}
+		h := w.Header()
+		if h.Get("Access-Control-Allow-Origin") != tt.origin || h.Get("Access-Control-Allow-Credentials") != "true" ||
+			h.Get("Access-Control-Allow-Methods") != "GET, POST" ||
+			h.Get("Access-Control-Allow-Headers") != "Content-Type, Authorization, X-Request-ID" ||
+			h.Get("Access-Control-Max-Age") != "3600" {
+			t.Errorf("%s: headers = %v", tt.name, h)
+		}
+	}
+}
+
+func TestCORSActualRequest(t *testing.T) {
+	router := newCORSRouter(testCORSConfig)
+
+	w := corsRequest(router, http.MethodPost, "https://app.example.com", nil)
+	if w.Code != http.StatusOK || w.Header().Get("Access-Control-Allow-Origin") != "https://app.example.com" ||
+		w.Header().Get("Access-Control-Expose-Headers") != "X-Request-ID" || w.Header().Get("Vary") != "Origin" {
+		t.Errorf("allowed origin: code %d, headers %v", w.Code, w.Header())
+	}
+
+	// The handler still runs; the browser blocks the response without CORS headers
+	w = corsRequest(router, http.MethodPost, "https://evil.example.com", nil)
+	if w.Code != http.StatusOK || w.Header().Get("Access-Control-Allow-Origin") != "" {
+		t.Errorf("unknown origin: code %d, headers %v", w.Code, w.Header())
+	}
+
+	// Same-origin and server-to-server requests are untouched
+	w = corsRequest(router, http.MethodPost, "", nil)
+	if w.Code != http.StatusOK || len(w.Header().Values("Vary")) != 0 {
+		t.Errorf("no origin: code %d, headers %v", w.Code, w.Header())
+	}
+}
+
+func TestCORSDeniesEverythingByDefault(t *testing.T) {
+	router := newCORSRouter(CORSConfig{})
+	w := corsRequest(router, http.MethodOptions, "https://app.example.com", map[string]string{
+		"Access-Control-Request-Method": "POST",
+	})
+	if w.Code != http.StatusForbidden {
+		t.Errorf("code = %d, want 403", w.Code)
+	}
+}
+
+func TestCORSWildcardOrigin(t *testing.T) {
+	config := testCORSConfig
+	config.AllowedOrigins = []string{"*"}
+
+	config.AllowCredentials = false
+	w := corsRequest(newCORSRouter(config), http.MethodPost, "https://any.example.com", nil)
+	if w.Header().Get("Access-Control-Allow-Origin") != "*" || w.Header().Get("Access-Control-Allow-Credentials") != "" {
+		t.Errorf("wildcard without credentials: headers %v", w.Header())
+	}
+
+	// With credentials the wildcard is ignored rather than reflecting every origin
+	config.AllowCredentials = true
+	w = corsRequest(newCORSRouter(config), http.MethodPost, "https://any.example.com", nil)
+	if got := w.Header().Get("Access-Control-Allow-Origin"); got != "" {
+		t.Errorf("wildcard with credentials: Allow-Origin = %q", got)
+	}
+}
@@ -0,0 +1,192 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"auth-service/internal/application/dto"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Idempotency-Key request handling
+const (
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayHeader is set to "true" on responses replayed from an
+	// earlier request with the same key
+	IdempotentReplayHeader = "Idempotent-Replayed"
+
+	maxIdempotencyKeyLength = 255
+	// idempotencyLockTTL bounds how long a key stays claimed by a request
+	// that never finishes (e.g. the replica crashed), so retries can run again
+	idempotencyLockTTL = time.Minute
+)
+
+// IdempotencyStore keeps the responses of requests sent with an
+// Idempotency-Key. Values are opaque to the store.
+type IdempotencyStore interface {
+	// Reserve atomically claims key for lockTTL. If key is already claimed it
+	// returns false and the saved value, which is nil while the request that
+	// claimed it is still running.
+	Reserve(ctx context.Context, key string, lockTTL time.Duration) (claimed bool, saved []byte, err error)
+	// Save stores value for key for ttl, replacing the claim
+	Save(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	// Release drops the claim on key so the request can be sent again
+	Release(ctx context.Context, key string) error
+}
+
+// idempotentResponse is the saved response of a completed request
+type idempotentResponse struct {
+	// Fingerprint identifies the request the response belongs to, so a key
+	// reused for a different request is rejected instead of replayed
+	Fingerprint string `json:"fingerprint"`
+	Status      int    `json:"status"`
+	ContentType string `json:"content_type"`
+	Body        []byte `json:"body"`
+}
+
+// responseRecorder keeps a copy of the response body written by the handler
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *responseRecorder) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *responseRecorder) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// Idempotency makes requests carrying an Idempotency-Key header safe to
+// retry. The first successful (2xx) response for a key is saved for ttl and
+// replayed for later requests with the same key to the same route; other
+// responses are not saved, so a retry runs the request again. While the
+// first request is still running, a second one with the same key gets 409
+// instead of running concurrently. Reusing a key with a different body gets
+// 422, and a body over maxBufferedBodyBytes gets 413 (it is buffered to be
+// fingerprinted). Requests without the header are not affected. If the store fails the
+// request runs normally: an outage of the store must not take down the
+// endpoint.
+func Idempotency(store IdempotencyStore, ttl time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if key == "" {
+			c.Next()
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error:   "invalid_idempotency_key",
+				Message: "Idempotency-Key must be at most 255 characters",
+			})
+			c.Abort()
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxBufferedBodyBytes))
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			c.JSON(http.StatusRequestEntityTooLarge, dto.ErrorResponse{
+				Error:   "request_too_large",
+				Message: "Request body is too large",
+			})
+			c.Abort()
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error:   "invalid_request",
+				Message: "Could not read request body",
+			})
+			c.Abort()
+			return
+		}
+		// Restore the body for the handler
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+		fingerprint := requestFingerprint(c.Request.Method, c.FullPath(), body)
+
+		ctx := c.Request.Context()
+		storeKey := c.FullPath() + "|" + key
+		claimed, saved, err := store.Reserve(ctx, storeKey, idempotencyLockTTL)
+		if err != nil {
+			log.Printf("idempotency: %v", err)
+			c.Next()
+			return
+		}
+		if !claimed {
+			replayIdempotentResponse(c, saved, fingerprint)
+			return
+		}
+
+		recorder := &responseRecorder{ResponseWriter: c.Writer}
+		c.Writer = recorder
+		c.Next()
+
+		// Saved with a fresh context: the client may already have gone away,
+		// which is exactly when it will retry
+		status := recorder.Status()
+		if status < 200 || status >= 300 {
+			if err := store.Release(context.Background(), storeKey); err != nil {
+				log.Printf("idempotency: %v", err)
+			}
+			return
+		}
+		value, err := json.Marshal(idempotentResponse{
+			Fingerprint: fingerprint,
+			Status:      status,
+			ContentType: recorder.Header().Get("Content-Type"),
+			Body:        recorder.body.Bytes(),
+		})
+		if err == nil {
+			err = store.Save(context.Background(), storeKey, value, ttl)
+		}
+		if err != nil {
+			log.Printf("idempotency: %v", err)
+		}
+	}
+}
+
+// replayIdempotentResponse answers a request whose key is already claimed
+func replayIdempotentResponse(c *gin.Context, saved []byte, fingerprint string) {
+	defer c.Abort()
+
+	var resp idempotentResponse
+	if saved == nil || json.Unmarshal(saved, &resp) != nil {
+		c.Header("Retry-After", "1")
+		c.JSON(http.StatusConflict, dto.ErrorResponse{
+			Error:   "idempotency_key_in_use",
+			Message: "A request with this Idempotency-Key is still being processed",
+		})
+		return
+	}
+	if resp.Fingerprint != fingerprint {
+		c.JSON(http.StatusUnprocessableEntity, dto.ErrorResponse{
+			Error:   "idempotency_key_reused",
+			Message: "Idempotency-Key was already used for a different request",
+		})
+		return
+	}
+
+	c.Header(IdempotentReplayHeader, "true")
+	c.Data(resp.Status, resp.ContentType, resp.Body)
+}
+
+// requestFingerprint hashes what makes two requests the same request
+func requestFingerprint(method, route string, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(method + " " + route + "\n"))
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
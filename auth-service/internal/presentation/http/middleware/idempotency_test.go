@@ -0,0 +1,189 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"auth-service/internal/infrastructure/idempotency"
+
+	"github.com/gin-gonic/gin"
+)
+
+// failingStore is an IdempotencyStore whose backend is down
+type failingStore struct{}
+
+func (failingStore) Reserve(ctx context.Context, key string, lockTTL time.Duration) (bool, []byte, error) {
+	return false, nil, errors.New("redis down")
+}
+
+func (failingStore) Save(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return errors.New("redis down")
+}
+
+func (failingStore) Release(ctx context.Context, key string) error {
+	return errors.New("redis down")
+}
+
+// newIdempotentRouter counts the calls to /register and /other; /register
+// answers with status
+func newIdempotentRouter(store IdempotencyStore, calls *atomic.Int32, status int) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	handler := func(c *gin.Context) {
+		n := calls.Add(1)
+		c.JSON(status, gin.H{"call": n})
+	}
+	router.POST("/register", Idempotency(store, time.Hour), handler)
+	router.POST("/other", Idempotency(store, time.Hour), handler)
+	return router
+}
+
+func idempotentRequest(router *gin.Engine, path, key, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if key != "" {
+		req.Header.Set(IdempotencyKeyHeader, key)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestIdempotencyReplaysFirstResponse(t *testing.T) {
+	var calls atomic.Int32
+	router := newIdempotentRouter(idempotency.NewMemoryStore(), &calls, http.StatusCreated)
+
+	first := idempotentRequest(router, "/register", "key-1", `{"email":"jane@example.com"}`)
+	retry := idempotentRequest(router, "/register", "key-1", `{"email":"jane@example.com"}`)
+
+	if calls.Load() != 1 {
+		t.Fatalf("handler ran %d times, want 1", calls.Load())
+	}
+	if retry.Code != http.StatusCreated || retry.Body.String() != first.Body.String() {
+		t.Errorf("retry = %d %s, want %d %s", retry.Code, retry.Body, first.Code, first.Body)
+	}
+	if retry.Header().Get(IdempotentReplayHeader) != "true" || first.Header().Get(IdempotentReplayHeader) != "" {
+		t.Error("only the replayed response should carry the replay header")
+	}
+	if got := retry.Header().Get("Content-Type"); !strings.HasPrefix(got, "application/json") {
+		t.Errorf("replayed content type = %q", got)
+	}
+}
+
+func TestIdempotencyKeysAreScopedToRoute(t *testing.T) {
+	var calls atomic.Int32
+	router := newIdempotentRouter(idempotency.NewMemoryStore(), &calls, http.StatusCreated)
+
+	idempotentRequest(router, "/register", "key-1", `{}`)
+	idempotentRequest(router, "/other", "key-1", `{}`)
+	if calls.Load() != 2 {
+		t.Errorf("handler ran %d times, want once per route", calls.Load())
+	}
+}
+
+func TestIdempotencyRejectsKeyReusedForDifferentRequest(t *testing.T) {
+	var calls atomic.Int32
+	router := newIdempotentRouter(idempotency.NewMemoryStore(), &calls, http.StatusCreated)
+
+	idempotentRequest(router, "/register", "key-1", `{"email":"jane@example.com"}`)
+	w := idempotentRequest(router, "/register", "key-1", `{"email":"john@example.com"}`)
+
+	if w.Code != http.StatusUnprocessableEntity || !strings.Contains(w.Body.String(), "idempotency_key_reused") {
+		t.Errorf("got %d %s, want 422 idempotency_key_reused", w.Code, w.Body)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("handler ran %d times, want 1", calls.Load())
+	}
+}
+
+func TestIdempotencyRejectsConcurrentRequest(t *testing.T) {
+	store := idempotency.NewMemoryStore()
+	started, release := make(chan struct{}), make(chan struct{})
+	var calls atomic.Int32
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/register", Idempotency(store, time.Hour), func(c *gin.Context) {
+		calls.Add(1)
+		close(started)
+		<-release
+		c.JSON(http.StatusCreated, gin.H{})
+	})
+
+	done := make(chan *httptest.ResponseRecorder)
+	go func() { done <- idempotentRequest(router, "/register", "key-1", `{}`) }()
+	<-started
+
+	w := idempotentRequest(router, "/register", "key-1", `{}`)
+	if w.Code != http.StatusConflict || w.Header().Get("Retry-After") == "" {
+		t.Errorf("concurrent request = %d %s, want 409 with Retry-After", w.Code, w.Body)
+	}
+
+	close(release)
+	if first := <-done; first.Code != http.StatusCreated {
+		t.Errorf("first request = %d", first.Code)
+	}
+	if calls.Load() != 1 {
+		t.Errorf("handler ran %d times, want 1", calls.Load())
+	}
+}
+
+func TestIdempotencyDoesNotSaveFailures(t *testing.T) {
+	var calls atomic.Int32
+	router := newIdempotentRouter(idempotency.NewMemoryStore(), &calls, http.StatusInternalServerError)
+
+	idempotentRequest(router, "/register", "key-1", `{}`)
+	w := idempotentRequest(router, "/register", "key-1", `{}`)
+
+	if calls.Load() != 2 || w.Header().Get(IdempotentReplayHeader) != "" {
+		t.Errorf("handler ran %d times, want a failed request to run again", calls.Load())
+	}
+}
+
+func TestIdempotencyWithoutKeyOrStore(t *testing.T) {
+	var calls atomic.Int32
+	router := newIdempotentRouter(idempotency.NewMemoryStore(), &calls, http.StatusCreated)
+	idempotentRequest(router, "/register", "", `{}`)
+	idempotentRequest(router, "/register", "", `{}`)
+	if calls.Load() != 2 {
+		t.Errorf("without a key the handler ran %d times, want 2", calls.Load())
+	}
+
+	calls.Store(0)
+	router = newIdempotentRouter(failingStore{}, &calls, http.StatusCreated)
+	if w := idempotentRequest(router, "/register", "key-1", `{}`); w.Code != http.StatusCreated || calls.Load() != 1 {
+		t.Errorf("store outage: got %d after %d calls, want the request to run", w.Code, calls.Load())
+	}
+}
+
+func TestIdempotencyRejectsOverlongKey(t *testing.T) {
+	var calls atomic.Int32
+	router := newIdempotentRouter(idempotency.NewMemoryStore(), &calls, http.StatusCreated)
+
+	w := idempotentRequest(router, "/register", strings.Repeat("k", 256), `{}`)
+	if w.Code != http.StatusBadRequest || calls.Load() != 0 {
+		t.Errorf("got %d after %d calls, want 400 without running the handler", w.Code, calls.Load())
+	}
+}
+
+func TestIdempotencyRejectsOversizedBody(t *testing.T) {
+	var calls atomic.Int32
+	router := newIdempotentRouter(idempotency.NewMemoryStore(), &calls, http.StatusCreated)
+
+	body := `{"padding":"` + strings.Repeat("x", maxBufferedBodyBytes) + `"}`
+	w := idempotentRequest(router, "/register", "key-1", body)
+	if w.Code != http.StatusRequestEntityTooLarge || calls.Load() != 0 {
+		t.Errorf("got %d after %d calls, want 413 without running the handler", w.Code, calls.Load())
+	}
+
+	// Without the header the body is not buffered here
+	if w := idempotentRequest(router, "/register", "", body); w.Code != http.StatusCreated {
+		t.Errorf("without a key: got %d, want the request to run", w.Code)
+	}
+}
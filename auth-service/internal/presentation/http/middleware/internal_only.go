@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"net"
+	"net/http"
+
+	"auth-service/internal/application/dto"
+
+	"github.com/gin-gonic/gin"
+)
+
+// InternalOnly restricts a route to callers on loopback or private networks.
+// It uses the direct peer address rather than X-Forwarded-For so the check
+// cannot be bypassed with a spoofed header.
+func InternalOnly() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ip := net.ParseIP(c.RemoteIP())
+		if ip == nil || !(ip.IsLoopback() || ip.IsPrivate()) {
+			c.JSON(http.StatusForbidden, dto.ErrorResponse{
+				Error:   "forbidden",
+				Message: "This endpoint is only available to internal callers",
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
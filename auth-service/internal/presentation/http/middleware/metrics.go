@@ -0,0 +1,32 @@
+package middleware
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HTTPMetrics records handled requests
+type HTTPMetrics interface {
+	ObserveHTTPRequest(method, route string, status int, duration time.Duration)
+}
+
+// unmatchedRoute labels requests that matched no route, so arbitrary paths
+// cannot create new metric series
+const unmatchedRoute = "unmatched"
+
+// Metrics records method, route pattern, status and latency of every request.
+// The route pattern is used instead of the raw path so IDs in URLs never
+// become label values.
+func Metrics(metrics HTTPMetrics) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = unmatchedRoute
+		}
+		metrics.ObserveHTTPRequest(c.Request.Method, route, c.Writer.Status(), time.Since(start))
+	}
+}
@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type observedRequest struct {
+	method, route string
+	status        int
+}
+
+type recordingMetrics struct {
+	requests []observedRequest
+}
+
+func (m *recordingMetrics) ObserveHTTPRequest(method, route string, status int, duration time.Duration) {
+	m.requests = append(m.requests, observedRequest{method, route, status})
+}
+
+func TestMetricsUsesRoutePattern(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	metrics := &recordingMetrics{}
+	router := gin.New()
+	router.Use(Metrics(metrics))
+	router.POST("/users/:id/approve", func(c *gin.Context) { c.Status(http.StatusConflict) })
+
+	for _, path := range []string{"/users/123/approve", "/does-not-exist"} {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, path, nil))
+	}
+
+	want := []observedRequest{
+		{"POST", "/users/:id/approve", http.StatusConflict},
+		{"POST", unmatchedRoute, http.StatusNotFound},
+	}
+	if len(metrics.requests) != len(want) {
+		t.Fatalf("requests = %+v", metrics.requests)
+	}
+	for i := range want {
+		if metrics.requests[i] != want[i] {
+			t.Errorf("request %d = %+v, want %+v", i, metrics.requests[i], want[i])
+		}
+	}
+}
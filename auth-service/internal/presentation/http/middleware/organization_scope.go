@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"net/http"
+
+	"auth-service/internal/application/dto"
+	"auth-service/internal/presentation/http/authctx"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// OrganizationScope rejects requests to routes with an :orgID path parameter
+// unless it is the organization of the token. Routes without the parameter
+// pass through. It must run after AuthMiddleware, which puts the "org_id"
+// claim in the authctx.
+func OrganizationScope() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		param := c.Param("orgID")
+		if param == "" {
+			c.Next()
+			return
+		}
+
+		requested, err := uuid.Parse(param)
+		auth, ok := authctx.From(c)
+		if err != nil || !ok || auth.OrgID == uuid.Nil || requested != auth.OrgID {
+			c.JSON(http.StatusForbidden, dto.ErrorResponse{
+				Error:   "organization_mismatch",
+				Message: "The token belongs to another organization",
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,51 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"auth-service/internal/presentation/http/authctx"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func TestOrganizationScope(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	own := uuid.New()
+
+	tests := []struct {
+		name  string
+		path  string
+		orgID uuid.UUID
+		want  int
+	}{
+		{"own organization", "/orgs/" + own.String() + "/users", own, http.StatusOK},
+		{"own organization, other case", "/orgs/" + strings.ToUpper(own.String()) + "/users", own, http.StatusOK},
+		{"other organization", "/orgs/" + uuid.NewString() + "/users", own, http.StatusForbidden},
+		{"token without organization", "/orgs/" + own.String() + "/users", uuid.Nil, http.StatusForbidden},
+		{"invalid organization ID", "/orgs/acme/users", own, http.StatusForbidden},
+		{"route without organization", "/me", own, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.Use(func(c *gin.Context) {
+				authctx.Set(c, &authctx.AuthContext{UserID: uuid.New(), OrgID: tt.orgID})
+			}, OrganizationScope())
+			ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+			router.GET("/orgs/:orgID/users", ok)
+			router.GET("/me", ok)
+
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d", rec.Code, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,109 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"auth-service/internal/application/dto"
+	"auth-service/internal/presentation/http/authctx"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxBufferedBodyBytes caps how much of a request body middleware buffers
+// to read a JSON field
+const maxBufferedBodyBytes = 64 << 10
+
+// RateLimiter counts requests per key
+type RateLimiter interface {
+	// Allow records a request for key and reports whether it is within limit
+	// requests per window. When it is not, retryAfter says how long the
+	// client should wait.
+	Allow(ctx context.Context, key string, limit int, window time.Duration) (allowed bool, retryAfter time.Duration, err error)
+}
+
+// KeyFunc derives the rate limit key of a request
+type KeyFunc func(c *gin.Context) string
+
+// KeyByIP limits each client IP separately. The router must only trust
+// X-Forwarded-For from known proxies (SetTrustedProxies), or a client picks a
+// fresh key per request.
+func KeyByIP(c *gin.Context) string {
+	return "ip:" + c.ClientIP()
+}
+
+// KeyByUser limits each authenticated user separately, wherever they sign
+// in from; requests that did not pass AuthMiddleware fall back to KeyByIP
+func KeyByUser(c *gin.Context) string {
+	if auth, ok := authctx.From(c); ok {
+		return "user:" + auth.UserID.String()
+	}
+	return KeyByIP(c)
+}
+
+// KeyByIPAndField limits each combination of client IP and the given JSON
+// body field (e.g. the submitted email). Attempts against one account are
+// capped without letting a single client exhaust the budget of everyone
+// behind the same NAT or proxy.
+func KeyByIPAndField(field string) KeyFunc {
+	return func(c *gin.Context) string {
+		value, err := jsonBodyField(c, field)
+		if err != nil {
+			return KeyByIP(c)
+		}
+		return KeyByIP(c) + "|" + field + ":" + strings.ToLower(strings.TrimSpace(value))
+	}
+}
+
+// jsonBodyField returns a string field of the JSON request body ("" when it
+// is missing or the body is not JSON) and restores the body for the handler.
+// Only the first maxBufferedBodyBytes are parsed; the unread rest is put back
+// behind them, so the handler still gets the whole body.
+func jsonBodyField(c *gin.Context, field string) (string, error) {
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxBufferedBodyBytes))
+	if err != nil {
+		return "", err
+	}
+	c.Request.Body = io.NopCloser(io.MultiReader(bytes.NewReader(body), c.Request.Body))
+
+	var fields map[string]any
+	_ = json.Unmarshal(body, &fields)
+	value, _ := fields[field].(string)
+	return value, nil
+}
+
+// RateLimit rejects requests with 429 Too Many Requests once the key
+// returned by keyFunc exceeds limit requests per window. Keys are scoped to
+// the route, so limits on different endpoints do not share a budget. If the
+// limiter fails the request is let through: an outage of the limiter backend
+// must not take down login.
+func RateLimit(limiter RateLimiter, keyFunc KeyFunc, limit int, window time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.FullPath() + "|" + keyFunc(c)
+		allowed, retryAfter, err := limiter.Allow(c.Request.Context(), key, limit, window)
+		if err != nil {
+			log.Printf("rate limit: %v", err)
+			c.Next()
+			return
+		}
+		if !allowed {
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+			c.JSON(http.StatusTooManyRequests, dto.ErrorResponse{
+				Error:   "rate_limited",
+				Message: "Too many requests, please try again later",
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
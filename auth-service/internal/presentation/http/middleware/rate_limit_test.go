@@ -0,0 +1,190 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"auth-service/internal/presentation/http/authctx"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// countingLimiter allows the first limit requests of each key
+type countingLimiter struct {
+	counts map[string]int
+	err    error
+}
+
+func (l *countingLimiter) Allow(ctx context.Context, key string, limit int, window time.Duration) (bool, time.Duration, error) {
+	if l.err != nil {
+		return false, 0, l.err
+	}
+	l.counts[key]++
+	return l.counts[key] <= limit, 1500 * time.Millisecond, nil
+}
+
+func newRateLimitedRouter(limiter RateLimiter, keyFunc KeyFunc) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/login", RateLimit(limiter, keyFunc, 2, time.Minute), func(c *gin.Context) {
+		body, _ := io.ReadAll(c.Request.Body)
+		c.String(http.StatusOK, string(body))
+	})
+	return router
+}
+
+func postLogin(router *gin.Engine, ip, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/login", strings.NewReader(body))
+	req.RemoteAddr = ip + ":1234"
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestRateLimitRejectsWithRetryAfter(t *testing.T) {
+	router := newRateLimitedRouter(&countingLimiter{counts: map[string]int{}}, KeyByIP)
+
+	for i := 0; i < 2; i++ {
+		if w := postLogin(router, "203.0.113.7", "{}"); w.Code != http.StatusOK {
+			t.Fatalf("request %d: status = %d", i+1, w.Code)
+		}
+	}
+	w := postLogin(router, "203.0.113.7", "{}")
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("status = %d, want 429", w.Code)
+	}
+	if got := w.Header().Get("Retry-After"); got != "2" {
+		t.Errorf("Retry-After = %q, want rounded up to 2", got)
+	}
+	if !strings.Contains(w.Body.String(), "rate_limited") {
+		t.Errorf("body = %s", w.Body)
+	}
+
+	// Another client is unaffected
+	if w := postLogin(router, "198.51.100.20", "{}"); w.Code != http.StatusOK {
+		t.Errorf("other IP status = %d", w.Code)
+	}
+}
+
+func TestRateLimitByIPAndField(t *testing.T) {
+	limiter := &countingLimiter{counts: map[string]int{}}
+	router := newRateLimitedRouter(limiter, KeyByIPAndField("email"))
+
+	for i := 0; i < 2; i++ {
+		postLogin(router, "203.0.113.7", `{"email":"jane@example.com"}`)
+	}
+	// Same account, different case: still the same key
+	if w := postLogin(router, "203.0.113.7", `{"email":" Jane@Example.com"}`); w.Code != http.StatusTooManyRequests {
+		t.Errorf("status = %d, want 429", w.Code)
+	}
+
+	// Another account behind the same IP keeps its own budget, and the
+	// handler still sees the body
+	w := postLogin(router, "203.0.113.7", `{"email":"john@example.com"}`)
+	if w.Code != http.StatusOK || w.Body.String() != `{"email":"john@example.com"}` {
+		t.Errorf("status = %d, body = %q", w.Code, w.Body)
+	}
+}
+
+func TestKeyByIPAndFieldKeepsLargeBodies(t *testing.T) {
+	router := newRateLimitedRouter(&countingLimiter{counts: map[string]int{}}, KeyByIPAndField("email"))
+
+	// Larger than the part the key function reads
+	body := `{"email":"jane@example.com","padding":"` + strings.Repeat("x", maxBufferedBodyBytes) + `"}`
+	w := postLogin(router, "203.0.113.7", body)
+	if w.Code != http.StatusOK || w.Body.String() != body {
+		t.Errorf("status = %d, handler got %d of %d bytes", w.Code, w.Body.Len(), len(body))
+	}
+}
+
+func TestKeyByIPIgnoresSpoofedForwardedFor(t *testing.T) {
+	postVia := func(router *gin.Engine, remoteIP, forwardedFor string) int {
+		req := httptest.NewRequest(http.MethodPost, "/login", strings.NewReader("{}"))
+		req.RemoteAddr = remoteIP + ":1234"
+		req.Header.Set("X-Forwarded-For", forwardedFor)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	// No trusted proxy (the TRUSTED_PROXIES default): a fresh header per
+	// request does not buy a fresh budget
+	router := newRateLimitedRouter(&countingLimiter{counts: map[string]int{}}, KeyByIP)
+	if err := router.SetTrustedProxies(nil); err != nil {
+		t.Fatal(err)
+	}
+	for i, forwardedFor := range []string{"198.51.100.1", "198.51.100.2"} {
+		if code := postVia(router, "203.0.113.7", forwardedFor); code != http.StatusOK {
+			t.Fatalf("request %d: status = %d", i+1, code)
+		}
+	}
+	if code := postVia(router, "203.0.113.7", "198.51.100.3"); code != http.StatusTooManyRequests {
+		t.Errorf("spoofed X-Forwarded-For: status = %d, want 429", code)
+	}
+
+	// Behind a trusted proxy each forwarded client keeps its own budget
+	router = newRateLimitedRouter(&countingLimiter{counts: map[string]int{}}, KeyByIP)
+	if err := router.SetTrustedProxies([]string{"10.0.0.1"}); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		postVia(router, "10.0.0.1", "198.51.100.1")
+	}
+	if code := postVia(router, "10.0.0.1", "198.51.100.2"); code != http.StatusOK {
+		t.Errorf("another client behind the proxy: status = %d, want 200", code)
+	}
+}
+
+func TestRateLimitByUser(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	limiter := &countingLimiter{counts: map[string]int{}}
+	userID := uuid.New()
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		if c.GetHeader("X-Test-User") != "" {
+			authctx.Set(c, &authctx.AuthContext{UserID: userID})
+		}
+	})
+	router.GET("/export", RateLimit(limiter, KeyByUser, 1, time.Minute), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	get := func(ip string, signedIn bool) int {
+		req := httptest.NewRequest(http.MethodGet, "/export", nil)
+		req.RemoteAddr = ip + ":1234"
+		if signedIn {
+			req.Header.Set("X-Test-User", "1")
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	if code := get("203.0.113.7", true); code != http.StatusOK {
+		t.Fatalf("first request: status = %d", code)
+	}
+	// The same user from another IP shares the budget
+	if code := get("198.51.100.1", true); code != http.StatusTooManyRequests {
+		t.Errorf("same user, other IP: status = %d, want 429", code)
+	}
+	// Requests without a user fall back to the client IP
+	if code := get("203.0.113.7", false); code != http.StatusOK {
+		t.Errorf("anonymous request: status = %d, want 200", code)
+	}
+}
+
+func TestRateLimitFailsOpen(t *testing.T) {
+	router := newRateLimitedRouter(&countingLimiter{err: errors.New("redis down")}, KeyByIP)
+
+	for i := 0; i < 5; i++ {
+		if w := postLogin(router, "203.0.113.7", "{}"); w.Code != http.StatusOK {
+			t.Fatalf("request %d: status = %d", i+1, w.Code)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"auth-service/internal/application/usecase"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// RequestIDHeader carries the request ID in both directions
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength bounds IDs accepted from clients (audit_logs.request_id)
+const maxRequestIDLength = 64
+
+// RequestID takes the request ID from the X-Request-ID header, or generates
+// one if it is missing or malformed, and echoes it in the response. The ID is
+// stored as "requestID" in the gin context and in the request context, where
+// use cases pick it up for logs and audit events.
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(RequestIDHeader)
+		if !validRequestID(requestID) {
+			requestID = uuid.New().String()
+		}
+
+		c.Set("requestID", requestID)
+		c.Request = c.Request.WithContext(usecase.ContextWithRequestID(c.Request.Context(), requestID))
+		c.Header(RequestIDHeader, requestID)
+		c.Next()
+	}
+}
+
+// validRequestID accepts short IDs of letters, digits and "-_.:" so a client
+// cannot inject arbitrary text into logs
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, r := range id {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case r == '-', r == '_', r == '.', r == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"auth-service/internal/application/usecase"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func TestRequestID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(RequestID())
+	var fromGin, fromContext string
+	router.GET("/", func(c *gin.Context) {
+		fromGin = c.GetString("requestID")
+		fromContext = usecase.RequestIDFromContext(c.Request.Context())
+	})
+
+	tests := []struct {
+		name     string
+		header   string
+		generate bool
+	}{
+		{"propagated", "abc-123.def:4_5", false},
+		{"missing", "", true},
+		{"too long", strings.Repeat("a", maxRequestIDLength+1), true},
+		{"log injection", "abc\nlevel=ERROR", true},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		if tt.header != "" {
+			req.Header.Set(RequestIDHeader, tt.header)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		got := w.Header().Get(RequestIDHeader)
+		if tt.generate {
+			if _, err := uuid.Parse(got); err != nil {
+				t.Errorf("%s: response ID = %q, want a generated UUID", tt.name, got)
+			}
+		} else if got != tt.header {
+			t.Errorf("%s: response ID = %q, want %q", tt.name, got, tt.header)
+		}
+		if fromGin != got || fromContext != got {
+			t.Errorf("%s: gin context %q, request context %q, want %q", tt.name, fromGin, fromContext, got)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"auth-service/internal/presentation/http/authctx"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func TestRequestLogger(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var buf bytes.Buffer
+	userID := uuid.New()
+	router := gin.New()
+	router.Use(RequestID(), RequestLogger(slog.New(slog.NewJSONHandler(&buf, nil))))
+	router.GET("/me", func(c *gin.Context) {
+		authctx.Set(c, &authctx.AuthContext{UserID: userID})
+		c.Status(http.StatusOK)
+	})
+	router.GET("/boom", func(c *gin.Context) { c.Status(http.StatusInternalServerError) })
+
+	req := httptest.NewRequest(http.MethodGet, "/me?token=secret", nil)
+	req.Header.Set(RequestIDHeader, "req-1")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/boom", nil))
+
+	lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+	if len(lines) != 2 {
+		t.Fatalf("log = %s", buf.String())
+	}
+	var first, second map[string]interface{}
+	if err := json.Unmarshal(lines[0], &first); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(lines[1], &second); err != nil {
+		t.Fatal(err)
+	}
+
+	if first["level"] != "INFO" || first["method"] != "GET" || first["path"] != "/me" ||
+		first["status"] != float64(200) || first["request_id"] != "req-1" || first["user_id"] != userID.String() {
+		t.Errorf("first line = %v", first)
+	}
+	if _, ok := first["latency_ms"]; !ok {
+		t.Error("latency missing")
+	}
+	if second["level"] != "ERROR" || second["status"] != float64(500) || second["user_id"] != nil {
+		t.Errorf("second line = %v", second)
+	}
+}
@@ -0,0 +1,134 @@
+package middleware
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"auth-service/internal/application/dto"
+	"auth-service/pkg/security"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Headers used for HMAC request signing
+const (
+	SignatureHeader          = "X-Signature"
+	SignatureTimestampHeader = "X-Signature-Timestamp"
+)
+
+// maxSignedBodyBytes caps how much of a signed request body is buffered
+const maxSignedBodyBytes = 1 << 20
+
+// ReplayCache remembers signatures that were already accepted
+type ReplayCache interface {
+	// Seen records signature until expiresAt and reports whether it was
+	// already recorded
+	Seen(signature string, expiresAt time.Time) bool
+}
+
+// MemoryReplayCache is an in-process ReplayCache. With several replicas a
+// replayed request may still reach a replica that has not seen it; the
+// timestamp window bounds that exposure.
+type MemoryReplayCache struct {
+	mu      sync.Mutex
+	entries map[string]time.Time
+}
+
+// NewMemoryReplayCache creates a new in-memory replay cache
+func NewMemoryReplayCache() *MemoryReplayCache {
+	return &MemoryReplayCache{entries: map[string]time.Time{}}
+}
+
+func (c *MemoryReplayCache) Seen(signature string, expiresAt time.Time) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	for sig, exp := range c.entries {
+		if now.After(exp) {
+			delete(c.entries, sig)
+		}
+	}
+
+	if _, ok := c.entries[signature]; ok {
+		return true
+	}
+	c.entries[signature] = expiresAt
+	return false
+}
+
+// RequestSignatureConfig configures RequestSignature
+type RequestSignatureConfig struct {
+	Secret []byte
+	// MaxSkew is how far the signature timestamp may be from the server clock
+	MaxSkew time.Duration
+	// Paths lists the route patterns (as registered, e.g. "/health/detailed")
+	// that require a signature. Other routes pass through unchanged.
+	Paths  []string
+	Replay ReplayCache
+}
+
+// RequestSignature verifies HMAC signatures on the configured internal
+// routes. Callers sign method, path, timestamp and body with the shared
+// secret (see security.SignRequest). It is independent of user auth and is a
+// no-op when no secret is configured.
+func RequestSignature(cfg RequestSignatureConfig) gin.HandlerFunc {
+	paths := make(map[string]bool, len(cfg.Paths))
+	for _, p := range cfg.Paths {
+		paths[p] = true
+	}
+
+	return func(c *gin.Context) {
+		if len(cfg.Secret) == 0 || !paths[c.FullPath()] {
+			c.Next()
+			return
+		}
+
+		signature := c.GetHeader(SignatureHeader)
+		timestamp, err := strconv.ParseInt(c.GetHeader(SignatureTimestampHeader), 10, 64)
+		if signature == "" || err != nil {
+			rejectSignature(c, "Request signature headers are missing or malformed")
+			return
+		}
+
+		// Reject stale or future-dated requests
+		signedAt := time.Unix(timestamp, 0)
+		if d := time.Since(signedAt); d > cfg.MaxSkew || d < -cfg.MaxSkew {
+			rejectSignature(c, "Request signature has expired")
+			return
+		}
+
+		body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxSignedBodyBytes))
+		if err != nil {
+			rejectSignature(c, "Failed to read request body")
+			return
+		}
+		// Restore the body for the handler
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		if !security.VerifyRequestSignature(cfg.Secret, c.Request.Method, c.Request.URL.RequestURI(), body, timestamp, signature) {
+			rejectSignature(c, "Request signature is invalid")
+			return
+		}
+
+		// A valid signature may only be used once within its validity window
+		if cfg.Replay != nil && cfg.Replay.Seen(signature, signedAt.Add(cfg.MaxSkew)) {
+			rejectSignature(c, "Request signature has already been used")
+			return
+		}
+
+		c.Next()
+	}
+}
+
+func rejectSignature(c *gin.Context, message string) {
+	c.JSON(http.StatusUnauthorized, dto.ErrorResponse{
+		Error:   "invalid_signature",
+		Message: message,
+	})
+	c.Abort()
+}
@@ -0,0 +1,114 @@
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"auth-service/pkg/security"
+
+	"github.com/gin-gonic/gin"
+)
+
+var testSigningSecret = []byte("internal-secret")
+
+func newSignedRouter(paths ...string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(RequestSignature(RequestSignatureConfig{
+		Secret:  testSigningSecret,
+		MaxSkew: time.Minute,
+		Paths:   paths,
+		Replay:  NewMemoryReplayCache(),
+	}))
+	echo := func(c *gin.Context) {
+		body, _ := io.ReadAll(c.Request.Body)
+		c.String(http.StatusOK, string(body))
+	}
+	router.POST("/internal/lookup", echo)
+	router.POST("/public", echo)
+	return router
+}
+
+func signedRequest(path, body string, signedAt time.Time) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	ts := signedAt.Unix()
+	req.Header.Set(SignatureTimestampHeader, strconv.FormatInt(ts, 10))
+	req.Header.Set(SignatureHeader, security.SignRequest(testSigningSecret, http.MethodPost, path, []byte(body), ts))
+	return req
+}
+
+func TestRequestSignatureAcceptsValidSignature(t *testing.T) {
+	router := newSignedRouter("/internal/lookup")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, signedRequest("/internal/lookup", `{"id":1}`, time.Now()))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", w.Code, w.Body)
+	}
+	if w.Body.String() != `{"id":1}` {
+		t.Errorf("handler saw body %q, want it restored", w.Body)
+	}
+}
+
+func TestRequestSignatureRejections(t *testing.T) {
+	tests := []struct {
+		name string
+		req  func() *http.Request
+	}{
+		{"missing headers", func() *http.Request {
+			return httptest.NewRequest(http.MethodPost, "/internal/lookup", strings.NewReader("{}"))
+		}},
+		{"tampered body", func() *http.Request {
+			req := signedRequest("/internal/lookup", `{"id":1}`, time.Now())
+			req.Body = io.NopCloser(strings.NewReader(`{"id":2}`))
+			return req
+		}},
+		{"stale timestamp", func() *http.Request {
+			return signedRequest("/internal/lookup", "{}", time.Now().Add(-2*time.Minute))
+		}},
+		{"future timestamp", func() *http.Request {
+			return signedRequest("/internal/lookup", "{}", time.Now().Add(2*time.Minute))
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			newSignedRouter("/internal/lookup").ServeHTTP(w, tt.req())
+			if w.Code != http.StatusUnauthorized {
+				t.Errorf("status = %d, want 401", w.Code)
+			}
+		})
+	}
+}
+
+func TestRequestSignatureRejectsReplay(t *testing.T) {
+	router := newSignedRouter("/internal/lookup")
+	signedAt := time.Now()
+
+	first := httptest.NewRecorder()
+	router.ServeHTTP(first, signedRequest("/internal/lookup", "{}", signedAt))
+	replay := httptest.NewRecorder()
+	router.ServeHTTP(replay, signedRequest("/internal/lookup", "{}", signedAt))
+
+	if first.Code != http.StatusOK || replay.Code != http.StatusUnauthorized {
+		t.Errorf("statuses = %d, %d; want 200, 401", first.Code, replay.Code)
+	}
+}
+
+func TestRequestSignatureOnlyOnConfiguredPaths(t *testing.T) {
+	router := newSignedRouter("/internal/lookup")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/public", strings.NewReader("{}")))
+
+	if w.Code != http.StatusOK {
+		t.Errorf("unsigned request to unlisted path: status = %d, want 200", w.Code)
+	}
+}
@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"net/http"
+
+	"auth-service/internal/application/dto"
+	"auth-service/internal/presentation/http/authctx"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequirePasswordChanged rejects requests of users who signed in with a
+// temporary password from an admin and have not changed it yet, except on
+// the allowed route patterns (e.g. "/api/auth/password"). It must run after
+// AuthMiddleware, which puts the token's pwd_chg claim in the authctx.
+// Requests without an authctx pass; the routes' own checks reject them.
+func RequirePasswordChanged(allowed ...string) gin.HandlerFunc {
+	routes := make(map[string]bool, len(allowed))
+	for _, route := range allowed {
+		routes[route] = true
+	}
+
+	return func(c *gin.Context) {
+		if auth, ok := authctx.From(c); ok && auth.MustChangePassword && !routes[c.FullPath()] {
+			c.JSON(http.StatusForbidden, dto.ErrorResponse{
+				Error:   "password_change_required",
+				Message: "Change your temporary password before using this endpoint",
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
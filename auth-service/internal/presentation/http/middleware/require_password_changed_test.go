@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"auth-service/pkg/security"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func TestRequirePasswordChanged(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	jwtService := security.NewJWTService("test-secret", time.Minute, time.Hour)
+
+	router := gin.New()
+	protected := router.Group("", AuthMiddleware(jwtService, nil, nil), RequirePasswordChanged("/auth/password"))
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	protected.PUT("/auth/password", ok)
+	protected.GET("/auth/sessions", ok)
+
+	tests := []struct {
+		name       string
+		mustChange bool
+		method     string
+		path       string
+		want       int
+	}{
+		{"temporary password, other route", true, http.MethodGet, "/auth/sessions", http.StatusForbidden},
+		{"temporary password, password change", true, http.MethodPut, "/auth/password", http.StatusOK},
+		{"own password", false, http.MethodGet, "/auth/sessions", http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, err := jwtService.GenerateAccessToken(uuid.New(), "jane@example.com", "jane", "user", nil, uuid.New(), uuid.Nil, 0, tt.mustChange)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			req.Header.Set("Authorization", "Bearer "+token)
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d: %s", rec.Code, tt.want, rec.Body)
+			}
+		})
+	}
+}
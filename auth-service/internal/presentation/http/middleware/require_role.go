@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"net/http"
+
+	"auth-service/internal/application/dto"
+	"auth-service/internal/presentation/http/authctx"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequireRole rejects requests whose token does not carry the given role. It
+// must run after AuthMiddleware, which puts the "role" claim in the authctx.
+func RequireRole(role string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if auth, ok := authctx.From(c); !ok || auth.Role != role {
+			c.JSON(http.StatusForbidden, dto.ErrorResponse{
+				Error:   "forbidden",
+				Message: "This endpoint requires the " + role + " role",
+			})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"net/http"
+
+	"auth-service/internal/application/dto"
+	"auth-service/internal/presentation/http/authctx"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequireScope rejects requests whose credentials were not granted scope. It
+// must run after AuthMiddleware, which puts the token's scopes in the
+// authctx, or APIKeyMiddleware, which puts the key's scopes in the context
+// as "scopes".
+func RequireScope(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		scopes := c.GetStringSlice("scopes")
+		if auth, ok := authctx.From(c); ok {
+			scopes = auth.Scopes
+		}
+		for _, granted := range scopes {
+			if granted == scope {
+				c.Next()
+				return
+			}
+		}
+
+		c.JSON(http.StatusForbidden, dto.ErrorResponse{
+			Error:   "insufficient_scope",
+			Message: "This endpoint requires the " + scope + " scope",
+		})
+		c.Abort()
+	}
+}
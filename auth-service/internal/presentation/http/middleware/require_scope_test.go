@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"auth-service/internal/domain"
+	"auth-service/pkg/security"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+func TestRequireScopeWithAccessToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	jwtService := security.NewJWTService("test-secret", time.Minute, time.Hour)
+
+	router := gin.New()
+	router.GET("/admin/users", AuthMiddleware(jwtService, nil, nil), RequireScope(domain.ScopeUsersRead), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	tests := []struct {
+		name string
+		role string
+		want int
+	}{
+		{"scope granted", domain.RoleAdmin, http.StatusOK},
+		{"scope missing", domain.RoleUser, http.StatusForbidden},
+		{"no scopes", "", http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token, err := jwtService.GenerateAccessToken(uuid.New(), "jane@example.com", "jane", tt.role, domain.ScopesForRole(tt.role), uuid.New(), uuid.Nil, 0, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "/admin/users", nil)
+			req.Header.Set("Authorization", "Bearer "+token)
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d", rec.Code, tt.want)
+			}
+		})
+	}
+}
+
+func TestRequireScopeWithoutCredentials(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/", RequireScope(domain.ScopeTokenIntrospect), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("status = %d, want 403", rec.Code)
+	}
+}
@@ -0,0 +1,49 @@
+package rpc
+
+import (
+	"context"
+
+	"auth-service/internal/domain"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// APIKeyMetadata is the metadata key carrying the caller's API key; the gRPC
+// counterpart of the X-API-Key header
+const APIKeyMetadata = "x-api-key"
+
+// APIKeyAuthenticator resolves a presented API key. It returns an error for
+// unknown, revoked and expired keys.
+type APIKeyAuthenticator interface {
+	AuthenticateAPIKey(ctx context.Context, key string) (*domain.APIKey, error)
+}
+
+// APIKeyInterceptor authenticates every call by the x-api-key metadata and
+// requires the key to have the scope scopes maps the method to (keyed by
+// full method name). A method missing from scopes is denied.
+func APIKeyInterceptor(keys APIKeyAuthenticator, scopes map[string]string) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		scope, ok := scopes[info.FullMethod]
+		if !ok {
+			return nil, status.Errorf(codes.PermissionDenied, "no API key scope allows %s", info.FullMethod)
+		}
+
+		md, _ := metadata.FromIncomingContext(ctx)
+		presented := md.Get(APIKeyMetadata)
+		if len(presented) == 0 || presented[0] == "" {
+			return nil, status.Error(codes.Unauthenticated, "x-api-key metadata is required")
+		}
+
+		key, err := keys.AuthenticateAPIKey(ctx, presented[0])
+		if err != nil {
+			return nil, status.Error(codes.Unauthenticated, "invalid, revoked or expired API key")
+		}
+		if !key.HasScope(scope) {
+			return nil, status.Errorf(codes.PermissionDenied, "API key lacks the %s scope", scope)
+		}
+		return handler(ctx, req)
+	}
+}
@@ -0,0 +1,72 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"net"
+
+	"auth-service/internal/domain"
+	"auth-service/pkg/authpb"
+
+	"google.golang.org/grpc"
+)
+
+// Server serves the gRPC API on its own port. It implements server.Service
+// so it starts and stops with the HTTP server.
+type Server struct {
+	addr       string
+	grpcServer *grpc.Server
+}
+
+// methodScopes is the API key scope each RPC needs
+var methodScopes = map[string]string{
+	authpb.TokenService_ValidateToken_FullMethodName: domain.ScopeTokenIntrospect,
+	authpb.TokenService_Revoke_FullMethodName:        domain.ScopeTokenRevoke,
+}
+
+// NewServer registers the token service on a gRPC server that will listen on
+// addr. Every call needs an API key with the method's scope (methodScopes).
+func NewServer(addr string, tokens TokenUseCase, keys APIKeyAuthenticator) *Server {
+	grpcServer := grpc.NewServer(
+		grpc.UnaryInterceptor(APIKeyInterceptor(keys, methodScopes)),
+	)
+	authpb.RegisterTokenServiceServer(grpcServer, NewTokenServer(tokens))
+	return &Server{addr: addr, grpcServer: grpcServer}
+}
+
+// Serve listens on the configured address and blocks until Shutdown
+func (s *Server) Serve() error {
+	ln, err := net.Listen("tcp", s.addr)
+	if err != nil {
+		return err
+	}
+	return s.ServeListener(ln)
+}
+
+// ServeListener serves on an existing listener (tests use an in-memory one).
+// It returns nil once Shutdown was called, even if that happened first.
+func (s *Server) ServeListener(ln net.Listener) error {
+	if err := s.grpcServer.Serve(ln); !errors.Is(err, grpc.ErrServerStopped) {
+		return err
+	}
+	return nil
+}
+
+// Shutdown stops accepting calls and waits for in-flight ones; when ctx is
+// done first the remaining calls are cancelled
+func (s *Server) Shutdown(ctx context.Context) error {
+	stopped := make(chan struct{})
+	go func() {
+		s.grpcServer.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+		return nil
+	case <-ctx.Done():
+		s.grpcServer.Stop()
+		<-stopped
+		return ctx.Err()
+	}
+}
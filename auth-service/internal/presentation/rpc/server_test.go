@@ -0,0 +1,171 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"auth-service/internal/application/dto"
+	"auth-service/internal/application/usecase"
+	"auth-service/internal/domain"
+	"auth-service/pkg/authpb"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+type stubAPIKeys map[string]*domain.APIKey
+
+func (s stubAPIKeys) AuthenticateAPIKey(ctx context.Context, key string) (*domain.APIKey, error) {
+	if k, ok := s[key]; ok {
+		return k, nil
+	}
+	return nil, errors.New("invalid API key")
+}
+
+// stubTokens treats "good" as the only active token
+type stubTokens struct {
+	revoked []string
+}
+
+func (s *stubTokens) IntrospectToken(ctx context.Context, token string) (*dto.IntrospectionResponse, error) {
+	if token != "good" {
+		return &dto.IntrospectionResponse{Active: false}, nil
+	}
+	return &dto.IntrospectionResponse{Active: true, Sub: "user-1", Email: "jane@example.com", Username: "jane", Scope: "read write", Exp: 1700000000}, nil
+}
+
+func (s *stubTokens) RevokeAccessToken(ctx context.Context, token string) error {
+	if token != "good" {
+		return usecase.ErrInvalidToken
+	}
+	s.revoked = append(s.revoked, token)
+	return nil
+}
+
+// startServer serves over an in-memory listener and returns a connected client
+func startServer(t *testing.T, tokens TokenUseCase) authpb.TokenServiceClient {
+	t.Helper()
+	keys := stubAPIKeys{
+		"gateway-key":    {Scopes: []string{domain.ScopeTokenIntrospect, domain.ScopeTokenRevoke}},
+		"introspect-key": {Scopes: []string{domain.ScopeTokenIntrospect}},
+		"other-key":      {Scopes: []string{}},
+	}
+	srv := NewServer("", tokens, keys)
+	ln := bufconn.Listen(1 << 20)
+	go srv.ServeListener(ln)
+	t.Cleanup(func() { srv.Shutdown(context.Background()) })
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return ln.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return authpb.NewTokenServiceClient(conn)
+}
+
+func withKey(key string) context.Context {
+	return metadata.AppendToOutgoingContext(context.Background(), APIKeyMetadata, key)
+}
+
+func TestValidateToken(t *testing.T) {
+	client := startServer(t, &stubTokens{})
+
+	resp, err := client.ValidateToken(withKey("gateway-key"), &authpb.ValidateTokenRequest{Token: "good"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !resp.Valid || resp.UserId != "user-1" || resp.Username != "jane" || resp.ExpiresAt != 1700000000 ||
+		len(resp.Scopes) != 2 || resp.Scopes[1] != "write" {
+		t.Errorf("active token: %+v", resp)
+	}
+
+	resp, err = client.ValidateToken(withKey("gateway-key"), &authpb.ValidateTokenRequest{Token: "forged"})
+	if err != nil || resp.Valid || resp.UserId != "" {
+		t.Errorf("inactive token = %+v, %v; want valid=false", resp, err)
+	}
+}
+
+func TestCallsRequireAnAPIKeyWithScope(t *testing.T) {
+	client := startServer(t, &stubTokens{})
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want codes.Code
+	}{
+		{"missing key", context.Background(), codes.Unauthenticated},
+		{"unknown key", withKey("nope"), codes.Unauthenticated},
+		{"key without scope", withKey("other-key"), codes.PermissionDenied},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := client.ValidateToken(tt.ctx, &authpb.ValidateTokenRequest{Token: "good"})
+			if got := status.Code(err); got != tt.want {
+				t.Errorf("code = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRevoke(t *testing.T) {
+	tokens := &stubTokens{}
+	client := startServer(t, tokens)
+
+	if _, err := client.Revoke(withKey("gateway-key"), &authpb.RevokeRequest{Token: "good"}); err != nil {
+		t.Fatal(err)
+	}
+	if len(tokens.revoked) != 1 {
+		t.Errorf("revoked = %q, want the token", tokens.revoked)
+	}
+
+	for _, token := range []string{"", "forged"} {
+		_, err := client.Revoke(withKey("gateway-key"), &authpb.RevokeRequest{Token: token})
+		if got := status.Code(err); got != codes.InvalidArgument {
+			t.Errorf("Revoke(%q) code = %s, want InvalidArgument", token, got)
+		}
+	}
+}
+
+func TestRevokeRequiresRevokeScope(t *testing.T) {
+	tokens := &stubTokens{}
+	client := startServer(t, tokens)
+
+	// A key that may only validate tokens must not log users out
+	_, err := client.Revoke(withKey("introspect-key"), &authpb.RevokeRequest{Token: "good"})
+	if got := status.Code(err); got != codes.PermissionDenied {
+		t.Errorf("code = %s, want PermissionDenied", got)
+	}
+	if len(tokens.revoked) != 0 {
+		t.Errorf("revoked = %q, want none", tokens.revoked)
+	}
+
+	if _, err := client.ValidateToken(withKey("introspect-key"), &authpb.ValidateTokenRequest{Token: "good"}); err != nil {
+		t.Errorf("ValidateToken with the introspect scope = %v", err)
+	}
+}
+
+func TestServeReturnsNilAfterShutdown(t *testing.T) {
+	srv := NewServer("", &stubTokens{}, stubAPIKeys{})
+	ln := bufconn.Listen(1 << 20)
+	served := make(chan error, 1)
+	go func() { served <- srv.ServeListener(ln) }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	// Shutdown may win the race with Serve; both orders must end cleanly
+	if err := srv.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown with no calls in flight = %v", err)
+	}
+	if err := <-served; err != nil {
+		t.Errorf("Serve after Shutdown = %v, want nil", err)
+	}
+}
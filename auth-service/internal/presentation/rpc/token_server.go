@@ -0,0 +1,81 @@
+// Package rpc serves the gRPC API used by other services in the mesh
+package rpc
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"auth-service/internal/application/dto"
+	"auth-service/internal/application/usecase"
+	"auth-service/pkg/authpb"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// TokenUseCase is the part of the auth use case the token service needs
+type TokenUseCase interface {
+	IntrospectToken(ctx context.Context, token string) (*dto.IntrospectionResponse, error)
+	RevokeAccessToken(ctx context.Context, token string) error
+}
+
+// TokenServer implements authpb.TokenServiceServer
+type TokenServer struct {
+	authpb.UnimplementedTokenServiceServer
+	tokens TokenUseCase
+}
+
+// NewTokenServer creates the token service
+func NewTokenServer(tokens TokenUseCase) *TokenServer {
+	return &TokenServer{tokens: tokens}
+}
+
+// ValidateToken reports whether an access token is active, like
+// POST /api/auth/introspect
+func (s *TokenServer) ValidateToken(ctx context.Context, req *authpb.ValidateTokenRequest) (*authpb.ValidateTokenResponse, error) {
+	if req.GetToken() == "" {
+		return nil, status.Error(codes.InvalidArgument, "token is required")
+	}
+
+	resp, err := s.tokens.IntrospectToken(ctx, req.GetToken())
+	if err != nil {
+		return nil, statusFromError(err)
+	}
+	if !resp.Active {
+		return &authpb.ValidateTokenResponse{Valid: false}, nil
+	}
+	return &authpb.ValidateTokenResponse{
+		Valid:     true,
+		UserId:    resp.Sub,
+		Email:     resp.Email,
+		Username:  resp.Username,
+		Scopes:    strings.Fields(resp.Scope),
+		ExpiresAt: resp.Exp,
+	}, nil
+}
+
+// Revoke blacklists an access token until it expires
+func (s *TokenServer) Revoke(ctx context.Context, req *authpb.RevokeRequest) (*authpb.RevokeResponse, error) {
+	if req.GetToken() == "" {
+		return nil, status.Error(codes.InvalidArgument, "token is required")
+	}
+
+	if err := s.tokens.RevokeAccessToken(ctx, req.GetToken()); err != nil {
+		return nil, statusFromError(err)
+	}
+	return &authpb.RevokeResponse{}, nil
+}
+
+// statusFromError maps use case errors to gRPC codes; anything unknown is
+// Internal and its message is not sent to the caller
+func statusFromError(err error) error {
+	switch {
+	case errors.Is(err, usecase.ErrInvalidToken):
+		return status.Error(codes.InvalidArgument, "invalid or expired token")
+	case errors.Is(err, usecase.ErrRequestTimeout):
+		return status.Error(codes.DeadlineExceeded, "request timed out")
+	default:
+		return status.Error(codes.Internal, "internal error")
+	}
+}
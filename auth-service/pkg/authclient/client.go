@@ -0,0 +1,72 @@
+// Package authclient is a Go client for the auth service's gRPC token API.
+// Other services use it to validate access tokens without knowing the
+// signing keys:
+//
+//	client, err := authclient.Dial("auth-service:5005", os.Getenv("AUTH_API_KEY"))
+//	...
+//	defer client.Close()
+//	token, err := client.ValidateToken(ctx, bearer)
+//	if err != nil || !token.Valid { /* 401 */ }
+package authclient
+
+import (
+	"context"
+	"errors"
+
+	"auth-service/pkg/authpb"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// ErrInvalidToken is returned by Revoke for a token that is malformed,
+// expired or not issued by the auth service
+var ErrInvalidToken = errors.New("invalid or expired token")
+
+// Client calls the TokenService with an API key that has the
+// token:introspect scope (and token:revoke to call Revoke)
+type Client struct {
+	conn   *grpc.ClientConn
+	tokens authpb.TokenServiceClient
+	apiKey string
+}
+
+// Dial creates a client for the auth service at target. The connection is
+// plaintext unless opts carry other transport credentials (e.g.
+// grpc.WithTransportCredentials(credentials.NewTLS(...))); it is opened
+// lazily on the first call.
+func Dial(target, apiKey string, opts ...grpc.DialOption) (*Client, error) {
+	opts = append([]grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}, opts...)
+	conn, err := grpc.NewClient(target, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &Client{conn: conn, tokens: authpb.NewTokenServiceClient(conn), apiKey: apiKey}, nil
+}
+
+// ValidateToken checks an access token. An inactive token (expired, revoked,
+// forged) is not an error: the response has Valid set to false.
+func (c *Client) ValidateToken(ctx context.Context, token string) (*authpb.ValidateTokenResponse, error) {
+	return c.tokens.ValidateToken(c.withAPIKey(ctx), &authpb.ValidateTokenRequest{Token: token})
+}
+
+// Revoke blacklists an access token until it expires
+func (c *Client) Revoke(ctx context.Context, token string) error {
+	_, err := c.tokens.Revoke(c.withAPIKey(ctx), &authpb.RevokeRequest{Token: token})
+	if status.Code(err) == codes.InvalidArgument {
+		return ErrInvalidToken
+	}
+	return err
+}
+
+// Close closes the connection
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+func (c *Client) withAPIKey(ctx context.Context) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, "x-api-key", c.apiKey)
+}
@@ -0,0 +1,90 @@
+package authclient
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"auth-service/pkg/authpb"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// fakeTokenService accepts "good" and records the API key of each call
+type fakeTokenService struct {
+	authpb.UnimplementedTokenServiceServer
+	apiKeys []string
+}
+
+func (s *fakeTokenService) recordKey(ctx context.Context) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	s.apiKeys = append(s.apiKeys, md.Get("x-api-key")...)
+}
+
+func (s *fakeTokenService) ValidateToken(ctx context.Context, req *authpb.ValidateTokenRequest) (*authpb.ValidateTokenResponse, error) {
+	s.recordKey(ctx)
+	if req.Token != "good" {
+		return &authpb.ValidateTokenResponse{Valid: false}, nil
+	}
+	return &authpb.ValidateTokenResponse{Valid: true, UserId: "user-1"}, nil
+}
+
+func (s *fakeTokenService) Revoke(ctx context.Context, req *authpb.RevokeRequest) (*authpb.RevokeResponse, error) {
+	s.recordKey(ctx)
+	if req.Token != "good" {
+		return nil, status.Error(codes.InvalidArgument, "invalid or expired token")
+	}
+	return &authpb.RevokeResponse{}, nil
+}
+
+func newTestClient(t *testing.T, service *fakeTokenService) *Client {
+	t.Helper()
+	ln := bufconn.Listen(1 << 20)
+	srv := grpc.NewServer()
+	authpb.RegisterTokenServiceServer(srv, service)
+	go srv.Serve(ln)
+	t.Cleanup(srv.Stop)
+
+	client, err := Dial("passthrough:///bufnet", "secret-key",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return ln.DialContext(ctx) }))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+func TestClient(t *testing.T) {
+	service := &fakeTokenService{}
+	client := newTestClient(t, service)
+	ctx := context.Background()
+
+	token, err := client.ValidateToken(ctx, "good")
+	if err != nil || !token.Valid || token.UserId != "user-1" {
+		t.Errorf("ValidateToken(good) = %+v, %v", token, err)
+	}
+	if token, err := client.ValidateToken(ctx, "forged"); err != nil || token.Valid {
+		t.Errorf("ValidateToken(forged) = %+v, %v; want valid=false", token, err)
+	}
+
+	if err := client.Revoke(ctx, "good"); err != nil {
+		t.Errorf("Revoke(good) = %v", err)
+	}
+	if err := client.Revoke(ctx, "forged"); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("Revoke(forged) = %v, want ErrInvalidToken", err)
+	}
+
+	for _, key := range service.apiKeys {
+		if key != "secret-key" {
+			t.Errorf("call sent API key %q", key)
+		}
+	}
+	if len(service.apiKeys) != 4 {
+		t.Errorf("%d calls carried an API key, want 4", len(service.apiKeys))
+	}
+}
@@ -3,6 +3,8 @@ package database
 import (
 	"fmt"
 	"log"
+	"os"
+	"time"
 
 	"auth-service/config"
 	"auth-service/internal/domain"
@@ -17,25 +19,236 @@ func NewPostgresDB(cfg *config.DatabaseConfig) (*gorm.DB, error) {
 	dsn := cfg.GetDSN()
 
 	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
-		Logger: logger.Default.LogMode(logger.Info),
+		Logger: newLogger(cfg.LogLevel),
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
+	if err := configurePool(db, cfg); err != nil {
+		return nil, fmt.Errorf("failed to configure database pool: %w", err)
+	}
 
 	// Run migrations
+	if err := hashRefreshTokens(db); err != nil {
+		return nil, fmt.Errorf("failed to hash stored refresh tokens: %w", err)
+	}
 	if err := runMigrations(db); err != nil {
 		return nil, fmt.Errorf("failed to run migrations: %w", err)
 	}
+	if err := dropRedundantIndexes(db); err != nil {
+		return nil, fmt.Errorf("failed to drop redundant indexes: %w", err)
+	}
+	if err := migrateDefaultOrganization(db); err != nil {
+		return nil, fmt.Errorf("failed to migrate users to the default organization: %w", err)
+	}
+	if err := lowercaseEmails(db); err != nil {
+		return nil, fmt.Errorf("failed to lowercase emails: %w", err)
+	}
+	if err := db.Exec(lowerUsernameIndex).Error; err != nil {
+		return nil, fmt.Errorf("failed to create the case-insensitive username index: %w", err)
+	}
+	if err := backfillDeviceFingerprints(db); err != nil {
+		return nil, fmt.Errorf("failed to backfill device fingerprints: %w", err)
+	}
+	if cfg.CaseInsensitiveUsernames {
+		if err := backfillNormalizedUsernames(db); err != nil {
+			return nil, fmt.Errorf("failed to backfill normalized usernames: %w", err)
+		}
+	}
 
 	log.Println("✅ Database connected and migrated successfully")
 	return db, nil
 }
 
+// newLogger returns a GORM logger at level (silent, error, warn or info).
+// Lookups that find no row are expected, e.g. logins for unknown users, and
+// are not logged as errors
+func newLogger(level string) logger.Interface {
+	logLevel := logger.Warn
+	switch level {
+	case "silent":
+		logLevel = logger.Silent
+	case "error":
+		logLevel = logger.Error
+	case "info":
+		logLevel = logger.Info
+	}
+	return logger.New(log.New(os.Stdout, "\r\n", log.LstdFlags), logger.Config{
+		SlowThreshold:             200 * time.Millisecond,
+		LogLevel:                  logLevel,
+		IgnoreRecordNotFoundError: true,
+		Colorful:                  true,
+	})
+}
+
+// configurePool applies the connection pool limits and logs them so
+// operators can check what a deployment actually runs with
+func configurePool(db *gorm.DB, cfg *config.DatabaseConfig) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	sqlDB.SetMaxOpenConns(cfg.MaxOpenConns)
+	sqlDB.SetMaxIdleConns(cfg.MaxIdleConns)
+	sqlDB.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+	sqlDB.SetConnMaxIdleTime(cfg.ConnMaxIdleTime)
+
+	log.Printf("🗄️  Database pool: max_open=%d max_idle=%d conn_max_lifetime=%s conn_max_idle_time=%s",
+		cfg.MaxOpenConns, cfg.MaxIdleConns, cfg.ConnMaxLifetime, cfg.ConnMaxIdleTime)
+	return nil
+}
+
 // runMigrations runs database migrations
 func runMigrations(db *gorm.DB) error {
 	return db.AutoMigrate(
+		&domain.Organization{},
 		&domain.User{},
 		&domain.RefreshToken{},
+		&domain.PasswordResetToken{},
+		&domain.PasswordHistory{},
+		&domain.VerificationToken{},
+		&domain.MagicLinkToken{},
+		&domain.Invite{},
+		&domain.OAuthAccount{},
+		&domain.Credential{},
+		&domain.AuditLog{},
+		&domain.APIKey{},
+		&domain.WebhookDelivery{},
 	)
 }
+
+// hashRefreshTokens replaces the plaintext refresh_tokens.token column with
+// token_hash, hashing every stored token in place with the same SHA-256 hex
+// encoding as security.HashToken, so existing sessions keep working. It runs
+// before AutoMigrate, which would otherwise add an empty token_hash column,
+// and is a no-op once token_hash exists.
+func hashRefreshTokens(db *gorm.DB) error {
+	migrator := db.Migrator()
+	if !migrator.HasTable(&domain.RefreshToken{}) || !migrator.HasColumn(&domain.RefreshToken{}, "token") ||
+		migrator.HasColumn(&domain.RefreshToken{}, "token_hash") {
+		return nil
+	}
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Migrator().RenameColumn(&domain.RefreshToken{}, "token", "token_hash"); err != nil {
+			return err
+		}
+		if err := tx.Exec("UPDATE refresh_tokens SET token_hash = encode(sha256(convert_to(token_hash, 'UTF8')), 'hex')").Error; err != nil {
+			return err
+		}
+		if tx.Migrator().HasIndex(&domain.RefreshToken{}, "idx_refresh_tokens_token") {
+			return tx.Migrator().RenameIndex(&domain.RefreshToken{}, "idx_refresh_tokens_token", "idx_refresh_tokens_token_hash")
+		}
+		return nil
+	})
+}
+
+// redundantRefreshTokenIndexes are covered by the leading column of
+// idx_refresh_tokens_user_active
+var redundantRefreshTokenIndexes = []string{"idx_refresh_tokens_user_id"}
+
+// dropRedundantIndexes drops indexes superseded by composite ones; AutoMigrate
+// only ever adds indexes. It is idempotent.
+func dropRedundantIndexes(db *gorm.DB) error {
+	for _, index := range redundantRefreshTokenIndexes {
+		if db.Migrator().HasIndex(&domain.RefreshToken{}, index) {
+			if err := db.Migrator().DropIndex(&domain.RefreshToken{}, index); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// singleTenantIndexes are the global unique indexes on users from before
+// multi-tenancy; the per-organization indexes replace them
+var singleTenantIndexes = []string{"idx_users_email", "idx_users_username", "idx_users_username_normalized"}
+
+// migrateDefaultOrganization creates the default organization and moves
+// users without one into it, then drops the global unique indexes so emails
+// and usernames only have to be unique per organization. It is idempotent.
+func migrateDefaultOrganization(db *gorm.DB) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		org := domain.Organization{Name: "Default", Slug: domain.DefaultOrganizationSlug}
+		if err := tx.Where("slug = ?", org.Slug).FirstOrCreate(&org).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&domain.User{}).Where("organization_id IS NULL").
+			Update("organization_id", org.ID).Error; err != nil {
+			return err
+		}
+		for _, index := range singleTenantIndexes {
+			if tx.Migrator().HasIndex(&domain.User{}, index) {
+				if err := tx.Migrator().DropIndex(&domain.User{}, index); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// lowerUsernameIndex backs the case-insensitive username match of logins
+const lowerUsernameIndex = "CREATE INDEX IF NOT EXISTS idx_users_org_username_lower ON users (organization_id, LOWER(username))"
+
+// lowercaseEmails lowercases emails stored before they were normalized on
+// write. Where two accounts in one organization differ only by the case of
+// their email, neither is changed, as the unique index would reject it: the
+// mixed-case one can then only sign in by username until an admin merges or
+// renames them, and they are logged here on every start. It is idempotent.
+func lowercaseEmails(db *gorm.DB) error {
+	err := db.Exec(`UPDATE users u SET email = LOWER(u.email)
+		WHERE u.email <> LOWER(u.email) AND NOT EXISTS (
+			SELECT 1 FROM users o
+			WHERE o.organization_id = u.organization_id AND o.id <> u.id AND LOWER(o.email) = LOWER(u.email))`).Error
+	if err != nil {
+		return err
+	}
+
+	// IDs only: emails don't belong in logs
+	var conflicts []string
+	if err := db.Model(&domain.User{}).Where("email <> LOWER(email)").Pluck("id", &conflicts).Error; err != nil {
+		return err
+	}
+	if len(conflicts) > 0 {
+		log.Printf("⚠️  %d users share an email with another user apart from case and were not lowercased: %v", len(conflicts), conflicts)
+	}
+	return nil
+}
+
+// backfillNormalizedUsernames fills username_normalized for users created
+// while case-insensitive usernames were off. It fails on the unique index if
+// two usernames in one organization differ only in case; rename one of them
+// first.
+func backfillNormalizedUsernames(db *gorm.DB) error {
+	var users []domain.User
+	return db.Select("id", "username").Where("username_normalized IS NULL").
+		FindInBatches(&users, 500, func(tx *gorm.DB, batch int) error {
+			for _, user := range users {
+				normalized := domain.NormalizeUsername(user.Username)
+				if err := tx.Model(&domain.User{}).Where("id = ?", user.ID).
+					Update("username_normalized", normalized).Error; err != nil {
+					return err
+				}
+			}
+			return nil
+		}).Error
+}
+
+// backfillDeviceFingerprints fills device_fingerprint for refresh tokens
+// issued before it existed, so their devices do not count as new on the
+// next login. Tokens without device metadata are left empty.
+func backfillDeviceFingerprints(db *gorm.DB) error {
+	var tokens []domain.RefreshToken
+	return db.Select("id", "ip_address", "user_agent").
+		Where("(device_fingerprint IS NULL OR device_fingerprint = '') AND (ip_address <> '' OR user_agent <> '')").
+		FindInBatches(&tokens, 500, func(tx *gorm.DB, batch int) error {
+			for _, token := range tokens {
+				fingerprint := domain.DeviceFingerprint(token.IPAddress, token.UserAgent)
+				if err := tx.Model(&domain.RefreshToken{}).Where("id = ?", token.ID).
+					Update("device_fingerprint", fingerprint).Error; err != nil {
+					return err
+				}
+			}
+			return nil
+		}).Error
+}
@@ -0,0 +1,17 @@
+package database
+
+import (
+	"auth-service/config"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// NewRedisClient creates a new Redis client. The connection is established
+// lazily, so an unavailable Redis does not prevent the service from starting.
+func NewRedisClient(cfg *config.RedisConfig) *redis.Client {
+	return redis.NewClient(&redis.Options{
+		Addr:     cfg.GetRedisAddr(),
+		Password: cfg.Password,
+		DB:       cfg.DB,
+	})
+}
@@ -0,0 +1,103 @@
+package security
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+)
+
+// argon2idPrefix starts every Argon2id hash in the PHC string format:
+// $argon2id$v=19$m=65536,t=3,p=4$<salt>$<key>
+const argon2idPrefix = "$argon2id$"
+
+// Salt and key sizes recommended by RFC 9106
+const (
+	argon2SaltLength = 16
+	argon2KeyLength  = 32
+)
+
+// Argon2Params are the Argon2id cost parameters
+type Argon2Params struct {
+	Memory      uint32 // Memory in KiB
+	Time        uint32 // Number of passes over the memory
+	Parallelism uint8  // Number of lanes (threads)
+}
+
+// Argon2idHasher hashes passwords with Argon2id. Unlike bcrypt it has no
+// length limit and is memory-hard, which makes GPU cracking expensive.
+type Argon2idHasher struct {
+	params Argon2Params
+}
+
+// NewArgon2idHasher creates an Argon2id password hasher
+func NewArgon2idHasher(params Argon2Params) *Argon2idHasher {
+	return &Argon2idHasher{params: params}
+}
+
+// Hash hashes a password using Argon2id with a random salt
+func (h *Argon2idHasher) Hash(password string) (string, error) {
+	salt := make([]byte, argon2SaltLength)
+	if _, err := rand.Read(salt); err != nil {
+		return "", err
+	}
+	p := h.params
+	key := argon2.IDKey([]byte(password), salt, p.Time, p.Memory, p.Parallelism, argon2KeyLength)
+
+	return fmt.Sprintf("%sv=%d$m=%d,t=%d,p=%d$%s$%s", argon2idPrefix, argon2.Version, p.Memory, p.Time, p.Parallelism,
+		base64.RawStdEncoding.EncodeToString(salt), base64.RawStdEncoding.EncodeToString(key)), nil
+}
+
+// Compare compares a hashed password with a plain text password. bcrypt
+// hashes from before the switch to Argon2id are still accepted.
+func (h *Argon2idHasher) Compare(hash, password string) bool {
+	return comparePassword(hash, password)
+}
+
+// NeedsRehash reports whether hash is not an Argon2id hash with the
+// configured parameters
+func (h *Argon2idHasher) NeedsRehash(hash string) bool {
+	params, _, key, ok := decodeArgon2id(hash)
+	return !ok || params != h.params || len(key) != argon2KeyLength
+}
+
+// compareArgon2id verifies password with the parameters stored in hash, so
+// hashes made with older parameters keep working after they are changed
+func compareArgon2id(hash, password string) bool {
+	p, salt, key, ok := decodeArgon2id(hash)
+	if !ok {
+		return false
+	}
+	other := argon2.IDKey([]byte(password), salt, p.Time, p.Memory, p.Parallelism, uint32(len(key)))
+	return subtle.ConstantTimeCompare(key, other) == 1
+}
+
+// decodeArgon2id splits a PHC string into its parameters, salt and key
+func decodeArgon2id(hash string) (p Argon2Params, salt, key []byte, ok bool) {
+	parts := strings.Split(hash, "$")
+	// "", "argon2id", "v=19", "m=...,t=...,p=...", salt, key
+	if len(parts) != 6 || parts[1] != "argon2id" {
+		return p, nil, nil, false
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return p, nil, nil, false
+	}
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &p.Memory, &p.Time, &p.Parallelism); err != nil ||
+		p.Time == 0 || p.Parallelism == 0 {
+		return p, nil, nil, false
+	}
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return p, nil, nil, false
+	}
+	key, err = base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil || len(key) == 0 {
+		return p, nil, nil, false
+	}
+	return p, salt, key, true
+}
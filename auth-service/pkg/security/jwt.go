@@ -3,62 +3,169 @@
 package security
 
 import (
-	"crypto/rand"         // Kriptografik random sayı üretimi (güvenli)
-	"encoding/base64"     // Base64 encoding/decoding
-	"errors"              // Hata tanımlamaları
-	"time"                // Zaman işlemleri
+	"crypto/rand"     // Kriptografik random sayı üretimi (güvenli)
+	"crypto/rsa"      // RSA anahtarları (RS256 için)
+	"encoding/base64" // Base64 encoding/decoding
+	"errors"          // Hata tanımlamaları
+	"sync"            // Key ring'i korumak için (RotateKey)
+	"time"            // Zaman işlemleri
 
-	"github.com/golang-jwt/jwt/v5"  // JWT (JSON Web Token) kütüphanesi
-	"github.com/google/uuid"        // UUID işlemleri
+	"github.com/golang-jwt/jwt/v5" // JWT (JSON Web Token) kütüphanesi
+	"github.com/google/uuid"       // UUID işlemleri
 )
 
 // Hata tanımlamaları
 var (
-	ErrInvalidToken = errors.New("invalid token")  // Token formatı yanlış veya signature geçersiz
-	ErrExpiredToken = errors.New("expired token")  // Token süresi dolmuş
+	ErrInvalidToken = errors.New("invalid token") // Token formatı yanlış veya signature geçersiz
+	ErrExpiredToken = errors.New("expired token") // Token süresi dolmuş
+	// ErrSigningKeyMissing - Servis sadece public key ile kurulmuş, token üretemez (sadece doğrular)
+	ErrSigningKeyMissing = errors.New("signing key missing")
 )
 
+// Token kullanım tipleri ("token_use" claim'i)
+// Bir tipteki token'ın başka bir tip yerine kabul edilmesini (token type confusion) engeller.
+// Örn: ileride refresh token'lar da JWT olursa, access token bekleyen yerde kabul edilmemeli.
+const (
+	TokenUseAccess = "access"
+)
+
+// DefaultIssuer - WithIssuer verilmezse token'lara yazılan ve doğrulamada beklenen "iss"
+const DefaultIssuer = "auth-service"
+
+// DefaultLeeway - WithLeeway verilmezse exp/nbf/iat kontrollerinde tolere edilen saat farkı
+const DefaultLeeway = 30 * time.Second
+
 // JWTClaims - JWT token içinde saklanacak bilgiler (payload)
 // JWT = 3 parça: Header.Payload.Signature
 // Claims = Payload kısmında saklanan bilgiler
 type JWTClaims struct {
 	// Custom claims (bizim eklediğimiz bilgiler)
-	UserID   string `json:"user_id"`   // Kullanıcı ID'si
-	Email    string `json:"email"`     // Email adresi
+	UserID   string `json:"user_id"`  // Kullanıcı ID'si
+	Email    string `json:"email"`    // Email adresi
 	Username string `json:"username"` // Kullanıcı adı
-	
+	Role     string `json:"role"`     // Yetki rolü (user, admin)
+	// Scopes - Token'ın kullanabileceği yetkiler (örn. "profile:read"); RequireScope middleware'i kontrol eder
+	// Rolden türetilir (bkz. domain.ScopesForRole); rol tek başına "ne yapabilir"i söylemez
+	Scopes []string `json:"scopes,omitempty"`
+	// OrgID - Kullanıcının organizasyonu (tenant); :orgID taşıyan route'larda OrganizationScope karşılaştırır
+	OrgID string `json:"org_id,omitempty"`
+	// SessionID - Token'ın ait olduğu oturum (refresh token kaydının ID'si)
+	// "Bu oturum hariç diğerlerini kapat" gibi işlemler için gerekli
+	SessionID string `json:"sid,omitempty"`
+	// TokenVersion - Token üretildiğinde kullanıcının token version'ı ("tv")
+	// Kullanıcının version'ı artırılınca (şifre değişikliği, force logout) daha eski token'lar reddedilir
+	// Bu claim'i taşımayan eski token'lar version 0 sayılır
+	TokenVersion int `json:"tv"`
+	// PasswordChangeRequired - Kullanıcı admin'in verdiği geçici şifreyle giriş yaptı ("pwd_chg")
+	// Şifresini değiştirene kadar RequirePasswordChanged middleware'i diğer route'ları reddeder
+	PasswordChangeRequired bool `json:"pwd_chg,omitempty"`
+	// TokenUse - Token'ın ne için üretildiği (TokenUseAccess)
+	// ValidateToken beklenen tip dışındaki (veya tipi olmayan) token'ları reddeder
+	TokenUse string `json:"token_use"`
+
 	// Standard JWT claims (RFC 7519)
 	// jwt.RegisteredClaims = exp, iat, nbf, iss, sub, aud, jti
-	jwt.RegisteredClaims  // Embedding (Go'nun inheritance benzeri özelliği)
+	jwt.RegisteredClaims // Embedding (Go'nun inheritance benzeri özelliği)
 }
 
 // JWTService - JWT token oluşturma ve doğrulama servisi
 // Bu servis JWT işlemlerini kapsüller (encapsulation)
 type JWTService struct {
-	// secretKey - JWT'yi imzalamak için kullanılan gizli anahtar
-	// HMAC-SHA256 algoritması kullanılır: hash(header + payload + secret)
-	// Bu key'i bilen herkes token oluşturabilir, bu yüzden GİZLİ tutulmalı!
-	// []byte = byte array (string yerine performans için)
-	secretKey       []byte
-	
+	// signingMethod - Token'ları imzalamak için kullanılan algoritma (HS256 veya RS256)
+	// ValidateToken SADECE bu algoritma ile imzalanmış token'ları kabul eder
+	signingMethod jwt.SigningMethod
+
+	// keys - İmzalama ve doğrulama anahtarları (key ring), mu ile korunur
+	// keys[0] = aktif anahtar: yeni token'lar bununla imzalanır ("kid" header'ı bu anahtarın ID'si)
+	// Diğerleri = RotateKey ile emekliye ayrılmış anahtarlar, sadece doğrulama için;
+	// access token TTL'i dolunca (onlarla imzalanmış son token da expire olunca) atılır.
+	// Anahtar tipleri için bkz. signingKey
+	mu   sync.RWMutex
+	keys []*signingKey
+
 	// accessTokenTTL - Access token ne kadar süre geçerli olacak
 	// TTL = Time To Live (yaşam süresi)
 	// Genelde kısa: 15 dakika - 1 saat
-	accessTokenTTL  time.Duration
-	
+	accessTokenTTL time.Duration
+
 	// refreshTokenTTL - Refresh token ne kadar süre geçerli olacak
 	// Genelde uzun: 7 gün - 30 gün
 	refreshTokenTTL time.Duration
+
+	// issuer - Token'lara yazılan "iss"; başka issuer'lı token'lar reddedilir
+	// Aynı secret'ı paylaşan iki auth servisi birbirinin token'ını kabul etmesin diye
+	issuer string
+
+	// audiences - Token'lara yazılan "aud" listesi; doğrulamada token'ın aud'unda
+	// bunlardan en az biri olmalı. Boşsa aud yazılmaz ve kontrol edilmez.
+	audiences []string
+
+	// leeway - Sunucular arası saat kaymasına tolerans (exp, nbf, iat kontrollerinde)
+	// Saati birkaç saniye ileride olan bir replikanın ürettiği token "henüz geçerli değil" sayılmasın diye
+	leeway time.Duration
+
+	// provider - HS256 secret'larının kaynağı; RefreshKeys buradan yeniden okur (RS256'da nil)
+	provider SecretProvider
+}
+
+// JWTOption - NewJWTService / NewRSAJWTService'e opsiyonel ayar vermek için (functional options)
+type JWTOption func(*JWTService)
+
+// WithIssuer - Token'ların "iss" claim'i (varsayılan DefaultIssuer)
+func WithIssuer(issuer string) JWTOption {
+	return func(s *JWTService) {
+		s.issuer = issuer
+	}
+}
+
+// WithAudience - Token'ların kimin için olduğu ("aud" claim'i, örn: "user-service")
+// Doğrulamada token'ın audience'larından en az biri bu listede olmalı.
+func WithAudience(audiences ...string) JWTOption {
+	return func(s *JWTService) {
+		s.audiences = audiences
+	}
 }
 
 // NewJWTService - JWTService oluşturan factory fonksiyon
 // Factory Pattern: Obje oluşturmayı kapsülleyen design pattern
-func NewJWTService(secretKey string, accessTokenTTL, refreshTokenTTL time.Duration) *JWTService {
-	return &JWTService{
-		secretKey:       []byte(secretKey),  // String'i byte array'e çevir
+// Secret sabit bir StaticSecretProvider'a sarılır; değişen secret'lar için NewJWTServiceWithProvider.
+func NewJWTService(secretKey string, accessTokenTTL, refreshTokenTTL time.Duration, opts ...JWTOption) *JWTService {
+	s := newJWTService(jwt.SigningMethodHS256, newHMACSigningKey(secretKey), accessTokenTTL, refreshTokenTTL, opts)
+	s.provider = NewStaticSecretProvider(secretKey)
+	return s
+}
+
+// NewRSAJWTService - RS256 (asymmetric) ile çalışan JWTService oluşturur
+// privateKey: Token imzalamak için (sadece auth-service'te bulunur)
+// publicKey: Token doğrulamak için (downstream servislerle paylaşılabilir)
+// Sadece doğrulama yapacak servisler privateKey = nil verebilir; GenerateAccessToken ErrSigningKeyMissing döner.
+// publicKey nil ise privateKey'den türetilir.
+func NewRSAJWTService(privateKey *rsa.PrivateKey, publicKey *rsa.PublicKey, accessTokenTTL, refreshTokenTTL time.Duration, opts ...JWTOption) *JWTService {
+	return newJWTService(jwt.SigningMethodRS256, newRSASigningKey(privateKey, publicKey), accessTokenTTL, refreshTokenTTL, opts)
+}
+
+// WithLeeway - Token zaman kontrollerindeki saat kayması toleransı (varsayılan DefaultLeeway)
+// 0 verilirse tolerans yoktur.
+func WithLeeway(leeway time.Duration) JWTOption {
+	return func(s *JWTService) {
+		s.leeway = leeway
+	}
+}
+
+// newJWTService - İki constructor'ın ortak kısmı
+func newJWTService(method jwt.SigningMethod, key *signingKey, accessTokenTTL, refreshTokenTTL time.Duration, opts []JWTOption) *JWTService {
+	s := &JWTService{
+		signingMethod:   method,
+		keys:            []*signingKey{key},
 		accessTokenTTL:  accessTokenTTL,
 		refreshTokenTTL: refreshTokenTTL,
+		issuer:          DefaultIssuer,
+		leeway:          DefaultLeeway,
 	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
 // GenerateAccessToken - Yeni JWT access token oluşturur
@@ -66,49 +173,89 @@ func NewJWTService(secretKey string, accessTokenTTL, refreshTokenTTL time.Durati
 // - xxxxx: Header (algorithm, type)
 // - yyyyy: Payload (claims - kullanıcı bilgileri)
 // - zzzzz: Signature (doğrulama için)
-func (s *JWTService) GenerateAccessToken(userID uuid.UUID, email, username string) (string, error) {
+// role = kullanıcının rolü ("role" claim'i, RequireRole middleware'i kontrol eder)
+// scopes = token'ın yetkileri ("scopes" claim'i, RequireScope middleware'i kontrol eder)
+// sessionID = token'ın bağlı olduğu refresh token kaydının ID'si ("sid" claim'i)
+// orgID = kullanıcının organizasyonu ("org_id" claim'i); uuid.Nil ise claim eklenmez
+// tokenVersion = kullanıcının güncel token version'ı ("tv" claim'i, AuthMiddleware karşılaştırır)
+// mustChangePassword = kullanıcı geçici şifreyle giriş yaptı ("pwd_chg" claim'i)
+func (s *JWTService) GenerateAccessToken(userID uuid.UUID, email, username, role string, scopes []string, sessionID, orgID uuid.UUID, tokenVersion int, mustChangePassword bool) (string, error) {
 	// Şu anki zaman (token oluşturulma zamanı)
 	now := time.Now()
-	
+
 	// Claims'leri (payload) oluştur
 	claims := &JWTClaims{
 		// Custom claims - bizim eklediğimiz bilgiler
-		UserID:   userID.String(),  // UUID'yi string'e çevir
-		Email:    email,
-		Username: username,
-		
+		UserID:       userID.String(), // UUID'yi string'e çevir
+		Email:        email,
+		Username:     username,
+		Role:         role,
+		Scopes:       scopes,
+		SessionID:    sessionID.String(),
+		TokenVersion: tokenVersion,
+		TokenUse:     TokenUseAccess,
+
+		PasswordChangeRequired: mustChangePassword,
+
 		// Standard JWT claims (RFC 7519 standardı)
 		RegisteredClaims: jwt.RegisteredClaims{
 			// ExpiresAt - Token ne zaman expire olacak
 			// Şimdi + 15 dakika (veya config'den gelen süre)
 			ExpiresAt: jwt.NewNumericDate(now.Add(s.accessTokenTTL)),
-			
+
 			// IssuedAt - Token ne zaman oluşturuldu
-			IssuedAt:  jwt.NewNumericDate(now),
-			
+			IssuedAt: jwt.NewNumericDate(now),
+
 			// NotBefore - Token ne zamandan itibaren geçerli
 			// Genelde şimdi, ama gelecekte aktif olacak token'lar için kullanılabilir
 			NotBefore: jwt.NewNumericDate(now),
-			
+
 			// Issuer - Token'ı kim oluşturdu
 			// Mikroservis ortamlarında hangi servis oluşturdu anlamak için
-			Issuer:    "auth-service",
-			
+			Issuer: s.issuer,
+
+			// Audience - Token kimler için (hangi servisler kabul etmeli); boşsa claim yazılmaz
+			Audience: s.audiences,
+
 			// Subject - Token kimin için oluşturuldu
 			// Genelde user ID kullanılır
-			Subject:   userID.String(),
+			Subject: userID.String(),
+
+			// ID (jti) - Token'ın benzersiz ID'si
+			// Logout'ta token'ı süresi dolmadan blacklist'e almak için kullanılır
+			ID: uuid.NewString(),
 		},
 	}
 
+	if orgID != uuid.Nil {
+		claims.OrgID = orgID.String()
+	}
+
 	// JWT token oluştur
 	// SigningMethodHS256 = HMAC-SHA256 algoritması
 	// HS256 = Symmetric encryption (aynı key hem imzalar hem doğrular)
-	// Alternatif: RS256 (Asymmetric - public/private key)
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	
-	// Token'ı secret key ile imzala ve string'e çevir
+	// RS256 = Asymmetric (private key imzalar, public key doğrular) - NewRSAJWTService
+	token := jwt.NewWithClaims(s.signingMethod, claims)
+
+	// Token'ı aktif anahtar ile imzala ve string'e çevir
+	// "kid" header'ı doğrulamada hangi anahtarın kullanılacağını söyler (key rotation)
 	// Sonuç: "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9.eyJ1c2VyX2lkIjoiMTIzIn0.signature"
-	return token.SignedString(s.secretKey)
+	active := s.activeKey()
+	if active.sign == nil {
+		return "", ErrSigningKeyMissing
+	}
+	token.Header["kid"] = active.id
+	return token.SignedString(active.sign)
+}
+
+// HasScope - Token'a scope yetkisi verilmiş mi?
+func HasScope(claims *JWTClaims, scope string) bool {
+	for _, s := range claims.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
 }
 
 // GenerateRefreshToken - Yeni refresh token oluşturur
@@ -121,14 +268,14 @@ func (s *JWTService) GenerateRefreshToken() (string, error) {
 	// 32 byte'lık random byte array oluştur
 	// make() = Go'da array/slice oluşturma fonksiyonu
 	b := make([]byte, 32)
-	
+
 	// Kriptografik random byte'lar üret
 	// crypto/rand = Güvenli random (math/rand değil!)
 	// math/rand = Tahmin edilebilir (güvenlik için kullanma!)
 	if _, err := rand.Read(b); err != nil {
 		return "", err
 	}
-	
+
 	// Byte array'i base64 string'e çevir
 	// Base64 = Binary veriyi text'e çevirme yöntemi
 	// URLEncoding = URL-safe karakterler (+ ve / yerine - ve _ kullanır)
@@ -137,30 +284,60 @@ func (s *JWTService) GenerateRefreshToken() (string, error) {
 }
 
 // ValidateToken - JWT token'ı doğrular ve claims'ı döndürür
+// Hata her zaman ErrExpiredToken (süresi dolmuş ama başka açıdan geçerli) veya ErrInvalidToken'dır.
 // Token doğrulama adımları:
 // 1. Format kontrolü (xxxxx.yyyyy.zzzzz)
 // 2. Signature doğrulama (secret key ile)
 // 3. Expiration kontrolü (süresi dolmuş mu)
 // 4. Claims parse etme
 func (s *JWTService) ValidateToken(tokenString string) (*JWTClaims, error) {
+	return s.validateToken(tokenString)
+}
+
+// ValidateTokenWithGrace - ValidateToken gibi, ama süresi en fazla grace kadar önce
+// dolmuş token'ları da kabul eder (grace, saat kayması toleransına eklenir).
+// Sadece salt-okunur endpoint'lerde kullanılmalı; grace <= 0 ise ValidateToken ile aynıdır.
+func (s *JWTService) ValidateTokenWithGrace(tokenString string, grace time.Duration) (*JWTClaims, error) {
+	if grace <= 0 {
+		return s.validateToken(tokenString)
+	}
+	return s.validateToken(tokenString, jwt.WithLeeway(s.leeway+grace))
+}
+
+// validateToken - Token doğrulamanın ortak kısmı, opts ile parser davranışı değiştirilebilir
+func (s *JWTService) validateToken(tokenString string, opts ...jwt.ParserOption) (*JWTClaims, error) {
+	// "iss" her zaman kontrol edilir: başka bir issuer'ın (aynı secret'lı) token'ı geçersiz
+	// Saat kayması toleransı önce gelir; opts'taki WithLeeway (grace) onu ezer
+	opts = append([]jwt.ParserOption{jwt.WithLeeway(s.leeway), jwt.WithIssuer(s.issuer)}, opts...)
+
 	// JWT token'ı parse et ve doğrula
 	// ParseWithClaims = Token'ı çöz ve claims'ı JWTClaims struct'ına map'le
 	token, err := jwt.ParseWithClaims(tokenString, &JWTClaims{}, func(token *jwt.Token) (interface{}, error) {
 		// Callback fonksiyon: Signing method kontrolü
-		// Token'ın HMAC algoritması ile imzalandığını doğrula
-		// Type assertion: token.Method'un *jwt.SigningMethodHMAC tipinde olup olmadığını kontrol et
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			// Yanlış algoritma (belki RS256 veya başka bir şey)
-			// Güvenlik: Algorithm confusion attack'ı engellemek için
+		// Token'ın servisin kullandığı algoritma ile imzalandığını doğrula
+		// Güvenlik: Algorithm confusion attack'ı engellemek için
+		// Örn: RS256 modunda public key herkese açıktır; HS256 token'ı kabul etseydik
+		// saldırgan public key'i HMAC secret'ı olarak kullanıp token üretebilirdi
+		if token.Method.Alg() != s.signingMethod.Alg() {
+			return nil, ErrInvalidToken
+		}
+		// Doğrulama için "kid"e göre anahtarı seç (HS256: secret, RS256: public key)
+		// Bilinmeyen veya süresi dolmuş (atılmış) anahtar = geçersiz token
+		key := s.verificationKey(token.Header["kid"])
+		if key == nil {
 			return nil, ErrInvalidToken
 		}
-		// Doğrulama için secret key'i döndür
-		return s.secretKey, nil
-	})
+		return key, nil
+	}, opts...)
 
-	// Parse hatası varsa (format yanlış, signature uyuşmuyor vs.)
+	// Parse hatası varsa (format yanlış, signature uyuşmuyor, süresi dolmuş vs.)
+	// Süresi dolmuş token ayrı döner: client yeniden login değil, refresh yapmalı.
+	// Diğer her şey (bozuk format, yanlış imza, başka issuer...) ErrInvalidToken
 	if err != nil {
-		return nil, err
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			return nil, ErrExpiredToken
+		}
+		return nil, ErrInvalidToken
 	}
 
 	// Token geçerli mi kontrol et (expiration vs.)
@@ -176,10 +353,38 @@ func (s *JWTService) ValidateToken(tokenString string) (*JWTClaims, error) {
 		return nil, ErrInvalidToken
 	}
 
+	// Token tipi kontrolü: sadece access token'lar kabul edilir
+	// token_use'u olmayan veya farklı olan token'lar (örn: "refresh") reddedilir
+	if claims.TokenUse != TokenUseAccess {
+		return nil, ErrInvalidToken
+	}
+
+	// Audience kontrolü: token bu servisin kabul ettiği audience'lardan birine verilmiş olmalı
+	if !s.audienceAllowed(claims.Audience) {
+		return nil, ErrInvalidToken
+	}
+
 	// Geçerli token, claims'ı döndür
 	return claims, nil
 }
 
+// audienceAllowed - Token'ın "aud"unda beklenen audience'lardan biri var mı?
+// Audience yapılandırılmamışsa her token kabul edilir.
+// (jwt.WithAudience tek audience bekler, bu yüzden kontrol burada yapılır)
+func (s *JWTService) audienceAllowed(tokenAudiences jwt.ClaimStrings) bool {
+	if len(s.audiences) == 0 {
+		return true
+	}
+	for _, expected := range s.audiences {
+		for _, aud := range tokenAudiences {
+			if aud == expected {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // ExtractUserID - Token'dan user ID'şi çıkarır
 // Yardımcı fonksiyon: Middleware'de kullanıcı ID'sini hızlıca almak için
 func (s *JWTService) ExtractUserID(tokenString string) (uuid.UUID, error) {
@@ -0,0 +1,135 @@
+package security
+
+import (
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// ErrKeyTypeMismatch - Rotate edilen anahtarın tipi servisin algoritmasına uymuyor
+// (örn: HS256 servisine RSA anahtarı)
+var ErrKeyTypeMismatch = errors.New("key type does not match signing method")
+
+// signingKey - Key ring'deki tek bir anahtar
+// HS256: sign ve verify aynı gizli anahtar ([]byte), hash(header + payload + secret)
+//
+//	Bu key'i bilen herkes token oluşturabilir, bu yüzden GİZLİ tutulmalı!
+//
+// RS256: sign = private key, verify = public key (asymmetric)
+//
+//	Downstream servisler sadece public key ile doğrulama yapabilir, secret paylaşmaya gerek yok
+type signingKey struct {
+	// id - Token'daki "kid" header'ı; anahtarın parmak izi (fingerprint)
+	// Anahtardan türetildiği için aynı anahtarı kullanan tüm replica'lar aynı kid'i üretir
+	id string
+
+	sign   interface{} // nil = sadece doğrulama (private key'i olmayan RS256 servisi)
+	verify interface{}
+
+	// retiredAt - Anahtarın aktiflikten çıktığı zaman (aktif anahtar için sıfır)
+	retiredAt time.Time
+}
+
+func newHMACSigningKey(secret string) *signingKey {
+	key := []byte(secret) // String'i byte array'e çevir
+	return &signingKey{id: keyID(key), sign: key, verify: key}
+}
+
+// newRSASigningKey - publicKey nil ise privateKey'den türetilir
+func newRSASigningKey(privateKey *rsa.PrivateKey, publicKey *rsa.PublicKey) *signingKey {
+	if publicKey == nil && privateKey != nil {
+		publicKey = &privateKey.PublicKey
+	}
+	der, _ := x509.MarshalPKIXPublicKey(publicKey)
+	k := &signingKey{id: keyID(der), verify: publicKey}
+	// nil *rsa.PrivateKey'i interface'e koymuyoruz: nil kontrolü (sign == nil) çalışsın
+	if privateKey != nil {
+		k.sign = privateKey
+	}
+	return k
+}
+
+// keyID - Anahtar materyalinin SHA-256 özetinin ilk 8 byte'ı (hex)
+// HS256'da özet secret'ı açığa çıkarmaz: brute force maliyeti imzayı kırmakla aynıdır
+func keyID(material []byte) string {
+	sum := sha256.Sum256(material)
+	return hex.EncodeToString(sum[:8])
+}
+
+// RotateKey - Yeni HS256 secret'ını aktif anahtar yapar
+// Eski anahtar access token TTL'i boyunca doğrulama için tutulur: rotation'dan önce
+// imzalanmış token'lar süreleri dolana kadar geçerli kalır, oturumlar düşmez.
+func (s *JWTService) RotateKey(newKey string) error {
+	if _, ok := s.signingMethod.(*jwt.SigningMethodHMAC); !ok {
+		return ErrKeyTypeMismatch
+	}
+	s.rotate(newHMACSigningKey(newKey))
+	return nil
+}
+
+// RotateRSAKey - RotateKey'in RS256 karşılığı
+func (s *JWTService) RotateRSAKey(privateKey *rsa.PrivateKey) error {
+	if _, ok := s.signingMethod.(*jwt.SigningMethodRSA); !ok || privateKey == nil {
+		return ErrKeyTypeMismatch
+	}
+	s.rotate(newRSASigningKey(privateKey, nil))
+	return nil
+}
+
+func (s *JWTService) rotate(next *signingKey) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Aynı anahtar tekrar verildiyse bir şey yapma
+	if s.keys[0].id == next.id {
+		return
+	}
+
+	now := time.Now()
+	s.keys[0].retiredAt = now
+
+	// Yeni anahtar başa (aktif), süresi dolan eski anahtarlar atılır
+	keys := []*signingKey{next}
+	for _, k := range s.keys {
+		if k.id != next.id && s.usable(k, now) {
+			keys = append(keys, k)
+		}
+	}
+	s.keys = keys
+}
+
+// activeKey - Yeni token'ların imzalanacağı anahtar
+func (s *JWTService) activeKey() *signingKey {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.keys[0]
+}
+
+// verificationKey - Token header'ındaki "kid"e karşılık gelen doğrulama anahtarı (yoksa nil)
+// kid'i olmayan token'lar (key rotation öncesi üretilmiş) sadece aktif anahtarla doğrulanır.
+func (s *JWTService) verificationKey(kid interface{}) interface{} {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if kid == nil {
+		return s.keys[0].verify
+	}
+	id, _ := kid.(string)
+	now := time.Now()
+	for _, k := range s.keys {
+		if k.id == id && s.usable(k, now) {
+			return k.verify
+		}
+	}
+	return nil
+}
+
+// usable - Anahtar aktif mi, ya da onunla imzalanmış token'lar hala geçerli olabilir mi
+func (s *JWTService) usable(k *signingKey, now time.Time) bool {
+	return k.retiredAt.IsZero() || now.Before(k.retiredAt.Add(s.accessTokenTTL))
+}
@@ -0,0 +1,123 @@
+package security
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+func TestRotateKeyKeepsOldTokensValidUntilTheyExpire(t *testing.T) {
+	s := NewJWTService("old-secret", time.Minute, time.Hour)
+
+	before, err := s.GenerateAccessToken(uuid.New(), "jane@example.com", "jane", "user", nil, uuid.New(), uuid.Nil, 0, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.RotateKey("new-secret"); err != nil {
+		t.Fatal(err)
+	}
+	after, err := s.GenerateAccessToken(uuid.New(), "jane@example.com", "jane", "user", nil, uuid.New(), uuid.Nil, 0, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Both tokens validate during the overlap
+	for name, token := range map[string]string{"before": before, "after": after} {
+		if _, err := s.ValidateToken(token); err != nil {
+			t.Errorf("token issued %s rotation rejected: %v", name, err)
+		}
+	}
+
+	// New tokens are signed with the new key only
+	if kid := tokenKID(t, after); kid != keyID([]byte("new-secret")) {
+		t.Errorf("kid = %q, want the new key's ID", kid)
+	}
+	if _, err := NewJWTService("new-secret", time.Minute, time.Hour).ValidateToken(after); err != nil {
+		t.Errorf("token not signed with the new secret: %v", err)
+	}
+
+	// Once every token signed with the old key has expired, the key is dropped
+	s.keys[1].retiredAt = time.Now().Add(-time.Minute)
+	if _, err := s.ValidateToken(before); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("token signed with an expired key: got %v, want ErrInvalidToken", err)
+	}
+	if err := s.RotateKey("newer-secret"); err != nil {
+		t.Fatal(err)
+	}
+	if len(s.keys) != 2 {
+		t.Errorf("len(keys) = %d, want 2 (active + one retired)", len(s.keys))
+	}
+}
+
+func TestValidateTokenRejectsUnknownKeyID(t *testing.T) {
+	s := NewJWTService("test-secret", time.Minute, time.Hour)
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, &JWTClaims{
+		UserID:   uuid.NewString(),
+		TokenUse: TokenUseAccess,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    DefaultIssuer,
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+		},
+	})
+	token.Header["kid"] = "unknown"
+	signed, err := token.SignedString([]byte("test-secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.ValidateToken(signed); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("got %v, want ErrInvalidToken", err)
+	}
+
+	// Tokens from before key rotation existed carry no kid and use the active key
+	delete(token.Header, "kid")
+	signed, err = token.SignedString([]byte("test-secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.ValidateToken(signed); err != nil {
+		t.Errorf("token without kid rejected: %v", err)
+	}
+}
+
+func TestRotateRSAKey(t *testing.T) {
+	oldKey, newKey := testRSAKey(t), testRSAKey(t)
+	s := NewRSAJWTService(oldKey, nil, time.Minute, time.Hour)
+
+	before, err := s.GenerateAccessToken(uuid.New(), "jane@example.com", "jane", "user", nil, uuid.New(), uuid.Nil, 0, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.RotateRSAKey(newKey); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.ValidateToken(before); err != nil {
+		t.Errorf("token issued before rotation rejected: %v", err)
+	}
+
+	// A verify-only replica with the new public key derives the same kid
+	after, err := s.GenerateAccessToken(uuid.New(), "jane@example.com", "jane", "user", nil, uuid.New(), uuid.Nil, 0, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewRSAJWTService(nil, &newKey.PublicKey, time.Minute, time.Hour).ValidateToken(after); err != nil {
+		t.Errorf("verify-only service rejected token: %v", err)
+	}
+
+	if err := s.RotateKey("secret"); err != ErrKeyTypeMismatch {
+		t.Errorf("RotateKey on RS256 service: got %v, want ErrKeyTypeMismatch", err)
+	}
+}
+
+func tokenKID(t *testing.T, token string) string {
+	t.Helper()
+	parsed, _, err := jwt.NewParser().ParseUnverified(token, &JWTClaims{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	kid, _ := parsed.Header["kid"].(string)
+	return kid
+}
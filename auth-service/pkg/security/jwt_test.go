@@ -0,0 +1,313 @@
+package security
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+func TestAccessTokenCarriesSessionRoleScopesAndOrg(t *testing.T) {
+	s := NewJWTService("test-secret", time.Minute, time.Hour)
+	userID, sessionID, orgID := uuid.New(), uuid.New(), uuid.New()
+
+	token, err := s.GenerateAccessToken(userID, "jane@example.com", "jane", "user", []string{"profile:read"}, sessionID, orgID, 3, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	claims, err := s.ValidateToken(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claims.UserID != userID.String() || claims.SessionID != sessionID.String() || claims.Role != "user" || claims.OrgID != orgID.String() ||
+		claims.TokenVersion != 3 {
+		t.Errorf("claims = %+v", claims)
+	}
+	if !HasScope(claims, "profile:read") || HasScope(claims, "users:write") {
+		t.Errorf("scopes = %q, want [profile:read]", claims.Scopes)
+	}
+}
+
+func TestValidateTokenRejectsOtherSecret(t *testing.T) {
+	token, err := NewJWTService("secret-a", time.Minute, time.Hour).GenerateAccessToken(uuid.New(), "a@example.com", "a", "user", nil, uuid.New(), uuid.Nil, 0, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewJWTService("secret-b", time.Minute, time.Hour).ValidateToken(token); err == nil {
+		t.Error("token signed with another secret was accepted")
+	}
+}
+
+func TestAccessTokensHaveUniqueIDs(t *testing.T) {
+	s := NewJWTService("test-secret", time.Minute, time.Hour)
+	userID, sessionID := uuid.New(), uuid.New()
+
+	seen := map[string]bool{}
+	for i := 0; i < 2; i++ {
+		token, err := s.GenerateAccessToken(userID, "jane@example.com", "jane", "user", nil, sessionID, uuid.Nil, 0, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		claims, err := s.ValidateToken(token)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if claims.ID == "" || seen[claims.ID] {
+			t.Fatalf("jti = %q, want a unique non-empty ID", claims.ID)
+		}
+		seen[claims.ID] = true
+	}
+}
+
+func TestValidateTokenWithGrace(t *testing.T) {
+	// Negative TTL: the token expired 5 seconds ago; no clock skew leeway so
+	// only the grace period counts
+	s := NewJWTService("test-secret", -5*time.Second, time.Hour, WithLeeway(0))
+	token, err := s.GenerateAccessToken(uuid.New(), "jane@example.com", "jane", "user", nil, uuid.New(), uuid.Nil, 0, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := s.ValidateToken(token); err == nil {
+		t.Error("ValidateToken accepted an expired token")
+	}
+	if _, err := s.ValidateTokenWithGrace(token, 0); err == nil {
+		t.Error("zero grace accepted an expired token")
+	}
+	if _, err := s.ValidateTokenWithGrace(token, time.Second); err == nil {
+		t.Error("token expired beyond the grace window was accepted")
+	}
+	if _, err := s.ValidateTokenWithGrace(token, 30*time.Second); err != nil {
+		t.Errorf("token within the grace window rejected: %v", err)
+	}
+}
+
+func testRSAKey(t *testing.T) *rsa.PrivateKey {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+func TestRSAJWTServiceValidatesWithPublicKeyOnly(t *testing.T) {
+	key := testRSAKey(t)
+	issuer := NewRSAJWTService(key, nil, time.Minute, time.Hour)
+	userID := uuid.New()
+
+	token, err := issuer.GenerateAccessToken(userID, "jane@example.com", "jane", "user", nil, uuid.New(), uuid.Nil, 0, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verifier := NewRSAJWTService(nil, &key.PublicKey, time.Minute, time.Hour)
+	claims, err := verifier.ValidateToken(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claims.UserID != userID.String() {
+		t.Errorf("user_id = %s, want %s", claims.UserID, userID)
+	}
+
+	if _, err := verifier.GenerateAccessToken(userID, "jane@example.com", "jane", "user", nil, uuid.New(), uuid.Nil, 0, false); err != ErrSigningKeyMissing {
+		t.Errorf("verify-only service: got %v, want ErrSigningKeyMissing", err)
+	}
+}
+
+func TestRSAJWTServiceRejectsAlgorithmConfusion(t *testing.T) {
+	key := testRSAKey(t)
+	rsaService := NewRSAJWTService(key, nil, time.Minute, time.Hour)
+
+	// An attacker signs an HS256 token using the (public) RSA key as HMAC secret
+	publicPEM, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	forged, err := jwt.NewWithClaims(jwt.SigningMethodHS256, &JWTClaims{
+		UserID:   uuid.NewString(),
+		TokenUse: TokenUseAccess,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    DefaultIssuer,
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+		},
+	}).SignedString(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicPEM}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := rsaService.ValidateToken(forged); err == nil {
+		t.Error("RS256 service accepted an HS256 token")
+	}
+
+	// And the other way round: an HS256 service must not accept RS256 tokens
+	rsaToken, err := rsaService.GenerateAccessToken(uuid.New(), "jane@example.com", "jane", "user", nil, uuid.New(), uuid.Nil, 0, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewJWTService("test-secret", time.Minute, time.Hour).ValidateToken(rsaToken); err == nil {
+		t.Error("HS256 service accepted an RS256 token")
+	}
+}
+
+func TestValidateTokenRejectsOtherTokenTypes(t *testing.T) {
+	s := NewJWTService("test-secret", time.Minute, time.Hour)
+
+	sign := func(use string) string {
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, &JWTClaims{
+			UserID:   uuid.NewString(),
+			TokenUse: use,
+			RegisteredClaims: jwt.RegisteredClaims{
+				Issuer:    DefaultIssuer,
+				ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+			},
+		}).SignedString([]byte("test-secret"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return token
+	}
+
+	if _, err := s.ValidateToken(sign(TokenUseAccess)); err != nil {
+		t.Errorf("access token rejected: %v", err)
+	}
+	for _, use := range []string{"refresh", ""} {
+		if _, err := s.ValidateToken(sign(use)); err != ErrInvalidToken {
+			t.Errorf("token_use %q: got %v, want ErrInvalidToken", use, err)
+		}
+	}
+
+	// Generated access tokens carry the claim
+	token, err := s.GenerateAccessToken(uuid.New(), "jane@example.com", "jane", "user", nil, uuid.New(), uuid.Nil, 0, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	claims, err := s.ValidateToken(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claims.TokenUse != TokenUseAccess {
+		t.Errorf("token_use = %q", claims.TokenUse)
+	}
+}
+
+func TestValidateTokenChecksIssuerAndAudience(t *testing.T) {
+	issue := func(s *JWTService) string {
+		token, err := s.GenerateAccessToken(uuid.New(), "jane@example.com", "jane", "user", nil, uuid.New(), uuid.Nil, 0, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return token
+	}
+
+	validator := NewJWTService("shared-secret", time.Minute, time.Hour,
+		WithIssuer("https://auth.example.com"), WithAudience("user-service", "billing-service"))
+
+	claims, err := validator.ValidateToken(issue(validator))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if claims.Issuer != "https://auth.example.com" || len(claims.Audience) != 2 || claims.Audience[0] != "user-service" {
+		t.Errorf("iss = %q, aud = %q", claims.Issuer, claims.Audience)
+	}
+
+	tests := []struct {
+		name   string
+		issuer *JWTService
+	}{
+		{"default issuer", NewJWTService("shared-secret", time.Minute, time.Hour, WithAudience("user-service"))},
+		{"other issuer", NewJWTService("shared-secret", time.Minute, time.Hour, WithIssuer("https://other.example.com"), WithAudience("user-service"))},
+		{"no audience", NewJWTService("shared-secret", time.Minute, time.Hour, WithIssuer("https://auth.example.com"))},
+		{"other audience", NewJWTService("shared-secret", time.Minute, time.Hour, WithIssuer("https://auth.example.com"), WithAudience("admin-service"))},
+	}
+	for _, tt := range tests {
+		if _, err := validator.ValidateToken(issue(tt.issuer)); err != ErrInvalidToken {
+			t.Errorf("%s: got %v, want ErrInvalidToken", tt.name, err)
+		}
+	}
+
+	// One matching audience is enough
+	partial := NewJWTService("shared-secret", time.Minute, time.Hour, WithIssuer("https://auth.example.com"), WithAudience("billing-service", "admin-service"))
+	if _, err := validator.ValidateToken(issue(partial)); err != nil {
+		t.Errorf("token with one accepted audience rejected: %v", err)
+	}
+}
+
+func TestValidateTokenLeeway(t *testing.T) {
+	s := NewJWTService("test-secret", time.Minute, time.Hour)
+
+	sign := func(notBefore, expiresAt time.Time) string {
+		token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, &JWTClaims{
+			UserID:   uuid.NewString(),
+			TokenUse: TokenUseAccess,
+			RegisteredClaims: jwt.RegisteredClaims{
+				Issuer:    DefaultIssuer,
+				NotBefore: jwt.NewNumericDate(notBefore),
+				ExpiresAt: jwt.NewNumericDate(expiresAt),
+			},
+		}).SignedString([]byte("test-secret"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return token
+	}
+
+	now := time.Now()
+	tests := []struct {
+		name   string
+		token  string
+		wantOK bool
+	}{
+		// Issued by a replica whose clock runs ahead
+		{"nbf within leeway", sign(now.Add(20*time.Second), now.Add(time.Hour)), true},
+		{"nbf beyond leeway", sign(now.Add(45*time.Second), now.Add(time.Hour)), false},
+		// Checked by a replica whose clock runs ahead
+		{"exp within leeway", sign(now.Add(-time.Hour), now.Add(-20*time.Second)), true},
+		{"exp beyond leeway", sign(now.Add(-time.Hour), now.Add(-45*time.Second)), false},
+	}
+	for _, tt := range tests {
+		_, err := s.ValidateToken(tt.token)
+		if (err == nil) != tt.wantOK {
+			t.Errorf("%s: err = %v, want accepted = %v", tt.name, err, tt.wantOK)
+		}
+	}
+
+	strict := NewJWTService("test-secret", time.Minute, time.Hour, WithLeeway(0))
+	if _, err := strict.ValidateToken(tests[0].token); err == nil {
+		t.Error("zero leeway accepted a token that is not valid yet")
+	}
+}
+
+func TestValidateTokenErrors(t *testing.T) {
+	s := NewJWTService("test-secret", time.Minute, time.Hour, WithLeeway(0))
+	issue := func(s *JWTService) string {
+		token, err := s.GenerateAccessToken(uuid.New(), "jane@example.com", "jane", "user", nil, uuid.New(), uuid.Nil, 0, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return token
+	}
+	valid := issue(s)
+
+	tests := []struct {
+		name  string
+		token string
+		want  error
+	}{
+		{"expired", issue(NewJWTService("test-secret", -time.Second, time.Hour)), ErrExpiredToken},
+		{"malformed", "not-a-jwt", ErrInvalidToken},
+		{"truncated", valid[:len(valid)-10], ErrInvalidToken},
+		{"wrong signature", issue(NewJWTService("other-secret", time.Minute, time.Hour)), ErrInvalidToken},
+		// A forged token must not be reported as merely expired
+		{"expired with wrong signature", issue(NewJWTService("other-secret", -time.Second, time.Hour)), ErrInvalidToken},
+	}
+	for _, tt := range tests {
+		if _, err := s.ValidateToken(tt.token); err != tt.want {
+			t.Errorf("%s: got %v, want %v", tt.name, err, tt.want)
+		}
+	}
+}
@@ -1,33 +1,166 @@
 package security
 
 import (
+	"crypto/sha256"
+	"encoding/base64"
+	"strings"
+	"time"
+
 	"golang.org/x/crypto/bcrypt"
 )
 
-// PasswordService handles password hashing and verification
-type PasswordService struct {
+// PasswordHasher hashes and verifies passwords
+type PasswordHasher interface {
+	// Hash returns the encoded hash of password, including algorithm and parameters
+	Hash(password string) (string, error)
+	// Compare reports whether password matches hash. Every implementation
+	// accepts hashes of every supported algorithm, so changing the default
+	// algorithm does not lock out users with older hashes.
+	Compare(hash, password string) bool
+	// NeedsRehash reports whether hash was made with another algorithm or
+	// other parameters than the hasher's current ones
+	NeedsRehash(hash string) bool
+}
+
+// BcryptHasher hashes passwords with bcrypt. bcrypt only uses the first 72
+// bytes of a password; longer passwords are rejected by Hash unless the
+// hasher pre-hashes them (WithBcryptPreHash).
+type BcryptHasher struct {
 	cost int
+
+	// preHash hashes SHA-256(password) instead of the password itself
+	preHash bool
 }
 
-// NewPasswordService creates a new password service
-func NewPasswordService(cost int) *PasswordService {
+// BcryptOption configures optional bcrypt hasher behaviour
+type BcryptOption func(*BcryptHasher)
+
+// WithBcryptPreHash makes Hash run bcrypt on the base64-encoded SHA-256 of
+// the password (44 bytes), so every byte of a long password counts and
+// passwords that differ only after byte 72 get different hashes. Such hashes
+// start with "$bcrypt-sha256$"; plain bcrypt hashes keep verifying and are
+// upgraded through NeedsRehash.
+func WithBcryptPreHash() BcryptOption {
+	return func(h *BcryptHasher) {
+		h.preHash = true
+	}
+}
+
+// NewBcryptHasher creates a bcrypt password hasher
+func NewBcryptHasher(cost int, opts ...BcryptOption) *BcryptHasher {
 	if cost < bcrypt.MinCost || cost > bcrypt.MaxCost {
 		cost = bcrypt.DefaultCost
 	}
-	return &PasswordService{cost: cost}
+	h := &BcryptHasher{cost: cost}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
 }
 
-// HashPassword hashes a password using bcrypt
-func (s *PasswordService) HashPassword(password string) (string, error) {
-	hash, err := bcrypt.GenerateFromPassword([]byte(password), s.cost)
+// Hash hashes a password using bcrypt
+func (h *BcryptHasher) Hash(password string) (string, error) {
+	if h.preHash {
+		hash, err := bcrypt.GenerateFromPassword(preHashPassword(password), h.cost)
+		if err != nil {
+			return "", err
+		}
+		return bcryptSHA256Prefix + string(hash), nil
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), h.cost)
 	if err != nil {
 		return "", err
 	}
 	return string(hash), nil
 }
 
-// ComparePassword compares a hashed password with a plain text password
-func (s *PasswordService) ComparePassword(hashedPassword, password string) bool {
-	err := bcrypt.CompareHashAndPassword([]byte(hashedPassword), []byte(password))
-	return err == nil
+// Compare compares a hashed password with a plain text password
+func (h *BcryptHasher) Compare(hash, password string) bool {
+	return comparePassword(hash, password)
+}
+
+// NeedsRehash reports whether hash is not a bcrypt hash of the configured
+// cost and pre-hash scheme
+func (h *BcryptHasher) NeedsRehash(hash string) bool {
+	bcryptHash, preHashed := strings.CutPrefix(hash, bcryptSHA256Prefix)
+	if preHashed != h.preHash {
+		return true
+	}
+	cost, err := bcrypt.Cost([]byte(bcryptHash))
+	return err != nil || cost != h.cost
+}
+
+// CalibrateCost benchmarks bcrypt on this machine and switches the hasher to
+// the highest cost whose hash takes at most target, clamped to bcrypt's
+// MinCost and MaxCost. It returns the chosen cost. Call it once at startup,
+// before the hasher is shared: it takes a few times target to run. Stored
+// hashes of another cost are upgraded on login through NeedsRehash.
+func (h *BcryptHasher) CalibrateCost(target time.Duration) int {
+	cost := bcrypt.MinCost
+	// Each step doubles the work; stop at the first cost that is too slow
+	for cost < bcrypt.MaxCost && bcryptDuration(cost+1) <= target {
+		cost++
+	}
+	h.cost = cost
+	return cost
+}
+
+// Cost returns the work factor of new hashes
+func (h *BcryptHasher) Cost() int {
+	return h.cost
+}
+
+// bcryptDuration measures one bcrypt hash at cost; tests replace it
+var bcryptDuration = func(cost int) time.Duration {
+	start := time.Now()
+	_, _ = bcrypt.GenerateFromPassword([]byte("calibrate-bcrypt-cost"), cost)
+	return time.Since(start)
+}
+
+// Password hash algorithms Compare can verify
+const (
+	AlgorithmBcrypt   = "bcrypt"
+	AlgorithmArgon2id = "argon2id"
+)
+
+// bcryptSHA256Prefix marks a bcrypt hash of the pre-hashed password
+// (WithBcryptPreHash); the plain bcrypt hash follows it
+const bcryptSHA256Prefix = "$bcrypt-sha256$"
+
+// preHashPassword is what pre-hashing bcrypt hashes: the base64 encoding of
+// SHA-256(password). 44 bytes fit in bcrypt's limit and, unlike the raw
+// digest, never contain the NUL bytes bcrypt implementations stop at.
+func preHashPassword(password string) []byte {
+	sum := sha256.Sum256([]byte(password))
+	return []byte(base64.StdEncoding.EncodeToString(sum[:]))
+}
+
+// HashAlgorithm detects the algorithm of an encoded password hash the same way
+// Compare does. It returns "" for hashes Compare cannot verify, e.g. an
+// unknown format or malformed parameters. Pre-hashed bcrypt hashes are
+// AlgorithmBcrypt too.
+func HashAlgorithm(hash string) string {
+	if strings.HasPrefix(hash, argon2idPrefix) {
+		if _, _, _, ok := decodeArgon2id(hash); ok {
+			return AlgorithmArgon2id
+		}
+		return ""
+	}
+	if _, err := bcrypt.Cost([]byte(strings.TrimPrefix(hash, bcryptSHA256Prefix))); err == nil {
+		return AlgorithmBcrypt
+	}
+	return ""
+}
+
+// comparePassword picks the algorithm from the hash prefix: "$argon2id$"
+// for Argon2id, "$bcrypt-sha256$" for bcrypt of the pre-hashed password,
+// anything else is treated as bcrypt ("$2a$", "$2b$"...)
+func comparePassword(hash, password string) bool {
+	if strings.HasPrefix(hash, argon2idPrefix) {
+		return compareArgon2id(hash, password)
+	}
+	if bcryptHash, ok := strings.CutPrefix(hash, bcryptSHA256Prefix); ok {
+		return bcrypt.CompareHashAndPassword([]byte(bcryptHash), preHashPassword(password)) == nil
+	}
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil
 }
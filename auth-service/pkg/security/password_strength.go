@@ -0,0 +1,302 @@
+package security
+
+import (
+	"math"
+	"strings"
+	"unicode"
+)
+
+// PasswordStrength is a zxcvbn-style estimate of how guessable a password is.
+// Unlike composition rules ("one digit, one symbol") it measures strength:
+// "P@ssw0rd1!" satisfies most rules and is still one of the first guesses.
+type PasswordStrength struct {
+	Score       int      // 0 (too guessable) to 4 (very unguessable)
+	Entropy     float64  // Estimated entropy in bits
+	Warning     string   // The most important problem, if any
+	Suggestions []string // Hints to show the user
+}
+
+// Score thresholds in bits. The estimate is more naive than zxcvbn's guess
+// counting, so the thresholds are set somewhat higher.
+var strengthThresholds = [4]float64{25, 35, 45, 60}
+
+// commonPasswords are the most frequent passwords and password words. The
+// list is short on purpose; it only has to catch the usual suspects.
+var commonPasswords = []string{
+	"password", "passw0rd", "123456", "12345678", "qwerty", "abc123", "letmein",
+	"welcome", "monkey", "dragon", "football", "baseball", "iloveyou", "admin",
+	"master", "sunshine", "princess", "shadow", "superman", "batman", "trustno1",
+	"starwars", "login", "secret", "hello", "freedom", "whatever", "michael",
+	"jennifer", "charlie", "jordan", "hunter", "ranger", "buster", "soccer",
+	"hockey", "killer", "george", "summer", "winter", "spring", "autumn",
+	"pepper", "cheese", "computer", "internet", "changeme", "default", "access",
+	"flower", "lovely", "mustang", "matrix", "ninja", "pokemon", "google",
+	"samsung", "apple", "orange", "banana", "chocolate", "love", "pass", "test",
+	"user", "root", "guest", "qazwsx", "asdfgh", "zxcvbn", "111111", "000000",
+}
+
+// keyboardRows are matched for keyboard walks such as "qwerty" or "asdf"
+var keyboardRows = []string{"qwertyuiop", "asdfghjkl", "zxcvbnm", "1234567890"}
+
+// leetSubstitutions undo l33t speak before dictionary lookups ("p@ssw0rd" -> "password")
+var leetSubstitutions = map[rune]rune{
+	'0': 'o', '1': 'i', '3': 'e', '4': 'a', '5': 's', '7': 't', '@': 'a', '$': 's', '!': 'i',
+}
+
+// Pattern kinds, used for feedback
+const (
+	patternDictionary = iota
+	patternUserInput
+	patternRepeat
+	patternSequence
+	patternKeyboard
+)
+
+// EstimatePasswordStrength scores a password. userInputs are values that
+// should not appear in it, such as the user's email and username.
+//
+// The password is split left to right into patterns and each pattern adds the
+// bits needed to guess it: a common word adds ~7 bits, a random lowercase
+// letter ~4.7.
+func EstimatePasswordStrength(password string, userInputs ...string) PasswordStrength {
+	runes := []rune(password)
+	normalized := normalizeLeet(runes)
+	pool := charsetSize(runes)
+	inputs := userInputTokens(userInputs)
+
+	var (
+		entropy  float64
+		found    = map[int]bool{}
+		usedLeet bool
+	)
+	for i := 0; i < len(runes); {
+		n, bits, pattern := matchPattern(runes, normalized, i, pool, inputs)
+		if n == 0 {
+			// No pattern: a single character from its character class
+			entropy += math.Log2(float64(charsetSize(runes[i : i+1])))
+			i++
+			continue
+		}
+		if pattern == patternDictionary || pattern == patternUserInput {
+			// Capitalisation and l33t make a word only slightly harder to guess
+			if string(runes[i:i+n]) != string(toLowerRunes(runes[i:i+n])) {
+				bits++
+			}
+			if string(normalized[i:i+n]) != string(toLowerRunes(runes[i:i+n])) {
+				bits++
+				usedLeet = true
+			}
+		}
+		entropy += bits
+		found[pattern] = true
+		i += n
+	}
+
+	strength := PasswordStrength{Entropy: entropy}
+	for _, threshold := range strengthThresholds {
+		if entropy >= threshold {
+			strength.Score++
+		}
+	}
+	strength.Warning, strength.Suggestions = strengthFeedback(strength.Score, len(runes), found, usedLeet)
+	return strength
+}
+
+// matchPattern finds the longest pattern starting at i. n is its length in
+// runes (0 when there is none) and bits its entropy.
+func matchPattern(runes, normalized []rune, i, pool int, inputs []string) (n int, bits float64, pattern int) {
+	rest := string(normalized[i:])
+
+	// The user's own email or username adds next to nothing
+	for _, input := range inputs {
+		if len(input) > n && strings.HasPrefix(rest, input) {
+			n, bits, pattern = len([]rune(input)), 2, patternUserInput
+		}
+	}
+	// A common password costs one guess per list entry
+	for _, word := range commonPasswords {
+		if len(word) > n && len(word) >= 4 && strings.HasPrefix(rest, word) {
+			n, bits, pattern = len([]rune(word)), math.Log2(float64(len(commonPasswords))), patternDictionary
+		}
+	}
+
+	// Repeats: "aaaa"
+	if k := repeatLength(runes, i); k >= 3 && k > n {
+		n, bits, pattern = k, math.Log2(float64(charsetSize(runes[i:i+1])))+math.Log2(float64(k)), patternRepeat
+	}
+	// Sequences: "abcd", "4321"
+	if k := sequenceLength(runes, i); k >= 3 && k > n {
+		n, bits, pattern = k, math.Log2(float64(pool))+math.Log2(float64(k))+1, patternSequence
+	}
+	// Keyboard walks: "qwerty", "asdf"
+	if k := keyboardLength(runes, i); k >= 4 && k > n {
+		n, bits, pattern = k, math.Log2(40)+math.Log2(float64(k)), patternKeyboard
+	}
+	return n, bits, pattern
+}
+
+func repeatLength(runes []rune, i int) int {
+	k := 1
+	for i+k < len(runes) && runes[i+k] == runes[i] {
+		k++
+	}
+	return k
+}
+
+func sequenceLength(runes []rune, i int) int {
+	if i+1 >= len(runes) {
+		return 1
+	}
+	delta := runes[i+1] - runes[i]
+	if delta != 1 && delta != -1 {
+		return 1
+	}
+	k := 2
+	for i+k < len(runes) && runes[i+k]-runes[i+k-1] == delta {
+		k++
+	}
+	return k
+}
+
+func keyboardLength(runes []rune, i int) int {
+	lower := string(toLowerRunes(runes[i:]))
+	best := 0
+	for _, row := range keyboardRows {
+		for start := 0; start < len(row); start++ {
+			k := 0
+			for k < len(lower) && start+k < len(row) && lower[k] == row[start+k] {
+				k++
+			}
+			if k > best {
+				best = k
+			}
+		}
+	}
+	return best
+}
+
+// charsetSize returns the combined size of the character classes in runes
+func charsetSize(runes []rune) int {
+	var lower, upper, digit, symbol, other bool
+	for _, r := range runes {
+		switch {
+		case r >= 'a' && r <= 'z':
+			lower = true
+		case r >= 'A' && r <= 'Z':
+			upper = true
+		case r >= '0' && r <= '9':
+			digit = true
+		case r < unicode.MaxASCII:
+			symbol = true
+		default:
+			other = true
+		}
+	}
+	size := 0
+	for _, class := range []struct {
+		used bool
+		size int
+	}{{lower, 26}, {upper, 26}, {digit, 10}, {symbol, 33}, {other, 100}} {
+		if class.used {
+			size += class.size
+		}
+	}
+	if size == 0 {
+		return 1
+	}
+	return size
+}
+
+// userInputTokens splits emails and usernames into the parts to look for,
+// e.g. "jane.doe@example.com" -> "jane.doe", "jane", "doe", "example"
+func userInputTokens(userInputs []string) []string {
+	var tokens []string
+	for _, input := range userInputs {
+		input = strings.ToLower(input)
+		local, domain, _ := strings.Cut(input, "@")
+		parts := []string{local}
+		parts = append(parts, strings.FieldsFunc(local, isSeparator)...)
+		if domain != "" {
+			parts = append(parts, strings.Split(domain, ".")[0])
+		}
+		for _, part := range parts {
+			if len(part) >= 3 {
+				tokens = append(tokens, part)
+			}
+		}
+	}
+	return tokens
+}
+
+func isSeparator(r rune) bool {
+	return r == '.' || r == '_' || r == '-' || r == '+'
+}
+
+func toLowerRunes(runes []rune) []rune {
+	out := make([]rune, len(runes))
+	for i, r := range runes {
+		out[i] = unicode.ToLower(r)
+	}
+	return out
+}
+
+// normalizeLeet lowercases runes and undoes l33t substitutions. It maps rune
+// by rune, so indexes into the result match indexes into the password.
+func normalizeLeet(runes []rune) []rune {
+	out := make([]rune, len(runes))
+	for i, r := range runes {
+		r = unicode.ToLower(r)
+		if sub, ok := leetSubstitutions[r]; ok {
+			r = sub
+		}
+		out[i] = r
+	}
+	return out
+}
+
+// strengthFeedback builds the warning and suggestions for weak passwords
+func strengthFeedback(score, length int, found map[int]bool, usedLeet bool) (string, []string) {
+	if score >= 3 {
+		return "", nil
+	}
+
+	var warning string
+	var suggestions []string
+	if found[patternDictionary] {
+		warning = "This is similar to a commonly used password"
+		suggestions = append(suggestions, "Avoid common words and passwords")
+	}
+	if found[patternUserInput] {
+		if warning == "" {
+			warning = "The password contains your email or username"
+		}
+		suggestions = append(suggestions, "Do not use your email or username in the password")
+	}
+	if found[patternRepeat] {
+		if warning == "" {
+			warning = `Repeated characters like "aaa" are easy to guess`
+		}
+		suggestions = append(suggestions, "Avoid repeated characters")
+	}
+	if found[patternSequence] {
+		if warning == "" {
+			warning = `Sequences like "abc" or "123" are easy to guess`
+		}
+		suggestions = append(suggestions, "Avoid sequences")
+	}
+	if found[patternKeyboard] {
+		if warning == "" {
+			warning = `Keyboard patterns like "qwerty" are easy to guess`
+		}
+		suggestions = append(suggestions, "Avoid keyboard patterns")
+	}
+	if usedLeet {
+		suggestions = append(suggestions, `Substitutions like "@" for "a" do not add much strength`)
+	}
+	if length < 12 {
+		suggestions = append(suggestions, "Use a longer password; a few unrelated words work well")
+	} else {
+		suggestions = append(suggestions, "Add another word or two; uncommon words are better")
+	}
+	return warning, suggestions
+}
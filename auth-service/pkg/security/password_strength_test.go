@@ -0,0 +1,52 @@
+package security
+
+import "testing"
+
+func TestEstimatePasswordStrength(t *testing.T) {
+	tests := []struct {
+		password   string
+		userInputs []string
+		maxScore   int
+		minScore   int
+		warning    bool
+	}{
+		{"password", nil, 0, 0, true},
+		{"P@ssw0rd1!", nil, 0, 0, true},
+		{"aaaaaaaaaaaa", nil, 0, 0, true},
+		{"abcdefgh", nil, 0, 0, true},
+		{"qwertyuiop12", nil, 0, 0, true},
+		{"jane1234", []string{"jane@example.com", "jane"}, 0, 0, true},
+		{"xK9#mQ2$vL", nil, 2, 2, false},
+		{"correct horse battery staple", nil, 4, 4, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.password, func(t *testing.T) {
+			got := EstimatePasswordStrength(tt.password, tt.userInputs...)
+			if got.Score < tt.minScore || got.Score > tt.maxScore {
+				t.Errorf("score = %d (%.1f bits), want %d-%d", got.Score, got.Entropy, tt.minScore, tt.maxScore)
+			}
+			if (got.Warning != "") != tt.warning {
+				t.Errorf("warning = %q", got.Warning)
+			}
+			if got.Score < 3 && len(got.Suggestions) == 0 {
+				t.Error("weak passwords should come with suggestions")
+			}
+		})
+	}
+}
+
+func TestEstimatePasswordStrengthUserInputs(t *testing.T) {
+	without := EstimatePasswordStrength("janedoe-summit")
+	with := EstimatePasswordStrength("janedoe-summit", "jane.doe@example.com", "janedoe")
+	if with.Entropy >= without.Entropy {
+		t.Errorf("entropy with user inputs = %.1f, without = %.1f", with.Entropy, without.Entropy)
+	}
+}
+
+func TestEstimatePasswordStrengthHandlesUnicode(t *testing.T) {
+	// Lowercasing must not change the rune count the estimator indexes by
+	if got := EstimatePasswordStrength("İstanbul-Kırmızı-Şehir"); got.Score == 0 {
+		t.Errorf("score = %d", got.Score)
+	}
+}
@@ -0,0 +1,225 @@
+package security
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// testArgon2Params keeps the tests fast; production uses much more memory
+var testArgon2Params = Argon2Params{Memory: 64, Time: 1, Parallelism: 1}
+
+func TestArgon2idHashAndCompare(t *testing.T) {
+	h := NewArgon2idHasher(testArgon2Params)
+
+	// Longer than bcrypt's 72 byte limit; every byte must count
+	password := strings.Repeat("correct-horse-", 10)
+	hash, err := h.Hash(password)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(hash, "$argon2id$v=19$m=64,t=1,p=1$") {
+		t.Errorf("hash = %q", hash)
+	}
+	if !h.Compare(hash, password) {
+		t.Error("correct password rejected")
+	}
+	if h.Compare(hash, password[:len(password)-1]+"X") {
+		t.Error("password differing after byte 72 accepted")
+	}
+
+	again, _ := h.Hash(password)
+	if again == hash {
+		t.Error("two hashes of the same password share a salt")
+	}
+}
+
+func TestCompareDetectsAlgorithmFromHash(t *testing.T) {
+	bcryptHasher := NewBcryptHasher(4)
+	argon2Hasher := NewArgon2idHasher(testArgon2Params)
+
+	bcryptHash, err := bcryptHasher.Hash("correct-horse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	argon2Hash, err := argon2Hasher.Hash("correct-horse")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// After switching the default either way, old hashes still verify
+	for name, h := range map[string]PasswordHasher{"bcrypt": bcryptHasher, "argon2id": argon2Hasher} {
+		if !h.Compare(bcryptHash, "correct-horse") || !h.Compare(argon2Hash, "correct-horse") {
+			t.Errorf("%s hasher rejected a hash of the other algorithm", name)
+		}
+		if h.Compare(bcryptHash, "wrong") || h.Compare(argon2Hash, "wrong") {
+			t.Errorf("%s hasher accepted a wrong password", name)
+		}
+	}
+
+	// Hashes made with other parameters verify with the stored parameters
+	stronger := NewArgon2idHasher(Argon2Params{Memory: 128, Time: 2, Parallelism: 2})
+	if !stronger.Compare(argon2Hash, "correct-horse") {
+		t.Error("hash with older parameters rejected")
+	}
+}
+
+func TestCompareRejectsMalformedArgon2idHashes(t *testing.T) {
+	h := NewArgon2idHasher(testArgon2Params)
+	for _, hash := range []string{
+		"$argon2id$",
+		"$argon2id$v=18$m=64,t=1,p=1$c2FsdHNhbHQ$a2V5",
+		"$argon2id$v=19$m=64,t=0,p=1$c2FsdHNhbHQ$a2V5",
+		"$argon2id$v=19$m=64,t=1,p=1$!!!$a2V5",
+		"$argon2id$v=19$m=64,t=1,p=1$c2FsdHNhbHQ$",
+		"",
+	} {
+		if h.Compare(hash, "correct-horse") {
+			t.Errorf("malformed hash %q accepted", hash)
+		}
+	}
+}
+
+func TestHashAlgorithm(t *testing.T) {
+	bcryptHash, _ := NewBcryptHasher(4).Hash("correct-horse")
+	argon2Hash, _ := NewArgon2idHasher(testArgon2Params).Hash("correct-horse")
+
+	for hash, want := range map[string]string{
+		bcryptHash: AlgorithmBcrypt,
+		argon2Hash: AlgorithmArgon2id,
+		"$argon2id$v=19$m=64,t=0,p=1$c2FsdHNhbHQ$a2V5": "",
+		"$2a$99$" + strings.Repeat("a", 53):            "",
+		"5f4dcc3b5aa765d61d8327deb882cf99":             "", // MD5
+		"":                                             "",
+	} {
+		if got := HashAlgorithm(hash); got != want {
+			t.Errorf("HashAlgorithm(%q) = %q, want %q", hash, got, want)
+		}
+	}
+}
+
+func TestNeedsRehash(t *testing.T) {
+	bcrypt4 := NewBcryptHasher(4)
+	argon2Hasher := NewArgon2idHasher(testArgon2Params)
+
+	preHash4 := NewBcryptHasher(4, WithBcryptPreHash())
+
+	bcryptHash, _ := bcrypt4.Hash("correct-horse")
+	preHashed, _ := preHash4.Hash("correct-horse")
+	argon2Hash, _ := argon2Hasher.Hash("correct-horse")
+
+	tests := []struct {
+		name   string
+		hasher PasswordHasher
+		hash   string
+		want   bool
+	}{
+		{"bcrypt same cost", bcrypt4, bcryptHash, false},
+		{"bcrypt higher cost", NewBcryptHasher(5), bcryptHash, true},
+		{"bcrypt to argon2id", argon2Hasher, bcryptHash, true},
+		{"bcrypt to pre-hashed bcrypt", preHash4, bcryptHash, true},
+		{"pre-hashed bcrypt same cost", preHash4, preHashed, false},
+		{"pre-hashed bcrypt higher cost", NewBcryptHasher(5, WithBcryptPreHash()), preHashed, true},
+		{"pre-hashed bcrypt to bcrypt", bcrypt4, preHashed, true},
+		{"argon2id same params", argon2Hasher, argon2Hash, false},
+		{"argon2id more memory", NewArgon2idHasher(Argon2Params{Memory: 128, Time: 1, Parallelism: 1}), argon2Hash, true},
+		{"argon2id to bcrypt", bcrypt4, argon2Hash, true},
+		{"garbage", argon2Hasher, "not-a-hash", true},
+	}
+	for _, tt := range tests {
+		if got := tt.hasher.NeedsRehash(tt.hash); got != tt.want {
+			t.Errorf("%s: NeedsRehash = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestBcryptPreHashCountsEveryByte(t *testing.T) {
+	h := NewBcryptHasher(4, WithBcryptPreHash())
+
+	// Two passwords longer than bcrypt's 72 bytes that differ only after byte 72
+	prefix := strings.Repeat("correct-horse-", 6)[:72]
+	password, other := prefix+"battery-staple", prefix+"battery-stapler"
+
+	// Plain bcrypt cannot hash them at all
+	if _, err := NewBcryptHasher(4).Hash(password); err == nil {
+		t.Error("plain bcrypt hashed a password longer than 72 bytes")
+	}
+
+	hash, err := h.Hash(password)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(hash, "$bcrypt-sha256$$2a$04$") {
+		t.Errorf("hash = %q, want a pre-hashed cost 4 bcrypt hash", hash)
+	}
+	if !h.Compare(hash, password) {
+		t.Error("correct password rejected")
+	}
+	if h.Compare(hash, other) || h.Compare(hash, prefix) {
+		t.Error("password differing after byte 72 accepted")
+	}
+	if got := HashAlgorithm(hash); got != AlgorithmBcrypt {
+		t.Errorf("HashAlgorithm = %q, want bcrypt", got)
+	}
+
+	// Hashes of either scheme verify with any hasher
+	plain, _ := NewBcryptHasher(4).Hash("correct-horse")
+	for name, verifier := range map[string]PasswordHasher{"pre-hashing bcrypt": h, "plain bcrypt": NewBcryptHasher(4), "argon2id": NewArgon2idHasher(testArgon2Params)} {
+		if !verifier.Compare(plain, "correct-horse") || !verifier.Compare(hash, password) {
+			t.Errorf("%s hasher rejected a hash of the other scheme", name)
+		}
+	}
+}
+
+func TestBcryptCalibrateCost(t *testing.T) {
+	// A machine where cost 4 takes 1ms and every step doubles it
+	measured := map[int]bool{}
+	defer func(orig func(int) time.Duration) { bcryptDuration = orig }(bcryptDuration)
+	bcryptDuration = func(cost int) time.Duration {
+		measured[cost] = true
+		return time.Millisecond << (cost - bcrypt.MinCost)
+	}
+
+	tests := []struct {
+		target time.Duration
+		want   int
+	}{
+		{100 * time.Millisecond, 10}, // 64ms; cost 11 would take 128ms
+		{64 * time.Millisecond, 10},
+		{time.Nanosecond, bcrypt.MinCost},
+		{time.Duration(1 << 62), bcrypt.MaxCost},
+	}
+	for _, tt := range tests {
+		h := NewBcryptHasher(12)
+		if got := h.CalibrateCost(tt.target); got != tt.want || h.Cost() != tt.want {
+			t.Errorf("CalibrateCost(%s) = %d (Cost %d), want %d", tt.target, got, h.Cost(), tt.want)
+		}
+	}
+	if measured[bcrypt.MaxCost+1] {
+		t.Error("measured a cost above bcrypt.MaxCost")
+	}
+}
+
+func TestBcryptCalibratedCostIsUsed(t *testing.T) {
+	defer func(orig func(int) time.Duration) { bcryptDuration = orig }(bcryptDuration)
+	bcryptDuration = func(cost int) time.Duration { return time.Millisecond << (cost - bcrypt.MinCost) }
+
+	h := NewBcryptHasher(12)
+	h.CalibrateCost(2 * time.Millisecond) // cost 5
+	hash, err := h.Hash("correct-horse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cost, _ := bcrypt.Cost([]byte(hash)); cost != 5 {
+		t.Errorf("hash cost = %d, want the calibrated 5", cost)
+	}
+	if h.NeedsRehash(hash) {
+		t.Error("hash with the calibrated cost needs rehash")
+	}
+	old, _ := NewBcryptHasher(12).Hash("correct-horse")
+	if !h.NeedsRehash(old) {
+		t.Error("hash with the configured cost does not need rehash after calibration")
+	}
+}
@@ -0,0 +1,35 @@
+package security
+
+import "testing"
+
+func TestVerifyRequestSignature(t *testing.T) {
+	secret := []byte("shared-secret")
+	body := []byte(`{"ids":["1"]}`)
+	sig := SignRequest(secret, "POST", "/internal/users/batch", body, 1700000000)
+
+	if !VerifyRequestSignature(secret, "post", "/internal/users/batch", body, 1700000000, sig) {
+		t.Fatal("valid signature rejected")
+	}
+
+	tampered := []struct {
+		name      string
+		secret    []byte
+		method    string
+		path      string
+		body      []byte
+		timestamp int64
+	}{
+		{"secret", []byte("other-secret"), "POST", "/internal/users/batch", body, 1700000000},
+		{"method", secret, "PUT", "/internal/users/batch", body, 1700000000},
+		{"path", secret, "POST", "/internal/users", body, 1700000000},
+		{"body", secret, "POST", "/internal/users/batch", []byte(`{"ids":["2"]}`), 1700000000},
+		{"timestamp", secret, "POST", "/internal/users/batch", body, 1700000001},
+	}
+	for _, tt := range tampered {
+		t.Run(tt.name, func(t *testing.T) {
+			if VerifyRequestSignature(tt.secret, tt.method, tt.path, tt.body, tt.timestamp, sig) {
+				t.Error("tampered request accepted")
+			}
+		})
+	}
+}
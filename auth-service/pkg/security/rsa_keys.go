@@ -0,0 +1,35 @@
+package security
+
+import (
+	"crypto/rsa"
+	"fmt"
+	"os"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// LoadRSAPrivateKey reads a PEM encoded (PKCS#1 or PKCS#8) RSA private key
+func LoadRSAPrivateKey(path string) (*rsa.PrivateKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	key, err := jwt.ParseRSAPrivateKeyFromPEM(data)
+	if err != nil {
+		return nil, fmt.Errorf("parse RSA private key %s: %w", path, err)
+	}
+	return key, nil
+}
+
+// LoadRSAPublicKey reads a PEM encoded (PKIX or PKCS#1) RSA public key
+func LoadRSAPublicKey(path string) (*rsa.PublicKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	key, err := jwt.ParseRSAPublicKeyFromPEM(data)
+	if err != nil {
+		return nil, fmt.Errorf("parse RSA public key %s: %w", path, err)
+	}
+	return key, nil
+}
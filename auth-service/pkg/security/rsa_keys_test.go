@@ -0,0 +1,47 @@
+package security
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadRSAKeys(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	publicDER, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dir := t.TempDir()
+	privatePath := filepath.Join(dir, "private.pem")
+	publicPath := filepath.Join(dir, "public.pem")
+	os.WriteFile(privatePath, pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)}), 0o600)
+	os.WriteFile(publicPath, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER}), 0o644)
+
+	private, err := LoadRSAPrivateKey(privatePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	public, err := LoadRSAPublicKey(publicPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !private.PublicKey.Equal(public) {
+		t.Error("loaded public key does not match the private key")
+	}
+
+	if _, err := LoadRSAPrivateKey(publicPath); err == nil {
+		t.Error("public key accepted as private key")
+	}
+	if _, err := LoadRSAPublicKey(filepath.Join(dir, "missing.pem")); err == nil {
+		t.Error("missing file accepted")
+	}
+}
@@ -0,0 +1,133 @@
+package security
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// SecretProvider - HS256 secret'larının kaynağı (env, Vault, KMS...)
+// JWTService secret'ları buradan alır ve RefreshKeys ile periyodik olarak yeniden okur;
+// böylece secret deploy gerekmeden kaynağında rotate edilebilir.
+type SecretProvider interface {
+	// CurrentSigningKey - Yeni token'ların imzalanacağı secret
+	CurrentSigningKey(ctx context.Context) (string, error)
+	// VerificationKeys - Rotation'dan önceki secret'lar (en yeni önce); sadece doğrulamada kabul edilir
+	VerificationKeys(ctx context.Context) ([]string, error)
+}
+
+// ErrEmptySigningKey - SecretProvider boş bir imzalama secret'ı döndü
+var ErrEmptySigningKey = errors.New("secret provider returned an empty signing key")
+
+// StaticSecretProvider - Başlangıçta verilen secret'ları döner (JWT_SECRET, JWT_PREVIOUS_SECRETS)
+// Değerler değişmediği için RefreshKeys hiçbir şeyi rotate etmez.
+type StaticSecretProvider struct {
+	current  string
+	previous []string
+}
+
+// NewStaticSecretProvider - previous: eski secret'lar, en yeni önce
+func NewStaticSecretProvider(current string, previous ...string) *StaticSecretProvider {
+	return &StaticSecretProvider{current: current, previous: previous}
+}
+
+func (p *StaticSecretProvider) CurrentSigningKey(ctx context.Context) (string, error) {
+	return p.current, nil
+}
+
+func (p *StaticSecretProvider) VerificationKeys(ctx context.Context) ([]string, error) {
+	return p.previous, nil
+}
+
+// NewJWTServiceWithProvider - HS256 secret'larını provider'dan alan JWTService oluşturur
+// Provider'a ulaşılamazsa hata döner: servis secret'sız başlamamalı.
+func NewJWTServiceWithProvider(ctx context.Context, provider SecretProvider, accessTokenTTL, refreshTokenTTL time.Duration, opts ...JWTOption) (*JWTService, error) {
+	current, previous, err := fetchSecrets(ctx, provider)
+	if err != nil {
+		return nil, err
+	}
+	s := newJWTService(jwt.SigningMethodHS256, newHMACSigningKey(current), accessTokenTTL, refreshTokenTTL, opts)
+	s.provider = provider
+	s.trust(previous)
+	return s, nil
+}
+
+// RefreshKeys - Secret'ları provider'dan yeniden okur
+// Yeni bir current secret aktif anahtar olur (eskisi RotateKey'deki gibi access token TTL'i
+// boyunca doğrulamada kalır); VerificationKeys'teki secret'lar listelendikleri sürece kabul edilir.
+// Provider hata verirse mevcut anahtarlar değişmez. RS256 servislerinde bir şey yapmaz.
+func (s *JWTService) RefreshKeys(ctx context.Context) error {
+	if s.provider == nil {
+		return nil
+	}
+	current, previous, err := fetchSecrets(ctx, s.provider)
+	if err != nil {
+		return err
+	}
+	s.trust(previous)
+	return s.RotateKey(current)
+}
+
+// StartKeyRefresh - RefreshKeys'i ctx iptal edilene kadar her interval'da çalıştırır
+// Hatalar onError'a verilir ve bir sonraki turda tekrar denenir.
+func (s *JWTService) StartKeyRefresh(ctx context.Context, interval time.Duration, onError func(error)) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			if err := s.RefreshKeys(ctx); err != nil && ctx.Err() == nil {
+				onError(err)
+			}
+		}
+	}()
+}
+
+func fetchSecrets(ctx context.Context, provider SecretProvider) (current string, previous []string, err error) {
+	if current, err = provider.CurrentSigningKey(ctx); err != nil {
+		return "", nil, err
+	}
+	if current == "" {
+		return "", nil, ErrEmptySigningKey
+	}
+	if previous, err = provider.VerificationKeys(ctx); err != nil {
+		return "", nil, err
+	}
+	return current, previous, nil
+}
+
+// trust - Eski secret'ları sadece doğrulama için key ring'e ekler
+// Zaten ringde olanların süresi uzatılır: provider listeledikçe kabul edilirler,
+// listeden çıkınca access token TTL'i sonra atılırlar.
+func (s *JWTService) trust(secrets []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	for _, secret := range secrets {
+		if secret == "" {
+			continue
+		}
+		key := newHMACSigningKey(secret)
+		if key.id == s.keys[0].id {
+			continue
+		}
+		key.retiredAt = now
+		found := false
+		for i, k := range s.keys {
+			if k.id == key.id {
+				s.keys[i] = key
+				found = true
+			}
+		}
+		if !found {
+			s.keys = append(s.keys, key)
+		}
+	}
+}
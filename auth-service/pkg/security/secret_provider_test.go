@@ -0,0 +1,141 @@
+package security
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// fakeSecretProvider serves secrets the test can change between refreshes
+type fakeSecretProvider struct {
+	mu       sync.Mutex
+	current  string
+	previous []string
+	err      error
+}
+
+func (p *fakeSecretProvider) set(current string, previous ...string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.current, p.previous = current, previous
+}
+
+func (p *fakeSecretProvider) CurrentSigningKey(ctx context.Context) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.current, p.err
+}
+
+func (p *fakeSecretProvider) VerificationKeys(ctx context.Context) ([]string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.previous, p.err
+}
+
+func issueToken(t *testing.T, s *JWTService) string {
+	t.Helper()
+	token, err := s.GenerateAccessToken(uuid.New(), "jane@example.com", "jane", "user", nil, uuid.New(), uuid.Nil, 0, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return token
+}
+
+func TestNewJWTServiceWithProvider(t *testing.T) {
+	provider := &fakeSecretProvider{current: "current-secret", previous: []string{"old-secret"}}
+	s, err := NewJWTServiceWithProvider(context.Background(), provider, time.Minute, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if kid := tokenKID(t, issueToken(t, s)); kid != keyID([]byte("current-secret")) {
+		t.Errorf("kid = %q, want the current secret's ID", kid)
+	}
+	// Tokens signed with a previous secret still validate
+	if _, err := s.ValidateToken(issueToken(t, NewJWTService("old-secret", time.Minute, time.Hour))); err != nil {
+		t.Errorf("token signed with a previous secret rejected: %v", err)
+	}
+
+	provider.set("")
+	if _, err := NewJWTServiceWithProvider(context.Background(), provider, time.Minute, time.Hour); !errors.Is(err, ErrEmptySigningKey) {
+		t.Errorf("empty secret: got %v, want ErrEmptySigningKey", err)
+	}
+	provider.err = errors.New("vault unavailable")
+	if _, err := NewJWTServiceWithProvider(context.Background(), provider, time.Minute, time.Hour); err == nil {
+		t.Error("expected the provider error")
+	}
+}
+
+func TestRefreshKeysPicksUpRotation(t *testing.T) {
+	provider := &fakeSecretProvider{current: "old-secret"}
+	s, err := NewJWTServiceWithProvider(context.Background(), provider, time.Minute, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	before := issueToken(t, s)
+
+	provider.set("new-secret", "old-secret")
+	if err := s.RefreshKeys(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	after := issueToken(t, s)
+	if kid := tokenKID(t, after); kid != keyID([]byte("new-secret")) {
+		t.Errorf("kid = %q, want the new secret's ID", kid)
+	}
+	for name, token := range map[string]string{"before": before, "after": after} {
+		if _, err := s.ValidateToken(token); err != nil {
+			t.Errorf("token issued %s rotation rejected: %v", name, err)
+		}
+	}
+
+	// A failing provider leaves the keys untouched
+	provider.err = errors.New("vault unavailable")
+	if err := s.RefreshKeys(context.Background()); err == nil {
+		t.Error("expected the provider error")
+	}
+	if _, err := s.ValidateToken(after); err != nil {
+		t.Errorf("token rejected after a failed refresh: %v", err)
+	}
+}
+
+func TestRefreshKeysKeepsListedVerificationKeys(t *testing.T) {
+	provider := &fakeSecretProvider{current: "new-secret", previous: []string{"old-secret"}}
+	s, err := NewJWTServiceWithProvider(context.Background(), provider, time.Minute, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	old := issueToken(t, NewJWTService("old-secret", time.Hour, time.Hour))
+
+	// Still listed: the key stays trusted past one access token lifetime
+	s.keys[1].retiredAt = time.Now().Add(-time.Hour)
+	if err := s.RefreshKeys(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.ValidateToken(old); err != nil {
+		t.Errorf("listed verification key rejected: %v", err)
+	}
+
+	// No longer listed: dropped once its tokens have expired
+	provider.set("new-secret")
+	if err := s.RefreshKeys(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	s.keys[1].retiredAt = time.Now().Add(-time.Hour)
+	if _, err := s.ValidateToken(old); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("unlisted key: got %v, want ErrInvalidToken", err)
+	}
+}
+
+func TestNewJWTServiceUsesStaticProvider(t *testing.T) {
+	s := NewJWTService("test-secret", time.Minute, time.Hour)
+	token := issueToken(t, s)
+	if err := s.RefreshKeys(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(s.keys) != 1 || tokenKID(t, issueToken(t, s)) != tokenKID(t, token) {
+		t.Error("refreshing a static provider changed the keys")
+	}
+}
@@ -0,0 +1,27 @@
+package security
+
+import "testing"
+
+func TestGenerateOpaqueTokenIsUnique(t *testing.T) {
+	a, err := GenerateOpaqueToken(32)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := GenerateOpaqueToken(32)
+	if a == b {
+		t.Fatal("two generated tokens are identical")
+	}
+	if len(a) != 43 {
+		t.Fatalf("len = %d, want 43 for 32 random bytes", len(a))
+	}
+}
+
+func TestHashTokenIsDeterministicAndHidesInput(t *testing.T) {
+	h := HashToken("secret-token")
+	if h != HashToken("secret-token") {
+		t.Fatal("hash is not deterministic")
+	}
+	if h == "secret-token" || len(h) != 64 {
+		t.Fatalf("unexpected hash %q", h)
+	}
+}
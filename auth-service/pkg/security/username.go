@@ -0,0 +1,153 @@
+package security
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Reasons a UsernameError gives for rejecting a username
+const (
+	UsernameReasonReserved    = "reserved"
+	UsernameReasonInvisible   = "invisible_characters"
+	UsernameReasonMixedScript = "mixed_scripts"
+	UsernameReasonConfusable  = "confusable"
+)
+
+// UsernameError is returned by UsernameValidator.Validate
+type UsernameError struct {
+	Reason  string // e.g. UsernameReasonReserved
+	Message string // Safe to show to the user
+}
+
+func (e *UsernameError) Error() string { return "username not allowed: " + e.Reason }
+
+// reservedUsernames can never be registered: they impersonate the service or
+// its staff. Deployments add their own terms (including offensive ones) with
+// NewUsernameValidator.
+var reservedUsernames = []string{
+	"admin", "administrator", "root", "system", "sysadmin", "superuser",
+	"support", "help", "helpdesk", "security", "staff", "moderator", "mod",
+	"owner", "official", "service", "api", "auth", "login", "signup",
+	"register", "account", "accounts", "billing", "info", "contact",
+	"noreply", "postmaster", "webmaster", "hostmaster", "abuse", "null",
+	"undefined", "anonymous", "everyone", "me", "self",
+}
+
+// confusables maps Cyrillic and Greek letters to the Latin letters they are
+// indistinguishable from in most fonts, so "аdmin" with a Cyrillic "а" is
+// recognized as "admin"
+var confusables = map[rune]rune{
+	// Cyrillic
+	'а': 'a', 'в': 'b', 'е': 'e', 'к': 'k', 'м': 'm', 'н': 'h', 'о': 'o',
+	'р': 'p', 'с': 'c', 'т': 't', 'у': 'y', 'х': 'x', 'ѕ': 's', 'і': 'i',
+	'ј': 'j', 'ԁ': 'd', 'ԛ': 'q', 'ԝ': 'w', 'һ': 'h', 'ӏ': 'l',
+	// Greek
+	'α': 'a', 'β': 'b', 'ε': 'e', 'η': 'n', 'ι': 'i', 'κ': 'k', 'ν': 'v',
+	'ο': 'o', 'ρ': 'p', 'τ': 't', 'υ': 'u', 'χ': 'x', 'ϲ': 'c', 'ϳ': 'j',
+}
+
+// usernameSeparators are ignored when comparing against the blocklist, so
+// "ad.min" and "ad_min" are as reserved as "admin"
+var usernameSeparators = strings.NewReplacer(".", "", "_", "", "-", "")
+
+// UsernameValidator rejects reserved usernames and usernames that could be
+// mistaken for another one
+type UsernameValidator struct {
+	blocked map[string]bool
+}
+
+// NewUsernameValidator blocks the built-in reserved usernames plus extra
+func NewUsernameValidator(extra ...string) *UsernameValidator {
+	v := &UsernameValidator{blocked: make(map[string]bool)}
+	for _, name := range append(reservedUsernames, extra...) {
+		if key := blocklistKey(name); key != "" {
+			v.blocked[key] = true
+		}
+	}
+	return v
+}
+
+// Normalize returns the canonical form of a username: surrounding space
+// trimmed and compatibility characters composed (NFKC), so fullwidth
+// "ａｄｍｉｎ" becomes "admin". Case is kept for display.
+func (v *UsernameValidator) Normalize(username string) string {
+	return strings.TrimSpace(norm.NFKC.String(username))
+}
+
+// Validate returns the normalized username, or a *UsernameError if it may
+// not be registered. Reserved names match regardless of case and separators.
+func (v *UsernameValidator) Validate(username string) (string, error) {
+	normalized := v.Normalize(username)
+
+	// Zero-width and other invisible characters make two usernames look identical
+	for _, r := range normalized {
+		if unicode.Is(unicode.Cf, r) || unicode.IsControl(r) {
+			return "", &UsernameError{Reason: UsernameReasonInvisible, Message: "Username must not contain invisible characters"}
+		}
+	}
+
+	// Latin mixed with Cyrillic or Greek is the classic spoofing trick ("pаypal")
+	scripts := usernameScripts(normalized)
+	if len(scripts) > 1 {
+		return "", &UsernameError{Reason: UsernameReasonMixedScript, Message: "Username must not mix letters from different alphabets"}
+	}
+
+	// A username written entirely in lookalike letters ("асе") reads as a Latin one
+	if scripts[unicode.Cyrillic] || scripts[unicode.Greek] {
+		if skeleton, ok := latinSkeleton(normalized); ok {
+			if v.blocked[blocklistKey(skeleton)] {
+				return "", reservedError()
+			}
+			return "", &UsernameError{Reason: UsernameReasonConfusable, Message: "Username looks like a username in Latin letters"}
+		}
+	}
+
+	if v.blocked[blocklistKey(normalized)] {
+		return "", reservedError()
+	}
+	return normalized, nil
+}
+
+func reservedError() *UsernameError {
+	return &UsernameError{Reason: UsernameReasonReserved, Message: "This username is reserved"}
+}
+
+// blocklistKey is the form usernames are compared in against the blocklist
+func blocklistKey(username string) string {
+	return usernameSeparators.Replace(cases.Fold().String(strings.TrimSpace(norm.NFKC.String(username))))
+}
+
+// usernameScripts returns which of the Latin, Cyrillic and Greek scripts the
+// username's letters are written in. These are the scripts whose letters are
+// mistaken for each other; other scripts are not restricted.
+func usernameScripts(username string) map[*unicode.RangeTable]bool {
+	scripts := make(map[*unicode.RangeTable]bool)
+	for _, r := range username {
+		for _, script := range []*unicode.RangeTable{unicode.Latin, unicode.Cyrillic, unicode.Greek} {
+			if unicode.Is(script, r) {
+				scripts[script] = true
+			}
+		}
+	}
+	return scripts
+}
+
+// latinSkeleton spells the username with the Latin letters its letters look
+// like. It reports false if some letter has no Latin lookalike.
+func latinSkeleton(username string) (string, bool) {
+	var b strings.Builder
+	for _, r := range cases.Fold().String(username) {
+		if unicode.IsLetter(r) {
+			latin, ok := confusables[r]
+			if !ok {
+				return "", false
+			}
+			r = latin
+		}
+		b.WriteRune(r)
+	}
+	return b.String(), true
+}
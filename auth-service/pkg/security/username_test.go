@@ -0,0 +1,48 @@
+package security
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestUsernameValidator(t *testing.T) {
+	v := NewUsernameValidator("Badword")
+
+	tests := []struct {
+		name     string
+		username string
+		want     string // normalized username, or empty when rejected
+		reason   string
+	}{
+		{"plain", "jane_doe", "jane_doe", ""},
+		{"keeps case, trims space", "  JaneDoe ", "JaneDoe", ""},
+		{"fullwidth is folded", "ｊａｎｅ", "jane", ""},
+		{"other scripts", "Ελένη", "Ελένη", ""},
+		{"reserved", "admin", "", UsernameReasonReserved},
+		{"reserved in another case", "ROOT", "", UsernameReasonReserved},
+		{"reserved with separators", "Ad.Min", "", UsernameReasonReserved},
+		{"reserved in fullwidth", "ｓｕｐｐｏｒｔ", "", UsernameReasonReserved},
+		{"configured term", "BADWORD", "", UsernameReasonReserved},
+		{"reserved in lookalike letters", "аdmin", "", UsernameReasonMixedScript},
+		{"whole-script lookalike of a reserved name", "гооt", "", UsernameReasonMixedScript},
+		{"whole-script lookalike", "асе", "", UsernameReasonConfusable},
+		{"whole-script lookalike of reserved", "ѕуѕтем", "", UsernameReasonReserved},
+		{"zero-width space", "ja​ne", "", UsernameReasonInvisible},
+		{"digits are not a script", "jane2000", "jane2000", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := v.Validate(tt.username)
+			if tt.reason == "" {
+				if err != nil || got != tt.want {
+					t.Fatalf("Validate(%q) = %q, %v; want %q", tt.username, got, err, tt.want)
+				}
+				return
+			}
+			var usernameErr *UsernameError
+			if !errors.As(err, &usernameErr) || usernameErr.Reason != tt.reason {
+				t.Fatalf("Validate(%q) error = %v, want reason %q", tt.username, err, tt.reason)
+			}
+		})
+	}
+}
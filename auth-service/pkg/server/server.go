@@ -0,0 +1,105 @@
+// Package server runs the HTTP server, and any services next to it, until
+// the process is asked to stop
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// Server wraps an http.Server with graceful shutdown
+type Server struct {
+	httpServer      *http.Server
+	shutdownTimeout time.Duration
+	onShutdown      []func() error
+	services        []Service
+}
+
+// Service is a server that runs next to the HTTP server on its own port
+// (gRPC...) and shares its lifecycle
+type Service interface {
+	// Serve blocks until the service stops; it returns nil after Shutdown
+	Serve() error
+	// Shutdown stops accepting work and waits for in-flight calls until ctx
+	// is done
+	Shutdown(ctx context.Context) error
+}
+
+// New creates a server. On shutdown, in-flight requests get shutdownTimeout
+// to finish; then the onShutdown functions run in order (stop background
+// jobs, close the database pool...).
+func New(httpServer *http.Server, shutdownTimeout time.Duration, onShutdown ...func() error) *Server {
+	return &Server{httpServer: httpServer, shutdownTimeout: shutdownTimeout, onShutdown: onShutdown}
+}
+
+// AddService runs svc alongside the HTTP server. It is started by Run and
+// stopped, within the same shutdown timeout, when the HTTP server is.
+func (s *Server) AddService(svc Service) *Server {
+	s.services = append(s.services, svc)
+	return s
+}
+
+// Run serves until ctx is cancelled or the process receives SIGINT or
+// SIGTERM, then stops accepting connections and waits for in-flight requests.
+// It returns an error if the server could not start or did not drain in time.
+func (s *Server) Run(ctx context.Context) error {
+	ctx, stop := signal.NotifyContext(ctx, syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	serveErr := make(chan error, 1+len(s.services))
+	go func() {
+		serveErr <- s.httpServer.ListenAndServe()
+	}()
+	for _, svc := range s.services {
+		go func(svc Service) {
+			serveErr <- svc.Serve()
+		}(svc)
+	}
+	running := 1 + len(s.services)
+
+	var startErr error
+	select {
+	case startErr = <-serveErr:
+		// Failed to start (port in use...); stop whatever did start
+		running--
+	case <-ctx.Done():
+		log.Printf("🛑 Shutting down server, waiting up to %s for in-flight requests...", s.shutdownTimeout)
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), s.shutdownTimeout)
+	defer cancel()
+
+	// Shutdown closes the listeners first, then waits for active requests
+	err := s.httpServer.Shutdown(shutdownCtx)
+	for _, svc := range s.services {
+		err = errors.Join(err, svc.Shutdown(shutdownCtx))
+	}
+	for ; running > 0; running-- {
+		if serr := <-serveErr; serr != nil && !errors.Is(serr, http.ErrServerClosed) {
+			err = errors.Join(err, serr)
+		}
+	}
+	s.close()
+	if startErr != nil {
+		return fmt.Errorf("server failed: %w", startErr)
+	}
+	if err != nil {
+		return fmt.Errorf("graceful shutdown failed: %w", err)
+	}
+	return nil
+}
+
+// close releases resources after the server stopped; errors are only logged
+func (s *Server) close() {
+	for _, fn := range s.onShutdown {
+		if err := fn(); err != nil {
+			log.Printf("shutdown: close failed: %v", err)
+		}
+	}
+}
@@ -0,0 +1,176 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+// freeAddr returns a local address nothing listens on
+func freeAddr(t *testing.T) string {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := ln.Addr().String()
+	ln.Close()
+	return addr
+}
+
+func TestRunDrainsInFlightRequests(t *testing.T) {
+	addr := freeAddr(t)
+	started := make(chan struct{})
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		time.Sleep(100 * time.Millisecond)
+		io.WriteString(w, "done")
+	})
+	closed := false
+	srv := New(&http.Server{Addr: addr, Handler: handler}, time.Second, func() error {
+		closed = true
+		return nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	runErr := make(chan error, 1)
+	go func() { runErr <- srv.Run(ctx) }()
+
+	// Wait for the listener, then send a slow request and shut down mid-flight
+	var resp *http.Response
+	reqErr := make(chan error, 1)
+	go func() {
+		var err error
+		for i := 0; i < 50; i++ {
+			if resp, err = http.Get("http://" + addr); err == nil {
+				break
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+		reqErr <- err
+	}()
+	<-started
+	cancel()
+
+	if err := <-reqErr; err != nil {
+		t.Fatalf("in-flight request dropped: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if string(body) != "done" {
+		t.Errorf("body = %q", body)
+	}
+	if err := <-runErr; err != nil {
+		t.Errorf("Run = %v", err)
+	}
+	if !closed {
+		t.Error("onShutdown not called")
+	}
+
+	// No new connections after shutdown
+	if _, err := http.Get("http://" + addr); err == nil {
+		t.Error("server still accepting connections")
+	}
+}
+
+func TestRunReportsDrainTimeout(t *testing.T) {
+	addr := freeAddr(t)
+	release := make(chan struct{})
+	started := make(chan struct{})
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+	})
+	defer close(release)
+	srv := New(&http.Server{Addr: addr, Handler: handler}, 50*time.Millisecond)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	runErr := make(chan error, 1)
+	go func() { runErr <- srv.Run(ctx) }()
+	go func() {
+		for i := 0; i < 50; i++ {
+			if resp, err := http.Get("http://" + addr); err == nil {
+				resp.Body.Close()
+				return
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}()
+	<-started
+	cancel()
+
+	if err := <-runErr; err == nil {
+		t.Error("Run returned nil although a request outlived the drain timeout")
+	}
+}
+
+func TestRunFailsWhenAddressIsTaken(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	srv := New(&http.Server{Addr: ln.Addr().String(), Handler: http.NotFoundHandler()}, time.Second)
+	if err := srv.Run(context.Background()); err == nil {
+		t.Error("Run returned nil for an address in use")
+	}
+}
+
+// fakeService blocks in Serve until Shutdown, or fails to start with err
+type fakeService struct {
+	err     error
+	stop    chan struct{}
+	stopped bool
+}
+
+func (f *fakeService) Serve() error {
+	if f.err != nil {
+		return f.err
+	}
+	<-f.stop
+	return nil
+}
+
+func (f *fakeService) Shutdown(ctx context.Context) error {
+	f.stopped = true
+	close(f.stop)
+	return nil
+}
+
+func TestRunStopsServicesWithTheHTTPServer(t *testing.T) {
+	svc := &fakeService{stop: make(chan struct{})}
+	srv := New(&http.Server{Addr: freeAddr(t), Handler: http.NotFoundHandler()}, time.Second).AddService(svc)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	runErr := make(chan error, 1)
+	go func() { runErr <- srv.Run(ctx) }()
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+
+	if err := <-runErr; err != nil {
+		t.Errorf("Run = %v", err)
+	}
+	if !svc.stopped {
+		t.Error("service not shut down")
+	}
+}
+
+func TestRunFailsWhenAServiceCannotStart(t *testing.T) {
+	addr := freeAddr(t)
+	svc := &fakeService{err: errors.New("listen: address in use"), stop: make(chan struct{})}
+	srv := New(&http.Server{Addr: addr, Handler: http.NotFoundHandler()}, time.Second).AddService(svc)
+
+	if err := srv.Run(context.Background()); err == nil || !strings.Contains(err.Error(), "address in use") {
+		t.Errorf("Run = %v, want the service's start error", err)
+	}
+	// The HTTP server that did start is stopped again
+	if _, err := http.Get("http://" + addr); err == nil {
+		t.Error("HTTP server still accepting connections")
+	}
+}